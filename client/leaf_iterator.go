@@ -0,0 +1,154 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/merkle/compact"
+	"github.com/google/trillian/types"
+)
+
+// LeafIterator streams the leaves of a log in order, verifying each batch it
+// receives before handing out its leaves.
+//
+// Every batch must come with a root that is correctly signed, and consistent
+// with the root of the previous batch (or with the client's trusted root, for
+// the first batch). Leaves must be contiguous and their Merkle leaf hashes
+// must match their values, so the iterator fails on leaves whose data the log
+// has pruned, as their Merkle leaf hashes can't be checked. If the iterator was started from index 0, it also
+// checks that the leaves hash to the root hash whenever it catches up with the
+// root's tree size.
+type LeafIterator struct {
+	v      *LogVerifier
+	stream trillian.TrillianLog_WatchLeavesClient
+	cancel context.CancelFunc
+
+	root types.LogRootV1
+	next int64
+	cr   *compact.Range
+	buf  []*trillian.LogLeaf
+}
+
+// WatchLeaves returns a LeafIterator which streams the log's leaves starting
+// at index start. The iterator's roots are verified against the currently
+// trusted root of the client, but the client's trusted root isn't updated.
+// The iterator must be closed once it's no longer needed.
+func (c *LogClient) WatchLeaves(ctx context.Context, start int64) (*LeafIterator, error) {
+	trusted := c.GetRoot()
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := c.client.WatchLeaves(ctx, &trillian.WatchLeavesRequest{
+		LogId:         c.LogID,
+		StartIndex:    start,
+		FirstTreeSize: int64(trusted.TreeSize),
+	})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	it := &LeafIterator{
+		v:      c.LogVerifier,
+		stream: stream,
+		cancel: cancel,
+		root:   *trusted,
+		next:   start,
+	}
+	if start == 0 {
+		f := &compact.RangeFactory{Hash: c.Hasher.HashChildren}
+		it.cr = f.NewEmptyRange(0)
+	}
+	return it, nil
+}
+
+// Next returns the next leaf of the log, blocking until it has been
+// integrated and verified. It returns an error if the stream fails, or if a
+// batch fails verification; in either case the iterator can't be used any
+// further.
+func (it *LeafIterator) Next() (*trillian.LogLeaf, error) {
+	for len(it.buf) == 0 {
+		if err := it.fetch(); err != nil {
+			return nil, err
+		}
+	}
+	leaf := it.buf[0]
+	it.buf = it.buf[1:]
+	return leaf, nil
+}
+
+// Root returns the latest verified root seen by the iterator. All leaves
+// returned by Next so far are covered by it.
+func (it *LeafIterator) Root() *types.LogRootV1 {
+	ret := it.root
+	return &ret
+}
+
+// Close stops the stream.
+func (it *LeafIterator) Close() {
+	it.cancel()
+}
+
+// fetch receives and verifies the next batch of leaves.
+func (it *LeafIterator) fetch() error {
+	rsp, err := it.stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	root, err := it.v.VerifyRoot(&it.root, rsp.GetSignedLogRoot(), rsp.GetProof().GetHashes())
+	if err != nil {
+		return err
+	}
+	if root.TreeSize < it.root.TreeSize {
+		return fmt.Errorf("root tree size went backwards: %d -> %d", it.root.TreeSize, root.TreeSize)
+	}
+	if end := it.next + int64(len(rsp.Leaves)); uint64(end) > root.TreeSize {
+		return fmt.Errorf("leaves up to index %d are not covered by root with tree size %d", end, root.TreeSize)
+	}
+
+	for i, leaf := range rsp.Leaves {
+		if want := it.next + int64(i); leaf.LeafIndex != want {
+			return fmt.Errorf("Leaves[%d].LeafIndex=%d, want %d", i, leaf.LeafIndex, want)
+		}
+		if leaf.DataPruned {
+			return fmt.Errorf("Leaves[%d]: data of leaf %d pruned by the log, can't verify it", i, leaf.LeafIndex)
+		}
+		if got := it.v.Hasher.HashLeaf(leaf.LeafValue); !bytes.Equal(got, leaf.MerkleLeafHash) {
+			return fmt.Errorf("Leaves[%d].MerkleLeafHash=%x, want %x", i, leaf.MerkleLeafHash, got)
+		}
+		if it.cr != nil {
+			if err := it.cr.Append(leaf.MerkleLeafHash, nil); err != nil {
+				return err
+			}
+		}
+	}
+	if it.cr != nil && it.cr.End() == root.TreeSize && root.TreeSize > 0 {
+		hash, err := it.cr.GetRootHash(nil)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(hash, root.RootHash) {
+			return fmt.Errorf("leaves [0, %d) hash to %x, want root hash %x", root.TreeSize, hash, root.RootHash)
+		}
+	}
+
+	it.root = *root
+	it.next += int64(len(rsp.Leaves))
+	it.buf = rsp.Leaves
+	return nil
+}
@@ -125,6 +125,39 @@ func (c *LogClient) ListByIndex(ctx context.Context, start, count int64) ([]*tri
 	return resp.Leaves, nil
 }
 
+// ListByIndexWithProof returns the requested leaves by index, after verifying
+// their inclusion in the trusted root with a range proof. Fewer leaves than
+// requested may be returned, but at least one.
+func (c *LogClient) ListByIndexWithProof(ctx context.Context, start, count int64) ([]*trillian.LogLeaf, error) {
+	root := c.GetRoot()
+	if start < 0 || uint64(start) >= root.TreeSize {
+		return nil, fmt.Errorf("start index %d is beyond trusted tree size %d", start, root.TreeSize)
+	}
+	resp, err := c.client.GetLeavesByRangeWithProof(ctx,
+		&trillian.GetLeavesByRangeWithProofRequest{
+			LogId:      c.LogID,
+			StartIndex: start,
+			Count:      count,
+			TreeSize:   int64(root.TreeSize),
+		})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Leaves) == 0 {
+		return nil, fmt.Errorf("no leaves returned from index %d at tree size %d", start, root.TreeSize)
+	}
+	if got := resp.Leaves[0].LeafIndex; got != start {
+		return nil, fmt.Errorf("Leaves[0].LeafIndex=%d, want %d", got, start)
+	}
+	if len(resp.Leaves) > int(count) {
+		return nil, fmt.Errorf("len(Leaves)=%d, want <= %d", len(resp.Leaves), count)
+	}
+	if err := c.VerifyLeavesByRange(root, resp.Leaves, resp.LeftHashes, resp.RightHashes); err != nil {
+		return nil, err
+	}
+	return resp.Leaves, nil
+}
+
 // WaitForRootUpdate repeatedly fetches the latest root until there is an
 // update, which it then applies, or until ctx times out.
 func (c *LogClient) WaitForRootUpdate(ctx context.Context) (*types.LogRootV1, error) {
@@ -403,3 +436,22 @@ func (c *LogClient) QueueLeaf(ctx context.Context, data []byte) error {
 	})
 	return err
 }
+
+// QueueLeafWithPromise adds a leaf to a Trillian log without blocking, and
+// returns the log's verified promise to incorporate it. If the leaf already
+// exists, the promise carries the time at which it was originally queued.
+func (c *LogClient) QueueLeafWithPromise(ctx context.Context, data []byte) (*trillian.SignedEntryTimestamp, error) {
+	leaf := c.BuildLeaf(data)
+	rsp, err := c.client.QueueLeaf(ctx, &trillian.QueueLeafRequest{
+		LogId:                      c.LogID,
+		Leaf:                       leaf,
+		ReturnSignedEntryTimestamp: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.VerifySignedEntryTimestamp(c.LogID, leaf, rsp.SignedEntryTimestamp); err != nil {
+		return nil, fmt.Errorf("VerifySignedEntryTimestamp(): %v", err)
+	}
+	return rsp.SignedEntryTimestamp, nil
+}
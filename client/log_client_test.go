@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -129,6 +130,43 @@ func TestListByIndex(t *testing.T) {
 	}
 }
 
+func TestListByIndexWithProof(t *testing.T) {
+	ctx := context.Background()
+	env, client := clientEnvForTest(ctx, t, stestonly.PreorderedLogTree)
+	defer env.Close()
+
+	// Add a few test leaves.
+	leafData := [][]byte{
+		[]byte("A"),
+		[]byte("B"),
+		[]byte("C"),
+		[]byte("D"),
+		[]byte("E"),
+	}
+
+	if err := addSequencedLeaves(ctx, env, client, leafData); err != nil {
+		t.Fatalf("Failed to add leaves: %v", err)
+	}
+
+	// Fetch and verify leaves.
+	leaves, err := client.ListByIndexWithProof(ctx, 1, 3)
+	if err != nil {
+		t.Fatalf("Failed to ListByIndexWithProof: %v", err)
+	}
+	if got, want := len(leaves), 3; got != want {
+		t.Fatalf("ListByIndexWithProof() returned %d leaves, want %d", got, want)
+	}
+	for i, l := range leaves {
+		if got, want := l.LeafValue, leafData[i+1]; !bytes.Equal(got, want) {
+			t.Errorf("ListByIndexWithProof()[%v] = %v, want %v", i, got, want)
+		}
+	}
+
+	if _, err := client.ListByIndexWithProof(ctx, 5, 1); err == nil {
+		t.Error("ListByIndexWithProof(5, 1) beyond tree size: nil, want error")
+	}
+}
+
 func TestVerifyInclusion(t *testing.T) {
 	ctx := context.Background()
 	env, client := clientEnvForTest(ctx, t, stestonly.PreorderedLogTree)
@@ -295,6 +333,86 @@ func TestUpdateRootSkew(t *testing.T) {
 	}
 }
 
+func TestWatchLeaves(t *testing.T) {
+	ctx := context.Background()
+	env, client := clientEnvForTest(ctx, t, stestonly.LogTree)
+	defer env.Close()
+
+	it, err := client.WatchLeaves(ctx, 0)
+	if err != nil {
+		t.Fatalf("WatchLeaves(): %v", err)
+	}
+	defer it.Close()
+
+	for _, batch := range [][]string{{"A", "B", "C"}, {"D", "E"}} {
+		want := make(map[string]bool)
+		for _, data := range batch {
+			if err := client.QueueLeaf(ctx, []byte(data)); err != nil {
+				t.Fatalf("QueueLeaf(%s): %v", data, err)
+			}
+			want[data] = true
+		}
+		env.Sequencer.OperationSingle(ctx)
+
+		// The sequencer doesn't guarantee any particular order within a batch.
+		for range batch {
+			leaf, err := it.Next()
+			if err != nil {
+				t.Fatalf("Next(): %v", err)
+			}
+			if data := string(leaf.LeafValue); !want[data] {
+				t.Errorf("Next()=%q, want one of %v", data, want)
+			}
+			delete(want, string(leaf.LeafValue))
+		}
+	}
+	if got, want := it.Root().TreeSize, uint64(5); got != want {
+		t.Errorf("Root().TreeSize=%d, want %d", got, want)
+	}
+}
+
+func TestWatchLeavesPruned(t *testing.T) {
+	ctx := context.Background()
+	env, client := clientEnvForTest(ctx, t, stestonly.LogTree)
+	defer env.Close()
+
+	if err := client.QueueLeaf(ctx, []byte("A")); err != nil {
+		t.Fatalf("QueueLeaf(A): %v", err)
+	}
+	env.Sequencer.OperationSingle(ctx)
+
+	pruning := &MutatingLogClient{TrillianLogClient: env.Log, pruneWatchedLeaves: true}
+	prunedClient := New(client.LogID, pruning, client.LogVerifier, *client.GetRoot())
+	it, err := prunedClient.WatchLeaves(ctx, 0)
+	if err != nil {
+		t.Fatalf("WatchLeaves(): %v", err)
+	}
+	defer it.Close()
+	if leaf, err := it.Next(); err == nil || !strings.Contains(err.Error(), "pruned") {
+		t.Errorf("Next(): %v, %v, want pruned error", leaf, err)
+	}
+}
+
+func TestQueueLeafWithPromise(t *testing.T) {
+	ctx := context.Background()
+	env, client := clientEnvForTest(ctx, t, stestonly.LogTree)
+	defer env.Close()
+
+	data := []byte("foo")
+	set, err := client.QueueLeafWithPromise(ctx, data)
+	if err != nil {
+		t.Fatalf("QueueLeafWithPromise(): %v", err)
+	}
+	// Queueing the same leaf again returns a promise with the original timestamp.
+	again, err := client.QueueLeafWithPromise(ctx, data)
+	if err != nil {
+		t.Fatalf("QueueLeafWithPromise(): %v", err)
+	}
+	if got, want := again.TimestampNanos, set.TimestampNanos; got != want {
+		t.Errorf("QueueLeafWithPromise() again: TimestampNanos %v, want %v", got, want)
+	}
+}
+
 func TestAddSequencedLeaves(t *testing.T) {
 	ctx := context.Background()
 	for _, tc := range []struct {
@@ -15,9 +15,11 @@
 package client
 
 import (
+	"bytes"
 	"crypto"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/trillian"
 	"github.com/google/trillian/crypto/keys/der"
@@ -133,6 +135,72 @@ func (c *LogVerifier) VerifyInclusionByHash(trusted *types.LogRootV1, leafHash [
 		trusted.RootHash, leafHash)
 }
 
+// VerifyLeavesByRange verifies that leaves, which must be consecutive and
+// ordered by index, are included in the tree described by trusted, according
+// to the left and right hashes of a range proof, as returned by the
+// GetLeavesByRangeWithProof RPC. The Merkle leaf hashes of the leaves are
+// recomputed from their values, except for the leaves whose data was pruned.
+func (c *LogVerifier) VerifyLeavesByRange(trusted *types.LogRootV1, leaves []*trillian.LogLeaf, left, right [][]byte) error {
+	if trusted == nil {
+		return fmt.Errorf("VerifyLeavesByRange() error: trusted == nil")
+	}
+	if len(leaves) == 0 {
+		return fmt.Errorf("VerifyLeavesByRange() error: no leaves")
+	}
+	leafHashes, err := c.rangeLeafHashes(leaves)
+	if err != nil {
+		return err
+	}
+	return c.v.VerifyRangeProof(leaves[0].LeafIndex, int64(trusted.TreeSize), left, right, trusted.RootHash, leafHashes)
+}
+
+// rangeLeafHashes returns the Merkle leaf hashes of a range of leaves, after
+// checking that the leaves are consecutive.
+func (c *LogVerifier) rangeLeafHashes(leaves []*trillian.LogLeaf) ([][]byte, error) {
+	leafHashes := make([][]byte, 0, len(leaves))
+	for i, leaf := range leaves {
+		if want := leaves[0].LeafIndex + int64(i); leaf.LeafIndex != want {
+			return nil, fmt.Errorf("leaves[%d].LeafIndex=%d, want %d", i, leaf.LeafIndex, want)
+		}
+		if leaf.DataPruned {
+			leafHashes = append(leafHashes, leaf.MerkleLeafHash)
+			continue
+		}
+		leafHash := c.Hasher.HashLeaf(leaf.LeafValue)
+		if len(leaf.MerkleLeafHash) > 0 && !bytes.Equal(leaf.MerkleLeafHash, leafHash) {
+			return nil, fmt.Errorf("leaves[%d].MerkleLeafHash=%x, want %x", i, leaf.MerkleLeafHash, leafHash)
+		}
+		leafHashes = append(leafHashes, leafHash)
+	}
+	return leafHashes, nil
+}
+
+// VerifySignedEntryTimestamp verifies that set is a promise by the log with the
+// given ID to incorporate leaf, and returns the time at which leaf was queued.
+// The promise is honoured if leaf is included in a root signed no later than
+// the log's MaxRootDuration after that time. If leaf has no identity hash, its
+// leaf hash is used instead, as is done by the log.
+func (c *LogVerifier) VerifySignedEntryTimestamp(logID int64, leaf *trillian.LogLeaf, set *trillian.SignedEntryTimestamp) (time.Time, error) {
+	if leaf == nil {
+		return time.Time{}, errors.New("VerifySignedEntryTimestamp() error: leaf == nil")
+	}
+	e, err := tcrypto.VerifySignedEntryTimestamp(c.PubKey, c.SigHash, set)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if got := int64(e.LogID); got != logID {
+		return time.Time{}, fmt.Errorf("signed entry timestamp is for log %d, want %d", got, logID)
+	}
+	identityHash := leaf.LeafIdentityHash
+	if len(identityHash) == 0 {
+		identityHash = c.Hasher.HashLeaf(leaf.LeafValue)
+	}
+	if !bytes.Equal(e.LeafIdentityHash, identityHash) {
+		return time.Time{}, fmt.Errorf("signed entry timestamp is for leaf %x, want %x", e.LeafIdentityHash, identityHash)
+	}
+	return time.Unix(0, int64(e.TimestampNanos)), nil
+}
+
 // BuildLeaf runs the leaf hasher over data and builds a leaf.
 // TODO(pavelkalinnikov): This can be misleading as it creates a partially
 // filled LogLeaf. Consider returning a pair instead, or leafHash only.
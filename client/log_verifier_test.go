@@ -16,10 +16,14 @@ package client
 
 import (
 	"crypto"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/google/trillian"
 	"github.com/google/trillian/crypto/keys/pem"
+	"github.com/google/trillian/merkle/compact"
 	"github.com/google/trillian/merkle/rfc6962"
 	"github.com/google/trillian/testonly"
 	"github.com/google/trillian/types"
@@ -90,3 +94,135 @@ func TestVerifyInclusionByHashErrors(t *testing.T) {
 		}
 	}
 }
+
+func TestVerifyLeavesByRange(t *testing.T) {
+	logVerifier := NewLogVerifier(rfc6962.DefaultHasher, nil, crypto.SHA256)
+	f := &compact.RangeFactory{Hash: rfc6962.DefaultHasher.HashChildren}
+	var leaves []*trillian.LogLeaf
+	tree := f.NewEmptyRange(0)
+	for i := int64(0); i < 11; i++ {
+		leaf := logVerifier.BuildLeaf([]byte(fmt.Sprintf("leaf %d", i)))
+		leaf.LeafIndex = i
+		leaves = append(leaves, leaf)
+		if err := tree.Append(leaf.MerkleLeafHash, nil); err != nil {
+			t.Fatalf("Append(): %v", err)
+		}
+	}
+	rootHash, err := tree.GetRootHash(nil)
+	if err != nil {
+		t.Fatalf("GetRootHash(): %v", err)
+	}
+	root := &types.LogRootV1{TreeSize: 11, RootHash: rootHash}
+	// rangeHashes returns the hashes of the compact range [begin, end).
+	rangeHashes := func(begin, end int) [][]byte {
+		r := f.NewEmptyRange(uint64(begin))
+		for _, leaf := range leaves[begin:end] {
+			if err := r.Append(leaf.MerkleLeafHash, nil); err != nil {
+				t.Fatalf("Append(): %v", err)
+			}
+		}
+		return r.Hashes()
+	}
+	left, right := rangeHashes(0, 3), rangeHashes(8, 11)
+
+	if err := logVerifier.VerifyLeavesByRange(root, leaves[3:8], left, right); err != nil {
+		t.Errorf("VerifyLeavesByRange(): %v", err)
+	}
+
+	pruned := append([]*trillian.LogLeaf(nil), leaves[3:8]...)
+	pruned[1] = &trillian.LogLeaf{LeafIndex: 4, MerkleLeafHash: leaves[4].MerkleLeafHash, DataPruned: true}
+	if err := logVerifier.VerifyLeavesByRange(root, pruned, left, right); err != nil {
+		t.Errorf("VerifyLeavesByRange(pruned): %v", err)
+	}
+
+	tampered := append([]*trillian.LogLeaf(nil), leaves[3:8]...)
+	tampered[1] = &trillian.LogLeaf{LeafIndex: 4, LeafValue: []byte("other")}
+	wrongHash := append([]*trillian.LogLeaf(nil), leaves[3:8]...)
+	wrongHash[1] = &trillian.LogLeaf{LeafIndex: 4, LeafValue: leaves[4].LeafValue, MerkleLeafHash: leaves[5].MerkleLeafHash}
+	gap := append(append([]*trillian.LogLeaf(nil), leaves[3:5]...), leaves[6:8]...)
+	for _, tc := range []struct {
+		desc        string
+		trusted     *types.LogRootV1
+		leaves      []*trillian.LogLeaf
+		left, right [][]byte
+	}{
+		{desc: "trustedNil", leaves: leaves[3:8], left: left, right: right},
+		{desc: "noLeaves", trusted: root, left: left, right: right},
+		{desc: "tamperedValue", trusted: root, leaves: tampered, left: left, right: right},
+		{desc: "wrongLeafHash", trusted: root, leaves: wrongHash, left: left, right: right},
+		{desc: "gap", trusted: root, leaves: gap, left: left, right: right},
+		{desc: "shortRange", trusted: root, leaves: leaves[3:7], left: left, right: right},
+		{desc: "wrongProof", trusted: root, leaves: leaves[3:8], left: right, right: left},
+		{desc: "otherRoot", trusted: &types.LogRootV1{TreeSize: 11, RootHash: left[0]}, leaves: leaves[3:8], left: left, right: right},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			if err := logVerifier.VerifyLeavesByRange(tc.trusted, tc.leaves, tc.left, tc.right); err == nil {
+				t.Error("VerifyLeavesByRange() error expected, but got nil")
+			}
+		})
+	}
+}
+
+func TestVerifySignedEntryTimestamp(t *testing.T) {
+	key, err := pem.UnmarshalPrivateKey(testonly.DemoPrivateKey, testonly.DemoPrivateKeyPass)
+	if err != nil {
+		t.Fatalf("Failed to open test key, err=%v", err)
+	}
+	signer := tcrypto.NewSigner(0, key, crypto.SHA256)
+	pk, err := pem.UnmarshalPublicKey(testonly.DemoPublicKey)
+	if err != nil {
+		t.Fatalf("Failed to load public key, err=%v", err)
+	}
+	logVerifier := NewLogVerifier(rfc6962.DefaultHasher, pk, crypto.SHA256)
+
+	const logID = 42
+	queued := time.Unix(1500000000, 12345)
+	data := []byte("data")
+	leaf := logVerifier.BuildLeaf(data)
+	set, err := signer.SignEntryTimestamp(&types.EntryTimestampV1{
+		LogID:            logID,
+		LeafIdentityHash: leaf.MerkleLeafHash,
+		TimestampNanos:   uint64(queued.UnixNano()),
+	})
+	if err != nil {
+		t.Fatalf("SignEntryTimestamp(): %v", err)
+	}
+	otherLogSET, err := signer.SignEntryTimestamp(&types.EntryTimestampV1{
+		LogID:            logID + 1,
+		LeafIdentityHash: leaf.MerkleLeafHash,
+		TimestampNanos:   uint64(queued.UnixNano()),
+	})
+	if err != nil {
+		t.Fatalf("SignEntryTimestamp(): %v", err)
+	}
+
+	tamperedSET := proto.Clone(set).(*trillian.SignedEntryTimestamp)
+	tamperedSET.TimestampNanos++
+
+	for _, test := range []struct {
+		desc    string
+		leaf    *trillian.LogLeaf
+		set     *trillian.SignedEntryTimestamp
+		wantErr bool
+	}{
+		{desc: "ok", leaf: leaf, set: set},
+		{desc: "okLeafValueOnly", leaf: &trillian.LogLeaf{LeafValue: data}, set: set},
+		{desc: "okIdentityHash", leaf: &trillian.LogLeaf{LeafValue: []byte("other"), LeafIdentityHash: leaf.MerkleLeafHash}, set: set},
+		{desc: "otherLeaf", leaf: logVerifier.BuildLeaf([]byte("other")), set: set, wantErr: true},
+		{desc: "otherLog", leaf: leaf, set: otherLogSET, wantErr: true},
+		{desc: "tampered", leaf: leaf, set: tamperedSET, wantErr: true},
+		{desc: "nilSET", leaf: leaf, set: nil, wantErr: true},
+		{desc: "nilLeaf", leaf: nil, set: set, wantErr: true},
+	} {
+		got, err := logVerifier.VerifySignedEntryTimestamp(logID, test.leaf, test.set)
+		if gotErr := err != nil; gotErr != test.wantErr {
+			t.Errorf("%v: VerifySignedEntryTimestamp(): %v, wantErr %v", test.desc, err, test.wantErr)
+			continue
+		} else if gotErr {
+			continue
+		}
+		if !got.Equal(queued) {
+			t.Errorf("%v: VerifySignedEntryTimestamp(): %v, want %v", test.desc, got, queued)
+		}
+	}
+}
@@ -15,10 +15,12 @@
 package client
 
 import (
+	"bytes"
 	"context"
 
 	"github.com/google/trillian"
 	"github.com/google/trillian/types"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
@@ -53,11 +55,13 @@ func (c *MapClient) GetAndVerifyLatestMapRoot(ctx context.Context) (*types.MapRo
 }
 
 // GetAndVerifyMapLeaves verifies and returns the requested map leaves.
-// indexes may not contain duplicates.
+// indexes may not contain duplicates. The inclusion proofs are requested in
+// compressed form, but verified in whichever form the server returns them.
 func (c *MapClient) GetAndVerifyMapLeaves(ctx context.Context, indexes [][]byte) ([]*trillian.MapLeaf, error) {
 	getResp, err := c.Conn.GetLeaves(ctx, &trillian.GetMapLeavesRequest{
-		MapId: c.MapID,
-		Index: indexes,
+		MapId:       c.MapID,
+		Index:       indexes,
+		ProofFormat: trillian.MapProofFormat_MAP_PROOF_FORMAT_COMPRESSED_BATCH,
 	})
 	if err != nil {
 		s := status.Convert(err)
@@ -70,9 +74,10 @@ func (c *MapClient) GetAndVerifyMapLeaves(ctx context.Context, indexes [][]byte)
 // indexes may not contain duplicates.
 func (c *MapClient) GetAndVerifyMapLeavesByRevision(ctx context.Context, revision int64, indexes [][]byte) ([]*trillian.MapLeaf, error) {
 	getResp, err := c.Conn.GetLeavesByRevision(ctx, &trillian.GetMapLeavesByRevisionRequest{
-		MapId:    c.MapID,
-		Index:    indexes,
-		Revision: revision,
+		MapId:       c.MapID,
+		Index:       indexes,
+		Revision:    revision,
+		ProofFormat: trillian.MapProofFormat_MAP_PROOF_FORMAT_COMPRESSED_BATCH,
 	})
 	if err != nil {
 		s := status.Convert(err)
@@ -81,6 +86,85 @@ func (c *MapClient) GetAndVerifyMapLeavesByRevision(ctx context.Context, revisio
 	return c.VerifyMapLeavesResponse(indexes, revision, getResp)
 }
 
+// GetAndVerifyMapLeavesByKey verifies and returns the map leaves of the raw
+// keys, whose indexes are derived by the map's VRF. keys may not contain
+// duplicates.
+func (c *MapClient) GetAndVerifyMapLeavesByKey(ctx context.Context, keys [][]byte) ([]*trillian.MapLeaf, error) {
+	getResp, err := c.Conn.GetLeaves(ctx, &trillian.GetMapLeavesRequest{
+		MapId:       c.MapID,
+		Key:         keys,
+		ProofFormat: trillian.MapProofFormat_MAP_PROOF_FORMAT_COMPRESSED_BATCH,
+	})
+	if err != nil {
+		s := status.Convert(err)
+		return nil, status.Errorf(s.Code(), "map.GetLeaves(): %v", s.Message())
+	}
+	return c.VerifyMapLeavesResponseForKeys(keys, -1, getResp)
+}
+
+// GetAndVerifyRevisionDiff verifies and returns all the map leaves which were
+// set after fromRevision, up to and including toRevision, along with their
+// values at both revisions.
+func (c *MapClient) GetAndVerifyRevisionDiff(ctx context.Context, fromRevision, toRevision int64) ([]*trillian.MapLeafDiff, error) {
+	req := &trillian.GetRevisionDiffRequest{
+		MapId:         c.MapID,
+		FromRevision:  fromRevision,
+		ToRevision:    toRevision,
+		IncludeValues: true,
+	}
+	var diffs []*trillian.MapLeafDiff
+	for {
+		resp, err := c.Conn.GetRevisionDiff(ctx, req)
+		if err != nil {
+			s := status.Convert(err)
+			return nil, status.Errorf(s.Code(), "map.GetRevisionDiff(): %v", s.Message())
+		}
+		page, err := c.VerifyRevisionDiffResponse(req, resp)
+		if err != nil {
+			return nil, err
+		}
+		// Pages follow each other in ascending order of index.
+		if len(diffs) > 0 && len(page) > 0 && bytes.Compare(diffs[len(diffs)-1].Index, page[0].Index) >= 0 {
+			return nil, status.Errorf(codes.Internal, "got index %x after %x, want ascending order", page[0].Index, diffs[len(diffs)-1].Index)
+		}
+		diffs = append(diffs, page...)
+		if resp.NextPageToken == "" {
+			return diffs, nil
+		}
+		req.PageToken = resp.NextPageToken
+	}
+}
+
+// GetAndVerifyLeafHistory verifies and returns the values which the map leaf
+// at index had from fromRevision up to and including toRevision: its value at
+// fromRevision, followed by its value at each revision where it changed.
+func (c *MapClient) GetAndVerifyLeafHistory(ctx context.Context, index []byte, fromRevision, toRevision int64) ([]*trillian.MapLeafVersion, error) {
+	req := &trillian.GetLeafHistoryRequest{
+		MapId:        c.MapID,
+		Index:        index,
+		FromRevision: fromRevision,
+		ToRevision:   toRevision,
+	}
+	var versions []*trillian.MapLeafVersion
+	for {
+		resp, err := c.Conn.GetLeafHistory(ctx, req)
+		if err != nil {
+			s := status.Convert(err)
+			return nil, status.Errorf(s.Code(), "map.GetLeafHistory(): %v", s.Message())
+		}
+		versions = append(versions, resp.Versions...)
+		if resp.NextPageToken == "" {
+			break
+		}
+		req.PageToken = resp.NextPageToken
+	}
+	req.PageToken = ""
+	if err := c.VerifyLeafHistory(req, versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
 // SetAndVerifyMapLeaves calls SetLeaves and verifies the signature of the returned map root.
 func (c *MapClient) SetAndVerifyMapLeaves(ctx context.Context, leaves []*trillian.MapLeaf, metadata []byte) (*types.MapRootV1, error) {
 	// Set new leaf values.
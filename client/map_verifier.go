@@ -15,10 +15,13 @@
 package client
 
 import (
+	"bytes"
+	"crypto/ecdsa"
 	"errors"
 	"fmt"
 
 	"github.com/google/trillian"
+	"github.com/google/trillian/crypto/vrf"
 	"github.com/google/trillian/maps"
 	"github.com/google/trillian/merkle"
 	"github.com/google/trillian/merkle/hashers"
@@ -101,7 +104,7 @@ func (m *MapVerifier) VerifyMapLeavesResponse(indexes [][]byte, revision int64,
 	for _, p := range resp.MapLeafInclusion {
 		p := p
 		g.Go(func() error {
-			return m.VerifyMapLeafInclusionHash(mapRoot.RootHash, p)
+			return m.verifyResponseInclusion(mapRoot.RootHash, resp, p)
 		})
 	}
 	if err := g.Wait(); err != nil {
@@ -114,3 +117,153 @@ func (m *MapVerifier) VerifyMapLeavesResponse(indexes [][]byte, revision int64,
 	}
 	return leaves, nil
 }
+
+// VerifyMapLeafVRF verifies that the index of the leaf in leafProof is the one
+// derived from the raw key by the map's VRF, using the VRF proof of leafProof
+// and the public key of the map.
+func (m *MapVerifier) VerifyMapLeafVRF(key []byte, leafProof *trillian.MapLeafInclusion) error {
+	pub, ok := m.PubKey.(*ecdsa.PublicKey)
+	if !ok {
+		return status.Errorf(codes.FailedPrecondition, "map %v has a %T key, VRF requires an ECDSA P-256 key", m.MapID, m.PubKey)
+	}
+	beta, err := vrf.Verify(pub, key, leafProof.GetVrfProof())
+	if err != nil {
+		return status.Errorf(codes.Internal, "vrf.Verify(%q): %v", key, err)
+	}
+	if m.Hasher.Size() > len(beta) {
+		return status.Errorf(codes.FailedPrecondition, "map %v has %d byte indexes, VRF outputs are %d bytes", m.MapID, m.Hasher.Size(), len(beta))
+	}
+	if got, want := leafProof.GetLeaf().GetIndex(), beta[:m.Hasher.Size()]; !bytes.Equal(got, want) {
+		return status.Errorf(codes.Internal, "got index %x for key %q, want %x", got, key, want)
+	}
+	return nil
+}
+
+// VerifyMapLeavesResponseForKeys verifies the responses of GetMapLeaves and
+// GetMapLeavesByRevision for raw keys. It checks the VRF proof of the index of
+// each key before verifying the response as VerifyMapLeavesResponse does.
+// To accept any map revision, pass -1 as revision.
+func (m *MapVerifier) VerifyMapLeavesResponseForKeys(keys [][]byte, revision int64, resp *trillian.GetMapLeavesResponse) ([]*trillian.MapLeaf, error) {
+	if got, want := len(resp.MapLeafInclusion), len(keys); got != want {
+		return nil, status.Errorf(codes.Internal, "got %v leaves, want %v", got, want)
+	}
+	indexes := make([][]byte, 0, len(keys))
+	for i, key := range keys {
+		leafProof := resp.MapLeafInclusion[i]
+		if err := m.VerifyMapLeafVRF(key, leafProof); err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, leafProof.GetLeaf().GetIndex())
+	}
+	return m.VerifyMapLeavesResponse(indexes, revision, resp)
+}
+
+// verifyResponseInclusion verifies a MapLeafInclusion of a GetMapLeaves
+// response against a root hash, with its proof in the format of the response.
+func (m *MapVerifier) verifyResponseInclusion(rootHash []byte, resp *trillian.GetMapLeavesResponse, leafProof *trillian.MapLeafInclusion) error {
+	switch resp.ProofFormat {
+	case trillian.MapProofFormat_MAP_PROOF_FORMAT_FULL:
+		return m.VerifyMapLeafInclusionHash(rootHash, leafProof)
+	case trillian.MapProofFormat_MAP_PROOF_FORMAT_COMPRESSED:
+		return merkle.VerifyCompressedMapInclusionProof(m.MapID, leafProof.GetLeaf(), rootHash, leafProof.GetCompressedInclusion(), nil, m.Hasher)
+	case trillian.MapProofFormat_MAP_PROOF_FORMAT_COMPRESSED_BATCH:
+		return merkle.VerifyCompressedMapInclusionProof(m.MapID, leafProof.GetLeaf(), rootHash, leafProof.GetCompressedInclusion(), resp.ProofHashes, m.Hasher)
+	}
+	return status.Errorf(codes.Internal, "unknown proof format %v", resp.ProofFormat)
+}
+
+// VerifyRevisionDiffResponse verifies a response of GetRevisionDiff to req,
+// and returns its diffs. It checks that both map roots are signed and are of
+// the requested revisions, that the indexes are in ascending order, and, if
+// req asked for values, that the leaves at both revisions are included in the
+// respective map roots. It can't check that no changed leaf is missing.
+func (m *MapVerifier) VerifyRevisionDiffResponse(req *trillian.GetRevisionDiffRequest, resp *trillian.GetRevisionDiffResponse) ([]*trillian.MapLeafDiff, error) {
+	fromRoot, err := m.VerifySignedMapRoot(resp.GetFromMapRoot())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "VerifySignedMapRoot(%v, from): %v", m.MapID, err)
+	}
+	if got, want := int64(fromRoot.Revision), req.FromRevision; got != want {
+		return nil, status.Errorf(codes.Internal, "got from map revision %v, want %v", got, want)
+	}
+	toRoot, err := m.VerifySignedMapRoot(resp.GetToMapRoot())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "VerifySignedMapRoot(%v, to): %v", m.MapID, err)
+	}
+	if got, want := int64(toRoot.Revision), req.ToRevision; got != want {
+		return nil, status.Errorf(codes.Internal, "got to map revision %v, want %v", got, want)
+	}
+
+	var g errgroup.Group
+	for i, d := range resp.Diffs {
+		if got, want := len(d.Index), m.Hasher.Size(); got != want {
+			return nil, status.Errorf(codes.Internal, "got index %x of %d bytes, want %d", d.Index, got, want)
+		}
+		if i > 0 && bytes.Compare(resp.Diffs[i-1].Index, d.Index) >= 0 {
+			return nil, status.Errorf(codes.Internal, "got index %x after %x, want ascending order", d.Index, resp.Diffs[i-1].Index)
+		}
+		if !req.IncludeValues {
+			continue
+		}
+		for _, l := range []struct {
+			inclusion *trillian.MapLeafInclusion
+			rootHash  []byte
+		}{
+			{d.FromLeaf, fromRoot.RootHash},
+			{d.ToLeaf, toRoot.RootHash},
+		} {
+			l := l
+			if got := l.inclusion.GetLeaf().GetIndex(); !bytes.Equal(got, d.Index) {
+				return nil, status.Errorf(codes.Internal, "got leaf %x in the diff of %x", got, d.Index)
+			}
+			g.Go(func() error {
+				return m.VerifyMapLeafInclusionHash(l.rootHash, l.inclusion)
+			})
+		}
+	}
+	if err := g.Wait(); err != nil {
+		return nil, status.Errorf(status.Code(err), "map: VerifyMapLeafInclusion(): %v", err)
+	}
+	return resp.Diffs, nil
+}
+
+// VerifyLeafHistory verifies the versions of the leaf returned by all the
+// pages of GetLeafHistory for req. It checks that the first version is at
+// req.FromRevision, that the later ones are in ascending order of revision up
+// to req.ToRevision and each have a different value than the previous one,
+// and that every leaf is included in the signed map root of its version. It
+// can't check that no change of the leaf is missing.
+func (m *MapVerifier) VerifyLeafHistory(req *trillian.GetLeafHistoryRequest, versions []*trillian.MapLeafVersion) error {
+	if len(versions) == 0 {
+		return status.Errorf(codes.Internal, "got no versions of leaf %x, want at least 1", req.Index)
+	}
+	var g errgroup.Group
+	var prev int64
+	for i, v := range versions {
+		root, err := m.VerifySignedMapRoot(v.GetMapRoot())
+		if err != nil {
+			return status.Errorf(codes.Internal, "VerifySignedMapRoot(%v): %v", m.MapID, err)
+		}
+		rev := int64(root.Revision)
+		switch {
+		case i == 0 && rev != req.FromRevision:
+			return status.Errorf(codes.Internal, "got first map revision %v, want %v", rev, req.FromRevision)
+		case i > 0 && (rev <= prev || rev > req.ToRevision):
+			return status.Errorf(codes.Internal, "got map revision %v after %v, want ascending order up to %v", rev, prev, req.ToRevision)
+		}
+		if got := v.GetLeaf().GetLeaf().GetIndex(); !bytes.Equal(got, req.Index) {
+			return status.Errorf(codes.Internal, "got leaf %x in the history of %x", got, req.Index)
+		}
+		if i > 0 && bytes.Equal(v.GetLeaf().GetLeaf().GetLeafValue(), versions[i-1].GetLeaf().GetLeaf().GetLeafValue()) {
+			return status.Errorf(codes.Internal, "got unchanged value of leaf %x at map revision %v", req.Index, rev)
+		}
+		prev = rev
+		rootHash, inclusion := root.RootHash, v.GetLeaf()
+		g.Go(func() error {
+			return m.VerifyMapLeafInclusionHash(rootHash, inclusion)
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return status.Errorf(status.Code(err), "map: VerifyMapLeafInclusion(): %v", err)
+	}
+	return nil
+}
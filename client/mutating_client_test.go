@@ -31,6 +31,7 @@ type MutatingLogClient struct {
 	mutateInclusionProof   bool
 	mutateConsistencyProof bool
 	mutateRootSize         bool
+	pruneWatchedLeaves     bool
 }
 
 // GetLatestSignedLogRoot forwards requests and optionally modifies the returned size.
@@ -94,3 +95,29 @@ func (c *MutatingLogClient) GetConsistencyProof(ctx context.Context, in *trillia
 	}
 	return resp, nil
 }
+
+// WatchLeaves forwards requests and optionally marks the streamed leaves as
+// pruned, dropping their data.
+func (c *MutatingLogClient) WatchLeaves(ctx context.Context, in *trillian.WatchLeavesRequest, opts ...grpc.CallOption) (trillian.TrillianLog_WatchLeavesClient, error) {
+	stream, err := c.TrillianLogClient.WatchLeaves(ctx, in)
+	if err != nil || !c.pruneWatchedLeaves {
+		return stream, err
+	}
+	return pruningStream{stream}, nil
+}
+
+// pruningStream marks the leaves it receives as pruned.
+type pruningStream struct {
+	trillian.TrillianLog_WatchLeavesClient
+}
+
+func (s pruningStream) Recv() (*trillian.WatchLeavesResponse, error) {
+	resp, err := s.TrillianLog_WatchLeavesClient.Recv()
+	if err != nil {
+		return nil, err
+	}
+	for _, leaf := range resp.Leaves {
+		leaf.LeafValue, leaf.ExtraData, leaf.DataPruned = nil, nil, true
+	}
+	return resp, nil
+}
@@ -0,0 +1,306 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+	"github.com/google/trillian/merkle/hashers"
+	"github.com/google/trillian/types"
+)
+
+// TileClient fetches the checkpoint and tiles of a log from the static HTTP
+// read API of a Trillian log server, or from a cache in front of it.
+type TileClient struct {
+	hc     *http.Client
+	url    string
+	height int
+	hasher hashers.LogHasher
+}
+
+// NewTileClient returns a TileClient fetching the tiles of the given height of
+// the log whose tiles are served under logURL, e.g.
+// "http://localhost:8091/tiles/123". If hc is nil, http.DefaultClient is used.
+func NewTileClient(hc *http.Client, logURL string, height int, hasher hashers.LogHasher) *TileClient {
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	return &TileClient{hc: hc, url: strings.TrimSuffix(logURL, "/"), height: height, hasher: hasher}
+}
+
+// Checkpoint returns the latest SignedLogRoot of the log. It is not verified;
+// use LogVerifier.VerifyRoot for that.
+func (c *TileClient) Checkpoint(ctx context.Context) (*trillian.SignedLogRoot, error) {
+	body, err := c.get(ctx, "checkpoint")
+	if err != nil {
+		return nil, err
+	}
+	var slr trillian.SignedLogRoot
+	if err := proto.Unmarshal(body, &slr); err != nil {
+		return nil, fmt.Errorf("client: failed to parse checkpoint: %v", err)
+	}
+	return &slr, nil
+}
+
+// HashTile returns the hashes of a hash tile of the log.
+func (c *TileClient) HashTile(ctx context.Context, tile types.Tile) ([][]byte, error) {
+	body, err := c.get(ctx, tile.Path())
+	if err != nil {
+		return nil, err
+	}
+	size := c.hasher.Size()
+	if len(body) != tile.Width*size {
+		return nil, fmt.Errorf("client: tile %s has %d bytes, want %d", tile.Path(), len(body), tile.Width*size)
+	}
+	hashes := make([][]byte, 0, tile.Width)
+	for i := 0; i < len(body); i += size {
+		hashes = append(hashes, body[i:i+size])
+	}
+	return hashes, nil
+}
+
+// EntryBundle returns the leaves of an entry bundle of the log, whose Level
+// must be types.TileDataLevel.
+func (c *TileClient) EntryBundle(ctx context.Context, tile types.Tile) ([]*trillian.LogLeaf, error) {
+	body, err := c.get(ctx, tile.Path())
+	if err != nil {
+		return nil, err
+	}
+	var rsp trillian.GetLeavesByRangeResponse
+	if err := proto.Unmarshal(body, &rsp); err != nil {
+		return nil, fmt.Errorf("client: failed to parse entry bundle %s: %v", tile.Path(), err)
+	}
+	start := int64(tile.Index << uint(tile.Height))
+	if got, want := len(rsp.Leaves), tile.Width; got != want {
+		return nil, fmt.Errorf("client: entry bundle %s has %d leaves, want %d", tile.Path(), got, want)
+	}
+	for i, leaf := range rsp.Leaves {
+		if got, want := leaf.LeafIndex, start+int64(i); got != want {
+			return nil, fmt.Errorf("client: entry bundle %s has leaf %d at position %d, want %d", tile.Path(), got, i, want)
+		}
+	}
+	return rsp.Leaves, nil
+}
+
+func (c *TileClient) get(ctx context.Context, path string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, c.url+"/"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp, err := c.hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	body, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("client: GET %s: %s: %s", path, rsp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// TileProofBuilder builds inclusion and consistency proofs for a log at a
+// fixed tree size, from the hash tiles of the log. The proofs are the same as
+// the ones returned by the log's RPCs, and can be verified the same way. It
+// caches the tiles it fetches, and is not safe for concurrent use.
+type TileProofBuilder struct {
+	c     *TileClient
+	size  uint64
+	tiles map[types.Tile][][]byte
+}
+
+// NewTileProofBuilder returns a TileProofBuilder for the given tree size, which
+// is usually the size of a verified checkpoint of the log.
+func NewTileProofBuilder(c *TileClient, treeSize uint64) *TileProofBuilder {
+	return &TileProofBuilder{c: c, size: treeSize, tiles: make(map[types.Tile][][]byte)}
+}
+
+// RootHash returns the root hash of the tree, as computed from the tiles. It
+// should match the root hash of the checkpoint the tree size comes from.
+func (b *TileProofBuilder) RootHash(ctx context.Context) ([]byte, error) {
+	if b.size == 0 {
+		return b.c.hasher.EmptyRoot(), nil
+	}
+	return b.subtreeHash(ctx, 0, b.size)
+}
+
+// InclusionProof returns the inclusion proof of the leaf at the given index.
+func (b *TileProofBuilder) InclusionProof(ctx context.Context, index uint64) ([][]byte, error) {
+	if index >= b.size {
+		return nil, fmt.Errorf("client: leaf index %d beyond tree size %d", index, b.size)
+	}
+	return b.inclusion(ctx, index, 0, b.size)
+}
+
+// ConsistencyProof returns the consistency proof between the given smaller
+// tree size and the tree size of the builder.
+func (b *TileProofBuilder) ConsistencyProof(ctx context.Context, size1 uint64) ([][]byte, error) {
+	if size1 > b.size {
+		return nil, fmt.Errorf("client: tree size %d beyond tree size %d", size1, b.size)
+	}
+	if size1 == 0 || size1 == b.size {
+		return [][]byte{}, nil
+	}
+	return b.consistency(ctx, size1, 0, b.size, true)
+}
+
+// inclusion returns the inclusion proof of leaf index in the subtree covering
+// leaves [begin, end), as described in RFC 6962 section 2.1.1.
+func (b *TileProofBuilder) inclusion(ctx context.Context, index, begin, end uint64) ([][]byte, error) {
+	if end-begin == 1 {
+		return [][]byte{}, nil
+	}
+	mid := begin + splitPoint(end-begin)
+	var proof [][]byte
+	var sibling []byte
+	var err error
+	if index < mid {
+		if proof, err = b.inclusion(ctx, index, begin, mid); err != nil {
+			return nil, err
+		}
+		sibling, err = b.subtreeHash(ctx, mid, end)
+	} else {
+		if proof, err = b.inclusion(ctx, index, mid, end); err != nil {
+			return nil, err
+		}
+		sibling, err = b.subtreeHash(ctx, begin, mid)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(proof, sibling), nil
+}
+
+// consistency returns the consistency proof between tree size size1 and the
+// subtree covering leaves [begin, end), as described in RFC 6962 section
+// 2.1.2. whole is true while the subtree starts at the first leaf, in which
+// case its hash is omitted from the proof if it is the whole old tree.
+func (b *TileProofBuilder) consistency(ctx context.Context, size1, begin, end uint64, whole bool) ([][]byte, error) {
+	if size1 == end {
+		if whole {
+			return [][]byte{}, nil
+		}
+		hash, err := b.subtreeHash(ctx, begin, end)
+		if err != nil {
+			return nil, err
+		}
+		return [][]byte{hash}, nil
+	}
+	mid := begin + splitPoint(end-begin)
+	var proof [][]byte
+	var sibling []byte
+	var err error
+	if size1 <= mid {
+		if proof, err = b.consistency(ctx, size1, begin, mid, whole); err != nil {
+			return nil, err
+		}
+		sibling, err = b.subtreeHash(ctx, mid, end)
+	} else {
+		if proof, err = b.consistency(ctx, size1, mid, end, false); err != nil {
+			return nil, err
+		}
+		sibling, err = b.subtreeHash(ctx, begin, mid)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(proof, sibling), nil
+}
+
+// subtreeHash returns the hash of the subtree covering leaves [begin, end),
+// which is either a perfect subtree, or the right border of the tree.
+func (b *TileProofBuilder) subtreeHash(ctx context.Context, begin, end uint64) ([]byte, error) {
+	n := end - begin
+	if n&(n-1) == 0 {
+		level := uint(0)
+		for ; n > 1; n >>= 1 {
+			level++
+		}
+		return b.nodeHash(ctx, level, begin>>level)
+	}
+	mid := begin + splitPoint(end-begin)
+	left, err := b.subtreeHash(ctx, begin, mid)
+	if err != nil {
+		return nil, err
+	}
+	right, err := b.subtreeHash(ctx, mid, end)
+	if err != nil {
+		return nil, err
+	}
+	return b.c.hasher.HashChildren(left, right), nil
+}
+
+// nodeHash returns the hash of the perfect subtree node at the given level
+// and index, computing it from the hashes of the tile holding its descendants.
+func (b *TileProofBuilder) nodeHash(ctx context.Context, level uint, index uint64) ([]byte, error) {
+	height := uint(b.c.height)
+	tileLevel := level / height
+	// The range of nodes on the bottom row of the tile which the node covers.
+	depth := level - tileLevel*height
+	first := index << depth
+	tile := types.Tile{
+		Height: b.c.height,
+		Level:  int(tileLevel),
+		Index:  first >> height,
+	}
+	width := (b.size >> (tileLevel * height)) - tile.Index<<height
+	if full := uint64(1) << height; width > full {
+		width = full
+	}
+	tile.Width = int(width)
+
+	hashes, ok := b.tiles[tile]
+	if !ok {
+		var err error
+		if hashes, err = b.c.HashTile(ctx, tile); err != nil {
+			return nil, err
+		}
+		b.tiles[tile] = hashes
+	}
+	offset := first - tile.Index<<height
+	if offset+1<<depth > uint64(len(hashes)) {
+		return nil, fmt.Errorf("client: node (level %d, index %d) beyond tree size %d", level, index, b.size)
+	}
+	return b.hashRow(hashes[offset : offset+1<<depth]), nil
+}
+
+// hashRow returns the root hash of the perfect subtree with the given hashes
+// as its bottom row.
+func (b *TileProofBuilder) hashRow(row [][]byte) []byte {
+	if len(row) == 1 {
+		return row[0]
+	}
+	mid := len(row) / 2
+	return b.c.hasher.HashChildren(b.hashRow(row[:mid]), b.hashRow(row[mid:]))
+}
+
+// splitPoint returns the largest power of two smaller than n, which must be
+// at least 2.
+func splitPoint(n uint64) uint64 {
+	k := uint64(1)
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
@@ -0,0 +1,155 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/rfc6962"
+	"github.com/google/trillian/types"
+)
+
+// tileServer serves the hash tiles of a tree with the given leaf hashes, and
+// counts the requests it gets.
+type tileServer struct {
+	leaves   [][]byte
+	requests int
+}
+
+// hash returns the RFC 6962 hash of the leaves [begin, end).
+func (s *tileServer) hash(begin, end uint64) []byte {
+	if end-begin == 1 {
+		return s.leaves[begin]
+	}
+	mid := begin + splitPoint(end-begin)
+	return rfc6962.DefaultHasher.HashChildren(s.hash(begin, mid), s.hash(mid, end))
+}
+
+func (s *tileServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.requests++
+	tile, err := types.ParseTilePath(strings.TrimPrefix(r.URL.Path, "/"))
+	if err != nil || tile.Level == types.TileDataLevel {
+		http.NotFound(w, r)
+		return
+	}
+	level := uint(tile.Level * tile.Height)
+	start := tile.Index << uint(tile.Height)
+	if start+uint64(tile.Width) > uint64(len(s.leaves))>>level {
+		http.NotFound(w, r)
+		return
+	}
+	var body []byte
+	for i := start; i < start+uint64(tile.Width); i++ {
+		body = append(body, s.hash(i<<level, (i+1)<<level)...)
+	}
+	w.Write(body)
+}
+
+func TestTileProofBuilder(t *testing.T) {
+	ctx := context.Background()
+	hasher := rfc6962.DefaultHasher
+	verifier := merkle.NewLogVerifier(hasher)
+
+	ts := &tileServer{}
+	for i := 0; i < 70; i++ {
+		ts.leaves = append(ts.leaves, hasher.HashLeaf([]byte(fmt.Sprintf("leaf %d", i))))
+	}
+	s := httptest.NewServer(ts)
+	defer s.Close()
+
+	for _, height := range []int{1, 2, 3, 8} {
+		c := NewTileClient(s.Client(), s.URL, height, hasher)
+		for size := uint64(1); size <= uint64(len(ts.leaves)); size++ {
+			b := NewTileProofBuilder(c, size)
+			root, err := b.RootHash(ctx)
+			if err != nil {
+				t.Fatalf("height %d, size %d: RootHash(): %v", height, size, err)
+			}
+			if want := ts.hash(0, size); !bytes.Equal(root, want) {
+				t.Fatalf("height %d, size %d: RootHash(): %x, want %x", height, size, root, want)
+			}
+
+			for index := uint64(0); index < size; index++ {
+				proof, err := b.InclusionProof(ctx, index)
+				if err != nil {
+					t.Fatalf("height %d, size %d: InclusionProof(%d): %v", height, size, index, err)
+				}
+				if err := verifier.VerifyInclusionProof(int64(index), int64(size), proof, root, ts.leaves[index]); err != nil {
+					t.Errorf("height %d, size %d: VerifyInclusionProof(%d): %v", height, size, index, err)
+				}
+			}
+
+			for size1 := uint64(0); size1 <= size; size1++ {
+				proof, err := b.ConsistencyProof(ctx, size1)
+				if err != nil {
+					t.Fatalf("height %d, size %d: ConsistencyProof(%d): %v", height, size, size1, err)
+				}
+				root1 := hasher.EmptyRoot()
+				if size1 > 0 {
+					root1 = ts.hash(0, size1)
+				}
+				if err := verifier.VerifyConsistencyProof(int64(size1), int64(size), root1, root, proof); err != nil {
+					t.Errorf("height %d, size %d: VerifyConsistencyProof(%d): %v", height, size, size1, err)
+				}
+			}
+		}
+	}
+}
+
+func TestTileProofBuilderCachesTiles(t *testing.T) {
+	ctx := context.Background()
+	ts := &tileServer{}
+	for i := 0; i < 300; i++ {
+		ts.leaves = append(ts.leaves, rfc6962.DefaultHasher.HashLeaf([]byte(fmt.Sprintf("leaf %d", i))))
+	}
+	s := httptest.NewServer(ts)
+	defer s.Close()
+
+	b := NewTileProofBuilder(NewTileClient(s.Client(), s.URL, 8, rfc6962.DefaultHasher), 300)
+	for index := uint64(0); index < 300; index++ {
+		if _, err := b.InclusionProof(ctx, index); err != nil {
+			t.Fatalf("InclusionProof(%d): %v", index, err)
+		}
+	}
+	// The full tile 8/0/0, the partial tile 8/0/1.p/44 and the partial tile
+	// 8/1/0.p/1.
+	if got, want := ts.requests, 3; got != want {
+		t.Errorf("Got %d tile requests, want %d", got, want)
+	}
+}
+
+func TestTileProofBuilderErrors(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(http.NotFoundHandler())
+	defer s.Close()
+
+	b := NewTileProofBuilder(NewTileClient(s.Client(), s.URL, 8, rfc6962.DefaultHasher), 10)
+	if _, err := b.InclusionProof(ctx, 10); err == nil {
+		t.Error("InclusionProof(10): nil, want error")
+	}
+	if _, err := b.ConsistencyProof(ctx, 11); err == nil {
+		t.Error("ConsistencyProof(11): nil, want error")
+	}
+	if _, err := b.InclusionProof(ctx, 3); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("InclusionProof(3): %v, want 404 error", err)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/types"
+
+	tcrypto "github.com/google/trillian/crypto"
+)
+
+// WitnessVerifier verifies roots of a Trillian Log which have been cosigned by
+// witnesses; it is safe for concurrent use (as its contents are fixed after
+// construction).
+type WitnessVerifier struct {
+	// LogID is the tree ID of the Log.
+	LogID int64
+	// Log verifies the Log's own signatures on its roots.
+	Log *LogVerifier
+	// Witnesses holds the public keys of the trusted witnesses, keyed by
+	// witness ID.
+	Witnesses map[string]crypto.PublicKey
+	// Quorum is the number of distinct trusted witnesses which must have
+	// cosigned a root for it to be accepted.
+	Quorum int
+}
+
+// NewWitnessVerifier returns an object that can verify roots of a Log cosigned
+// by at least quorum of witnesses.
+func NewWitnessVerifier(logID int64, log *LogVerifier, witnesses map[string]crypto.PublicKey, quorum int) (*WitnessVerifier, error) {
+	if quorum < 1 || quorum > len(witnesses) {
+		return nil, fmt.Errorf("client: NewWitnessVerifier(): quorum %d out of range [1, %d]", quorum, len(witnesses))
+	}
+	return &WitnessVerifier{
+		LogID:     logID,
+		Log:       log,
+		Witnesses: witnesses,
+		Quorum:    quorum,
+	}, nil
+}
+
+// VerifyCosignedRoot verifies the Log's signature on root, and that at least
+// Quorum trusted witnesses have cosigned it. Cosignatures from unknown
+// witnesses, or which don't verify, are ignored.
+func (v *WitnessVerifier) VerifyCosignedRoot(root *trillian.SignedLogRoot, cosigs []*trillian.LogRootCosignature) (*types.LogRootV1, error) {
+	logRoot, err := tcrypto.VerifySignedLogRoot(v.Log.PubKey, v.Log.SigHash, root)
+	if err != nil {
+		return nil, err
+	}
+	if got := len(v.validCosignatures(root, cosigs)); got < v.Quorum {
+		return nil, fmt.Errorf("root cosigned by %d trusted witnesses, want at least %d", got, v.Quorum)
+	}
+	return logRoot, nil
+}
+
+// validCosignatures returns the cosignatures of root made by distinct trusted
+// witnesses.
+func (v *WitnessVerifier) validCosignatures(root *trillian.SignedLogRoot, cosigs []*trillian.LogRootCosignature) []*trillian.LogRootCosignature {
+	var valid []*trillian.LogRootCosignature
+	seen := make(map[string]bool)
+	for _, cosig := range cosigs {
+		id := cosig.GetWitnessId()
+		pub, ok := v.Witnesses[id]
+		if !ok || seen[id] || cosig.LogId != v.LogID {
+			continue
+		}
+		// Witnesses always sign with SHA256 digests.
+		if _, err := tcrypto.VerifyCosignature(pub, crypto.SHA256, root, cosig); err != nil {
+			continue
+		}
+		seen[id] = true
+		valid = append(valid, cosig)
+	}
+	return valid
+}
+
+// WitnessClient gathers cosignatures of the roots of a Log from witnesses.
+type WitnessClient struct {
+	*WitnessVerifier
+	log       trillian.TrillianLogClient
+	witnesses []trillian.TrillianWitnessClient
+}
+
+// NewWitnessClient returns a WitnessClient which submits roots to witnesses,
+// along with consistency proofs fetched from log, and verifies the
+// cosignatures they return with v.
+func NewWitnessClient(v *WitnessVerifier, log trillian.TrillianLogClient, witnesses []trillian.TrillianWitnessClient) *WitnessClient {
+	return &WitnessClient{
+		WitnessVerifier: v,
+		log:             log,
+		witnesses:       witnesses,
+	}
+}
+
+// Cosign submits root to all the witnesses, and returns the verified root once
+// they have all responded, if at least Quorum trusted witnesses have cosigned
+// it. The returned cosignatures have all been verified.
+func (c *WitnessClient) Cosign(ctx context.Context, root *trillian.SignedLogRoot) (*types.LogRootV1, []*trillian.LogRootCosignature, error) {
+	logRoot, err := tcrypto.VerifySignedLogRoot(c.Log.PubKey, c.Log.SigHash, root)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var wg sync.WaitGroup
+	cosigs := make([]*trillian.LogRootCosignature, len(c.witnesses))
+	errs := make([]error, len(c.witnesses))
+	for i, w := range c.witnesses {
+		wg.Add(1)
+		go func(i int, w trillian.TrillianWitnessClient) {
+			defer wg.Done()
+			cosigs[i], errs[i] = c.cosign(ctx, w, root, logRoot)
+		}(i, w)
+	}
+	wg.Wait()
+
+	valid := c.validCosignatures(root, cosigs)
+	if len(valid) < c.Quorum {
+		var msgs []string
+		for i, err := range errs {
+			if err != nil {
+				msgs = append(msgs, fmt.Sprintf("witness %d: %v", i, err))
+			}
+		}
+		return nil, nil, fmt.Errorf("root cosigned by %d trusted witnesses, want at least %d: [%s]", len(valid), c.Quorum, strings.Join(msgs, "; "))
+	}
+	return logRoot, valid, nil
+}
+
+// cosign submits root to a single witness, with a consistency proof from the
+// latest root it has cosigned.
+func (c *WitnessClient) cosign(ctx context.Context, w trillian.TrillianWitnessClient, root *trillian.SignedLogRoot, logRoot *types.LogRootV1) (*trillian.LogRootCosignature, error) {
+	latest, err := w.GetLatestCosignedLogRoot(ctx, &trillian.GetLatestCosignedLogRootRequest{LogId: c.LogID})
+	if err != nil {
+		return nil, err
+	}
+	var proof *trillian.Proof
+	if slr := latest.GetSignedLogRoot(); slr != nil {
+		// Only the size of the witness's latest root is needed, to request a
+		// consistency proof. The witness verifies the proof itself.
+		var witnessRoot types.LogRootV1
+		if err := witnessRoot.UnmarshalBinary(slr.LogRoot); err != nil {
+			return nil, err
+		}
+		if witnessRoot.TreeSize > logRoot.TreeSize {
+			return nil, fmt.Errorf("witness has cosigned a larger root of size %d", witnessRoot.TreeSize)
+		}
+		if witnessRoot.TreeSize > 0 && witnessRoot.TreeSize < logRoot.TreeSize {
+			rsp, err := c.log.GetConsistencyProof(ctx, &trillian.GetConsistencyProofRequest{
+				LogId:          c.LogID,
+				FirstTreeSize:  int64(witnessRoot.TreeSize),
+				SecondTreeSize: int64(logRoot.TreeSize),
+			})
+			if err != nil {
+				return nil, err
+			}
+			proof = rsp.Proof
+		}
+	}
+
+	rsp, err := w.AddLogRoot(ctx, &trillian.AddLogRootRequest{
+		LogId:         c.LogID,
+		SignedLogRoot: root,
+		Proof:         proof,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rsp.Cosignature, nil
+}
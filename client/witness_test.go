@@ -0,0 +1,250 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/crypto/keys/pem"
+	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/rfc6962"
+	"github.com/google/trillian/server/witness"
+	"github.com/google/trillian/testonly"
+	"github.com/google/trillian/types"
+	"github.com/google/trillian/util/clock"
+	"google.golang.org/grpc"
+
+	tcrypto "github.com/google/trillian/crypto"
+)
+
+const witnessedLogID = 7
+
+// witnessedLog is an in-memory log serving consistency proofs.
+type witnessedLog struct {
+	trillian.TrillianLogClient
+	t      *testing.T
+	signer *tcrypto.Signer
+	tree   *merkle.InMemoryMerkleTree
+}
+
+func newWitnessedLog(t *testing.T, size int) *witnessedLog {
+	t.Helper()
+	key, err := pem.UnmarshalPrivateKey(testonly.DemoPrivateKey, testonly.DemoPrivateKeyPass)
+	if err != nil {
+		t.Fatalf("UnmarshalPrivateKey(): %v", err)
+	}
+	l := &witnessedLog{
+		t:      t,
+		signer: tcrypto.NewSigner(witnessedLogID, key, crypto.SHA256),
+		tree:   merkle.NewInMemoryMerkleTree(rfc6962.DefaultHasher),
+	}
+	for i := 0; i < size; i++ {
+		l.tree.AddLeaf([]byte(fmt.Sprintf("leaf-%d", i)))
+	}
+	return l
+}
+
+func (l *witnessedLog) root(size int64) *trillian.SignedLogRoot {
+	l.t.Helper()
+	slr, err := l.signer.SignLogRoot(&types.LogRootV1{
+		TreeSize:       uint64(size),
+		RootHash:       l.tree.RootAtSnapshot(size).Hash(),
+		TimestampNanos: uint64(size),
+	})
+	if err != nil {
+		l.t.Fatalf("SignLogRoot(): %v", err)
+	}
+	return slr
+}
+
+func (l *witnessedLog) GetConsistencyProof(ctx context.Context, req *trillian.GetConsistencyProofRequest, opts ...grpc.CallOption) (*trillian.GetConsistencyProofResponse, error) {
+	var hashes [][]byte
+	for _, d := range l.tree.SnapshotConsistency(req.FirstTreeSize, req.SecondTreeSize) {
+		hashes = append(hashes, d.Value.Hash())
+	}
+	return &trillian.GetConsistencyProofResponse{Proof: &trillian.Proof{Hashes: hashes}}, nil
+}
+
+// witnessServerClient adapts a TrillianWitnessServer to a
+// TrillianWitnessClient.
+type witnessServerClient struct {
+	s trillian.TrillianWitnessServer
+}
+
+func (c witnessServerClient) GetLatestCosignedLogRoot(ctx context.Context, req *trillian.GetLatestCosignedLogRootRequest, opts ...grpc.CallOption) (*trillian.GetLatestCosignedLogRootResponse, error) {
+	return c.s.GetLatestCosignedLogRoot(ctx, req)
+}
+
+func (c witnessServerClient) AddLogRoot(ctx context.Context, req *trillian.AddLogRootRequest, opts ...grpc.CallOption) (*trillian.AddLogRootResponse, error) {
+	return c.s.AddLogRoot(ctx, req)
+}
+
+// newWitnesses returns n witnesses of log, and their public keys.
+func newWitnesses(t *testing.T, log *witnessedLog, n int) ([]trillian.TrillianWitnessClient, map[string]crypto.PublicKey) {
+	t.Helper()
+	clients := make([]trillian.TrillianWitnessClient, 0, n)
+	keys := make(map[string]crypto.PublicKey)
+	for i := 0; i < n; i++ {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatalf("GenerateKey(): %v", err)
+		}
+		id := fmt.Sprintf("witness-%d", i)
+		s := witness.New(id, tcrypto.NewSigner(0, key, crypto.SHA256), []*witness.Log{{
+			ID:        witnessedLogID,
+			PublicKey: log.signer.Public(),
+			SigHash:   crypto.SHA256,
+			Hasher:    rfc6962.DefaultHasher,
+		}}, witness.NewMemoryStorage(), clock.System)
+		clients = append(clients, witnessServerClient{s})
+		keys[id] = key.Public()
+	}
+	return clients, keys
+}
+
+func TestNewWitnessVerifier(t *testing.T) {
+	keys := map[string]crypto.PublicKey{"a": nil, "b": nil}
+	for _, tc := range []struct {
+		quorum  int
+		wantErr bool
+	}{
+		{quorum: 0, wantErr: true},
+		{quorum: 1},
+		{quorum: 2},
+		{quorum: 3, wantErr: true},
+	} {
+		_, err := NewWitnessVerifier(witnessedLogID, nil, keys, tc.quorum)
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Errorf("NewWitnessVerifier(quorum=%d): %v, wantErr %v", tc.quorum, err, tc.wantErr)
+		}
+	}
+}
+
+func TestWitnessClientCosign(t *testing.T) {
+	ctx := context.Background()
+	log := newWitnessedLog(t, 20)
+	witnesses, keys := newWitnesses(t, log, 3)
+	logVerifier := NewLogVerifier(rfc6962.DefaultHasher, log.signer.Public(), crypto.SHA256)
+
+	newClient := func(quorum int) *WitnessClient {
+		v, err := NewWitnessVerifier(witnessedLogID, logVerifier, keys, quorum)
+		if err != nil {
+			t.Fatalf("NewWitnessVerifier(): %v", err)
+		}
+		return NewWitnessClient(v, log, witnesses)
+	}
+
+	// The steps are run in order against the same witnesses.
+	for _, step := range []struct {
+		desc string
+		// ahead, if non-zero, is the size of a root submitted to the first
+		// witness only, before the step.
+		ahead     int64
+		size      int64
+		quorum    int
+		wantCount int
+		wantErr   bool
+	}{
+		{desc: "first", size: 5, quorum: 3, wantCount: 3},
+		{desc: "consistent", size: 10, quorum: 3, wantCount: 3},
+		{desc: "same", size: 10, quorum: 3, wantCount: 3},
+		{desc: "oneWitnessAhead", ahead: 15, size: 12, quorum: 2, wantCount: 2},
+		{desc: "quorumNotMet", size: 13, quorum: 3, wantErr: true},
+		{desc: "caughtUp", size: 20, quorum: 3, wantCount: 3},
+	} {
+		if step.ahead > 0 {
+			if _, _, err := NewWitnessClient(&WitnessVerifier{
+				LogID:     witnessedLogID,
+				Log:       logVerifier,
+				Witnesses: keys,
+				Quorum:    1,
+			}, log, witnesses[:1]).Cosign(ctx, log.root(step.ahead)); err != nil {
+				t.Fatalf("%v: Cosign(ahead): %v", step.desc, err)
+			}
+		}
+
+		root := log.root(step.size)
+		c := newClient(step.quorum)
+		logRoot, cosigs, err := c.Cosign(ctx, root)
+		if gotErr := err != nil; gotErr != step.wantErr {
+			t.Fatalf("%v: Cosign(): %v, wantErr %v", step.desc, err, step.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if got, want := logRoot.TreeSize, uint64(step.size); got != want {
+			t.Errorf("%v: Cosign(): tree size %d, want %d", step.desc, got, want)
+		}
+		if got, want := len(cosigs), step.wantCount; got != want {
+			t.Errorf("%v: Cosign(): %d cosignatures, want %d", step.desc, got, want)
+		}
+		if _, err := c.VerifyCosignedRoot(root, cosigs); err != nil {
+			t.Errorf("%v: VerifyCosignedRoot(): %v", step.desc, err)
+		}
+	}
+}
+
+func TestVerifyCosignedRoot(t *testing.T) {
+	ctx := context.Background()
+	log := newWitnessedLog(t, 8)
+	witnesses, keys := newWitnesses(t, log, 3)
+	logVerifier := NewLogVerifier(rfc6962.DefaultHasher, log.signer.Public(), crypto.SHA256)
+	v, err := NewWitnessVerifier(witnessedLogID, logVerifier, keys, 2)
+	if err != nil {
+		t.Fatalf("NewWitnessVerifier(): %v", err)
+	}
+
+	root := log.root(8)
+	_, cosigs, err := NewWitnessClient(v, log, witnesses).Cosign(ctx, root)
+	if err != nil {
+		t.Fatalf("Cosign(): %v", err)
+	}
+	otherRoot := log.root(7)
+	badLogSig := log.root(8)
+	badLogSig.LogRootSignature = []byte("not a signature")
+	wrongLog := *cosigs[1]
+	wrongLog.LogId++
+	untrusted := *cosigs[1]
+	untrusted.WitnessId = "untrusted"
+
+	for _, tc := range []struct {
+		desc    string
+		root    *trillian.SignedLogRoot
+		cosigs  []*trillian.LogRootCosignature
+		wantErr bool
+	}{
+		{desc: "all", root: root, cosigs: cosigs},
+		{desc: "quorum", root: root, cosigs: cosigs[:2]},
+		{desc: "belowQuorum", root: root, cosigs: cosigs[:1], wantErr: true},
+		{desc: "duplicate", root: root, cosigs: []*trillian.LogRootCosignature{cosigs[0], cosigs[0]}, wantErr: true},
+		{desc: "wrongLog", root: root, cosigs: []*trillian.LogRootCosignature{cosigs[0], &wrongLog}, wantErr: true},
+		{desc: "untrustedWitness", root: root, cosigs: []*trillian.LogRootCosignature{cosigs[0], &untrusted}, wantErr: true},
+		{desc: "otherRoot", root: otherRoot, cosigs: cosigs, wantErr: true},
+		{desc: "badLogSignature", root: badLogSig, cosigs: cosigs, wantErr: true},
+		{desc: "nilCosignature", root: root, cosigs: []*trillian.LogRootCosignature{cosigs[0], nil}, wantErr: true},
+	} {
+		_, err := v.VerifyCosignedRoot(tc.root, tc.cosigs)
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Errorf("%v: VerifyCosignedRoot(): %v, wantErr %v", tc.desc, err, tc.wantErr)
+		}
+	}
+}
@@ -60,6 +60,8 @@ var (
 	description        = flag.String("description", "", "Description of the new tree")
 	maxRootDuration    = flag.Duration("max_root_duration", 0, "Interval after which a new signed root is produced despite no submissions; zero means never")
 	privateKeyFormat   = flag.String("private_key_format", "", "Type of protobuf message to send the key as (PrivateKey, PEMKeyFile, or PKCS11ConfigFile). If empty, a key will be generated for you by Trillian.")
+	logRootFormat      = flag.String("log_root_format", "", "Format of the signed roots of the new log (LOG_ROOT_FORMAT_V1 or LOG_ROOT_FORMAT_CHECKPOINT). If empty, the server default is used")
+	checkpointOrigin   = flag.String("checkpoint_origin", "", "Origin of the checkpoints of the new log, required by LOG_ROOT_FORMAT_CHECKPOINT")
 
 	configFile = flag.String("config", "", "Config file containing flags, file contents can be overridden by command line flags")
 
@@ -131,6 +133,16 @@ func newRequest() (*trillian.CreateTreeRequest, error) {
 		Description:        *description,
 		MaxRootDuration:    ptypes.DurationProto(*maxRootDuration),
 	}}
+	if *logRootFormat != "" || *checkpointOrigin != "" {
+		lrf, ok := trillian.LogRootFormat_value[*logRootFormat]
+		if !ok && *logRootFormat != "" {
+			return nil, fmt.Errorf("unknown LogRootFormat: %v", *logRootFormat)
+		}
+		ctr.Tree.LogRootConfig = &trillian.LogRootConfig{
+			Format: trillian.LogRootFormat(lrf),
+			Origin: *checkpointOrigin,
+		}
+	}
 	glog.Infof("Creating tree %+v", ctr.Tree)
 
 	if *privateKeyFormat != "" {
@@ -151,6 +163,10 @@ func newRequest() (*trillian.CreateTreeRequest, error) {
 			ctr.KeySpec.Params = &keyspb.Specification_RsaParams{
 				RsaParams: &keyspb.Specification_RSA{},
 			}
+		case sigpb.DigitallySigned_ED25519:
+			ctr.KeySpec.Params = &keyspb.Specification_Ed25519Params{
+				Ed25519Params: &keyspb.Specification_Ed25519{},
+			}
 		default:
 			return nil, fmt.Errorf("unsupported signature algorithm: %v", sa)
 		}
@@ -70,6 +70,12 @@ func TestCreateTree(t *testing.T) {
 	nonDefaultTree.DisplayName = "Llamas Map"
 	nonDefaultTree.Description = "For all your digital llama needs!"
 
+	checkpointTree := proto.Clone(defaultTree).(*trillian.Tree)
+	checkpointTree.LogRootConfig = &trillian.LogRootConfig{
+		Format: trillian.LogRootFormat_LOG_ROOT_FORMAT_CHECKPOINT,
+		Origin: "example.com/llamas",
+	}
+
 	runTest(t, []*testCase{
 		{
 			desc: "validOpts",
@@ -105,6 +111,20 @@ func TestCreateTree(t *testing.T) {
 			validateErr: errors.New("unknown TreeType"),
 			wantErr:     true,
 		},
+		{
+			desc: "checkpointOpts",
+			setFlags: func() {
+				*logRootFormat = checkpointTree.LogRootConfig.Format.String()
+				*checkpointOrigin = checkpointTree.LogRootConfig.Origin
+			},
+			wantTree: checkpointTree,
+		},
+		{
+			desc:        "invalidLogRootFormat",
+			setFlags:    func() { *logRootFormat = "LLAMA!" },
+			validateErr: errors.New("unknown LogRootFormat"),
+			wantErr:     true,
+		},
 		{
 			desc:        "invalidKeyTypeOpts",
 			setFlags:    func() { *privateKeyFormat = "LLAMA!!" },
@@ -0,0 +1,415 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/google/trillian"
+	"github.com/google/trillian/crypto/keys/der"
+	"github.com/google/trillian/merkle/compact"
+	"github.com/google/trillian/merkle/hashers"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/trees"
+	"github.com/google/trillian/types"
+
+	tcrypto "github.com/google/trillian/crypto"
+)
+
+// maxTreeDepth is the depth of the log trees, as used for storage node IDs.
+const maxTreeDepth = 64
+
+// merkleNode is a tree node recomputed from the leaf hashes.
+type merkleNode struct {
+	id   compact.NodeID
+	hash []byte
+}
+
+// fsck checks the integrity of a log tree. All the data is read in a single
+// read-only transaction, so the check sees a consistent snapshot of the tree.
+type fsck struct {
+	tree      *trillian.Tree
+	hasher    hashers.LogHasher
+	pubKey    crypto.PublicKey
+	sigHash   crypto.Hash
+	batchSize int
+
+	tx storage.ReadOnlyLogTreeTX
+	// latest is the latest root of the tree. Stored nodes are compared as of
+	// its revision.
+	latest *types.LogRootV1
+	// next is the index of the next leaf to read.
+	next uint64
+	// cr holds the Merkle tree recomputed from the leaves read so far, or nil
+	// if a leaf is missing, in which case the tree can't be recomputed any
+	// further.
+	cr *compact.Range
+	// nodes holds the recomputed nodes which haven't been compared with the
+	// stored ones yet.
+	nodes []merkleNode
+	// indices maps the identity hashes of the leaves read so far to their
+	// indices. Only used for LOG trees, which don't allow duplicate leaves.
+	indices map[string]int64
+
+	problems []string
+}
+
+// checkTree checks the integrity of the given log tree in storage, reading
+// leaves, nodes and roots in batches of batchSize. Returns the inconsistencies
+// found, or an error if the check couldn't complete.
+func checkTree(ctx context.Context, ls storage.ReadOnlyLogStorage, tree *trillian.Tree, batchSize int) ([]string, error) {
+	if tt := tree.TreeType; tt != trillian.TreeType_LOG && tt != trillian.TreeType_PREORDERED_LOG {
+		return nil, fmt.Errorf("tree %d is a %v tree, want LOG or PREORDERED_LOG", tree.TreeId, tt)
+	}
+	if batchSize <= 0 {
+		return nil, fmt.Errorf("batch size must be positive, got %d", batchSize)
+	}
+	hasher, err := hashers.NewLogHasher(tree.HashStrategy)
+	if err != nil {
+		return nil, err
+	}
+	pubKey, err := der.UnmarshalPublicKey(tree.PublicKey.GetDer())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the public key: %v", err)
+	}
+	sigHash, err := trees.Hash(tree)
+	if err != nil {
+		return nil, err
+	}
+	f := &fsck{
+		tree:      tree,
+		hasher:    hasher,
+		pubKey:    pubKey,
+		sigHash:   sigHash,
+		batchSize: batchSize,
+		cr:        (&compact.RangeFactory{Hash: hasher.HashChildren}).NewEmptyRange(0),
+	}
+	if tree.TreeType == trillian.TreeType_LOG {
+		f.indices = make(map[string]int64)
+	}
+
+	tx, err := ls.SnapshotForTree(ctx, tree)
+	if err == storage.ErrTreeNeedsInit {
+		if tx != nil {
+			tx.Close()
+		}
+		f.report("tree has no SignedLogRoot")
+		return f.problems, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer tx.Close()
+	f.tx = tx
+
+	if err := f.run(ctx); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return f.problems, nil
+}
+
+func (f *fsck) report(format string, args ...interface{}) {
+	problem := fmt.Sprintf(format, args...)
+	glog.Warningf("%d: %s", f.tree.TreeId, problem)
+	f.problems = append(f.problems, problem)
+}
+
+func (f *fsck) run(ctx context.Context) error {
+	slr, err := f.tx.LatestSignedLogRoot(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read the latest SignedLogRoot: %v", err)
+	}
+	if f.latest = f.verifyRoot(slr); f.latest == nil {
+		return nil
+	}
+
+	if hr, ok := f.tx.(storage.LogRootHistoryReader); ok {
+		if err := f.checkRoots(ctx, hr, slr); err != nil {
+			return err
+		}
+	} else {
+		glog.Warningf("%d: storage doesn't keep the SignedLogRoot history, checking the latest one only", f.tree.TreeId)
+		if err := f.checkRoot(ctx, f.latest); err != nil {
+			return err
+		}
+	}
+
+	// Check the leaves beyond the last root with a matching size, if any.
+	if err := f.readLeaves(ctx, f.latest.TreeSize); err != nil {
+		return err
+	}
+	if err := f.compareNodes(ctx); err != nil {
+		return err
+	}
+
+	if f.tree.TreeType == trillian.TreeType_LOG {
+		count, err := f.tx.GetSequencedLeafCount(ctx)
+		if err != nil {
+			return err
+		}
+		if count != int64(f.latest.TreeSize) {
+			f.report("found %d sequenced leaves, want %d", count, f.latest.TreeSize)
+		}
+	}
+	return nil
+}
+
+// verifyRoot checks the signature of a SignedLogRoot, and returns its
+// contents. Returns nil if the root can't be parsed.
+func (f *fsck) verifyRoot(slr *trillian.SignedLogRoot) *types.LogRootV1 {
+	var root types.LogRootV1
+	if err := root.UnmarshalBinary(slr.LogRoot); err != nil {
+		f.report("failed to parse SignedLogRoot %x: %v", slr.LogRoot, err)
+		return nil
+	}
+	if _, err := tcrypto.VerifySignedLogRoot(f.pubKey, f.sigHash, slr); err != nil {
+		f.report("revision %d: invalid SignedLogRoot signature: %v", root.Revision, err)
+	}
+	return &root
+}
+
+// checkRoots checks all the stored SignedLogRoots, from the oldest to the
+// latest one. The revision of each root must be one above the previous one.
+func (f *fsck) checkRoots(ctx context.Context, hr storage.LogRootHistoryReader, latest *trillian.SignedLogRoot) error {
+	var prev *types.LogRootV1
+	var last *trillian.SignedLogRoot
+	for start := int64(0); ; {
+		pageStart := start
+		slrs, err := hr.GetSignedLogRoots(ctx, start, f.batchSize)
+		if err != nil {
+			return fmt.Errorf("failed to read SignedLogRoots: %v", err)
+		}
+		if len(slrs) == 0 {
+			break
+		}
+		for _, slr := range slrs {
+			last = slr
+			root := f.verifyRoot(slr)
+			if root == nil {
+				continue
+			}
+			start = int64(root.TimestampNanos) + 1
+
+			wantRev := uint64(0)
+			if prev != nil {
+				wantRev = prev.Revision + 1
+			}
+			switch rev := root.Revision; {
+			case rev > wantRev:
+				f.report("no SignedLogRoots for revisions [%d, %d)", wantRev, rev)
+			case prev != nil && rev == prev.Revision:
+				f.report("revision %d: duplicate SignedLogRoot", rev)
+			case rev < wantRev:
+				f.report("revision %d: SignedLogRoot newer than the one of revision %d", rev, prev.Revision)
+			}
+			if err := f.checkRoot(ctx, root); err != nil {
+				return err
+			}
+			prev = root
+		}
+		if start <= pageStart {
+			return fmt.Errorf("failed to read SignedLogRoots past timestamp %d", start)
+		}
+	}
+
+	if last == nil {
+		f.report("no SignedLogRoots stored")
+	} else if !bytes.Equal(last.LogRoot, latest.LogRoot) || !bytes.Equal(last.LogRootSignature, latest.LogRootSignature) {
+		f.report("latest SignedLogRoot doesn't match the newest stored one")
+	}
+	return nil
+}
+
+// checkRoot recomputes the root hash of the tree at the size of the given
+// root, and compares it with the stored one. The roots must be checked in
+// increasing order of tree size, otherwise they are reported as inconsistent.
+func (f *fsck) checkRoot(ctx context.Context, root *types.LogRootV1) error {
+	switch {
+	case root.TreeSize > f.latest.TreeSize:
+		f.report("revision %d: tree size %d beyond the latest tree size %d", root.Revision, root.TreeSize, f.latest.TreeSize)
+		return nil
+	case root.TreeSize < f.next:
+		f.report("revision %d: tree size %d below the size %d of an older root", root.Revision, root.TreeSize, f.next)
+		return nil
+	}
+	if err := f.readLeaves(ctx, root.TreeSize); err != nil {
+		return err
+	}
+	if f.cr == nil {
+		return nil // The tree can't be recomputed.
+	}
+	hash, err := f.cr.GetRootHash(nil)
+	if err != nil {
+		return err
+	}
+	if f.cr.End() == 0 {
+		hash = f.hasher.EmptyRoot()
+	}
+	if !bytes.Equal(hash, root.RootHash) {
+		f.report("revision %d: root hash mismatch at tree size %d: stored %x, computed %x", root.Revision, root.TreeSize, root.RootHash, hash)
+	}
+	return nil
+}
+
+// readLeaves reads and checks the leaves up to the given tree size, and adds
+// them to the recomputed tree.
+func (f *fsck) readLeaves(ctx context.Context, size uint64) error {
+	for f.next < size {
+		count := size - f.next
+		if count > uint64(f.batchSize) {
+			count = uint64(f.batchSize)
+		}
+		leaves, err := f.tx.GetLeavesByRange(ctx, int64(f.next), int64(count))
+		if err != nil && count > 1 {
+			// Some storage implementations fail on gaps in the range, so fall
+			// back to reading the leaves one by one to find them.
+			glog.Warningf("%d: failed to read leaves [%d, %d), reading them one by one: %v", f.tree.TreeId, f.next, f.next+count, err)
+			leaves, err = f.readLeavesOneByOne(ctx, f.next, f.next+count)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read leaves [%d, %d): %v", f.next, f.next+count, err)
+		}
+
+		// The storage may return fewer leaves than requested, so only an empty
+		// result means that the range is missing. Gaps between the returned
+		// leaves are reported by addLeaf.
+		if len(leaves) == 0 {
+			f.missingLeaves(f.next + count)
+			continue
+		}
+		for _, leaf := range leaves {
+			if leaf.LeafIndex >= int64(size) {
+				break // Only PREORDERED_LOG trees return leaves beyond the size.
+			}
+			if err := f.addLeaf(ctx, leaf); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (f *fsck) readLeavesOneByOne(ctx context.Context, begin, end uint64) ([]*trillian.LogLeaf, error) {
+	var ret []*trillian.LogLeaf
+	for index := begin; index < end; index++ {
+		leaves, err := f.tx.GetLeavesByRange(ctx, int64(index), 1)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, leaves...)
+	}
+	return ret, nil
+}
+
+// missingLeaves reports the leaves in [f.next, end) as missing, and stops
+// recomputing the tree.
+func (f *fsck) missingLeaves(end uint64) {
+	if end <= f.next {
+		return
+	}
+	if f.cr != nil {
+		f.report("leaves [%d, %d) missing, the tree can't be recomputed beyond size %d", f.next, end, f.next)
+	} else {
+		f.report("leaves [%d, %d) missing", f.next, end)
+	}
+	f.cr, f.nodes = nil, nil
+	f.next = end
+}
+
+// addLeaf checks the given leaf, and adds it to the recomputed tree.
+func (f *fsck) addLeaf(ctx context.Context, leaf *trillian.LogLeaf) error {
+	index := leaf.LeafIndex
+	if index < int64(f.next) {
+		f.report("leaf %d: duplicate leaf with identity hash %x", index, leaf.LeafIdentityHash)
+		return nil
+	}
+	f.missingLeaves(uint64(index))
+	f.next = uint64(index) + 1
+
+	if !leaf.DataPruned {
+		if hash := f.hasher.HashLeaf(leaf.LeafValue); !bytes.Equal(hash, leaf.MerkleLeafHash) {
+			f.report("leaf %d: Merkle leaf hash mismatch: stored %x, computed %x", index, leaf.MerkleLeafHash, hash)
+		}
+	}
+	if f.indices != nil {
+		key := string(leaf.LeafIdentityHash)
+		if prev, ok := f.indices[key]; ok {
+			f.report("leaf %d: duplicate of leaf %d with identity hash %x", index, prev, leaf.LeafIdentityHash)
+		} else {
+			f.indices[key] = index
+		}
+	}
+
+	if f.cr == nil {
+		return nil
+	}
+	f.visit(compact.NewNodeID(0, uint64(index)), leaf.MerkleLeafHash)
+	if err := f.cr.Append(leaf.MerkleLeafHash, f.visit); err != nil {
+		return err
+	}
+	if len(f.nodes) >= f.batchSize {
+		return f.compareNodes(ctx)
+	}
+	return nil
+}
+
+func (f *fsck) visit(id compact.NodeID, hash []byte) {
+	f.nodes = append(f.nodes, merkleNode{id: id, hash: hash})
+}
+
+// compareNodes compares the pending recomputed nodes with the stored ones, as
+// of the latest revision.
+func (f *fsck) compareNodes(ctx context.Context) error {
+	if len(f.nodes) == 0 {
+		return nil
+	}
+	ids := make([]storage.NodeID, len(f.nodes))
+	for i, node := range f.nodes {
+		id, err := storage.NewNodeIDForTreeCoords(int64(node.id.Level), int64(node.id.Index), maxTreeDepth)
+		if err != nil {
+			return err
+		}
+		ids[i] = id
+	}
+	stored, err := f.tx.GetMerkleNodes(ctx, int64(f.latest.Revision), ids)
+	if err != nil {
+		return fmt.Errorf("failed to read Merkle nodes: %v", err)
+	}
+
+	// The stored nodes are returned in the requested order, but missing ones
+	// are skipped.
+	j := 0
+	for i, node := range f.nodes {
+		if j >= len(stored) || !stored[j].NodeID.Equivalent(ids[i]) {
+			f.report("node (level %d, index %d) missing", node.id.Level, node.id.Index)
+			continue
+		}
+		if !bytes.Equal(stored[j].Hash, node.hash) {
+			f.report("node (level %d, index %d) mismatch: stored %x, computed %x", node.id.Level, node.id.Index, stored[j].Hash, node.hash)
+		}
+		j++
+	}
+	if j < len(stored) {
+		return fmt.Errorf("got %d unexpected Merkle nodes", len(stored)-j)
+	}
+	f.nodes = f.nodes[:0]
+	return nil
+}
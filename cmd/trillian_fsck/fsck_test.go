@@ -0,0 +1,285 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+	"github.com/google/trillian/log"
+	"github.com/google/trillian/merkle/rfc6962"
+	"github.com/google/trillian/monitoring"
+	"github.com/google/trillian/quota"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/memory"
+	"github.com/google/trillian/storage/testonly"
+	"github.com/google/trillian/trees"
+	"github.com/google/trillian/types"
+	"github.com/google/trillian/util/clock"
+)
+
+// newLog creates a log in memory storage, and integrates the given number of
+// leaves into it, batchSize at a time. Each batch makes a new root.
+func newLog(ctx context.Context, t *testing.T, size, batchSize int) (storage.LogStorage, *trillian.Tree) {
+	t.Helper()
+	ts := memory.NewTreeStorage()
+	ls := memory.NewLogStorage(ts, monitoring.InertMetricFactory{})
+	tree, err := storage.CreateTree(ctx, memory.NewAdminStorage(ts), testonly.LogTree)
+	if err != nil {
+		t.Fatalf("CreateTree(): %v", err)
+	}
+	signer, err := trees.Signer(ctx, tree)
+	if err != nil {
+		t.Fatalf("Signer(): %v", err)
+	}
+	timeSource := clock.NewFake(time.Unix(1000, 0))
+
+	root, err := signer.SignLogRoot(&types.LogRootV1{
+		RootHash:       rfc6962.DefaultHasher.EmptyRoot(),
+		TimestampNanos: uint64(timeSource.Now().UnixNano()),
+	})
+	if err != nil {
+		t.Fatalf("SignLogRoot(): %v", err)
+	}
+	if err := ls.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		return tx.StoreSignedLogRoot(ctx, root)
+	}); err != nil {
+		t.Fatalf("StoreSignedLogRoot(): %v", err)
+	}
+
+	seq := log.NewSequencer(rfc6962.DefaultHasher, timeSource, ls, signer, nil, quota.Noop())
+	for begin := 0; begin < size; begin += batchSize {
+		var leaves []*trillian.LogLeaf
+		for i := begin; i < begin+batchSize && i < size; i++ {
+			data := []byte(fmt.Sprintf("leaf %d", i))
+			hash := sha256.Sum256(data)
+			leaves = append(leaves, &trillian.LogLeaf{
+				LeafValue:        data,
+				LeafIdentityHash: hash[:],
+				MerkleLeafHash:   rfc6962.DefaultHasher.HashLeaf(data),
+			})
+		}
+		timeSource.Set(timeSource.Now().Add(time.Second))
+		if _, err := ls.QueueLeaves(ctx, tree, leaves, timeSource.Now()); err != nil {
+			t.Fatalf("QueueLeaves(): %v", err)
+		}
+		timeSource.Set(timeSource.Now().Add(time.Second))
+		if got, err := seq.IntegrateBatch(ctx, tree, batchSize, 0, 24*time.Hour); err != nil || got != len(leaves) {
+			t.Fatalf("IntegrateBatch(): %d, %v, want %d leaves", got, err, len(leaves))
+		}
+	}
+	return ls, tree
+}
+
+// corruptStorage alters the leaves and SignedLogRoots read from the wrapped
+// storage.
+type corruptStorage struct {
+	storage.ReadOnlyLogStorage
+	leaf func(*trillian.LogLeaf) []*trillian.LogLeaf
+	root func(*trillian.SignedLogRoot, *types.LogRootV1) []*trillian.SignedLogRoot
+}
+
+func (s *corruptStorage) SnapshotForTree(ctx context.Context, tree *trillian.Tree) (storage.ReadOnlyLogTreeTX, error) {
+	tx, err := s.ReadOnlyLogStorage.SnapshotForTree(ctx, tree)
+	if err != nil {
+		return tx, err
+	}
+	return &corruptTX{ReadOnlyLogTreeTX: tx, s: s}, nil
+}
+
+type corruptTX struct {
+	storage.ReadOnlyLogTreeTX
+	s *corruptStorage
+}
+
+func (t *corruptTX) GetLeavesByRange(ctx context.Context, start, count int64) ([]*trillian.LogLeaf, error) {
+	leaves, err := t.ReadOnlyLogTreeTX.GetLeavesByRange(ctx, start, count)
+	if err != nil || t.s.leaf == nil {
+		return leaves, err
+	}
+	var ret []*trillian.LogLeaf
+	for _, leaf := range leaves {
+		ret = append(ret, t.s.leaf(proto.Clone(leaf).(*trillian.LogLeaf))...)
+	}
+	return ret, nil
+}
+
+func (t *corruptTX) GetSignedLogRoots(ctx context.Context, startNanos int64, count int) ([]*trillian.SignedLogRoot, error) {
+	slrs, err := t.ReadOnlyLogTreeTX.(storage.LogRootHistoryReader).GetSignedLogRoots(ctx, startNanos, count)
+	if err != nil || t.s.root == nil {
+		return slrs, err
+	}
+	var ret []*trillian.SignedLogRoot
+	for _, slr := range slrs {
+		var root types.LogRootV1
+		if err := root.UnmarshalBinary(slr.LogRoot); err != nil {
+			return nil, err
+		}
+		ret = append(ret, t.s.root(proto.Clone(slr).(*trillian.SignedLogRoot), &root)...)
+	}
+	return ret, nil
+}
+
+func TestCheckTree(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		desc string
+		leaf func(*trillian.LogLeaf) []*trillian.LogLeaf
+		root func(*trillian.SignedLogRoot, *types.LogRootV1) []*trillian.SignedLogRoot
+		want []string
+	}{
+		{desc: "ok"},
+		{
+			desc: "leaf-hash-mismatch",
+			leaf: func(leaf *trillian.LogLeaf) []*trillian.LogLeaf {
+				if leaf.LeafIndex == 5 {
+					leaf.MerkleLeafHash = rfc6962.DefaultHasher.HashLeaf([]byte("bad"))
+				}
+				return []*trillian.LogLeaf{leaf}
+			},
+			want: []string{
+				"leaf 5: Merkle leaf hash mismatch",
+				"revision 1: root hash mismatch at tree size 7",
+				"node (level 0, index 5) mismatch",
+				"node (level 1, index 2) mismatch",
+				"node (level 2, index 1) mismatch",
+				"node (level 3, index 0) mismatch",
+				"revision 2: root hash mismatch at tree size 14",
+				"node (level 4, index 0) mismatch",
+				"revision 3: root hash mismatch at tree size 21",
+				"revision 4: root hash mismatch at tree size 28",
+				"revision 5: root hash mismatch at tree size 30",
+			},
+		},
+		{
+			desc: "missing-leaves",
+			leaf: func(leaf *trillian.LogLeaf) []*trillian.LogLeaf {
+				if i := leaf.LeafIndex; i == 9 || i == 10 || i == 20 {
+					return nil
+				}
+				return []*trillian.LogLeaf{leaf}
+			},
+			want: []string{
+				"leaves [9, 11) missing, the tree can't be recomputed beyond size 9",
+				"leaves [20, 21) missing",
+			},
+		},
+		{
+			desc: "duplicate-leaf",
+			leaf: func(leaf *trillian.LogLeaf) []*trillian.LogLeaf {
+				if leaf.LeafIndex == 12 {
+					return []*trillian.LogLeaf{leaf, leaf}
+				}
+				return []*trillian.LogLeaf{leaf}
+			},
+			want: []string{"leaf 12: duplicate leaf"},
+		},
+		{
+			desc: "duplicate-identity-hash",
+			leaf: func(leaf *trillian.LogLeaf) []*trillian.LogLeaf {
+				if leaf.LeafIndex == 13 {
+					hash := sha256.Sum256([]byte("leaf 3"))
+					leaf.LeafIdentityHash = hash[:]
+				}
+				return []*trillian.LogLeaf{leaf}
+			},
+			want: []string{"leaf 13: duplicate of leaf 3"},
+		},
+		{
+			desc: "missing-root",
+			root: func(slr *trillian.SignedLogRoot, root *types.LogRootV1) []*trillian.SignedLogRoot {
+				if root.Revision == 2 || root.Revision == 3 {
+					return nil
+				}
+				return []*trillian.SignedLogRoot{slr}
+			},
+			want: []string{"no SignedLogRoots for revisions [2, 4)"},
+		},
+		{
+			desc: "duplicate-root",
+			root: func(slr *trillian.SignedLogRoot, root *types.LogRootV1) []*trillian.SignedLogRoot {
+				if root.Revision == 2 {
+					return []*trillian.SignedLogRoot{slr, slr}
+				}
+				return []*trillian.SignedLogRoot{slr}
+			},
+			want: []string{"revision 2: duplicate SignedLogRoot"},
+		},
+		{
+			desc: "bad-signature",
+			root: func(slr *trillian.SignedLogRoot, root *types.LogRootV1) []*trillian.SignedLogRoot {
+				if root.Revision == 4 {
+					slr.LogRootSignature = []byte("bad")
+				}
+				return []*trillian.SignedLogRoot{slr}
+			},
+			want: []string{"revision 4: invalid SignedLogRoot signature"},
+		},
+		{
+			desc: "bad-root-hash",
+			root: func(slr *trillian.SignedLogRoot, root *types.LogRootV1) []*trillian.SignedLogRoot {
+				if root.Revision == 3 {
+					root.RootHash = rfc6962.DefaultHasher.EmptyRoot()
+					slr.LogRoot, _ = root.MarshalBinary()
+				}
+				return []*trillian.SignedLogRoot{slr}
+			},
+			want: []string{
+				"revision 3: invalid SignedLogRoot signature",
+				"revision 3: root hash mismatch at tree size 21",
+			},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ls, tree := newLog(ctx, t, 30, 7)
+			cs := &corruptStorage{ReadOnlyLogStorage: ls, leaf: tc.leaf, root: tc.root}
+			problems, err := checkTree(ctx, cs, tree, 4)
+			if err != nil {
+				t.Fatalf("checkTree(): %v", err)
+			}
+			if got, want := len(problems), len(tc.want); got != want {
+				t.Errorf("checkTree(): got %d problems, want %d: %q", got, want, problems)
+			}
+			for i, problem := range problems {
+				if i < len(tc.want) && !strings.HasPrefix(problem, tc.want[i]) {
+					t.Errorf("checkTree(): problem %d = %q, want prefix %q", i, problem, tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckTreeNotInitialised(t *testing.T) {
+	ctx := context.Background()
+	ts := memory.NewTreeStorage()
+	ls := memory.NewLogStorage(ts, monitoring.InertMetricFactory{})
+	tree, err := storage.CreateTree(ctx, memory.NewAdminStorage(ts), testonly.LogTree)
+	if err != nil {
+		t.Fatalf("CreateTree(): %v", err)
+	}
+	problems, err := checkTree(ctx, ls, tree, 10)
+	if err != nil {
+		t.Fatalf("checkTree(): %v", err)
+	}
+	if got, want := problems, []string{"tree has no SignedLogRoot"}; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("checkTree(): %q, want %q", got, want)
+	}
+}
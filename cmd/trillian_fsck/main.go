@@ -0,0 +1,86 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package main contains the implementation and entry point for the
+// trillian_fsck command, which checks the integrity of a log tree directly in
+// storage.
+//
+// The command recomputes the Merkle tree from the stored leaf hashes, and
+// compares it with the stored tree nodes and with all the stored
+// SignedLogRoots, whose signatures are verified too. It reports every
+// mismatch, gap or duplicate found, and exits with status 1 if there are any.
+//
+// Example usage:
+// $ ./trillian_fsck --storage_system=mysql --mysql_uri=... --tree_id=123
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/golang/glog"
+	"github.com/google/trillian/monitoring"
+	"github.com/google/trillian/server"
+	"github.com/google/trillian/storage"
+
+	// Register key ProtoHandlers
+	_ "github.com/google/trillian/crypto/keys/der/proto"
+	_ "github.com/google/trillian/crypto/keys/pem/proto"
+	_ "github.com/google/trillian/crypto/keys/pkcs11/proto"
+
+	// Load hashers
+	_ "github.com/google/trillian/merkle/rfc6962"
+)
+
+var (
+	treeID    = flag.Int64("tree_id", 0, "The ID of the log tree to check")
+	batchSize = flag.Int("batch_size", 1024, "The number of leaves, nodes or roots read at once")
+)
+
+func main() {
+	flag.Parse()
+	defer glog.Flush()
+
+	if *treeID == 0 {
+		glog.Exit("--tree_id must be set")
+	}
+	ctx := context.Background()
+
+	sp, err := server.NewStorageProviderFromFlags(monitoring.InertMetricFactory{})
+	if err != nil {
+		glog.Exitf("Failed to get storage provider: %v", err)
+	}
+	defer sp.Close()
+
+	tree, err := storage.GetTree(ctx, sp.AdminStorage(), *treeID)
+	if err != nil {
+		glog.Exitf("Failed to read tree %d: %v", *treeID, err)
+	}
+	problems, err := checkTree(ctx, sp.LogStorage(), tree, *batchSize)
+	if err != nil {
+		glog.Exitf("Failed to check tree %d: %v", *treeID, err)
+	}
+
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+	if len(problems) > 0 {
+		fmt.Printf("Tree %d: %d problems found\n", *treeID, len(problems))
+		glog.Flush()
+		os.Exit(1)
+	}
+	fmt.Printf("Tree %d: OK\n", *treeID)
+}
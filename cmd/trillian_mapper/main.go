@@ -0,0 +1,147 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package main contains the implementation and entry point for the
+// trillian_mapper command, which continuously maps the leaves of a Trillian
+// log into a Trillian map with one of the built-in map functions:
+//
+//   - key_value: the log holds updates of keys, whose key is the extra data of
+//     the leaf and whose value is the leaf value. The map holds the latest value
+//     of each key, at index SHA-256(key).
+//   - leaf_index: the map holds, at index SHA-256(leaf value), the index of the
+//     first log leaf with that value, in decimal.
+//
+// Other mappings can be built with the mapper package.
+//
+// Example usage:
+// $ ./trillian_mapper --log_server=host:port --log_id=123 --map_server=host:port --map_id=456
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/golang/glog"
+	"github.com/google/trillian"
+	"github.com/google/trillian/client/rpcflags"
+	"github.com/google/trillian/maps/mapper"
+	"github.com/google/trillian/util"
+	"google.golang.org/grpc"
+)
+
+var (
+	logServer    = flag.String("log_server", "", "Address of the gRPC Trillian Log Server (host:port)")
+	logID        = flag.Int64("log_id", 0, "The ID of the log to map")
+	mapServer    = flag.String("map_server", "", "Address of the gRPC Trillian Map Server (host:port)")
+	mapID        = flag.Int64("map_id", 0, "The ID of the map to write to")
+	mapFunc      = flag.String("map_func", "key_value", "The map function to use, one of: key_value, leaf_index")
+	batchSize    = flag.Int64("batch_size", mapper.DefaultBatchSize, "Max number of log leaves mapped into a single map revision")
+	pollInterval = flag.Duration("poll_interval", mapper.DefaultPollInterval, "How often to check the log for new leaves")
+)
+
+var mapFuncs = map[string]mapper.MapFunc{
+	"key_value":  mapKeyValue,
+	"leaf_index": mapLeafIndex,
+}
+
+// mapKeyValue maps a log leaf holding a key in its extra data to a map leaf
+// holding its value.
+func mapKeyValue(leaf *trillian.LogLeaf) []mapper.MapMutation {
+	if len(leaf.ExtraData) == 0 {
+		return nil
+	}
+	index := sha256.Sum256(leaf.ExtraData)
+	return []mapper.MapMutation{mapper.SetValue(index[:], leaf.LeafValue)}
+}
+
+// mapLeafIndex maps a log leaf to a map leaf holding its index, unless the map
+// leaf is already set by an earlier log leaf with the same value.
+func mapLeafIndex(leaf *trillian.LogLeaf) []mapper.MapMutation {
+	index := sha256.Sum256(leaf.LeafValue)
+	return []mapper.MapMutation{{
+		Index: index[:],
+		Mutate: func(value []byte) ([]byte, error) {
+			if len(value) > 0 {
+				return value, nil
+			}
+			return []byte(strconv.FormatInt(leaf.LeafIndex, 10)), nil
+		},
+	}}
+}
+
+func getTree(ctx context.Context, conn *grpc.ClientConn, treeID int64) (*trillian.Tree, error) {
+	tree, err := trillian.NewTrillianAdminClient(conn).GetTree(ctx, &trillian.GetTreeRequest{TreeId: treeID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree %d: %v", treeID, err)
+	}
+	return tree, nil
+}
+
+func main() {
+	flag.Parse()
+	defer glog.Flush()
+
+	mapFn, ok := mapFuncs[*mapFunc]
+	if !ok {
+		var names []string
+		for name := range mapFuncs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		glog.Exitf("Unknown --map_func %q, want one of %v", *mapFunc, names)
+	}
+	if *logID == 0 || *mapID == 0 {
+		glog.Exit("--log_id and --map_id must be set")
+	}
+	ctx := context.Background()
+
+	dialOpts, err := rpcflags.NewClientDialOptionsFromFlags()
+	if err != nil {
+		glog.Exitf("Failed to determine dial options: %v", err)
+	}
+	logConn, err := grpc.Dial(*logServer, dialOpts...)
+	if err != nil {
+		glog.Exitf("Failed to dial %v: %v", *logServer, err)
+	}
+	defer logConn.Close()
+	mapConn, err := grpc.Dial(*mapServer, dialOpts...)
+	if err != nil {
+		glog.Exitf("Failed to dial %v: %v", *mapServer, err)
+	}
+	defer mapConn.Close()
+
+	logTree, err := getTree(ctx, logConn, *logID)
+	if err != nil {
+		glog.Exit(err)
+	}
+	mapTree, err := getTree(ctx, mapConn, *mapID)
+	if err != nil {
+		glog.Exit(err)
+	}
+	m, err := mapper.New(trillian.NewTrillianLogClient(logConn), logTree,
+		trillian.NewTrillianMapClient(mapConn), trillian.NewTrillianMapWriteClient(mapConn), mapTree,
+		mapFn, mapper.Options{BatchSize: *batchSize, PollInterval: *pollInterval})
+	if err != nil {
+		glog.Exitf("Failed to create mapper: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go util.AwaitSignal(ctx, cancel)
+	m.Run(ctx)
+}
@@ -0,0 +1,60 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/google/trillian"
+)
+
+func TestMapKeyValue(t *testing.T) {
+	if got := mapKeyValue(&trillian.LogLeaf{LeafValue: []byte("value")}); len(got) != 0 {
+		t.Errorf("mapKeyValue(no key): %d mutations, want none", len(got))
+	}
+
+	got := mapKeyValue(&trillian.LogLeaf{LeafValue: []byte("value"), ExtraData: []byte("key")})
+	if len(got) != 1 {
+		t.Fatalf("mapKeyValue(): %d mutations, want 1", len(got))
+	}
+	if want := sha256.Sum256([]byte("key")); !bytes.Equal(got[0].Index, want[:]) {
+		t.Errorf("mapKeyValue(): index %x, want %x", got[0].Index, want)
+	}
+	if value, err := got[0].Mutate([]byte("old")); err != nil || string(value) != "value" {
+		t.Errorf("Mutate(): %q, %v, want %q", value, err, "value")
+	}
+}
+
+func TestMapLeafIndex(t *testing.T) {
+	got := mapLeafIndex(&trillian.LogLeaf{LeafValue: []byte("value"), LeafIndex: 42})
+	if len(got) != 1 {
+		t.Fatalf("mapLeafIndex(): %d mutations, want 1", len(got))
+	}
+	if want := sha256.Sum256([]byte("value")); !bytes.Equal(got[0].Index, want[:]) {
+		t.Errorf("mapLeafIndex(): index %x, want %x", got[0].Index, want)
+	}
+	for _, tc := range []struct {
+		value, want string
+	}{
+		{value: "", want: "42"},
+		{value: "7", want: "7"},
+	} {
+		if value, err := got[0].Mutate([]byte(tc.value)); err != nil || string(value) != tc.want {
+			t.Errorf("Mutate(%q): %q, %v, want %q", tc.value, value, err, tc.want)
+		}
+	}
+}
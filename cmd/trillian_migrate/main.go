@@ -0,0 +1,152 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package main contains the implementation and entry point for the
+// trillian_migrate command, which brings the schema of a MySQL or PostgreSQL
+// Trillian database up to date.
+//
+// Example usage:
+// $ ./trillian_migrate --storage_system=mysql --mysql_uri=... status
+// $ ./trillian_migrate --storage_system=mysql --mysql_uri=... --dry_run up
+// $ ./trillian_migrate --storage_system=mysql --mysql_uri=... up
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/golang/glog"
+	"github.com/google/trillian/storage/migrate"
+	"github.com/google/trillian/storage/mysql"
+	"github.com/google/trillian/storage/postgres"
+
+	// Load the MySQL and PG drivers.
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+)
+
+var (
+	storageSystem = flag.String("storage_system", "mysql", "Storage system of the database to migrate: mysql or postgres")
+	mySQLURI      = flag.String("mysql_uri", "test:zaphod@tcp(127.0.0.1:3306)/test", "Connection URI for MySQL database")
+	pgConnStr     = flag.String("pg_conn_str", "user=postgres dbname=test port=5432 sslmode=disable", "Connection string for Postgres database")
+	dryRun        = flag.Bool("dry_run", false, "If true, the statements of the pending migrations are printed but not run")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s [flags] up|status\n", os.Args[0])
+	fmt.Fprintln(os.Stderr, "  up      applies the pending migrations (only prints them with --dry_run)")
+	fmt.Fprintln(os.Stderr, "  status  prints the schema version and the pending migrations")
+	flag.PrintDefaults()
+}
+
+func newMigrator() (*migrate.Migrator, func() error, error) {
+	var db *sql.DB
+	var m *migrate.Migrator
+	var err error
+	switch *storageSystem {
+	case "mysql":
+		if db, err = mysql.OpenDB(*mySQLURI); err != nil {
+			return nil, nil, err
+		}
+		m, err = mysql.NewMigrator(db)
+	case "postgres":
+		if db, err = postgres.OpenDB(*pgConnStr); err != nil {
+			return nil, nil, err
+		}
+		m, err = postgres.NewMigrator(db)
+	default:
+		return nil, nil, fmt.Errorf("unsupported storage system %q", *storageSystem)
+	}
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	return m, db.Close, nil
+}
+
+func status(ctx context.Context, m *migrate.Migrator) error {
+	version, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Schema version: %d (latest: %d)\n", version, m.Latest())
+	if version == 0 {
+		fmt.Println("No Trillian schema found")
+		return nil
+	}
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		fmt.Println("Schema is up to date")
+		return nil
+	}
+	fmt.Println("Pending migrations:")
+	for _, mig := range pending {
+		fmt.Printf("  %d: %s\n", mig.Version, mig.Description)
+	}
+	return nil
+}
+
+func up(ctx context.Context, m *migrate.Migrator) error {
+	applied, err := m.Up(ctx, *dryRun, os.Stdout)
+	for _, mig := range applied {
+		if *dryRun {
+			glog.Infof("Dry run, not applied: version %d", mig.Version)
+		} else {
+			glog.Infof("Applied version %d: %s", mig.Version, mig.Description)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		fmt.Println("Schema is up to date")
+	}
+	return nil
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	defer glog.Flush()
+
+	if flag.NArg() != 1 {
+		usage()
+		os.Exit(2)
+	}
+	var run func(context.Context, *migrate.Migrator) error
+	switch cmd := flag.Arg(0); cmd {
+	case "up":
+		run = up
+	case "status":
+		run = status
+	default:
+		glog.Exitf("Unknown command %q, want up or status", cmd)
+	}
+
+	m, closeDB, err := newMigrator()
+	if err != nil {
+		glog.Exitf("Failed to open database: %v", err)
+	}
+	defer closeDB()
+
+	if err := run(context.Background(), m); err != nil {
+		glog.Exitf("Failed to %s: %v", flag.Arg(0), err)
+	}
+}
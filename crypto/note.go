@@ -0,0 +1,135 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crypto
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/types"
+	"golang.org/x/crypto/ed25519"
+)
+
+// Signature types of note keys, which are part of their key IDs.
+const (
+	noteAlgEd25519 = 0x01
+	noteAlgECDSA   = 0x02
+)
+
+// noteSigPrefix starts the signature lines of notes.
+const noteSigPrefix = "— "
+
+// NoteKeyID returns the ID of the key with the given name and public key,
+// which prefixes its signatures in the signature lines of notes. It is the
+// first 4 bytes of SHA-256(name || "\n" || type || key), where type is 0x01
+// for Ed25519 keys, followed by the 32 bytes of the key, and 0x02 for ECDSA
+// keys, followed by their DER-encoded SubjectPublicKeyInfo. Ed25519 key IDs
+// are therefore the same as in golang.org/x/mod/sumdb/note.
+func NoteKeyID(name string, pub crypto.PublicKey) (uint32, error) {
+	var key []byte
+	switch pub := pub.(type) {
+	case ed25519.PublicKey:
+		key = append([]byte{noteAlgEd25519}, pub...)
+	case *ecdsa.PublicKey:
+		der, err := x509.MarshalPKIXPublicKey(pub)
+		if err != nil {
+			return 0, err
+		}
+		key = append([]byte{noteAlgECDSA}, der...)
+	default:
+		return 0, fmt.Errorf("unsupported note key type: %T", pub)
+	}
+	h := sha256.New()
+	h.Write([]byte(name + "\n"))
+	h.Write(key)
+	return binary.BigEndian.Uint32(h.Sum(nil)), nil
+}
+
+// MarshalSignedNote returns the signed note form of a SignedLogRoot in the
+// LOG_ROOT_FORMAT_CHECKPOINT format: its checkpoint text, followed by a blank
+// line and a signature line with the origin of the checkpoint as key name.
+// pub is the public key of the log, and is used to compute the key ID.
+func MarshalSignedNote(r *trillian.SignedLogRoot, pub crypto.PublicKey) ([]byte, error) {
+	origin, _, err := types.ParseCheckpoint(r.LogRoot)
+	if err != nil {
+		return nil, err
+	}
+	keyID, err := NoteKeyID(origin, pub)
+	if err != nil {
+		return nil, err
+	}
+	sig := make([]byte, 4, 4+len(r.LogRootSignature))
+	binary.BigEndian.PutUint32(sig, keyID)
+	sig = append(sig, r.LogRootSignature...)
+
+	var b bytes.Buffer
+	b.Write(r.LogRoot)
+	fmt.Fprintf(&b, "\n%s%s %s\n", noteSigPrefix, origin, base64.StdEncoding.EncodeToString(sig))
+	return b.Bytes(), nil
+}
+
+// VerifySignedNote verifies a checkpoint in signed note form, as returned by
+// MarshalSignedNote, and returns the log root it holds. The note must have a
+// valid signature from pub under the origin of the checkpoint; signature
+// lines of other keys, e.g. of witnesses, are ignored.
+func VerifySignedNote(pub crypto.PublicKey, hash crypto.Hash, note []byte) (*types.LogRootV1, error) {
+	i := bytes.Index(note, []byte("\n\n"))
+	if i < 0 {
+		return nil, errors.New("malformed note: no signatures")
+	}
+	text, sigs := note[:i+1], string(note[i+2:])
+	origin, _, err := types.ParseCheckpoint(text)
+	if err != nil {
+		return nil, err
+	}
+	keyID, err := NoteKeyID(origin, pub)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(sigs, "\n") {
+		return nil, errors.New("malformed note: signatures don't end with a newline")
+	}
+
+	var logSig []byte
+	for _, line := range strings.Split(sigs[:len(sigs)-1], "\n") {
+		if !strings.HasPrefix(line, noteSigPrefix) {
+			return nil, fmt.Errorf("malformed note signature line %q", line)
+		}
+		fields := strings.Split(line[len(noteSigPrefix):], " ")
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("malformed note signature line %q", line)
+		}
+		sig, err := base64.StdEncoding.DecodeString(fields[1])
+		if err != nil || len(sig) < 5 {
+			return nil, fmt.Errorf("malformed note signature line %q", line)
+		}
+		if fields[0] == origin && binary.BigEndian.Uint32(sig) == keyID && logSig == nil {
+			logSig = sig[4:]
+		}
+	}
+	if logSig == nil {
+		return nil, fmt.Errorf("note has no signature by key %s+%08x", origin, keyID)
+	}
+	return VerifySignedLogRoot(pub, hash, &trillian.SignedLogRoot{LogRoot: text, LogRootSignature: logSig})
+}
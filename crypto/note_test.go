@@ -0,0 +1,149 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crypto
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/crypto/keys/pem"
+	"github.com/google/trillian/testonly"
+	"github.com/google/trillian/types"
+	"golang.org/x/crypto/ed25519"
+)
+
+func TestNoteKeyID(t *testing.T) {
+	// The verifier key "PeterNeumann+c74f20a3+ARpc2QcUPDhMQegwxbzhKqiBfsVkmqq/LDE4izWy10TW"
+	// from the documentation of golang.org/x/mod/sumdb/note.
+	key, err := base64.StdEncoding.DecodeString("ARpc2QcUPDhMQegwxbzhKqiBfsVkmqq/LDE4izWy10TW")
+	if err != nil {
+		t.Fatalf("DecodeString(): %v", err)
+	}
+	id, err := NoteKeyID("PeterNeumann", ed25519.PublicKey(key[1:]))
+	if err != nil {
+		t.Fatalf("NoteKeyID(): %v", err)
+	}
+	if got, want := id, uint32(0xc74f20a3); got != want {
+		t.Errorf("NoteKeyID(): %08x, want %08x", got, want)
+	}
+}
+
+func TestSignLogRootCheckpoint(t *testing.T) {
+	root := &types.LogRootV1{
+		TreeSize:       123,
+		RootHash:       []byte("01234567890123456789012345678901"),
+		TimestampNanos: 1500000000000000000,
+		Revision:       7,
+		Metadata:       []byte("metadata"),
+	}
+	for _, test := range []struct {
+		name, pem, password string
+	}{
+		{name: "ECDSA", pem: privPEM},
+		{name: "Ed25519", pem: ed25519PEM},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			key, err := pem.UnmarshalPrivateKey(test.pem, test.password)
+			if err != nil {
+				t.Fatalf("UnmarshalPrivateKey(): %v", err)
+			}
+			signer := NewSigner(0, key, crypto.SHA256)
+			signer.LogRootFormat = trillian.LogRootFormat_LOG_ROOT_FORMAT_CHECKPOINT
+			signer.CheckpointOrigin = "example.com/log"
+
+			slr, err := signer.SignLogRoot(root)
+			if err != nil {
+				t.Fatalf("SignLogRoot(): %v", err)
+			}
+			if !strings.HasPrefix(string(slr.LogRoot), "example.com/log\n123\nMDEyMzQ1Njc4OTAxMjM0NTY3ODkwMTIzNDU2Nzg5MDE=\n") {
+				t.Errorf("SignLogRoot(): unexpected checkpoint %q", slr.LogRoot)
+			}
+			got, err := VerifySignedLogRoot(key.Public(), crypto.SHA256, slr)
+			if err != nil {
+				t.Fatalf("VerifySignedLogRoot(): %v", err)
+			}
+			if !reflect.DeepEqual(got, root) {
+				t.Errorf("VerifySignedLogRoot(): %+v, want %+v", got, root)
+			}
+
+			note, err := MarshalSignedNote(slr, key.Public())
+			if err != nil {
+				t.Fatalf("MarshalSignedNote(): %v", err)
+			}
+			if !bytes.HasPrefix(note, append(slr.LogRoot, []byte("\n— example.com/log ")...)) {
+				t.Errorf("MarshalSignedNote(): unexpected note %q", note)
+			}
+			got, err = VerifySignedNote(key.Public(), crypto.SHA256, note)
+			if err != nil {
+				t.Fatalf("VerifySignedNote(): %v", err)
+			}
+			if !reflect.DeepEqual(got, root) {
+				t.Errorf("VerifySignedNote(): %+v, want %+v", got, root)
+			}
+
+			// Signatures of other keys are skipped.
+			cosigned := append(append([]byte{}, note...), []byte("— witness.example.com AAAAAAAAAAA=\n")...)
+			if _, err := VerifySignedNote(key.Public(), crypto.SHA256, cosigned); err != nil {
+				t.Errorf("VerifySignedNote(cosigned): %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifySignedNoteErrors(t *testing.T) {
+	key, err := pem.UnmarshalPrivateKey(privPEM, "")
+	if err != nil {
+		t.Fatalf("UnmarshalPrivateKey(): %v", err)
+	}
+	signer := NewSigner(0, key, crypto.SHA256)
+	signer.LogRootFormat = trillian.LogRootFormat_LOG_ROOT_FORMAT_CHECKPOINT
+	signer.CheckpointOrigin = "example.com/log"
+	slr, err := signer.SignLogRoot(&types.LogRootV1{TreeSize: 5, RootHash: []byte("hash")})
+	if err != nil {
+		t.Fatalf("SignLogRoot(): %v", err)
+	}
+	note, err := MarshalSignedNote(slr, key.Public())
+	if err != nil {
+		t.Fatalf("MarshalSignedNote(): %v", err)
+	}
+	otherKey, err := pem.UnmarshalPrivateKey(testonly.DemoPrivateKey, testonly.DemoPrivateKeyPass)
+	if err != nil {
+		t.Fatalf("UnmarshalPrivateKey(): %v", err)
+	}
+
+	for _, test := range []struct {
+		desc string
+		pub  crypto.PublicKey
+		note string
+	}{
+		{desc: "otherKey", pub: otherKey.Public(), note: string(note)},
+		{desc: "noSignatures", pub: key.Public(), note: string(slr.LogRoot)},
+		{desc: "tamperedSize", pub: key.Public(), note: strings.Replace(string(note), "\n5\n", "\n6\n", 1)},
+		{desc: "otherOrigin", pub: key.Public(), note: strings.Replace(string(note), "example.com/log", "example.com/other", -1)},
+		{desc: "malformedSignature", pub: key.Public(), note: string(note) + "— witness\n"},
+		{desc: "noFinalNewline", pub: key.Public(), note: strings.TrimSuffix(string(note), "\n")},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			if root, err := VerifySignedNote(test.pub, crypto.SHA256, []byte(test.note)); err == nil {
+				t.Errorf("VerifySignedNote(): %+v, want error", root)
+			}
+		})
+	}
+}
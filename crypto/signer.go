@@ -21,6 +21,7 @@ import (
 
 	"github.com/golang/glog"
 	"github.com/google/trillian"
+	"github.com/google/trillian/crypto/sigpb"
 	"github.com/google/trillian/types"
 	"golang.org/x/crypto/ed25519"
 )
@@ -34,6 +35,12 @@ type Signer struct {
 	// If Hash is noHash (zero), the signer expects to be given the full message not a hashed digest.
 	Hash   crypto.Hash
 	Signer crypto.Signer
+	// LogRootFormat is the format of the log roots signed by SignLogRoot, with
+	// LOG_ROOT_FORMAT_UNKNOWN meaning LOG_ROOT_FORMAT_V1.
+	LogRootFormat trillian.LogRootFormat
+	// CheckpointOrigin is the origin of the checkpoints signed by SignLogRoot
+	// if LogRootFormat is LOG_ROOT_FORMAT_CHECKPOINT.
+	CheckpointOrigin string
 }
 
 // NewSigner returns a new signer. The signer will set the KeyHint field, when available, with KeyID.
@@ -76,9 +83,10 @@ func (s *Signer) Sign(data []byte) ([]byte, error) {
 	return s.Signer.Sign(rand.Reader, digest, s.Hash)
 }
 
-// SignLogRoot returns a complete SignedLogRoot (including signature).
+// SignLogRoot returns a complete SignedLogRoot (including signature), in the
+// format selected by s.LogRootFormat.
 func (s *Signer) SignLogRoot(r *types.LogRootV1) (*trillian.SignedLogRoot, error) {
-	logRoot, err := r.MarshalBinary()
+	logRoot, err := r.MarshalForConfig(&trillian.LogRootConfig{Format: s.LogRootFormat, Origin: s.CheckpointOrigin})
 	if err != nil {
 		return nil, err
 	}
@@ -113,3 +121,61 @@ func (s *Signer) SignMapRoot(r *types.MapRootV1) (*trillian.SignedMapRoot, error
 		Signature: signature,
 	}, nil
 }
+
+// SignEntryTimestamp returns a complete SignedEntryTimestamp (including
+// signature).
+func (s *Signer) SignEntryTimestamp(e *types.EntryTimestampV1) (*trillian.SignedEntryTimestamp, error) {
+	entryTimestamp, err := e.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	signature, err := s.Sign(entryTimestamp)
+	if err != nil {
+		glog.Warningf("%v: signer failed to sign entry timestamp: %v", s.KeyHint, err)
+		return nil, err
+	}
+
+	hashAlgorithm := sigpb.DigitallySigned_NONE
+	if s.Hash == crypto.SHA256 {
+		hashAlgorithm = sigpb.DigitallySigned_SHA256
+	}
+	return &trillian.SignedEntryTimestamp{
+		TimestampNanos:   int64(e.TimestampNanos),
+		LogId:            int64(e.LogID),
+		LeafIdentityHash: e.LeafIdentityHash,
+		Signature: &sigpb.DigitallySigned{
+			HashAlgorithm:      hashAlgorithm,
+			SignatureAlgorithm: SignatureAlgorithm(s.Public()),
+			Signature:          signature,
+		},
+	}, nil
+}
+
+// SignCosignature returns a complete LogRootCosignature (including signature)
+// over the log root in c.
+func (s *Signer) SignCosignature(c *types.CosignatureV1) (*trillian.LogRootCosignature, error) {
+	cosignature, err := c.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	signature, err := s.Sign(cosignature)
+	if err != nil {
+		glog.Warningf("%s: signer failed to sign cosignature: %v", c.WitnessID, err)
+		return nil, err
+	}
+
+	hashAlgorithm := sigpb.DigitallySigned_NONE
+	if s.Hash == crypto.SHA256 {
+		hashAlgorithm = sigpb.DigitallySigned_SHA256
+	}
+	return &trillian.LogRootCosignature{
+		WitnessId:      string(c.WitnessID),
+		LogId:          int64(c.LogID),
+		TimestampNanos: int64(c.TimestampNanos),
+		Signature: &sigpb.DigitallySigned{
+			HashAlgorithm:      hashAlgorithm,
+			SignatureAlgorithm: SignatureAlgorithm(s.Public()),
+			Signature:          signature,
+		},
+	}, nil
+}
@@ -20,7 +20,9 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/trillian"
 	"github.com/google/trillian/crypto/keys/pem"
+	"github.com/google/trillian/crypto/sigpb"
 	"github.com/google/trillian/testonly"
 	"github.com/google/trillian/types"
 )
@@ -137,3 +139,86 @@ func TestSignMapRoot(t *testing.T) {
 		}
 	}
 }
+
+func TestSignEntryTimestamp(t *testing.T) {
+	key, err := pem.UnmarshalPrivateKey(testonly.DemoPrivateKey, testonly.DemoPrivateKeyPass)
+	if err != nil {
+		t.Fatalf("Failed to open test key, err=%v", err)
+	}
+	signer := NewSigner(0, key, crypto.SHA256)
+
+	for _, et := range []types.EntryTimestampV1{
+		{LogID: 10, LeafIdentityHash: []byte("Angel"), TimestampNanos: 2267709},
+	} {
+		set, err := signer.SignEntryTimestamp(&et)
+		if err != nil {
+			t.Errorf("Failed to sign entry timestamp: %v", err)
+			continue
+		}
+		if got := len(set.GetSignature().GetSignature()); got == 0 {
+			t.Errorf("len(sig): %v, want > 0", got)
+		}
+		if got, want := set.GetSignature().GetHashAlgorithm(), sigpb.DigitallySigned_SHA256; got != want {
+			t.Errorf("HashAlgorithm: %v, want %v", got, want)
+		}
+
+		if _, err := VerifySignedEntryTimestamp(key.Public(), crypto.SHA256, set); err != nil {
+			t.Errorf("Verify(%v) failed: %v", et, err)
+		}
+		// Any change to the signed fields must invalidate the signature.
+		set.TimestampNanos++
+		if _, err := VerifySignedEntryTimestamp(key.Public(), crypto.SHA256, set); err == nil {
+			t.Errorf("Verify(%v) with modified timestamp succeeded", et)
+		}
+	}
+}
+
+func TestSignCosignature(t *testing.T) {
+	key, err := pem.UnmarshalPrivateKey(testonly.DemoPrivateKey, testonly.DemoPrivateKeyPass)
+	if err != nil {
+		t.Fatalf("Failed to open test key, err=%v", err)
+	}
+	signer := NewSigner(0, key, crypto.SHA256)
+
+	for _, root := range []types.LogRootV1{
+		{TimestampNanos: 2267709, RootHash: []byte("Islington"), TreeSize: 2},
+	} {
+		logRoot, err := root.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary(): %v", err)
+		}
+		slr := &trillian.SignedLogRoot{LogRoot: logRoot}
+		cosig, err := signer.SignCosignature(&types.CosignatureV1{
+			WitnessID:      []byte("witness"),
+			LogID:          10,
+			LogRoot:        logRoot,
+			TimestampNanos: 2267710,
+		})
+		if err != nil {
+			t.Errorf("Failed to sign cosignature: %v", err)
+			continue
+		}
+		if got, want := cosig.GetSignature().GetHashAlgorithm(), sigpb.DigitallySigned_SHA256; got != want {
+			t.Errorf("HashAlgorithm: %v, want %v", got, want)
+		}
+
+		if _, err := VerifyCosignature(key.Public(), crypto.SHA256, slr, cosig); err != nil {
+			t.Errorf("Verify(%v) failed: %v", root, err)
+		}
+		// The cosignature must not be valid for a different log root.
+		root.TreeSize++
+		otherRoot, err := root.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary(): %v", err)
+		}
+		other := &trillian.SignedLogRoot{LogRoot: otherRoot}
+		if _, err := VerifyCosignature(key.Public(), crypto.SHA256, other, cosig); err == nil {
+			t.Errorf("Verify(%v) with modified root succeeded", root)
+		}
+		// Nor for a different log.
+		cosig.LogId++
+		if _, err := VerifyCosignature(key.Public(), crypto.SHA256, slr, cosig); err == nil {
+			t.Errorf("Verify(%v) with modified log ID succeeded", root)
+		}
+	}
+}
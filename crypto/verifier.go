@@ -31,6 +31,8 @@ import (
 var errVerify = errors.New("signature verification failed")
 
 // VerifySignedLogRoot verifies the SignedLogRoot and returns its contents.
+// log_root may be in the LOG_ROOT_FORMAT_V1 or LOG_ROOT_FORMAT_CHECKPOINT
+// format, see types.LogRootV1.UnmarshalBinary.
 func VerifySignedLogRoot(pub crypto.PublicKey, hash crypto.Hash, r *trillian.SignedLogRoot) (*types.LogRootV1, error) {
 	if err := Verify(pub, hash, r.LogRoot, r.LogRootSignature); err != nil {
 		return nil, err
@@ -60,6 +62,49 @@ func VerifySignedMapRoot(pub crypto.PublicKey, hash crypto.Hash, smr *trillian.S
 	return &root, nil
 }
 
+// VerifySignedEntryTimestamp verifies the signature on the SignedEntryTimestamp
+// and returns the EntryTimestampV1 it covers.
+func VerifySignedEntryTimestamp(pub crypto.PublicKey, hash crypto.Hash, set *trillian.SignedEntryTimestamp) (*types.EntryTimestampV1, error) {
+	if set == nil {
+		return nil, errors.New("SignedEntryTimestamp is nil")
+	}
+	if set.Signature == nil {
+		return nil, errors.New("SignedEntryTimestamp.Signature is nil")
+	}
+	entryTimestamp := types.NewEntryTimestampV1(set)
+	data, err := entryTimestamp.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if err := Verify(pub, hash, data, set.Signature.Signature); err != nil {
+		return nil, err
+	}
+	return entryTimestamp, nil
+}
+
+// VerifyCosignature verifies the signature of a witness on the log root of r,
+// and returns the CosignatureV1 it covers. It doesn't verify r itself.
+func VerifyCosignature(pub crypto.PublicKey, hash crypto.Hash, r *trillian.SignedLogRoot, cosig *trillian.LogRootCosignature) (*types.CosignatureV1, error) {
+	if r == nil {
+		return nil, errors.New("SignedLogRoot is nil")
+	}
+	if cosig == nil {
+		return nil, errors.New("LogRootCosignature is nil")
+	}
+	if cosig.Signature == nil {
+		return nil, errors.New("LogRootCosignature.Signature is nil")
+	}
+	cosignature := types.NewCosignatureV1(r.LogRoot, cosig)
+	data, err := cosignature.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if err := Verify(pub, hash, data, cosig.Signature.Signature); err != nil {
+		return nil, err
+	}
+	return cosignature, nil
+}
+
 // Verify cryptographically verifies the output of Signer.
 func Verify(pub crypto.PublicKey, hasher crypto.Hash, data, sig []byte) error {
 	if sig == nil {
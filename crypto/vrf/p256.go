@@ -0,0 +1,271 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vrf implements the ECVRF-P256-SHA256-TAI verifiable random function
+// of RFC 9381.
+//
+// A VRF maps an input to a pseudorandom output under a private key, together
+// with a proof that anyone holding the public key can check. Maps use it to
+// derive leaf indexes from raw keys, so that the indexes do not reveal the
+// keys, yet clients can verify that the index of a key is the right one.
+//
+// The VRF key pair of a map is its signing key pair. Rotating the signing key
+// of a map therefore changes the index of every key: the leaves set under the
+// old key are no longer found by their keys, and have to be set again.
+package vrf
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+const (
+	// suite is the suite_string of ECVRF-P256-SHA256-TAI.
+	suite = 0x01
+
+	// pointSize is the size of a compressed point.
+	pointSize = 33
+	// challengeSize is the size of the challenge c in a proof.
+	challengeSize = 16
+	// scalarSize is the size of the scalar s in a proof.
+	scalarSize = 32
+
+	// ProofSize is the size of a VRF proof.
+	ProofSize = pointSize + challengeSize + scalarSize
+	// OutputSize is the size of a VRF output.
+	OutputSize = sha256.Size
+)
+
+var (
+	// ErrUnsupportedKey is returned for keys which are not on the P-256 curve.
+	ErrUnsupportedKey = errors.New("vrf: key is not a P-256 key")
+	// ErrInvalidProof is returned when a proof fails to verify.
+	ErrInvalidProof = errors.New("vrf: invalid proof")
+)
+
+// Prove returns the VRF output of alpha under priv, and its proof.
+func Prove(priv *ecdsa.PrivateKey, alpha []byte) (beta, pi []byte, err error) {
+	if priv.Curve != elliptic.P256() {
+		return nil, nil, ErrUnsupportedKey
+	}
+	curve := priv.Curve
+	params := curve.Params()
+
+	y := compress(priv.PublicKey.X, priv.PublicKey.Y)
+	hx, hy, err := encodeToCurve(curve, y, alpha)
+	if err != nil {
+		return nil, nil, err
+	}
+	h := compress(hx, hy)
+	gx, gy := curve.ScalarMult(hx, hy, priv.D.Bytes())
+
+	k := nonce(params.N, priv.D, h)
+	ux, uy := curve.ScalarBaseMult(k.Bytes())
+	vx, vy := curve.ScalarMult(hx, hy, k.Bytes())
+	c := challenge(y, h, compress(gx, gy), compress(ux, uy), compress(vx, vy))
+
+	s := new(big.Int).Mul(c, priv.D)
+	s.Add(s, k)
+	s.Mod(s, params.N)
+
+	pi = make([]byte, 0, ProofSize)
+	pi = append(pi, compress(gx, gy)...)
+	pi = append(pi, intToBytes(c, challengeSize)...)
+	pi = append(pi, intToBytes(s, scalarSize)...)
+	return proofToHash(pi[:pointSize]), pi, nil
+}
+
+// Verify checks that pi is a valid VRF proof of alpha under pub, and returns
+// the VRF output of alpha.
+func Verify(pub *ecdsa.PublicKey, alpha, pi []byte) ([]byte, error) {
+	if pub.Curve != elliptic.P256() {
+		return nil, ErrUnsupportedKey
+	}
+	curve := pub.Curve
+	params := curve.Params()
+	if !curve.IsOnCurve(pub.X, pub.Y) {
+		return nil, ErrUnsupportedKey
+	}
+	if len(pi) != ProofSize {
+		return nil, ErrInvalidProof
+	}
+	gx, gy, err := decompress(curve, pi[:pointSize])
+	if err != nil {
+		return nil, ErrInvalidProof
+	}
+	c := new(big.Int).SetBytes(pi[pointSize : pointSize+challengeSize])
+	s := new(big.Int).SetBytes(pi[pointSize+challengeSize:])
+	if s.Cmp(params.N) >= 0 {
+		return nil, ErrInvalidProof
+	}
+
+	y := compress(pub.X, pub.Y)
+	hx, hy, err := encodeToCurve(curve, y, alpha)
+	if err != nil {
+		return nil, err
+	}
+
+	// U = s*B - c*Y, V = s*H - c*Gamma.
+	sbx, sby := curve.ScalarBaseMult(s.Bytes())
+	cyx, cyy := curve.ScalarMult(pub.X, pub.Y, c.Bytes())
+	ux, uy := subtract(curve, sbx, sby, cyx, cyy)
+	shx, shy := curve.ScalarMult(hx, hy, s.Bytes())
+	cgx, cgy := curve.ScalarMult(gx, gy, c.Bytes())
+	vx, vy := subtract(curve, shx, shy, cgx, cgy)
+
+	got := challenge(y, compress(hx, hy), pi[:pointSize], compress(ux, uy), compress(vx, vy))
+	if got.Cmp(c) != 0 {
+		return nil, ErrInvalidProof
+	}
+	return proofToHash(pi[:pointSize]), nil
+}
+
+// ProofToHash returns the VRF output of a proof, without verifying it.
+func ProofToHash(pi []byte) ([]byte, error) {
+	if len(pi) != ProofSize {
+		return nil, ErrInvalidProof
+	}
+	return proofToHash(pi[:pointSize]), nil
+}
+
+// proofToHash returns the VRF output for the compressed point Gamma.
+func proofToHash(gamma []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{suite, 0x03})
+	h.Write(gamma)
+	h.Write([]byte{0x00})
+	return h.Sum(nil)
+}
+
+// encodeToCurve hashes alpha to a point of the curve with the try-and-increment
+// method, salted with the compressed public key y.
+func encodeToCurve(curve elliptic.Curve, y, alpha []byte) (*big.Int, *big.Int, error) {
+	for ctr := 0; ctr < 256; ctr++ {
+		h := sha256.New()
+		h.Write([]byte{suite, 0x01})
+		h.Write(y)
+		h.Write(alpha)
+		h.Write([]byte{byte(ctr), 0x00})
+		x, y, err := decompress(curve, append([]byte{0x02}, h.Sum(nil)...))
+		if err == nil {
+			return x, y, nil
+		}
+	}
+	return nil, nil, errors.New("vrf: failed to encode input to the curve")
+}
+
+// challenge returns the challenge c derived from the given compressed points.
+func challenge(points ...[]byte) *big.Int {
+	h := sha256.New()
+	h.Write([]byte{suite, 0x02})
+	for _, p := range points {
+		h.Write(p)
+	}
+	h.Write([]byte{0x00})
+	return new(big.Int).SetBytes(h.Sum(nil)[:challengeSize])
+}
+
+// nonce returns the deterministic nonce of RFC 6979 for the private scalar x
+// and the message m.
+func nonce(q, x *big.Int, m []byte) *big.Int {
+	h1 := sha256.Sum256(m)
+	z := new(big.Int).SetBytes(h1[:])
+	z.Mod(z, q)
+	seed := append(intToBytes(x, scalarSize), intToBytes(z, scalarSize)...)
+
+	mac := func(key []byte, data ...[]byte) []byte {
+		h := hmac.New(sha256.New, key)
+		for _, d := range data {
+			h.Write(d)
+		}
+		return h.Sum(nil)
+	}
+	v := make([]byte, sha256.Size)
+	for i := range v {
+		v[i] = 0x01
+	}
+	k := make([]byte, sha256.Size)
+	k = mac(k, v, []byte{0x00}, seed)
+	v = mac(k, v)
+	k = mac(k, v, []byte{0x01}, seed)
+	v = mac(k, v)
+	for {
+		v = mac(k, v)
+		t := new(big.Int).SetBytes(v)
+		if t.Sign() > 0 && t.Cmp(q) < 0 {
+			return t
+		}
+		k = mac(k, v, []byte{0x00})
+		v = mac(k, v)
+	}
+}
+
+// subtract returns (x1, y1) - (x2, y2).
+func subtract(curve elliptic.Curve, x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	if x2.Sign() == 0 && y2.Sign() == 0 {
+		return x1, y1
+	}
+	negY := new(big.Int).Sub(curve.Params().P, y2)
+	return curve.Add(x1, y1, x2, negY)
+}
+
+// compress returns the SEC1 compressed encoding of the point (x, y).
+func compress(x, y *big.Int) []byte {
+	b := make([]byte, 1, pointSize)
+	b[0] = 0x02 | byte(y.Bit(0))
+	return append(b, intToBytes(x, pointSize-1)...)
+}
+
+// decompress parses the SEC1 compressed encoding of a point of the curve.
+func decompress(curve elliptic.Curve, b []byte) (*big.Int, *big.Int, error) {
+	params := curve.Params()
+	if len(b) != pointSize || (b[0] != 0x02 && b[0] != 0x03) {
+		return nil, nil, errors.New("vrf: invalid point encoding")
+	}
+	x := new(big.Int).SetBytes(b[1:])
+	if x.Cmp(params.P) >= 0 {
+		return nil, nil, errors.New("vrf: invalid point encoding")
+	}
+
+	// y² = x³ - 3x + b.
+	y2 := new(big.Int).Mul(x, x)
+	y2.Mul(y2, x)
+	x3 := new(big.Int).Lsh(x, 1)
+	x3.Add(x3, x)
+	y2.Sub(y2, x3)
+	y2.Add(y2, params.B)
+	y2.Mod(y2, params.P)
+
+	y := new(big.Int).ModSqrt(y2, params.P)
+	if y == nil {
+		return nil, nil, errors.New("vrf: point is not on the curve")
+	}
+	if y.Bit(0) != uint(b[0]&1) {
+		y.Sub(params.P, y)
+	}
+	return x, y, nil
+}
+
+// intToBytes returns the big-endian encoding of n, left-padded to size bytes.
+func intToBytes(n *big.Int, size int) []byte {
+	b := n.Bytes()
+	if len(b) >= size {
+		return b
+	}
+	return append(make([]byte, size-len(b)), b...)
+}
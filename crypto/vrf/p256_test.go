@@ -0,0 +1,157 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrf
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("DecodeString(%q): %v", s, err)
+	}
+	return b
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey(): %v", err)
+	}
+	return priv
+}
+
+// TestVector checks the P-256 example of RFC 9381, Appendix B.1.
+func TestVector(t *testing.T) {
+	curve := elliptic.P256()
+	priv := &ecdsa.PrivateKey{D: new(big.Int).SetBytes(mustHex(t, "c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721"))}
+	priv.Curve = curve
+	priv.X, priv.Y = curve.ScalarBaseMult(priv.D.Bytes())
+	if got, want := compress(priv.X, priv.Y), mustHex(t, "0360fed4ba255a9d31c961eb74c6356d68c049b8923b61fa6ce669622e60f29fb6"); !bytes.Equal(got, want) {
+		t.Fatalf("public key: %x, want %x", got, want)
+	}
+
+	alpha := []byte("sample")
+	beta, pi, err := Prove(priv, alpha)
+	if err != nil {
+		t.Fatalf("Prove(): %v", err)
+	}
+	wantPi := mustHex(t, "035b5c726e8c0e2c488a107c600578ee75cb702343c153cb1eb8dec77f4b5071b4a53f0a46f018bc2c56e58d383f2305e0975972c26feea0eb122fe7893c15af376b33edf7de17c6ea056d4d82de6bc02f")
+	if !bytes.Equal(pi, wantPi) {
+		t.Errorf("Prove(): pi %x, want %x", pi, wantPi)
+	}
+	wantBeta := mustHex(t, "a3ad7b0ef73d8fc6655053ea22f9bede8c743f08bbed3d38821f0e16474b505e")
+	if !bytes.Equal(beta, wantBeta) {
+		t.Errorf("Prove(): beta %x, want %x", beta, wantBeta)
+	}
+
+	got, err := Verify(&priv.PublicKey, alpha, wantPi)
+	if err != nil {
+		t.Fatalf("Verify(): %v", err)
+	}
+	if !bytes.Equal(got, wantBeta) {
+		t.Errorf("Verify(): %x, want %x", got, wantBeta)
+	}
+}
+
+func TestProveVerify(t *testing.T) {
+	priv := newKey(t)
+	for _, alpha := range [][]byte{nil, []byte("alice"), []byte("bob"), bytes.Repeat([]byte("x"), 1000)} {
+		beta, pi, err := Prove(priv, alpha)
+		if err != nil {
+			t.Fatalf("Prove(%q): %v", alpha, err)
+		}
+		if got := len(beta); got != OutputSize {
+			t.Errorf("Prove(%q): len(beta) %d, want %d", alpha, got, OutputSize)
+		}
+		if got := len(pi); got != ProofSize {
+			t.Errorf("Prove(%q): len(pi) %d, want %d", alpha, got, ProofSize)
+		}
+		got, err := Verify(&priv.PublicKey, alpha, pi)
+		if err != nil {
+			t.Fatalf("Verify(%q): %v", alpha, err)
+		}
+		if !bytes.Equal(got, beta) {
+			t.Errorf("Verify(%q): %x, want %x", alpha, got, beta)
+		}
+		if got, err := ProofToHash(pi); err != nil || !bytes.Equal(got, beta) {
+			t.Errorf("ProofToHash(%q): %x, %v, want %x", alpha, got, err, beta)
+		}
+
+		// The output is deterministic.
+		beta2, pi2, err := Prove(priv, alpha)
+		if err != nil || !bytes.Equal(beta2, beta) || !bytes.Equal(pi2, pi) {
+			t.Errorf("Prove(%q) again: %x, %x, %v, want %x, %x", alpha, beta2, pi2, err, beta, pi)
+		}
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	priv := newKey(t)
+	alpha := []byte("alice")
+	_, pi, err := Prove(priv, alpha)
+	if err != nil {
+		t.Fatalf("Prove(): %v", err)
+	}
+
+	flip := func(i int) []byte {
+		b := append([]byte(nil), pi...)
+		b[i] ^= 0x01
+		return b
+	}
+	for _, test := range []struct {
+		desc  string
+		pub   *ecdsa.PublicKey
+		alpha []byte
+		pi    []byte
+	}{
+		{desc: "otherAlpha", pub: &priv.PublicKey, alpha: []byte("bob"), pi: pi},
+		{desc: "otherKey", pub: &newKey(t).PublicKey, alpha: alpha, pi: pi},
+		{desc: "shortProof", pub: &priv.PublicKey, alpha: alpha, pi: pi[:ProofSize-1]},
+		{desc: "longProof", pub: &priv.PublicKey, alpha: alpha, pi: append(pi, 0)},
+		{desc: "gamma", pub: &priv.PublicKey, alpha: alpha, pi: flip(1)},
+		{desc: "gammaPrefix", pub: &priv.PublicKey, alpha: alpha, pi: flip(0)},
+		{desc: "challenge", pub: &priv.PublicKey, alpha: alpha, pi: flip(pointSize)},
+		{desc: "scalar", pub: &priv.PublicKey, alpha: alpha, pi: flip(ProofSize - 1)},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			if _, err := Verify(test.pub, test.alpha, test.pi); err == nil {
+				t.Error("Verify(): nil, want error")
+			}
+		})
+	}
+}
+
+func TestUnsupportedKey(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey(): %v", err)
+	}
+	if _, _, err := Prove(priv, []byte("alice")); err != ErrUnsupportedKey {
+		t.Errorf("Prove(): %v, want %v", err, ErrUnsupportedKey)
+	}
+	if _, err := Verify(&priv.PublicKey, []byte("alice"), make([]byte, ProofSize)); err != ErrUnsupportedKey {
+		t.Errorf("Verify(): %v, want %v", err, ErrUnsupportedKey)
+	}
+}
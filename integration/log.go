@@ -168,6 +168,16 @@ func RunLogIntegration(client trillian.TrillianLogClient, params TestParameters)
 		}
 	}
 
+	// Probe the log with all of those leaf indices at once
+	if err := checkBatchInclusionProofs(params.TreeID, tree, client, params); err != nil {
+		return fmt.Errorf("log batch inclusion proof checks failed: %v", err)
+	}
+
+	// Probe the log with ranges of leaves
+	if err := checkRangeProofs(params.TreeID, tree, client, params); err != nil {
+		return fmt.Errorf("log range proof checks failed: %v", err)
+	}
+
 	// TODO(al): test some inclusion proofs by Merkle hash too.
 
 	// Step 6 - Test some consistency proofs
@@ -470,6 +480,86 @@ func checkInclusionProofsAtIndex(index int64, logID int64, tree *merkle.InMemory
 	return nil
 }
 
+// checkBatchInclusionProofs obtains and checks batch proofs for the inclusionProofTestIndices, and the
+// last leaf, at tree sizes up to 2 x the sequencing batch size (or number of leaves queued if less).
+// As for checkInclusionProofsAtIndex, the proofs are checked against the alternate Merkle Tree
+// implementation.
+func checkBatchInclusionProofs(logID int64, tree *merkle.InMemoryMerkleTree, client trillian.TrillianLogClient, params TestParameters) error {
+	verifier := merkle.NewLogVerifier(rfc6962.DefaultHasher)
+	for treeSize := int64(1); treeSize < min(params.LeafCount, int64(2*params.SequencerBatchSize)); treeSize++ {
+		var leafIndices []int64
+		for _, index := range inclusionProofTestIndices {
+			if index < treeSize-1 {
+				leafIndices = append(leafIndices, index)
+			}
+		}
+		leafIndices = append(leafIndices, treeSize-1)
+
+		ctx, cancel := getRPCDeadlineContext(params)
+		resp, err := client.GetBatchInclusionProof(ctx, &trillian.GetBatchInclusionProofRequest{
+			LogId:     logID,
+			LeafIndex: leafIndices,
+			TreeSize:  treeSize,
+		})
+		cancel()
+		if err != nil {
+			return fmt.Errorf("GetBatchInclusionProof(indices: %v, treeSize %d): %v", leafIndices, treeSize, err)
+		}
+
+		root := tree.RootAtSnapshot(treeSize).Hash()
+		leafHashes := make([][]byte, 0, len(resp.LeafIndex))
+		for _, index := range resp.LeafIndex {
+			// Offset by 1 to make up for C++ / Go implementation differences.
+			leafHashes = append(leafHashes, tree.LeafHash(index+1))
+		}
+		if err := verifier.VerifyBatchInclusionProof(resp.LeafIndex, treeSize, resp.Hashes, root, leafHashes); err != nil {
+			return fmt.Errorf("VerifyBatchInclusionProof(indices: %v, treeSize %d): %v", resp.LeafIndex, treeSize, err)
+		}
+	}
+
+	return nil
+}
+
+// checkRangeProofs obtains and checks range proofs for ranges starting at the
+// inclusionProofTestIndices, at tree sizes up to 2 x the sequencing batch size (or number of
+// leaves queued if less). The proofs are checked against the alternate Merkle Tree implementation.
+func checkRangeProofs(logID int64, tree *merkle.InMemoryMerkleTree, client trillian.TrillianLogClient, params TestParameters) error {
+	verifier := merkle.NewLogVerifier(rfc6962.DefaultHasher)
+	for treeSize := int64(1); treeSize < min(params.LeafCount, int64(2*params.SequencerBatchSize)); treeSize++ {
+		for _, start := range inclusionProofTestIndices {
+			if start >= treeSize {
+				continue
+			}
+			ctx, cancel := getRPCDeadlineContext(params)
+			resp, err := client.GetLeavesByRangeWithProof(ctx, &trillian.GetLeavesByRangeWithProofRequest{
+				LogId:      logID,
+				StartIndex: start,
+				Count:      treeSize,
+				TreeSize:   treeSize,
+			})
+			cancel()
+			if err != nil {
+				return fmt.Errorf("GetLeavesByRangeWithProof(start: %d, treeSize %d): %v", start, treeSize, err)
+			}
+			if len(resp.Leaves) == 0 {
+				return fmt.Errorf("GetLeavesByRangeWithProof(start: %d, treeSize %d): no leaves", start, treeSize)
+			}
+
+			root := tree.RootAtSnapshot(treeSize).Hash()
+			leafHashes := make([][]byte, 0, len(resp.Leaves))
+			for i := range resp.Leaves {
+				// Offset by 1 to make up for C++ / Go implementation differences.
+				leafHashes = append(leafHashes, tree.LeafHash(start+int64(i)+1))
+			}
+			if err := verifier.VerifyRangeProof(start, treeSize, resp.LeftHashes, resp.RightHashes, root, leafHashes); err != nil {
+				return fmt.Errorf("VerifyRangeProof(start: %d, treeSize %d): %v", start, treeSize, err)
+			}
+		}
+	}
+
+	return nil
+}
+
 func checkConsistencyProof(consistParams consistencyProofParams, treeID int64, tree *merkle.InMemoryMerkleTree, client trillian.TrillianLogClient, params TestParameters, batchSize int64) error {
 	// We expect the proof request to succeed
 	ctx, cancel := getRPCDeadlineContext(params)
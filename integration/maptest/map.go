@@ -32,6 +32,8 @@ import (
 	"github.com/google/trillian/examples/ct/ctmapper/ctmapperpb"
 	"github.com/google/trillian/testonly"
 	"github.com/google/trillian/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	stestonly "github.com/google/trillian/storage/testonly"
 )
@@ -57,6 +59,11 @@ var AllTests = TestTable{
 	{"Inclusion", RunInclusion},
 	{"InclusionBatch", RunInclusionBatch},
 	{"RunGetLeafByRevisionNoProof", RunGetLeafByRevisionNoProof},
+	{"ListLeavesByRevision", RunListLeavesByRevision},
+	{"RevisionDiff", RunRevisionDiff},
+	{"GetLeafHistory", RunGetLeafHistory},
+	{"CompressedInclusion", RunCompressedInclusion},
+	{"VRFInclusion", RunVRFInclusion},
 	{"WriteStress", RunWriteStress},
 }
 
@@ -619,6 +626,448 @@ func RunGetLeafByRevisionNoProof(ctx context.Context, t *testing.T, tadmin trill
 	}
 }
 
+// RunListLeavesByRevision checks that paging through the leaves of a map
+// revision returns all of them, in order.
+func RunListLeavesByRevision(ctx context.Context, t *testing.T, tadmin trillian.TrillianAdminClient, tmap trillian.TrillianMapClient, twrite trillian.TrillianMapWriteClient) {
+	tree, err := newTreeWithHasher(ctx, tadmin, tmap, trillian.HashStrategy_TEST_MAP_HASHER)
+	if err != nil {
+		t.Fatalf("newTreeWithHasher(): %v", err)
+	}
+	batchSize := 10
+	numBatches := 3
+	leafMap := writeBatch(ctx, t, tmap, twrite, tree, batchSize, numBatches)
+
+	for _, tc := range []struct {
+		desc     string
+		revision int64
+		pageSize int32
+		want     int
+	}{
+		{desc: "revision0", revision: 0, pageSize: 7, want: 0},
+		{desc: "revision1", revision: 1, pageSize: 7, want: batchSize},
+		{desc: "latest", revision: int64(numBatches), pageSize: 7, want: batchSize * numBatches},
+		{desc: "singlePage", revision: int64(numBatches), want: batchSize * numBatches},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			var leaves []*trillian.MapLeaf
+			req := &trillian.ListMapLeavesByRevisionRequest{MapId: tree.TreeId, Revision: tc.revision, PageSize: tc.pageSize}
+			for {
+				resp, err := tmap.ListLeavesByRevision(ctx, req)
+				if err != nil {
+					t.Fatalf("ListLeavesByRevision(): %v", err)
+				}
+				if tc.pageSize > 0 && len(resp.Leaves) > int(tc.pageSize) {
+					t.Errorf("ListLeavesByRevision(): %d leaves, want <= %d", len(resp.Leaves), tc.pageSize)
+				}
+				leaves = append(leaves, resp.Leaves...)
+				if resp.NextPageToken == "" {
+					break
+				}
+				req.PageToken = resp.NextPageToken
+			}
+
+			if got := len(leaves); got != tc.want {
+				t.Errorf("ListLeavesByRevision(): %d leaves, want %d", got, tc.want)
+			}
+			for i, leaf := range leaves {
+				if i > 0 && bytes.Compare(leaves[i-1].Index, leaf.Index) >= 0 {
+					t.Errorf("ListLeavesByRevision(): index %x after %x, want ascending order", leaf.Index, leaves[i-1].Index)
+				}
+				if want := leafMap[string(leaf.Index)]; !proto.Equal(leaf, want) {
+					t.Errorf("ListLeavesByRevision(): leaf %v, want %v", leaf, want)
+				}
+			}
+		})
+	}
+
+	// The write API lists the same leaves.
+	resp, err := twrite.ListLeavesByRevision(ctx, &trillian.ListMapLeavesByRevisionRequest{MapId: tree.TreeId, Revision: 1})
+	if err != nil {
+		t.Fatalf("ListLeavesByRevision(write): %v", err)
+	}
+	if got, want := len(resp.Leaves), batchSize; got != want {
+		t.Errorf("ListLeavesByRevision(write): %d leaves, want %d", got, want)
+	}
+}
+
+// RunRevisionDiff checks that the leaves set between two map revisions, and
+// their values, are returned and verified.
+func RunRevisionDiff(ctx context.Context, t *testing.T, tadmin trillian.TrillianAdminClient, tmap trillian.TrillianMapClient, twrite trillian.TrillianMapWriteClient) {
+	tree, err := newTreeWithHasher(ctx, tadmin, tmap, trillian.HashStrategy_TEST_MAP_HASHER)
+	if err != nil {
+		t.Fatalf("newTreeWithHasher(): %v", err)
+	}
+	mapClient, err := client.NewMapClientFromTree(tmap, tree)
+	if err != nil {
+		t.Fatalf("NewMapClientFromTree(): %v", err)
+	}
+	batchSize := 10
+	numBatches := 3
+	leafMap := writeBatch(ctx, t, tmap, twrite, tree, batchSize, numBatches)
+
+	// Revision 4 updates the first batch of leaves.
+	updated := createBatchLeaves(0, batchSize)
+	for _, l := range updated {
+		l.LeafValue = append(l.LeafValue, "-updated"...)
+	}
+	if _, err := twrite.WriteLeaves(ctx, &trillian.WriteMapLeavesRequest{MapId: tree.TreeId, Leaves: updated}); err != nil {
+		t.Fatalf("WriteLeaves(): %v", err)
+	}
+	updatedMap := make(map[string]*trillian.MapLeaf)
+	for _, l := range updated {
+		updatedMap[string(l.Index)] = l
+	}
+
+	for _, tc := range []struct {
+		desc     string
+		from, to int64
+		want     int
+	}{
+		{desc: "firstBatch", from: 0, to: 1, want: batchSize},
+		{desc: "lastBatches", from: 1, to: 3, want: 2 * batchSize},
+		{desc: "all", from: 0, to: 4, want: numBatches * batchSize},
+		{desc: "update", from: 3, to: 4, want: batchSize},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			diffs, err := mapClient.GetAndVerifyRevisionDiff(ctx, tc.from, tc.to)
+			if err != nil {
+				t.Fatalf("GetAndVerifyRevisionDiff(): %v", err)
+			}
+			if got := len(diffs); got != tc.want {
+				t.Errorf("GetAndVerifyRevisionDiff(): %d diffs, want %d", got, tc.want)
+			}
+			for _, d := range diffs {
+				leaf, ok := leafMap[string(d.Index)]
+				if !ok {
+					t.Fatalf("GetAndVerifyRevisionDiff(): unexpected index %x", d.Index)
+				}
+				if u, ok := updatedMap[string(d.Index)]; ok && tc.to == 4 {
+					if got, want := d.ToLeaf.Leaf.LeafValue, u.LeafValue; !bytes.Equal(got, want) {
+						t.Errorf("GetAndVerifyRevisionDiff(): to value %q, want %q", got, want)
+					}
+					if tc.from == 3 && !bytes.Equal(d.FromLeaf.Leaf.LeafValue, leaf.LeafValue) {
+						t.Errorf("GetAndVerifyRevisionDiff(): from value %q, want %q", d.FromLeaf.Leaf.LeafValue, leaf.LeafValue)
+					}
+				} else if got, want := d.ToLeaf.Leaf.LeafValue, leaf.LeafValue; !bytes.Equal(got, want) {
+					t.Errorf("GetAndVerifyRevisionDiff(): to value %q, want %q", got, want)
+				}
+				if tc.from == 0 && len(d.FromLeaf.Leaf.LeafValue) != 0 {
+					t.Errorf("GetAndVerifyRevisionDiff(): from value %q, want empty", d.FromLeaf.Leaf.LeafValue)
+				}
+			}
+		})
+	}
+
+	// Page through the indexes only.
+	req := &trillian.GetRevisionDiffRequest{MapId: tree.TreeId, FromRevision: 1, ToRevision: 3, PageSize: 7}
+	var diffs []*trillian.MapLeafDiff
+	for {
+		resp, err := tmap.GetRevisionDiff(ctx, req)
+		if err != nil {
+			t.Fatalf("GetRevisionDiff(): %v", err)
+		}
+		page, err := mapClient.VerifyRevisionDiffResponse(req, resp)
+		if err != nil {
+			t.Fatalf("VerifyRevisionDiffResponse(): %v", err)
+		}
+		for _, d := range page {
+			if d.FromLeaf != nil || d.ToLeaf != nil {
+				t.Errorf("GetRevisionDiff(): diff of %x has leaves, want none", d.Index)
+			}
+		}
+		diffs = append(diffs, page...)
+		if resp.NextPageToken == "" {
+			break
+		}
+		req.PageToken = resp.NextPageToken
+	}
+	if got, want := len(diffs), 2*batchSize; got != want {
+		t.Errorf("GetRevisionDiff(): %d diffs, want %d", got, want)
+	}
+
+	// A tampered leaf value doesn't verify.
+	req = &trillian.GetRevisionDiffRequest{MapId: tree.TreeId, FromRevision: 3, ToRevision: 4, IncludeValues: true}
+	resp, err := tmap.GetRevisionDiff(ctx, req)
+	if err != nil {
+		t.Fatalf("GetRevisionDiff(): %v", err)
+	}
+	resp.Diffs[0].ToLeaf.Leaf.LeafValue = []byte("tampered")
+	if _, err := mapClient.VerifyRevisionDiffResponse(req, resp); err == nil {
+		t.Error("VerifyRevisionDiffResponse(tampered): nil, want error")
+	}
+}
+
+// RunGetLeafHistory checks that the values a leaf had in a range of map
+// revisions are returned and verified.
+func RunGetLeafHistory(ctx context.Context, t *testing.T, tadmin trillian.TrillianAdminClient, tmap trillian.TrillianMapClient, twrite trillian.TrillianMapWriteClient) {
+	tree, err := newTreeWithHasher(ctx, tadmin, tmap, trillian.HashStrategy_TEST_MAP_HASHER)
+	if err != nil {
+		t.Fatalf("newTreeWithHasher(): %v", err)
+	}
+	mapClient, err := client.NewMapClientFromTree(tmap, tree)
+	if err != nil {
+		t.Fatalf("NewMapClientFromTree(): %v", err)
+	}
+	index := testonly.TransparentHash("history-key")
+	other := testonly.TransparentHash("other-key")
+
+	// Revision 3 sets the leaf to the value it already had.
+	for _, leaf := range []*trillian.MapLeaf{
+		{Index: index, LeafValue: []byte("value-1")},
+		{Index: other, LeafValue: []byte("other-2")},
+		{Index: index, LeafValue: []byte("value-1")},
+		{Index: index, LeafValue: []byte("value-4")},
+		{Index: other, LeafValue: []byte("other-5")},
+	} {
+		if _, err := twrite.WriteLeaves(ctx, &trillian.WriteMapLeavesRequest{MapId: tree.TreeId, Leaves: []*trillian.MapLeaf{leaf}}); err != nil {
+			t.Fatalf("WriteLeaves(): %v", err)
+		}
+	}
+
+	type version struct {
+		rev   uint64
+		value string
+	}
+	for _, tc := range []struct {
+		desc     string
+		from, to int64
+		want     []version
+	}{
+		{desc: "all", from: 0, to: 5, want: []version{{0, ""}, {1, "value-1"}, {4, "value-4"}}},
+		{desc: "unchanged", from: 1, to: 3, want: []version{{1, "value-1"}}},
+		{desc: "single", from: 2, to: 2, want: []version{{2, "value-1"}}},
+		{desc: "lastChange", from: 2, to: 4, want: []version{{2, "value-1"}, {4, "value-4"}}},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			versions, err := mapClient.GetAndVerifyLeafHistory(ctx, index, tc.from, tc.to)
+			if err != nil {
+				t.Fatalf("GetAndVerifyLeafHistory(): %v", err)
+			}
+			var got []version
+			for _, v := range versions {
+				var root types.MapRootV1
+				if err := root.UnmarshalBinary(v.MapRoot.MapRoot); err != nil {
+					t.Fatalf("UnmarshalBinary(): %v", err)
+				}
+				got = append(got, version{root.Revision, string(v.Leaf.Leaf.LeafValue)})
+			}
+			if !cmp.Equal(got, tc.want, cmp.AllowUnexported(version{})) {
+				t.Errorf("GetAndVerifyLeafHistory(): %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	// Page through the history, one revision of the leaf at a time.
+	req := &trillian.GetLeafHistoryRequest{MapId: tree.TreeId, Index: index, FromRevision: 0, ToRevision: 5, PageSize: 1}
+	var versions []*trillian.MapLeafVersion
+	for {
+		resp, err := tmap.GetLeafHistory(ctx, req)
+		if err != nil {
+			t.Fatalf("GetLeafHistory(): %v", err)
+		}
+		versions = append(versions, resp.Versions...)
+		if resp.NextPageToken == "" {
+			break
+		}
+		req.PageToken = resp.NextPageToken
+	}
+	req.PageToken = ""
+	if err := mapClient.VerifyLeafHistory(req, versions); err != nil {
+		t.Fatalf("VerifyLeafHistory(): %v", err)
+	}
+	if got, want := len(versions), 3; got != want {
+		t.Errorf("GetLeafHistory(): %d versions, want %d", got, want)
+	}
+
+	// Tampered or missing versions don't verify.
+	versions[1].Leaf.Leaf.LeafValue = []byte("tampered")
+	if err := mapClient.VerifyLeafHistory(req, versions); err == nil {
+		t.Error("VerifyLeafHistory(tampered): nil, want error")
+	}
+	if err := mapClient.VerifyLeafHistory(req, versions[2:]); err == nil {
+		t.Error("VerifyLeafHistory(missing first): nil, want error")
+	}
+}
+
+// RunCompressedInclusion checks that the inclusion proofs of map leaves are
+// returned and verified in each MapProofFormat, and that compressed proofs are
+// smaller.
+func RunCompressedInclusion(ctx context.Context, t *testing.T, tadmin trillian.TrillianAdminClient, tmap trillian.TrillianMapClient, twrite trillian.TrillianMapWriteClient) {
+	tree, err := newTreeWithHasher(ctx, tadmin, tmap, trillian.HashStrategy_TEST_MAP_HASHER)
+	if err != nil {
+		t.Fatalf("newTreeWithHasher(): %v", err)
+	}
+	mapClient, err := client.NewMapClientFromTree(tmap, tree)
+	if err != nil {
+		t.Fatalf("NewMapClientFromTree(): %v", err)
+	}
+	leafMap := writeBatch(ctx, t, tmap, twrite, tree, 10, 2)
+	indexes := make([][]byte, 0, len(leafMap)+1)
+	for _, l := range leafMap {
+		indexes = append(indexes, l.Index)
+	}
+	indexes = append(indexes, testonly.TransparentHash("unset-key"))
+
+	var fullSize int
+	for _, format := range []trillian.MapProofFormat{
+		trillian.MapProofFormat_MAP_PROOF_FORMAT_FULL,
+		trillian.MapProofFormat_MAP_PROOF_FORMAT_COMPRESSED,
+		trillian.MapProofFormat_MAP_PROOF_FORMAT_COMPRESSED_BATCH,
+	} {
+		t.Run(format.String(), func(t *testing.T) {
+			resp, err := tmap.GetLeavesByRevision(ctx, &trillian.GetMapLeavesByRevisionRequest{
+				MapId:       tree.TreeId,
+				Index:       indexes,
+				Revision:    2,
+				ProofFormat: format,
+			})
+			if err != nil {
+				t.Fatalf("GetLeavesByRevision(): %v", err)
+			}
+			if got := resp.ProofFormat; got != format {
+				t.Errorf("GetLeavesByRevision(): proof format %v, want %v", got, format)
+			}
+			leaves, err := mapClient.VerifyMapLeavesResponse(indexes, 2, resp)
+			if err != nil {
+				t.Fatalf("VerifyMapLeavesResponse(): %v", err)
+			}
+			for i, leaf := range leaves {
+				want := leafMap[string(indexes[i])].GetLeafValue()
+				if !bytes.Equal(leaf.LeafValue, want) {
+					t.Errorf("VerifyMapLeavesResponse(): value %q, want %q", leaf.LeafValue, want)
+				}
+			}
+
+			size := proto.Size(resp)
+			if format == trillian.MapProofFormat_MAP_PROOF_FORMAT_FULL {
+				fullSize = size
+			} else if size >= fullSize {
+				t.Errorf("GetLeavesByRevision(): %d bytes, want fewer than the %d bytes of full proofs", size, fullSize)
+			}
+			if format == trillian.MapProofFormat_MAP_PROOF_FORMAT_FULL {
+				return
+			}
+
+			// A proof with a tampered bitmap doesn't verify.
+			resp.MapLeafInclusion[0].CompressedInclusion.Bitmap[0] ^= 0x80
+			if _, err := mapClient.VerifyMapLeavesResponse(indexes, 2, resp); err == nil {
+				t.Error("VerifyMapLeavesResponse(tampered): nil, want error")
+			}
+		})
+	}
+
+	// The client negotiates compressed proofs.
+	leaves, err := mapClient.GetAndVerifyMapLeaves(ctx, indexes)
+	if err != nil {
+		t.Fatalf("GetAndVerifyMapLeaves(): %v", err)
+	}
+	if got, want := len(leaves), len(indexes); got != want {
+		t.Errorf("GetAndVerifyMapLeaves(): %d leaves, want %d", got, want)
+	}
+}
+
+// RunVRFInclusion checks that map leaves are set and got by raw key, with their
+// indexes derived by the map's VRF, and that the VRF proofs are verified.
+func RunVRFInclusion(ctx context.Context, t *testing.T, tadmin trillian.TrillianAdminClient, tmap trillian.TrillianMapClient, twrite trillian.TrillianMapWriteClient) {
+	tree, err := newTreeWithHasher(ctx, tadmin, tmap, trillian.HashStrategy_CONIKS_SHA512_256)
+	if err != nil {
+		t.Fatalf("newTreeWithHasher(): %v", err)
+	}
+	mapClient, err := client.NewMapClientFromTree(tmap, tree)
+	if err != nil {
+		t.Fatalf("NewMapClientFromTree(): %v", err)
+	}
+
+	keys := [][]byte{[]byte("alice"), []byte("bob")}
+	if _, err := tmap.SetLeaves(ctx, &trillian.SetMapLeavesRequest{
+		MapId:  tree.TreeId,
+		Leaves: []*trillian.MapLeaf{{LeafValue: []byte("A")}, {LeafValue: []byte("B")}},
+		Keys:   keys,
+	}); err != nil {
+		t.Fatalf("SetLeaves(): %v", err)
+	}
+
+	getKeys := [][]byte{keys[0], keys[1], []byte("carol")}
+	leaves, err := mapClient.GetAndVerifyMapLeavesByKey(ctx, getKeys)
+	if err != nil {
+		t.Fatalf("GetAndVerifyMapLeavesByKey(): %v", err)
+	}
+	for i, want := range []string{"A", "B", ""} {
+		if got := string(leaves[i].LeafValue); got != want {
+			t.Errorf("GetAndVerifyMapLeavesByKey(): %q has value %q, want %q", getKeys[i], got, want)
+		}
+	}
+
+	// The leaves are at the indexes derived from their keys.
+	indexes := [][]byte{leaves[0].Index, leaves[1].Index}
+	byIndex, err := mapClient.GetAndVerifyMapLeaves(ctx, indexes)
+	if err != nil {
+		t.Fatalf("GetAndVerifyMapLeaves(): %v", err)
+	}
+	for i, leaf := range byIndex {
+		if !bytes.Equal(leaf.LeafValue, leaves[i].LeafValue) {
+			t.Errorf("GetAndVerifyMapLeaves(): value %q, want %q", leaf.LeafValue, leaves[i].LeafValue)
+		}
+	}
+
+	// The VRF proof of a key doesn't verify for another one.
+	resp, err := tmap.GetLeaves(ctx, &trillian.GetMapLeavesRequest{MapId: tree.TreeId, Key: keys})
+	if err != nil {
+		t.Fatalf("GetLeaves(): %v", err)
+	}
+	if _, err := mapClient.VerifyMapLeavesResponseForKeys(keys, -1, resp); err != nil {
+		t.Errorf("VerifyMapLeavesResponseForKeys(): %v", err)
+	}
+	if _, err := mapClient.VerifyMapLeavesResponseForKeys([][]byte{keys[1], keys[0]}, -1, resp); err == nil {
+		t.Error("VerifyMapLeavesResponseForKeys(swapped keys): nil, want error")
+	}
+	resp.MapLeafInclusion[0].VrfProof, resp.MapLeafInclusion[1].VrfProof = resp.MapLeafInclusion[1].VrfProof, resp.MapLeafInclusion[0].VrfProof
+	if _, err := mapClient.VerifyMapLeavesResponseForKeys(keys, -1, resp); err == nil {
+		t.Error("VerifyMapLeavesResponseForKeys(swapped proofs): nil, want error")
+	}
+
+	for _, tc := range []struct {
+		desc string
+		call func() error
+	}{
+		{
+			desc: "getIndexAndKey",
+			call: func() error {
+				_, err := tmap.GetLeaves(ctx, &trillian.GetMapLeavesRequest{MapId: tree.TreeId, Index: indexes, Key: keys})
+				return err
+			},
+		},
+		{
+			desc: "setTooFewKeys",
+			call: func() error {
+				_, err := tmap.SetLeaves(ctx, &trillian.SetMapLeavesRequest{
+					MapId:  tree.TreeId,
+					Leaves: []*trillian.MapLeaf{{LeafValue: []byte("A")}, {LeafValue: []byte("B")}},
+					Keys:   keys[:1],
+				})
+				return err
+			},
+		},
+		{
+			desc: "setIndexAndKey",
+			call: func() error {
+				_, err := tmap.SetLeaves(ctx, &trillian.SetMapLeavesRequest{
+					MapId:  tree.TreeId,
+					Leaves: []*trillian.MapLeaf{{Index: indexes[0], LeafValue: []byte("A")}},
+					Keys:   keys[:1],
+				})
+				return err
+			},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			if err := tc.call(); status.Code(err) != codes.InvalidArgument {
+				t.Errorf("%v, want %v", err, codes.InvalidArgument)
+			}
+		})
+	}
+}
+
 // RunInclusionBatch performs checks on Trillian Map inclusion proofs, after setting and getting leafs in
 // larger batches, checking also the SignedMapRoot revisions along the way, for a variety of hash strategies.
 func RunInclusionBatch(ctx context.Context, t *testing.T, tadmin trillian.TrillianAdminClient, tmap trillian.TrillianMapClient, twrite trillian.TrillianMapWriteClient) {
@@ -20,6 +20,13 @@ import (
 	"log"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian/crypto/keys/der"
+	"github.com/google/trillian/crypto/keyspb"
+	"github.com/google/trillian/extension"
+	"github.com/google/trillian/monitoring"
+	"github.com/google/trillian/quota"
+	"github.com/google/trillian/storage/memory"
 	"github.com/google/trillian/storage/testdb"
 	"github.com/google/trillian/testonly/integration"
 
@@ -55,3 +62,31 @@ func TestMapIntegration(t *testing.T) {
 		})
 	}
 }
+
+func TestInMemoryMapIntegration(t *testing.T) {
+	if *server != "" {
+		t.Skip("Skipping in-memory map integration test, testing against remote server")
+	}
+	ctx := context.Background()
+	ts := memory.NewTreeStorage()
+	registry := extension.Registry{
+		AdminStorage:  memory.NewAdminStorage(ts),
+		MapStorage:    memory.NewMapStorage(ts),
+		QuotaManager:  quota.Noop(),
+		MetricFactory: monitoring.InertMetricFactory{},
+		NewKeyProto: func(ctx context.Context, spec *keyspb.Specification) (proto.Message, error) {
+			return der.NewProtoFromSpec(spec)
+		},
+	}
+	env, err := integration.NewMapEnvWithRegistry(registry, *singleTX)
+	if err != nil {
+		t.Fatalf("Could not create MapEnv: %v", err)
+	}
+	defer env.Close()
+
+	for _, test := range AllTests {
+		t.Run(test.Name, func(t *testing.T) {
+			test.Fn(ctx, t, env.Admin, env.Map, env.Write)
+		})
+	}
+}
@@ -58,3 +58,31 @@ func mapTree(mapID int64) *trillian.Tree {
 		HashStrategy: trillian.HashStrategy_TEST_MAP_HASHER,
 	}
 }
+
+// writeMapRevision stores a map revision with the given leaves, keyed by the
+// first byte of their 32 byte index.
+func writeMapRevision(ctx context.Context, t *testing.T, s storage.MapStorage, tree *trillian.Tree, revision int64, values map[byte]string) {
+	t.Helper()
+	signer := tcrypto.NewSigner(tree.TreeId, testonly.NewSignerWithFixedSig(nil, []byte("sig")), crypto.SHA256)
+	err := s.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+		for b, value := range values {
+			index := make([]byte, 32)
+			index[0] = b
+			if err := tx.Set(ctx, index, &trillian.MapLeaf{Index: index, LeafValue: []byte(value)}); err != nil {
+				return err
+			}
+		}
+		root, err := signer.SignMapRoot(&types.MapRootV1{
+			RootHash:       []byte("rootHash"),
+			TimestampNanos: uint64(revision),
+			Revision:       uint64(revision),
+		})
+		if err != nil {
+			return err
+		}
+		return tx.StoreSignedMapRoot(ctx, root)
+	})
+	if err != nil {
+		t.Fatalf("ReadWriteTransaction() = %v", err)
+	}
+}
@@ -16,11 +16,15 @@
 package storagetest
 
 import (
+	"bytes"
 	"context"
+	"reflect"
 	"testing"
 
 	"github.com/google/trillian"
 	"github.com/google/trillian/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	storageto "github.com/google/trillian/storage/testonly"
 )
@@ -88,3 +92,216 @@ func (*MapTests) TestMapSnapshot(ctx context.Context, t *testing.T, s storage.Ma
 		})
 	}
 }
+
+func (*MapTests) TestListLeaves(ctx context.Context, t *testing.T, s storage.MapStorage, as storage.AdminStorage) {
+	tree := createInitializedMapForTests(ctx, t, s, as)
+	index := func(b byte) []byte {
+		idx := make([]byte, 32)
+		idx[0] = b
+		return idx
+	}
+	writeMapRevision(ctx, t, s, tree, 1, map[byte]string{0x10: "a", 0x20: "b", 0x30: "c"})
+	writeMapRevision(ctx, t, s, tree, 2, map[byte]string{0x10: "a2", 0x20: "", 0x40: "d"})
+
+	tests := []struct {
+		desc       string
+		revision   int64
+		start, end []byte
+		limit      int
+		want       []string
+	}{
+		{desc: "revision0", revision: 0, limit: 10},
+		{desc: "revision1", revision: 1, limit: 10, want: []string{"a", "b", "c"}},
+		{desc: "revision2", revision: 2, limit: 10, want: []string{"a2", "", "c", "d"}},
+		{desc: "range", revision: 2, start: index(0x20), end: index(0x40), limit: 10, want: []string{"", "c"}},
+		{desc: "limit", revision: 2, limit: 2, want: []string{"a2", ""}},
+		{desc: "startAndLimit", revision: 2, start: index(0x15), limit: 2, want: []string{"", "c"}},
+		{desc: "emptyRange", revision: 2, start: index(0x41), end: index(0x50), limit: 10},
+		{desc: "noLimit", revision: 2, limit: 0},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			tx, err := s.SnapshotForTree(ctx, tree)
+			if err != nil {
+				t.Fatalf("SnapshotForTree()=_,%v; want _, nil", err)
+			}
+			defer tx.Close()
+
+			leaves, err := tx.ListLeaves(ctx, test.revision, test.start, test.end, test.limit)
+			if err != nil {
+				t.Fatalf("ListLeaves()=_,%v; want _, nil", err)
+			}
+			if err := tx.Commit(ctx); err != nil {
+				t.Errorf("Commit()=_,%v; want _,nil", err)
+			}
+			var got []string
+			for i, leaf := range leaves {
+				if i > 0 && bytes.Compare(leaves[i-1].Index, leaf.Index) >= 0 {
+					t.Errorf("ListLeaves(): index %x after %x, want ascending order", leaf.Index, leaves[i-1].Index)
+				}
+				got = append(got, string(leaf.LeafValue))
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ListLeaves(): values %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func (*MapTests) TestListChangedKeys(ctx context.Context, t *testing.T, s storage.MapStorage, as storage.AdminStorage) {
+	tree := createInitializedMapForTests(ctx, t, s, as)
+	index := func(b byte) []byte {
+		idx := make([]byte, 32)
+		idx[0] = b
+		return idx
+	}
+	writeMapRevision(ctx, t, s, tree, 1, map[byte]string{0x10: "a", 0x20: "b", 0x30: "c"})
+	writeMapRevision(ctx, t, s, tree, 2, map[byte]string{0x10: "a2", 0x40: "d"})
+	writeMapRevision(ctx, t, s, tree, 3, map[byte]string{0x20: ""})
+
+	tests := []struct {
+		desc     string
+		from, to int64
+		start    []byte
+		limit    int
+		want     []byte
+	}{
+		{desc: "revision1", from: 0, to: 1, limit: 10, want: []byte{0x10, 0x20, 0x30}},
+		{desc: "revision2", from: 1, to: 2, limit: 10, want: []byte{0x10, 0x40}},
+		{desc: "revisions2and3", from: 1, to: 3, limit: 10, want: []byte{0x10, 0x20, 0x40}},
+		{desc: "revision3", from: 2, to: 3, limit: 10, want: []byte{0x20}},
+		{desc: "limit", from: 0, to: 3, limit: 2, want: []byte{0x10, 0x20}},
+		{desc: "start", from: 0, to: 3, start: index(0x15), limit: 10, want: []byte{0x20, 0x30, 0x40}},
+		{desc: "noRevisions", from: 3, to: 3, limit: 10},
+		{desc: "noLimit", from: 0, to: 3, limit: 0},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			tx, err := s.SnapshotForTree(ctx, tree)
+			if err != nil {
+				t.Fatalf("SnapshotForTree()=_,%v; want _, nil", err)
+			}
+			defer tx.Close()
+
+			keys, err := tx.ListChangedKeys(ctx, test.from, test.to, test.start, test.limit)
+			if err != nil {
+				t.Fatalf("ListChangedKeys()=_,%v; want _, nil", err)
+			}
+			if err := tx.Commit(ctx); err != nil {
+				t.Errorf("Commit()=_,%v; want _,nil", err)
+			}
+			var want [][]byte
+			for _, b := range test.want {
+				want = append(want, index(b))
+			}
+			if len(keys) != len(want) {
+				t.Fatalf("ListChangedKeys(): %x, want %x", keys, want)
+			}
+			for i := range keys {
+				if !bytes.Equal(keys[i], want[i]) {
+					t.Errorf("ListChangedKeys(): %x, want %x", keys, want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func (*MapTests) TestListLeafRevisions(ctx context.Context, t *testing.T, s storage.MapStorage, as storage.AdminStorage) {
+	tree := createInitializedMapForTests(ctx, t, s, as)
+	writeMapRevision(ctx, t, s, tree, 1, map[byte]string{0x10: "a"})
+	writeMapRevision(ctx, t, s, tree, 2, map[byte]string{0x20: "b"})
+	writeMapRevision(ctx, t, s, tree, 3, map[byte]string{0x10: "a3", 0x20: "b3"})
+	writeMapRevision(ctx, t, s, tree, 4, map[byte]string{0x10: ""})
+
+	tests := []struct {
+		desc     string
+		key      byte
+		from, to int64
+		limit    int
+		want     []int64
+	}{
+		{desc: "all", key: 0x10, from: 0, to: 4, limit: 10, want: []int64{1, 3, 4}},
+		{desc: "other", key: 0x20, from: 0, to: 4, limit: 10, want: []int64{2, 3}},
+		{desc: "range", key: 0x10, from: 1, to: 3, limit: 10, want: []int64{3}},
+		{desc: "limit", key: 0x10, from: 0, to: 4, limit: 2, want: []int64{1, 3}},
+		{desc: "unset", key: 0x30, from: 0, to: 4, limit: 10, want: []int64{}},
+		{desc: "noRevisions", key: 0x10, from: 4, to: 4, limit: 10, want: []int64{}},
+		{desc: "noLimit", key: 0x10, from: 0, to: 4, limit: 0, want: []int64{}},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			tx, err := s.SnapshotForTree(ctx, tree)
+			if err != nil {
+				t.Fatalf("SnapshotForTree()=_,%v; want _, nil", err)
+			}
+			defer tx.Close()
+
+			index := make([]byte, 32)
+			index[0] = test.key
+			revs, err := tx.ListLeafRevisions(ctx, index, test.from, test.to, test.limit)
+			if err != nil {
+				t.Fatalf("ListLeafRevisions()=_,%v; want _, nil", err)
+			}
+			if err := tx.Commit(ctx); err != nil {
+				t.Errorf("Commit()=_,%v; want _,nil", err)
+			}
+			if !reflect.DeepEqual(revs, test.want) {
+				t.Errorf("ListLeafRevisions(): %v, want %v", revs, test.want)
+			}
+		})
+	}
+}
+
+func (*MapTests) TestCollectRevisions(ctx context.Context, t *testing.T, s storage.MapStorage, as storage.AdminStorage) {
+	tree := createInitializedMapForTests(ctx, t, s, as)
+	writeMapRevision(ctx, t, s, tree, 1, map[byte]string{0x10: "a", 0x20: "b"})
+	writeMapRevision(ctx, t, s, tree, 2, map[byte]string{0x10: "a2"})
+	writeMapRevision(ctx, t, s, tree, 3, map[byte]string{0x30: "c"})
+
+	err := s.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+		_, err := tx.CollectRevisions(ctx, 2, 1000)
+		return err
+	})
+	if status.Code(err) == codes.Unimplemented {
+		t.Skipf("CollectRevisions() not supported: %v", err)
+	} else if err != nil {
+		t.Fatalf("CollectRevisions()=%v; want nil", err)
+	}
+
+	tx, err := s.SnapshotForTree(ctx, tree)
+	if err != nil {
+		t.Fatalf("SnapshotForTree()=_,%v; want _, nil", err)
+	}
+	defer tx.Close()
+	for _, rev := range []int64{0, 1} {
+		if _, err := tx.GetSignedMapRoot(ctx, rev); status.Code(err) != codes.OutOfRange {
+			t.Errorf("GetSignedMapRoot(%d)=_,%v; want OutOfRange", rev, err)
+		}
+	}
+	for rev, want := range map[int64][]string{2: {"a2", "b", ""}, 3: {"a2", "b", "c"}} {
+		if _, err := tx.GetSignedMapRoot(ctx, rev); err != nil {
+			t.Errorf("GetSignedMapRoot(%d)=_,%v; want _,nil", rev, err)
+		}
+		var got []string
+		for _, b := range []byte{0x10, 0x20, 0x30} {
+			index := make([]byte, 32)
+			index[0] = b
+			leaves, err := tx.Get(ctx, rev, [][]byte{index})
+			if err != nil {
+				t.Fatalf("Get(%d)=_,%v; want _,nil", rev, err)
+			}
+			value := ""
+			if len(leaves) > 0 {
+				value = string(leaves[0].LeafValue)
+			}
+			got = append(got, value)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Get(%d): values %q, want %q", rev, got, want)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Errorf("Commit()=_,%v; want _,nil", err)
+	}
+}
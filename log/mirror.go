@@ -0,0 +1,373 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/google/trillian"
+	"github.com/google/trillian/extension"
+	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/hashers"
+	"github.com/google/trillian/monitoring"
+	"github.com/google/trillian/quota"
+	"github.com/google/trillian/trees"
+	"github.com/google/trillian/types"
+	"github.com/google/trillian/util/clock"
+	"github.com/google/trillian/util/election2"
+)
+
+// DefaultMirrorBatchSize is the default maximum number of leaves that a
+// Mirror integrates under a single local root.
+const DefaultMirrorBatchSize = 1000
+
+var (
+	mirrorOnce         sync.Once
+	mirrorUpstreamSize monitoring.Gauge
+	mirrorTreeSize     monitoring.Gauge
+	mirrorLeaves       monitoring.Counter
+	mirrorFailedSyncs  monitoring.Counter
+)
+
+func createMirrorMetrics(mf monitoring.MetricFactory) {
+	if mf == nil {
+		mf = monitoring.InertMetricFactory{}
+	}
+	mirrorUpstreamSize = mf.NewGauge("mirror_upstream_tree_size", "Tree size of the last verified upstream root", logIDLabel)
+	mirrorTreeSize = mf.NewGauge("mirror_tree_size", "Tree size of the mirrored log", logIDLabel)
+	mirrorLeaves = mf.NewCounter("mirror_leaves", "Number of leaves copied from the upstream log", logIDLabel)
+	mirrorFailedSyncs = mf.NewCounter("mirror_failed_syncs", "Number of times syncing with the upstream log has failed", logIDLabel)
+}
+
+// mirrorOpts are the options for reading mirrored trees. Writes through the
+// public API are forbidden for them, as they're FROZEN.
+var mirrorOpts = trees.NewGetOpts(trees.Query, trillian.TreeType_PREORDERED_LOG)
+
+// RootVerifier verifies signed roots of an upstream log, and their consistency
+// with a trusted root. It's implemented by client.LogVerifier.
+type RootVerifier interface {
+	// VerifyRoot verifies that newRoot is correctly signed, and consistent with
+	// trusted according to the consistency proof. It returns the contents of
+	// newRoot.
+	VerifyRoot(trusted *types.LogRootV1, newRoot *trillian.SignedLogRoot, consistency [][]byte) (*types.LogRootV1, error)
+}
+
+// MirrorOptions configures a Mirror.
+type MirrorOptions struct {
+	// UpstreamLogID is the ID of the log to mirror on the upstream server.
+	UpstreamLogID int64
+	// LogID is the ID of the local PREORDERED_LOG tree that the upstream log
+	// is copied to. The tree must be initialized, and FROZEN so that it can't
+	// be written to through the public API.
+	LogID int64
+	// BatchSize is the maximum number of leaves integrated under a single
+	// local root. DefaultMirrorBatchSize is used if it's not positive.
+	BatchSize int
+	// PollInterval is the time Run waits between checks for new upstream
+	// roots.
+	PollInterval time.Duration
+}
+
+// Mirror keeps a local tree in sync with a log served by an upstream Trillian
+// server. Every upstream root is verified before the leaves it covers are
+// copied, and every local root is checked to be consistent with a verified
+// upstream root before it's signed, so the local tree can only ever serve
+// roots that have been checked against the upstream log.
+//
+// Only one Mirror should run for a given local tree at a time, which RunElected
+// ensures for replicated servers.
+type Mirror struct {
+	registry   extension.Registry
+	upstream   trillian.TrillianLogClient
+	verifier   RootVerifier
+	timeSource clock.TimeSource
+	opts       MirrorOptions
+	label      string
+}
+
+// NewMirror returns a Mirror which copies the upstream log, verified by
+// verifier, to the local tree stored in registry.
+func NewMirror(registry extension.Registry, upstream trillian.TrillianLogClient, verifier RootVerifier, timeSource clock.TimeSource, opts MirrorOptions) *Mirror {
+	mirrorOnce.Do(func() {
+		createMirrorMetrics(registry.MetricFactory)
+	})
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultMirrorBatchSize
+	}
+	return &Mirror{
+		registry:   registry,
+		upstream:   upstream,
+		verifier:   verifier,
+		timeSource: timeSource,
+		opts:       opts,
+		label:      strconv.FormatInt(opts.LogID, 10),
+	}
+}
+
+// Run syncs the local tree with the upstream log every PollInterval, until ctx
+// is done.
+func (m *Mirror) Run(ctx context.Context) {
+	glog.Infof("%v: mirroring upstream log %v", m.opts.LogID, m.opts.UpstreamLogID)
+	for {
+		if n, err := m.SyncOnce(ctx); err != nil {
+			glog.Warningf("%v: failed to sync with upstream log: %v", m.opts.LogID, err)
+		} else if n > 0 {
+			glog.V(1).Infof("%v: copied %d leaves from upstream log", m.opts.LogID, n)
+		}
+
+		select {
+		case <-ctx.Done():
+			glog.Infof("%v: mirror shutting down", m.opts.LogID)
+			return
+		case <-time.After(m.opts.PollInterval):
+		}
+	}
+}
+
+// RunElected runs the mirror like Run, but only while this instance is the
+// master for the local tree, as elected with the ElectionFactory of the
+// registry. It returns nil once ctx is done, or an error if the election
+// can't be set up.
+func (m *Mirror) RunElected(ctx context.Context) error {
+	if m.registry.ElectionFactory == nil {
+		return errors.New("mirror: no ElectionFactory in registry")
+	}
+	// The sequencer elects masters by tree ID, so a separate resource is used,
+	// even though the sequencer skips frozen trees.
+	e, err := m.registry.ElectionFactory.NewElection(ctx, "mirror-"+m.label)
+	if err != nil {
+		return fmt.Errorf("NewElection(): %v", err)
+	}
+	defer func() {
+		if err := e.Close(context.Background()); err != nil {
+			glog.Warningf("%v: failed to close mirror election: %v", m.opts.LogID, err)
+		}
+	}()
+
+	for ctx.Err() == nil {
+		mctx, err := m.awaitMastership(ctx, e)
+		if err != nil {
+			glog.Warningf("%v: mirror election failed: %v", m.opts.LogID, err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(m.opts.PollInterval):
+			}
+			continue
+		}
+		glog.Infof("%v: mirror is master", m.opts.LogID)
+		m.Run(mctx)
+	}
+	return nil
+}
+
+// awaitMastership blocks until this instance is the master for the local tree,
+// and returns a context which is done once it stops being the master.
+func (m *Mirror) awaitMastership(ctx context.Context, e election2.Election) (context.Context, error) {
+	if err := e.Await(ctx); err != nil {
+		return nil, err
+	}
+	return e.WithMastership(ctx)
+}
+
+// SyncOnce fetches and verifies the latest upstream root, and copies the
+// leaves it covers to the local tree, in batches of up to BatchSize leaves. It
+// returns the number of leaves copied.
+func (m *Mirror) SyncOnce(ctx context.Context) (int, error) {
+	n, err := m.syncOnce(ctx)
+	if err != nil {
+		mirrorFailedSyncs.Inc(m.label)
+	}
+	mirrorLeaves.Add(float64(n), m.label)
+	return n, err
+}
+
+func (m *Mirror) syncOnce(ctx context.Context) (int, error) {
+	tree, err := trees.GetTree(ctx, m.registry.AdminStorage, m.opts.LogID, mirrorOpts)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving log %v: %v", m.opts.LogID, err)
+	}
+	if tree.TreeState != trillian.TreeState_FROZEN {
+		return 0, fmt.Errorf("mirrored log %v is %v, want %v", m.opts.LogID, tree.TreeState, trillian.TreeState_FROZEN)
+	}
+	ctx = trees.NewContext(ctx, tree)
+
+	hasher, err := hashers.NewLogHasher(tree.HashStrategy)
+	if err != nil {
+		return 0, fmt.Errorf("error getting hasher for log %v: %v", m.opts.LogID, err)
+	}
+	signer, err := trees.Signer(ctx, tree)
+	if err != nil {
+		return 0, fmt.Errorf("error getting signer for log %v: %v", m.opts.LogID, err)
+	}
+	sequencer := NewSequencer(hasher, m.timeSource, m.registry.LogStorage, signer, m.registry.MetricFactory, quota.Noop())
+
+	local, err := m.latestLocalRoot(ctx, tree)
+	if err != nil {
+		return 0, err
+	}
+	mirrorTreeSize.Set(float64(local.TreeSize), m.label)
+
+	upstream, err := m.latestUpstreamRoot(ctx, local)
+	if err != nil {
+		return 0, err
+	}
+	mirrorUpstreamSize.Set(float64(upstream.TreeSize), m.label)
+	if upstream.TreeSize < local.TreeSize {
+		return 0, fmt.Errorf("upstream log %v shrank from %d to %d", m.opts.UpstreamLogID, local.TreeSize, upstream.TreeSize)
+	}
+
+	verifier := merkle.NewLogVerifier(hasher)
+	copied := 0
+	for size := local.TreeSize; size < upstream.TreeSize; {
+		end := size + uint64(m.opts.BatchSize)
+		if end > upstream.TreeSize {
+			end = upstream.TreeSize
+		}
+		leaves, err := m.fetchLeaves(ctx, hasher, size, end)
+		if err != nil {
+			return copied, err
+		}
+
+		// Roots short of the upstream root are checked with a consistency
+		// proof to it, so that they're verified as well.
+		var proof [][]byte
+		if end < upstream.TreeSize {
+			rsp, err := m.upstream.GetConsistencyProof(ctx, &trillian.GetConsistencyProofRequest{
+				LogId:          m.opts.UpstreamLogID,
+				FirstTreeSize:  int64(end),
+				SecondTreeSize: int64(upstream.TreeSize),
+			})
+			if err != nil {
+				return copied, fmt.Errorf("GetConsistencyProof(%d, %d): %v", end, upstream.TreeSize, err)
+			}
+			proof = rsp.GetProof().GetHashes()
+		}
+		verify := func(root *types.LogRootV1) error {
+			if root.TreeSize != end {
+				return fmt.Errorf("got tree size %d, want %d", root.TreeSize, end)
+			}
+			if end == upstream.TreeSize {
+				if !bytes.Equal(root.RootHash, upstream.RootHash) {
+					return fmt.Errorf("got root hash %x, want %x", root.RootHash, upstream.RootHash)
+				}
+				return nil
+			}
+			return verifier.VerifyConsistencyProof(int64(end), int64(upstream.TreeSize), root.RootHash, upstream.RootHash, proof)
+		}
+
+		batch := &mirrorBatch{leaves: leaves, verify: verify}
+		n, err := sequencer.integrateBatch(ctx, tree, len(leaves), 0, 0, 0, batch)
+		if err != nil {
+			return copied, fmt.Errorf("failed to integrate mirrored batch for %v: %v", m.opts.LogID, err)
+		}
+		if n != len(leaves) {
+			return copied, fmt.Errorf("integrated %d mirrored leaves for %v, want %d", n, m.opts.LogID, len(leaves))
+		}
+		copied += n
+		size = end
+		mirrorTreeSize.Set(float64(size), m.label)
+	}
+	return copied, nil
+}
+
+// latestLocalRoot returns the latest root of the local tree. It's not verified
+// as there's no trust boundary between the mirror and its storage.
+func (m *Mirror) latestLocalRoot(ctx context.Context, tree *trillian.Tree) (*types.LogRootV1, error) {
+	tx, err := m.registry.LogStorage.SnapshotForTree(ctx, tree)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Close()
+	slr, err := tx.LatestSignedLogRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var root types.LogRootV1
+	if err := root.UnmarshalBinary(slr.LogRoot); err != nil {
+		return nil, fmt.Errorf("%v: failed to unmarshal latest root: %v", m.opts.LogID, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &root, nil
+}
+
+// latestUpstreamRoot fetches the latest upstream root, and verifies it
+// against the local root, which only ever matches verified upstream roots.
+func (m *Mirror) latestUpstreamRoot(ctx context.Context, local *types.LogRootV1) (*types.LogRootV1, error) {
+	rsp, err := m.upstream.GetLatestSignedLogRoot(ctx, &trillian.GetLatestSignedLogRootRequest{
+		LogId:         m.opts.UpstreamLogID,
+		FirstTreeSize: int64(local.TreeSize),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GetLatestSignedLogRoot(): %v", err)
+	}
+	if rsp.GetSignedLogRoot() == nil {
+		return nil, errors.New("GetLatestSignedLogRoot(): missing root")
+	}
+	trusted := &types.LogRootV1{TreeSize: local.TreeSize, RootHash: local.RootHash}
+	root, err := m.verifier.VerifyRoot(trusted, rsp.SignedLogRoot, rsp.GetProof().GetHashes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify upstream root: %v", err)
+	}
+	return root, nil
+}
+
+// fetchLeaves returns the upstream leaves in [begin, end), with their Merkle
+// leaf hashes recomputed locally.
+func (m *Mirror) fetchLeaves(ctx context.Context, hasher hashers.LogHasher, begin, end uint64) ([]*trillian.LogLeaf, error) {
+	leaves := make([]*trillian.LogLeaf, 0, end-begin)
+	for next := begin; next < end; {
+		rsp, err := m.upstream.GetLeavesByRange(ctx, &trillian.GetLeavesByRangeRequest{
+			LogId:      m.opts.UpstreamLogID,
+			StartIndex: int64(next),
+			Count:      int64(end - next),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("GetLeavesByRange(%d, %d): %v", next, end-next, err)
+		}
+		if len(rsp.Leaves) == 0 {
+			return nil, fmt.Errorf("GetLeavesByRange(%d, %d): no leaves returned", next, end-next)
+		}
+		for _, leaf := range rsp.Leaves {
+			if next == end {
+				break
+			}
+			if got, want := leaf.LeafIndex, int64(next); got != want {
+				return nil, fmt.Errorf("GetLeavesByRange(): got leaf index %d, want %d", got, want)
+			}
+			// The data of a pruned leaf is gone, so it can't be mirrored.
+			if leaf.DataPruned {
+				return nil, fmt.Errorf("GetLeavesByRange(): upstream leaf %d data pruned", next)
+			}
+			// Only the leaf value is covered by the upstream root, so the Merkle
+			// leaf hash is recomputed rather than trusted.
+			leaf.MerkleLeafHash = hasher.HashLeaf(leaf.LeafValue)
+			if len(leaf.LeafIdentityHash) == 0 {
+				leaf.LeafIdentityHash = leaf.MerkleLeafHash
+			}
+			leaves = append(leaves, leaf)
+			next++
+		}
+	}
+	return leaves, nil
+}
@@ -0,0 +1,429 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/extension"
+	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/rfc6962"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/memory"
+	"github.com/google/trillian/trees"
+	"github.com/google/trillian/types"
+	"github.com/google/trillian/util/clock"
+	"github.com/google/trillian/util/election2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	_ "github.com/google/trillian/crypto/keys/der/proto" // Register PrivateKey ProtoHandler
+	stestonly "github.com/google/trillian/storage/testonly"
+	eto "github.com/google/trillian/util/election2/testonly"
+)
+
+// maxUpstreamLeaves is the maximum number of leaves returned by a single
+// fakeUpstream.GetLeavesByRange call, so that paging is exercised.
+const maxUpstreamLeaves = 3
+
+// fakeUpstream is a TrillianLogClient serving a log held in memory.
+type fakeUpstream struct {
+	trillian.TrillianLogClient
+	tree   *merkle.InMemoryMerkleTree
+	leaves [][]byte
+	// corrupt, if set, is applied to every leaf returned by GetLeavesByRange.
+	corrupt func(*trillian.LogLeaf)
+}
+
+func newFakeUpstream(size int, prefix string) *fakeUpstream {
+	u := &fakeUpstream{tree: merkle.NewInMemoryMerkleTree(rfc6962.DefaultHasher)}
+	u.add(size, prefix)
+	return u
+}
+
+func (u *fakeUpstream) add(n int, prefix string) {
+	for i := 0; i < n; i++ {
+		data := []byte(fmt.Sprintf("%s-%d", prefix, len(u.leaves)))
+		u.leaves = append(u.leaves, data)
+		u.tree.AddLeaf(data)
+	}
+}
+
+func (u *fakeUpstream) consistency(first, second int64) [][]byte {
+	var proof [][]byte
+	for _, d := range u.tree.SnapshotConsistency(first, second) {
+		proof = append(proof, d.Value.Hash())
+	}
+	return proof
+}
+
+func (u *fakeUpstream) GetLatestSignedLogRoot(ctx context.Context, req *trillian.GetLatestSignedLogRootRequest, opts ...grpc.CallOption) (*trillian.GetLatestSignedLogRootResponse, error) {
+	size := int64(len(u.leaves))
+	root := &types.LogRootV1{TreeSize: uint64(size), RootHash: u.tree.RootAtSnapshot(size).Hash()}
+	logRoot, err := root.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	rsp := &trillian.GetLatestSignedLogRootResponse{SignedLogRoot: &trillian.SignedLogRoot{LogRoot: logRoot}}
+	if req.FirstTreeSize > 0 {
+		rsp.Proof = &trillian.Proof{Hashes: u.consistency(req.FirstTreeSize, size)}
+	}
+	return rsp, nil
+}
+
+func (u *fakeUpstream) GetLeavesByRange(ctx context.Context, req *trillian.GetLeavesByRangeRequest, opts ...grpc.CallOption) (*trillian.GetLeavesByRangeResponse, error) {
+	if req.StartIndex >= int64(len(u.leaves)) {
+		return nil, status.Errorf(codes.OutOfRange, "start index %d beyond tree size %d", req.StartIndex, len(u.leaves))
+	}
+	end := req.StartIndex + req.Count
+	if n := req.StartIndex + maxUpstreamLeaves; end > n {
+		end = n
+	}
+	if n := int64(len(u.leaves)); end > n {
+		end = n
+	}
+	rsp := &trillian.GetLeavesByRangeResponse{}
+	for i := req.StartIndex; i < end; i++ {
+		leaf := &trillian.LogLeaf{LeafIndex: i, LeafValue: u.leaves[i]}
+		if u.corrupt != nil {
+			u.corrupt(leaf)
+		}
+		rsp.Leaves = append(rsp.Leaves, leaf)
+	}
+	return rsp, nil
+}
+
+func (u *fakeUpstream) GetConsistencyProof(ctx context.Context, req *trillian.GetConsistencyProofRequest, opts ...grpc.CallOption) (*trillian.GetConsistencyProofResponse, error) {
+	return &trillian.GetConsistencyProofResponse{
+		Proof: &trillian.Proof{Hashes: u.consistency(req.FirstTreeSize, req.SecondTreeSize)},
+	}, nil
+}
+
+// fakeRootVerifier checks the consistency of upstream roots, but not their
+// signatures, as fakeUpstream doesn't sign them.
+type fakeRootVerifier struct {
+	err error
+}
+
+func (v fakeRootVerifier) VerifyRoot(trusted *types.LogRootV1, newRoot *trillian.SignedLogRoot, consistency [][]byte) (*types.LogRootV1, error) {
+	if v.err != nil {
+		return nil, v.err
+	}
+	var root types.LogRootV1
+	if err := root.UnmarshalBinary(newRoot.LogRoot); err != nil {
+		return nil, err
+	}
+	if trusted.TreeSize > 0 {
+		verifier := merkle.NewLogVerifier(rfc6962.DefaultHasher)
+		if err := verifier.VerifyConsistencyProof(int64(trusted.TreeSize), int64(root.TreeSize), trusted.RootHash, root.RootHash, consistency); err != nil {
+			return nil, err
+		}
+	}
+	return &root, nil
+}
+
+// preorderedLogStorage adds support for AddSequencedLeaves to the memory
+// LogStorage, which mirrors need.
+type preorderedLogStorage struct {
+	storage.LogStorage
+}
+
+func (s preorderedLogStorage) ReadWriteTransaction(ctx context.Context, tree *trillian.Tree, fn storage.LogTXFunc) error {
+	return s.LogStorage.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		return fn(ctx, preorderedLogTX{tx})
+	})
+}
+
+type preorderedLogTX struct {
+	storage.LogTreeTX
+}
+
+func (t preorderedLogTX) AddSequencedLeaves(ctx context.Context, leaves []*trillian.LogLeaf, timestamp time.Time) ([]*trillian.QueuedLogLeaf, error) {
+	if _, err := t.QueueLeaves(ctx, leaves, timestamp); err != nil {
+		return nil, err
+	}
+	if err := t.UpdateSequencedLeaves(ctx, leaves); err != nil {
+		return nil, err
+	}
+	res := make([]*trillian.QueuedLogLeaf, len(leaves))
+	for i := range res {
+		res[i] = &trillian.QueuedLogLeaf{Status: status.New(codes.OK, "OK").Proto()}
+	}
+	return res, nil
+}
+
+// setupMirror creates an initialized local tree in memory storage, optionally
+// freezes it, and returns a Mirror copying upstream to it.
+func setupMirror(ctx context.Context, t *testing.T, upstream trillian.TrillianLogClient, verifier RootVerifier, batchSize int, freeze bool) (*Mirror, *trillian.Tree) {
+	t.Helper()
+	ts := memory.NewTreeStorage()
+	registry := extension.Registry{
+		AdminStorage: memory.NewAdminStorage(ts),
+		LogStorage:   preorderedLogStorage{memory.NewLogStorage(ts, nil)},
+	}
+
+	tree, err := storage.CreateTree(ctx, registry.AdminStorage, stestonly.PreorderedLogTree)
+	if err != nil {
+		t.Fatalf("CreateTree(): %v", err)
+	}
+	signer, err := trees.Signer(ctx, tree)
+	if err != nil {
+		t.Fatalf("trees.Signer(): %v", err)
+	}
+	root, err := signer.SignLogRoot(&types.LogRootV1{
+		RootHash:       rfc6962.DefaultHasher.EmptyRoot(),
+		TimestampNanos: uint64(time.Now().UnixNano()),
+	})
+	if err != nil {
+		t.Fatalf("SignLogRoot(): %v", err)
+	}
+	if err := registry.LogStorage.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		return tx.StoreSignedLogRoot(ctx, root)
+	}); err != nil {
+		t.Fatalf("StoreSignedLogRoot(): %v", err)
+	}
+	if freeze {
+		tree, err = storage.UpdateTree(ctx, registry.AdminStorage, tree.TreeId, func(tree *trillian.Tree) {
+			tree.TreeState = trillian.TreeState_FROZEN
+		})
+		if err != nil {
+			t.Fatalf("UpdateTree(): %v", err)
+		}
+	}
+
+	m := NewMirror(registry, upstream, verifier, clock.System, MirrorOptions{
+		UpstreamLogID: 12345,
+		LogID:         tree.TreeId,
+		BatchSize:     batchSize,
+	})
+	return m, tree
+}
+
+func TestMirrorSyncOnce(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		desc       string
+		batchSize  int
+		added      []int
+		wantCopied []int
+	}{
+		{desc: "empty", batchSize: 10, added: []int{0}, wantCopied: []int{0}},
+		{desc: "oneBatch", batchSize: 10, added: []int{5}, wantCopied: []int{5}},
+		{desc: "fullBatch", batchSize: 5, added: []int{5}, wantCopied: []int{5}},
+		{desc: "manyBatches", batchSize: 5, added: []int{23}, wantCopied: []int{23}},
+		{desc: "defaultBatchSize", added: []int{1500}, wantCopied: []int{1500}},
+		{desc: "incremental", batchSize: 4, added: []int{7, 0, 13, 1}, wantCopied: []int{7, 0, 13, 1}},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			upstream := newFakeUpstream(0, "leaf")
+			m, tree := setupMirror(ctx, t, upstream, fakeRootVerifier{}, tc.batchSize, true /* freeze */)
+
+			for i, added := range tc.added {
+				upstream.add(added, "leaf")
+				copied, err := m.SyncOnce(ctx)
+				if err != nil {
+					t.Fatalf("SyncOnce(#%d): %v", i, err)
+				}
+				if got, want := copied, tc.wantCopied[i]; got != want {
+					t.Errorf("SyncOnce(#%d): copied %d leaves, want %d", i, got, want)
+				}
+
+				root, err := m.latestLocalRoot(ctx, tree)
+				if err != nil {
+					t.Fatalf("latestLocalRoot(): %v", err)
+				}
+				size := int64(len(upstream.leaves))
+				if got, want := root.TreeSize, uint64(size); got != want {
+					t.Errorf("SyncOnce(#%d): local tree size %d, want %d", i, got, want)
+				}
+				if got, want := root.RootHash, upstream.tree.RootAtSnapshot(size).Hash(); !bytes.Equal(got, want) {
+					t.Errorf("SyncOnce(#%d): local root hash %x, want %x", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+// fixedElectionFactory returns the same Election for every resource.
+type fixedElectionFactory struct {
+	e election2.Election
+}
+
+func (f fixedElectionFactory) NewElection(ctx context.Context, resourceID string) (election2.Election, error) {
+	return f.e, nil
+}
+
+func TestMirrorRunElected(t *testing.T) {
+	ctx := context.Background()
+	upstream := newFakeUpstream(5, "leaf")
+	m, tree := setupMirror(ctx, t, upstream, fakeRootVerifier{}, 10, true /* freeze */)
+	m.opts.PollInterval = time.Millisecond
+	e := eto.NewDecorator(eto.NewElection())
+	m.registry.ElectionFactory = fixedElectionFactory{e: e}
+
+	// runFor runs the mirror for a while, and returns the local tree size. The
+	// tree is only read once the mirror has stopped, as snapshots of memory
+	// storage can't be taken concurrently.
+	runFor := func(d time.Duration) uint64 {
+		t.Helper()
+		cctx, cancel := context.WithTimeout(ctx, d)
+		defer cancel()
+		if err := m.RunElected(cctx); err != nil {
+			t.Fatalf("RunElected(): %v", err)
+		}
+		root, err := m.latestLocalRoot(ctx, tree)
+		if err != nil {
+			t.Fatalf("latestLocalRoot(): %v", err)
+		}
+		return root.TreeSize
+	}
+
+	// Nothing is copied until the mirror is elected.
+	e.BlockAwait(true)
+	if got := runFor(50 * time.Millisecond); got != 0 {
+		t.Fatalf("local tree size %d before election, want 0", got)
+	}
+	e.BlockAwait(false)
+	for i := 0; ; i++ {
+		got := runFor(50 * time.Millisecond)
+		if got == 5 {
+			break
+		}
+		if i == 100 {
+			t.Fatalf("local tree size %d after election, want 5", got)
+		}
+	}
+}
+
+func TestMirrorRunElectedNoFactory(t *testing.T) {
+	ctx := context.Background()
+	m, _ := setupMirror(ctx, t, newFakeUpstream(0, "leaf"), fakeRootVerifier{}, 10, true /* freeze */)
+	if err := m.RunElected(ctx); err == nil {
+		t.Error("RunElected(): nil, want error")
+	}
+}
+
+func TestMirrorSyncOnceErrors(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		desc     string
+		verifier RootVerifier
+		// notFrozen leaves the local tree ACTIVE.
+		notFrozen bool
+		// prepare is called after a successful sync of 5 upstream leaves, and
+		// returns the upstream used for the failing sync.
+		prepare  func(u *fakeUpstream) *fakeUpstream
+		wantSize uint64
+	}{
+		{
+			desc:      "notFrozen",
+			notFrozen: true,
+			prepare:   func(u *fakeUpstream) *fakeUpstream { return u },
+		},
+		{
+			desc:     "badUpstreamRoot",
+			verifier: fakeRootVerifier{err: errors.New("bad signature")},
+			prepare:  func(u *fakeUpstream) *fakeUpstream { return u },
+			wantSize: 5,
+		},
+		{
+			desc: "forkedUpstream",
+			prepare: func(u *fakeUpstream) *fakeUpstream {
+				return newFakeUpstream(8, "fork")
+			},
+			wantSize: 5,
+		},
+		{
+			desc: "corruptLeafValue",
+			prepare: func(u *fakeUpstream) *fakeUpstream {
+				u.add(4, "leaf")
+				u.corrupt = func(leaf *trillian.LogLeaf) {
+					if leaf.LeafIndex == 7 {
+						leaf.LeafValue = []byte("corrupt")
+					}
+				}
+				return u
+			},
+			// The batch before the corrupt leaf is verified, and kept.
+			wantSize: 7,
+		},
+		{
+			desc: "prunedLeaf",
+			prepare: func(u *fakeUpstream) *fakeUpstream {
+				u.add(4, "leaf")
+				u.corrupt = func(leaf *trillian.LogLeaf) {
+					if leaf.LeafIndex == 7 {
+						leaf.MerkleLeafHash = rfc6962.DefaultHasher.HashLeaf(leaf.LeafValue)
+						leaf.LeafValue, leaf.DataPruned = nil, true
+					}
+				}
+				return u
+			},
+			wantSize: 7,
+		},
+		{
+			desc: "wrongLeafIndex",
+			prepare: func(u *fakeUpstream) *fakeUpstream {
+				u.add(4, "leaf")
+				u.corrupt = func(leaf *trillian.LogLeaf) {
+					leaf.LeafIndex++
+				}
+				return u
+			},
+			wantSize: 5,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			var upstream struct{ *fakeUpstream }
+			upstream.fakeUpstream = newFakeUpstream(5, "leaf")
+			verifier := &switchingVerifier{RootVerifier: fakeRootVerifier{}}
+			m, tree := setupMirror(ctx, t, &upstream, verifier, 2, !tc.notFrozen)
+
+			if !tc.notFrozen {
+				if _, err := m.SyncOnce(ctx); err != nil {
+					t.Fatalf("SyncOnce(): %v", err)
+				}
+			}
+
+			upstream.fakeUpstream = tc.prepare(upstream.fakeUpstream)
+			if tc.verifier != nil {
+				verifier.RootVerifier = tc.verifier
+			}
+			if _, err := m.SyncOnce(ctx); err == nil {
+				t.Errorf("SyncOnce(): nil, want err")
+			}
+
+			root, err := m.latestLocalRoot(ctx, tree)
+			if err != nil {
+				t.Fatalf("latestLocalRoot(): %v", err)
+			}
+			if got, want := root.TreeSize, tc.wantSize; got != want {
+				t.Errorf("local tree size %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+// switchingVerifier is a RootVerifier which can be replaced between syncs.
+type switchingVerifier struct {
+	RootVerifier
+}
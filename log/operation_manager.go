@@ -85,7 +85,8 @@ type OperationInfo struct {
 
 	// The following parameters are passed to individual Operations.
 
-	// BatchSize is the processing batch size to be passed to tasks run by this manager
+	// BatchSize is the processing batch size to be passed to tasks run by this
+	// manager. Logs may override it in their SequencingConfig.
 	BatchSize int
 	// TimeSource should be used by the Operation to allow mocking for tests.
 	TimeSource clock.TimeSource
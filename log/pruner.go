@@ -0,0 +1,173 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/google/trillian"
+	"github.com/google/trillian/monitoring"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/types"
+	"github.com/google/trillian/util/clock"
+)
+
+var (
+	prunerOnce    sync.Once
+	prunedCounter monitoring.Counter
+	pruneLatency  monitoring.Histogram
+
+	// PruneInterval is the minimum time between two pruning runs for the same
+	// log, unless the previous run left more leaves to be pruned.
+	PruneInterval = time.Minute
+	// PruneBatchSize is the maximum number of leaves pruned in a single
+	// pruning run.
+	PruneBatchSize = 1000
+)
+
+func createPrunerMetrics(mf monitoring.MetricFactory) {
+	if mf == nil {
+		mf = monitoring.InertMetricFactory{}
+	}
+	prunedCounter = mf.NewCounter("pruner_pruned_leaves", "Number of leaves whose data was pruned", logIDLabel)
+	pruneLatency = mf.NewHistogram("pruner_latency", "Latency of pruning runs in seconds", logIDLabel)
+}
+
+// Pruner drops the data of log leaves which fall outside of the retention
+// policy of their tree. The Merkle hashes of pruned leaves are kept, so that
+// proofs can still be served.
+type Pruner struct {
+	logStorage storage.LogStorage
+	timeSource clock.TimeSource
+}
+
+// NewPruner creates a new Pruner instance for the specified inputs.
+func NewPruner(logStorage storage.LogStorage, timeSource clock.TimeSource, mf monitoring.MetricFactory) *Pruner {
+	prunerOnce.Do(func() {
+		createPrunerMetrics(mf)
+	})
+	return &Pruner{logStorage: logStorage, timeSource: timeSource}
+}
+
+// PruneLeaves drops the data of at most limit leaves of the tree which fall
+// outside of its RetentionPolicy, oldest first, and returns the number of
+// leaves pruned.
+func (p *Pruner) PruneLeaves(ctx context.Context, tree *trillian.Tree, limit int) (int, error) {
+	policy := tree.RetentionPolicy
+	if policy == nil || limit <= 0 {
+		return 0, nil
+	}
+	label := strconv.FormatInt(tree.TreeId, 10)
+	start := p.timeSource.Now()
+
+	var pruned int
+	err := p.logStorage.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		slr, err := tx.LatestSignedLogRoot(ctx)
+		if err == storage.ErrTreeNeedsInit {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		var root types.LogRootV1
+		if err := root.UnmarshalBinary(slr.LogRoot); err != nil {
+			return fmt.Errorf("failed to unmarshal root: %v", err)
+		}
+
+		end, err := p.pruneEnd(ctx, tx, policy, int64(root.TreeSize))
+		if err != nil {
+			return err
+		}
+		if end == 0 {
+			return nil
+		}
+		pruned, err = tx.PruneLeaves(ctx, end, limit)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	prunedCounter.Add(float64(pruned), label)
+	pruneLatency.Observe(clock.SecondsSince(p.timeSource, start), label)
+	return pruned, nil
+}
+
+// pruneEnd returns the index of the first leaf of a tree of the given size
+// whose data is retained under the policy.
+func (p *Pruner) pruneEnd(ctx context.Context, tx storage.ReadOnlyLogTreeTX, policy *trillian.RetentionPolicy, treeSize int64) (int64, error) {
+	var end int64
+	if m := policy.MaxEntries; m > 0 && treeSize > m {
+		end = treeSize - m
+	}
+	if policy.MaxAge == nil {
+		return end, nil
+	}
+	maxAge, err := ptypes.Duration(policy.MaxAge)
+	if err != nil {
+		return 0, fmt.Errorf("invalid max_age: %v", err)
+	}
+	if maxAge <= 0 {
+		return end, nil
+	}
+	cutoff := p.timeSource.Now().Add(-maxAge)
+
+	// Leaves are (roughly) ordered by time, so search for the first leaf which
+	// isn't older than the cutoff.
+	lo, hi := end, treeSize
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		leaves, err := tx.GetLeavesByRange(ctx, mid, 1)
+		if err != nil {
+			return 0, err
+		}
+		if got := len(leaves); got != 1 {
+			return 0, fmt.Errorf("got %d leaves at index %d, want 1", got, mid)
+		}
+		t, err := leafTime(leaves[0])
+		if err != nil {
+			return 0, err
+		}
+		if t.Before(cutoff) {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo, nil
+}
+
+// leafTime returns the later of the queue and integrate timestamps of leaf.
+func leafTime(leaf *trillian.LogLeaf) (time.Time, error) {
+	var ret time.Time
+	for _, ts := range []*timestamp.Timestamp{leaf.QueueTimestamp, leaf.IntegrateTimestamp} {
+		if ts == nil {
+			continue
+		}
+		t, err := ptypes.Timestamp(ts)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("leaf %d has invalid timestamp: %v", leaf.LeafIndex, err)
+		}
+		if t.After(ret) {
+			ret = t
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,211 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/trillian"
+	"github.com/google/trillian/extension"
+	"github.com/google/trillian/merkle/rfc6962"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/memory"
+	"github.com/google/trillian/types"
+	"github.com/google/trillian/util/clock"
+
+	stestonly "github.com/google/trillian/storage/testonly"
+)
+
+// pruneTestStart is the queue time of the first leaf of the logs created by
+// setupPrunerLog. Further leaves are queued a minute apart.
+var pruneTestStart = time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+
+// setupPrunerLog creates a log holding size leaves in memory storage, and a
+// root covering all of them.
+func setupPrunerLog(ctx context.Context, t *testing.T, size int, policy *trillian.RetentionPolicy) (storage.LogStorage, *trillian.Tree) {
+	t.Helper()
+	ts := memory.NewTreeStorage()
+	as := memory.NewAdminStorage(ts)
+	ls := memory.NewLogStorage(ts, nil)
+
+	tree := proto.Clone(stestonly.LogTree).(*trillian.Tree)
+	tree.RetentionPolicy = policy
+	tree, err := storage.CreateTree(ctx, as, tree)
+	if err != nil {
+		t.Fatalf("CreateTree(): %v", err)
+	}
+
+	leaves := make([]*trillian.LogLeaf, 0, size)
+	for i := 0; i < size; i++ {
+		data := []byte(fmt.Sprintf("leaf-%d", i))
+		hash := rfc6962.DefaultHasher.HashLeaf(data)
+		queued, err := ptypes.TimestampProto(pruneTestStart.Add(time.Duration(i) * time.Minute))
+		if err != nil {
+			t.Fatalf("TimestampProto(): %v", err)
+		}
+		leaves = append(leaves, &trillian.LogLeaf{
+			MerkleLeafHash:     hash,
+			LeafIdentityHash:   hash,
+			LeafValue:          data,
+			ExtraData:          []byte("extra"),
+			LeafIndex:          int64(i),
+			QueueTimestamp:     queued,
+			IntegrateTimestamp: queued,
+		})
+	}
+	logRoot, err := (&types.LogRootV1{TreeSize: uint64(size), TimestampNanos: 1}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary(): %v", err)
+	}
+	if err := ls.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		if _, err := tx.QueueLeaves(ctx, leaves, pruneTestStart); err != nil {
+			return err
+		}
+		if err := tx.UpdateSequencedLeaves(ctx, leaves); err != nil {
+			return err
+		}
+		return tx.StoreSignedLogRoot(ctx, &trillian.SignedLogRoot{LogRoot: logRoot})
+	}); err != nil {
+		t.Fatalf("ReadWriteTransaction(): %v", err)
+	}
+	return ls, tree
+}
+
+// countPruned returns the number of leaves of the tree whose data is pruned,
+// and checks that they form a prefix of the tree.
+func countPruned(ctx context.Context, t *testing.T, ls storage.LogStorage, tree *trillian.Tree, size int) int {
+	t.Helper()
+	var leaves []*trillian.LogLeaf
+	if err := ls.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		var err error
+		leaves, err = tx.GetLeavesByRange(ctx, 0, int64(size))
+		return err
+	}); err != nil {
+		t.Fatalf("GetLeavesByRange(): %v", err)
+	}
+
+	pruned := 0
+	for i, leaf := range leaves {
+		if !leaf.DataPruned {
+			continue
+		}
+		if pruned != i {
+			t.Errorf("leaf %d pruned after a retained leaf", i)
+		}
+		if leaf.LeafValue != nil || leaf.ExtraData != nil || leaf.MerkleLeafHash == nil {
+			t.Errorf("pruned leaf %d: %+v, want hashes without data", i, leaf)
+		}
+		pruned++
+	}
+	return pruned
+}
+
+func TestPrunerPruneLeaves(t *testing.T) {
+	ctx := context.Background()
+	const size = 100
+	// The leaves are queued [0, 100) minutes after pruneTestStart.
+	now := pruneTestStart.Add(size * time.Minute)
+
+	for _, tc := range []struct {
+		desc       string
+		policy     *trillian.RetentionPolicy
+		limit      int
+		wantPruned int
+	}{
+		{desc: "noPolicy", limit: 1000},
+		{desc: "emptyPolicy", policy: &trillian.RetentionPolicy{}, limit: 1000},
+		{desc: "maxEntries", policy: &trillian.RetentionPolicy{MaxEntries: 30}, limit: 1000, wantPruned: 70},
+		{desc: "maxEntriesLimited", policy: &trillian.RetentionPolicy{MaxEntries: 30}, limit: 20, wantPruned: 20},
+		{desc: "maxEntriesAboveSize", policy: &trillian.RetentionPolicy{MaxEntries: 200}, limit: 1000},
+		{desc: "maxAge", policy: &trillian.RetentionPolicy{MaxAge: ptypes.DurationProto(50 * time.Minute)}, limit: 1000, wantPruned: 50},
+		{desc: "maxAgeBetweenLeaves", policy: &trillian.RetentionPolicy{MaxAge: ptypes.DurationProto(50*time.Minute + time.Second)}, limit: 1000, wantPruned: 50},
+		{desc: "maxAgeAboveAll", policy: &trillian.RetentionPolicy{MaxAge: ptypes.DurationProto(200 * time.Minute)}, limit: 1000},
+		{desc: "maxAgeBelowAll", policy: &trillian.RetentionPolicy{MaxAge: ptypes.DurationProto(time.Second)}, limit: 1000, wantPruned: 100},
+		{
+			desc:       "maxEntriesStricter",
+			policy:     &trillian.RetentionPolicy{MaxEntries: 30, MaxAge: ptypes.DurationProto(50 * time.Minute)},
+			limit:      1000,
+			wantPruned: 70,
+		},
+		{
+			desc:       "maxAgeStricter",
+			policy:     &trillian.RetentionPolicy{MaxEntries: 80, MaxAge: ptypes.DurationProto(50 * time.Minute)},
+			limit:      1000,
+			wantPruned: 50,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ls, tree := setupPrunerLog(ctx, t, size, tc.policy)
+			p := NewPruner(ls, clock.NewFake(now), nil)
+
+			pruned, err := p.PruneLeaves(ctx, tree, tc.limit)
+			if err != nil {
+				t.Fatalf("PruneLeaves(): %v", err)
+			}
+			if got, want := pruned, tc.wantPruned; got != want {
+				t.Errorf("PruneLeaves(): pruned %d leaves, want %d", got, want)
+			}
+			if got, want := countPruned(ctx, t, ls, tree, size), tc.wantPruned; got != want {
+				t.Errorf("%d leaves pruned in storage, want %d", got, want)
+			}
+
+			// Nothing is left to prune, unless the limit was hit.
+			pruned, err = p.PruneLeaves(ctx, tree, tc.limit)
+			if err != nil {
+				t.Fatalf("PruneLeaves(again): %v", err)
+			}
+			if tc.wantPruned < tc.limit && pruned != 0 {
+				t.Errorf("PruneLeaves(again): pruned %d leaves, want 0", pruned)
+			}
+		})
+	}
+}
+
+func TestSequencerManagerPrune(t *testing.T) {
+	ctx := context.Background()
+	const size = 25
+	defer func(batchSize int) { PruneBatchSize = batchSize }(PruneBatchSize)
+	PruneBatchSize = 10
+
+	ls, tree := setupPrunerLog(ctx, t, size, &trillian.RetentionPolicy{MaxEntries: 10})
+	ts := clock.NewFake(pruneTestStart)
+	sm := NewSequencerManager(extension.Registry{LogStorage: ls}, 0)
+	info := &OperationInfo{TimeSource: ts}
+
+	// Full batches are followed by another run straight away.
+	for _, want := range []int{10, 15, 15} {
+		sm.prune(ctx, tree, info)
+		if got := countPruned(ctx, t, ls, tree, size); got != want {
+			t.Errorf("prune(): %d leaves pruned, want %d", got, want)
+		}
+	}
+
+	// Otherwise the next run waits for PruneInterval.
+	tree.RetentionPolicy.MaxEntries = 5
+	sm.prune(ctx, tree, info)
+	if got, want := countPruned(ctx, t, ls, tree, size), 15; got != want {
+		t.Errorf("prune(): %d leaves pruned before PruneInterval, want %d", got, want)
+	}
+	ts.Set(ts.Now().Add(PruneInterval))
+	sm.prune(ctx, tree, info)
+	if got, want := countPruned(ctx, t, ls, tree, size), 20; got != want {
+		t.Errorf("prune(): %d leaves pruned after PruneInterval, want %d", got, want)
+	}
+}
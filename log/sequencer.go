@@ -32,6 +32,7 @@ import (
 	"github.com/google/trillian/storage"
 	"github.com/google/trillian/types"
 	"github.com/google/trillian/util/clock"
+	"google.golang.org/grpc/codes"
 
 	tcrypto "github.com/google/trillian/crypto"
 )
@@ -52,6 +53,7 @@ var (
 	seqStoreRootLatency    monitoring.Histogram
 	seqCounter             monitoring.Counter
 	seqMergeDelay          monitoring.Histogram
+	seqLatePromises        monitoring.Counter
 	seqTimestamp           monitoring.Gauge
 
 	// QuotaIncreaseFactor is the multiplier used for the number of tokens added back to
@@ -92,6 +94,7 @@ func createSequencerMetrics(mf monitoring.MetricFactory) {
 	seqStoreRootLatency = mf.NewHistogram("sequencer_latency_store_root", "Latency of store-root part of sequencer batch operation in seconds", logIDLabel)
 	seqCounter = mf.NewCounter("sequencer_sequenced", "Number of leaves sequenced", logIDLabel)
 	seqMergeDelay = mf.NewHistogram("sequencer_merge_delay", "Delay between queuing and integration of leaves", logIDLabel)
+	seqLatePromises = mf.NewCounter("sequencer_late_promises", "Number of leaves integrated later than max_root_duration after being queued, breaking any signed entry timestamp issued for them", logIDLabel)
 }
 
 // Sequencer instances are responsible for integrating new leaves into a single log.
@@ -195,7 +198,10 @@ func (s Sequencer) buildNodesFromNodeMap(nodeMap map[compact.NodeID][]byte, newV
 	return nodes, nil
 }
 
-func (s Sequencer) prepareLeaves(leaves []*trillian.LogLeaf, begin uint64, label string) error {
+// prepareLeaves sets the integration timestamps of leaves, which must be
+// indexed consecutively from begin. Leaves which were queued more than
+// promiseDeadline ago are counted as late, unless promiseDeadline is zero.
+func (s Sequencer) prepareLeaves(leaves []*trillian.LogLeaf, begin uint64, promiseDeadline time.Duration, label string) error {
 	now := s.timeSource.Now()
 	integrateAt, err := ptypes.TimestampProto(now)
 	if err != nil {
@@ -217,6 +223,9 @@ func (s Sequencer) prepareLeaves(leaves []*trillian.LogLeaf, begin uint64, label
 			}
 			mergeDelay := now.Sub(queueTS)
 			seqMergeDelay.Observe(mergeDelay.Seconds(), label)
+			if promiseDeadline > 0 && mergeDelay > promiseDeadline {
+				seqLatePromises.Inc(label)
+			}
 		}
 	}
 	return nil
@@ -322,9 +331,99 @@ func (s *preorderedLogSequencingTask) update(ctx context.Context, leaves []*tril
 	return nil
 }
 
+// mirrorBatch is a batch of leaves copied from an upstream log, together with
+// a check that the root resulting from their integration matches the upstream
+// log.
+type mirrorBatch struct {
+	leaves []*trillian.LogLeaf
+	verify func(*types.LogRootV1) error
+}
+
+// mirrorSequencingTask is a sequencingTask implementation for mirrored
+// Pre-ordered Logs. It stores the sequenced entries of a mirrorBatch, and
+// returns them for integration in the same transaction.
+type mirrorSequencingTask struct {
+	*sequencingTaskData
+	batch *mirrorBatch
+}
+
+func (s *mirrorSequencingTask) fetch(ctx context.Context, limit int, cutoff time.Time) ([]*trillian.LogLeaf, error) {
+	leaves := s.batch.leaves
+	if len(leaves) == 0 {
+		return nil, nil
+	}
+	if got, want := leaves[0].LeafIndex, int64(s.treeSize); got != want {
+		return nil, fmt.Errorf("%v: mirrored batch starts at %d, want %d", s.label, got, want)
+	}
+	res, err := s.tx.AddSequencedLeaves(ctx, leaves, s.timeSource.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%v: Sequencer failed to store mirrored leaves: %v", s.label, err)
+	}
+	for i, r := range res {
+		if code := r.GetStatus().GetCode(); code != int32(codes.OK) {
+			return nil, fmt.Errorf("%v: Sequencer failed to store mirrored leaf %d: %v", s.label, leaves[i].LeafIndex, r.GetStatus().GetMessage())
+		}
+	}
+	return leaves, nil
+}
+
+func (s *mirrorSequencingTask) update(ctx context.Context, leaves []*trillian.LogLeaf) error {
+	return nil
+}
+
 // IntegrateBatch wraps up all the operations needed to take a batch of queued
 // or sequenced leaves and integrate them into the tree.
+// The limit and guardWindow are defaults, which the tree's SequencingConfig may
+// override. Nothing is done if the latest root of the tree is more recent than
+// its minimum root interval. Whether sequencing of the tree is paused is left
+// to the caller to check.
 func (s Sequencer) IntegrateBatch(ctx context.Context, tree *trillian.Tree, limit int, guardWindow, maxRootDurationInterval time.Duration) (int, error) {
+	params, err := newSequencingParams(tree.SequencingConfig, limit, guardWindow)
+	if err != nil {
+		return 0, fmt.Errorf("%v: invalid sequencing config: %v", tree.TreeId, err)
+	}
+	return s.integrateBatch(ctx, tree, params.batchSize, params.guardWindow, maxRootDurationInterval, params.minRootInterval, nil /* mirror */)
+}
+
+// sequencingParams holds the settings of a signing run for a tree.
+type sequencingParams struct {
+	batchSize       int
+	guardWindow     time.Duration
+	minRootInterval time.Duration
+}
+
+// newSequencingParams returns the settings of a signing run for a tree with
+// the given SequencingConfig, which may be nil. The batch size and guard
+// window default to the passed in values.
+func newSequencingParams(cfg *trillian.SequencingConfig, batchSize int, guardWindow time.Duration) (sequencingParams, error) {
+	params := sequencingParams{
+		batchSize:   batchSize,
+		guardWindow: guardWindow,
+	}
+	if n := cfg.GetBatchSize(); n > 0 {
+		params.batchSize = int(n)
+	}
+	if gw := cfg.GetGuardWindow(); gw != nil {
+		d, err := ptypes.Duration(gw)
+		if err != nil {
+			return sequencingParams{}, fmt.Errorf("malformed guard_window: %v", err)
+		}
+		params.guardWindow = d
+	}
+	if ri := cfg.GetMinRootInterval(); ri != nil {
+		d, err := ptypes.Duration(ri)
+		if err != nil {
+			return sequencingParams{}, fmt.Errorf("malformed min_root_interval: %v", err)
+		}
+		params.minRootInterval = d
+	}
+	return params, nil
+}
+
+// integrateBatch implements IntegrateBatch. If mirror is not nil, the leaves to
+// integrate are taken from it rather than from storage, and the new root is
+// only signed and stored if it passes mirror.verify.
+func (s Sequencer) integrateBatch(ctx context.Context, tree *trillian.Tree, limit int, guardWindow, maxRootDurationInterval, minRootInterval time.Duration, mirror *mirrorBatch) (int, error) {
 	start := s.timeSource.Now()
 	label := strconv.FormatInt(tree.TreeId, 10)
 
@@ -351,6 +450,13 @@ func (s Sequencer) IntegrateBatch(ctx context.Context, tree *trillian.Tree, limi
 		seqGetRootLatency.Observe(clock.SecondsSince(s.timeSource, stageStart), label)
 		seqTreeSize.Set(float64(currentRoot.TreeSize), label)
 
+		if minRootInterval > 0 {
+			if age := time.Duration(start.UnixNano() - int64(currentRoot.TimestampNanos)); age < minRootInterval {
+				glog.V(1).Infof("%v: Latest root is %v old, waiting for min root interval %v", tree.TreeId, age, minRootInterval)
+				return nil
+			}
+		}
+
 		if currentRoot.RootHash == nil {
 			glog.Warningf("%v: Fresh log - no previous TreeHeads exist.", tree.TreeId)
 			return storage.ErrTreeNeedsInit
@@ -363,10 +469,14 @@ func (s Sequencer) IntegrateBatch(ctx context.Context, tree *trillian.Tree, limi
 			tx:         tx,
 		}
 		var st sequencingTask
-		switch tree.TreeType {
-		case trillian.TreeType_LOG:
+		switch {
+		case mirror != nil && tree.TreeType == trillian.TreeType_PREORDERED_LOG:
+			st = &mirrorSequencingTask{sequencingTaskData: taskData, batch: mirror}
+		case mirror != nil:
+			return fmt.Errorf("mirroring not supported for TreeType %v", tree.TreeType)
+		case tree.TreeType == trillian.TreeType_LOG:
 			st = (*logSequencingTask)(taskData)
-		case trillian.TreeType_PREORDERED_LOG:
+		case tree.TreeType == trillian.TreeType_PREORDERED_LOG:
 			st = (*preorderedLogSequencingTask)(taskData)
 		default:
 			return fmt.Errorf("IntegrateBatch not supported for TreeType %v", tree.TreeType)
@@ -411,8 +521,14 @@ func (s Sequencer) IntegrateBatch(ctx context.Context, tree *trillian.Tree, limi
 			return fmt.Errorf("%v: got writeRevision of %v, but expected %v", tree.TreeId, got, want)
 		}
 
-		// Collate node updates.
-		if err := s.prepareLeaves(sequencedLeaves, cr.End(), label); err != nil {
+		// Collate node updates. Only leaves of regular logs may have been
+		// promised, through signed entry timestamps, to be integrated within the
+		// maximum root duration.
+		var promiseDeadline time.Duration
+		if tree.TreeType == trillian.TreeType_LOG {
+			promiseDeadline = maxRootDurationInterval
+		}
+		if err := s.prepareLeaves(sequencedLeaves, cr.End(), promiseDeadline, label); err != nil {
 			return err
 		}
 		nodeMap, newRoot, err := s.updateCompactRange(cr, sequencedLeaves, label)
@@ -462,6 +578,11 @@ func (s Sequencer) IntegrateBatch(ctx context.Context, tree *trillian.Tree, limi
 		if newLogRoot.TimestampNanos <= currentRoot.TimestampNanos {
 			return fmt.Errorf("%v: refusing to sign root with timestamp earlier than previous root (%d <= %d)", tree.TreeId, newLogRoot.TimestampNanos, currentRoot.TimestampNanos)
 		}
+		if mirror != nil {
+			if err := mirror.verify(newLogRoot); err != nil {
+				return fmt.Errorf("%v: refusing to sign root not matching upstream log: %v", tree.TreeId, err)
+			}
+		}
 
 		newSLR, err = s.signer.SignLogRoot(newLogRoot)
 		if err != nil {
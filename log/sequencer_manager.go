@@ -36,6 +36,11 @@ type SequencerManager struct {
 	registry     extension.Registry
 	signers      map[int64]*tcrypto.Signer
 	signersMutex sync.Mutex
+
+	// nextPrune holds the earliest time of the next pruning run for each log
+	// with a retention policy.
+	nextPrune  map[int64]time.Time
+	pruneMutex sync.Mutex
 }
 
 var seqOpts = trees.NewGetOpts(trees.SequenceLog, trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG)
@@ -47,18 +52,20 @@ func NewSequencerManager(registry extension.Registry, gw time.Duration) *Sequenc
 		guardWindow: gw,
 		registry:    registry,
 		signers:     make(map[int64]*tcrypto.Signer),
+		nextPrune:   make(map[int64]time.Time),
 	}
 }
 
 // ExecutePass performs sequencing for the specified Log.
 func (s *SequencerManager) ExecutePass(ctx context.Context, logID int64, info *OperationInfo) (int, error) {
-	// TODO(Martin2112): Honor the sequencing enabled in log parameters, needs an API change
-	// so deferring it
-
 	tree, err := trees.GetTree(ctx, s.registry.AdminStorage, logID, seqOpts)
 	if err != nil {
 		return 0, fmt.Errorf("error retrieving log %v: %v", logID, err)
 	}
+	if tree.GetSequencingConfig().GetPaused() {
+		glog.V(1).Infof("%v: sequencing paused", logID)
+		return 0, nil
+	}
 	ctx = trees.NewContext(ctx, tree)
 
 	hasher, err := hashers.NewLogHasher(tree.HashStrategy)
@@ -78,13 +85,48 @@ func (s *SequencerManager) ExecutePass(ctx context.Context, logID int64, info *O
 		glog.Warning("failed to parse tree.MaxRootDuration, using zero")
 		maxRootDuration = 0
 	}
+	// The batch size and guard window are defaults, which the tree's
+	// SequencingConfig may override.
 	leaves, err := sequencer.IntegrateBatch(ctx, tree, info.BatchSize, s.guardWindow, maxRootDuration)
 	if err != nil {
 		return 0, fmt.Errorf("failed to integrate batch for %v: %v", logID, err)
 	}
+	if tree.RetentionPolicy != nil {
+		s.prune(ctx, tree, info)
+	}
 	return leaves, nil
 }
 
+// prune drops the data of the leaves outside of the tree's retention policy.
+// It runs at most once every PruneInterval for each tree, unless the previous
+// run left more leaves to be pruned. Failures are logged, but don't fail the
+// sequencing pass.
+func (s *SequencerManager) prune(ctx context.Context, tree *trillian.Tree, info *OperationInfo) {
+	now := info.TimeSource.Now()
+	s.pruneMutex.Lock()
+	next := s.nextPrune[tree.TreeId]
+	s.pruneMutex.Unlock()
+	if now.Before(next) {
+		return
+	}
+
+	pruner := NewPruner(s.registry.LogStorage, info.TimeSource, s.registry.MetricFactory)
+	pruned, err := pruner.PruneLeaves(ctx, tree, PruneBatchSize)
+	if err != nil {
+		glog.Warningf("%v: failed to prune leaves: %v", tree.TreeId, err)
+	} else if pruned > 0 {
+		glog.V(1).Infof("%v: pruned %d leaves", tree.TreeId, pruned)
+	}
+	next = now.Add(PruneInterval)
+	if err == nil && pruned >= PruneBatchSize {
+		next = now
+	}
+
+	s.pruneMutex.Lock()
+	s.nextPrune[tree.TreeId] = next
+	s.pruneMutex.Unlock()
+}
+
 // getSigner returns a signer for the given tree.
 // Signers are cached, so only one will be created per tree.
 func (s *SequencerManager) getSigner(ctx context.Context, tree *trillian.Tree) (*tcrypto.Signer, error) {
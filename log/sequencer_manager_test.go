@@ -297,6 +297,83 @@ func TestSequencerManagerGuardWindow(t *testing.T) {
 	sm.ExecutePass(ctx, logID, createTestInfo(registry))
 }
 
+func TestSequencerManagerSequencingConfig(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tree := proto.Clone(stestonly.LogTree).(*trillian.Tree)
+	tree.SequencingConfig = &trillian.SequencingConfig{
+		BatchSize:   7,
+		GuardWindow: ptypes.DurationProto(2 * time.Second),
+	}
+	logID := tree.GetTreeId()
+	mockAdminTx := storage.NewMockReadOnlyAdminTX(mockCtrl)
+	mockAdmin := &stestonly.FakeAdminStorage{ReadOnlyTX: []storage.ReadOnlyAdminTX{mockAdminTx}}
+	mockTx := storage.NewMockLogTreeTX(mockCtrl)
+	fakeStorage := &stestonly.FakeLogStorage{TX: mockTx}
+
+	var keyProto ptypes.DynamicAny
+	if err := ptypes.UnmarshalAny(tree.PrivateKey, &keyProto); err != nil {
+		t.Fatalf("Failed to unmarshal tree.PrivateKey: %v", err)
+	}
+
+	keys.RegisterHandler(fakeKeyProtoHandler(keyProto.Message, fixedGoSigner, nil))
+	defer keys.UnregisterHandler(keyProto.Message)
+
+	mockTx.EXPECT().Commit(gomock.Any()).Return(nil)
+	mockTx.EXPECT().Close().Return(nil)
+	mockTx.EXPECT().WriteRevision(gomock.Any()).AnyTimes().Return(writeRev, nil)
+	mockTx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(testSignedRoot0, nil)
+	// Expect the tree's batch size and guard window instead of the defaults.
+	mockTx.EXPECT().DequeueLeaves(gomock.Any(), 7, fakeTime.Add(-time.Second*2)).Return([]*trillian.LogLeaf{}, nil)
+
+	mockAdminTx.EXPECT().GetTree(gomock.Any(), logID).Return(tree, nil)
+	mockAdminTx.EXPECT().Commit().Return(nil)
+	mockAdminTx.EXPECT().Close().Return(nil)
+
+	registry := extension.Registry{
+		AdminStorage: mockAdmin,
+		LogStorage:   fakeStorage,
+		QuotaManager: quota.Noop(),
+	}
+
+	sm := NewSequencerManager(registry, time.Second*5)
+	if _, err := sm.ExecutePass(ctx, logID, createTestInfo(registry)); err != nil {
+		t.Fatalf("ExecutePass() = (_, %v), want nil", err)
+	}
+}
+
+// Test that sequencing is skipped, without touching the log storage, for a
+// paused tree.
+func TestSequencerManagerSequencingPaused(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tree := proto.Clone(stestonly.LogTree).(*trillian.Tree)
+	tree.SequencingConfig = &trillian.SequencingConfig{Paused: true}
+	logID := tree.GetTreeId()
+	mockAdminTx := storage.NewMockReadOnlyAdminTX(mockCtrl)
+	mockAdmin := &stestonly.FakeAdminStorage{ReadOnlyTX: []storage.ReadOnlyAdminTX{mockAdminTx}}
+	fakeStorage := &stestonly.FakeLogStorage{TXErr: errors.New("unexpected log storage access")}
+
+	mockAdminTx.EXPECT().GetTree(gomock.Any(), logID).Return(tree, nil)
+	mockAdminTx.EXPECT().Commit().Return(nil)
+	mockAdminTx.EXPECT().Close().Return(nil)
+
+	registry := extension.Registry{
+		AdminStorage: mockAdmin,
+		LogStorage:   fakeStorage,
+		QuotaManager: quota.Noop(),
+	}
+
+	sm := NewSequencerManager(registry, zeroDuration)
+	if got, err := sm.ExecutePass(ctx, logID, createTestInfo(registry)); got != 0 || err != nil {
+		t.Fatalf("ExecutePass() = (%v, %v), want (0, nil)", got, err)
+	}
+}
+
 func createTestInfo(registry extension.Registry) *OperationInfo {
 	// Set sign interval to 100 years so it won't trigger a root expiry signing unless overridden
 	return &OperationInfo{
@@ -19,11 +19,13 @@ import (
 	"crypto"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/google/trillian"
 	"github.com/google/trillian/crypto/keys/pem"
 	"github.com/google/trillian/merkle/rfc6962"
@@ -367,12 +369,13 @@ func TestIntegrateBatch(t *testing.T) {
 	}
 
 	var tests = []struct {
-		desc            string
-		params          testParameters
-		guardWindow     time.Duration
-		maxRootDuration time.Duration
-		wantCount       int
-		errStr          string
+		desc             string
+		params           testParameters
+		guardWindow      time.Duration
+		maxRootDuration  time.Duration
+		sequencingConfig *trillian.SequencingConfig
+		wantCount        int
+		errStr           string
 	}{
 		{
 			desc: "begin-tx-fails",
@@ -456,6 +459,53 @@ func TestIntegrateBatch(t *testing.T) {
 			},
 			guardWindow: guardWindow,
 		},
+		{
+			desc: "sequencing-config-overrides",
+			params: testParameters{
+				logID:               154035,
+				dequeueLimit:        5,
+				shouldCommit:        true,
+				latestSignedRoot:    testSignedRoot16,
+				dequeuedLeaves:      []*trillian.LogLeaf{},
+				skipStoreSignedRoot: true,
+				overrideDequeueTime: &expectedCutoffTime,
+			},
+			guardWindow: time.Hour,
+			sequencingConfig: &trillian.SequencingConfig{
+				BatchSize:   5,
+				GuardWindow: ptypes.DurationProto(guardWindow),
+			},
+		},
+		{
+			desc: "within-min-root-interval",
+			params: testParameters{
+				logID:               154035,
+				shouldCommit:        true,
+				latestSignedRoot:    testSignedRoot16,
+				skipDequeue:         true,
+				skipStoreSignedRoot: true,
+			},
+			maxRootDuration:  9 * time.Millisecond,
+			sequencingConfig: &trillian.SequencingConfig{MinRootInterval: ptypes.DurationProto(11 * time.Millisecond)},
+		},
+		{
+			desc: "on-min-root-interval",
+			params: testParameters{
+				logID:            154035,
+				dequeueLimit:     1,
+				shouldCommit:     true,
+				latestSignedRoot: testSignedRoot16,
+				dequeuedLeaves:   noLeaves,
+				writeRevision:    int64(testRoot16.Revision + 1),
+				merkleNodesGet:   &compactTree16,
+				updatedLeaves:    &noLeaves,
+				merkleNodesSet:   &noNodes,
+				signer:           fixedGoSigner,
+				storeSignedRoot:  newSignedRoot16,
+			},
+			maxRootDuration:  9 * time.Millisecond,
+			sequencingConfig: &trillian.SequencingConfig{MinRootInterval: ptypes.DurationProto(10 * time.Millisecond)},
+		},
 		{
 			desc: "dequeue-fails",
 			params: testParameters{
@@ -670,7 +720,7 @@ func TestIntegrateBatch(t *testing.T) {
 				qm.EXPECT().PutTokens(gomock.Any(), test.wantCount, specs).Return(nil)
 			}
 			c, ctx := createTestContext(ctrl, test.params)
-			tree := &trillian.Tree{TreeId: test.params.logID, TreeType: trillian.TreeType_LOG}
+			tree := &trillian.Tree{TreeId: test.params.logID, TreeType: trillian.TreeType_LOG, SequencingConfig: test.sequencingConfig}
 
 			got, err := c.sequencer.IntegrateBatch(ctx, tree, 1, test.guardWindow, test.maxRootDuration)
 			if err != nil {
@@ -801,3 +851,79 @@ func TestIntegrateBatch_PutTokens(t *testing.T) {
 		}()
 	}
 }
+
+func TestIntegrateBatch_LatePromises(t *testing.T) {
+	cryptoSigner := newSignerWithFixedSig(testSignedRoot.LogRootSignature)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	hasher := rfc6962.DefaultHasher
+	ts := clock.NewFake(fakeTime)
+	signer := tcrypto.NewSigner(0, cryptoSigner, crypto.SHA256)
+
+	const limit = 1000
+	const guardWindow = 10 * time.Second
+
+	tests := []struct {
+		desc            string
+		treeID          int64
+		maxRootDuration time.Duration
+		queuedAgo       []time.Duration
+		wantLate        float64
+	}{
+		{
+			desc:            "allOnTime",
+			treeID:          4201,
+			maxRootDuration: time.Hour,
+			queuedAgo:       []time.Duration{time.Minute, 59 * time.Minute},
+		},
+		{
+			desc:            "someLate",
+			treeID:          4202,
+			maxRootDuration: time.Hour,
+			queuedAgo:       []time.Duration{time.Minute, 61 * time.Minute, 2 * time.Hour},
+			wantLate:        2,
+		},
+		{
+			desc:      "noMaxRootDuration",
+			treeID:    4203,
+			queuedAgo: []time.Duration{2 * time.Hour},
+		},
+	}
+
+	any := gomock.Any()
+	ctx := context.Background()
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			leaves := make([]*trillian.LogLeaf, 0, len(test.queuedAgo))
+			for i, ago := range test.queuedAgo {
+				leaves = append(leaves, &trillian.LogLeaf{
+					LeafValue:      []byte(fmt.Sprintf("leaf-%v", i)),
+					QueueTimestamp: testonly.MustToTimestampProto(fakeTime.Add(-ago)),
+				})
+			}
+
+			logTX := storage.NewMockLogTreeTX(ctrl)
+			logTX.EXPECT().DequeueLeaves(any, any, any).Return(leaves, nil)
+			logTX.EXPECT().LatestSignedLogRoot(any).Return(testSignedRoot16, nil)
+			logTX.EXPECT().GetMerkleNodes(any, any, any).Return(compactTree16, nil)
+			logTX.EXPECT().WriteRevision(gomock.Any()).AnyTimes().Return(int64(testRoot16.Revision+1), nil)
+			logTX.EXPECT().UpdateSequencedLeaves(any, any).AnyTimes().Return(nil)
+			logTX.EXPECT().SetMerkleNodes(any, any).AnyTimes().Return(nil)
+			logTX.EXPECT().StoreSignedLogRoot(any, any).AnyTimes().Return(nil)
+			logTX.EXPECT().Commit(gomock.Any()).Return(nil)
+			logTX.EXPECT().Close().Return(nil)
+			logStorage := &stestonly.FakeLogStorage{TX: logTX}
+
+			sequencer := NewSequencer(hasher, ts, logStorage, signer, nil /* mf */, quota.Noop())
+			tree := &trillian.Tree{TreeId: test.treeID, TreeType: trillian.TreeType_LOG}
+			if _, err := sequencer.IntegrateBatch(ctx, tree, limit, guardWindow, test.maxRootDuration); err != nil {
+				t.Fatalf("IntegrateBatch() returned err = %v", err)
+			}
+			if got := seqLatePromises.Value(strconv.FormatInt(test.treeID, 10)); got != test.wantLate {
+				t.Errorf("late promises = %v, want %v", got, test.wantLate)
+			}
+		})
+	}
+}
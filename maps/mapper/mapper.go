@@ -0,0 +1,277 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mapper builds Trillian maps from the contents of Trillian logs.
+//
+// A Mapper reads the leaves of a log in batches, turns each leaf into changes
+// of map leaves with a user-supplied MapFunc, and writes each batch as a new
+// map revision. Every batch of log leaves is verified against a log root which
+// is itself checked to be consistent with the previous one. The position of
+// the Mapper in the log, and the log root it verified, are stored in the
+// metadata of the map root, so that mapping resumes exactly where it stopped.
+package mapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+	"github.com/google/trillian/client"
+	"github.com/google/trillian/maps/mapper/mapperpb"
+	"github.com/google/trillian/types"
+)
+
+// DefaultBatchSize is the default maximum number of log leaves that a Mapper
+// maps into a single map revision.
+const DefaultBatchSize = 1000
+
+// DefaultPollInterval is the default time that a Mapper waits between checks
+// for new log leaves.
+const DefaultPollInterval = 10 * time.Second
+
+// MapMutation is a change of the value of a single map leaf.
+type MapMutation struct {
+	// Index is the index of the map leaf to change.
+	Index []byte
+	// Mutate returns the new value of the leaf given its current one, which is
+	// nil if the leaf is not set. Returning an empty value clears the leaf.
+	Mutate func(value []byte) ([]byte, error)
+}
+
+// SetValue returns a MapMutation which sets the value of the map leaf at index,
+// regardless of its current value.
+func SetValue(index, value []byte) MapMutation {
+	return MapMutation{
+		Index:  index,
+		Mutate: func([]byte) ([]byte, error) { return value, nil },
+	}
+}
+
+// MapFunc returns the changes of the map that a log leaf maps to. It must be
+// deterministic, as a batch of leaves may be mapped more than once if writing
+// it to the map fails.
+type MapFunc func(leaf *trillian.LogLeaf) []MapMutation
+
+// Options configures a Mapper.
+type Options struct {
+	// BatchSize is the maximum number of log leaves mapped into a single map
+	// revision. DefaultBatchSize is used if it's not positive.
+	BatchSize int64
+	// PollInterval is the time Run waits between checks for new log leaves,
+	// once all the leaves of the log have been mapped. DefaultPollInterval is
+	// used if it's not positive.
+	PollInterval time.Duration
+}
+
+// Mapper maps the leaves of a log into a map.
+//
+// The map must be written to by a single Mapper only. Concurrent writes are
+// detected, as every revision is written with the revision it's expected to
+// have, but they make mapping fail until only one writer is left.
+type Mapper struct {
+	logID     int64
+	log       trillian.TrillianLogClient
+	verifier  *client.LogVerifier
+	mapClient *client.MapClient
+	mapWrite  trillian.TrillianMapWriteClient
+	mapFn     MapFunc
+	opts      Options
+}
+
+// New returns a Mapper which maps the leaves of logTree, read from log, into
+// mapTree, read from mapRead and written to mapWrite, using mapFn.
+func New(log trillian.TrillianLogClient, logTree *trillian.Tree, mapRead trillian.TrillianMapClient, mapWrite trillian.TrillianMapWriteClient, mapTree *trillian.Tree, mapFn MapFunc, opts Options) (*Mapper, error) {
+	if mapFn == nil {
+		return nil, errors.New("mapper: nil MapFunc")
+	}
+	verifier, err := client.NewLogVerifierFromTree(logTree)
+	if err != nil {
+		return nil, err
+	}
+	mapClient, err := client.NewMapClientFromTree(mapRead, mapTree)
+	if err != nil {
+		return nil, err
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultPollInterval
+	}
+	return &Mapper{
+		logID:     logTree.TreeId,
+		log:       log,
+		verifier:  verifier,
+		mapClient: mapClient,
+		mapWrite:  mapWrite,
+		mapFn:     mapFn,
+		opts:      opts,
+	}, nil
+}
+
+// Run maps new log leaves as they're integrated into the log, until ctx is
+// done.
+func (m *Mapper) Run(ctx context.Context) {
+	glog.Infof("%v: mapping log %v", m.mapClient.MapID, m.logID)
+	for {
+		n, err := m.MapBatch(ctx)
+		if err != nil {
+			glog.Warningf("%v: failed to map log leaves: %v", m.mapClient.MapID, err)
+		} else if n > 0 {
+			glog.V(1).Infof("%v: mapped %d log leaves", m.mapClient.MapID, n)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			glog.Infof("%v: mapper shutting down", m.mapClient.MapID)
+			return
+		case <-time.After(m.opts.PollInterval):
+		}
+	}
+}
+
+// MapBatch maps the next batch of up to BatchSize log leaves, if any, into a
+// new map revision. It returns the number of log leaves mapped. It fails if
+// the data of any of the leaves has been pruned by the log.
+func (m *Mapper) MapBatch(ctx context.Context) (int, error) {
+	mapRoot, err := m.mapClient.GetAndVerifyLatestMapRoot(ctx)
+	if err != nil {
+		return 0, err
+	}
+	meta, trusted, err := m.parseMetadata(mapRoot.Metadata)
+	if err != nil {
+		return 0, fmt.Errorf("map revision %d: %v", mapRoot.Revision, err)
+	}
+
+	// The log root is fetched with a consistency proof from the last one that
+	// was verified, so that the log can't change the leaves already mapped.
+	logClient := client.New(m.logID, m.log, m.verifier, *trusted)
+	if _, err := logClient.UpdateRoot(ctx); err != nil {
+		return 0, fmt.Errorf("failed to update log root: %v", err)
+	}
+	logRoot := logClient.GetRoot()
+	if int64(logRoot.TreeSize) <= meta.NextIndex {
+		return 0, nil
+	}
+
+	leaves, err := logClient.ListByIndexWithProof(ctx, meta.NextIndex, m.opts.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	// The data of a pruned leaf is gone, so it can't be mapped.
+	for _, leaf := range leaves {
+		if leaf.DataPruned {
+			return 0, fmt.Errorf("log leaf %d data pruned", leaf.LeafIndex)
+		}
+	}
+	mapLeaves, err := m.mapLeaves(ctx, int64(mapRoot.Revision), leaves)
+	if err != nil {
+		return 0, err
+	}
+
+	meta.LogId = m.logID
+	meta.NextIndex += int64(len(leaves))
+	if meta.LogRoot, err = logRoot.MarshalBinary(); err != nil {
+		return 0, err
+	}
+	metadata, err := proto.Marshal(meta)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := m.mapWrite.WriteLeaves(ctx, &trillian.WriteMapLeavesRequest{
+		MapId:          m.mapClient.MapID,
+		Leaves:         mapLeaves,
+		Metadata:       metadata,
+		ExpectRevision: int64(mapRoot.Revision) + 1,
+	}); err != nil {
+		return 0, fmt.Errorf("failed to write map revision %d: %v", mapRoot.Revision+1, err)
+	}
+	return len(leaves), nil
+}
+
+// parseMetadata returns the MapperMetadata held in the metadata of a map root,
+// and the log root held in it. Empty metadata, as in the roots of new maps,
+// means that nothing has been mapped yet.
+func (m *Mapper) parseMetadata(metadata []byte) (*mapperpb.MapperMetadata, *types.LogRootV1, error) {
+	var meta mapperpb.MapperMetadata
+	if err := proto.Unmarshal(metadata, &meta); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal mapper metadata: %v", err)
+	}
+	if meta.LogId != 0 && meta.LogId != m.logID {
+		return nil, nil, fmt.Errorf("map is built from log %d, not %d", meta.LogId, m.logID)
+	}
+	var root types.LogRootV1
+	if len(meta.LogRoot) > 0 {
+		if err := root.UnmarshalBinary(meta.LogRoot); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal log root: %v", err)
+		}
+	}
+	if meta.NextIndex < 0 || uint64(meta.NextIndex) > root.TreeSize {
+		return nil, nil, fmt.Errorf("next leaf index %d is beyond log root size %d", meta.NextIndex, root.TreeSize)
+	}
+	return &meta, &root, nil
+}
+
+// mapLeaves applies the map mutations of the given log leaves, in order, to the
+// map leaves at revision, and returns the changed map leaves.
+func (m *Mapper) mapLeaves(ctx context.Context, revision int64, leaves []*trillian.LogLeaf) ([]*trillian.MapLeaf, error) {
+	var mutations []MapMutation
+	for _, leaf := range leaves {
+		mutations = append(mutations, m.mapFn(leaf)...)
+	}
+	if len(mutations) == 0 {
+		return nil, nil
+	}
+
+	values := make(map[string][]byte)
+	var indexes [][]byte
+	for _, mutation := range mutations {
+		if _, ok := values[string(mutation.Index)]; !ok {
+			values[string(mutation.Index)] = nil
+			indexes = append(indexes, mutation.Index)
+		}
+	}
+	current, err := m.mapWrite.GetLeavesByRevision(ctx, &trillian.GetMapLeavesByRevisionRequest{
+		MapId:    m.mapClient.MapID,
+		Index:    indexes,
+		Revision: revision,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read map revision %d: %v", revision, err)
+	}
+	for _, leaf := range current.Leaves {
+		if _, ok := values[string(leaf.Index)]; !ok {
+			return nil, fmt.Errorf("map returned unrequested leaf %x", leaf.Index)
+		}
+		values[string(leaf.Index)] = leaf.LeafValue
+	}
+
+	for _, mutation := range mutations {
+		value, err := mutation.Mutate(values[string(mutation.Index)])
+		if err != nil {
+			return nil, fmt.Errorf("failed to mutate map leaf %x: %v", mutation.Index, err)
+		}
+		values[string(mutation.Index)] = value
+	}
+	mapLeaves := make([]*trillian.MapLeaf, 0, len(indexes))
+	for _, index := range indexes {
+		mapLeaves = append(mapLeaves, &trillian.MapLeaf{Index: index, LeafValue: values[string(index)]})
+	}
+	return mapLeaves, nil
+}
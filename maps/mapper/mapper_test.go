@@ -0,0 +1,260 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mapper
+
+import (
+	"context"
+	"crypto/sha256"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+	"github.com/google/trillian/client"
+	"github.com/google/trillian/crypto/keys/der"
+	"github.com/google/trillian/crypto/keyspb"
+	"github.com/google/trillian/extension"
+	"github.com/google/trillian/maps/mapper/mapperpb"
+	"github.com/google/trillian/monitoring"
+	"github.com/google/trillian/quota"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/memory"
+	"github.com/google/trillian/storage/testonly"
+	"github.com/google/trillian/testonly/integration"
+	"github.com/google/trillian/types"
+
+	_ "github.com/google/trillian/merkle/maphasher"
+	_ "github.com/google/trillian/merkle/rfc6962"
+)
+
+// appendValues maps log leaves of the form "key:value" to the map leaf at
+// SHA-256(key), appending value to its current value.
+func appendValues(leaf *trillian.LogLeaf) []MapMutation {
+	kv := strings.SplitN(string(leaf.LeafValue), ":", 2)
+	if len(kv) != 2 {
+		return nil
+	}
+	index := sha256.Sum256([]byte(kv[0]))
+	return []MapMutation{{
+		Index: index[:],
+		Mutate: func(value []byte) ([]byte, error) {
+			return append(append([]byte{}, value...), kv[1]...), nil
+		},
+	}}
+}
+
+type mapperEnv struct {
+	registry extension.Registry
+	logEnv   *integration.LogEnv
+	mapEnv   *integration.MapEnv
+	mapTree  *trillian.Tree
+}
+
+func newMapperEnv(ctx context.Context, t *testing.T) *mapperEnv {
+	t.Helper()
+	ts := memory.NewTreeStorage()
+	registry := extension.Registry{
+		AdminStorage:  memory.NewAdminStorage(ts),
+		LogStorage:    memory.NewLogStorage(ts, nil),
+		MapStorage:    memory.NewMapStorage(ts),
+		QuotaManager:  quota.Noop(),
+		MetricFactory: monitoring.InertMetricFactory{},
+		NewKeyProto: func(ctx context.Context, spec *keyspb.Specification) (proto.Message, error) {
+			return der.NewProtoFromSpec(spec)
+		},
+	}
+	logEnv, err := integration.NewLogEnvWithRegistry(ctx, 1, registry)
+	if err != nil {
+		t.Fatalf("NewLogEnvWithRegistry(): %v", err)
+	}
+	mapEnv, err := integration.NewMapEnvWithRegistry(registry, true /* singleTX */)
+	if err != nil {
+		logEnv.Close()
+		t.Fatalf("NewMapEnvWithRegistry(): %v", err)
+	}
+	mapTree, err := client.CreateAndInitTree(ctx, &trillian.CreateTreeRequest{Tree: testonly.MapTree}, mapEnv.Admin, mapEnv.Map, nil)
+	if err != nil {
+		t.Fatalf("CreateAndInitTree(map): %v", err)
+	}
+	return &mapperEnv{registry: registry, logEnv: logEnv, mapEnv: mapEnv, mapTree: mapTree}
+}
+
+func (e *mapperEnv) Close() {
+	e.mapEnv.Close()
+	e.logEnv.Close()
+}
+
+// newLog creates a log holding the given leaves.
+func (e *mapperEnv) newLog(ctx context.Context, t *testing.T, values ...string) *client.LogClient {
+	t.Helper()
+	tree, err := client.CreateAndInitTree(ctx, &trillian.CreateTreeRequest{Tree: testonly.LogTree}, e.logEnv.Admin, nil, e.logEnv.Log)
+	if err != nil {
+		t.Fatalf("CreateAndInitTree(log): %v", err)
+	}
+	c, err := client.NewFromTree(e.logEnv.Log, tree, types.LogRootV1{})
+	if err != nil {
+		t.Fatalf("NewFromTree(): %v", err)
+	}
+	addLeaves(ctx, t, c, values...)
+	return c
+}
+
+// addLeaves adds leaves to a log, and waits for them to be integrated.
+func addLeaves(ctx context.Context, t *testing.T, c *client.LogClient, values ...string) {
+	t.Helper()
+	want := c.GetRoot().TreeSize + uint64(len(values))
+	for _, value := range values {
+		if err := c.QueueLeaf(ctx, []byte(value)); err != nil {
+			t.Fatalf("QueueLeaf(%q): %v", value, err)
+		}
+	}
+	for c.GetRoot().TreeSize < want {
+		if _, err := c.WaitForRootUpdate(ctx); err != nil {
+			t.Fatalf("WaitForRootUpdate(): %v", err)
+		}
+	}
+}
+
+func (e *mapperEnv) newMapper(ctx context.Context, t *testing.T, logID int64, batchSize int64) *Mapper {
+	t.Helper()
+	logTree, err := e.logEnv.Admin.GetTree(ctx, &trillian.GetTreeRequest{TreeId: logID})
+	if err != nil {
+		t.Fatalf("GetTree(): %v", err)
+	}
+	m, err := New(e.logEnv.Log, logTree, e.mapEnv.Map, e.mapEnv.Write, e.mapTree, appendValues, Options{BatchSize: batchSize})
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	return m
+}
+
+func (e *mapperEnv) checkMap(ctx context.Context, t *testing.T, want map[string]string) {
+	t.Helper()
+	mc, err := client.NewMapClientFromTree(e.mapEnv.Map, e.mapTree)
+	if err != nil {
+		t.Fatalf("NewMapClientFromTree(): %v", err)
+	}
+	for key, value := range want {
+		index := sha256.Sum256([]byte(key))
+		leaves, err := mc.GetAndVerifyMapLeaves(ctx, [][]byte{index[:]})
+		if err != nil {
+			t.Fatalf("GetAndVerifyMapLeaves(%q): %v", key, err)
+		}
+		if got := string(leaves[0].LeafValue); got != value {
+			t.Errorf("Map value of %q: %q, want %q", key, got, value)
+		}
+	}
+}
+
+func TestMapBatch(t *testing.T) {
+	ctx := context.Background()
+	env := newMapperEnv(ctx, t)
+	defer env.Close()
+	log := env.newLog(ctx, t, "a:1", "b:2", "a:3", "no key")
+
+	m := env.newMapper(ctx, t, log.LogID, 3)
+	for _, want := range []int{3, 1, 0} {
+		n, err := m.MapBatch(ctx)
+		if err != nil {
+			t.Fatalf("MapBatch(): %v", err)
+		}
+		if n != want {
+			t.Errorf("MapBatch(): mapped %d leaves, want %d", n, want)
+		}
+	}
+	env.checkMap(ctx, t, map[string]string{"a": "13", "b": "2", "c": ""})
+
+	// Another mapper picks up where the first one stopped.
+	addLeaves(ctx, t, log, "c:4", "a:5")
+	m = env.newMapper(ctx, t, log.LogID, 10)
+	if n, err := m.MapBatch(ctx); err != nil || n != 2 {
+		t.Fatalf("MapBatch(): %d, %v, want 2 leaves", n, err)
+	}
+	env.checkMap(ctx, t, map[string]string{"a": "135", "b": "2", "c": "4"})
+
+	mc, err := client.NewMapClientFromTree(env.mapEnv.Map, env.mapTree)
+	if err != nil {
+		t.Fatalf("NewMapClientFromTree(): %v", err)
+	}
+	root, err := mc.GetAndVerifyLatestMapRoot(ctx)
+	if err != nil {
+		t.Fatalf("GetAndVerifyLatestMapRoot(): %v", err)
+	}
+	var meta mapperpb.MapperMetadata
+	if err := proto.Unmarshal(root.Metadata, &meta); err != nil {
+		t.Fatalf("Unmarshal(): %v", err)
+	}
+	if meta.LogId != log.LogID || meta.NextIndex != 6 {
+		t.Errorf("MapperMetadata: %+v, want log %d and next index 6", meta, log.LogID)
+	}
+	if got, want := root.Revision, uint64(3); got != want {
+		t.Errorf("Map revision %d, want %d", got, want)
+	}
+}
+
+func TestMapBatchOtherLog(t *testing.T) {
+	ctx := context.Background()
+	env := newMapperEnv(ctx, t)
+	defer env.Close()
+	log := env.newLog(ctx, t, "a:1")
+	otherLog := env.newLog(ctx, t, "b:2")
+
+	if _, err := env.newMapper(ctx, t, log.LogID, 10).MapBatch(ctx); err != nil {
+		t.Fatalf("MapBatch(): %v", err)
+	}
+	if n, err := env.newMapper(ctx, t, otherLog.LogID, 10).MapBatch(ctx); err == nil {
+		t.Errorf("MapBatch(other log): mapped %d leaves, want error", n)
+	}
+}
+
+func TestMapBatchPrunedLeaf(t *testing.T) {
+	ctx := context.Background()
+	env := newMapperEnv(ctx, t)
+	defer env.Close()
+	log := env.newLog(ctx, t, "a:1", "b:2", "c:3")
+
+	logTree, err := env.logEnv.Admin.GetTree(ctx, &trillian.GetTreeRequest{TreeId: log.LogID})
+	if err != nil {
+		t.Fatalf("GetTree(): %v", err)
+	}
+	if err := env.registry.LogStorage.ReadWriteTransaction(ctx, logTree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		_, err := tx.PruneLeaves(ctx, 2, 10)
+		return err
+	}); err != nil {
+		t.Fatalf("PruneLeaves(): %v", err)
+	}
+
+	m := env.newMapper(ctx, t, log.LogID, 10)
+	if n, err := m.MapBatch(ctx); err == nil || !strings.Contains(err.Error(), "pruned") {
+		t.Errorf("MapBatch(): %d, %v, want pruned error", n, err)
+	}
+	env.checkMap(ctx, t, map[string]string{"a": "", "b": "", "c": ""})
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(nil, testonly.LogTree, nil, nil, testonly.MapTree, nil, Options{}); err == nil {
+		t.Error("New(nil MapFunc): nil, want error")
+	}
+	if _, err := New(nil, testonly.MapTree, nil, nil, testonly.MapTree, appendValues, Options{}); err == nil {
+		t.Error("New(map as log): nil, want error")
+	}
+	m, err := New(nil, testonly.LogTree, nil, nil, testonly.MapTree, appendValues, Options{})
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	if m.opts.BatchSize != DefaultBatchSize || m.opts.PollInterval != DefaultPollInterval {
+		t.Errorf("New(): options %+v, want defaults", m.opts)
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maps
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/trillian"
+	"github.com/google/trillian/monitoring"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultRevisionGCMinInterval is the suggested min interval between map
+// revision GC sweeps.
+const DefaultRevisionGCMinInterval = 10 * time.Minute
+
+var (
+	timeNow   = time.Now
+	timeSleep = time.Sleep
+
+	// RevisionGCBatchSize is the maximum number of records of collected map
+	// revisions deleted in a single transaction.
+	RevisionGCBatchSize = 1000
+
+	collectedCounter monitoring.Counter
+	earliestGauge    monitoring.Gauge
+	revisionGCOnce   sync.Once
+)
+
+// RevisionGC garbage collects the revisions of maps which fall outside of the
+// MapRetentionPolicy of their tree.
+//
+// A revision is collected by deleting its SignedMapRoot, and the versions of
+// leaves and subtrees which are superseded at the earliest retained revision.
+// Reads of collected revisions fail with codes.OutOfRange. Records are deleted
+// in batches of RevisionGCBatchSize, each in its own transaction, so that an
+// interrupted sweep is resumed by the next one.
+type RevisionGC struct {
+	// admin is used to list the trees whose revisions are collected.
+	admin storage.AdminStorage
+
+	// mapStorage holds the revisions of the maps.
+	mapStorage storage.MapStorage
+
+	// minRunInterval defines how frequently sweeps are performed.
+	// Actual runs happen randomly between [minInterval,2*minInterval).
+	minRunInterval time.Duration
+}
+
+// NewRevisionGC returns a new RevisionGC.
+func NewRevisionGC(admin storage.AdminStorage, mapStorage storage.MapStorage, minRunInterval time.Duration, mf monitoring.MetricFactory) *RevisionGC {
+	revisionGCOnce.Do(func() {
+		if mf == nil {
+			mf = monitoring.InertMetricFactory{}
+		}
+		collectedCounter = mf.NewCounter("map_revision_gc_collected_records", "Counter of records deleted from collected map revisions", monitoring.TreeIDLabel)
+		earliestGauge = mf.NewGauge("map_revision_gc_earliest_revision", "Earliest revision of a map retained by the last sweep", monitoring.TreeIDLabel)
+	})
+	return &RevisionGC{
+		admin:          admin,
+		mapStorage:     mapStorage,
+		minRunInterval: minRunInterval,
+	}
+}
+
+// Run starts the map revision garbage collection process. It runs until ctx
+// is cancelled.
+func (gc *RevisionGC) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		count, err := gc.RunOnce(ctx)
+		if err != nil {
+			glog.Errorf("RevisionGC.Run: %v", err)
+		}
+		if count > 0 {
+			glog.Infof("RevisionGC.Run: successfully deleted %v records", count)
+		}
+
+		d := gc.minRunInterval + time.Duration(rand.Int63n(gc.minRunInterval.Nanoseconds()))
+		timeSleep(d)
+	}
+}
+
+// RunOnce performs a single map revision garbage collection sweep over all the
+// maps which have a MapRetentionPolicy. Returns the number of deleted records.
+//
+// It attempts to collect the revisions of as many maps as possible, regardless
+// of failures. If it encounters any failures the resulting error is non-nil.
+func (gc *RevisionGC) RunOnce(ctx context.Context) (int, error) {
+	trees, err := storage.ListTrees(ctx, gc.admin, false /* includeDeleted */)
+	if err != nil {
+		return 0, fmt.Errorf("error listing trees: %v", err)
+	}
+
+	count := 0
+	var errs []error
+	for _, tree := range trees {
+		if tree.TreeType != trillian.TreeType_MAP || tree.MapRetentionPolicy == nil {
+			continue
+		}
+		n, err := gc.CollectTree(ctx, tree)
+		count += n
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error collecting revisions of map %v: %v", tree.TreeId, err))
+		}
+	}
+
+	if len(errs) == 0 {
+		return count, nil
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString("encountered errors collecting map revisions:")
+	for _, err := range errs {
+		buf.WriteString("\n\t")
+		buf.WriteString(err.Error())
+	}
+	return count, errors.New(buf.String())
+}
+
+// CollectTree collects the revisions of the map tree which fall outside of its
+// MapRetentionPolicy, and returns the number of deleted records.
+func (gc *RevisionGC) CollectTree(ctx context.Context, tree *trillian.Tree) (int, error) {
+	if tree.MapRetentionPolicy == nil {
+		return 0, nil
+	}
+	end, err := gc.retainedRevision(ctx, tree)
+	if err != nil {
+		return 0, err
+	}
+	if end <= 0 {
+		return 0, nil
+	}
+
+	label := fmt.Sprint(tree.TreeId)
+	total := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		var collected int
+		err := gc.mapStorage.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+			var err error
+			collected, err = tx.CollectRevisions(ctx, end, RevisionGCBatchSize)
+			return err
+		})
+		if err != nil {
+			return total, err
+		}
+		collectedCounter.Add(float64(collected), label)
+		total += collected
+		if collected < RevisionGCBatchSize {
+			break
+		}
+		glog.V(1).Infof("RevisionGC: deleted %v records of map %v so far", total, tree.TreeId)
+	}
+	earliestGauge.Set(float64(end), label)
+	if total > 0 {
+		glog.Infof("RevisionGC: deleted %v records of revisions of map %v below %v", total, tree.TreeId, end)
+	}
+	return total, nil
+}
+
+// retainedRevision returns the earliest revision of the map tree retained
+// under its MapRetentionPolicy. The latest revision is always retained.
+func (gc *RevisionGC) retainedRevision(ctx context.Context, tree *trillian.Tree) (int64, error) {
+	policy := tree.MapRetentionPolicy
+	var cutoff time.Time
+	if policy.MaxAge != nil {
+		maxAge, err := ptypes.Duration(policy.MaxAge)
+		if err != nil {
+			return 0, fmt.Errorf("invalid max_age: %v", err)
+		}
+		if maxAge > 0 {
+			cutoff = timeNow().Add(-maxAge)
+		}
+	}
+
+	tx, err := gc.mapStorage.SnapshotForTree(ctx, tree)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Close()
+
+	smr, err := tx.LatestSignedMapRoot(ctx)
+	if err == storage.ErrTreeNeedsInit {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	var root types.MapRootV1
+	if err := root.UnmarshalBinary(smr.MapRoot); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal root: %v", err)
+	}
+	latest := int64(root.Revision)
+
+	var end int64
+	if m := policy.MaxRevisions; m > 0 && latest >= m {
+		end = latest - m + 1
+	}
+	if !cutoff.IsZero() {
+		// Revisions are ordered by time, so search for the first revision which
+		// isn't older than the cutoff. Collected revisions are older still.
+		lo, hi := end, latest
+		for lo < hi {
+			mid := lo + (hi-lo)/2
+			smr, err := tx.GetSignedMapRoot(ctx, mid)
+			if status.Code(err) == codes.OutOfRange {
+				lo = mid + 1
+				continue
+			} else if err != nil {
+				return 0, err
+			}
+			var r types.MapRootV1
+			if err := r.UnmarshalBinary(smr.MapRoot); err != nil {
+				return 0, fmt.Errorf("failed to unmarshal root at revision %d: %v", mid, err)
+			}
+			if time.Unix(0, int64(r.TimestampNanos)).Before(cutoff) {
+				lo = mid + 1
+			} else {
+				hi = mid
+			}
+		}
+		end = lo
+	}
+	return end, tx.Commit(ctx)
+}
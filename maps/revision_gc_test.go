@@ -0,0 +1,217 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maps
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/trillian"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/memory"
+	"github.com/google/trillian/testonly"
+	"github.com/google/trillian/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	tcrypto "github.com/google/trillian/crypto"
+	storageto "github.com/google/trillian/storage/testonly"
+)
+
+var (
+	gcEpoch = time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	gcIndex = make([]byte, 32)
+)
+
+// newGCMap creates a map with revisions [0, latest], written an hour apart.
+// Every revision updates the same leaf to "v<revision>".
+func newGCMap(ctx context.Context, t *testing.T, as storage.AdminStorage, ms storage.MapStorage, policy *trillian.MapRetentionPolicy, latest int64) *trillian.Tree {
+	t.Helper()
+	tree := proto.Clone(storageto.MapTree).(*trillian.Tree)
+	tree.MapRetentionPolicy = policy
+	tree, err := storage.CreateTree(ctx, as, tree)
+	if err != nil {
+		t.Fatalf("CreateTree(): %v", err)
+	}
+
+	signer := tcrypto.NewSigner(tree.TreeId, testonly.NewSignerWithFixedSig(nil, []byte("sig")), crypto.SHA256)
+	for rev := int64(0); rev <= latest; rev++ {
+		err := ms.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+			if rev > 0 {
+				leaf := &trillian.MapLeaf{Index: gcIndex, LeafValue: []byte(fmt.Sprintf("v%d", rev))}
+				if err := tx.Set(ctx, gcIndex, leaf); err != nil {
+					return err
+				}
+			}
+			root, err := signer.SignMapRoot(&types.MapRootV1{
+				RootHash:       []byte("rootHash"),
+				TimestampNanos: uint64(gcEpoch.Add(time.Duration(rev) * time.Hour).UnixNano()),
+				Revision:       uint64(rev),
+			})
+			if err != nil {
+				return err
+			}
+			return tx.StoreSignedMapRoot(ctx, root)
+		})
+		if err != nil {
+			t.Fatalf("ReadWriteTransaction(%d): %v", rev, err)
+		}
+	}
+	return tree
+}
+
+// checkRevisions verifies that revisions below end are collected, and that
+// revisions from end onwards read the same as before.
+func checkRevisions(ctx context.Context, t *testing.T, ms storage.MapStorage, tree *trillian.Tree, end, latest int64) {
+	t.Helper()
+	tx, err := ms.SnapshotForTree(ctx, tree)
+	if err != nil {
+		t.Fatalf("SnapshotForTree(): %v", err)
+	}
+	defer tx.Close()
+	for rev := int64(0); rev <= latest; rev++ {
+		_, err := tx.GetSignedMapRoot(ctx, rev)
+		if rev < end {
+			if status.Code(err) != codes.OutOfRange {
+				t.Errorf("GetSignedMapRoot(%d): %v, want OutOfRange", rev, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("GetSignedMapRoot(%d): %v", rev, err)
+			continue
+		}
+		leaves, err := tx.Get(ctx, rev, [][]byte{gcIndex})
+		if err != nil {
+			t.Fatalf("Get(%d): %v", rev, err)
+		}
+		got := ""
+		if len(leaves) > 0 {
+			got = string(leaves[0].LeafValue)
+		}
+		if want := fmt.Sprintf("v%d", rev); rev > 0 && got != want {
+			t.Errorf("Get(%d): %q, want %q", rev, got, want)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Errorf("Commit(): %v", err)
+	}
+}
+
+func TestRevisionGC_RunOnce(t *testing.T) {
+	const latest = 5
+	now := gcEpoch.Add(latest * time.Hour)
+	defer func(f func() time.Time) { timeNow = f }(timeNow)
+	timeNow = func() time.Time { return now }
+
+	for _, test := range []struct {
+		desc    string
+		policy  *trillian.MapRetentionPolicy
+		wantEnd int64
+	}{
+		{desc: "noPolicy"},
+		{desc: "emptyPolicy", policy: &trillian.MapRetentionPolicy{}},
+		{desc: "maxRevisions", policy: &trillian.MapRetentionPolicy{MaxRevisions: 2}, wantEnd: 4},
+		{desc: "maxRevisionsAboveLatest", policy: &trillian.MapRetentionPolicy{MaxRevisions: 10}},
+		{desc: "maxAge", policy: &trillian.MapRetentionPolicy{MaxAge: ptypes.DurationProto(150 * time.Minute)}, wantEnd: 3},
+		{desc: "maxAgeKeepsLatest", policy: &trillian.MapRetentionPolicy{MaxAge: ptypes.DurationProto(time.Minute)}, wantEnd: latest},
+		{
+			desc:    "maxAgeAndRevisions",
+			policy:  &trillian.MapRetentionPolicy{MaxRevisions: 4, MaxAge: ptypes.DurationProto(150 * time.Minute)},
+			wantEnd: 3,
+		},
+		{
+			desc:    "maxRevisionsAndAge",
+			policy:  &trillian.MapRetentionPolicy{MaxRevisions: 2, MaxAge: ptypes.DurationProto(150 * time.Minute)},
+			wantEnd: 4,
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			ctx := context.Background()
+			ts := memory.NewTreeStorage()
+			as := memory.NewAdminStorage(ts)
+			ms := memory.NewMapStorage(ts)
+			tree := newGCMap(ctx, t, as, ms, test.policy, latest)
+
+			gc := NewRevisionGC(as, ms, time.Minute, nil /* mf */)
+			count, err := gc.RunOnce(ctx)
+			if err != nil {
+				t.Fatalf("RunOnce(): %v", err)
+			}
+			if test.wantEnd > 0 && count == 0 {
+				t.Errorf("RunOnce(): deleted no records, want some")
+			} else if test.wantEnd == 0 && count != 0 {
+				t.Errorf("RunOnce(): deleted %d records, want none", count)
+			}
+			checkRevisions(ctx, t, ms, tree, test.wantEnd, latest)
+
+			// A second sweep has nothing left to collect.
+			if count, err := gc.RunOnce(ctx); err != nil || count != 0 {
+				t.Errorf("RunOnce(): %d, %v, want 0, nil", count, err)
+			}
+			checkRevisions(ctx, t, ms, tree, test.wantEnd, latest)
+		})
+	}
+}
+
+func TestRevisionGC_CollectTreeBatches(t *testing.T) {
+	defer func(size int) { RevisionGCBatchSize = size }(RevisionGCBatchSize)
+	RevisionGCBatchSize = 1
+
+	ctx := context.Background()
+	ts := memory.NewTreeStorage()
+	as := memory.NewAdminStorage(ts)
+	ms := memory.NewMapStorage(ts)
+	const latest = 5
+	tree := newGCMap(ctx, t, as, ms, &trillian.MapRetentionPolicy{MaxRevisions: 1}, latest)
+
+	count, err := NewRevisionGC(as, ms, time.Minute, nil /* mf */).CollectTree(ctx, tree)
+	if err != nil {
+		t.Fatalf("CollectTree(): %v", err)
+	}
+	if count <= latest {
+		t.Errorf("CollectTree(): deleted %d records, want more than %d", count, latest)
+	}
+	checkRevisions(ctx, t, ms, tree, latest, latest)
+}
+
+func TestRevisionGC_Run(t *testing.T) {
+	defer func(f func(time.Duration)) { timeSleep = f }(timeSleep)
+	ctx, cancel := context.WithCancel(context.Background())
+	ts := memory.NewTreeStorage()
+	as := memory.NewAdminStorage(ts)
+	ms := memory.NewMapStorage(ts)
+	tree := newGCMap(ctx, t, as, ms, &trillian.MapRetentionPolicy{MaxRevisions: 1}, 3)
+
+	const runInterval = 3 * time.Second
+	calls := 0
+	timeSleep = func(d time.Duration) {
+		calls++
+		if d < runInterval || d >= 2*runInterval {
+			t.Errorf("Called time.Sleep(%v), want %v", d, runInterval)
+		}
+		if calls >= 2 {
+			cancel()
+		}
+	}
+
+	NewRevisionGC(as, ms, runInterval, nil /* mf */).Run(ctx)
+	checkRevisions(context.Background(), t, ms, tree, 3, 3)
+}
@@ -54,3 +54,28 @@ func RangeNodesForPrefix(size uint64) []NodeID {
 	}
 	return ids
 }
+
+// RangeNodes returns the list of node IDs that comprise the [begin, end)
+// compact range. Nodes are ordered from left to right, i.e. in the order of
+// the hashes of a Range covering the same leaves.
+func RangeNodes(begin, end uint64) []NodeID {
+	left, right := decompose(begin, end)
+	ids := make([]NodeID, 0, bits.OnesCount64(left)+bits.OnesCount64(right))
+
+	pos := begin
+	// Iterate over perfect subtrees along the left border of the range, from
+	// lower to upper levels.
+	for bit := uint64(0); left != 0; pos, left = pos+bit, left^bit {
+		level := uint(bits.TrailingZeros64(left))
+		bit = uint64(1) << level
+		ids = append(ids, NewNodeID(level, pos>>level))
+	}
+	// Iterate over perfect subtrees along the right border of the range, from
+	// upper to lower levels.
+	for bit := uint64(0); right != 0; pos, right = pos+bit, right^bit {
+		level := uint(bits.Len64(right)) - 1
+		bit = uint64(1) << level
+		ids = append(ids, NewNodeID(level, pos>>level))
+	}
+	return ids
+}
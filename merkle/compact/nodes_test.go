@@ -44,3 +44,41 @@ func TestRangeNodesForPrefix(t *testing.T) {
 		})
 	}
 }
+
+func TestRangeNodes(t *testing.T) {
+	for _, tc := range []struct {
+		begin, end uint64
+		want       []NodeID
+	}{
+		{begin: 0, end: 0, want: []NodeID{}},
+		{begin: 5, end: 5, want: []NodeID{}},
+		{begin: 0, end: 1, want: []NodeID{{Level: 0, Index: 0}}},
+		{begin: 3, end: 4, want: []NodeID{{Level: 0, Index: 3}}},
+		{begin: 1, end: 4, want: []NodeID{{Level: 0, Index: 1}, {Level: 1, Index: 1}}},
+		{begin: 2, end: 9, want: []NodeID{{Level: 1, Index: 1}, {Level: 2, Index: 1}, {Level: 0, Index: 8}}},
+		{begin: 6, end: 29, want: []NodeID{{Level: 1, Index: 3}, {Level: 3, Index: 1}, {Level: 3, Index: 2}, {Level: 2, Index: 6}, {Level: 0, Index: 28}}},
+		{begin: 8, end: 16, want: []NodeID{{Level: 3, Index: 1}}},
+		{begin: 0, end: 100, want: []NodeID{{Level: 6, Index: 0}, {Level: 5, Index: 2}, {Level: 2, Index: 24}}},
+		{begin: 1, end: uint64(1) << 63, want: func() []NodeID {
+			ids := make([]NodeID, 0, 63)
+			for level := uint(0); level < 63; level++ {
+				ids = append(ids, NodeID{Level: level, Index: 1})
+			}
+			return ids
+		}()},
+	} {
+		t.Run(fmt.Sprintf("range:%d:%d", tc.begin, tc.end), func(t *testing.T) {
+			if got, want := RangeNodes(tc.begin, tc.end), tc.want; !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("RangeNodes: got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestRangeNodesAndPrefix(t *testing.T) {
+	for size := uint64(0); size < 300; size++ {
+		if got, want := RangeNodes(0, size), RangeNodesForPrefix(size); !reflect.DeepEqual(got, want) {
+			t.Fatalf("RangeNodes(0, %d): got %v, want %v", size, got, want)
+		}
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"math/bits"
 
+	"github.com/google/trillian/merkle/compact"
 	"github.com/google/trillian/merkle/hashers"
 )
 
@@ -86,6 +87,160 @@ func (v LogVerifier) RootFromInclusionProof(leafIndex, treeSize int64, proof [][
 	return res, nil
 }
 
+// VerifyBatchInclusionProof verifies the correctness of a batch inclusion
+// proof for the leaves with the given strictly increasing indices and
+// corresponding leaf hashes, in a tree of the given size with the given root.
+// The proof is expected to be in the format produced by
+// CalcBatchInclusionProofNodeAddresses, i.e. the hashes of the subtrees that
+// contain none of the leaves, ordered from left to right.
+func (v LogVerifier) VerifyBatchInclusionProof(leafIndices []int64, treeSize int64, proof [][]byte, root []byte, leafHashes [][]byte) error {
+	calcRoot, err := v.RootFromBatchInclusionProof(leafIndices, treeSize, proof, leafHashes)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(calcRoot, root) {
+		return RootMismatchError{
+			CalculatedRoot: calcRoot,
+			ExpectedRoot:   root,
+		}
+	}
+	return nil
+}
+
+// RootFromBatchInclusionProof calculates the expected tree root given a batch
+// inclusion proof and the leaves it covers. See VerifyBatchInclusionProof for
+// the meaning of the parameters.
+func (v LogVerifier) RootFromBatchInclusionProof(leafIndices []int64, treeSize int64, proof [][]byte, leafHashes [][]byte) ([]byte, error) {
+	switch {
+	case len(leafIndices) == 0:
+		return nil, errors.New("no leaves to verify")
+	case len(leafIndices) != len(leafHashes):
+		return nil, fmt.Errorf("got %d leaf indices, but %d leaf hashes", len(leafIndices), len(leafHashes))
+	case treeSize < 0:
+		return nil, fmt.Errorf("treeSize %d < 0", treeSize)
+	}
+	for i, index := range leafIndices {
+		switch {
+		case index < 0:
+			return nil, fmt.Errorf("leafIndex %d < 0", index)
+		case index >= treeSize:
+			return nil, fmt.Errorf("leafIndex is beyond treeSize: %d >= %d", index, treeSize)
+		case i > 0 && index <= leafIndices[i-1]:
+			return nil, fmt.Errorf("leafIndex %d is not greater than previous leafIndex %d", index, leafIndices[i-1])
+		}
+		if got, want := len(leafHashes[i]), v.hasher.Size(); got != want {
+			return nil, fmt.Errorf("leafHash has unexpected size %d, want %d", got, want)
+		}
+	}
+
+	b := batchProofVerifier{hasher: v.hasher, indices: leafIndices, leafHashes: leafHashes, proof: proof}
+	res, err := b.subtreeHash(0, treeSize)
+	if err != nil {
+		return nil, err
+	}
+	if len(b.proof) > 0 {
+		return nil, fmt.Errorf("wrong proof size %d, want %d", len(proof), len(proof)-len(b.proof))
+	}
+	return res, nil
+}
+
+// batchProofVerifier recomputes the hashes of subtrees from the leaves and
+// proof hashes of a batch inclusion proof, consuming them in order.
+type batchProofVerifier struct {
+	hasher     hashers.LogHasher
+	indices    []int64
+	leafHashes [][]byte
+	proof      [][]byte
+}
+
+// subtreeHash returns the hash of the subtree covering leaves [begin, end),
+// as defined by RFC 6962. Subtrees containing none of the remaining leaves are
+// taken from the proof, the others are computed recursively.
+func (b *batchProofVerifier) subtreeHash(begin, end int64) ([]byte, error) {
+	if len(b.indices) == 0 || b.indices[0] >= end {
+		if len(b.proof) == 0 {
+			return nil, errors.New("proof is too short")
+		}
+		res := b.proof[0]
+		b.proof = b.proof[1:]
+		return res, nil
+	}
+	if end-begin == 1 {
+		res := b.leafHashes[0]
+		b.indices, b.leafHashes = b.indices[1:], b.leafHashes[1:]
+		return res, nil
+	}
+
+	// The left subtree is the largest perfect subtree smaller than the range.
+	split := begin + int64(1)<<uint(bits.Len64(uint64(end-begin-1))-1)
+	left, err := b.subtreeHash(begin, split)
+	if err != nil {
+		return nil, err
+	}
+	right, err := b.subtreeHash(split, end)
+	if err != nil {
+		return nil, err
+	}
+	return b.hasher.HashChildren(left, right), nil
+}
+
+// VerifyRangeProof verifies the correctness of a range inclusion proof for
+// the consecutive leaves with the given hashes, the first of which is at index
+// begin, in a tree of the given size with the given root. The proof consists
+// of the hashes of the [0, begin) and [begin+len(leafHashes), treeSize)
+// compact ranges, as listed by compact.RangeNodes, ordered from left to right.
+func (v LogVerifier) VerifyRangeProof(begin, treeSize int64, left, right [][]byte, root []byte, leafHashes [][]byte) error {
+	calcRoot, err := v.RootFromRangeProof(begin, treeSize, left, right, leafHashes)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(calcRoot, root) {
+		return RootMismatchError{
+			CalculatedRoot: calcRoot,
+			ExpectedRoot:   root,
+		}
+	}
+	return nil
+}
+
+// RootFromRangeProof calculates the expected tree root given a range
+// inclusion proof and the leaves it covers. See VerifyRangeProof for the
+// meaning of the parameters.
+func (v LogVerifier) RootFromRangeProof(begin, treeSize int64, left, right [][]byte, leafHashes [][]byte) ([]byte, error) {
+	end := begin + int64(len(leafHashes))
+	switch {
+	case len(leafHashes) == 0:
+		return nil, errors.New("no leaves to verify")
+	case begin < 0:
+		return nil, fmt.Errorf("begin %d < 0", begin)
+	case end > treeSize:
+		return nil, fmt.Errorf("range end is beyond treeSize: %d > %d", end, treeSize)
+	}
+
+	f := &compact.RangeFactory{Hash: v.hasher.HashChildren}
+	// The range takes ownership of the hashes, and modifies them as it grows.
+	rng, err := f.NewRange(0, uint64(begin), append([][]byte(nil), left...))
+	if err != nil {
+		return nil, fmt.Errorf("invalid left proof: %v", err)
+	}
+	for _, hash := range leafHashes {
+		if got, want := len(hash), v.hasher.Size(); got != want {
+			return nil, fmt.Errorf("leafHash has unexpected size %d, want %d", got, want)
+		}
+		if err := rng.Append(hash, nil); err != nil {
+			return nil, err
+		}
+	}
+	rightRng, err := f.NewRange(uint64(end), uint64(treeSize), right)
+	if err != nil {
+		return nil, fmt.Errorf("invalid right proof: %v", err)
+	}
+	if err := rng.AppendRange(rightRng, nil); err != nil {
+		return nil, err
+	}
+	return rng.GetRootHash(nil)
+}
+
 // VerifyConsistencyProof checks that the passed in consistency proof is valid
 // between the passed in tree snapshots. Snapshots are the respective tree
 // sizes. Accepts shapshot2 >= snapshot1 >= 0.
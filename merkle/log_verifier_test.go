@@ -18,9 +18,11 @@ import (
 	"bytes"
 	"encoding/hex"
 	"fmt"
+	"sort"
 	"strings"
 	"testing"
 
+	"github.com/google/trillian/merkle/compact"
 	"github.com/google/trillian/merkle/rfc6962"
 )
 
@@ -338,6 +340,129 @@ func TestVerifyInclusionProofGenerated(t *testing.T) {
 	}
 }
 
+func TestVerifyBatchInclusionProofGenerated(t *testing.T) {
+	tree, v := createTree(0)
+	for size := int64(1); size <= 40; size++ {
+		growTree(tree, size)
+		root := tree.CurrentRoot().Hash()
+		// Try batches of leaves spaced out by different strides.
+		for stride := int64(1); stride <= size; stride++ {
+			for first := int64(0); first < stride && first < size; first++ {
+				var indices []int64
+				for i := first; i < size; i += stride {
+					indices = append(indices, i)
+				}
+				leafHashes, proof := getLeavesAndBatchProof(tree, indices)
+				if err := v.VerifyBatchInclusionProof(indices, size, proof, root, leafHashes); err != nil {
+					t.Errorf("VerifyBatchInclusionProof(%v, %d): %v", indices, size, err)
+				}
+			}
+		}
+	}
+}
+
+func TestVerifyBatchInclusionProofErrors(t *testing.T) {
+	tree, v := createTree(13)
+	root := tree.CurrentRoot().Hash()
+	indices := []int64{1, 5, 6, 12}
+	leafHashes, proof := getLeavesAndBatchProof(tree, indices)
+	if err := v.VerifyBatchInclusionProof(indices, 13, proof, root, leafHashes); err != nil {
+		t.Fatalf("VerifyBatchInclusionProof(): %v", err)
+	}
+
+	swapped := append([][]byte(nil), proof...)
+	swapped[0], swapped[1] = swapped[1], swapped[0]
+	otherLeaf := append([][]byte(nil), leafHashes...)
+	otherLeaf[2] = leafHashes[1]
+
+	for _, tc := range []struct {
+		desc       string
+		indices    []int64
+		size       int64
+		proof      [][]byte
+		root       []byte
+		leafHashes [][]byte
+	}{
+		{desc: "no-leaves", size: 13, proof: proof, root: root},
+		{desc: "wrong-root", indices: indices, size: 13, proof: proof, root: sha256EmptyTreeHash, leafHashes: leafHashes},
+		{desc: "wrong-size", indices: indices, size: 14, proof: proof, root: root, leafHashes: leafHashes},
+		{desc: "index-beyond-size", indices: []int64{1, 5, 6, 13}, size: 13, proof: proof, root: root, leafHashes: leafHashes},
+		{desc: "negative-index", indices: []int64{-1, 5, 6, 12}, size: 13, proof: proof, root: root, leafHashes: leafHashes},
+		{desc: "unordered-indices", indices: []int64{1, 6, 5, 12}, size: 13, proof: proof, root: root, leafHashes: leafHashes},
+		{desc: "duplicate-indices", indices: []int64{1, 5, 5, 12}, size: 13, proof: proof, root: root, leafHashes: leafHashes},
+		{desc: "missing-leaf-hash", indices: indices, size: 13, proof: proof, root: root, leafHashes: leafHashes[1:]},
+		{desc: "wrong-leaf-hash", indices: indices, size: 13, proof: proof, root: root, leafHashes: otherLeaf},
+		{desc: "short-leaf-hash", indices: indices, size: 13, proof: proof, root: root, leafHashes: append([][]byte{{1}}, leafHashes[1:]...)},
+		{desc: "short-proof", indices: indices, size: 13, proof: proof[1:], root: root, leafHashes: leafHashes},
+		{desc: "long-proof", indices: indices, size: 13, proof: extend(proof, sha256EmptyTreeHash), root: root, leafHashes: leafHashes},
+		{desc: "swapped-proof", indices: indices, size: 13, proof: swapped, root: root, leafHashes: leafHashes},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			if err := v.VerifyBatchInclusionProof(tc.indices, tc.size, tc.proof, tc.root, tc.leafHashes); err == nil {
+				t.Error("Incorrectly verified invalid batch proof")
+			}
+		})
+	}
+}
+
+func TestVerifyRangeProofGenerated(t *testing.T) {
+	tree, v := createTree(0)
+	for size := int64(1); size <= 40; size++ {
+		growTree(tree, size)
+		root := tree.CurrentRoot().Hash()
+		for begin := int64(0); begin < size; begin++ {
+			for end := begin + 1; end <= size; end++ {
+				leafHashes, left, right := getLeavesAndRangeProof(tree, begin, end)
+				if err := v.VerifyRangeProof(begin, size, left, right, root, leafHashes); err != nil {
+					t.Errorf("VerifyRangeProof(%d, %d, %d): %v", begin, end, size, err)
+				}
+			}
+		}
+	}
+}
+
+func TestVerifyRangeProofErrors(t *testing.T) {
+	tree, v := createTree(13)
+	root := tree.CurrentRoot().Hash()
+	leafHashes, left, right := getLeavesAndRangeProof(tree, 3, 7)
+	if err := v.VerifyRangeProof(3, 13, left, right, root, leafHashes); err != nil {
+		t.Fatalf("VerifyRangeProof(): %v", err)
+	}
+
+	swapped := append([][]byte(nil), right...)
+	swapped[0], swapped[1] = swapped[1], swapped[0]
+	otherLeaf := append([][]byte(nil), leafHashes...)
+	otherLeaf[2] = leafHashes[1]
+
+	for _, tc := range []struct {
+		desc        string
+		begin, size int64
+		left, right [][]byte
+		root        []byte
+		leafHashes  [][]byte
+	}{
+		{desc: "no-leaves", begin: 3, size: 13, left: left, right: right, root: root},
+		{desc: "wrong-root", begin: 3, size: 13, left: left, right: right, root: sha256EmptyTreeHash, leafHashes: leafHashes},
+		{desc: "wrong-begin", begin: 2, size: 13, left: left, right: right, root: root, leafHashes: leafHashes},
+		{desc: "negative-begin", begin: -1, size: 13, left: left, right: right, root: root, leafHashes: leafHashes},
+		{desc: "wrong-size", begin: 3, size: 15, left: left, right: right, root: root, leafHashes: leafHashes},
+		{desc: "range-beyond-size", begin: 10, size: 13, left: left, right: right, root: root, leafHashes: leafHashes},
+		{desc: "missing-leaf-hash", begin: 3, size: 13, left: left, right: right, root: root, leafHashes: leafHashes[1:]},
+		{desc: "wrong-leaf-hash", begin: 3, size: 13, left: left, right: right, root: root, leafHashes: otherLeaf},
+		{desc: "short-leaf-hash", begin: 3, size: 13, left: left, right: right, root: root, leafHashes: append([][]byte{{1}}, leafHashes[1:]...)},
+		{desc: "short-left-proof", begin: 3, size: 13, left: left[1:], right: right, root: root, leafHashes: leafHashes},
+		{desc: "long-right-proof", begin: 3, size: 13, left: left, right: extend(right, sha256EmptyTreeHash), root: root, leafHashes: leafHashes},
+		{desc: "swapped-proofs", begin: 3, size: 13, left: right, right: left, root: root, leafHashes: leafHashes},
+		{desc: "swapped-right-proof", begin: 3, size: 13, left: left, right: swapped, root: root, leafHashes: leafHashes},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			if err := v.VerifyRangeProof(tc.begin, tc.size, tc.left, tc.right, tc.root, tc.leafHashes); err == nil {
+				t.Error("Incorrectly verified invalid range proof")
+			}
+		})
+	}
+}
+
 func TestVerifyConsistencyProof(t *testing.T) {
 	v := NewLogVerifier(rfc6962.DefaultHasher)
 
@@ -529,6 +654,69 @@ func getLeafAndProof(tree *InMemoryMerkleTree, index int64) ([]byte, [][]byte) {
 	return leafHash, proof
 }
 
+// getLeavesAndBatchProof returns the leaf hashes and batch inclusion proof for
+// the given strictly increasing indices. The proof is built by merging the
+// individual inclusion proofs of the leaves, dropping nodes which are
+// duplicated or which contain any of the leaves.
+func getLeavesAndBatchProof(tree *InMemoryMerkleTree, indices []int64) ([][]byte, [][]byte) {
+	type node struct {
+		begin int64
+		hash  []byte
+	}
+	var nodes []node
+	seen := make(map[int64]bool)
+	leafHashes := make([][]byte, 0, len(indices))
+	for _, index := range indices {
+		leafHashes = append(leafHashes, tree.LeafHash(index+1))
+		for _, d := range tree.PathToCurrentRoot(index + 1) {
+			// Recomputed nodes have negated coordinates.
+			level, sibling := d.XCoord, d.YCoord
+			if level < 0 || sibling < 0 {
+				level, sibling = -level, -sibling
+			}
+			begin, end := sibling<<uint(level), (sibling+1)<<uint(level)
+			covered := false
+			for _, i := range indices {
+				covered = covered || (i >= begin && i < end)
+			}
+			if !covered && !seen[begin] {
+				seen[begin] = true
+				nodes = append(nodes, node{begin: begin, hash: d.Value.Hash()})
+			}
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].begin < nodes[j].begin })
+	proof := make([][]byte, 0, len(nodes))
+	for _, n := range nodes {
+		proof = append(proof, n.hash)
+	}
+	return leafHashes, proof
+}
+
+// getLeavesAndRangeProof returns the hashes of the leaves in [begin, end), and
+// the hashes of the compact ranges to the left and to the right of them.
+func getLeavesAndRangeProof(tree *InMemoryMerkleTree, begin, end int64) ([][]byte, [][]byte, [][]byte) {
+	var nodeHash func(level uint, index int64) []byte
+	nodeHash = func(level uint, index int64) []byte {
+		if level == 0 {
+			return tree.LeafHash(index + 1)
+		}
+		return rfc6962.DefaultHasher.HashChildren(nodeHash(level-1, 2*index), nodeHash(level-1, 2*index+1))
+	}
+	rangeHashes := func(begin, end int64) [][]byte {
+		var hashes [][]byte
+		for _, id := range compact.RangeNodes(uint64(begin), uint64(end)) {
+			hashes = append(hashes, nodeHash(id.Level, int64(id.Index)))
+		}
+		return hashes
+	}
+	leafHashes := make([][]byte, 0, end-begin)
+	for index := begin; index < end; index++ {
+		leafHashes = append(leafHashes, tree.LeafHash(index+1))
+	}
+	return leafHashes, rangeHashes(0, begin), rangeHashes(end, tree.LeafCount())
+}
+
 func rawProof(desc []TreeEntryDescriptor) [][]byte {
 	proof := make([][]byte, len(desc))
 	for i, d := range desc {
@@ -16,6 +16,7 @@ package merkle
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 
 	"github.com/google/trillian"
@@ -92,3 +93,93 @@ func VerifyMapInclusionProof(treeID int64, leaf *trillian.MapLeaf, expectedRoot
 	}
 	return nil
 }
+
+// VerifyCompressedMapInclusionProof is like VerifyMapInclusionProof, for a
+// proof in compressed form. proofHashes holds the deduplicated entries which
+// the proof refers to, if it is part of a batch.
+func VerifyCompressedMapInclusionProof(treeID int64, leaf *trillian.MapLeaf, expectedRoot []byte, proof *trillian.CompressedMapInclusionProof, proofHashes [][]byte, h hashers.MapHasher) error {
+	full, err := DecompressMapInclusionProof(proof, proofHashes, h)
+	if err != nil {
+		return err
+	}
+	return VerifyMapInclusionProof(treeID, leaf, expectedRoot, full, h)
+}
+
+// CompressMapInclusionProof returns the compressed form of a map inclusion
+// proof, which holds a bitmap of its non-empty entries and only those entries.
+func CompressMapInclusionProof(proof [][]byte) *trillian.CompressedMapInclusionProof {
+	c := &trillian.CompressedMapInclusionProof{Bitmap: make([]byte, (len(proof)+7)/8)}
+	for height, element := range proof {
+		if len(element) == 0 {
+			continue
+		}
+		c.Bitmap[height/8] |= 0x80 >> uint(height%8)
+		c.Hashes = append(c.Hashes, element)
+	}
+	return c
+}
+
+// CompressMapInclusionProofs returns the compressed forms of a batch of map
+// inclusion proofs, along with their deduplicated non-empty entries, which the
+// compressed proofs refer to by position.
+func CompressMapInclusionProofs(proofs [][][]byte) ([]*trillian.CompressedMapInclusionProof, [][]byte) {
+	var proofHashes [][]byte
+	refs := make(map[string]uint32)
+	ret := make([]*trillian.CompressedMapInclusionProof, 0, len(proofs))
+	for _, proof := range proofs {
+		c := CompressMapInclusionProof(proof)
+		for _, hash := range c.Hashes {
+			ref, ok := refs[string(hash)]
+			if !ok {
+				ref = uint32(len(proofHashes))
+				refs[string(hash)] = ref
+				proofHashes = append(proofHashes, hash)
+			}
+			c.HashRefs = append(c.HashRefs, ref)
+		}
+		c.Hashes = nil
+		ret = append(ret, c)
+	}
+	return ret, proofHashes
+}
+
+// DecompressMapInclusionProof returns the map inclusion proof, with an entry
+// for each level of the tree, of its compressed form. The non-empty entries
+// of the proof are either in its hashes, or referred to by its hash_refs in
+// proofHashes.
+func DecompressMapInclusionProof(proof *trillian.CompressedMapInclusionProof, proofHashes [][]byte, h hashers.MapHasher) ([][]byte, error) {
+	if got, want := len(proof.GetBitmap())*8, h.BitLen(); got != want {
+		return nil, fmt.Errorf("bitmap len: %d bits, want %d", got, want)
+	}
+	hashes := proof.GetHashes()
+	if refs := proof.GetHashRefs(); len(refs) > 0 {
+		if len(hashes) > 0 {
+			return nil, errors.New("proof has both hashes and hash refs")
+		}
+		hashes = make([][]byte, 0, len(refs))
+		for _, ref := range refs {
+			if ref >= uint32(len(proofHashes)) {
+				return nil, fmt.Errorf("hash ref %d out of range [0, %d)", ref, len(proofHashes))
+			}
+			hashes = append(hashes, proofHashes[ref])
+		}
+	}
+
+	full := make([][]byte, h.BitLen())
+	for height := range full {
+		if proof.Bitmap[height/8]&(0x80>>uint(height%8)) == 0 {
+			continue
+		}
+		if len(hashes) == 0 {
+			return nil, fmt.Errorf("missing proof entry for height %d", height)
+		}
+		if got, want := len(hashes[0]), h.Size(); got != want {
+			return nil, fmt.Errorf("proof entry for height %d len: %d, want %d", height, got, want)
+		}
+		full[height], hashes = hashes[0], hashes[1:]
+	}
+	if len(hashes) != 0 {
+		return nil, fmt.Errorf("%d proof entries not in bitmap", len(hashes))
+	}
+	return full, nil
+}
@@ -15,8 +15,10 @@
 package merkle
 
 import (
+	"reflect"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/google/trillian"
 	"github.com/google/trillian/merkle/coniks"
 	"github.com/google/trillian/merkle/maphasher"
@@ -72,56 +74,58 @@ func TestConiksHasherTestVectors(t *testing.T) {
 	}
 }
 
+// mapHasherTestVector was copied from a python implementation.
+var mapHasherTestVector = struct {
+	Index        []byte
+	Value        []byte
+	Proof        [][]byte
+	ExpectedRoot []byte
+}{
+
+	testonly.HashKey("key-0-848"),
+	[]byte("value-0-848"),
+	[][]byte{
+		// 246 x nil
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil,
+		testonly.MustDecodeBase64("vMWPHFclXXchQbAGJr6pcB002vQZYHnJTfOC42E1iT8="),
+		nil,
+		testonly.MustDecodeBase64("C3VKkaOliXmuHXM0zrkSulYX6ORaNG8qWHez/dyQkQs="),
+		testonly.MustDecodeBase64("7vmVXjPm0XhOMJlnpxJa/ZKn8eeK0PIthOOy74w+sJc="),
+		testonly.MustDecodeBase64("vEWXkf+9ZJQ/oxyyOaQdIfZfsx2GCA/NldZ+UopQF6Y="),
+		testonly.MustDecodeBase64("lrGGFxtBKRdE53Dl6p0GeFgM6VomF9Fx5k/6+aIzMWc="),
+		testonly.MustDecodeBase64("I5nVuy9wljpxbgv/aE9ivo854GhFRdsAWwmmEXDjaxE="),
+		testonly.MustDecodeBase64("yAxifDRQUd+vjc6RaHG9f8tCWSa0mzV4rry50khiD3M="),
+		testonly.MustDecodeBase64("YmUpJx/UagsoBYv6PnFRaVYw3x6kAx3N3OOSyiXsGtg="),
+		testonly.MustDecodeBase64("CtC2GCsc3/zFn1DNkoUThUnn7k+DMotaNXvmceKIL4Y="),
+	},
+	testonly.MustDecodeBase64("U6ANU1en3BSbbnWqhV2nTGtQ+scBlaZf9kRPEEDZsHM="),
+}
+
 func TestMapHasherTestVectors(t *testing.T) {
-	// Test vectors were copied from a python implementation.
 	h := maphasher.Default
-	tv := struct {
-		Index        []byte
-		Value        []byte
-		Proof        [][]byte
-		ExpectedRoot []byte
-	}{
-
-		testonly.HashKey("key-0-848"),
-		[]byte("value-0-848"),
-		[][]byte{
-			// 246 x nil
-			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
-			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
-			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
-			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
-			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
-			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
-			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
-			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
-			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
-			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
-			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
-			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
-			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
-			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
-			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
-			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
-			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
-			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
-			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
-			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
-			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
-			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
-			nil, nil, nil, nil,
-			testonly.MustDecodeBase64("vMWPHFclXXchQbAGJr6pcB002vQZYHnJTfOC42E1iT8="),
-			nil,
-			testonly.MustDecodeBase64("C3VKkaOliXmuHXM0zrkSulYX6ORaNG8qWHez/dyQkQs="),
-			testonly.MustDecodeBase64("7vmVXjPm0XhOMJlnpxJa/ZKn8eeK0PIthOOy74w+sJc="),
-			testonly.MustDecodeBase64("vEWXkf+9ZJQ/oxyyOaQdIfZfsx2GCA/NldZ+UopQF6Y="),
-			testonly.MustDecodeBase64("lrGGFxtBKRdE53Dl6p0GeFgM6VomF9Fx5k/6+aIzMWc="),
-			testonly.MustDecodeBase64("I5nVuy9wljpxbgv/aE9ivo854GhFRdsAWwmmEXDjaxE="),
-			testonly.MustDecodeBase64("yAxifDRQUd+vjc6RaHG9f8tCWSa0mzV4rry50khiD3M="),
-			testonly.MustDecodeBase64("YmUpJx/UagsoBYv6PnFRaVYw3x6kAx3N3OOSyiXsGtg="),
-			testonly.MustDecodeBase64("CtC2GCsc3/zFn1DNkoUThUnn7k+DMotaNXvmceKIL4Y="),
-		},
-		testonly.MustDecodeBase64("U6ANU1en3BSbbnWqhV2nTGtQ+scBlaZf9kRPEEDZsHM="),
-	}
+	tv := mapHasherTestVector
 
 	// Copy the bad proof so we don't mess up the good proof.
 	badProof := make([][]byte, len(tv.Proof))
@@ -152,3 +156,81 @@ func TestMapHasherTestVectors(t *testing.T) {
 		}
 	}
 }
+
+func TestCompressedMapInclusionProof(t *testing.T) {
+	h := maphasher.Default
+	tv := mapHasherTestVector
+	leaf := &trillian.MapLeaf{Index: tv.Index, LeafValue: tv.Value}
+
+	c := CompressMapInclusionProof(tv.Proof)
+	if got, want := len(c.Bitmap), h.Size(); got != want {
+		t.Errorf("CompressMapInclusionProof(): bitmap of %d bytes, want %d", got, want)
+	}
+	if got, want := len(c.Hashes), 9; got != want {
+		t.Errorf("CompressMapInclusionProof(): %d hashes, want %d", got, want)
+	}
+	if err := VerifyCompressedMapInclusionProof(treeID, leaf, tv.ExpectedRoot, c, nil, h); err != nil {
+		t.Errorf("VerifyCompressedMapInclusionProof(): %v", err)
+	}
+
+	full, err := DecompressMapInclusionProof(c, nil, h)
+	if err != nil {
+		t.Fatalf("DecompressMapInclusionProof(): %v", err)
+	}
+	if !reflect.DeepEqual(full, tv.Proof) {
+		t.Errorf("DecompressMapInclusionProof(): %x, want %x", full, tv.Proof)
+	}
+
+	for _, tc := range []struct {
+		desc  string
+		proof *trillian.CompressedMapInclusionProof
+	}{
+		{desc: "shortBitmap", proof: &trillian.CompressedMapInclusionProof{Bitmap: c.Bitmap[1:], Hashes: c.Hashes}},
+		{desc: "missingHash", proof: &trillian.CompressedMapInclusionProof{Bitmap: c.Bitmap, Hashes: c.Hashes[1:]}},
+		{desc: "excessHash", proof: &trillian.CompressedMapInclusionProof{Bitmap: c.Bitmap, Hashes: append(c.Hashes, c.Hashes[0])}},
+		{desc: "shortHash", proof: &trillian.CompressedMapInclusionProof{Bitmap: c.Bitmap, Hashes: append([][]byte{c.Hashes[0][1:]}, c.Hashes[1:]...)}},
+		{desc: "hashesAndRefs", proof: &trillian.CompressedMapInclusionProof{Bitmap: c.Bitmap, Hashes: c.Hashes, HashRefs: []uint32{0}}},
+		{desc: "refOutOfRange", proof: &trillian.CompressedMapInclusionProof{Bitmap: c.Bitmap, HashRefs: []uint32{0, 1, 2, 3, 4, 5, 6, 7, 9}}},
+	} {
+		if _, err := DecompressMapInclusionProof(tc.proof, c.Hashes, h); err == nil {
+			t.Errorf("%v: DecompressMapInclusionProof(): nil, want error", tc.desc)
+		}
+	}
+
+	bad := proto.Clone(c).(*trillian.CompressedMapInclusionProof)
+	bad.Bitmap[31] ^= 0x01
+	if err := VerifyCompressedMapInclusionProof(treeID, leaf, tv.ExpectedRoot, bad, nil, h); err == nil {
+		t.Error("VerifyCompressedMapInclusionProof(wrong bitmap): nil, want error")
+	}
+}
+
+func TestCompressMapInclusionProofs(t *testing.T) {
+	h := maphasher.Default
+	tv := mapHasherTestVector
+	a, b := []byte("a"), []byte("b")
+	proofs := [][][]byte{tv.Proof, {a, nil, b, nil}, {b, a, nil, nil}, tv.Proof}
+
+	compressed, proofHashes := CompressMapInclusionProofs(proofs)
+	if got, want := len(proofHashes), 9+2; got != want {
+		t.Errorf("CompressMapInclusionProofs(): %d proof hashes, want %d", got, want)
+	}
+	if got, want := compressed[1].HashRefs, []uint32{9, 10}; !reflect.DeepEqual(got, want) {
+		t.Errorf("CompressMapInclusionProofs(): refs %v, want %v", got, want)
+	}
+	if got, want := compressed[2].HashRefs, []uint32{10, 9}; !reflect.DeepEqual(got, want) {
+		t.Errorf("CompressMapInclusionProofs(): refs %v, want %v", got, want)
+	}
+	for i, c := range compressed {
+		if len(c.Hashes) != 0 {
+			t.Errorf("CompressMapInclusionProofs(): proof %d has %d hashes, want refs only", i, len(c.Hashes))
+		}
+	}
+	if !reflect.DeepEqual(compressed[0], compressed[3]) {
+		t.Errorf("CompressMapInclusionProofs(): %v and %v differ for the same proof", compressed[0], compressed[3])
+	}
+
+	leaf := &trillian.MapLeaf{Index: tv.Index, LeafValue: tv.Value}
+	if err := VerifyCompressedMapInclusionProof(treeID, leaf, tv.ExpectedRoot, compressed[3], proofHashes, h); err != nil {
+		t.Errorf("VerifyCompressedMapInclusionProof(): %v", err)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"math/bits"
+	"sort"
 
 	"github.com/golang/glog"
 	"github.com/google/trillian/storage"
@@ -75,6 +76,83 @@ func CalcInclusionProofNodeAddresses(snapshot, index, treeSize int64, maxBitLen
 	return pathFromNodeToRootAtSnapshot(index, 0, snapshot, treeSize, maxBitLen)
 }
 
+// CalcBatchInclusionProofNodeAddresses returns the tree node IDs needed to
+// build a single inclusion proof for all of the specified leaves, given as
+// strictly increasing indices, at the given snapshot. The snapshot, treeSize
+// and maxBitLen parameters have the same meaning as for
+// CalcInclusionProofNodeAddresses.
+//
+// The proof is the union of the individual inclusion proofs for the leaves,
+// with duplicate nodes removed, as are nodes which the verifier can compute
+// from the leaves themselves. The remaining nodes are ordered by the index of
+// their leftmost leaf, which is the order in which a verifier will need them
+// when recomputing the root hash from left to right.
+func CalcBatchInclusionProofNodeAddresses(snapshot int64, indices []int64, treeSize int64, maxBitLen int) ([]NodeFetch, error) {
+	if err := checkSnapshot("snapshot", snapshot, treeSize); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid parameter for batch inclusion proof: %v", err)
+	}
+	if len(indices) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid parameter for batch inclusion proof: no indices")
+	}
+	for i, index := range indices {
+		if index < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid parameter for batch inclusion proof: index %d is < 0", index)
+		}
+		if index >= snapshot {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid parameter for batch inclusion proof: index %d is >= snapshot %d", index, snapshot)
+		}
+		if i > 0 && index <= indices[i-1] {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid parameter for batch inclusion proof: index %d is <= previous index %d", index, indices[i-1])
+		}
+	}
+	if maxBitLen <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid parameter for batch inclusion proof: maxBitLen %d <= 0", maxBitLen)
+	}
+
+	// covered returns whether the subtree at the given level and index
+	// contains any of the requested leaves, in which case its hash is
+	// computed by the verifier instead of being part of the proof.
+	covered := func(level int, node int64) bool {
+		begin := node << uint(level)
+		i := sort.Search(len(indices), func(i int) bool { return indices[i] >= begin })
+		return i < len(indices) && indices[i]>>uint(level) == node
+	}
+
+	type proofNode struct {
+		begin   int64
+		fetches []NodeFetch
+	}
+	var nodes []proofNode
+	seen := make(map[[2]int64]bool)
+	for _, index := range indices {
+		node, level := index, 0
+		for lastNode := snapshot - 1; lastNode != 0; lastNode >>= 1 {
+			sibling := node ^ 1
+			if key := [2]int64{int64(level), sibling}; !seen[key] && !covered(level, sibling) {
+				seen[key] = true
+				fetches, err := siblingFetches(node, level, lastNode, snapshot, treeSize, maxBitLen)
+				if err != nil {
+					return nil, err
+				}
+				if len(fetches) > 0 {
+					nodes = append(nodes, proofNode{begin: sibling << uint(level), fetches: fetches})
+				}
+			}
+			node >>= 1
+			level++
+		}
+	}
+
+	// The nodes don't overlap, because none of them contains a requested
+	// leaf, so ordering them by their first leaf is unambiguous.
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].begin < nodes[j].begin })
+	var proof []NodeFetch
+	for _, n := range nodes {
+		proof = append(proof, n.fetches...)
+	}
+	return proof, nil
+}
+
 // CalcConsistencyProofNodeAddresses returns the tree node IDs needed to
 // build a consistency proof between two specified tree sizes. snapshot1 and snapshot2 represent
 // the two tree sizes for which consistency should be proved, treeSize is the actual size of the
@@ -153,56 +231,13 @@ func pathFromNodeToRootAtSnapshot(node int64, level int, snapshot, treeSize int6
 
 	// Move up, recording the sibling of the current node at each level.
 	for lastNode != 0 {
-		sibling := node ^ 1
-		if sibling < lastNode {
-			// The sibling is not the last node of the level in the snapshot tree
-			if glog.V(vvLevel) {
-				glog.Infof("Not last: S:%d L:%d", sibling, level)
-			}
-			n, err := storage.NewNodeIDForTreeCoords(int64(level), sibling, maxBitLen)
-			if err != nil {
-				return nil, err
-			}
-			proof = append(proof, NodeFetch{NodeID: n})
-		} else if sibling == lastNode {
-			// The sibling is the last node of the level in the snapshot tree.
-			// We might need to recompute a previous hash value here. This can only occur on the
-			// rightmost tree nodes because this is the only area of the tree that is not fully populated.
-			if glog.V(vvLevel) {
-				glog.Infof("Last: S:%d L:%d", sibling, level)
-			}
-
-			if snapshot == treeSize {
-				// No recomputation required as we're using the tree in its current state
-				// Account for non existent nodes - these can only be the rightmost node at an
-				// intermediate (non leaf) level in the tree so will always be a right sibling.
-				n, err := siblingIDSkipLevels(snapshot, lastNode, level, node, maxBitLen)
-				if err != nil {
-					return nil, err
-				}
-				proof = append(proof, NodeFetch{NodeID: n})
-			} else {
-				// We need to recompute this node, as it was at the prior snapshot point. We record
-				// the additional fetches needed to do this later
-				rehashFetches, err := recomputePastSnapshot(snapshot, treeSize, level, maxBitLen)
-				if err != nil {
-					return nil, err
-				}
-
-				// Extra check that the recomputation produced one node
-				if err = checkRecomputation(rehashFetches); err != nil {
-					return nil, err
-				}
-
-				proof = append(proof, rehashFetches...)
-			}
-		} else {
-			if glog.V(vvLevel) {
-				glog.Infof("Nonexistent: S:%d L:%d", sibling, level)
-			}
+		fetches, err := siblingFetches(node, level, lastNode, snapshot, treeSize, maxBitLen)
+		if err != nil {
+			return nil, err
 		}
+		proof = append(proof, fetches...)
 
-		// Sibling > lastNode so does not exist, move up
+		// Move up to the parent
 		node >>= 1
 		lastNode >>= 1
 		level++
@@ -211,6 +246,61 @@ func pathFromNodeToRootAtSnapshot(node int64, level int, snapshot, treeSize int6
 	return proof, nil
 }
 
+// siblingFetches returns the fetches needed to obtain the hash of the sibling
+// of the given node, at a level whose last node (in the snapshot tree) is
+// lastNode. The result is empty if the sibling doesn't exist in the snapshot
+// tree, and consists of several fetches if the sibling needs rehashing.
+func siblingFetches(node int64, level int, lastNode, snapshot, treeSize int64, maxBitLen int) ([]NodeFetch, error) {
+	sibling := node ^ 1
+	if sibling < lastNode {
+		// The sibling is not the last node of the level in the snapshot tree
+		if glog.V(vvLevel) {
+			glog.Infof("Not last: S:%d L:%d", sibling, level)
+		}
+		n, err := storage.NewNodeIDForTreeCoords(int64(level), sibling, maxBitLen)
+		if err != nil {
+			return nil, err
+		}
+		return []NodeFetch{{NodeID: n}}, nil
+	} else if sibling == lastNode {
+		// The sibling is the last node of the level in the snapshot tree.
+		// We might need to recompute a previous hash value here. This can only occur on the
+		// rightmost tree nodes because this is the only area of the tree that is not fully populated.
+		if glog.V(vvLevel) {
+			glog.Infof("Last: S:%d L:%d", sibling, level)
+		}
+
+		if snapshot == treeSize {
+			// No recomputation required as we're using the tree in its current state
+			// Account for non existent nodes - these can only be the rightmost node at an
+			// intermediate (non leaf) level in the tree so will always be a right sibling.
+			n, err := siblingIDSkipLevels(snapshot, lastNode, level, node, maxBitLen)
+			if err != nil {
+				return nil, err
+			}
+			return []NodeFetch{{NodeID: n}}, nil
+		}
+
+		// We need to recompute this node, as it was at the prior snapshot point. We record
+		// the additional fetches needed to do this later
+		rehashFetches, err := recomputePastSnapshot(snapshot, treeSize, level, maxBitLen)
+		if err != nil {
+			return nil, err
+		}
+
+		// Extra check that the recomputation produced one node
+		if err = checkRecomputation(rehashFetches); err != nil {
+			return nil, err
+		}
+		return rehashFetches, nil
+	}
+
+	if glog.V(vvLevel) {
+		glog.Infof("Nonexistent: S:%d L:%d", sibling, level)
+	}
+	return nil, nil
+}
+
 // recomputePastSnapshot does the work to recalculate nodes that need to be rehashed because the
 // tree state at the snapshot size differs from the size we've stored it at. The calculations
 // also need to take into account missing levels, see the tree diagrams in this file.
@@ -16,6 +16,7 @@ package merkle
 
 import (
 	"fmt"
+	"sort"
 	"testing"
 
 	"github.com/google/trillian/storage"
@@ -88,6 +89,27 @@ var expectedPathSize7Index6 = []NodeFetch{ // from j
 	MustCreateNodeFetchForTreeCoords(2, 0, 64, false), // k
 }
 
+var expectedBatchPathSize7Index3 = []NodeFetch{ // from d
+	MustCreateNodeFetchForTreeCoords(1, 0, 64, false), // g
+	MustCreateNodeFetchForTreeCoords(0, 2, 64, false), // c
+	MustCreateNodeFetchForTreeCoords(2, 1, 64, false), // l
+}
+var expectedBatchPathSize7Index0And3 = []NodeFetch{ // from a and d
+	MustCreateNodeFetchForTreeCoords(0, 1, 64, false), // b
+	MustCreateNodeFetchForTreeCoords(0, 2, 64, false), // c
+	MustCreateNodeFetchForTreeCoords(2, 1, 64, false), // l
+}
+var expectedBatchPathSize7Index4And6 = []NodeFetch{ // from e and j
+	MustCreateNodeFetchForTreeCoords(2, 0, 64, false), // k
+	MustCreateNodeFetchForTreeCoords(0, 5, 64, false), // f
+}
+var expectedBatchPathSize7Index1And2And5 = []NodeFetch{ // from b, c and f
+	MustCreateNodeFetchForTreeCoords(0, 0, 64, false), // a
+	MustCreateNodeFetchForTreeCoords(0, 3, 64, false), // d
+	MustCreateNodeFetchForTreeCoords(0, 4, 64, false), // e
+	MustCreateNodeFetchForTreeCoords(0, 6, 64, false), // j
+}
+
 // Expected consistency proofs built from the examples in RFC 6962. Again, in our implementation
 // node layers are filled from the bottom upwards.
 var expectedConsistencyProofFromSize1To2 = []NodeFetch{
@@ -214,6 +236,33 @@ var pathTestBad = []auditPathTestData{
 	{7, 8, []NodeFetch{}},
 }
 
+// These should all successfully compute the expected batch path
+var batchPathTests = []struct {
+	treeSize     int64
+	leafIndices  []int64
+	expectedPath []NodeFetch
+}{
+	{1, []int64{0}, []NodeFetch{}},
+	{7, []int64{3}, expectedBatchPathSize7Index3},
+	{7, []int64{0, 3}, expectedBatchPathSize7Index0And3},
+	{7, []int64{4, 6}, expectedBatchPathSize7Index4And6},
+	{7, []int64{1, 2, 5}, expectedBatchPathSize7Index1And2And5},
+	{7, []int64{0, 1, 2, 3, 4, 5, 6}, []NodeFetch{}},
+}
+
+// These should all fail
+var batchPathTestsBad = []struct {
+	treeSize    int64
+	leafIndices []int64
+}{
+	{7, nil},
+	{7, []int64{-1, 3}},
+	{7, []int64{3, 7}},
+	{7, []int64{3, 3}},
+	{7, []int64{4, 3}},
+	{0, []int64{0}},
+}
+
 // These should compute the expected consistency proofs
 var consistencyTests = []consistencyProofTestData{
 	{1, 2, expectedConsistencyProofFromSize1To2},
@@ -270,6 +319,49 @@ func TestCalcInclusionProofNodeAddressesRejectsBadBitLen(t *testing.T) {
 	}
 }
 
+func TestCalcBatchInclusionProofNodeAddresses(t *testing.T) {
+	for _, testCase := range batchPathTests {
+		path, err := CalcBatchInclusionProofNodeAddresses(testCase.treeSize, testCase.leafIndices, testCase.treeSize, 64)
+		if err != nil {
+			t.Fatalf("unexpected error calculating batch path %v: %v", testCase, err)
+		}
+
+		comparePaths(t, fmt.Sprintf("b(%v,%d)", testCase.leafIndices, testCase.treeSize), path, testCase.expectedPath)
+	}
+}
+
+func TestCalcBatchInclusionProofNodeAddressesBadInputs(t *testing.T) {
+	for _, testCase := range batchPathTestsBad {
+		if _, err := CalcBatchInclusionProofNodeAddresses(testCase.treeSize, testCase.leafIndices, testCase.treeSize, 64); err == nil {
+			t.Errorf("incorrectly accepted bad params: %v", testCase)
+		}
+	}
+	if _, err := CalcBatchInclusionProofNodeAddresses(7, []int64{3}, 7, 0); err == nil {
+		t.Error("incorrectly accepted zero maxBitLen")
+	}
+}
+
+// A batch of a single leaf must have the same path as the plain inclusion
+// proof, including when rehashing is needed for earlier snapshots.
+func TestCalcBatchInclusionProofNodeAddressesSingleLeaf(t *testing.T) {
+	for ts := int64(1); ts < 20; ts++ {
+		for ss := int64(1); ss <= ts; ss++ {
+			for i := int64(0); i < ss; i++ {
+				want, err := CalcInclusionProofNodeAddresses(ss, i, ts, 64)
+				if err != nil {
+					t.Fatalf("CalcInclusionProofNodeAddresses(%d, %d, %d): %v", ss, i, ts, err)
+				}
+				got, err := CalcBatchInclusionProofNodeAddresses(ss, []int64{i}, ts, 64)
+				if err != nil {
+					t.Fatalf("CalcBatchInclusionProofNodeAddresses(%d, [%d], %d): %v", ss, i, ts, err)
+				}
+				// Paths are ordered bottom up, and batch paths left to right.
+				comparePaths(t, fmt.Sprintf("b(%d,%d,%d)", ss, i, ts), sortedFetches(got), sortedFetches(want))
+			}
+		}
+	}
+}
+
 func TestCalcConsistencyProofNodeAddresses(t *testing.T) {
 	for _, testCase := range consistencyTests {
 		proof, err := CalcConsistencyProofNodeAddresses(testCase.priorTreeSize, testCase.treeSize, testCase.treeSize, 64)
@@ -313,6 +405,13 @@ func comparePaths(t *testing.T, desc string, got, expected []NodeFetch) {
 	}
 }
 
+// sortedFetches returns a copy of fetches sorted by node ID.
+func sortedFetches(fetches []NodeFetch) []NodeFetch {
+	ret := append([]NodeFetch(nil), fetches...)
+	sort.Slice(ret, func(i, j int) bool { return ret[i].NodeID.String() < ret[j].NodeID.String() })
+	return ret
+}
+
 func TestLastNodeWritten(t *testing.T) {
 	for _, testCase := range lastNodeWrittenVec {
 		str := ""
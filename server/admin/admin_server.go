@@ -221,6 +221,12 @@ func applyUpdateMask(from, to *trillian.Tree, mask *field_mask.FieldMask) error
 			to.MaxRootDuration = from.MaxRootDuration
 		case "private_key":
 			to.PrivateKey = from.PrivateKey
+		case "sequencing_config":
+			to.SequencingConfig = from.SequencingConfig
+		case "retention_policy":
+			to.RetentionPolicy = from.RetentionPolicy
+		case "map_retention_policy":
+			to.MapRetentionPolicy = from.MapRetentionPolicy
 		default:
 			return status.Errorf(codes.InvalidArgument, "invalid update_mask path: %q", path)
 		}
@@ -640,9 +640,23 @@ func TestServer_UpdateTree(t *testing.T) {
 		StorageSettings: settings,
 		MaxRootDuration: ptypes.DurationProto(2 * time.Nanosecond),
 		PrivateKey:      ttestonly.MustMarshalAny(t, &empty.Empty{}),
+		SequencingConfig: &trillian.SequencingConfig{
+			Paused:          true,
+			BatchSize:       50,
+			GuardWindow:     ptypes.DurationProto(time.Second),
+			MinRootInterval: ptypes.DurationProto(time.Minute),
+		},
+		RetentionPolicy: &trillian.RetentionPolicy{
+			MaxEntries: 1000,
+			MaxAge:     ptypes.DurationProto(time.Hour),
+		},
+		MapRetentionPolicy: &trillian.MapRetentionPolicy{
+			MaxRevisions: 100,
+			MaxAge:       ptypes.DurationProto(time.Hour),
+		},
 	}
 	successMask := &field_mask.FieldMask{
-		Paths: []string{"tree_state", "display_name", "description", "storage_settings", "max_root_duration", "private_key"},
+		Paths: []string{"tree_state", "display_name", "description", "storage_settings", "max_root_duration", "private_key", "sequencing_config", "retention_policy", "map_retention_policy"},
 	}
 
 	successWant := proto.Clone(existingTree).(*trillian.Tree)
@@ -652,6 +666,9 @@ func TestServer_UpdateTree(t *testing.T) {
 	successWant.StorageSettings = successTree.StorageSettings
 	successWant.PrivateKey = nil // redacted on responses
 	successWant.MaxRootDuration = successTree.MaxRootDuration
+	successWant.SequencingConfig = successTree.SequencingConfig
+	successWant.RetentionPolicy = successTree.RetentionPolicy
+	successWant.MapRetentionPolicy = successTree.MapRetentionPolicy
 
 	tests := []struct {
 		desc                           string
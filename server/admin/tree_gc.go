@@ -31,6 +31,7 @@ import (
 
 const (
 	deleteErrReason        = "delete_error"
+	purgeErrReason         = "purge_error"
 	timestampParseErrReson = "timestamp_parse_error"
 )
 
@@ -38,7 +39,13 @@ var (
 	timeNow   = time.Now
 	timeSleep = time.Sleep
 
+	// PurgeBatchSize is the maximum number of records of tree data deleted in
+	// a single transaction while hard-deleting a tree.
+	PurgeBatchSize = 1000
+
 	hardDeleteCounter monitoring.Counter
+	purgedCounter     monitoring.Counter
+	pendingGauge      monitoring.Gauge
 	metricsOnce       sync.Once
 )
 
@@ -54,6 +61,10 @@ func incHardDeleteCounter(treeID int64, success bool, reason string) {
 //
 // DeletedTreeGC performs the transition from soft to hard deletion. Trees that have been deleted
 // for at least DeletedThreshold are eligible for garbage collection.
+//
+// The data of a tree is purged in batches of PurgeBatchSize records, each in its own
+// transaction, before the tree itself is deleted. A tree stays soft deleted until all of its
+// data is gone, so an interrupted hard deletion is resumed by the next sweep.
 type DeletedTreeGC struct {
 	// admin is the storage.AdminStorage interface.
 	admin storage.AdminStorage
@@ -79,6 +90,8 @@ func NewDeletedTreeGC(admin storage.AdminStorage, threshold, minRunInterval time
 			mf = monitoring.InertMetricFactory{}
 		}
 		hardDeleteCounter = mf.NewCounter("tree_hard_delete_counter", "Counter of hard-deleted trees", monitoring.TreeIDLabel, "success", "reason")
+		purgedCounter = mf.NewCounter("tree_purged_records_counter", "Counter of records purged from hard-deleted trees", monitoring.TreeIDLabel)
+		pendingGauge = mf.NewGauge("tree_hard_delete_pending", "Number of trees eligible for hard deletion which weren't processed yet by the current sweep")
 	})
 	return gc
 }
@@ -114,7 +127,8 @@ func (gc *DeletedTreeGC) RunOnce(ctx context.Context) (int, error) {
 	now := timeNow()
 
 	// List and delete trees in separate transactions. Hard-deletes may cascade to a lot of data, so
-	// each delete should be in its own transaction as well.
+	// each delete should be in its own transaction as well, preceded by purging the tree's data in
+	// batches.
 	// It's OK to list and delete separately because HardDelete does its own state checking, plus
 	// deleted trees are unlikely to change, specially those deleted for a while.
 	trees, err := storage.ListTrees(ctx, gc.admin, true /* includeDeleted */)
@@ -122,7 +136,7 @@ func (gc *DeletedTreeGC) RunOnce(ctx context.Context) (int, error) {
 		return 0, fmt.Errorf("error listing trees: %v", err)
 	}
 
-	count := 0
+	var eligible []int64
 	var errs []error
 	for _, tree := range trees {
 		if !tree.Deleted {
@@ -138,17 +152,29 @@ func (gc *DeletedTreeGC) RunOnce(ctx context.Context) (int, error) {
 		if durationSinceDelete <= gc.deleteThreshold {
 			continue
 		}
-
 		glog.Infof("DeletedTreeGC.RunOnce: Hard-deleting tree %v after %v", tree.TreeId, durationSinceDelete)
-		if err := storage.HardDeleteTree(ctx, gc.admin, tree.TreeId); err != nil {
-			errs = append(errs, fmt.Errorf("error hard-deleting tree %v: %v", tree.TreeId, err))
-			incHardDeleteCounter(tree.TreeId, false, deleteErrReason)
+		eligible = append(eligible, tree.TreeId)
+	}
+
+	count := 0
+	for i, treeID := range eligible {
+		pendingGauge.Set(float64(len(eligible) - i))
+
+		if err := gc.purgeTreeData(ctx, treeID); err != nil {
+			errs = append(errs, fmt.Errorf("error purging data of tree %v: %v", treeID, err))
+			incHardDeleteCounter(treeID, false, purgeErrReason)
+			continue
+		}
+		if err := storage.HardDeleteTree(ctx, gc.admin, treeID); err != nil {
+			errs = append(errs, fmt.Errorf("error hard-deleting tree %v: %v", treeID, err))
+			incHardDeleteCounter(treeID, false, deleteErrReason)
 			continue
 		}
 
 		count++
-		incHardDeleteCounter(tree.TreeId, true, "")
+		incHardDeleteCounter(treeID, true, "")
 	}
+	pendingGauge.Set(0)
 
 	if len(errs) == 0 {
 		return count, nil
@@ -162,3 +188,28 @@ func (gc *DeletedTreeGC) RunOnce(ctx context.Context) (int, error) {
 	}
 	return count, errors.New(buf.String())
 }
+
+// purgeTreeData deletes all data of the specified tree in batches of PurgeBatchSize records.
+func (gc *DeletedTreeGC) purgeTreeData(ctx context.Context, treeID int64) error {
+	label := fmt.Sprint(treeID)
+	total := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		purged, err := storage.PurgeTreeData(ctx, gc.admin, treeID, PurgeBatchSize)
+		if err != nil {
+			return err
+		}
+		purgedCounter.Add(float64(purged), label)
+		total += purged
+		if purged < PurgeBatchSize {
+			break
+		}
+		glog.V(1).Infof("DeletedTreeGC: purged %v records of tree %v so far", total, treeID)
+	}
+	if total > 0 {
+		glog.Infof("DeletedTreeGC: purged %v records of tree %v", total, treeID)
+	}
+	return nil
+}
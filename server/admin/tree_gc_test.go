@@ -49,22 +49,27 @@ func TestDeletedTreeGC_Run(t *testing.T) {
 
 	listTX1 := storage.NewMockReadOnlyAdminTX(ctrl)
 	listTX2 := storage.NewMockReadOnlyAdminTX(ctrl)
+	purgeTX1 := storage.NewMockAdminTX(ctrl)
 	deleteTX1 := storage.NewMockAdminTX(ctrl)
 	as := &testonly.FakeAdminStorage{
-		TX:         []storage.AdminTX{deleteTX1},
+		TX:         []storage.AdminTX{purgeTX1, deleteTX1},
 		ReadOnlyTX: []storage.ReadOnlyAdminTX{listTX1, listTX2},
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
 	// Sequence Snapshot()/ReadWriteTransaction() calls.
-	// * 1st loop: Snapshot()/ListTrees() followed by ReadWriteTransaction()/HardDeleteTree()
+	// * 1st loop: Snapshot()/ListTrees() followed by ReadWriteTransaction()/PurgeTreeData() and
+	//   ReadWriteTransaction()/HardDeleteTree()
 	// * 2nd loop: Snapshot()/ListTrees() only.
 
 	// 1st loop
 	listTX1.EXPECT().ListTrees(gomock.Any(), true /* includeDeleted */).Return([]*trillian.Tree{tree1}, nil)
 	listTX1.EXPECT().Close().Return(nil)
 	listTX1.EXPECT().Commit().Return(nil)
+	purgeTX1.EXPECT().PurgeTreeData(gomock.Any(), tree1.TreeId, PurgeBatchSize).Return(0, nil)
+	purgeTX1.EXPECT().Close().Return(nil)
+	purgeTX1.EXPECT().Commit().Return(nil)
 	deleteTX1.EXPECT().HardDeleteTree(gomock.Any(), tree1.TreeId).Return(nil)
 	deleteTX1.EXPECT().Close().Return(nil)
 	deleteTX1.EXPECT().Commit().Return(nil)
@@ -167,6 +172,12 @@ func TestDeletedTreeGC_RunOnce(t *testing.T) {
 		listTX.EXPECT().Commit().Return(nil)
 
 		for _, id := range test.wantDeleted {
+			purgeTX := storage.NewMockAdminTX(ctrl)
+			purgeTX.EXPECT().PurgeTreeData(gomock.Any(), id, PurgeBatchSize).Return(0, nil)
+			purgeTX.EXPECT().Close().Return(nil)
+			purgeTX.EXPECT().Commit().Return(nil)
+			as.TX = append(as.TX, purgeTX)
+
 			deleteTX := storage.NewMockAdminTX(ctrl)
 			deleteTX.EXPECT().HardDeleteTree(gomock.Any(), id).Return(nil)
 			deleteTX.EXPECT().Close().Return(nil)
@@ -190,10 +201,11 @@ type listTreesSpec struct {
 	trees                           []*trillian.Tree
 }
 
-// hardDeleteTreeSpec specifies all parameters required to mock a HardDeleteTree TX call.
+// hardDeleteTreeSpec specifies all parameters required to mock the PurgeTreeData and
+// HardDeleteTree TX calls of a tree.
 type hardDeleteTreeSpec struct {
-	beginErr, deleteErr, commitErr error
-	treeID                         int64
+	beginErr, purgeErr, deleteErr, commitErr error
+	treeID                                   int64
 }
 
 func TestDeletedTreeGC_RunOnceErrors(t *testing.T) {
@@ -274,6 +286,18 @@ func TestDeletedTreeGC_RunOnceErrors(t *testing.T) {
 			wantCount: 1,
 			wantErrs:  []string{"begin err"},
 		},
+		{
+			desc: "purgeErr",
+			listTrees: listTreesSpec{
+				trees: []*trillian.Tree{logTree1, logTree2},
+			},
+			hardDeleteTree: []hardDeleteTreeSpec{
+				{purgeErr: errors.New("cannot purge logTree1"), treeID: logTree1.TreeId},
+				{treeID: logTree2.TreeId},
+			},
+			wantCount: 1,
+			wantErrs:  []string{"cannot purge logTree1"},
+		},
 		{
 			desc: "deleteErr",
 			listTrees: listTreesSpec{
@@ -335,13 +359,18 @@ func TestDeletedTreeGC_RunOnceErrors(t *testing.T) {
 			for _, hardDeleteTree := range test.hardDeleteTree {
 				deleteTX := storage.NewMockAdminTX(ctrl)
 
-				if hardDeleteTree.beginErr != nil {
+				// The same TX is used for both PurgeTreeData and HardDeleteTree, unless purging fails.
+				switch {
+				case hardDeleteTree.beginErr != nil:
 					as.TXErr = append(as.TXErr, hardDeleteTree.beginErr)
-				} else {
+				case hardDeleteTree.purgeErr != nil || hardDeleteTree.commitErr != nil:
 					as.TX = append(as.TX, deleteTX)
+				default:
+					as.TX = append(as.TX, deleteTX, deleteTX)
 				}
 
 				if hardDeleteTree.treeID != 0 {
+					deleteTX.EXPECT().PurgeTreeData(gomock.Any(), hardDeleteTree.treeID, PurgeBatchSize).AnyTimes().Return(0, hardDeleteTree.purgeErr)
 					deleteTX.EXPECT().HardDeleteTree(gomock.Any(), hardDeleteTree.treeID).AnyTimes().Return(hardDeleteTree.deleteErr)
 				}
 				deleteTX.EXPECT().Close().AnyTimes().Return(nil)
@@ -364,3 +393,70 @@ func TestDeletedTreeGC_RunOnceErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestDeletedTreeGC_RunOncePurgesInBatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tree := proto.Clone(testonly.LogTree).(*trillian.Tree)
+	tree.TreeId = 1
+	tree.Deleted = true
+	tree.DeleteTime, _ = ptypes.TimestampProto(time.Date(2017, 9, 21, 10, 0, 0, 0, time.UTC))
+
+	defer func(f func() time.Time, batchSize int) {
+		timeNow = f
+		PurgeBatchSize = batchSize
+	}(timeNow, PurgeBatchSize)
+	timeNow = func() time.Time { return time.Date(2017, 9, 22, 10, 0, 0, 0, time.UTC) }
+	PurgeBatchSize = 10
+
+	// The runs are made in order against the same tree.
+	for _, run := range []struct {
+		desc string
+		// purged holds the results of consecutive PurgeTreeData calls. The last call fails if
+		// purgeErr is set, otherwise it's followed by HardDeleteTree.
+		purged    []int
+		purgeErr  error
+		wantCount int
+	}{
+		{desc: "interrupted", purged: []int{10, 10, 0}, purgeErr: errors.New("purge err")},
+		{desc: "resumed", purged: []int{10, 3}, wantCount: 1},
+	} {
+		listTX := storage.NewMockReadOnlyAdminTX(ctrl)
+		listTX.EXPECT().ListTrees(gomock.Any(), true /* includeDeleted */).Return([]*trillian.Tree{tree}, nil)
+		listTX.EXPECT().Close().Return(nil)
+		listTX.EXPECT().Commit().Return(nil)
+		as := &testonly.FakeAdminStorage{ReadOnlyTX: []storage.ReadOnlyAdminTX{listTX}}
+
+		for i, purged := range run.purged {
+			tx := storage.NewMockAdminTX(ctrl)
+			tx.EXPECT().Close().Return(nil)
+			if i == len(run.purged)-1 && run.purgeErr != nil {
+				tx.EXPECT().PurgeTreeData(gomock.Any(), tree.TreeId, PurgeBatchSize).Return(purged, run.purgeErr)
+			} else {
+				tx.EXPECT().PurgeTreeData(gomock.Any(), tree.TreeId, PurgeBatchSize).Return(purged, nil)
+				tx.EXPECT().Commit().Return(nil)
+			}
+			as.TX = append(as.TX, tx)
+		}
+		if run.purgeErr == nil {
+			deleteTX := storage.NewMockAdminTX(ctrl)
+			deleteTX.EXPECT().HardDeleteTree(gomock.Any(), tree.TreeId).Return(nil)
+			deleteTX.EXPECT().Close().Return(nil)
+			deleteTX.EXPECT().Commit().Return(nil)
+			as.TX = append(as.TX, deleteTX)
+		}
+
+		gc := NewDeletedTreeGC(as, time.Hour /* threshold */, time.Second /* minRunInterval */, nil /* mf */)
+		count, err := gc.RunOnce(context.Background())
+		if gotErr, wantErr := err != nil, run.purgeErr != nil; gotErr != wantErr {
+			t.Errorf("%v: RunOnce() returned err = %v, wantErr = %v", run.desc, err, wantErr)
+		}
+		if count != run.wantCount {
+			t.Errorf("%v: RunOnce() = %v, want = %v", run.desc, count, run.wantCount)
+		}
+		if len(as.TX) != 0 {
+			t.Errorf("%v: %d transactions left unused", run.desc, len(as.TX))
+		}
+	}
+}
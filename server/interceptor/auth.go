@@ -0,0 +1,212 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/google/trillian/trees"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// AnyPrincipal matches every principal in an ACL, including anonymous ones.
+const AnyPrincipal = "*"
+
+// Permission is a level of access to trees. Each level includes the ones
+// below it, i.e. Admin includes Write, which includes Read.
+type Permission int
+
+// Permission values, in increasing order of access.
+const (
+	// None grants no access.
+	None Permission = iota
+	// Read allows querying trees.
+	Read
+	// Write allows adding data to trees.
+	Write
+	// Admin allows initializing, creating, updating and deleting trees.
+	Admin
+)
+
+var permissionNames = []string{"none", "read", "write", "admin"}
+
+// String returns the lower-case name of p, as used in ACL files.
+func (p Permission) String() string {
+	if p < None || int(p) >= len(permissionNames) {
+		return fmt.Sprintf("Permission(%d)", int(p))
+	}
+	return permissionNames[p]
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (p Permission) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (p *Permission) UnmarshalText(text []byte) error {
+	for i, name := range permissionNames {
+		if strings.EqualFold(string(text), name) {
+			*p = Permission(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown permission %q", text)
+}
+
+// PermissionFor returns the permission needed to perform operations of the
+// given type. Unknown operation types need Admin permission.
+func PermissionFor(op trees.OpType) Permission {
+	switch op {
+	case trees.Query:
+		return Read
+	case trees.QueueLog, trees.SequenceLog, trees.UpdateMap:
+		return Write
+	default:
+		return Admin
+	}
+}
+
+// Authenticator identifies the principal making an RPC.
+type Authenticator interface {
+	// Authenticate returns the principal that made the RPC carried by ctx. An
+	// empty principal denotes an anonymous caller. An error is returned if the
+	// caller presented credentials that couldn't be verified.
+	Authenticate(ctx context.Context) (string, error)
+}
+
+// Authorizer decides whether principals may perform operations on trees.
+type Authorizer interface {
+	// Authorize returns nil if principal may perform an operation of type op
+	// on the tree with the given ID. A treeID of zero denotes operations that
+	// don't address an existing tree, such as CreateTree or ListTrees.
+	Authorize(ctx context.Context, principal string, treeID int64, op trees.OpType) error
+}
+
+// AuthConfig is the JSON representation of the authentication and
+// authorization settings of a Trillian server.
+type AuthConfig struct {
+	// Tokens maps bearer tokens to the principals they identify.
+	Tokens map[string]string `json:"tokens"`
+	// ACL holds the permissions of principals.
+	ACL ACL `json:"acl"`
+}
+
+// LoadAuthConfig reads an AuthConfig from a JSON file.
+func LoadAuthConfig(path string) (*AuthConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &AuthConfig{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse auth config %q: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// ACL is an Authorizer which grants principals permissions on all trees, or
+// on individual trees. A principal's permission on a tree is the highest one
+// granted to it, or to AnyPrincipal, either globally or for that tree.
+type ACL struct {
+	// Global holds the permissions of principals on all trees. It's also the
+	// only place where the permissions for operations that don't address an
+	// existing tree are looked up.
+	Global map[string]Permission `json:"global"`
+	// Trees holds the permissions of principals on individual trees, keyed by
+	// tree ID.
+	Trees map[int64]map[string]Permission `json:"trees"`
+}
+
+// Authorize implements Authorizer.
+func (a *ACL) Authorize(ctx context.Context, principal string, treeID int64, op trees.OpType) error {
+	want := PermissionFor(op)
+	if got := a.permission(principal, treeID); got < want {
+		who := principal
+		if who == "" {
+			who = "anonymous caller"
+		}
+		return status.Errorf(codes.PermissionDenied, "%v needs %v permission on tree %v for %v operations, has %v", who, want, treeID, op, got)
+	}
+	return nil
+}
+
+func (a *ACL) permission(principal string, treeID int64) Permission {
+	perm := None
+	for _, perms := range []map[string]Permission{a.Global, a.Trees[treeID]} {
+		for _, p := range []string{principal, AnyPrincipal} {
+			if p == "" {
+				continue
+			}
+			if got := perms[p]; got > perm {
+				perm = got
+			}
+		}
+	}
+	return perm
+}
+
+// NewAuthenticator returns an Authenticator which identifies callers by their
+// TLS client certificate, if they presented one that was verified by the
+// server, or else by a bearer token found in the "authorization" metadata.
+// Certificates identify the principal named by their subject's common name,
+// tokens identify the principal they are mapped to. Callers presenting
+// neither are anonymous.
+func NewAuthenticator(tokens map[string]string) Authenticator {
+	return &certOrTokenAuthenticator{tokens: tokens}
+}
+
+type certOrTokenAuthenticator struct {
+	tokens map[string]string
+}
+
+func (a *certOrTokenAuthenticator) Authenticate(ctx context.Context) (string, error) {
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(info.State.VerifiedChains) > 0 {
+			cert := info.State.VerifiedChains[0][0]
+			if cert.Subject.CommonName == "" {
+				return "", status.Error(codes.Unauthenticated, "client certificate has no subject common name")
+			}
+			return cert.Subject.CommonName, nil
+		}
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	switch len(values) {
+	case 0:
+		return "", nil
+	case 1:
+	default:
+		return "", status.Errorf(codes.Unauthenticated, "got %d authorization values, want 1", len(values))
+	}
+	const prefix = "bearer "
+	if len(values[0]) < len(prefix) || !strings.EqualFold(values[0][:len(prefix)], prefix) {
+		return "", status.Error(codes.Unauthenticated, "unsupported authorization scheme, want bearer token")
+	}
+	principal, ok := a.tokens[strings.TrimSpace(values[0][len(prefix):])]
+	if !ok || principal == "" {
+		return "", status.Error(codes.Unauthenticated, "unknown bearer token")
+	}
+	return principal, nil
+}
@@ -0,0 +1,398 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+	"github.com/google/trillian/quota"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/testonly"
+	"github.com/google/trillian/trees"
+	"github.com/kylelemons/godebug/pretty"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestPermission_UnmarshalText(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    Permission
+		wantErr bool
+	}{
+		{text: "none", want: None},
+		{text: "read", want: Read},
+		{text: "Write", want: Write},
+		{text: "ADMIN", want: Admin},
+		{text: "", wantErr: true},
+		{text: "superuser", wantErr: true},
+	}
+	for _, test := range tests {
+		var got Permission
+		err := got.UnmarshalText([]byte(test.text))
+		if hasErr := err != nil; hasErr != test.wantErr {
+			t.Errorf("UnmarshalText(%q) returned err = %v, wantErr = %v", test.text, err, test.wantErr)
+			continue
+		} else if hasErr {
+			continue
+		}
+		if got != test.want {
+			t.Errorf("UnmarshalText(%q) = %v, want = %v", test.text, got, test.want)
+		}
+		if text, _ := got.MarshalText(); string(text) != test.want.String() {
+			t.Errorf("MarshalText() = %q, want = %q", text, test.want.String())
+		}
+	}
+}
+
+func TestLoadAuthConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "authconfig")
+	if err != nil {
+		t.Fatalf("TempDir() returned err = %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "auth.json")
+	config := `{
+  "tokens": {"s3cr3t": "alice"},
+  "acl": {
+    "global": {"admin-bot": "admin", "*": "read"},
+    "trees": {"10": {"alice": "write"}}
+  }
+}`
+	if err := ioutil.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatalf("WriteFile() returned err = %v", err)
+	}
+
+	got, err := LoadAuthConfig(path)
+	if err != nil {
+		t.Fatalf("LoadAuthConfig() returned err = %v", err)
+	}
+	want := &AuthConfig{
+		Tokens: map[string]string{"s3cr3t": "alice"},
+		ACL: ACL{
+			Global: map[string]Permission{"admin-bot": Admin, AnyPrincipal: Read},
+			Trees:  map[int64]map[string]Permission{10: {"alice": Write}},
+		},
+	}
+	if diff := pretty.Compare(got, want); diff != "" {
+		t.Errorf("LoadAuthConfig() diff (-got +want):\n%v", diff)
+	}
+
+	if err := ioutil.WriteFile(path, []byte(`{"acl": {"global": {"alice": "root"}}}`), 0600); err != nil {
+		t.Fatalf("WriteFile() returned err = %v", err)
+	}
+	if _, err := LoadAuthConfig(path); err == nil {
+		t.Error("LoadAuthConfig() with unknown permission returned err = nil")
+	}
+	if _, err := LoadAuthConfig(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("LoadAuthConfig() with missing file returned err = nil")
+	}
+}
+
+func TestACL_Authorize(t *testing.T) {
+	acl := &ACL{
+		Global: map[string]Permission{
+			"admin-bot": Admin,
+			"reader":    Read,
+		},
+		Trees: map[int64]map[string]Permission{
+			10: {"alice": Write, AnyPrincipal: Read},
+			11: {"reader": Admin, "bob": None},
+		},
+	}
+
+	tests := []struct {
+		desc      string
+		principal string
+		treeID    int64
+		op        trees.OpType
+		wantErr   bool
+	}{
+		{desc: "globalAdmin", principal: "admin-bot", treeID: 10, op: trees.Admin},
+		{desc: "globalAdminCreate", principal: "admin-bot", treeID: 0, op: trees.Admin},
+		{desc: "globalReader", principal: "reader", treeID: 12, op: trees.Query},
+		{desc: "globalReaderWrite", principal: "reader", treeID: 12, op: trees.QueueLog, wantErr: true},
+		{desc: "globalReaderCreate", principal: "reader", treeID: 0, op: trees.Admin, wantErr: true},
+		{desc: "treeWriter", principal: "alice", treeID: 10, op: trees.QueueLog},
+		{desc: "treeWriterSequence", principal: "alice", treeID: 10, op: trees.SequenceLog},
+		{desc: "treeWriterAdmin", principal: "alice", treeID: 10, op: trees.Admin, wantErr: true},
+		{desc: "treeWriterOtherTree", principal: "alice", treeID: 11, op: trees.Query, wantErr: true},
+		{desc: "perTreeUpgrade", principal: "reader", treeID: 11, op: trees.Admin},
+		{desc: "perTreeNoneDoesNotDowngrade", principal: "admin-bot", treeID: 11, op: trees.Admin},
+		{desc: "anyPrincipal", principal: "carol", treeID: 10, op: trees.Query},
+		{desc: "anyPrincipalWrite", principal: "carol", treeID: 10, op: trees.QueueLog, wantErr: true},
+		{desc: "anonymous", principal: "", treeID: 10, op: trees.Query},
+		{desc: "anonymousOtherTree", principal: "", treeID: 12, op: trees.Query, wantErr: true},
+		{desc: "unknownOp", principal: "alice", treeID: 10, op: trees.OpType(-1), wantErr: true},
+	}
+
+	ctx := context.Background()
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			err := acl.Authorize(ctx, test.principal, test.treeID, test.op)
+			if hasErr := err != nil; hasErr != test.wantErr {
+				t.Fatalf("Authorize() returned err = %v, wantErr = %v", err, test.wantErr)
+			}
+			if err != nil && status.Code(err) != codes.PermissionDenied {
+				t.Errorf("Authorize() returned err = %v, want code %v", err, codes.PermissionDenied)
+			}
+		})
+	}
+}
+
+func TestAuthenticator(t *testing.T) {
+	authn := NewAuthenticator(map[string]string{"s3cr3t": "alice"})
+
+	withCert := func(cn string) context.Context {
+		cert := &x509.Certificate{Subject: pkix.Name{CommonName: cn}}
+		return peer.NewContext(context.Background(), &peer.Peer{
+			AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+				VerifiedChains: [][]*x509.Certificate{{cert}},
+			}},
+		})
+	}
+	withAuthorization := func(ctx context.Context, values ...string) context.Context {
+		md := metadata.MD{}
+		md.Append("authorization", values...)
+		return metadata.NewIncomingContext(ctx, md)
+	}
+	unverified := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "mallory"}}},
+		}},
+	})
+
+	tests := []struct {
+		desc     string
+		ctx      context.Context
+		want     string
+		wantCode codes.Code
+	}{
+		{desc: "anonymous", ctx: context.Background(), want: ""},
+		{desc: "cert", ctx: withCert("bob"), want: "bob"},
+		{desc: "certTakesPrecedence", ctx: withAuthorization(withCert("bob"), "Bearer s3cr3t"), want: "bob"},
+		{desc: "certWithoutCommonName", ctx: withCert(""), wantCode: codes.Unauthenticated},
+		{desc: "unverifiedCert", ctx: unverified, want: ""},
+		{desc: "token", ctx: withAuthorization(context.Background(), "Bearer s3cr3t"), want: "alice"},
+		{desc: "tokenLowerCase", ctx: withAuthorization(context.Background(), "bearer s3cr3t"), want: "alice"},
+		{desc: "unknownToken", ctx: withAuthorization(context.Background(), "Bearer guess"), wantCode: codes.Unauthenticated},
+		{desc: "wrongScheme", ctx: withAuthorization(context.Background(), "Basic YWxpY2U6cHc="), wantCode: codes.Unauthenticated},
+		{desc: "multipleTokens", ctx: withAuthorization(context.Background(), "Bearer s3cr3t", "Bearer s3cr3t"), wantCode: codes.Unauthenticated},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			got, err := authn.Authenticate(test.ctx)
+			if code := status.Code(err); code != test.wantCode {
+				t.Fatalf("Authenticate() returned err = %v, want code %v", err, test.wantCode)
+			}
+			if got != test.want {
+				t.Errorf("Authenticate() = %q, want = %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestTrillianInterceptor_AuthInterception(t *testing.T) {
+	logTree := proto.Clone(testonly.LogTree).(*trillian.Tree)
+	logTree.TreeId = 10
+
+	authn := NewAuthenticator(map[string]string{"alice-token": "alice", "bob-token": "bob"})
+	authz := &ACL{
+		Global: map[string]Permission{"bob": Admin},
+		Trees:  map[int64]map[string]Permission{logTree.TreeId: {"alice": Write}},
+	}
+
+	withToken := func(token string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	}
+
+	tests := []struct {
+		desc     string
+		ctx      context.Context
+		method   string
+		req      interface{}
+		wantCode codes.Code
+	}{
+		{
+			desc:   "writerQueue",
+			ctx:    withToken("alice-token"),
+			method: "/trillian.TrillianLog/QueueLeaf",
+			req:    &trillian.QueueLeafRequest{LogId: logTree.TreeId},
+		},
+		{
+			desc:     "writerInitLog",
+			ctx:      withToken("alice-token"),
+			method:   "/trillian.TrillianLog/InitLog",
+			req:      &trillian.InitLogRequest{LogId: logTree.TreeId},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			desc:     "writerCreateTree",
+			ctx:      withToken("alice-token"),
+			method:   "/trillian.TrillianAdmin/CreateTree",
+			req:      &trillian.CreateTreeRequest{Tree: logTree},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			desc:   "adminCreateTree",
+			ctx:    withToken("bob-token"),
+			method: "/trillian.TrillianAdmin/CreateTree",
+			req:    &trillian.CreateTreeRequest{Tree: logTree},
+		},
+		{
+			desc:   "adminListTrees",
+			ctx:    withToken("bob-token"),
+			method: "/trillian.TrillianAdmin/ListTrees",
+			req:    &trillian.ListTreesRequest{},
+		},
+		{
+			desc:     "anonymousQuery",
+			ctx:      context.Background(),
+			method:   "/trillian.TrillianLog/GetLatestSignedLogRoot",
+			req:      &trillian.GetLatestSignedLogRootRequest{LogId: logTree.TreeId},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			desc:     "unknownToken",
+			ctx:      withToken("mallory-token"),
+			method:   "/trillian.TrillianLog/GetLatestSignedLogRoot",
+			req:      &trillian.GetLatestSignedLogRootRequest{LogId: logTree.TreeId},
+			wantCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			admin := storage.NewMockAdminStorage(ctrl)
+			adminTX := storage.NewMockReadOnlyAdminTX(ctrl)
+			admin.EXPECT().Snapshot(gomock.Any()).AnyTimes().Return(adminTX, nil)
+			adminTX.EXPECT().GetTree(gomock.Any(), logTree.TreeId).AnyTimes().Return(logTree, nil)
+			adminTX.EXPECT().Close().AnyTimes().Return(nil)
+			adminTX.EXPECT().Commit().AnyTimes().Return(nil)
+
+			intercept := New(admin, quota.Noop(), false /* quotaDryRun */, nil /* mf */).WithAuth(authn, authz)
+			handler := &fakeHandler{resp: "handler response"}
+
+			_, err := intercept.UnaryInterceptor(test.ctx, test.req,
+				&grpc.UnaryServerInfo{FullMethod: test.method},
+				handler.run)
+			if code := status.Code(err); code != test.wantCode {
+				t.Fatalf("UnaryInterceptor() returned err = %v, want code %v", err, test.wantCode)
+			}
+			if handler.called != (test.wantCode == codes.OK) {
+				t.Errorf("handler.called = %v, want = %v", handler.called, test.wantCode == codes.OK)
+			}
+		})
+	}
+}
+
+func TestTrillianInterceptor_StreamAuthInterception(t *testing.T) {
+	logTree := proto.Clone(testonly.LogTree).(*trillian.Tree)
+	logTree.TreeId = 10
+
+	authn := NewAuthenticator(map[string]string{"alice-token": "alice", "bob-token": "bob"})
+	authz := &ACL{Trees: map[int64]map[string]Permission{logTree.TreeId: {"alice": Read}}}
+
+	withToken := func(token string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	}
+
+	for _, test := range []struct {
+		desc     string
+		ctx      context.Context
+		wantCode codes.Code
+	}{
+		{desc: "reader", ctx: withToken("alice-token")},
+		{desc: "otherUser", ctx: withToken("bob-token"), wantCode: codes.PermissionDenied},
+		{desc: "anonymous", ctx: context.Background(), wantCode: codes.PermissionDenied},
+		{desc: "unknownToken", ctx: withToken("mallory-token"), wantCode: codes.Unauthenticated},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			admin := storage.NewMockAdminStorage(ctrl)
+			adminTX := storage.NewMockReadOnlyAdminTX(ctrl)
+			admin.EXPECT().Snapshot(gomock.Any()).AnyTimes().Return(adminTX, nil)
+			adminTX.EXPECT().GetTree(gomock.Any(), logTree.TreeId).AnyTimes().Return(logTree, nil)
+			adminTX.EXPECT().Close().AnyTimes().Return(nil)
+			adminTX.EXPECT().Commit().AnyTimes().Return(nil)
+
+			intercept := New(admin, quota.Noop(), false /* quotaDryRun */, nil /* mf */).WithAuth(authn, authz)
+			stream := &fakeServerStream{ctx: test.ctx, req: &trillian.WatchLeavesRequest{LogId: logTree.TreeId}}
+			handler := func(srv interface{}, ss grpc.ServerStream) error {
+				if err := ss.RecvMsg(&trillian.WatchLeavesRequest{}); err != nil {
+					return err
+				}
+				return ss.SendMsg(&trillian.WatchLeavesResponse{})
+			}
+
+			err := intercept.StreamInterceptor(nil, stream,
+				&grpc.StreamServerInfo{FullMethod: "/trillian.TrillianLog/WatchLeaves", IsServerStream: true},
+				handler)
+			if code := status.Code(err); code != test.wantCode {
+				t.Fatalf("StreamInterceptor() returned err = %v, want code %v", err, test.wantCode)
+			}
+			if sent := len(stream.sent) > 0; sent != (test.wantCode == codes.OK) {
+				t.Errorf("StreamInterceptor() sent %d responses, want sent: %v", len(stream.sent), test.wantCode == codes.OK)
+			}
+		})
+	}
+}
+
+func TestTrillianInterceptor_AuthError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	admin := storage.NewMockAdminStorage(ctrl)
+
+	wantErr := errors.New("authorizer unavailable")
+	intercept := New(admin, quota.Noop(), false /* quotaDryRun */, nil /* mf */).WithAuth(NewAuthenticator(nil), failingAuthorizer{wantErr})
+	handler := &fakeHandler{resp: "handler response"}
+
+	_, err := intercept.UnaryInterceptor(context.Background(), &trillian.ListTreesRequest{},
+		&grpc.UnaryServerInfo{FullMethod: "/trillian.TrillianAdmin/ListTrees"},
+		handler.run)
+	if err != wantErr {
+		t.Errorf("UnaryInterceptor() returned err = %v, want = %v", err, wantErr)
+	}
+	if handler.called {
+		t.Error("handler called despite authorization failure")
+	}
+}
+
+type failingAuthorizer struct {
+	err error
+}
+
+func (f failingAuthorizer) Authorize(ctx context.Context, principal string, treeID int64, op trees.OpType) error {
+	return f.err
+}
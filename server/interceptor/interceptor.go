@@ -28,6 +28,7 @@ import (
 	"github.com/google/trillian/quota"
 	"github.com/google/trillian/quota/etcd/quotapb"
 	"github.com/google/trillian/server/errors"
+	"github.com/google/trillian/server/paging"
 	"github.com/google/trillian/storage"
 	"github.com/google/trillian/trees"
 	"google.golang.org/grpc"
@@ -39,6 +40,8 @@ const (
 	badInfoReason            = "bad_info"
 	badTreeReason            = "bad_tree"
 	insufficientTokensReason = "insufficient_tokens"
+	unauthenticatedReason    = "unauthenticated"
+	permissionDeniedReason   = "permission_denied"
 	getTreeStage             = "get_tree"
 	getTokensStage           = "get_tokens"
 	traceSpanRoot            = "/trillian/server/int"
@@ -83,7 +86,7 @@ type RequestProcessor interface {
 
 // TrillianInterceptor checks that:
 // * Requests addressing a tree have the correct tree type and tree state;
-// * TODO(codingllama): Requests are properly authenticated / authorized ; and
+// * Requests are properly authenticated / authorized, if enabled with WithAuth; and
 // * Requests are rate limited appropriately.
 type TrillianInterceptor struct {
 	admin storage.AdminStorage
@@ -92,6 +95,10 @@ type TrillianInterceptor struct {
 	// quotaDryRun controls whether lack of tokens actually blocks requests (if set to true, no
 	// requests are blocked by lack of tokens).
 	quotaDryRun bool
+
+	// authn and authz are nil unless auth interception is enabled.
+	authn Authenticator
+	authz Authorizer
 }
 
 // New returns a new TrillianInterceptor instance.
@@ -104,6 +111,15 @@ func New(admin storage.AdminStorage, qm quota.Manager, quotaDryRun bool, mf moni
 	}
 }
 
+// WithAuth enables auth interception: the principal making each request is
+// identified by authn, and must be allowed to perform the request's operation
+// on the addressed tree by authz. It returns i for convenience.
+func (i *TrillianInterceptor) WithAuth(authn Authenticator, authz Authorizer) *TrillianInterceptor {
+	i.authn = authn
+	i.authz = authz
+	return i
+}
+
 func initMetrics(mf monitoring.MetricFactory) {
 	if mf == nil {
 		mf = monitoring.InertMetricFactory{}
@@ -144,6 +160,71 @@ func (i *TrillianInterceptor) UnaryInterceptor(ctx context.Context, req interfac
 	return resp, err
 }
 
+// StreamInterceptor executes the TrillianInterceptor logic for streaming RPCs.
+// The request is intercepted when the handler receives its first message, so
+// that server-streaming RPCs like WatchLeaves are checked just like unary ones.
+// Later messages of client-streaming RPCs aren't intercepted.
+func (i *TrillianInterceptor) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	stream := &interceptedStream{
+		ServerStream: ss,
+		ctx:          ss.Context(),
+		rp:           i.NewProcessor(),
+		method:       info.FullMethod,
+	}
+	err := handler(srv, stream)
+	if stream.intercepted {
+		// Streams only end with an error once they have started sending
+		// responses, which the tokens were spent on.
+		handlerErr := err
+		if stream.sent {
+			handlerErr = nil
+		}
+		stream.rp.After(stream.ctx, nil, info.FullMethod, handlerErr)
+	}
+	return err
+}
+
+// interceptedStream is a grpc.ServerStream which runs the Before logic of a
+// RequestProcessor on the first message it receives.
+type interceptedStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	rp     RequestProcessor
+	method string
+
+	// received is set once the first message has been received, and
+	// intercepted once it also passed Before.
+	received, intercepted bool
+	// sent is set once a message has been sent.
+	sent bool
+}
+
+func (s *interceptedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *interceptedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if s.received {
+		return nil
+	}
+	s.received = true
+	ctx, err := s.rp.Before(s.ctx, m, s.method)
+	if err != nil {
+		return err
+	}
+	s.ctx = ctx
+	s.intercepted = true
+	return nil
+}
+
+func (s *interceptedStream) SendMsg(m interface{}) error {
+	s.sent = true
+	return s.ServerStream.SendMsg(m)
+}
+
 // NewProcessor returns a RequestProcessor for the TrillianInterceptor logic.
 func (i *TrillianInterceptor) NewProcessor() RequestProcessor {
 	return &trillianProcessor{parent: i}
@@ -172,7 +253,17 @@ func (tp *trillianProcessor) Before(ctx context.Context, req interface{}, method
 	tp.info = info
 	requestCounter.Inc(fmt.Sprint(info.treeID))
 
-	// TODO(codingllama): Add auth interception
+	if tp.parent.authz != nil {
+		principal, err := tp.parent.authn.Authenticate(innerCtx)
+		if err != nil {
+			incRequestDeniedCounter(unauthenticatedReason, info.treeID, info.quotaUsers)
+			return ctx, err
+		}
+		if err := tp.parent.authz.Authorize(innerCtx, principal, info.treeID, info.opType); err != nil {
+			incRequestDeniedCounter(permissionDeniedReason, info.treeID, info.quotaUsers)
+			return ctx, err
+		}
+	}
 
 	if info.getTree {
 		tree, err := trees.GetTree(
@@ -302,6 +393,9 @@ type rpcInfo struct {
 	readonly  bool
 	treeID    int64
 	treeTypes []trillian.TreeType
+	// opType is the type of operation performed by the request, used for
+	// authorization.
+	opType trees.OpType
 
 	specs  []quota.Spec
 	tokens int
@@ -335,6 +429,7 @@ func newRPCInfoForRequest(req interface{}) (*rpcInfo, error) {
 		getTree:   true,
 		readonly:  true,
 		treeTypes: nil,
+		opType:    trees.Query,
 		tokens:    0,
 	}
 
@@ -350,19 +445,23 @@ func newRPCInfoForRequest(req interface{}) (*rpcInfo, error) {
 		*quotapb.UpdateConfigRequest:
 		info.getTree = false
 		info.readonly = false // Doesn't really matter as all interceptors are turned off
+		info.opType = trees.Admin
 
 	// Admin create
 	case *trillian.CreateTreeRequest:
 		info.getTree = false // Tree doesn't exist
 		info.readonly = false
+		info.opType = trees.Admin
 
 	// Admin list
 	case *trillian.ListTreesRequest:
 		info.getTree = false // Zero to many trees
+		info.opType = trees.Admin
 
 	// Admin / readonly
 	case *trillian.GetTreeRequest:
 		info.getTree = false // Read done within RPC handler
+		info.opType = trees.Admin
 
 	// Admin / readwrite
 	case *trillian.DeleteTreeRequest,
@@ -370,6 +469,7 @@ func newRPCInfoForRequest(req interface{}) (*rpcInfo, error) {
 		*trillian.UpdateTreeRequest:
 		info.getTree = false // Read-modify-write done within RPC handler
 		info.readonly = false
+		info.opType = trees.Admin
 
 	// (Log + Pre-ordered Log) / readonly
 	case *trillian.GetConsistencyProofRequest,
@@ -379,6 +479,9 @@ func newRPCInfoForRequest(req interface{}) (*rpcInfo, error) {
 		*trillian.GetLatestSignedLogRootRequest:
 		info.treeTypes = []trillian.TreeType{trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG}
 		info.tokens = 1
+	case *trillian.GetBatchInclusionProofRequest:
+		info.treeTypes = []trillian.TreeType{trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG}
+		info.tokens = len(req.GetLeafIndex())
 	case *trillian.GetLeavesByHashRequest:
 		info.treeTypes = []trillian.TreeType{trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG}
 		info.tokens = len(req.GetLeafHash())
@@ -391,32 +494,48 @@ func newRPCInfoForRequest(req interface{}) (*rpcInfo, error) {
 		if c := req.GetCount(); c > 1 {
 			info.tokens = int(c)
 		}
+	case *trillian.GetLeavesByRangeWithProofRequest:
+		info.treeTypes = []trillian.TreeType{trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG}
+		info.tokens = 1
+		if c := req.GetCount(); c > 1 {
+			info.tokens = int(c)
+		}
 	case *trillian.GetSequencedLeafCountRequest:
 		info.treeTypes = []trillian.TreeType{trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG}
+	case *trillian.WatchLeavesRequest:
+		info.treeTypes = []trillian.TreeType{trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG}
+		// Opening the stream costs a token, the handler charges the leaves it
+		// sends as it goes.
+		info.tokens = 1
 
 	// Log / readwrite
 	case *trillian.QueueLeafRequest:
 		info.readonly = false
+		info.opType = trees.QueueLog
 		info.treeTypes = []trillian.TreeType{trillian.TreeType_LOG}
 		info.tokens = 1
 	case *trillian.QueueLeavesRequest:
 		info.readonly = false
+		info.opType = trees.QueueLog
 		info.treeTypes = []trillian.TreeType{trillian.TreeType_LOG}
 		info.tokens = len(req.GetLeaves())
 
 	// Pre-ordered Log / readwrite
 	case *trillian.AddSequencedLeafRequest:
 		info.readonly = false
+		info.opType = trees.SequenceLog
 		info.treeTypes = []trillian.TreeType{trillian.TreeType_PREORDERED_LOG}
 		info.tokens = 1
 	case *trillian.AddSequencedLeavesRequest:
 		info.readonly = false
+		info.opType = trees.SequenceLog
 		info.treeTypes = []trillian.TreeType{trillian.TreeType_PREORDERED_LOG}
 		info.tokens = len(req.GetLeaves())
 
 	// (Log + Pre-ordered Log) / readwrite
 	case *trillian.InitLogRequest:
 		info.readonly = false
+		info.opType = trees.Admin
 		info.treeTypes = []trillian.TreeType{trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG}
 		info.tokens = 1
 
@@ -429,10 +548,34 @@ func newRPCInfoForRequest(req interface{}) (*rpcInfo, error) {
 		info.tokens = len(req.GetIndex())
 	case *trillian.GetMapLeavesByRevisionRequest:
 		info.treeTypes = []trillian.TreeType{trillian.TreeType_MAP}
-		info.tokens = len(req.GetIndex())
+		info.tokens = len(req.GetIndex()) + len(req.GetKey())
 	case *trillian.GetMapLeavesRequest:
 		info.treeTypes = []trillian.TreeType{trillian.TreeType_MAP}
-		info.tokens = len(req.GetIndex())
+		info.tokens = len(req.GetIndex()) + len(req.GetKey())
+	case *trillian.GetRevisionDiffRequest:
+		info.treeTypes = []trillian.TreeType{trillian.TreeType_MAP}
+		// Paginated requests are charged for a full page, as sized by the
+		// server. Invalid page sizes are rejected by the server.
+		info.tokens = 1
+		if s, err := paging.MapPageSize(req.GetPageSize()); err == nil {
+			info.tokens = s
+		}
+		// Both the old and the new value of each index are read.
+		if req.GetIncludeValues() {
+			info.tokens *= 2
+		}
+	case *trillian.GetLeafHistoryRequest:
+		info.treeTypes = []trillian.TreeType{trillian.TreeType_MAP}
+		info.tokens = 1
+		if s, err := paging.LeafHistoryPageSize(req.GetPageSize()); err == nil {
+			info.tokens = s
+		}
+	case *trillian.ListMapLeavesByRevisionRequest:
+		info.treeTypes = []trillian.TreeType{trillian.TreeType_MAP}
+		info.tokens = 1
+		if s, err := paging.MapPageSize(req.GetPageSize()); err == nil {
+			info.tokens = s
+		}
 	case *trillian.GetSignedMapRootByRevisionRequest,
 		*trillian.GetSignedMapRootRequest:
 		info.treeTypes = []trillian.TreeType{trillian.TreeType_MAP}
@@ -441,14 +584,17 @@ func newRPCInfoForRequest(req interface{}) (*rpcInfo, error) {
 	// Map / readwrite
 	case *trillian.SetMapLeavesRequest:
 		info.readonly = false
+		info.opType = trees.UpdateMap
 		info.treeTypes = []trillian.TreeType{trillian.TreeType_MAP}
 		info.tokens = len(req.GetLeaves())
 	case *trillian.WriteMapLeavesRequest:
 		info.readonly = false
+		info.opType = trees.UpdateMap
 		info.treeTypes = []trillian.TreeType{trillian.TreeType_MAP}
 		info.tokens = len(req.GetLeaves())
 	case *trillian.InitMapRequest:
 		info.readonly = false
+		info.opType = trees.Admin
 		info.treeTypes = []trillian.TreeType{trillian.TreeType_MAP}
 		info.tokens = 1
 
@@ -465,17 +611,21 @@ func newRPCInfo(req interface{}) (*rpcInfo, error) {
 		return nil, err
 	}
 
-	if info.getTree || info.tokens > 0 {
-		switch req := req.(type) {
-		case logIDRequest:
-			info.treeID = req.GetLogId()
-		case mapIDRequest:
-			info.treeID = req.GetMapId()
-		case treeIDRequest:
-			info.treeID = req.GetTreeId()
-		case treeRequest:
-			info.treeID = req.GetTree().GetTreeId()
-		default:
+	// The treeID is also read for requests that don't need it otherwise, so
+	// they can be authorized against the tree they address.
+	switch req := req.(type) {
+	case *trillian.CreateTreeRequest:
+		// The tree doesn't exist yet, whatever its ID says.
+	case logIDRequest:
+		info.treeID = req.GetLogId()
+	case mapIDRequest:
+		info.treeID = req.GetMapId()
+	case treeIDRequest:
+		info.treeID = req.GetTreeId()
+	case treeRequest:
+		info.treeID = req.GetTree().GetTreeId()
+	default:
+		if info.getTree || info.tokens > 0 {
 			return nil, status.Errorf(codes.Internal, "cannot retrieve treeID from request: %T", req)
 		}
 	}
@@ -26,6 +26,7 @@ import (
 	"github.com/google/trillian"
 	"github.com/google/trillian/quota"
 	"github.com/google/trillian/quota/etcd/quotapb"
+	"github.com/google/trillian/server/paging"
 	"github.com/google/trillian/storage"
 	"github.com/google/trillian/storage/testonly"
 	"github.com/google/trillian/trees"
@@ -234,6 +235,16 @@ func TestTrillianInterceptor_QuotaInterception(t *testing.T) {
 			},
 			wantTokens: 3,
 		},
+		{
+			desc:   "logReadBatchProof",
+			method: "/trillian.TrillianLog/GetBatchInclusionProof",
+			req:    &trillian.GetBatchInclusionProofRequest{LogId: logTree.TreeId, LeafIndex: []int64{1, 5}, TreeSize: 10},
+			specs: []quota.Spec{
+				{Group: quota.Tree, Kind: quota.Read, TreeID: logTree.TreeId},
+				{Group: quota.Global, Kind: quota.Read},
+			},
+			wantTokens: 2,
+		},
 		{
 			desc:   "logReadRange",
 			method: "/trillian.TrillianLog/GetLeavesByRange",
@@ -244,6 +255,16 @@ func TestTrillianInterceptor_QuotaInterception(t *testing.T) {
 			},
 			wantTokens: 123,
 		},
+		{
+			desc:   "logReadRangeWithProof",
+			method: "/trillian.TrillianLog/GetLeavesByRangeWithProof",
+			req:    &trillian.GetLeavesByRangeWithProofRequest{LogId: logTree.TreeId, Count: 123, TreeSize: 1000},
+			specs: []quota.Spec{
+				{Group: quota.Tree, Kind: quota.Read, TreeID: logTree.TreeId},
+				{Group: quota.Global, Kind: quota.Read},
+			},
+			wantTokens: 123,
+		},
 		{
 			desc:   "logReadNegativeRange",
 			method: "/trillian.TrillianLog/GetLeavesByRange",
@@ -308,6 +329,116 @@ func TestTrillianInterceptor_QuotaInterception(t *testing.T) {
 			},
 			wantTokens: 2,
 		},
+		{
+			desc:   "mapReadByKey",
+			method: "/trillian.TrillianMap/GetLeaves",
+			req:    &trillian.GetMapLeavesRequest{MapId: mapTree.TreeId, Key: [][]byte{[]byte("a"), []byte("b"), []byte("c")}},
+			specs: []quota.Spec{
+				{Group: quota.Tree, Kind: quota.Read, TreeID: mapTree.TreeId},
+				{Group: quota.Global, Kind: quota.Read},
+			},
+			wantTokens: 3,
+		},
+		{
+			desc:   "mapList",
+			method: "/trillian.TrillianMap/ListLeavesByRevision",
+			req:    &trillian.ListMapLeavesByRevisionRequest{MapId: mapTree.TreeId, PageSize: 50},
+			specs: []quota.Spec{
+				{Group: quota.Tree, Kind: quota.Read, TreeID: mapTree.TreeId},
+				{Group: quota.Global, Kind: quota.Read},
+			},
+			wantTokens: 50,
+		},
+		{
+			desc:   "mapDiff",
+			method: "/trillian.TrillianMap/GetRevisionDiff",
+			req:    &trillian.GetRevisionDiffRequest{MapId: mapTree.TreeId, ToRevision: 1, PageSize: 20},
+			specs: []quota.Spec{
+				{Group: quota.Tree, Kind: quota.Read, TreeID: mapTree.TreeId},
+				{Group: quota.Global, Kind: quota.Read},
+			},
+			wantTokens: 20,
+		},
+		{
+			desc:   "mapLeafHistory",
+			method: "/trillian.TrillianMap/GetLeafHistory",
+			req:    &trillian.GetLeafHistoryRequest{MapId: mapTree.TreeId, ToRevision: 1, PageSize: 30},
+			specs: []quota.Spec{
+				{Group: quota.Tree, Kind: quota.Read, TreeID: mapTree.TreeId},
+				{Group: quota.Global, Kind: quota.Read},
+			},
+			wantTokens: 30,
+		},
+		{
+			desc:   "mapListDefaultPageSize",
+			method: "/trillian.TrillianMap/ListLeavesByRevision",
+			req:    &trillian.ListMapLeavesByRevisionRequest{MapId: mapTree.TreeId},
+			specs: []quota.Spec{
+				{Group: quota.Tree, Kind: quota.Read, TreeID: mapTree.TreeId},
+				{Group: quota.Global, Kind: quota.Read},
+			},
+			wantTokens: paging.DefaultMapPageSize,
+		},
+		{
+			desc:   "mapListMaxPageSize",
+			method: "/trillian.TrillianMap/ListLeavesByRevision",
+			req:    &trillian.ListMapLeavesByRevisionRequest{MapId: mapTree.TreeId, PageSize: paging.MaxMapPageSize + 1},
+			specs: []quota.Spec{
+				{Group: quota.Tree, Kind: quota.Read, TreeID: mapTree.TreeId},
+				{Group: quota.Global, Kind: quota.Read},
+			},
+			wantTokens: paging.MaxMapPageSize,
+		},
+		{
+			desc:   "mapDiffDefaultPageSize",
+			method: "/trillian.TrillianMap/GetRevisionDiff",
+			req:    &trillian.GetRevisionDiffRequest{MapId: mapTree.TreeId, ToRevision: 1},
+			specs: []quota.Spec{
+				{Group: quota.Tree, Kind: quota.Read, TreeID: mapTree.TreeId},
+				{Group: quota.Global, Kind: quota.Read},
+			},
+			wantTokens: paging.DefaultMapPageSize,
+		},
+		{
+			desc:   "mapDiffMaxPageSize",
+			method: "/trillian.TrillianMap/GetRevisionDiff",
+			req:    &trillian.GetRevisionDiffRequest{MapId: mapTree.TreeId, ToRevision: 1, PageSize: paging.MaxMapPageSize + 1},
+			specs: []quota.Spec{
+				{Group: quota.Tree, Kind: quota.Read, TreeID: mapTree.TreeId},
+				{Group: quota.Global, Kind: quota.Read},
+			},
+			wantTokens: paging.MaxMapPageSize,
+		},
+		{
+			desc:   "mapDiffValues",
+			method: "/trillian.TrillianMap/GetRevisionDiff",
+			req:    &trillian.GetRevisionDiffRequest{MapId: mapTree.TreeId, ToRevision: 1, IncludeValues: true, PageSize: 20},
+			specs: []quota.Spec{
+				{Group: quota.Tree, Kind: quota.Read, TreeID: mapTree.TreeId},
+				{Group: quota.Global, Kind: quota.Read},
+			},
+			wantTokens: 40,
+		},
+		{
+			desc:   "mapLeafHistoryDefaultPageSize",
+			method: "/trillian.TrillianMap/GetLeafHistory",
+			req:    &trillian.GetLeafHistoryRequest{MapId: mapTree.TreeId, ToRevision: 1},
+			specs: []quota.Spec{
+				{Group: quota.Tree, Kind: quota.Read, TreeID: mapTree.TreeId},
+				{Group: quota.Global, Kind: quota.Read},
+			},
+			wantTokens: paging.DefaultLeafHistoryPageSize,
+		},
+		{
+			desc:   "mapLeafHistoryMaxPageSize",
+			method: "/trillian.TrillianMap/GetLeafHistory",
+			req:    &trillian.GetLeafHistoryRequest{MapId: mapTree.TreeId, ToRevision: 1, PageSize: paging.MaxLeafHistoryPageSize + 1},
+			specs: []quota.Spec{
+				{Group: quota.Tree, Kind: quota.Read, TreeID: mapTree.TreeId},
+				{Group: quota.Global, Kind: quota.Read},
+			},
+			wantTokens: paging.MaxLeafHistoryPageSize,
+		},
 		{
 			desc:   "emptyBatchRequest",
 			method: "/trillian.TrillianLog/QueueLeaves",
@@ -384,6 +515,16 @@ func TestTrillianInterceptor_QuotaInterception(t *testing.T) {
 			},
 			wantTokens: 5,
 		},
+		{
+			desc:   "watchLeaves",
+			method: "/trillian.TrillianLog/WatchLeaves",
+			req:    &trillian.WatchLeavesRequest{LogId: logTree.TreeId, MaxBatchSize: 50},
+			specs: []quota.Spec{
+				{Group: quota.Tree, Kind: quota.Read, TreeID: logTree.TreeId},
+				{Group: quota.Global, Kind: quota.Read},
+			},
+			wantTokens: 1,
+		},
 		{
 			desc:   "quotaError",
 			method: "/trillian.TrillianLog/GetLatestSignedLogRoot",
@@ -695,6 +836,125 @@ func TestTrillianInterceptor_BeforeAfter(t *testing.T) {
 	}
 }
 
+func TestTrillianInterceptor_StreamInterceptor(t *testing.T) {
+	logTree := proto.Clone(testonly.LogTree).(*trillian.Tree)
+	logTree.TreeId = 10
+	mapTree := proto.Clone(testonly.MapTree).(*trillian.Tree)
+	mapTree.TreeId = 11
+	specs := []quota.Spec{
+		{Group: quota.Tree, Kind: quota.Read, TreeID: logTree.TreeId},
+		{Group: quota.Global, Kind: quota.Read},
+	}
+
+	for _, test := range []struct {
+		desc          string
+		req           *trillian.WatchLeavesRequest
+		getTokensErr  error
+		handlerErr    error
+		wantCode      codes.Code
+		wantGetTokens bool
+		wantPutTokens bool
+		wantSent      bool
+	}{
+		{
+			desc:          "success",
+			req:           &trillian.WatchLeavesRequest{LogId: logTree.TreeId},
+			wantGetTokens: true,
+			wantSent:      true,
+		},
+		{
+			desc:          "streamEnded",
+			req:           &trillian.WatchLeavesRequest{LogId: logTree.TreeId},
+			handlerErr:    status.Error(codes.Canceled, "stream ended"),
+			wantCode:      codes.Canceled,
+			wantGetTokens: true,
+			wantSent:      true,
+		},
+		{
+			desc:          "badRequest",
+			req:           &trillian.WatchLeavesRequest{LogId: logTree.TreeId, StartIndex: -1},
+			wantCode:      codes.InvalidArgument,
+			wantGetTokens: true,
+			wantPutTokens: true,
+		},
+		{
+			desc:     "wrongTreeType",
+			req:      &trillian.WatchLeavesRequest{LogId: mapTree.TreeId},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:          "quotaError",
+			req:           &trillian.WatchLeavesRequest{LogId: logTree.TreeId},
+			getTokensErr:  errors.New("not enough tokens"),
+			wantCode:      codes.ResourceExhausted,
+			wantGetTokens: true,
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			admin := storage.NewMockAdminStorage(ctrl)
+			adminTX := storage.NewMockReadOnlyAdminTX(ctrl)
+			admin.EXPECT().Snapshot(gomock.Any()).AnyTimes().Return(adminTX, nil)
+			adminTX.EXPECT().GetTree(gomock.Any(), logTree.TreeId).AnyTimes().Return(logTree, nil)
+			adminTX.EXPECT().GetTree(gomock.Any(), mapTree.TreeId).AnyTimes().Return(mapTree, nil)
+			adminTX.EXPECT().Close().AnyTimes().Return(nil)
+			adminTX.EXPECT().Commit().AnyTimes().Return(nil)
+
+			qm := quota.NewMockManager(ctrl)
+			if test.wantGetTokens {
+				qm.EXPECT().GetTokens(gomock.Any(), 1, specs).Return(test.getTokensErr)
+			}
+			putTokensCh := make(chan bool, 1)
+			if test.wantPutTokens {
+				qm.EXPECT().PutTokens(gomock.Any(), 1, specs).Do(func(context.Context, int, []quota.Spec) {
+					putTokensCh <- true
+				}).Return(nil)
+			}
+
+			stream := &fakeServerStream{ctx: context.Background(), req: test.req}
+			var gotTree *trillian.Tree
+			handler := func(srv interface{}, ss grpc.ServerStream) error {
+				req := &trillian.WatchLeavesRequest{}
+				if err := ss.RecvMsg(req); err != nil {
+					return err
+				}
+				if req.StartIndex < 0 {
+					return status.Error(codes.InvalidArgument, "bad start index")
+				}
+				gotTree, _ = trees.FromContext(ss.Context())
+				if err := ss.SendMsg(&trillian.WatchLeavesResponse{}); err != nil {
+					return err
+				}
+				return test.handlerErr
+			}
+
+			intercept := New(admin, qm, false /* quotaDryRun */, nil /* mf */)
+			err := intercept.StreamInterceptor(nil, stream,
+				&grpc.StreamServerInfo{FullMethod: "/trillian.TrillianLog/WatchLeaves", IsServerStream: true},
+				handler)
+			if got := status.Code(err); got != test.wantCode {
+				t.Fatalf("StreamInterceptor() returned err = %v, want code %v", err, test.wantCode)
+			}
+			if got := len(stream.sent) > 0; got != test.wantSent {
+				t.Errorf("StreamInterceptor() sent %d responses, want sent: %v", len(stream.sent), test.wantSent)
+			}
+			if test.wantSent && !proto.Equal(gotTree, logTree) {
+				t.Errorf("tree in handler ctx = %v, want %v", gotTree, logTree)
+			}
+
+			// PutTokens is delegated to a separate goroutine. Give it some time to complete.
+			if test.wantPutTokens {
+				select {
+				case <-putTokensCh:
+				case <-time.After(1 * time.Second):
+					// No need to error here, gomock will fail if the call is missing.
+				}
+			}
+		})
+	}
+}
+
 func TestCombine(t *testing.T) {
 	i1 := &fakeInterceptor{key: "key1", val: "foo"}
 	i2 := &fakeInterceptor{key: "key2", val: "bar"}
@@ -863,6 +1123,29 @@ func (f *fakeHandler) run(ctx context.Context, req interface{}) (interface{}, er
 	return f.resp, f.err
 }
 
+// fakeServerStream is a grpc.ServerStream which receives req, and records the
+// messages sent.
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	req  proto.Message
+	sent []interface{}
+}
+
+func (f *fakeServerStream) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeServerStream) RecvMsg(m interface{}) error {
+	proto.Merge(m.(proto.Message), f.req)
+	return nil
+}
+
+func (f *fakeServerStream) SendMsg(m interface{}) error {
+	f.sent = append(f.sent, m)
+	return nil
+}
+
 type fakeInterceptor struct {
 	key    interface{}
 	val    interface{}
@@ -17,8 +17,11 @@ package server
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/golang/glog"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/google/trillian"
 	"github.com/google/trillian/extension"
 	"github.com/google/trillian/merkle"
@@ -30,15 +33,27 @@ import (
 	"github.com/google/trillian/util/clock"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+
+	tcrypto "github.com/google/trillian/crypto"
 )
 
-// TODO: There is no access control in the server yet and clients could easily modify
-// any tree.
+// Access control is not done by the server itself, but by interceptor.TrillianInterceptor
+// when it's configured with an Authorizer. Without one, clients could easily modify any tree.
 
 // Pass this as a fixed value to proof calculations. It's used as the max depth of the tree
 const (
 	proofMaxBitLen = 64
 	traceSpanRoot  = "/trillian"
+
+	// defaultWatchLeavesBatchSize is the maximum number of leaves sent in a
+	// single WatchLeavesResponse if the request doesn't set one.
+	defaultWatchLeavesBatchSize = 1000
+	// defaultWatchLeavesPollInterval is how long WatchLeaves waits before
+	// checking again for new leaves once the stream has caught up.
+	defaultWatchLeavesPollInterval = time.Second
+	// maxWatchLeavesDuration is how long a WatchLeaves stream is kept open,
+	// after which clients have to resume with a new request.
+	maxWatchLeavesDuration = 10 * time.Minute
 )
 
 var (
@@ -54,6 +69,8 @@ type TrillianLogRPCServer struct {
 	timeSource            clock.TimeSource
 	leafCounter           monitoring.Counter
 	proofIndexPercentiles monitoring.Histogram
+	watchPollInterval     time.Duration
+	watchMaxDuration      time.Duration
 }
 
 // NewTrillianLogRPCServer creates a new RPC server backed by a LogStorageProvider.
@@ -75,6 +92,8 @@ func NewTrillianLogRPCServer(registry extension.Registry, timeSource clock.TimeS
 			"Count of inclusion proof request index using percentage of current log size at the time",
 			monitoring.PercentileBuckets(1),
 		),
+		watchPollInterval: defaultWatchLeavesPollInterval,
+		watchMaxDuration:  maxWatchLeavesDuration,
 	}
 }
 
@@ -94,8 +113,9 @@ func (t *TrillianLogRPCServer) QueueLeaf(ctx context.Context, req *trillian.Queu
 	}
 
 	queueReq := &trillian.QueueLeavesRequest{
-		LogId:  req.LogId,
-		Leaves: []*trillian.LogLeaf{req.Leaf},
+		LogId:                       req.LogId,
+		Leaves:                      []*trillian.LogLeaf{req.Leaf},
+		ReturnSignedEntryTimestamps: req.ReturnSignedEntryTimestamp,
 	}
 	queueRsp, err := t.QueueLeaves(ctx, queueReq)
 	if err != nil {
@@ -107,7 +127,14 @@ func (t *TrillianLogRPCServer) QueueLeaf(ctx context.Context, req *trillian.Queu
 	if len(queueRsp.QueuedLeaves) != 1 {
 		return nil, status.Errorf(codes.Internal, "unexpected count of leaves %d", len(queueRsp.QueuedLeaves))
 	}
-	return &trillian.QueueLeafResponse{QueuedLeaf: queueRsp.QueuedLeaves[0]}, nil
+	rsp := &trillian.QueueLeafResponse{QueuedLeaf: queueRsp.QueuedLeaves[0]}
+	if req.ReturnSignedEntryTimestamp {
+		if len(queueRsp.SignedEntryTimestamps) != 1 {
+			return nil, status.Errorf(codes.Internal, "unexpected count of signed entry timestamps %d", len(queueRsp.SignedEntryTimestamps))
+		}
+		rsp.SignedEntryTimestamp = queueRsp.SignedEntryTimestamps[0]
+	}
+	return rsp, nil
 }
 
 func hashLeaves(leaves []*trillian.LogLeaf, hasher hashers.LogHasher) {
@@ -135,9 +162,19 @@ func (t *TrillianLogRPCServer) QueueLeaves(ctx context.Context, req *trillian.Qu
 
 	ctx = trees.NewContext(ctx, tree)
 
+	// Get hold of the signer before queueing anything, so that leaves aren't
+	// queued without the promises that were asked for.
+	var signer *tcrypto.Signer
+	if req.ReturnSignedEntryTimestamps {
+		if signer, err = trees.Signer(ctx, tree); err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "no signer for tree %v: %v", logID, err)
+		}
+	}
+
 	hashLeaves(req.Leaves, hasher)
 
-	ret, err := t.registry.LogStorage.QueueLeaves(ctx, tree, req.Leaves, t.timeSource.Now())
+	now := t.timeSource.Now()
+	ret, err := t.registry.LogStorage.QueueLeaves(ctx, tree, req.Leaves, now)
 	if err != nil {
 		return nil, err
 	}
@@ -149,7 +186,43 @@ func (t *TrillianLogRPCServer) QueueLeaves(ctx context.Context, req *trillian.Qu
 			t.leafCounter.Inc("existing")
 		}
 	}
-	return &trillian.QueueLeavesResponse{QueuedLeaves: ret}, nil
+	rsp := &trillian.QueueLeavesResponse{QueuedLeaves: ret}
+	if signer != nil {
+		if rsp.SignedEntryTimestamps, err = signEntryTimestamps(signer, logID, ret, now); err != nil {
+			return nil, err
+		}
+	}
+	return rsp, nil
+}
+
+// signEntryTimestamps returns promises to incorporate each of the queued
+// leaves. Leaves without a queue timestamp, which some storage implementations
+// don't return for newly queued leaves, are promised as of queueTime.
+func signEntryTimestamps(signer *tcrypto.Signer, logID int64, queued []*trillian.QueuedLogLeaf, queueTime time.Time) ([]*trillian.SignedEntryTimestamp, error) {
+	sets := make([]*trillian.SignedEntryTimestamp, 0, len(queued))
+	for _, q := range queued {
+		leaf := q.GetLeaf()
+		if leaf == nil {
+			return nil, status.Errorf(codes.Internal, "queued leaf missing from storage response")
+		}
+		ts := queueTime
+		if leaf.QueueTimestamp != nil {
+			var err error
+			if ts, err = ptypes.Timestamp(leaf.QueueTimestamp); err != nil {
+				return nil, status.Errorf(codes.Internal, "invalid queue timestamp: %v", err)
+			}
+		}
+		set, err := signer.SignEntryTimestamp(&types.EntryTimestampV1{
+			LogID:            uint64(logID),
+			LeafIdentityHash: leaf.LeafIdentityHash,
+			TimestampNanos:   uint64(ts.UnixNano()),
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to sign entry timestamp: %v", err)
+		}
+		sets = append(sets, set)
+	}
+	return sets, nil
 }
 
 // AddSequencedLeaf submits one sequenced leaf to the storage.
@@ -259,6 +332,69 @@ func (t *TrillianLogRPCServer) GetInclusionProof(ctx context.Context, req *trill
 	return r, nil
 }
 
+// GetBatchInclusionProof obtains a single proof of inclusion in the tree for a
+// number of leaves that have been sequenced. The proof contains each node
+// needed by the individual inclusion proofs only once.
+func (t *TrillianLogRPCServer) GetBatchInclusionProof(ctx context.Context, req *trillian.GetBatchInclusionProofRequest) (*trillian.GetBatchInclusionProofResponse, error) {
+	ctx, spanEnd := spanFor(ctx, "GetBatchInclusionProof")
+	defer spanEnd()
+	if err := validateGetBatchInclusionProofRequest(req); err != nil {
+		return nil, err
+	}
+
+	tree, hasher, err := t.getTreeAndHasher(ctx, req.LogId, optsLogRead)
+	if err != nil {
+		return nil, err
+	}
+	ctx = trees.NewContext(ctx, tree)
+
+	tx, err := t.snapshotForTree(ctx, tree, "GetBatchInclusionProof")
+	if err != nil {
+		return nil, err
+	}
+	defer t.closeAndLog(ctx, tree.TreeId, tx, "GetBatchInclusionProof")
+
+	slr, err := tx.LatestSignedLogRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var root types.LogRootV1
+	if err := root.UnmarshalBinary(slr.LogRoot); err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not read current log root: %v", err)
+	}
+
+	indices := sortedUniqueIndices(req.LeafIndex)
+	r := &trillian.GetBatchInclusionProofResponse{LeafIndex: indices, SignedLogRoot: slr}
+
+	if uint64(req.TreeSize) > root.TreeSize {
+		return r, nil
+	}
+
+	proofNodeIDs, err := merkle.CalcBatchInclusionProofNodeAddresses(req.TreeSize, indices, int64(root.TreeSize), proofMaxBitLen)
+	if err != nil {
+		return nil, err
+	}
+	rev, err := tx.ReadRevision(ctx)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := fetchNodesAndBuildProof(ctx, tx, hasher, rev, 0, proofNodeIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range indices {
+		t.recordIndexPercent(index, root.TreeSize)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	r.Hashes = proof.Hashes
+
+	return r, nil
+}
+
 // GetInclusionProofByHash obtains proofs of inclusion by leaf hash. Because some logs can
 // contain duplicate hashes it is possible for multiple proofs to be returned.
 func (t *TrillianLogRPCServer) GetInclusionProofByHash(ctx context.Context, req *trillian.GetInclusionProofByHashRequest) (*trillian.GetInclusionProofByHashResponse, error) {
@@ -571,6 +707,87 @@ func (t *TrillianLogRPCServer) GetLeavesByRange(ctx context.Context, req *trilli
 	return r, nil
 }
 
+// GetLeavesByRangeWithProof obtains leaves based on a range of sequence
+// numbers within the tree, together with a proof of their inclusion in the
+// tree of the requested size: the hashes of the compact ranges covering the
+// leaves to the left and to the right of the returned ones.
+func (t *TrillianLogRPCServer) GetLeavesByRangeWithProof(ctx context.Context, req *trillian.GetLeavesByRangeWithProofRequest) (*trillian.GetLeavesByRangeWithProofResponse, error) {
+	ctx, spanEnd := spanFor(ctx, "GetLeavesByRangeWithProof")
+	defer spanEnd()
+	if err := validateGetLeavesByRangeWithProofRequest(req); err != nil {
+		return nil, err
+	}
+
+	tree, ctx, err := t.getTreeAndContext(ctx, req.LogId, optsLogRead)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := t.snapshotForTree(ctx, tree, "GetLeavesByRangeWithProof")
+	if err != nil {
+		return nil, err
+	}
+	defer t.closeAndLog(ctx, tree.TreeId, tx, "GetLeavesByRangeWithProof")
+
+	slr, err := tx.LatestSignedLogRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var root types.LogRootV1
+	if err := root.UnmarshalBinary(slr.LogRoot); err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not read current log root: %v", err)
+	}
+
+	r := &trillian.GetLeavesByRangeWithProofResponse{SignedLogRoot: slr}
+
+	treeSize := req.TreeSize
+	if treeSize == 0 {
+		treeSize = int64(root.TreeSize)
+	}
+	if uint64(treeSize) > root.TreeSize || req.StartIndex >= treeSize {
+		return r, nil
+	}
+
+	count := req.Count
+	if rest := treeSize - req.StartIndex; count > rest {
+		count = rest
+	}
+	leaves, err := tx.GetLeavesByRange(ctx, req.StartIndex, count)
+	if err != nil {
+		return nil, err
+	}
+	if len(leaves) == 0 {
+		return nil, status.Errorf(codes.Internal, "no leaves found from index %d", req.StartIndex)
+	}
+	if int64(len(leaves)) > count {
+		leaves = leaves[:count]
+	}
+	for i, leaf := range leaves {
+		if want := req.StartIndex + int64(i); leaf.LeafIndex != want {
+			return nil, status.Errorf(codes.Internal, "got leaf index %d from storage, want %d", leaf.LeafIndex, want)
+		}
+	}
+
+	rev, err := tx.ReadRevision(ctx)
+	if err != nil {
+		return nil, err
+	}
+	end := req.StartIndex + int64(len(leaves))
+	left, right, err := fetchRangeProof(ctx, tx, rev, uint64(req.StartIndex), uint64(end), uint64(treeSize))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.commitAndLog(ctx, req.LogId, tx, "GetLeavesByRangeWithProof"); err != nil {
+		return nil, err
+	}
+
+	r.Leaves = leaves
+	r.LeftHashes = left
+	r.RightHashes = right
+	r.TreeSize = treeSize
+	return r, nil
+}
+
 // GetLeavesByHash obtains one or more leaves based on their tree hash. It is not possible
 // to fetch leaves that have been queued but not yet integrated. Logs may accept duplicate
 // entries so this may return more results than the number of hashes in the request.
@@ -687,6 +904,108 @@ func (t *TrillianLogRPCServer) GetEntryAndProof(ctx context.Context, req *trilli
 	return r, nil
 }
 
+// WatchLeaves streams leaves from the log in order, starting at
+// req.StartIndex, waiting for new leaves to be integrated once it has caught up
+// with the current tree. Each batch is charged to the read quota by its number
+// of leaves before it is sent. It returns when the stream's context is done, on
+// error, or once the stream has been open for watchMaxDuration.
+func (t *TrillianLogRPCServer) WatchLeaves(req *trillian.WatchLeavesRequest, stream trillian.TrillianLog_WatchLeavesServer) error {
+	ctx, spanEnd := spanFor(stream.Context(), "WatchLeaves")
+	defer spanEnd()
+	if err := validateWatchLeavesRequest(req); err != nil {
+		return err
+	}
+	batchSize := req.MaxBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultWatchLeavesBatchSize
+	}
+
+	tree, hasher, err := t.getTreeAndHasher(ctx, req.LogId, optsLogRead)
+	if err != nil {
+		return err
+	}
+	ctx = trees.NewContext(ctx, tree)
+
+	end := t.timeSource.Now().Add(t.watchMaxDuration)
+	next, prevSize := req.StartIndex, req.FirstTreeSize
+	for t.timeSource.Now().Before(end) {
+		rsp, err := t.nextWatchLeavesBatch(ctx, tree, hasher, next, batchSize, prevSize)
+		if err != nil {
+			return err
+		}
+		if rsp == nil {
+			// Nothing new to send yet.
+			if err := clock.SleepSource(ctx, t.watchPollInterval, t.timeSource); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := getReadTokens(ctx, t.registry.QuotaManager, req.LogId, req.ChargeTo, len(rsp.Leaves)); err != nil {
+			return err
+		}
+		if err := stream.Send(rsp); err != nil {
+			return err
+		}
+
+		var root types.LogRootV1
+		if err := root.UnmarshalBinary(rsp.SignedLogRoot.LogRoot); err != nil {
+			return status.Errorf(codes.Internal, "Could not read current log root: %v", err)
+		}
+		next += int64(len(rsp.Leaves))
+		prevSize = int64(root.TreeSize)
+	}
+	return nil
+}
+
+// nextWatchLeavesBatch reads up to batchSize leaves starting at start, along
+// with the latest root and a consistency proof to it from prevSize. It returns
+// nil if there are no leaves at or beyond start yet.
+func (t *TrillianLogRPCServer) nextWatchLeavesBatch(ctx context.Context, tree *trillian.Tree, hasher hashers.LogHasher, start, batchSize, prevSize int64) (*trillian.WatchLeavesResponse, error) {
+	tx, err := t.snapshotForTree(ctx, tree, "WatchLeaves")
+	if err != nil {
+		return nil, err
+	}
+	defer t.closeAndLog(ctx, tree.TreeId, tx, "WatchLeaves")
+
+	slr, err := tx.LatestSignedLogRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var root types.LogRootV1
+	if err := root.UnmarshalBinary(slr.LogRoot); err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not read current log root: %v", err)
+	}
+	treeSize := int64(root.TreeSize)
+	// The tree may appear smaller than the one we've already sent a root for
+	// if this read hits a lagging replica; wait for it to catch up.
+	if start >= treeSize || treeSize < prevSize {
+		return nil, t.commitAndLog(ctx, tree.TreeId, tx, "WatchLeaves")
+	}
+
+	count := treeSize - start
+	if count > batchSize {
+		count = batchSize
+	}
+	leaves, err := tx.GetLeavesByRange(ctx, start, count)
+	if err != nil {
+		return nil, err
+	}
+	r := &trillian.WatchLeavesResponse{Leaves: leaves, SignedLogRoot: slr}
+
+	if prevSize > 0 && prevSize < treeSize {
+		proof, err := tryGetConsistencyProof(ctx, prevSize, treeSize, treeSize, tx, hasher)
+		if err != nil {
+			return nil, err
+		}
+		r.Proof = proof
+	}
+
+	if err := t.commitAndLog(ctx, tree.TreeId, tx, "WatchLeaves"); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
 func (t *TrillianLogRPCServer) commitAndLog(ctx context.Context, logID int64, tx storage.ReadOnlyLogTreeTX, op string) error {
 	err := tx.Commit(ctx)
 	if err != nil {
@@ -719,6 +1038,19 @@ func getInclusionProofForLeafIndex(ctx context.Context, tx storage.ReadOnlyLogTr
 	return fetchNodesAndBuildProof(ctx, tx, hasher, rev, leafIndex, proofNodeIDs)
 }
 
+// sortedUniqueIndices returns a sorted copy of indices with duplicates removed.
+func sortedUniqueIndices(indices []int64) []int64 {
+	sorted := append([]int64(nil), indices...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	ret := sorted[:0]
+	for _, index := range sorted {
+		if len(ret) == 0 || index != ret[len(ret)-1] {
+			ret = append(ret, index)
+		}
+	}
+	return ret
+}
+
 func (t *TrillianLogRPCServer) getTreeAndHasher(ctx context.Context, treeID int64, opts trees.GetOpts) (*trillian.Tree, hashers.LogHasher, error) {
 	tree, err := trees.GetTree(ctx, t.registry.AdminStorage, treeID, opts)
 	if err != nil {
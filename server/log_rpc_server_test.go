@@ -25,12 +25,16 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/trillian"
+	"github.com/google/trillian/client"
 	"github.com/google/trillian/crypto/keys/pem"
 	"github.com/google/trillian/crypto/sigpb"
 	"github.com/google/trillian/extension"
+	"github.com/google/trillian/merkle"
 	"github.com/google/trillian/merkle/rfc6962"
+	"github.com/google/trillian/quota"
 	"github.com/google/trillian/storage"
 	"github.com/google/trillian/testonly"
 	"github.com/google/trillian/types"
@@ -416,6 +420,237 @@ func TestGetLeavesByRange(t *testing.T) {
 	}
 }
 
+// fakeWatchLeavesServer collects the responses sent on a WatchLeaves stream,
+// and fails the stream once it has seen the expected number of them.
+type fakeWatchLeavesServer struct {
+	trillian.TrillianLog_WatchLeavesServer
+	ctx  context.Context
+	want int
+	got  []*trillian.WatchLeavesResponse
+}
+
+var errStreamDone = errors.New("stream done")
+
+func (f *fakeWatchLeavesServer) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeWatchLeavesServer) Send(rsp *trillian.WatchLeavesResponse) error {
+	f.got = append(f.got, rsp)
+	if len(f.got) >= f.want {
+		return errStreamDone
+	}
+	return nil
+}
+
+func TestWatchLeaves(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	smallRoot, err := fixedSigner.SignLogRoot(&types.LogRootV1{TimestampNanos: 987654320, RootHash: []byte("A SMALL HASH"), TreeSize: 1, Revision: 4})
+	if err != nil {
+		t.Fatalf("SignLogRoot(): %v", err)
+	}
+
+	// The first snapshot has nothing beyond the start index, so the server has
+	// to wait for the tree to grow. The next two serve a batch each.
+	fakeStorage := storage.NewMockLogStorage(ctrl)
+	var calls []*gomock.Call
+	for _, batch := range []struct {
+		root   *trillian.SignedLogRoot
+		start  int64
+		count  int64
+		leaves []*trillian.LogLeaf
+	}{
+		{root: smallRoot},
+		{root: signedRoot1, start: 1, count: 2, leaves: []*trillian.LogLeaf{leaf1, leaf2}},
+		{root: signedRoot1, start: 3, count: 2, leaves: []*trillian.LogLeaf{leaf3}},
+	} {
+		mockTX := storage.NewMockLogTreeTX(ctrl)
+		calls = append(calls, fakeStorage.EXPECT().SnapshotForTree(gomock.Any(), gomock.Any()).Return(mockTX, nil))
+		mockTX.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(batch.root, nil)
+		if batch.leaves != nil {
+			mockTX.EXPECT().GetLeavesByRange(gomock.Any(), batch.start, batch.count).Return(batch.leaves, nil)
+		}
+		mockTX.EXPECT().Commit(gomock.Any()).Return(nil)
+		mockTX.EXPECT().Close().Return(nil)
+	}
+	gomock.InOrder(calls...)
+
+	registry := extension.Registry{
+		AdminStorage: fakeAdminStorage(ctrl, storageParams{treeID: logID1, numSnapshots: 1}),
+		LogStorage:   fakeStorage,
+	}
+	server := NewTrillianLogRPCServer(registry, clock.System)
+	server.watchPollInterval = time.Millisecond
+
+	stream := &fakeWatchLeavesServer{ctx: ctx, want: 2}
+	req := &trillian.WatchLeavesRequest{LogId: logID1, StartIndex: 1, MaxBatchSize: 2}
+	if err := server.WatchLeaves(req, stream); err != errStreamDone {
+		t.Fatalf("WatchLeaves()=%v, want %v", err, errStreamDone)
+	}
+
+	var got []*trillian.LogLeaf
+	for _, rsp := range stream.got {
+		if !proto.Equal(rsp.SignedLogRoot, signedRoot1) {
+			t.Errorf("WatchLeaves() sent root %v, want %v", rsp.SignedLogRoot, signedRoot1)
+		}
+		got = append(got, rsp.Leaves...)
+	}
+	if want := []*trillian.LogLeaf{leaf1, leaf2, leaf3}; !cmp.Equal(got, want, cmp.Comparer(proto.Equal)) {
+		t.Errorf("WatchLeaves() sent leaves %+v, want %+v", got, want)
+	}
+}
+
+func TestWatchLeavesQuota(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeStorage := storage.NewMockLogStorage(ctrl)
+	mockTX := storage.NewMockLogTreeTX(ctrl)
+	fakeStorage.EXPECT().SnapshotForTree(gomock.Any(), gomock.Any()).Return(mockTX, nil)
+	mockTX.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
+	mockTX.EXPECT().GetLeavesByRange(gomock.Any(), int64(1), int64(2)).Return([]*trillian.LogLeaf{leaf1, leaf2}, nil)
+	mockTX.EXPECT().Commit(gomock.Any()).Return(nil)
+	mockTX.EXPECT().Close().Return(nil)
+
+	qm := quota.NewMockManager(ctrl)
+	specs := []quota.Spec{
+		{Group: quota.User, Kind: quota.Read, User: "alice"},
+		{Group: quota.Tree, Kind: quota.Read, TreeID: logID1},
+		{Group: quota.Global, Kind: quota.Read},
+	}
+	qm.EXPECT().GetTokens(gomock.Any(), 2, specs).Return(errors.New("not enough tokens"))
+
+	registry := extension.Registry{
+		AdminStorage: fakeAdminStorage(ctrl, storageParams{treeID: logID1, numSnapshots: 1}),
+		LogStorage:   fakeStorage,
+		QuotaManager: qm,
+	}
+	server := NewTrillianLogRPCServer(registry, clock.System)
+
+	stream := &fakeWatchLeavesServer{ctx: ctx, want: 1}
+	req := &trillian.WatchLeavesRequest{
+		LogId:        logID1,
+		StartIndex:   1,
+		MaxBatchSize: 2,
+		ChargeTo:     &trillian.ChargeTo{User: []string{"alice"}},
+	}
+	if err := server.WatchLeaves(req, stream); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("WatchLeaves()=%v, want code %v", err, codes.ResourceExhausted)
+	}
+	if got := len(stream.got); got != 0 {
+		t.Errorf("WatchLeaves() sent %d responses, want 0", got)
+	}
+}
+
+func TestWatchLeavesMaxDuration(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The stream ends before reading anything from the log.
+	registry := extension.Registry{
+		AdminStorage: fakeAdminStorage(ctrl, storageParams{treeID: logID1, numSnapshots: 1}),
+		LogStorage:   storage.NewMockLogStorage(ctrl),
+	}
+	server := NewTrillianLogRPCServer(registry, clock.System)
+	server.watchMaxDuration = 0
+
+	stream := &fakeWatchLeavesServer{ctx: ctx, want: 1}
+	if err := server.WatchLeaves(&trillian.WatchLeavesRequest{LogId: logID1}, stream); err != nil {
+		t.Errorf("WatchLeaves()=%v, want nil", err)
+	}
+}
+
+func TestGetLeavesByRangeWithProof(t *testing.T) {
+	ctx := context.Background()
+	registry, tree := newTileLog(ctx, t, 21)
+	server := NewTrillianLogRPCServer(registry, fakeTimeSource)
+	v, err := client.NewLogVerifierFromTree(tree)
+	if err != nil {
+		t.Fatalf("NewLogVerifierFromTree(): %v", err)
+	}
+	rsp, err := server.GetLatestSignedLogRoot(ctx, &trillian.GetLatestSignedLogRootRequest{LogId: tree.TreeId})
+	if err != nil {
+		t.Fatalf("GetLatestSignedLogRoot(): %v", err)
+	}
+	latest, err := v.VerifyRoot(&types.LogRootV1{}, rsp.SignedLogRoot, nil)
+	if err != nil {
+		t.Fatalf("VerifyRoot(): %v", err)
+	}
+	// The root of a smaller tree, as a mirror that is catching up would have.
+	mt := merkle.NewInMemoryMerkleTree(rfc6962.DefaultHasher)
+	for i := 0; i < 13; i++ {
+		mt.AddLeaf([]byte(fmt.Sprintf("leaf %d", i)))
+	}
+	older := &types.LogRootV1{TreeSize: 13, RootHash: mt.CurrentRoot().Hash()}
+
+	for _, tc := range []struct {
+		desc                string
+		start, count, size  int64
+		trusted             *types.LogRootV1
+		wantStart, wantSize int64
+		wantLeaves          int
+	}{
+		{desc: "all", start: 0, count: 21, trusted: latest, wantSize: 21, wantLeaves: 21},
+		{desc: "middle", start: 5, count: 7, trusted: latest, wantSize: 21, wantLeaves: 7},
+		{desc: "end", start: 16, count: 100, trusted: latest, wantSize: 21, wantLeaves: 5},
+		{desc: "olderTree", start: 3, count: 100, size: 13, trusted: older, wantSize: 13, wantLeaves: 10},
+		{desc: "olderTreeLastLeaf", start: 12, count: 1, size: 13, trusted: older, wantSize: 13, wantLeaves: 1},
+		{desc: "beyondTree", start: 21, count: 1},
+		{desc: "unknownTreeSize", start: 0, count: 1, size: 22},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			rsp, err := server.GetLeavesByRangeWithProof(ctx, &trillian.GetLeavesByRangeWithProofRequest{
+				LogId:      tree.TreeId,
+				StartIndex: tc.start,
+				Count:      tc.count,
+				TreeSize:   tc.size,
+			})
+			if err != nil {
+				t.Fatalf("GetLeavesByRangeWithProof(): %v", err)
+			}
+			if rsp.SignedLogRoot == nil {
+				t.Error("GetLeavesByRangeWithProof(): no signed log root")
+			}
+			if got, want := len(rsp.Leaves), tc.wantLeaves; got != want {
+				t.Fatalf("GetLeavesByRangeWithProof(): %d leaves, want %d", got, want)
+			}
+			if got, want := rsp.TreeSize, tc.wantSize; got != want {
+				t.Errorf("GetLeavesByRangeWithProof(): tree size %d, want %d", got, want)
+			}
+			if tc.wantLeaves == 0 {
+				return
+			}
+			if err := v.VerifyLeavesByRange(tc.trusted, rsp.Leaves, rsp.LeftHashes, rsp.RightHashes); err != nil {
+				t.Errorf("VerifyLeavesByRange(): %v", err)
+			}
+		})
+	}
+}
+
+func TestWatchLeavesErrors(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		desc string
+		req  *trillian.WatchLeavesRequest
+	}{
+		{desc: "negative start", req: &trillian.WatchLeavesRequest{LogId: logID1, StartIndex: -1}},
+		{desc: "negative first tree size", req: &trillian.WatchLeavesRequest{LogId: logID1, FirstTreeSize: -1}},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			server := NewTrillianLogRPCServer(extension.Registry{}, fakeTimeSource)
+			err := server.WatchLeaves(tc.req, &fakeWatchLeavesServer{ctx: ctx})
+			if got, want := status.Code(err), codes.InvalidArgument; got != want {
+				t.Errorf("WatchLeaves()=%v, want code %v", err, want)
+			}
+		})
+	}
+}
+
 func TestQueueLeavesStorageError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -512,6 +747,88 @@ func TestQueueLeaves(t *testing.T) {
 	}
 }
 
+func TestQueueLeavesSignedEntryTimestamps(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	pubKey, err := pem.UnmarshalPublicKey(stestonly.PublicKeyPEM)
+	if err != nil {
+		t.Fatalf("UnmarshalPublicKey(): %v", err)
+	}
+
+	// The pre-existing leaf carries its original queue timestamp.
+	queuedAt := fakeTime.Add(-time.Hour)
+	existing := proto.Clone(leaf1).(*trillian.LogLeaf)
+	existing.LeafIdentityHash = []byte("existing identity")
+	existing.QueueTimestamp, _ = ptypes.TimestampProto(queuedAt)
+
+	mockStorage := storage.NewMockLogStorage(ctrl)
+	c1 := mockStorage.EXPECT().QueueLeaves(gomock.Any(), tree1, []*trillian.LogLeaf{leaf1}, fakeTime).Return([]*trillian.QueuedLogLeaf{okQueuedLeaf(leaf1)}, nil)
+	mockStorage.EXPECT().QueueLeaves(gomock.Any(), tree1, []*trillian.LogLeaf{leaf1}, fakeTime).After(c1).Return([]*trillian.QueuedLogLeaf{dupeQueuedLeaf(existing)}, nil)
+
+	registry := extension.Registry{
+		AdminStorage: fakeAdminStorage(ctrl, storageParams{treeID: logID1, numSnapshots: 2}),
+		LogStorage:   mockStorage,
+	}
+	server := NewTrillianLogRPCServer(registry, fakeTimeSource)
+
+	for _, test := range []struct {
+		desc     string
+		wantHash []byte
+		wantTime time.Time
+	}{
+		{desc: "new", wantHash: leaf1.LeafIdentityHash, wantTime: fakeTime},
+		{desc: "existing", wantHash: existing.LeafIdentityHash, wantTime: queuedAt},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			rsp, err := server.QueueLeaf(ctx, &trillian.QueueLeafRequest{
+				LogId:                      logID1,
+				Leaf:                       leaf1,
+				ReturnSignedEntryTimestamp: true,
+			})
+			if err != nil {
+				t.Fatalf("QueueLeaf(): %v", err)
+			}
+			et, err := tcrypto.VerifySignedEntryTimestamp(pubKey, crypto.SHA256, rsp.SignedEntryTimestamp)
+			if err != nil {
+				t.Fatalf("VerifySignedEntryTimestamp(): %v", err)
+			}
+			want := &types.EntryTimestampV1{
+				LogID:            uint64(logID1),
+				LeafIdentityHash: test.wantHash,
+				TimestampNanos:   uint64(test.wantTime.UnixNano()),
+			}
+			if diff := pretty.Compare(et, want); diff != "" {
+				t.Errorf("VerifySignedEntryTimestamp() diff:\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestQueueLeavesNoSignedEntryTimestamps(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := storage.NewMockLogStorage(ctrl)
+	mockStorage.EXPECT().QueueLeaves(gomock.Any(), tree1, []*trillian.LogLeaf{leaf1}, fakeTime).Return([]*trillian.QueuedLogLeaf{okQueuedLeaf(leaf1)}, nil)
+
+	registry := extension.Registry{
+		AdminStorage: fakeAdminStorage(ctrl, storageParams{treeID: logID1, numSnapshots: 1}),
+		LogStorage:   mockStorage,
+	}
+	server := NewTrillianLogRPCServer(registry, fakeTimeSource)
+
+	rsp, err := server.QueueLeaf(ctx, &trillian.QueueLeafRequest{LogId: logID1, Leaf: leaf1})
+	if err != nil {
+		t.Fatalf("QueueLeaf(): %v", err)
+	}
+	if rsp.SignedEntryTimestamp != nil {
+		t.Errorf("QueueLeaf().SignedEntryTimestamp = %v, want nil", rsp.SignedEntryTimestamp)
+	}
+}
+
 func TestAddSequencedLeavesStorageError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1252,6 +1569,123 @@ func TestGetProofByIndex(t *testing.T) {
 	}
 }
 
+func TestGetBatchInclusionProof(t *testing.T) {
+	// Leaves 0 and 2 of a tree of size 7 need nodes b, d and l of the tree
+	// pictured in merkle/merkle_path_test.go.
+	req := &trillian.GetBatchInclusionProofRequest{LogId: logID1, TreeSize: 7, LeafIndex: []int64{2, 0, 2}}
+	nodeIDs := []storage.NodeID{
+		stestonly.MustCreateNodeIDForTreeCoords(0, 1, 64),
+		stestonly.MustCreateNodeIDForTreeCoords(0, 3, 64),
+		stestonly.MustCreateNodeIDForTreeCoords(2, 1, 64),
+	}
+
+	for _, tc := range []struct {
+		name         string
+		setupStorage func(*gomock.Controller, *storage.MockLogStorage)
+		req          *trillian.GetBatchInclusionProofRequest
+		errStr       string
+		wantResp     *trillian.GetBatchInclusionProofResponse
+	}{
+		{
+			name: "begin fails",
+			setupStorage: func(_ *gomock.Controller, s *storage.MockLogStorage) {
+				s.EXPECT().SnapshotForTree(gomock.Any(), tree1).Return(nil, errors.New("TX"))
+			},
+			req:    req,
+			errStr: "TX",
+		},
+		{
+			name: "get nodes fails",
+			setupStorage: func(c *gomock.Controller, s *storage.MockLogStorage) {
+				tx := storage.NewMockLogTreeTX(c)
+				s.EXPECT().SnapshotForTree(gomock.Any(), tree1).Return(tx, nil)
+				tx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
+				tx.EXPECT().ReadRevision(gomock.Any()).Return(int64(root1.Revision), nil)
+				tx.EXPECT().GetMerkleNodes(gomock.Any(), revision1, nodeIDs).Return(nil, errors.New("STORAGE"))
+				tx.EXPECT().Close().Return(nil)
+			},
+			req:    req,
+			errStr: "STORAGE",
+		},
+		{
+			name: "commit fails",
+			setupStorage: func(c *gomock.Controller, s *storage.MockLogStorage) {
+				tx := storage.NewMockLogTreeTX(c)
+				s.EXPECT().SnapshotForTree(gomock.Any(), tree1).Return(tx, nil)
+				tx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
+				tx.EXPECT().ReadRevision(gomock.Any()).Return(int64(root1.Revision), nil)
+				tx.EXPECT().GetMerkleNodes(gomock.Any(), revision1, nodeIDs).Return([]storage.Node{{NodeID: nodeIDs[0]}, {NodeID: nodeIDs[1]}, {NodeID: nodeIDs[2]}}, nil)
+				tx.EXPECT().Commit(gomock.Any()).Return(errors.New("COMMIT"))
+				tx.EXPECT().Close().Return(nil)
+			},
+			req:    req,
+			errStr: "COMMIT",
+		},
+		{
+			name: "ok",
+			setupStorage: func(c *gomock.Controller, s *storage.MockLogStorage) {
+				tx := storage.NewMockLogTreeTX(c)
+				s.EXPECT().SnapshotForTree(gomock.Any(), tree1).Return(tx, nil)
+				tx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
+				tx.EXPECT().ReadRevision(gomock.Any()).Return(int64(root1.Revision), nil)
+				tx.EXPECT().GetMerkleNodes(gomock.Any(), revision1, nodeIDs).Return([]storage.Node{
+					{NodeID: nodeIDs[0], NodeRevision: 3, Hash: []byte("nodehash0")},
+					{NodeID: nodeIDs[1], NodeRevision: 2, Hash: []byte("nodehash1")},
+					{NodeID: nodeIDs[2], NodeRevision: 3, Hash: []byte("nodehash2")}}, nil)
+				tx.EXPECT().Commit(gomock.Any()).Return(nil)
+				tx.EXPECT().Close().Return(nil)
+			},
+			req: req,
+			wantResp: &trillian.GetBatchInclusionProofResponse{
+				SignedLogRoot: signedRoot1,
+				LeafIndex:     []int64{0, 2},
+				Hashes: [][]byte{
+					[]byte("nodehash0"),
+					[]byte("nodehash1"),
+					[]byte("nodehash2"),
+				},
+			},
+		},
+		{
+			name: "skew beyond sth",
+			setupStorage: func(c *gomock.Controller, s *storage.MockLogStorage) {
+				tx := storage.NewMockLogTreeTX(c)
+				s.EXPECT().SnapshotForTree(gomock.Any(), tree1).Return(tx, nil)
+				tx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(signedRoot1, nil)
+				tx.EXPECT().Close().Return(nil)
+			},
+			req: &trillian.GetBatchInclusionProofRequest{LogId: logID1, TreeSize: 50, LeafIndex: []int64{25, 3}},
+			wantResp: &trillian.GetBatchInclusionProofResponse{
+				SignedLogRoot: signedRoot1,
+				LeafIndex:     []int64{3, 25},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			fakeStorage := storage.NewMockLogStorage(ctrl)
+			tc.setupStorage(ctrl, fakeStorage)
+			registry := extension.Registry{
+				AdminStorage: fakeAdminStorage(ctrl, storageParams{treeID: leaf0Request.LogId, numSnapshots: 1}),
+				LogStorage:   fakeStorage,
+			}
+			server := NewTrillianLogRPCServer(registry, fakeTimeSource)
+			resp, err := server.GetBatchInclusionProof(context.Background(), tc.req)
+			if len(tc.errStr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.errStr) {
+					t.Errorf("GetBatchInclusionProof(%v)=%v, %v want nil, err containing: %s", tc.req, resp, err, tc.errStr)
+				}
+				return
+			}
+
+			if err != nil || !proto.Equal(tc.wantResp, resp) {
+				t.Errorf("GetBatchInclusionProof(%v)=%v, %v, want: %v, nil", tc.req, resp, err, tc.wantResp)
+			}
+		})
+	}
+}
+
 func TestGetEntryAndProof(t *testing.T) {
 	for _, tc := range []struct {
 		name         string
@@ -1839,6 +2273,39 @@ func TestTrillianLogRPCServer_GetInclusionProofErrors(t *testing.T) {
 	}
 }
 
+func TestTrillianLogRPCServer_GetBatchInclusionProofErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		req  *trillian.GetBatchInclusionProofRequest
+	}{
+		{
+			desc: "noLeafIndex",
+			req:  &trillian.GetBatchInclusionProofRequest{LogId: 1, TreeSize: 20},
+		},
+		{
+			desc: "badLeafIndex",
+			req:  &trillian.GetBatchInclusionProofRequest{LogId: 1, LeafIndex: []int64{1, -10}, TreeSize: 20},
+		},
+		{
+			desc: "badTreeSize",
+			req:  &trillian.GetBatchInclusionProofRequest{LogId: 1, LeafIndex: []int64{10}, TreeSize: -20},
+		},
+		{
+			desc: "indexGreaterThanSize",
+			req:  &trillian.GetBatchInclusionProofRequest{LogId: 1, LeafIndex: []int64{1, 10}, TreeSize: 9},
+		},
+	}
+
+	logServer := NewTrillianLogRPCServer(extension.Registry{}, fakeTimeSource)
+	ctx := context.Background()
+	for _, test := range tests {
+		_, err := logServer.GetBatchInclusionProof(ctx, test.req)
+		if s, ok := status.FromError(err); !ok || s.Code() != codes.InvalidArgument {
+			t.Errorf("%v: GetBatchInclusionProof() returned err = %v, wantCode = %s", test.desc, err, codes.InvalidArgument)
+		}
+	}
+}
+
 func TestTrillianLogRPCServer_GetInclusionProofByHashErrors(t *testing.T) {
 	tests := []struct {
 		desc string
@@ -1953,6 +2420,39 @@ func TestTrillianLogRPCServer_GetLeavesByIndexErrors(t *testing.T) {
 	}
 }
 
+func TestTrillianLogRPCServer_GetLeavesByRangeWithProofErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		req  *trillian.GetLeavesByRangeWithProofRequest
+	}{
+		{
+			desc: "negativeStartIndex",
+			req:  &trillian.GetLeavesByRangeWithProofRequest{LogId: 1, StartIndex: -1, Count: 10},
+		},
+		{
+			desc: "zeroCount",
+			req:  &trillian.GetLeavesByRangeWithProofRequest{LogId: 1, StartIndex: 1},
+		},
+		{
+			desc: "negativeTreeSize",
+			req:  &trillian.GetLeavesByRangeWithProofRequest{LogId: 1, StartIndex: 1, Count: 10, TreeSize: -20},
+		},
+		{
+			desc: "startIndexBeyondTreeSize",
+			req:  &trillian.GetLeavesByRangeWithProofRequest{LogId: 1, StartIndex: 20, Count: 10, TreeSize: 20},
+		},
+	}
+
+	logServer := NewTrillianLogRPCServer(extension.Registry{}, fakeTimeSource)
+	ctx := context.Background()
+	for _, test := range tests {
+		_, err := logServer.GetLeavesByRangeWithProof(ctx, test.req)
+		if s, ok := status.FromError(err); !ok || s.Code() != codes.InvalidArgument {
+			t.Errorf("%v: GetLeavesByRangeWithProof() returned err = %v, wantCode = %s", test.desc, err, codes.InvalidArgument)
+		}
+	}
+}
+
 func TestTrillianLogRPCServer_QueueLeafErrors(t *testing.T) {
 	leafValue := []byte("leaf value")
 
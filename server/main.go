@@ -17,6 +17,11 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"time"
@@ -51,6 +56,10 @@ const (
 	// hard-deleting them.
 	// Actual runs happen randomly between [minInterval,2*minInterval).
 	DefaultTreeDeleteMinInterval = 4 * time.Hour
+
+	// DefaultTreePurgeBatchSize is the suggested maximum number of records of tree data deleted
+	// in a single transaction while hard-deleting a tree.
+	DefaultTreePurgeBatchSize = 1000
 )
 
 // Main encapsulates the data and logic to start a Trillian server (Log or Map).
@@ -61,6 +70,15 @@ type Main struct {
 
 	// TLS Certificate and Key files for the server.
 	TLSCertFile, TLSKeyFile string
+	// TLSClientCAFile holds the CA certificates used to verify client
+	// certificates. If empty, clients aren't asked for certificates.
+	TLSClientCAFile string
+
+	// Authenticator and Authorizer enable auth interception of requests if
+	// Authorizer is set. If Authenticator is nil, callers are identified by
+	// their TLS client certificates only.
+	Authenticator interceptor.Authenticator
+	Authorizer    interceptor.Authorizer
 
 	DBClose func() error
 
@@ -73,6 +91,9 @@ type Main struct {
 	RegisterHandlerFn func(context.Context, *runtime.ServeMux, string, []grpc.DialOption) error
 	// RegisterServerFn is called to register RPC servers.
 	RegisterServerFn func(*grpc.Server, extension.Registry) error
+	// HTTPHandlers are registered on the HTTP server next to the REST-proxy
+	// handlers, keyed by the path pattern they serve.
+	HTTPHandlers map[string]http.Handler
 
 	// IsHealthy will be called whenever "/healthz" is called on the mux.
 	// A nil return value from this function will result in a 200-OK response
@@ -89,6 +110,9 @@ type Main struct {
 	TreeGCEnabled         bool
 	TreeDeleteThreshold   time.Duration
 	TreeDeleteMinInterval time.Duration
+	// TreePurgeBatchSize is the maximum number of records of tree data deleted in a single
+	// transaction by the tree GC. If zero, admin.PurgeBatchSize is left unchanged.
+	TreePurgeBatchSize int
 
 	// These will be added to the GRPC server options.
 	ExtraOptions []grpc.ServerOption
@@ -140,6 +164,9 @@ func (m *Main) Run(ctx context.Context) error {
 		}
 
 		http.Handle("/", gatewayMux)
+		for pattern, handler := range m.HTTPHandlers {
+			http.Handle(pattern, handler)
+		}
 		http.Handle("/metrics", promhttp.Handler())
 		http.HandleFunc("/healthz", m.healthz)
 
@@ -168,6 +195,9 @@ func (m *Main) Run(ctx context.Context) error {
 	go util.AwaitSignal(ctx, srv.Stop)
 
 	if m.TreeGCEnabled {
+		if m.TreePurgeBatchSize > 0 {
+			admin.PurgeBatchSize = m.TreePurgeBatchSize
+		}
 		go func() {
 			glog.Info("Deleted tree GC started")
 			gc := admin.NewDeletedTreeGC(
@@ -196,6 +226,13 @@ func (m *Main) Run(ctx context.Context) error {
 func (m *Main) newGRPCServer() (*grpc.Server, error) {
 	stats := monitoring.NewRPCStatsInterceptor(clock.System, m.StatsPrefix, m.Registry.MetricFactory)
 	ti := interceptor.New(m.Registry.AdminStorage, m.Registry.QuotaManager, m.QuotaDryRun, m.Registry.MetricFactory)
+	if m.Authorizer != nil {
+		authn := m.Authenticator
+		if authn == nil {
+			authn = interceptor.NewAuthenticator(nil)
+		}
+		ti.WithAuth(authn, m.Authorizer)
+	}
 
 	serverOpts := []grpc.ServerOption{
 		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
@@ -203,16 +240,25 @@ func (m *Main) newGRPCServer() (*grpc.Server, error) {
 			interceptor.ErrorWrapper,
 			ti.UnaryInterceptor,
 		)),
+		grpc.StreamInterceptor(ti.StreamInterceptor),
 	}
 	serverOpts = append(serverOpts, m.ExtraOptions...)
 
 	// Let credentials.NewServerTLSFromFile handle the error case when only one of the flags is set.
 	if m.TLSCertFile != "" || m.TLSKeyFile != "" {
-		serverCreds, err := credentials.NewServerTLSFromFile(m.TLSCertFile, m.TLSKeyFile)
+		var serverCreds credentials.TransportCredentials
+		var err error
+		if m.TLSClientCAFile != "" {
+			serverCreds, err = m.newClientAuthTLS()
+		} else {
+			serverCreds, err = credentials.NewServerTLSFromFile(m.TLSCertFile, m.TLSKeyFile)
+		}
 		if err != nil {
 			return nil, err
 		}
 		serverOpts = append(serverOpts, grpc.Creds(serverCreds))
+	} else if m.TLSClientCAFile != "" {
+		return nil, errors.New("client CA file given without a server certificate and key")
 	}
 
 	s := grpc.NewServer(serverOpts...)
@@ -220,6 +266,28 @@ func (m *Main) newGRPCServer() (*grpc.Server, error) {
 	return s, nil
 }
 
+// newClientAuthTLS returns TLS credentials which verify client certificates
+// against TLSClientCAFile, if clients present them.
+func (m *Main) newClientAuthTLS() (credentials.TransportCredentials, error) {
+	cert, err := tls.LoadX509KeyPair(m.TLSCertFile, m.TLSKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	caPEM, err := ioutil.ReadFile(m.TLSClientCAFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificates found in client CA file %q", m.TLSClientCAFile)
+	}
+	return credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.VerifyClientCertIfGiven,
+	}), nil
+}
+
 // AnnounceSelf announces this binary's presence to etcd.  Returns a function that
 // should be called on process exit.
 // AnnounceSelf does nothing if client is nil.
@@ -15,16 +15,22 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"encoding/base64"
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/google/trillian"
+	"github.com/google/trillian/crypto/vrf"
 	"github.com/google/trillian/extension"
 	"github.com/google/trillian/merkle"
 	"github.com/google/trillian/merkle/hashers"
 	"github.com/google/trillian/monitoring"
+	"github.com/google/trillian/server/paging"
 	"github.com/google/trillian/storage"
 	"github.com/google/trillian/trees"
 	"github.com/google/trillian/types"
@@ -45,8 +51,8 @@ var (
 	optsMapWrite = trees.NewGetOpts(trees.UpdateMap, trillian.TreeType_MAP)
 )
 
-// TODO(codingllama): There is no access control in the server yet and clients could easily modify
-// any tree.
+// Access control is not done by the server itself, but by interceptor.TrillianInterceptor
+// when it's configured with an Authorizer. Without one, clients could easily modify any tree.
 
 // TrillianMapServerOptions allows various options to be provided when creating
 // a new TrillianMapServer.
@@ -108,7 +114,67 @@ func (t *TrillianMapServer) IsHealthy() error {
 func (t *TrillianMapServer) GetLeaves(ctx context.Context, req *trillian.GetMapLeavesRequest) (*trillian.GetMapLeavesResponse, error) {
 	ctx, spanEnd := spanFor(ctx, "GetLeaves")
 	defer spanEnd()
-	return t.getLeavesByRevision(ctx, req.MapId, req.Index, mostRecentRevision)
+	resp, err := t.getLeavesByIndexOrKey(ctx, req.MapId, req.Index, req.Key, mostRecentRevision)
+	if err != nil {
+		return nil, err
+	}
+	return compressProofs(resp, req.ProofFormat), nil
+}
+
+// getLeavesByIndexOrKey returns the leaves at indices, or at the indexes the
+// map's VRF derives from the raw keys if any, along with their VRF proofs.
+func (t *TrillianMapServer) getLeavesByIndexOrKey(ctx context.Context, mapID int64, indices, keys [][]byte, revision int64) (*trillian.GetMapLeavesResponse, error) {
+	if len(keys) == 0 {
+		return t.getLeavesByRevision(ctx, mapID, indices, revision)
+	}
+	if len(indices) > 0 {
+		return nil, status.Error(codes.InvalidArgument, "index and key are mutually exclusive")
+	}
+	tree, hasher, err := t.getTreeAndHasher(ctx, mapID, optsMapRead)
+	if err != nil {
+		return nil, fmt.Errorf("could not get map %v: %v", mapID, err)
+	}
+	indices, proofs, err := vrfIndexes(ctx, tree, hasher, keys)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.getLeavesByRevision(ctx, mapID, indices, revision)
+	if err != nil {
+		return nil, err
+	}
+	for i, inclusion := range resp.MapLeafInclusion {
+		inclusion.VrfProof = proofs[i]
+	}
+	return resp, nil
+}
+
+// vrfIndexes derives the indexes of raw keys with the VRF of the map's private
+// key, and returns them along with their VRF proofs.
+func vrfIndexes(ctx context.Context, tree *trillian.Tree, hasher hashers.MapHasher, keys [][]byte) ([][]byte, [][]byte, error) {
+	signer, err := trees.Signer(ctx, tree)
+	if err != nil {
+		return nil, nil, fmt.Errorf("trees.Signer(): %v", err)
+	}
+	priv, ok := signer.Signer.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, nil, status.Errorf(codes.FailedPrecondition, "map %v has a %T key, VRF requires an ECDSA P-256 key", tree.TreeId, signer.Signer)
+	}
+	if hasher.Size() > vrf.OutputSize {
+		return nil, nil, status.Errorf(codes.FailedPrecondition, "map %v has %d byte indexes, VRF outputs are %d bytes", tree.TreeId, hasher.Size(), vrf.OutputSize)
+	}
+	indexes := make([][]byte, 0, len(keys))
+	proofs := make([][]byte, 0, len(keys))
+	for _, key := range keys {
+		beta, pi, err := vrf.Prove(priv, key)
+		if err == vrf.ErrUnsupportedKey {
+			return nil, nil, status.Errorf(codes.FailedPrecondition, "map %v: %v", tree.TreeId, err)
+		} else if err != nil {
+			return nil, nil, err
+		}
+		indexes = append(indexes, beta[:hasher.Size()])
+		proofs = append(proofs, pi)
+	}
+	return indexes, proofs, nil
 }
 
 // GetLeaf returns an inclusion proof to the leaf, or nil if the leaf does not exist.
@@ -152,7 +218,11 @@ func (t *TrillianMapServer) GetLeavesByRevision(ctx context.Context, req *trilli
 	if req.Revision < 0 {
 		return nil, fmt.Errorf("map revision %d must be >= 0", req.Revision)
 	}
-	return t.getLeavesByRevision(ctx, req.MapId, req.Index, req.Revision)
+	resp, err := t.getLeavesByIndexOrKey(ctx, req.MapId, req.Index, req.Key, req.Revision)
+	if err != nil {
+		return nil, err
+	}
+	return compressProofs(resp, req.ProofFormat), nil
 }
 
 // GetLeavesByRevisionNoProof implements the GetLeavesByRevision RPC method.
@@ -178,6 +248,10 @@ func (t *TrillianMapServer) GetLeavesByRevisionNoProof(ctx context.Context, req
 	}
 	defer t.closeAndLog(ctx, tree.TreeId, tx, "GetLeavesByRevisionNoProof")
 
+	// Check that the revision exists, and hasn't been garbage collected.
+	if _, err := tx.GetSignedMapRoot(ctx, req.Revision); err != nil {
+		return nil, status.Errorf(status.Code(err), "could not fetch SignedMapRoot %v: %v", req.Revision, err)
+	}
 	leaves, err := tx.Get(ctx, req.Revision, req.Index)
 	if err != nil {
 		return nil, err
@@ -191,6 +265,245 @@ func (t *TrillianMapServer) GetLeavesByRevisionNoProof(ctx context.Context, req
 	return &trillian.MapLeaves{Leaves: leaves}, nil
 }
 
+// ListLeavesByRevision implements the ListLeavesByRevision RPC method.
+func (t *TrillianMapServer) ListLeavesByRevision(ctx context.Context, req *trillian.ListMapLeavesByRevisionRequest) (*trillian.ListMapLeavesByRevisionResponse, error) {
+	ctx, spanEnd := spanFor(ctx, "ListLeavesByRevision")
+	defer spanEnd()
+	if req.Revision < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "map revision %d must be >= 0", req.Revision)
+	}
+	pageSize, err := paging.MapPageSize(req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	tree, hasher, err := t.getTreeAndHasher(ctx, req.MapId, optsMapRead)
+	if err != nil {
+		return nil, fmt.Errorf("could not get map %v: %v", req.MapId, err)
+	}
+	start, end, err := listLeavesRange(req, hasher)
+	if err != nil {
+		return nil, err
+	}
+	if end != nil && bytes.Compare(start, end) >= 0 {
+		return &trillian.ListMapLeavesByRevisionResponse{}, nil
+	}
+
+	ctx = trees.NewContext(ctx, tree)
+	tx, err := t.snapshotForTree(ctx, tree, "ListLeavesByRevision")
+	if err != nil {
+		return nil, fmt.Errorf("could not create database snapshot: %v", err)
+	}
+	defer t.closeAndLog(ctx, tree.TreeId, tx, "ListLeavesByRevision")
+
+	// Listing the leaves of a revision which doesn't exist yet would return
+	// the leaves of the latest one.
+	if _, err := tx.GetSignedMapRoot(ctx, req.Revision); err != nil {
+		return nil, err
+	}
+	leaves, err := tx.ListLeaves(ctx, req.Revision, start, end, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		glog.Warningf("%v: Commit failed for ListLeavesByRevision: %v", req.MapId, err)
+		return nil, err
+	}
+
+	// Remove LeafHash because SetLeaves does not supply it.
+	for _, l := range leaves {
+		l.LeafHash = nil
+	}
+	resp := &trillian.ListMapLeavesByRevisionResponse{Leaves: leaves}
+	if len(leaves) == pageSize {
+		resp.NextPageToken = nextPageToken(leaves[len(leaves)-1].Index, end)
+	}
+	return resp, nil
+}
+
+// GetRevisionDiff implements the GetRevisionDiff RPC method.
+func (t *TrillianMapServer) GetRevisionDiff(ctx context.Context, req *trillian.GetRevisionDiffRequest) (*trillian.GetRevisionDiffResponse, error) {
+	ctx, spanEnd := spanFor(ctx, "GetRevisionDiff")
+	defer spanEnd()
+	if req.FromRevision < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "from_revision %d must be >= 0", req.FromRevision)
+	}
+	if req.ToRevision <= req.FromRevision {
+		return nil, status.Errorf(codes.InvalidArgument, "to_revision %d must be > from_revision %d", req.ToRevision, req.FromRevision)
+	}
+	pageSize, err := paging.MapPageSize(req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	tree, hasher, err := t.getTreeAndHasher(ctx, req.MapId, optsMapRead)
+	if err != nil {
+		return nil, fmt.Errorf("could not get map %v: %v", req.MapId, err)
+	}
+	var start []byte
+	if req.PageToken != "" {
+		if start, err = parsePageToken(req.PageToken, hasher); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx = trees.NewContext(ctx, tree)
+	tx, err := t.snapshotForTree(ctx, tree, "GetRevisionDiff")
+	if err != nil {
+		return nil, fmt.Errorf("could not create database snapshot: %v", err)
+	}
+	defer t.closeAndLog(ctx, tree.TreeId, tx, "GetRevisionDiff")
+
+	fromRoot, err := tx.GetSignedMapRoot(ctx, req.FromRevision)
+	if err != nil {
+		return nil, status.Errorf(status.Code(err), "could not fetch SignedMapRoot %v: %v", req.FromRevision, err)
+	}
+	toRoot, err := tx.GetSignedMapRoot(ctx, req.ToRevision)
+	if err != nil {
+		return nil, status.Errorf(status.Code(err), "could not fetch SignedMapRoot %v: %v", req.ToRevision, err)
+	}
+	keys, err := tx.ListChangedKeys(ctx, req.FromRevision, req.ToRevision, start, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		glog.Warningf("%v: Commit failed for GetRevisionDiff: %v", req.MapId, err)
+		return nil, err
+	}
+
+	resp := &trillian.GetRevisionDiffResponse{
+		FromMapRoot: fromRoot,
+		ToMapRoot:   toRoot,
+		Diffs:       make([]*trillian.MapLeafDiff, 0, len(keys)),
+	}
+	for _, key := range keys {
+		resp.Diffs = append(resp.Diffs, &trillian.MapLeafDiff{Index: key})
+	}
+	if req.IncludeValues && len(keys) > 0 {
+		from, err := t.getLeavesByRevision(ctx, req.MapId, keys, req.FromRevision)
+		if err != nil {
+			return nil, err
+		}
+		to, err := t.getLeavesByRevision(ctx, req.MapId, keys, req.ToRevision)
+		if err != nil {
+			return nil, err
+		}
+		for i, diff := range resp.Diffs {
+			diff.FromLeaf = from.MapLeafInclusion[i]
+			diff.ToLeaf = to.MapLeafInclusion[i]
+		}
+	}
+	if len(keys) == pageSize {
+		resp.NextPageToken = nextPageToken(keys[len(keys)-1], nil)
+	}
+	return resp, nil
+}
+
+// GetLeafHistory implements the GetLeafHistory RPC method.
+func (t *TrillianMapServer) GetLeafHistory(ctx context.Context, req *trillian.GetLeafHistoryRequest) (*trillian.GetLeafHistoryResponse, error) {
+	ctx, spanEnd := spanFor(ctx, "GetLeafHistory")
+	defer spanEnd()
+	if req.FromRevision < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "from_revision %d must be >= 0", req.FromRevision)
+	}
+	if req.ToRevision < req.FromRevision {
+		return nil, status.Errorf(codes.InvalidArgument, "to_revision %d must be >= from_revision %d", req.ToRevision, req.FromRevision)
+	}
+	pageSize, err := paging.LeafHistoryPageSize(req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	tree, hasher, err := t.getTreeAndHasher(ctx, req.MapId, optsMapRead)
+	if err != nil {
+		return nil, fmt.Errorf("could not get map %v: %v", req.MapId, err)
+	}
+	if err := checkIndexSize(req.Index, hasher); err != nil {
+		return nil, err
+	}
+	// The page token is the last revision considered by the previous page.
+	after := req.FromRevision
+	if req.PageToken != "" {
+		after, err = strconv.ParseInt(req.PageToken, 10, 64)
+		if err != nil || after < req.FromRevision || after >= req.ToRevision {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page_token %q", req.PageToken)
+		}
+	}
+
+	ctx = trees.NewContext(ctx, tree)
+	tx, err := t.snapshotForTree(ctx, tree, "GetLeafHistory")
+	if err != nil {
+		return nil, fmt.Errorf("could not create database snapshot: %v", err)
+	}
+	defer t.closeAndLog(ctx, tree.TreeId, tx, "GetLeafHistory")
+
+	if _, err := tx.GetSignedMapRoot(ctx, req.ToRevision); err != nil {
+		return nil, status.Errorf(status.Code(err), "could not fetch SignedMapRoot %v: %v", req.ToRevision, err)
+	}
+	var prev []byte
+	if req.PageToken != "" {
+		// Later pages only return changes from the value at the last revision
+		// considered by the previous page.
+		leaves, err := tx.Get(ctx, after, [][]byte{req.Index})
+		if err != nil {
+			return nil, err
+		}
+		if len(leaves) > 0 {
+			prev = leaves[0].LeafValue
+		}
+	}
+	changed, err := tx.ListLeafRevisions(ctx, req.Index, after, req.ToRevision, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		glog.Warningf("%v: Commit failed for GetLeafHistory: %v", req.MapId, err)
+		return nil, err
+	}
+
+	resp := &trillian.GetLeafHistoryResponse{}
+	revs := changed
+	if req.PageToken == "" {
+		revs = append([]int64{req.FromRevision}, changed...)
+	}
+	for i, rev := range revs {
+		leaves, err := t.getLeafVersion(ctx, tree, hasher, req.Index, rev)
+		if err != nil {
+			return nil, err
+		}
+		leaf := leaves.MapLeafInclusion[0]
+		if i > 0 || req.PageToken != "" {
+			// A leaf which was set to the value it already had hasn't changed.
+			if bytes.Equal(leaf.GetLeaf().GetLeafValue(), prev) {
+				continue
+			}
+		}
+		prev = leaf.GetLeaf().GetLeafValue()
+		resp.Versions = append(resp.Versions, &trillian.MapLeafVersion{MapRoot: leaves.MapRoot, Leaf: leaf})
+	}
+	if n := len(changed); n == pageSize && changed[n-1] < req.ToRevision {
+		resp.NextPageToken = strconv.FormatInt(changed[n-1], 10)
+	}
+	return resp, nil
+}
+
+// getLeafVersion returns the leaf at index, with its inclusion proof, in the
+// map root of revision. The subtree cache of a transaction only holds the
+// nodes of a single revision, so each version is read in a snapshot of its own.
+func (t *TrillianMapServer) getLeafVersion(ctx context.Context, tree *trillian.Tree, hasher hashers.MapHasher, index []byte, revision int64) (*trillian.GetMapLeavesResponse, error) {
+	tx, err := t.snapshotForTree(ctx, tree, "GetLeafHistory")
+	if err != nil {
+		return nil, fmt.Errorf("could not create database snapshot: %v", err)
+	}
+	defer t.closeAndLog(ctx, tree.TreeId, tx, "GetLeafHistory")
+
+	resp, err := getLeavesInTX(ctx, tx, tree.TreeId, hasher, [][]byte{index}, revision)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("could not commit db transaction: %v", err)
+	}
+	return resp, nil
+}
+
 func (t *TrillianMapServer) getLeavesByRevision(ctx context.Context, mapID int64, indices [][]byte, revision int64) (*trillian.GetMapLeavesResponse, error) {
 	if err := hasDuplicates(indices); err != nil {
 		return nil, err
@@ -214,6 +527,20 @@ func (t *TrillianMapServer) getLeavesByRevision(ctx context.Context, mapID int64
 	}
 	defer t.closeAndLog(ctx, tree.TreeId, tx, "GetLeavesByRevision")
 
+	resp, err := getLeavesInTX(ctx, tx, mapID, hasher, indices, revision)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("could not commit db transaction: %v", err)
+	}
+	return resp, nil
+}
+
+// getLeavesInTX returns the leaves at indices, and their inclusion proofs, in
+// the map root of revision read from tx, or of the latest revision if revision
+// is negative.
+func getLeavesInTX(ctx context.Context, tx storage.ReadOnlyMapTreeTX, mapID int64, hasher hashers.MapHasher, indices [][]byte, revision int64) (*trillian.GetMapLeavesResponse, error) {
 	var root *trillian.SignedMapRoot
 	if revision < 0 {
 		// need to know the newest published revision
@@ -225,7 +552,7 @@ func (t *TrillianMapServer) getLeavesByRevision(ctx context.Context, mapID int64
 	} else {
 		r, err := tx.GetSignedMapRoot(ctx, revision)
 		if err != nil {
-			return nil, fmt.Errorf("could not fetch SignedMapRoot %v: %v", revision, err)
+			return nil, status.Errorf(status.Code(err), "could not fetch SignedMapRoot %v: %v", revision, err)
 		}
 		root = r
 	}
@@ -297,10 +624,6 @@ func (t *TrillianMapServer) getLeavesByRevision(ctx context.Context, mapID int64
 		}
 	}
 
-	if err := tx.Commit(ctx); err != nil {
-		return nil, fmt.Errorf("could not commit db transaction: %v", err)
-	}
-
 	inclusions := make([]*trillian.MapLeafInclusion, len(indices))
 	for i, index := range indices {
 		inclusions[i] = &trillian.MapLeafInclusion{
@@ -315,6 +638,33 @@ func (t *TrillianMapServer) getLeavesByRevision(ctx context.Context, mapID int64
 	}, nil
 }
 
+// compressProofs converts the inclusion proofs of resp to the requested
+// format. Proofs are left in MAP_PROOF_FORMAT_FULL if the format is unknown.
+func compressProofs(resp *trillian.GetMapLeavesResponse, format trillian.MapProofFormat) *trillian.GetMapLeavesResponse {
+	switch format {
+	case trillian.MapProofFormat_MAP_PROOF_FORMAT_COMPRESSED:
+		for _, inc := range resp.MapLeafInclusion {
+			inc.CompressedInclusion = merkle.CompressMapInclusionProof(inc.Inclusion)
+			inc.Inclusion = nil
+		}
+	case trillian.MapProofFormat_MAP_PROOF_FORMAT_COMPRESSED_BATCH:
+		proofs := make([][][]byte, 0, len(resp.MapLeafInclusion))
+		for _, inc := range resp.MapLeafInclusion {
+			proofs = append(proofs, inc.Inclusion)
+		}
+		compressed, proofHashes := merkle.CompressMapInclusionProofs(proofs)
+		for i, inc := range resp.MapLeafInclusion {
+			inc.CompressedInclusion = compressed[i]
+			inc.Inclusion = nil
+		}
+		resp.ProofHashes = proofHashes
+	default:
+		return resp
+	}
+	resp.ProofFormat = format
+	return resp
+}
+
 func checkIndexSize(index []byte, hasher hashers.MapHasher) error {
 	// The parameter is named 'index' (here and in the RPC API) because it's the ordinal number
 	// of the leaf, but that number is obtained by hashing the key value that corresponds to the
@@ -330,8 +680,103 @@ func checkIndexSize(index []byte, hasher hashers.MapHasher) error {
 	return nil
 }
 
+// listLeavesRange returns the range [start, end) of the indexes listed by a
+// ListLeavesByRevision request, where a nil end means that the range is
+// unbounded above. If the request has a page token, the range starts there.
+func listLeavesRange(req *trillian.ListMapLeavesByRevisionRequest, hasher hashers.MapHasher) ([]byte, []byte, error) {
+	size := hasher.Size()
+	var start, end []byte
+	switch {
+	case req.PrefixBits < 0 || int(req.PrefixBits) > size*8:
+		return nil, nil, status.Errorf(codes.InvalidArgument, "prefix_bits %d is not in [0, %d]", req.PrefixBits, size*8)
+	case req.PrefixBits > 0:
+		if len(req.StartIndex) > 0 || len(req.EndIndex) > 0 {
+			return nil, nil, status.Error(codes.InvalidArgument, "prefix can't be combined with start_index or end_index")
+		}
+		if len(req.Prefix)*8 < int(req.PrefixBits) {
+			return nil, nil, status.Errorf(codes.InvalidArgument, "prefix len(%x) is too short for %d prefix_bits", req.Prefix, req.PrefixBits)
+		}
+		start = make([]byte, size)
+		copy(start, req.Prefix[:(req.PrefixBits+7)/8])
+		if rem := req.PrefixBits % 8; rem != 0 {
+			start[req.PrefixBits/8] &= byte(0xff << uint(8-rem))
+		}
+		end = incrementIndex(start, int(req.PrefixBits))
+	case len(req.Prefix) > 0:
+		return nil, nil, status.Error(codes.InvalidArgument, "prefix is set but prefix_bits is 0")
+	default:
+		if len(req.StartIndex) > 0 {
+			if err := checkIndexSize(req.StartIndex, hasher); err != nil {
+				return nil, nil, err
+			}
+			start = req.StartIndex
+		}
+		if len(req.EndIndex) > 0 {
+			if err := checkIndexSize(req.EndIndex, hasher); err != nil {
+				return nil, nil, err
+			}
+			end = req.EndIndex
+		}
+	}
+
+	if req.PageToken != "" {
+		next, err := parsePageToken(req.PageToken, hasher)
+		if err != nil {
+			return nil, nil, err
+		}
+		if bytes.Compare(next, start) < 0 || (end != nil && bytes.Compare(next, end) >= 0) {
+			return nil, nil, status.Errorf(codes.InvalidArgument, "page_token %q is out of range", req.PageToken)
+		}
+		start = next
+	}
+	return start, end, nil
+}
+
+// parsePageToken returns the index at which the page requested by a
+// page_token starts.
+func parsePageToken(token string, hasher hashers.MapHasher) ([]byte, error) {
+	index, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(index) != hasher.Size() {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid page_token %q", token)
+	}
+	return index, nil
+}
+
+// nextPageToken returns the page_token of the page which follows a full page
+// ending at index last, or "" if there are no more indexes before end, where a
+// nil end means that there is no bound.
+func nextPageToken(last, end []byte) string {
+	next := incrementIndex(last, len(last)*8)
+	if next == nil || (end != nil && bytes.Compare(next, end) >= 0) {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(next)
+}
+
+// incrementIndex returns a copy of index, interpreted as a big-endian number,
+// plus one at the given bit, where bit 1 is the MSB. It returns nil if the
+// result overflows.
+func incrementIndex(index []byte, bit int) []byte {
+	ret := append([]byte{}, index...)
+	i := (bit - 1) / 8
+	add := byte(1 << uint(7-(bit-1)%8))
+	for ; i >= 0; i-- {
+		ret[i] += add
+		if ret[i] >= add {
+			return ret
+		}
+		add = 1
+	}
+	return nil
+}
+
 // SetLeaves implements the SetLeaves RPC method.
 func (t *TrillianMapServer) SetLeaves(ctx context.Context, req *trillian.SetMapLeavesRequest) (*trillian.SetMapLeavesResponse, error) {
+	if len(req.Keys) > 0 {
+		if err := t.setLeafIndexes(ctx, req); err != nil {
+			return nil, err
+		}
+	}
 	indexes := make([][]byte, 0, len(req.Leaves))
 	for _, l := range req.Leaves {
 		indexes = append(indexes, l.Index)
@@ -416,6 +861,31 @@ func (t *TrillianMapServer) SetLeaves(ctx context.Context, req *trillian.SetMapL
 	return &trillian.SetMapLeavesResponse{MapRoot: newRoot}, nil
 }
 
+// setLeafIndexes sets the index of each leaf of req to the one the map's VRF
+// derives from its raw key.
+func (t *TrillianMapServer) setLeafIndexes(ctx context.Context, req *trillian.SetMapLeavesRequest) error {
+	if got, want := len(req.Keys), len(req.Leaves); got != want {
+		return status.Errorf(codes.InvalidArgument, "got %d keys for %d leaves", got, want)
+	}
+	for i, l := range req.Leaves {
+		if len(l.Index) > 0 {
+			return status.Errorf(codes.InvalidArgument, "leaf %d has both a key and an index", i)
+		}
+	}
+	tree, hasher, err := t.getTreeAndHasher(ctx, req.MapId, optsMapWrite)
+	if err != nil {
+		return err
+	}
+	indexes, _, err := vrfIndexes(ctx, tree, hasher, req.Keys)
+	if err != nil {
+		return err
+	}
+	for i, l := range req.Leaves {
+		l.Index = indexes[i]
+	}
+	return nil
+}
+
 func (t *TrillianMapServer) newTXRunner(tree *trillian.Tree, tx storage.MapTreeTX) merkle.TXRunner {
 	if t.opts.UseSingleTransaction {
 		return &singleTXRunner{tx: tx}
@@ -15,20 +15,29 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"errors"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/google/trillian"
+	"github.com/google/trillian/crypto/keys/der"
+	"github.com/google/trillian/crypto/keyspb"
+	"github.com/google/trillian/crypto/sigpb"
 	"github.com/google/trillian/extension"
+	"github.com/google/trillian/server/paging"
 	"github.com/google/trillian/storage"
 	"github.com/kylelemons/godebug/pretty"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	stestonly "github.com/google/trillian/storage/testonly"
+
+	_ "github.com/google/trillian/crypto/keys/der/proto" // PrivateKey proto handler
 )
 
 const mapID1 = int64(1)
@@ -337,16 +346,483 @@ func TestGetSignedMapRootByRevision(t *testing.T) {
 	}
 }
 
+func TestListLeavesByRevision(t *testing.T) {
+	ctx := context.Background()
+	index := func(b ...byte) []byte {
+		idx := make([]byte, 32)
+		copy(idx, b)
+		return idx
+	}
+	leaves := func(bs ...byte) []*trillian.MapLeaf {
+		var ret []*trillian.MapLeaf
+		for _, b := range bs {
+			ret = append(ret, &trillian.MapLeaf{Index: index(b), LeafValue: []byte{b}, LeafHash: []byte("hash")})
+		}
+		return ret
+	}
+	maxIndex := bytes.Repeat([]byte{0xff}, 32)
+
+	tests := []struct {
+		desc       string
+		req        *trillian.ListMapLeavesByRevisionRequest
+		start, end []byte
+		limit      int
+		leaves     []*trillian.MapLeaf
+		wantToken  string
+		wantCode   codes.Code
+	}{
+		{
+			desc:   "all",
+			req:    &trillian.ListMapLeavesByRevisionRequest{MapId: mapID1, Revision: 1},
+			limit:  paging.DefaultMapPageSize,
+			leaves: leaves(1, 2),
+		},
+		{
+			desc:      "page",
+			req:       &trillian.ListMapLeavesByRevisionRequest{MapId: mapID1, Revision: 1, PageSize: 2},
+			limit:     2,
+			leaves:    leaves(1, 2),
+			wantToken: base64.RawURLEncoding.EncodeToString(incrementIndex(index(2), 256)),
+		},
+		{
+			desc:   "lastPage",
+			req:    &trillian.ListMapLeavesByRevisionRequest{MapId: mapID1, Revision: 1, PageSize: 1},
+			limit:  1,
+			leaves: []*trillian.MapLeaf{{Index: maxIndex}},
+		},
+		{
+			desc:   "maxPageSize",
+			req:    &trillian.ListMapLeavesByRevisionRequest{MapId: mapID1, Revision: 1, PageSize: paging.MaxMapPageSize + 1},
+			limit:  paging.MaxMapPageSize,
+			leaves: leaves(1),
+		},
+		{
+			desc:   "range",
+			req:    &trillian.ListMapLeavesByRevisionRequest{MapId: mapID1, Revision: 1, StartIndex: index(1), EndIndex: index(3), PageSize: 1},
+			start:  index(1),
+			end:    index(3),
+			limit:  1,
+			leaves: leaves(2),
+			// The next index, 0x0200...01, is still in the range.
+			wantToken: base64.RawURLEncoding.EncodeToString(incrementIndex(index(2), 256)),
+		},
+		{
+			desc:   "rangeEnd",
+			req:    &trillian.ListMapLeavesByRevisionRequest{MapId: mapID1, Revision: 1, EndIndex: incrementIndex(index(2), 256), PageSize: 1},
+			end:    incrementIndex(index(2), 256),
+			limit:  1,
+			leaves: leaves(2),
+		},
+		{
+			desc:   "prefix",
+			req:    &trillian.ListMapLeavesByRevisionRequest{MapId: mapID1, Revision: 1, Prefix: []byte{0x12, 0x3f}, PrefixBits: 12},
+			start:  index(0x12, 0x30),
+			end:    index(0x12, 0x40),
+			limit:  paging.DefaultMapPageSize,
+			leaves: leaves(),
+		},
+		{
+			desc:   "pageToken",
+			req:    &trillian.ListMapLeavesByRevisionRequest{MapId: mapID1, Revision: 1, Prefix: []byte{0x12}, PrefixBits: 8, PageToken: base64.RawURLEncoding.EncodeToString(index(0x12, 0x34))},
+			start:  index(0x12, 0x34),
+			end:    index(0x13),
+			limit:  paging.DefaultMapPageSize,
+			leaves: leaves(),
+		},
+		{
+			desc:     "negativeRevision",
+			req:      &trillian.ListMapLeavesByRevisionRequest{MapId: mapID1, Revision: -1},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "negativePageSize",
+			req:      &trillian.ListMapLeavesByRevisionRequest{MapId: mapID1, PageSize: -1},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "prefixAndRange",
+			req:      &trillian.ListMapLeavesByRevisionRequest{MapId: mapID1, Prefix: []byte{1}, PrefixBits: 8, StartIndex: index(1)},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "prefixTooShort",
+			req:      &trillian.ListMapLeavesByRevisionRequest{MapId: mapID1, Prefix: []byte{1}, PrefixBits: 9},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "prefixWithoutBits",
+			req:      &trillian.ListMapLeavesByRevisionRequest{MapId: mapID1, Prefix: []byte{1}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "wrongIndexSize",
+			req:      &trillian.ListMapLeavesByRevisionRequest{MapId: mapID1, StartIndex: []byte{1}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "pageTokenOutOfRange",
+			req:      &trillian.ListMapLeavesByRevisionRequest{MapId: mapID1, EndIndex: index(2), PageToken: base64.RawURLEncoding.EncodeToString(index(3))},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "badPageToken",
+			req:      &trillian.ListMapLeavesByRevisionRequest{MapId: mapID1, PageToken: "!"},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			fakeStorage := storage.NewMockMapStorage(ctrl)
+			if test.wantCode == codes.OK {
+				mockTX := storage.NewMockMapTreeTX(ctrl)
+				fakeStorage.EXPECT().SnapshotForTree(gomock.Any(), gomock.Any()).Return(mockTX, nil)
+				mockTX.EXPECT().GetSignedMapRoot(gomock.Any(), test.req.Revision).Return(&trillian.SignedMapRoot{}, nil)
+				mockTX.EXPECT().ListLeaves(gomock.Any(), test.req.Revision, test.start, test.end, test.limit).Return(test.leaves, nil)
+				mockTX.EXPECT().Commit(gomock.Any()).Return(nil)
+				mockTX.EXPECT().Close().Return(nil)
+				mockTX.EXPECT().IsOpen().AnyTimes().Return(false)
+			}
+
+			server := NewTrillianMapServer(extension.Registry{
+				AdminStorage: fakeAdminStorageForMap(ctrl, 1, mapID1),
+				MapStorage:   fakeStorage,
+			}, TrillianMapServerOptions{})
+
+			resp, err := server.ListLeavesByRevision(ctx, test.req)
+			if got := status.Code(err); got != test.wantCode {
+				t.Fatalf("ListLeavesByRevision()=_, %v, want code %v", err, test.wantCode)
+			}
+			if err != nil {
+				return
+			}
+			for _, leaf := range resp.Leaves {
+				if leaf.LeafHash != nil {
+					t.Errorf("ListLeavesByRevision(): leaf %x has LeafHash set", leaf.Index)
+				}
+			}
+			if got, want := len(resp.Leaves), len(test.leaves); got != want {
+				t.Errorf("ListLeavesByRevision(): %d leaves, want %d", got, want)
+			}
+			if got, want := resp.NextPageToken, test.wantToken; got != want {
+				t.Errorf("ListLeavesByRevision(): next_page_token %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestGetRevisionDiff(t *testing.T) {
+	ctx := context.Background()
+	index := func(b byte) []byte {
+		idx := make([]byte, 32)
+		idx[0] = b
+		return idx
+	}
+
+	tests := []struct {
+		desc      string
+		req       *trillian.GetRevisionDiffRequest
+		start     []byte
+		limit     int
+		keys      [][]byte
+		wantToken string
+		wantCode  codes.Code
+	}{
+		{
+			desc:  "all",
+			req:   &trillian.GetRevisionDiffRequest{MapId: mapID1, FromRevision: 1, ToRevision: 3},
+			limit: paging.DefaultMapPageSize,
+			keys:  [][]byte{index(1), index(2)},
+		},
+		{
+			desc:      "page",
+			req:       &trillian.GetRevisionDiffRequest{MapId: mapID1, FromRevision: 1, ToRevision: 3, PageSize: 2},
+			limit:     2,
+			keys:      [][]byte{index(1), index(2)},
+			wantToken: base64.RawURLEncoding.EncodeToString(incrementIndex(index(2), 256)),
+		},
+		{
+			desc:  "pageToken",
+			req:   &trillian.GetRevisionDiffRequest{MapId: mapID1, FromRevision: 1, ToRevision: 3, PageToken: base64.RawURLEncoding.EncodeToString(index(2))},
+			start: index(2),
+			limit: paging.DefaultMapPageSize,
+			keys:  [][]byte{index(2)},
+		},
+		{
+			desc:     "negativeFromRevision",
+			req:      &trillian.GetRevisionDiffRequest{MapId: mapID1, FromRevision: -1, ToRevision: 3},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "toBeforeFrom",
+			req:      &trillian.GetRevisionDiffRequest{MapId: mapID1, FromRevision: 3, ToRevision: 3},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "negativePageSize",
+			req:      &trillian.GetRevisionDiffRequest{MapId: mapID1, FromRevision: 1, ToRevision: 3, PageSize: -1},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "badPageToken",
+			req:      &trillian.GetRevisionDiffRequest{MapId: mapID1, FromRevision: 1, ToRevision: 3, PageToken: "AAAA"},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			fakeStorage := storage.NewMockMapStorage(ctrl)
+			if test.wantCode == codes.OK {
+				mockTX := storage.NewMockMapTreeTX(ctrl)
+				fakeStorage.EXPECT().SnapshotForTree(gomock.Any(), gomock.Any()).Return(mockTX, nil)
+				mockTX.EXPECT().GetSignedMapRoot(gomock.Any(), test.req.FromRevision).Return(&trillian.SignedMapRoot{MapRoot: []byte("from")}, nil)
+				mockTX.EXPECT().GetSignedMapRoot(gomock.Any(), test.req.ToRevision).Return(&trillian.SignedMapRoot{MapRoot: []byte("to")}, nil)
+				mockTX.EXPECT().ListChangedKeys(gomock.Any(), test.req.FromRevision, test.req.ToRevision, test.start, test.limit).Return(test.keys, nil)
+				mockTX.EXPECT().Commit(gomock.Any()).Return(nil)
+				mockTX.EXPECT().Close().Return(nil)
+				mockTX.EXPECT().IsOpen().AnyTimes().Return(false)
+			}
+
+			server := NewTrillianMapServer(extension.Registry{
+				AdminStorage: fakeAdminStorageForMap(ctrl, 1, mapID1),
+				MapStorage:   fakeStorage,
+			}, TrillianMapServerOptions{})
+
+			resp, err := server.GetRevisionDiff(ctx, test.req)
+			if got := status.Code(err); got != test.wantCode {
+				t.Fatalf("GetRevisionDiff()=_, %v, want code %v", err, test.wantCode)
+			}
+			if err != nil {
+				return
+			}
+			if got, want := string(resp.FromMapRoot.MapRoot), "from"; got != want {
+				t.Errorf("GetRevisionDiff(): from_map_root %q, want %q", got, want)
+			}
+			if got, want := string(resp.ToMapRoot.MapRoot), "to"; got != want {
+				t.Errorf("GetRevisionDiff(): to_map_root %q, want %q", got, want)
+			}
+			if got, want := len(resp.Diffs), len(test.keys); got != want {
+				t.Fatalf("GetRevisionDiff(): %d diffs, want %d", got, want)
+			}
+			for i, d := range resp.Diffs {
+				if !bytes.Equal(d.Index, test.keys[i]) || d.FromLeaf != nil || d.ToLeaf != nil {
+					t.Errorf("GetRevisionDiff(): diff %v, want index %x only", d, test.keys[i])
+				}
+			}
+			if got, want := resp.NextPageToken, test.wantToken; got != want {
+				t.Errorf("GetRevisionDiff(): next_page_token %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestGetLeafHistoryInvalid(t *testing.T) {
+	ctx := context.Background()
+	index := make([]byte, 32)
+
+	for _, test := range []struct {
+		desc string
+		req  *trillian.GetLeafHistoryRequest
+	}{
+		{desc: "negativeFromRevision", req: &trillian.GetLeafHistoryRequest{MapId: mapID1, Index: index, FromRevision: -1, ToRevision: 3}},
+		{desc: "toBeforeFrom", req: &trillian.GetLeafHistoryRequest{MapId: mapID1, Index: index, FromRevision: 3, ToRevision: 2}},
+		{desc: "negativePageSize", req: &trillian.GetLeafHistoryRequest{MapId: mapID1, Index: index, ToRevision: 3, PageSize: -1}},
+		{desc: "shortIndex", req: &trillian.GetLeafHistoryRequest{MapId: mapID1, Index: index[1:], ToRevision: 3}},
+		{desc: "badPageToken", req: &trillian.GetLeafHistoryRequest{MapId: mapID1, Index: index, ToRevision: 3, PageToken: "AAAA"}},
+		{desc: "pageTokenBeforeFrom", req: &trillian.GetLeafHistoryRequest{MapId: mapID1, Index: index, FromRevision: 2, ToRevision: 3, PageToken: "1"}},
+		{desc: "pageTokenAtTo", req: &trillian.GetLeafHistoryRequest{MapId: mapID1, Index: index, ToRevision: 3, PageToken: "3"}},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			server := NewTrillianMapServer(extension.Registry{
+				AdminStorage: fakeAdminStorageForMap(ctrl, 1, mapID1),
+				MapStorage:   storage.NewMockMapStorage(ctrl),
+			}, TrillianMapServerOptions{})
+
+			if _, err := server.GetLeafHistory(ctx, test.req); status.Code(err) != codes.InvalidArgument {
+				t.Errorf("GetLeafHistory()=_, %v, want code %v", err, codes.InvalidArgument)
+			}
+		})
+	}
+}
+
+func TestVRFKeysInvalid(t *testing.T) {
+	ctx := context.Background()
+	index, key := make([]byte, 32), []byte("alice")
+
+	rsaKey, err := der.NewProtoFromSpec(&keyspb.Specification{
+		Params: &keyspb.Specification_RsaParams{RsaParams: &keyspb.Specification_RSA{}},
+	})
+	if err != nil {
+		t.Fatalf("NewProtoFromSpec(): %v", err)
+	}
+	rsaTree := proto.Clone(stestonly.MapTree).(*trillian.Tree)
+	rsaTree.TreeId = mapID1
+	rsaTree.SignatureAlgorithm = sigpb.DigitallySigned_RSA
+	if rsaTree.PrivateKey, err = ptypes.MarshalAny(rsaKey); err != nil {
+		t.Fatalf("MarshalAny(): %v", err)
+	}
+
+	getLeaves := func(req *trillian.GetMapLeavesRequest) func(*TrillianMapServer) error {
+		return func(server *TrillianMapServer) error {
+			_, err := server.GetLeaves(ctx, req)
+			return err
+		}
+	}
+	setLeaves := func(req *trillian.SetMapLeavesRequest) func(*TrillianMapServer) error {
+		return func(server *TrillianMapServer) error {
+			_, err := server.SetLeaves(ctx, req)
+			return err
+		}
+	}
+	for _, test := range []struct {
+		desc     string
+		tree     *trillian.Tree
+		call     func(*TrillianMapServer) error
+		wantCode codes.Code
+	}{
+		{
+			desc:     "getIndexAndKey",
+			call:     getLeaves(&trillian.GetMapLeavesRequest{MapId: mapID1, Index: [][]byte{index}, Key: [][]byte{key}}),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc: "setKeysLeavesMismatch",
+			call: setLeaves(&trillian.SetMapLeavesRequest{
+				MapId:  mapID1,
+				Leaves: []*trillian.MapLeaf{{LeafValue: []byte("a")}, {LeafValue: []byte("b")}},
+				Keys:   [][]byte{key},
+			}),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc: "setLeafKeyAndIndex",
+			call: setLeaves(&trillian.SetMapLeavesRequest{
+				MapId:  mapID1,
+				Leaves: []*trillian.MapLeaf{{Index: index, LeafValue: []byte("a")}},
+				Keys:   [][]byte{key},
+			}),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "getRSAKey",
+			tree:     rsaTree,
+			call:     getLeaves(&trillian.GetMapLeavesRequest{MapId: mapID1, Key: [][]byte{key}}),
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			desc: "setRSAKey",
+			tree: rsaTree,
+			call: setLeaves(&trillian.SetMapLeavesRequest{
+				MapId:  mapID1,
+				Leaves: []*trillian.MapLeaf{{LeafValue: []byte("a")}},
+				Keys:   [][]byte{key},
+			}),
+			wantCode: codes.FailedPrecondition,
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tree := test.tree
+			if tree == nil {
+				tree = proto.Clone(stestonly.MapTree).(*trillian.Tree)
+				tree.TreeId = mapID1
+			}
+			server := NewTrillianMapServer(extension.Registry{
+				AdminStorage: fakeAdminStorageForTree(ctrl, 1, tree),
+				MapStorage:   storage.NewMockMapStorage(ctrl),
+			}, TrillianMapServerOptions{})
+
+			if err := test.call(server); status.Code(err) != test.wantCode {
+				t.Errorf("got %v, want code %v", err, test.wantCode)
+			}
+		})
+	}
+}
+
+func TestCompressProofs(t *testing.T) {
+	a, b := []byte("a"), []byte("b")
+	newResp := func() *trillian.GetMapLeavesResponse {
+		return &trillian.GetMapLeavesResponse{
+			MapLeafInclusion: []*trillian.MapLeafInclusion{
+				{Inclusion: [][]byte{a, nil, b, nil, nil, nil, nil, nil}},
+				{Inclusion: [][]byte{nil, a, nil, nil, nil, nil, nil, b}},
+			},
+		}
+	}
+
+	for _, test := range []struct {
+		format     trillian.MapProofFormat
+		wantFormat trillian.MapProofFormat
+		wantHashes int
+	}{
+		{format: trillian.MapProofFormat_MAP_PROOF_FORMAT_FULL},
+		{format: trillian.MapProofFormat_MAP_PROOF_FORMAT_COMPRESSED, wantFormat: trillian.MapProofFormat_MAP_PROOF_FORMAT_COMPRESSED},
+		{format: trillian.MapProofFormat_MAP_PROOF_FORMAT_COMPRESSED_BATCH, wantFormat: trillian.MapProofFormat_MAP_PROOF_FORMAT_COMPRESSED_BATCH, wantHashes: 2},
+		{format: 42},
+	} {
+		t.Run(test.format.String(), func(t *testing.T) {
+			resp := compressProofs(newResp(), test.format)
+			if got := resp.ProofFormat; got != test.wantFormat {
+				t.Errorf("compressProofs(): format %v, want %v", got, test.wantFormat)
+			}
+			if got := len(resp.ProofHashes); got != test.wantHashes {
+				t.Errorf("compressProofs(): %d proof hashes, want %d", got, test.wantHashes)
+			}
+			for i, inc := range resp.MapLeafInclusion {
+				full := test.wantFormat == trillian.MapProofFormat_MAP_PROOF_FORMAT_FULL
+				if got := len(inc.Inclusion) == 8 && inc.CompressedInclusion == nil; got != full {
+					t.Errorf("compressProofs(): proof %d is %v, want full: %v", i, inc, full)
+				}
+			}
+		})
+	}
+}
+
+func TestIncrementIndex(t *testing.T) {
+	for _, test := range []struct {
+		index []byte
+		bit   int
+		want  []byte
+	}{
+		{index: []byte{0x00, 0x00}, bit: 16, want: []byte{0x00, 0x01}},
+		{index: []byte{0x00, 0xff}, bit: 16, want: []byte{0x01, 0x00}},
+		{index: []byte{0x12, 0x30}, bit: 12, want: []byte{0x12, 0x40}},
+		{index: []byte{0x12, 0xf0}, bit: 12, want: []byte{0x13, 0x00}},
+		{index: []byte{0x7f, 0x00}, bit: 1, want: []byte{0xff, 0x00}},
+		{index: []byte{0x80, 0x00}, bit: 1, want: nil},
+		{index: []byte{0xff, 0xff}, bit: 16, want: nil},
+	} {
+		if got := incrementIndex(test.index, test.bit); !bytes.Equal(got, test.want) {
+			t.Errorf("incrementIndex(%x, %d)=%x, want %x", test.index, test.bit, got, test.want)
+		}
+	}
+}
+
 func fakeAdminStorageForMap(ctrl *gomock.Controller, times int, treeID int64) storage.AdminStorage {
 	tree := proto.Clone(stestonly.MapTree).(*trillian.Tree)
 	tree.TreeId = treeID
+	return fakeAdminStorageForTree(ctrl, times, tree)
+}
 
+func fakeAdminStorageForTree(ctrl *gomock.Controller, times int, tree *trillian.Tree) storage.AdminStorage {
 	adminTX := storage.NewMockReadOnlyAdminTX(ctrl)
 	adminStorage := &stestonly.FakeAdminStorage{
 		ReadOnlyTX: []storage.ReadOnlyAdminTX{adminTX},
 	}
 
-	adminTX.EXPECT().GetTree(gomock.Any(), treeID).MaxTimes(times).Return(tree, nil)
+	adminTX.EXPECT().GetTree(gomock.Any(), tree.TreeId).MaxTimes(times).Return(tree, nil)
 	adminTX.EXPECT().Close().MaxTimes(times).Return(nil)
 	adminTX.EXPECT().Commit().MaxTimes(times).Return(nil)
 
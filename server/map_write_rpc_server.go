@@ -39,6 +39,11 @@ func (t *TrillianMapWriteServer) GetLeavesByRevision(ctx context.Context, req *t
 	return t.mapServer.GetLeavesByRevisionNoProof(ctx, req)
 }
 
+// ListLeavesByRevision implements the ListLeavesByRevision write RPC method.
+func (t *TrillianMapWriteServer) ListLeavesByRevision(ctx context.Context, req *trillian.ListMapLeavesByRevisionRequest) (*trillian.ListMapLeavesByRevisionResponse, error) {
+	return t.mapServer.ListLeavesByRevision(ctx, req)
+}
+
 // WriteLeaves implements the WriteLeaves write RPC method.
 func (t *TrillianMapWriteServer) WriteLeaves(ctx context.Context, req *trillian.WriteMapLeavesRequest) (*trillian.WriteMapLeavesResponse, error) {
 	tree, err := trees.GetTree(ctx, t.registry.AdminStorage, req.MapId, optsMapWrite)
@@ -46,9 +46,9 @@ func TestMemoryStorageProviderMapStorage(t *testing.T) {
 		t.Fatalf("Got an unexpected error: %v", err)
 	}
 
-	as := sp.MapStorage()
-	if as == nil {
-		t.Skip("Memory map storage is not implemented yet.")
+	ms := sp.MapStorage()
+	if ms == nil {
+		t.Fatal("Got a nil map storage interface.")
 	}
 }
 
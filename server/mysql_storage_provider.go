@@ -15,14 +15,18 @@
 package server
 
 import (
+	"context"
 	"database/sql"
 	"flag"
 	"sync"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/google/trillian/monitoring"
 	"github.com/google/trillian/storage"
 	"github.com/google/trillian/storage/mysql"
+	"github.com/google/trillian/util/election2"
+	sqlelect "github.com/google/trillian/util/election2/sql"
 
 	// Load MySQL driver
 	_ "github.com/go-sql-driver/mysql"
@@ -56,6 +60,12 @@ func newMySQLStorageProvider(mf monitoring.MetricFactory) (StorageProvider, erro
 		if mysqlOnceErr != nil {
 			return
 		}
+		ctx, cancel := context.WithTimeout(context.Background(), schemaCheckTimeout)
+		defer cancel()
+		if mysqlOnceErr = mysql.CheckSchemaVersion(ctx, db); mysqlOnceErr != nil {
+			db.Close()
+			return
+		}
 		if *maxConns > 0 {
 			db.SetMaxOpenConns(*maxConns)
 		}
@@ -85,6 +95,10 @@ func (s *mysqlProvider) AdminStorage() storage.AdminStorage {
 	return mysql.NewAdminStorage(s.db)
 }
 
+func (s *mysqlProvider) ElectionFactory(instanceID string, lease time.Duration) election2.Factory {
+	return sqlelect.NewFactory(instanceID, s.db, sqlelect.MySQLDialect, lease)
+}
+
 func (s *mysqlProvider) Close() error {
 	return s.db.Close()
 }
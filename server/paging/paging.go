@@ -0,0 +1,65 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package paging contains the page size rules of the paginated map RPCs,
+// shared by the map server and the quota interceptor.
+package paging
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultMapPageSize is the number of items returned by the paginated map
+	// RPCs when the request doesn't set a page size.
+	DefaultMapPageSize = 1000
+	// MaxMapPageSize is the maximum number of items returned by the paginated
+	// map RPCs, whatever the requested page size.
+	MaxMapPageSize = 10000
+	// DefaultLeafHistoryPageSize and MaxLeafHistoryPageSize are the default
+	// and maximum numbers of revisions considered by a GetLeafHistory page.
+	DefaultLeafHistoryPageSize = 10
+	MaxLeafHistoryPageSize     = 100
+)
+
+// MapPageSize returns the number of items to return for the page_size of a
+// paginated map request.
+func MapPageSize(size int32) (int, error) {
+	switch {
+	case size < 0:
+		return 0, status.Errorf(codes.InvalidArgument, "page_size %d must be >= 0", size)
+	case size == 0:
+		return DefaultMapPageSize, nil
+	case size > MaxMapPageSize:
+		return MaxMapPageSize, nil
+	}
+	return int(size), nil
+}
+
+// LeafHistoryPageSize returns the number of revisions to consider for the
+// page_size of a GetLeafHistory request. Each returned version comes with its
+// own root and inclusion proof, so pages are much smaller than for the other
+// paginated map RPCs.
+func LeafHistoryPageSize(size int32) (int, error) {
+	switch {
+	case size < 0:
+		return 0, status.Errorf(codes.InvalidArgument, "page_size %d must be >= 0", size)
+	case size == 0:
+		return DefaultLeafHistoryPageSize, nil
+	case size > MaxLeafHistoryPageSize:
+		return MaxLeafHistoryPageSize, nil
+	}
+	return int(size), nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package paging
+
+import "testing"
+
+func TestMapPageSize(t *testing.T) {
+	for _, test := range []struct {
+		size    int32
+		want    int
+		wantErr bool
+	}{
+		{size: -1, wantErr: true},
+		{size: 0, want: DefaultMapPageSize},
+		{size: 5, want: 5},
+		{size: MaxMapPageSize, want: MaxMapPageSize},
+		{size: MaxMapPageSize + 1, want: MaxMapPageSize},
+	} {
+		got, err := MapPageSize(test.size)
+		if gotErr := err != nil; gotErr != test.wantErr {
+			t.Errorf("MapPageSize(%d): %v, wantErr %v", test.size, err, test.wantErr)
+		}
+		if got != test.want {
+			t.Errorf("MapPageSize(%d): %d, want %d", test.size, got, test.want)
+		}
+	}
+}
+
+func TestLeafHistoryPageSize(t *testing.T) {
+	for _, test := range []struct {
+		size    int32
+		want    int
+		wantErr bool
+	}{
+		{size: -1, wantErr: true},
+		{size: 0, want: DefaultLeafHistoryPageSize},
+		{size: 5, want: 5},
+		{size: MaxLeafHistoryPageSize, want: MaxLeafHistoryPageSize},
+		{size: MaxMapPageSize, want: MaxLeafHistoryPageSize},
+	} {
+		got, err := LeafHistoryPageSize(test.size)
+		if gotErr := err != nil; gotErr != test.wantErr {
+			t.Errorf("LeafHistoryPageSize(%d): %v, wantErr %v", test.size, err, test.wantErr)
+		}
+		if got != test.want {
+			t.Errorf("LeafHistoryPageSize(%d): %d, want %d", test.size, got, test.want)
+		}
+	}
+}
@@ -15,14 +15,18 @@
 package server
 
 import (
+	"context"
 	"database/sql"
 	"flag"
 	"sync"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/google/trillian/monitoring"
 	"github.com/google/trillian/storage"
 	"github.com/google/trillian/storage/postgres"
+	"github.com/google/trillian/util/election2"
+	sqlelect "github.com/google/trillian/util/election2/sql"
 
 	// Load PG driver
 	_ "github.com/lib/pq"
@@ -53,6 +57,12 @@ func newPGProvider(mf monitoring.MetricFactory) (StorageProvider, error) {
 		if pgOnceErr != nil {
 			return
 		}
+		ctx, cancel := context.WithTimeout(context.Background(), schemaCheckTimeout)
+		defer cancel()
+		if pgOnceErr = postgres.CheckSchemaVersion(ctx, db); pgOnceErr != nil {
+			db.Close()
+			return
+		}
 
 		pgStorageInstance = &pgProvider{
 			db: db,
@@ -72,13 +82,18 @@ func (s *pgProvider) LogStorage() storage.LogStorage {
 }
 
 func (s *pgProvider) MapStorage() storage.MapStorage {
-	panic("Not Implemented")
+	glog.Warningf("Support for the PostgreSQL map is experimental.  Please use at your own risk!!!")
+	return postgres.NewMapStorage(s.db)
 }
 
 func (s *pgProvider) AdminStorage() storage.AdminStorage {
 	return postgres.NewAdminStorage(s.db)
 }
 
+func (s *pgProvider) ElectionFactory(instanceID string, lease time.Duration) election2.Factory {
+	return sqlelect.NewFactory(instanceID, s.db, sqlelect.PostgresDialect, lease)
+}
+
 func (s *pgProvider) Close() error {
 	return s.db.Close()
 }
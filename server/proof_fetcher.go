@@ -17,9 +17,11 @@ package server
 import (
 	"context"
 	"fmt"
+	"math/bits"
 
 	"github.com/google/trillian"
 	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/compact"
 	"github.com/google/trillian/merkle/hashers"
 	"github.com/google/trillian/storage"
 )
@@ -45,6 +47,35 @@ func fetchNodesAndBuildProof(ctx context.Context, tx storage.NodeReader, th hash
 	return r.rehashedProof(leafIndex)
 }
 
+// fetchRangeProof returns the hashes of the [0, begin) and [end, treeSize)
+// compact ranges, which prove the inclusion of the leaves in [begin, end) in
+// the tree of the given size. All the nodes of these ranges are roots of
+// perfect subtrees, so their stored hashes never need rehashing.
+func fetchRangeProof(ctx context.Context, tx storage.NodeReader, treeRevision int64, begin, end, treeSize uint64) ([][]byte, [][]byte, error) {
+	ids := append(compact.RangeNodes(0, begin), compact.RangeNodes(end, treeSize)...)
+	if len(ids) == 0 {
+		return nil, nil, nil
+	}
+	fetches := make([]merkle.NodeFetch, 0, len(ids))
+	for _, id := range ids {
+		nodeID, err := storage.NewNodeIDForTreeCoords(int64(id.Level), int64(id.Index), proofMaxBitLen)
+		if err != nil {
+			return nil, nil, err
+		}
+		fetches = append(fetches, merkle.NodeFetch{NodeID: nodeID})
+	}
+	nodes, err := fetchNodes(ctx, tx, treeRevision, fetches)
+	if err != nil {
+		return nil, nil, err
+	}
+	hashes := make([][]byte, 0, len(nodes))
+	for _, node := range nodes {
+		hashes = append(hashes, node.Hash)
+	}
+	split := bits.OnesCount64(begin)
+	return hashes[:split], hashes[split:], nil
+}
+
 // rehasher bundles the rehashing logic into a simple state machine
 type rehasher struct {
 	th         hashers.LogHasher
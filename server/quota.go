@@ -15,12 +15,16 @@
 package server
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"sync"
 
 	"github.com/golang/glog"
+	"github.com/google/trillian"
 	"github.com/google/trillian/quota"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -94,3 +98,28 @@ func NewQuotaManager(name string) (quota.Manager, error) {
 	}
 	return f()
 }
+
+// getReadTokens charges tokens to the read quotas of the users in chargeTo,
+// of the tree and to the global read quota, like the interceptor does for read
+// requests. It is used by handlers whose cost isn't known before they run. It
+// returns a ResourceExhausted error if the quota is exhausted, and does nothing
+// if qm is nil.
+func getReadTokens(ctx context.Context, qm quota.Manager, treeID int64, chargeTo *trillian.ChargeTo, tokens int) error {
+	if qm == nil || tokens <= 0 {
+		return nil
+	}
+	var specs []quota.Spec
+	for _, user := range chargeTo.GetUser() {
+		specs = append(specs, quota.Spec{Group: quota.User, Kind: quota.Read, User: user})
+	}
+	specs = append(specs, []quota.Spec{
+		{Group: quota.Tree, Kind: quota.Read, TreeID: treeID},
+		{Group: quota.Global, Kind: quota.Read},
+	}...)
+	err := qm.GetTokens(ctx, tokens, specs)
+	quota.Metrics.IncAcquired(tokens, specs, err == nil)
+	if err != nil {
+		return status.Errorf(codes.ResourceExhausted, "quota exhausted: %v", err)
+	}
+	return nil
+}
@@ -18,11 +18,17 @@ import (
 	"flag"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/google/trillian/monitoring"
 	"github.com/google/trillian/storage"
+	"github.com/google/trillian/util/election2"
 )
 
+// schemaCheckTimeout bounds the check of the schema version made by SQL
+// storage providers when they connect to their database.
+const schemaCheckTimeout = 30 * time.Second
+
 // NewStorageProviderFunc is the signature of a function which can be registered
 // to provide instances of storage providers.
 type NewStorageProviderFunc func(monitoring.MetricFactory) (StorageProvider, error)
@@ -98,3 +104,11 @@ type StorageProvider interface {
 	// Close closes the underlying storage.
 	Close() error
 }
+
+// ElectionProvider is implemented by the StorageProviders which can also run
+// master election, by keeping leases in the underlying storage.
+type ElectionProvider interface {
+	// ElectionFactory returns an election2.Factory for the given instance,
+	// whose masters hold leases of the given duration.
+	ElectionFactory(instanceID string, lease time.Duration) election2.Factory
+}
@@ -0,0 +1,262 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+	"github.com/google/trillian/extension"
+	"github.com/google/trillian/server/interceptor"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/trees"
+	"github.com/google/trillian/types"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultTileHeight is the suggested height of the tiles served by a
+	// TileHandler, which makes full hash tiles hold 256 hashes.
+	DefaultTileHeight = 8
+	// MaxTileHeight is the maximum height of the tiles served by a TileHandler.
+	MaxTileHeight = 12
+
+	immutableCacheControl = "public, max-age=31536000, immutable"
+)
+
+// TileHandler serves a static, cacheable read API for logs over HTTP. For the
+// log with ID log_id it serves:
+//   - "/{log_id}/checkpoint": the latest SignedLogRoot, as a binary proto.
+//   - "/{log_id}/tile/H/L/N[.p/W]": the concatenated hashes of a hash tile, as
+//     described by types.Tile.
+//   - "/{log_id}/tile/H/data/N[.p/W]": the leaves of an entry bundle, as a
+//     binary GetLeavesByRangeResponse proto without a SignedLogRoot.
+//
+// Only tiles of the handler's height which are entirely within the latest
+// tree size are served, so they never change once served and can be cached
+// indefinitely. The exception is entry bundles of trees with a
+// RetentionPolicy, whose leaf data may be pruned later on.
+//
+// Requests are anonymous: if an Authorizer is set, only trees which it allows
+// anonymous callers to query are served. Like GetLeavesByRange, each request
+// is charged to the read quota of the log, by the width of the tile or a
+// single token for checkpoints.
+type TileHandler struct {
+	registry extension.Registry
+	height   int
+	authz    interceptor.Authorizer
+}
+
+// NewTileHandler returns a TileHandler serving tiles of the given height from
+// the storage of the registry. authz may be nil, in which case all logs are
+// served.
+func NewTileHandler(registry extension.Registry, height int, authz interceptor.Authorizer) (*TileHandler, error) {
+	if height < 1 || height > MaxTileHeight {
+		return nil, fmt.Errorf("tile height %d out of range [1, %d]", height, MaxTileHeight)
+	}
+	return &TileHandler{registry: registry, height: height, authz: authz}, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (h *TileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+	if len(parts) != 2 {
+		http.NotFound(w, r)
+		return
+	}
+	logID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	var body []byte
+	var cacheControl string
+	if parts[1] == "checkpoint" {
+		body, err = h.checkpoint(r.Context(), logID)
+		cacheControl = "no-cache"
+	} else {
+		tile, perr := types.ParseTilePath(parts[1])
+		if perr != nil || tile.Height != h.height {
+			http.NotFound(w, r)
+			return
+		}
+		body, cacheControl, err = h.tile(r.Context(), logID, tile)
+	}
+	if err != nil {
+		s := status.Convert(err)
+		http.Error(w, s.Message(), runtime.HTTPStatusFromCode(s.Code()))
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Cache-Control", cacheControl)
+	w.Write(body)
+}
+
+// checkpoint returns the latest SignedLogRoot of the log, serialized.
+func (h *TileHandler) checkpoint(ctx context.Context, logID int64) ([]byte, error) {
+	_, slr, err := h.snapshot(ctx, logID, 1, nil)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(slr)
+}
+
+// tile returns the contents of a tile of the log, and its Cache-Control header.
+func (h *TileHandler) tile(ctx context.Context, logID int64, tile types.Tile) ([]byte, string, error) {
+	var body []byte
+	tree, _, err := h.snapshot(ctx, logID, tile.Width, func(ctx context.Context, tx storage.ReadOnlyLogTreeTX, root *types.LogRootV1) error {
+		level := uint(0)
+		if tile.Level != types.TileDataLevel {
+			level = uint(tile.Level * tile.Height)
+		}
+		// The nodes on the level of the tile which are complete at the tree size.
+		complete := root.TreeSize >> level
+		if tile.Index > complete>>uint(tile.Height) || tile.Index<<uint(tile.Height)+uint64(tile.Width) > complete {
+			return status.Errorf(codes.NotFound, "tile %s is beyond tree size %d", tile.Path(), root.TreeSize)
+		}
+		start := tile.Index << uint(tile.Height)
+
+		var err error
+		if tile.Level == types.TileDataLevel {
+			body, err = readEntryBundle(ctx, tx, int64(start), int64(tile.Width))
+		} else {
+			body, err = readHashTile(ctx, tx, int64(root.Revision), level, start, tile.Width)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if tile.Level == types.TileDataLevel && tree.RetentionPolicy != nil {
+		return body, "no-cache", nil
+	}
+	return body, immutableCacheControl, nil
+}
+
+// readHashTile returns the concatenated hashes of the width nodes on the given
+// tree level starting from index start.
+func readHashTile(ctx context.Context, tx storage.ReadOnlyLogTreeTX, revision int64, level uint, start uint64, width int) ([]byte, error) {
+	ids := make([]storage.NodeID, 0, width)
+	for i := 0; i < width; i++ {
+		id, err := storage.NewNodeIDForTreeCoords(int64(level), int64(start)+int64(i), proofMaxBitLen)
+		if err != nil {
+			return nil, status.Errorf(codes.NotFound, "invalid tile node: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	nodes, err := tx.GetMerkleNodes(ctx, revision, ids)
+	if err != nil {
+		return nil, err
+	}
+	if got, want := len(nodes), len(ids); got != want {
+		return nil, status.Errorf(codes.Internal, "expected %d nodes from storage but got %d", want, got)
+	}
+	var body []byte
+	for i, node := range nodes {
+		if !node.NodeID.Equivalent(ids[i]) {
+			return nil, status.Errorf(codes.Internal, "expected node %v from storage but got %v", ids[i].CoordString(), node.NodeID.CoordString())
+		}
+		body = append(body, node.Hash...)
+	}
+	return body, nil
+}
+
+// readEntryBundle returns the serialized GetLeavesByRangeResponse holding the
+// count leaves starting from index start.
+func readEntryBundle(ctx context.Context, tx storage.ReadOnlyLogTreeTX, start, count int64) ([]byte, error) {
+	var leaves []*trillian.LogLeaf
+	// Storage may return fewer leaves than requested at once.
+	for int64(len(leaves)) < count {
+		next := start + int64(len(leaves))
+		got, err := tx.GetLeavesByRange(ctx, next, count-int64(len(leaves)))
+		if err != nil {
+			return nil, err
+		}
+		if len(got) == 0 {
+			return nil, status.Errorf(codes.Internal, "leaf %d missing from storage", next)
+		}
+		for _, leaf := range got {
+			if want := start + int64(len(leaves)); leaf.LeafIndex != want || want >= start+count {
+				return nil, status.Errorf(codes.Internal, "expected leaf %d from storage but got %d", want, leaf.LeafIndex)
+			}
+			leaves = append(leaves, leaf)
+		}
+	}
+	return proto.Marshal(&trillian.GetLeavesByRangeResponse{Leaves: leaves})
+}
+
+// snapshot charges tokens to the read quota of the log, then returns the tree
+// and the latest SignedLogRoot of the log and, if f is not nil, runs it in the
+// same snapshot transaction, passing it the latest root.
+func (h *TileHandler) snapshot(ctx context.Context, logID int64, tokens int, f func(context.Context, storage.ReadOnlyLogTreeTX, *types.LogRootV1) error) (*trillian.Tree, *trillian.SignedLogRoot, error) {
+	if h.authz != nil {
+		if err := h.authz.Authorize(ctx, "", logID, trees.Query); err != nil {
+			return nil, nil, err
+		}
+	}
+	tree, err := trees.GetTree(ctx, h.registry.AdminStorage, logID, optsLogRead)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := getReadTokens(ctx, h.registry.QuotaManager, logID, nil, tokens); err != nil {
+		return nil, nil, err
+	}
+	ctx = trees.NewContext(ctx, tree)
+
+	tx, err := h.registry.LogStorage.SnapshotForTree(ctx, tree)
+	if tx != nil {
+		// A transaction is returned along with ErrTreeNeedsInit, so close it
+		// whenever there is one.
+		defer func() {
+			if err := tx.Close(); err != nil {
+				glog.Warningf("%v: Close failed for tile request: %v", logID, err)
+			}
+		}()
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	slr, err := tx.LatestSignedLogRoot(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if f != nil {
+		var root types.LogRootV1
+		if err := root.UnmarshalBinary(slr.LogRoot); err != nil {
+			return nil, nil, status.Errorf(codes.Internal, "Could not read current log root: %v", err)
+		}
+		if err := f(ctx, tx, &root); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, err
+	}
+	return tree, slr, nil
+}
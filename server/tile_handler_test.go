@@ -0,0 +1,280 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+	"github.com/google/trillian/client"
+	"github.com/google/trillian/extension"
+	"github.com/google/trillian/log"
+	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/rfc6962"
+	"github.com/google/trillian/monitoring"
+	"github.com/google/trillian/quota"
+	"github.com/google/trillian/server/interceptor"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/memory"
+	"github.com/google/trillian/storage/testonly"
+	"github.com/google/trillian/trees"
+	"github.com/google/trillian/types"
+	"github.com/google/trillian/util/clock"
+)
+
+// newTileLog creates a log in memory storage, integrates size leaves into it,
+// and returns a registry holding the storage.
+func newTileLog(ctx context.Context, t *testing.T, size int) (extension.Registry, *trillian.Tree) {
+	t.Helper()
+	ts := memory.NewTreeStorage()
+	registry := extension.Registry{
+		AdminStorage: memory.NewAdminStorage(ts),
+		LogStorage:   memory.NewLogStorage(ts, monitoring.InertMetricFactory{}),
+	}
+	tree, err := storage.CreateTree(ctx, registry.AdminStorage, testonly.LogTree)
+	if err != nil {
+		t.Fatalf("CreateTree(): %v", err)
+	}
+	signer, err := trees.Signer(ctx, tree)
+	if err != nil {
+		t.Fatalf("Signer(): %v", err)
+	}
+	timeSource := clock.NewFake(time.Unix(1000, 0))
+	root, err := signer.SignLogRoot(&types.LogRootV1{
+		RootHash:       rfc6962.DefaultHasher.EmptyRoot(),
+		TimestampNanos: uint64(timeSource.Now().UnixNano()),
+	})
+	if err != nil {
+		t.Fatalf("SignLogRoot(): %v", err)
+	}
+	if err := registry.LogStorage.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		return tx.StoreSignedLogRoot(ctx, root)
+	}); err != nil {
+		t.Fatalf("StoreSignedLogRoot(): %v", err)
+	}
+
+	var leaves []*trillian.LogLeaf
+	for i := 0; i < size; i++ {
+		data := []byte(fmt.Sprintf("leaf %d", i))
+		hash := sha256.Sum256(data)
+		leaves = append(leaves, &trillian.LogLeaf{
+			LeafValue:        data,
+			LeafIdentityHash: hash[:],
+			MerkleLeafHash:   rfc6962.DefaultHasher.HashLeaf(data),
+		})
+	}
+	if _, err := registry.LogStorage.QueueLeaves(ctx, tree, leaves, timeSource.Now()); err != nil {
+		t.Fatalf("QueueLeaves(): %v", err)
+	}
+	timeSource.Set(timeSource.Now().Add(time.Second))
+	seq := log.NewSequencer(rfc6962.DefaultHasher, timeSource, registry.LogStorage, signer, nil, quota.Noop())
+	if got, err := seq.IntegrateBatch(ctx, tree, size, 0, 24*time.Hour); err != nil || got != size {
+		t.Fatalf("IntegrateBatch(): %d, %v, want %d leaves", got, err, size)
+	}
+	return registry, tree
+}
+
+func TestTileHandler(t *testing.T) {
+	ctx := context.Background()
+	registry, tree := newTileLog(ctx, t, 45)
+	h, err := NewTileHandler(registry, 2, nil)
+	if err != nil {
+		t.Fatalf("NewTileHandler(): %v", err)
+	}
+	s := httptest.NewServer(h)
+	defer s.Close()
+	c := client.NewTileClient(s.Client(), fmt.Sprintf("%s/%d", s.URL, tree.TreeId), 2, rfc6962.DefaultHasher)
+
+	slr, err := c.Checkpoint(ctx)
+	if err != nil {
+		t.Fatalf("Checkpoint(): %v", err)
+	}
+	v, err := client.NewLogVerifierFromTree(tree)
+	if err != nil {
+		t.Fatalf("NewLogVerifierFromTree(): %v", err)
+	}
+	root, err := v.VerifyRoot(&types.LogRootV1{}, slr, nil)
+	if err != nil {
+		t.Fatalf("VerifyRoot(): %v", err)
+	}
+	if got, want := root.TreeSize, uint64(45); got != want {
+		t.Fatalf("Checkpoint(): tree size %d, want %d", got, want)
+	}
+
+	b := client.NewTileProofBuilder(c, root.TreeSize)
+	verifier := merkle.NewLogVerifier(rfc6962.DefaultHasher)
+	for index := uint64(0); index < root.TreeSize; index += 4 {
+		tile := types.Tile{Height: 2, Level: types.TileDataLevel, Index: index / 4, Width: 4}
+		if index+4 > root.TreeSize {
+			tile.Width = int(root.TreeSize - index)
+		}
+		leaves, err := c.EntryBundle(ctx, tile)
+		if err != nil {
+			t.Fatalf("EntryBundle(%s): %v", tile.Path(), err)
+		}
+		for _, leaf := range leaves {
+			if got, want := string(leaf.LeafValue), fmt.Sprintf("leaf %d", leaf.LeafIndex); got != want {
+				t.Errorf("EntryBundle(%s): leaf %d is %q, want %q", tile.Path(), leaf.LeafIndex, got, want)
+			}
+			proof, err := b.InclusionProof(ctx, uint64(leaf.LeafIndex))
+			if err != nil {
+				t.Fatalf("InclusionProof(%d): %v", leaf.LeafIndex, err)
+			}
+			if err := verifier.VerifyInclusionProof(leaf.LeafIndex, int64(root.TreeSize), proof, root.RootHash, leaf.MerkleLeafHash); err != nil {
+				t.Errorf("VerifyInclusionProof(%d): %v", leaf.LeafIndex, err)
+			}
+		}
+	}
+}
+
+func TestTileHandlerResponses(t *testing.T) {
+	ctx := context.Background()
+	registry, tree := newTileLog(ctx, t, 45)
+	acl := &interceptor.ACL{Global: map[string]interceptor.Permission{interceptor.AnyPrincipal: interceptor.Read}}
+	h, err := NewTileHandler(registry, 2, acl)
+	if err != nil {
+		t.Fatalf("NewTileHandler(): %v", err)
+	}
+	logPath := fmt.Sprintf("/%d/", tree.TreeId)
+
+	for _, tc := range []struct {
+		desc, method, path string
+		wantStatus         int
+		wantCacheControl   string
+		wantLen            int
+	}{
+		{desc: "checkpoint", path: logPath + "checkpoint", wantStatus: http.StatusOK, wantCacheControl: "no-cache"},
+		{desc: "fullTile", path: logPath + "tile/2/0/10", wantStatus: http.StatusOK, wantCacheControl: immutableCacheControl, wantLen: 4 * 32},
+		{desc: "partialTile", path: logPath + "tile/2/0/11.p/1", wantStatus: http.StatusOK, wantCacheControl: immutableCacheControl, wantLen: 32},
+		{desc: "upperTile", path: logPath + "tile/2/1/0.p/3", wantStatus: http.StatusOK, wantCacheControl: immutableCacheControl, wantLen: 3 * 32},
+		{desc: "entryBundle", path: logPath + "tile/2/data/11.p/1", wantStatus: http.StatusOK, wantCacheControl: immutableCacheControl},
+		{desc: "head", method: http.MethodHead, path: logPath + "tile/2/0/0", wantStatus: http.StatusOK, wantCacheControl: immutableCacheControl},
+		{desc: "tileBeyondTreeSize", path: logPath + "tile/2/0/11", wantStatus: http.StatusNotFound},
+		{desc: "partialTileBeyondTreeSize", path: logPath + "tile/2/0/11.p/2", wantStatus: http.StatusNotFound},
+		{desc: "upperTileBeyondTreeSize", path: logPath + "tile/2/2/0.p/3", wantStatus: http.StatusNotFound},
+		{desc: "entryBundleBeyondTreeSize", path: logPath + "tile/2/data/12.p/1", wantStatus: http.StatusNotFound},
+		{desc: "otherHeight", path: logPath + "tile/3/0/0", wantStatus: http.StatusNotFound},
+		{desc: "nonCanonical", path: logPath + "tile/2/0/010", wantStatus: http.StatusNotFound},
+		{desc: "malformed", path: logPath + "tiles", wantStatus: http.StatusNotFound},
+		{desc: "badLogID", path: "/abc/checkpoint", wantStatus: http.StatusNotFound},
+		{desc: "unknownLog", path: "/12345/checkpoint", wantStatus: http.StatusInternalServerError},
+		{desc: "post", method: http.MethodPost, path: logPath + "checkpoint", wantStatus: http.StatusMethodNotAllowed},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			method := tc.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(method, tc.path, nil))
+			if got, want := w.Code, tc.wantStatus; got != want {
+				t.Fatalf("%s %s: status %d, want %d: %s", method, tc.path, got, want, w.Body)
+			}
+			if got, want := w.Header().Get("Cache-Control"), tc.wantCacheControl; got != want {
+				t.Errorf("%s %s: Cache-Control %q, want %q", method, tc.path, got, want)
+			}
+			if tc.wantLen != 0 {
+				if got, want := w.Body.Len(), tc.wantLen; got != want {
+					t.Errorf("%s %s: got %d bytes, want %d", method, tc.path, got, want)
+				}
+			}
+		})
+	}
+
+	t.Run("entryBundleContents", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, logPath+"tile/2/data/3", nil))
+		var rsp trillian.GetLeavesByRangeResponse
+		if err := proto.Unmarshal(w.Body.Bytes(), &rsp); err != nil {
+			t.Fatalf("Unmarshal(): %v", err)
+		}
+		var got []int64
+		for _, leaf := range rsp.Leaves {
+			got = append(got, leaf.LeafIndex)
+		}
+		if want := []int64{12, 13, 14, 15}; fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("Entry bundle has leaves %v, want %v", got, want)
+		}
+	})
+}
+
+func TestTileHandlerAuthorization(t *testing.T) {
+	ctx := context.Background()
+	registry, tree := newTileLog(ctx, t, 5)
+	acl := &interceptor.ACL{Global: map[string]interceptor.Permission{"alice": interceptor.Read}}
+	h, err := NewTileHandler(registry, DefaultTileHeight, acl)
+	if err != nil {
+		t.Fatalf("NewTileHandler(): %v", err)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/%d/checkpoint", tree.TreeId), nil))
+	if got, want := w.Code, http.StatusForbidden; got != want {
+		t.Errorf("GET checkpoint: status %d, want %d: %s", got, want, w.Body)
+	}
+}
+
+func TestTileHandlerQuota(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	registry, tree := newTileLog(ctx, t, 5)
+	specs := []quota.Spec{
+		{Group: quota.Tree, Kind: quota.Read, TreeID: tree.TreeId},
+		{Group: quota.Global, Kind: quota.Read},
+	}
+	qm := quota.NewMockManager(ctrl)
+	registry.QuotaManager = qm
+	h, err := NewTileHandler(registry, 2, nil)
+	if err != nil {
+		t.Fatalf("NewTileHandler(): %v", err)
+	}
+
+	for _, test := range []struct {
+		path      string
+		tokens    int
+		tokensErr error
+		wantCode  int
+	}{
+		{path: "checkpoint", tokens: 1, wantCode: http.StatusOK},
+		{path: "tile/2/data/0", tokens: 4, wantCode: http.StatusOK},
+		{path: "tile/2/0/0.p/3", tokens: 3, wantCode: http.StatusOK},
+		{path: "tile/2/data/0", tokens: 4, tokensErr: errors.New("not enough tokens"), wantCode: http.StatusTooManyRequests},
+	} {
+		qm.EXPECT().GetTokens(gomock.Any(), test.tokens, specs).Return(test.tokensErr)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/%d/%s", tree.TreeId, test.path), nil))
+		if got, want := w.Code, test.wantCode; got != want {
+			t.Errorf("GET %s: status %d, want %d: %s", test.path, got, want, w.Body)
+		}
+	}
+}
+
+func TestNewTileHandler(t *testing.T) {
+	for _, height := range []int{-1, 0, MaxTileHeight + 1} {
+		if _, err := NewTileHandler(extension.Registry{}, height, nil); err == nil || !strings.Contains(err.Error(), "out of range") {
+			t.Errorf("NewTileHandler(height %d): %v, want out of range error", height, err)
+		}
+	}
+}
@@ -19,6 +19,8 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
+	"net/http"
 	_ "net/http/pprof" // Register pprof HTTP handlers.
 	"os"
 	"runtime/pprof"
@@ -37,6 +39,7 @@ import (
 	"github.com/google/trillian/quota/etcd/quotaapi"
 	"github.com/google/trillian/quota/etcd/quotapb"
 	"github.com/google/trillian/server"
+	"github.com/google/trillian/server/interceptor"
 	"github.com/google/trillian/util/clock"
 	"github.com/google/trillian/util/etcd"
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
@@ -57,14 +60,18 @@ var (
 	healthzTimeout  = flag.Duration("healthz_timeout", time.Second*5, "Timeout used during healthz checks")
 	tlsCertFile     = flag.String("tls_cert_file", "", "Path to the TLS server certificate. If unset, the server will use unsecured connections.")
 	tlsKeyFile      = flag.String("tls_key_file", "", "Path to the TLS server key. If unset, the server will use unsecured connections.")
+	tlsClientCAFile = flag.String("tls_client_ca_file", "", "Path to the CA certificates used to verify TLS client certificates. If unset, clients aren't asked for certificates.")
+	authConfigFile  = flag.String("auth_config_file", "", "Path to a JSON file holding bearer tokens and per-tree ACLs. If unset, requests aren't authenticated or authorized.")
 	etcdService     = flag.String("etcd_service", "trillian-logserver", "Service name to announce ourselves under")
 	etcdHTTPService = flag.String("etcd_http_service", "trillian-logserver-http", "Service name to announce our HTTP endpoint under")
+	tileHeight      = flag.Int("tile_height", 0, fmt.Sprintf("Height of the log tiles served under /tiles/ on the HTTP endpoint, e.g. %d (0 means disabled)", server.DefaultTileHeight))
 
 	quotaDryRun = flag.Bool("quota_dry_run", false, "If true no requests are blocked due to lack of tokens")
 
 	treeGCEnabled            = flag.Bool("tree_gc", true, "If true, tree garbage collection (hard-deletion) is periodically performed")
 	treeDeleteThreshold      = flag.Duration("tree_delete_threshold", server.DefaultTreeDeleteThreshold, "Minimum period a tree has to remain deleted before being hard-deleted")
 	treeDeleteMinRunInterval = flag.Duration("tree_delete_min_run_interval", server.DefaultTreeDeleteMinInterval, "Minimum interval between tree garbage collection sweeps. Actual runs happen randomly between [minInterval,2*minInterval).")
+	treePurgeBatchSize       = flag.Int("tree_purge_batch_size", server.DefaultTreePurgeBatchSize, "Maximum number of records of tree data deleted in a single transaction while hard-deleting a tree")
 
 	tracing          = flag.Bool("tracing", false, "If true opencensus Stackdriver tracing will be enabled. See https://opencensus.io/.")
 	tracingProjectID = flag.String("tracing_project_id", "", "project ID to pass to stackdriver. Can be empty for GCP, consult docs for other platforms.")
@@ -143,16 +150,39 @@ func main() {
 		defer pprof.StopCPUProfile()
 	}
 
+	var authn interceptor.Authenticator
+	var authz interceptor.Authorizer
+	if *authConfigFile != "" {
+		cfg, err := interceptor.LoadAuthConfig(*authConfigFile)
+		if err != nil {
+			glog.Exitf("Failed to load auth config: %v", err)
+		}
+		authn, authz = interceptor.NewAuthenticator(cfg.Tokens), &cfg.ACL
+	}
+
+	httpHandlers := make(map[string]http.Handler)
+	if *tileHeight > 0 {
+		tiles, err := server.NewTileHandler(registry, *tileHeight, authz)
+		if err != nil {
+			glog.Exitf("Failed to create tile handler: %v", err)
+		}
+		httpHandlers["/tiles/"] = http.StripPrefix("/tiles", tiles)
+	}
+
 	m := server.Main{
-		RPCEndpoint:  *rpcEndpoint,
-		HTTPEndpoint: *httpEndpoint,
-		TLSCertFile:  *tlsCertFile,
-		TLSKeyFile:   *tlsKeyFile,
-		StatsPrefix:  "log",
-		ExtraOptions: options,
-		QuotaDryRun:  *quotaDryRun,
-		DBClose:      sp.Close,
-		Registry:     registry,
+		RPCEndpoint:     *rpcEndpoint,
+		HTTPEndpoint:    *httpEndpoint,
+		TLSCertFile:     *tlsCertFile,
+		TLSKeyFile:      *tlsKeyFile,
+		TLSClientCAFile: *tlsClientCAFile,
+		Authenticator:   authn,
+		Authorizer:      authz,
+		StatsPrefix:     "log",
+		ExtraOptions:    options,
+		QuotaDryRun:     *quotaDryRun,
+		DBClose:         sp.Close,
+		Registry:        registry,
+		HTTPHandlers:    httpHandlers,
 		RegisterHandlerFn: func(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error {
 			if err := trillian.RegisterTrillianLogHandlerFromEndpoint(ctx, mux, endpoint, opts); err != nil {
 				return err
@@ -182,6 +212,7 @@ func main() {
 		TreeGCEnabled:         *treeGCEnabled,
 		TreeDeleteThreshold:   *treeDeleteThreshold,
 		TreeDeleteMinInterval: *treeDeleteMinRunInterval,
+		TreePurgeBatchSize:    *treePurgeBatchSize,
 	}
 
 	if err := m.Run(ctx); err != nil {
@@ -17,6 +17,7 @@ package main
 
 import (
 	"context"
+	"crypto"
 	"flag"
 	"fmt"
 	_ "net/http/pprof" // Register pprof HTTP handlers.
@@ -25,13 +26,17 @@ import (
 	"time"
 
 	"github.com/golang/glog"
+	"github.com/google/trillian/client"
 	"github.com/google/trillian/cmd"
+	"github.com/google/trillian/crypto/keys/pem"
 	"github.com/google/trillian/extension"
 	"github.com/google/trillian/log"
+	"github.com/google/trillian/merkle/hashers"
 	"github.com/google/trillian/monitoring"
 	"github.com/google/trillian/monitoring/opencensus"
 	"github.com/google/trillian/monitoring/prometheus"
 	"github.com/google/trillian/server"
+	"github.com/google/trillian/storage"
 	"github.com/google/trillian/util"
 	"github.com/google/trillian/util/clock"
 	"github.com/google/trillian/util/election"
@@ -39,7 +44,9 @@ import (
 	etcdelect "github.com/google/trillian/util/election2/etcd"
 	"github.com/google/trillian/util/etcd"
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"golang.org/x/crypto/ed25519"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	tpb "github.com/google/trillian"
 	// Register key ProtoHandlers
@@ -57,10 +64,12 @@ var (
 	tlsCertFile              = flag.String("tls_cert_file", "", "Path to the TLS server certificate. If unset, the server will use unsecured connections.")
 	tlsKeyFile               = flag.String("tls_key_file", "", "Path to the TLS server key. If unset, the server will use unsecured connections.")
 	sequencerIntervalFlag    = flag.Duration("sequencer_interval", 100*time.Millisecond, "Time between each sequencing pass through all logs")
-	batchSizeFlag            = flag.Int("batch_size", 1000, "Max number of leaves to process per batch")
+	batchSizeFlag            = flag.Int("batch_size", 1000, "Max number of leaves to process per batch, unless overridden by the tree's sequencing_config")
 	numSeqFlag               = flag.Int("num_sequencers", 10, "Number of sequencer workers to run in parallel")
-	sequencerGuardWindowFlag = flag.Duration("sequencer_guard_window", 0, "If set, the time elapsed before submitted leaves are eligible for sequencing")
+	sequencerGuardWindowFlag = flag.Duration("sequencer_guard_window", 0, "If set, the time elapsed before submitted leaves are eligible for sequencing, unless overridden by the tree's sequencing_config")
 	forceMaster              = flag.Bool("force_master", false, "If true, assume master for all logs")
+	electionSystem           = flag.String("election_system", "etcd", "Master election system to use, unless --force_master is set. One of: etcd, sql (the mysql or postgres storage database)")
+	electionLease            = flag.Duration("election_lease", 10*time.Second, "Duration of the master leases held in the storage database. Only effective for --election_system=sql.")
 	etcdHTTPService          = flag.String("etcd_http_service", "trillian-logsigner-http", "Service name to announce our HTTP endpoint under")
 	lockDir                  = flag.String("lock_file_path", "/test/multimaster", "etcd lock file directory path")
 	healthzTimeout           = flag.Duration("healthz_timeout", time.Second*5, "Timeout used during healthz checks")
@@ -69,6 +78,17 @@ var (
 		"Increase factor for tokens replenished by sequencing-based quotas (1 means a 1:1 relationship between sequenced leaves and replenished tokens)."+
 			"Only effective for --quota_system=etcd.")
 
+	pruneInterval  = flag.Duration("prune_interval", log.PruneInterval, "Minimum time between pruning runs for logs with a retention_policy")
+	pruneBatchSize = flag.Int("prune_batch_size", log.PruneBatchSize, "Max number of leaves whose data is pruned per pruning run")
+
+	mirrorUpstream            = flag.String("mirror_upstream", "", "Endpoint (host:port) of an upstream Trillian log server to mirror a log from. If unset, no log is mirrored.")
+	mirrorUpstreamTLSCertFile = flag.String("mirror_upstream_tls_cert_file", "", "Path to the PEM-encoded TLS certificate of the upstream log server. If unset, an unsecured connection is used.")
+	mirrorUpstreamLogID       = flag.Int64("mirror_upstream_log_id", 0, "ID of the log to mirror on the upstream server")
+	mirrorUpstreamPublicKey   = flag.String("mirror_upstream_public_key", "", "Path to the PEM-encoded public key of the upstream log, used to verify its roots")
+	mirrorLogID               = flag.Int64("mirror_log_id", 0, "ID of the local FROZEN PREORDERED_LOG tree the upstream log is mirrored to")
+	mirrorBatchSize           = flag.Int("mirror_batch_size", log.DefaultMirrorBatchSize, "Maximum number of mirrored leaves integrated under a single root")
+	mirrorPollInterval        = flag.Duration("mirror_poll_interval", 10*time.Second, "Time between checks of the upstream log for new roots")
+
 	preElectionPause   = flag.Duration("pre_election_pause", 1*time.Second, "Maximum time to wait before starting elections")
 	masterHoldInterval = flag.Duration("master_hold_interval", 60*time.Second, "Minimum interval to hold mastership for")
 	masterHoldJitter   = flag.Duration("master_hold_jitter", 120*time.Second, "Maximal random addition to --master_hold_interval")
@@ -121,10 +141,18 @@ func main() {
 	case *forceMaster:
 		glog.Warning("**** Acting as master for all logs ****")
 		electionFactory = election2.NoopFactory{}
+	case *electionSystem == "sql":
+		ep, ok := sp.(server.ElectionProvider)
+		if !ok {
+			glog.Exit("--election_system=sql needs a mysql or postgres --storage_system")
+		}
+		electionFactory = ep.ElectionFactory(instanceID, *electionLease)
+	case *electionSystem != "etcd":
+		glog.Exitf("Unknown --election_system: %q", *electionSystem)
 	case client != nil:
 		electionFactory = etcdelect.NewFactory(instanceID, client, *lockDir)
 	default:
-		glog.Exit("Either --force_master or --etcd_servers must be supplied")
+		glog.Exit("Either --force_master, --election_system=sql or --etcd_servers must be supplied")
 	}
 
 	qm, err := server.NewQuotaManagerFromFlags()
@@ -151,6 +179,8 @@ func main() {
 	// both sequencing and signing.
 	// TODO(Martin2112): Should respect read only mode and the flags in tree control etc
 	log.QuotaIncreaseFactor = *quotaIncreaseFactor
+	log.PruneInterval = *pruneInterval
+	log.PruneBatchSize = *pruneBatchSize
 	sequencerManager := log.NewSequencerManager(registry, *sequencerGuardWindowFlag)
 	info := log.OperationInfo{
 		Registry:    registry,
@@ -168,6 +198,20 @@ func main() {
 	sequencerTask := log.NewOperationManager(info, sequencerManager)
 	go sequencerTask.OperationLoop(ctx)
 
+	// The mirror writes to its local tree, so it only runs on the master for
+	// that tree, like the sequencer.
+	if *mirrorUpstream != "" {
+		mirror, err := newMirrorFromFlags(ctx, registry)
+		if err != nil {
+			glog.Exitf("Failed to set up log mirror: %v", err)
+		}
+		go func() {
+			if err := mirror.RunElected(ctx); err != nil {
+				glog.Exitf("Log mirror failed: %v", err)
+			}
+		}()
+	}
+
 	// Enable CPU profile if requested
 	if *cpuProfile != "" {
 		f := mustCreate(*cpuProfile)
@@ -209,6 +253,60 @@ func main() {
 	time.Sleep(time.Second * 5)
 }
 
+// newMirrorFromFlags returns a Mirror of the upstream log configured by flags
+// to the local tree stored in registry.
+func newMirrorFromFlags(ctx context.Context, registry extension.Registry) (*log.Mirror, error) {
+	if *mirrorUpstreamLogID == 0 || *mirrorLogID == 0 || *mirrorUpstreamPublicKey == "" {
+		return nil, fmt.Errorf("--mirror_upstream_log_id, --mirror_log_id and --mirror_upstream_public_key must be set to mirror a log")
+	}
+
+	// The upstream log is verified with the hash strategy of the local tree,
+	// as the two must match anyway.
+	tree, err := storage.GetTree(ctx, registry.AdminStorage, *mirrorLogID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mirrored log %v: %v", *mirrorLogID, err)
+	}
+	hasher, err := hashers.NewLogHasher(tree.HashStrategy)
+	if err != nil {
+		return nil, err
+	}
+	pubKey, err := pem.ReadPublicKeyFile(*mirrorUpstreamPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upstream public key: %v", err)
+	}
+	verifier := client.NewLogVerifier(hasher, pubKey, signatureHash(pubKey))
+
+	dialOpts := []grpc.DialOption{grpc.WithInsecure()}
+	if *mirrorUpstreamTLSCertFile != "" {
+		creds, err := credentials.NewClientTLSFromFile(*mirrorUpstreamTLSCertFile, "")
+		if err != nil {
+			return nil, err
+		}
+		dialOpts = []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	}
+	conn, err := grpc.Dial(*mirrorUpstream, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial upstream %v: %v", *mirrorUpstream, err)
+	}
+
+	return log.NewMirror(registry, tpb.NewTrillianLogClient(conn), verifier, clock.System, log.MirrorOptions{
+		UpstreamLogID: *mirrorUpstreamLogID,
+		LogID:         *mirrorLogID,
+		BatchSize:     *mirrorBatchSize,
+		PollInterval:  *mirrorPollInterval,
+	}), nil
+}
+
+// signatureHash returns the hash of the data signed with the private key of
+// pub. Ed25519 keys sign whole messages, so the zero hash is returned for them;
+// trees only support SHA-256 otherwise.
+func signatureHash(pub crypto.PublicKey) crypto.Hash {
+	if _, ok := pub.(ed25519.PublicKey); ok {
+		return crypto.Hash(0)
+	}
+	return crypto.SHA256
+}
+
 func mustCreate(fileName string) *os.File {
 	f, err := os.Create(fileName)
 	if err != nil {
@@ -29,12 +29,14 @@ import (
 	"github.com/google/trillian/crypto/keys/der"
 	"github.com/google/trillian/crypto/keyspb"
 	"github.com/google/trillian/extension"
+	"github.com/google/trillian/maps"
 	"github.com/google/trillian/monitoring"
 	"github.com/google/trillian/monitoring/opencensus"
 	"github.com/google/trillian/monitoring/prometheus"
 	"github.com/google/trillian/quota/etcd/quotaapi"
 	"github.com/google/trillian/quota/etcd/quotapb"
 	"github.com/google/trillian/server"
+	"github.com/google/trillian/server/interceptor"
 	"github.com/google/trillian/util/etcd"
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
 	"google.golang.org/grpc"
@@ -50,17 +52,24 @@ import (
 )
 
 var (
-	rpcEndpoint    = flag.String("rpc_endpoint", "localhost:8090", "Endpoint for RPC requests (host:port)")
-	httpEndpoint   = flag.String("http_endpoint", "localhost:8091", "Endpoint for HTTP metrics and REST requests on (host:port, empty means disabled)")
-	healthzTimeout = flag.Duration("healthz_timeout", time.Second*5, "Timeout used during healthz checks")
-	tlsCertFile    = flag.String("tls_cert_file", "", "Path to the TLS server certificate. If unset, the server will use unsecured connections.")
-	tlsKeyFile     = flag.String("tls_key_file", "", "Path to the TLS server key. If unset, the server will use unsecured connections.")
+	rpcEndpoint     = flag.String("rpc_endpoint", "localhost:8090", "Endpoint for RPC requests (host:port)")
+	httpEndpoint    = flag.String("http_endpoint", "localhost:8091", "Endpoint for HTTP metrics and REST requests on (host:port, empty means disabled)")
+	healthzTimeout  = flag.Duration("healthz_timeout", time.Second*5, "Timeout used during healthz checks")
+	tlsCertFile     = flag.String("tls_cert_file", "", "Path to the TLS server certificate. If unset, the server will use unsecured connections.")
+	tlsKeyFile      = flag.String("tls_key_file", "", "Path to the TLS server key. If unset, the server will use unsecured connections.")
+	tlsClientCAFile = flag.String("tls_client_ca_file", "", "Path to the CA certificates used to verify TLS client certificates. If unset, clients aren't asked for certificates.")
+	authConfigFile  = flag.String("auth_config_file", "", "Path to a JSON file holding bearer tokens and per-tree ACLs. If unset, requests aren't authenticated or authorized.")
 
 	quotaDryRun = flag.Bool("quota_dry_run", false, "If true no requests are blocked due to lack of tokens")
 
 	treeGCEnabled            = flag.Bool("tree_gc", true, "If true, tree garbage collection (hard-deletion) is periodically performed")
 	treeDeleteThreshold      = flag.Duration("tree_delete_threshold", server.DefaultTreeDeleteThreshold, "Minimum period a tree has to remain deleted before being hard-deleted")
 	treeDeleteMinRunInterval = flag.Duration("tree_delete_min_run_interval", server.DefaultTreeDeleteMinInterval, "Minimum interval between tree garbage collection sweeps. Actual runs happen randomly between [minInterval,2*minInterval).")
+	treePurgeBatchSize       = flag.Int("tree_purge_batch_size", server.DefaultTreePurgeBatchSize, "Maximum number of records of tree data deleted in a single transaction while hard-deleting a tree")
+
+	revisionGCEnabled        = flag.Bool("map_revision_gc", true, "If true, revisions of maps outside of their map_retention_policy are periodically garbage collected")
+	revisionGCMinRunInterval = flag.Duration("map_revision_gc_min_run_interval", maps.DefaultRevisionGCMinInterval, "Minimum interval between map revision garbage collection sweeps. Actual runs happen randomly between [minInterval,2*minInterval).")
+	revisionGCBatchSize      = flag.Int("map_revision_gc_batch_size", maps.RevisionGCBatchSize, "Maximum number of records of collected map revisions deleted in a single transaction")
 
 	tracing          = flag.Bool("tracing", false, "If true opencensus Stackdriver tracing will be enabled. See https://opencensus.io/.")
 	tracingProjectID = flag.String("tracing_project_id", "", "project ID to pass to Stackdriver client. Can be empty for GCP, consult docs for other platforms.")
@@ -131,16 +140,29 @@ func main() {
 		defer pprof.StopCPUProfile()
 	}
 
+	var authn interceptor.Authenticator
+	var authz interceptor.Authorizer
+	if *authConfigFile != "" {
+		cfg, err := interceptor.LoadAuthConfig(*authConfigFile)
+		if err != nil {
+			glog.Exitf("Failed to load auth config: %v", err)
+		}
+		authn, authz = interceptor.NewAuthenticator(cfg.Tokens), &cfg.ACL
+	}
+
 	m := server.Main{
-		RPCEndpoint:  *rpcEndpoint,
-		HTTPEndpoint: *httpEndpoint,
-		TLSCertFile:  *tlsCertFile,
-		TLSKeyFile:   *tlsKeyFile,
-		StatsPrefix:  "map",
-		ExtraOptions: options,
-		QuotaDryRun:  *quotaDryRun,
-		DBClose:      sp.Close,
-		Registry:     registry,
+		RPCEndpoint:     *rpcEndpoint,
+		HTTPEndpoint:    *httpEndpoint,
+		TLSCertFile:     *tlsCertFile,
+		TLSKeyFile:      *tlsKeyFile,
+		TLSClientCAFile: *tlsClientCAFile,
+		Authenticator:   authn,
+		Authorizer:      authz,
+		StatsPrefix:     "map",
+		ExtraOptions:    options,
+		QuotaDryRun:     *quotaDryRun,
+		DBClose:         sp.Close,
+		Registry:        registry,
 		RegisterHandlerFn: func(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error {
 			if err := trillian.RegisterTrillianMapHandlerFromEndpoint(ctx, mux, endpoint, opts); err != nil {
 				return err
@@ -184,9 +206,20 @@ func main() {
 		TreeGCEnabled:         *treeGCEnabled,
 		TreeDeleteThreshold:   *treeDeleteThreshold,
 		TreeDeleteMinInterval: *treeDeleteMinRunInterval,
+		TreePurgeBatchSize:    *treePurgeBatchSize,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if *revisionGCEnabled {
+		maps.RevisionGCBatchSize = *revisionGCBatchSize
+		go func() {
+			glog.Info("Map revision GC started")
+			gc := maps.NewRevisionGC(sp.AdminStorage(), sp.MapStorage(), *revisionGCMinRunInterval, mf)
+			gc.Run(ctx)
+		}()
 	}
 
-	ctx := context.Background()
 	if err := m.Run(ctx); err != nil {
 		glog.Exitf("Server exited with error: %v", err)
 	}
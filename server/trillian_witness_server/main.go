@@ -0,0 +1,119 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// The trillian_witness_server binary runs a witness which cosigns the roots of
+// the Trillian logs it follows.
+package main
+
+import (
+	"context"
+	"crypto"
+	"flag"
+	"net"
+	"net/http"
+	_ "net/http/pprof" // Register pprof HTTP handlers.
+
+	"github.com/golang/glog"
+	"github.com/google/trillian"
+	"github.com/google/trillian/cmd"
+	"github.com/google/trillian/crypto/keys/pem"
+	"github.com/google/trillian/server/witness"
+	"github.com/google/trillian/util"
+	"github.com/google/trillian/util/clock"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
+
+	tcrypto "github.com/google/trillian/crypto"
+)
+
+var (
+	rpcEndpoint  = flag.String("rpc_endpoint", "localhost:8095", "Endpoint for RPC requests (host:port)")
+	httpEndpoint = flag.String("http_endpoint", "localhost:8096", "Endpoint for HTTP metrics and health checks (host:port, empty means disabled)")
+	tlsCertFile  = flag.String("tls_cert_file", "", "Path to the TLS server certificate. If unset, the server will use unsecured connections.")
+	tlsKeyFile   = flag.String("tls_key_file", "", "Path to the TLS server key. If unset, the server will use unsecured connections.")
+
+	witnessID          = flag.String("witness_id", "", "Identifier of this witness, included in its cosignatures")
+	privateKeyFile     = flag.String("private_key_file", "", "Path to the PEM-encoded private key the witness cosigns log roots with")
+	privateKeyPassword = flag.String("private_key_password", "", "Password of the private key")
+	witnessConfigFile  = flag.String("witness_config_file", "", "Path to the JSON file describing the logs followed by the witness")
+	storageDir         = flag.String("storage_dir", "", "Directory where the latest cosigned root of each log is kept")
+
+	configFile = flag.String("config", "", "Config file containing flags, file contents can be overridden by command line flags")
+)
+
+func main() {
+	flag.Parse()
+	defer glog.Flush()
+
+	if *configFile != "" {
+		if err := cmd.ParseFlagFile(*configFile); err != nil {
+			glog.Exitf("Failed to load flags from config file %q: %s", *configFile, err)
+		}
+	}
+	if *witnessID == "" || *privateKeyFile == "" || *witnessConfigFile == "" || *storageDir == "" {
+		glog.Exit("--witness_id, --private_key_file, --witness_config_file and --storage_dir must be set")
+	}
+
+	ctx := context.Background()
+
+	key, err := pem.ReadPrivateKeyFile(*privateKeyFile, *privateKeyPassword)
+	if err != nil {
+		glog.Exitf("Failed to read private key: %v", err)
+	}
+	logs, err := witness.LoadConfig(*witnessConfigFile)
+	if err != nil {
+		glog.Exitf("Failed to load witness config: %v", err)
+	}
+	w := witness.New(*witnessID, tcrypto.NewSigner(0, key, crypto.SHA256), logs, witness.NewFileStorage(*storageDir), clock.System)
+
+	var options []grpc.ServerOption
+	// Let credentials.NewServerTLSFromFile handle the error case when only one of the flags is set.
+	if *tlsCertFile != "" || *tlsKeyFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(*tlsCertFile, *tlsKeyFile)
+		if err != nil {
+			glog.Exitf("Failed to load TLS credentials: %v", err)
+		}
+		options = append(options, grpc.Creds(creds))
+	}
+	srv := grpc.NewServer(options...)
+	trillian.RegisterTrillianWitnessServer(srv, w)
+	reflection.Register(srv)
+
+	if endpoint := *httpEndpoint; endpoint != "" {
+		http.Handle("/metrics", promhttp.Handler())
+		http.HandleFunc("/healthz", func(rw http.ResponseWriter, req *http.Request) {
+			rw.Write([]byte("ok"))
+		})
+		go func() {
+			glog.Infof("HTTP server starting on %v", endpoint)
+			if err := http.ListenAndServe(endpoint, nil); err != nil {
+				glog.Errorf("HTTP server stopped: %v", err)
+			}
+		}()
+	}
+
+	glog.Infof("Witness %q following %d logs, RPC server starting on %v", *witnessID, len(logs), *rpcEndpoint)
+	lis, err := net.Listen("tcp", *rpcEndpoint)
+	if err != nil {
+		glog.Exitf("Failed to listen on %v: %v", *rpcEndpoint, err)
+	}
+	go util.AwaitSignal(ctx, srv.Stop)
+
+	if err := srv.Serve(lis); err != nil {
+		glog.Errorf("RPC server terminated: %v", err)
+	}
+	glog.Infof("Stopping server, about to exit")
+}
@@ -36,6 +36,24 @@ func validateGetInclusionProofRequest(req *trillian.GetInclusionProofRequest) er
 	return nil
 }
 
+func validateGetBatchInclusionProofRequest(req *trillian.GetBatchInclusionProofRequest) error {
+	if req.TreeSize <= 0 {
+		return status.Errorf(codes.InvalidArgument, "GetBatchInclusionProofRequest.TreeSize: %v, want > 0", req.TreeSize)
+	}
+	if len(req.LeafIndex) == 0 {
+		return status.Error(codes.InvalidArgument, "GetBatchInclusionProofRequest.LeafIndex empty")
+	}
+	for i, leafIndex := range req.LeafIndex {
+		if leafIndex < 0 {
+			return status.Errorf(codes.InvalidArgument, "GetBatchInclusionProofRequest.LeafIndex[%v]: %v, want >= 0", i, leafIndex)
+		}
+		if leafIndex >= req.TreeSize {
+			return status.Errorf(codes.InvalidArgument, "GetBatchInclusionProofRequest.LeafIndex[%v]: %v >= TreeSize: %v, want < ", i, leafIndex, req.TreeSize)
+		}
+	}
+	return nil
+}
+
 func validateGetInclusionProofByHashRequest(req *trillian.GetInclusionProofByHashRequest, hasher hashers.LogHasher) error {
 	if req.TreeSize <= 0 {
 		return status.Errorf(codes.InvalidArgument, "GetInclusionProofByHashRequest.TreeSize: %v, want > 0", req.TreeSize)
@@ -80,6 +98,32 @@ func validateGetLeavesByRangeRequest(req *trillian.GetLeavesByRangeRequest) erro
 	return nil
 }
 
+func validateGetLeavesByRangeWithProofRequest(req *trillian.GetLeavesByRangeWithProofRequest) error {
+	if req.StartIndex < 0 {
+		return status.Errorf(codes.InvalidArgument, "GetLeavesByRangeWithProofRequest.StartIndex: %v, want >= 0", req.StartIndex)
+	}
+	if req.Count <= 0 {
+		return status.Errorf(codes.InvalidArgument, "GetLeavesByRangeWithProofRequest.Count: %v, want > 0", req.Count)
+	}
+	if req.TreeSize < 0 {
+		return status.Errorf(codes.InvalidArgument, "GetLeavesByRangeWithProofRequest.TreeSize: %v, want >= 0", req.TreeSize)
+	}
+	if req.TreeSize > 0 && req.StartIndex >= req.TreeSize {
+		return status.Errorf(codes.InvalidArgument, "GetLeavesByRangeWithProofRequest.StartIndex: %v >= TreeSize: %v, want < ", req.StartIndex, req.TreeSize)
+	}
+	return nil
+}
+
+func validateWatchLeavesRequest(req *trillian.WatchLeavesRequest) error {
+	if req.StartIndex < 0 {
+		return status.Errorf(codes.InvalidArgument, "WatchLeavesRequest.StartIndex: %v, want >= 0", req.StartIndex)
+	}
+	if req.FirstTreeSize < 0 {
+		return status.Errorf(codes.InvalidArgument, "WatchLeavesRequest.FirstTreeSize: %v, want >= 0", req.FirstTreeSize)
+	}
+	return nil
+}
+
 func validateGetConsistencyProofRequest(req *trillian.GetConsistencyProofRequest) error {
 	if req.FirstTreeSize <= 0 {
 		return status.Errorf(codes.InvalidArgument, "GetConsistencyProofRequest.FirstTreeSize: %v, want > 0", req.FirstTreeSize)
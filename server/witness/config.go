@@ -0,0 +1,89 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witness
+
+import (
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/crypto/keys/pem"
+	"github.com/google/trillian/merkle/hashers"
+
+	_ "github.com/google/trillian/merkle/rfc6962" // Make hashers available
+)
+
+// Config holds the logs followed by a witness.
+type Config struct {
+	Logs []LogConfig `json:"logs"`
+}
+
+// LogConfig describes a log followed by a witness.
+type LogConfig struct {
+	LogID int64 `json:"log_id"`
+	// PublicKeyFile is the path to the PEM-encoded public key of the log.
+	PublicKeyFile string `json:"public_key_file"`
+	// HashStrategy is the name of the trillian.HashStrategy of the log.
+	// RFC6962_SHA256 is used if it's empty.
+	HashStrategy string `json:"hash_strategy"`
+}
+
+// LoadConfig reads a Config from a JSON file, and returns the logs it
+// describes.
+func LoadConfig(path string) ([]*Log, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse witness config %q: %v", path, err)
+	}
+
+	logs := make([]*Log, 0, len(cfg.Logs))
+	seen := make(map[int64]bool)
+	for _, lc := range cfg.Logs {
+		if seen[lc.LogID] {
+			return nil, fmt.Errorf("log %d configured more than once", lc.LogID)
+		}
+		seen[lc.LogID] = true
+
+		strategy := trillian.HashStrategy_RFC6962_SHA256
+		if lc.HashStrategy != "" {
+			v, ok := trillian.HashStrategy_value[lc.HashStrategy]
+			if !ok {
+				return nil, fmt.Errorf("log %d: unknown hash strategy %q", lc.LogID, lc.HashStrategy)
+			}
+			strategy = trillian.HashStrategy(v)
+		}
+		hasher, err := hashers.NewLogHasher(strategy)
+		if err != nil {
+			return nil, fmt.Errorf("log %d: %v", lc.LogID, err)
+		}
+		pubKey, err := pem.ReadPublicKeyFile(lc.PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("log %d: failed to read public key: %v", lc.LogID, err)
+		}
+		logs = append(logs, &Log{
+			ID:        lc.LogID,
+			PublicKey: pubKey,
+			SigHash:   crypto.SHA256,
+			Hasher:    hasher,
+		})
+	}
+	return logs, nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witness
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/trillian/testonly"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "witness")
+	if err != nil {
+		t.Fatalf("TempDir(): %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	keyFile := filepath.Join(dir, "log.pem")
+	if err := ioutil.WriteFile(keyFile, []byte(testonly.DemoPublicKey), 0600); err != nil {
+		t.Fatalf("WriteFile(): %v", err)
+	}
+
+	for _, tc := range []struct {
+		desc    string
+		config  string
+		wantIDs []int64
+		wantErr bool
+	}{
+		{desc: "empty", config: `{}`},
+		{
+			desc:    "ok",
+			config:  fmt.Sprintf(`{"logs": [{"log_id": 1, "public_key_file": %q}, {"log_id": 2, "public_key_file": %q, "hash_strategy": "RFC6962_SHA256"}]}`, keyFile, keyFile),
+			wantIDs: []int64{1, 2},
+		},
+		{
+			desc:    "duplicateLog",
+			config:  fmt.Sprintf(`{"logs": [{"log_id": 1, "public_key_file": %q}, {"log_id": 1, "public_key_file": %q}]}`, keyFile, keyFile),
+			wantErr: true,
+		},
+		{
+			desc:    "unknownHashStrategy",
+			config:  fmt.Sprintf(`{"logs": [{"log_id": 1, "public_key_file": %q, "hash_strategy": "MD5"}]}`, keyFile),
+			wantErr: true,
+		},
+		{
+			desc:    "missingKey",
+			config:  `{"logs": [{"log_id": 1, "public_key_file": "/does/not/exist.pem"}]}`,
+			wantErr: true,
+		},
+		{desc: "badJSON", config: `{"logs": [`, wantErr: true},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			path := filepath.Join(dir, tc.desc+".json")
+			if err := ioutil.WriteFile(path, []byte(tc.config), 0600); err != nil {
+				t.Fatalf("WriteFile(): %v", err)
+			}
+			logs, err := LoadConfig(path)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("LoadConfig(): %v, wantErr %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got, want := len(logs), len(tc.wantIDs); got != want {
+				t.Fatalf("LoadConfig(): %d logs, want %d", got, want)
+			}
+			for i, l := range logs {
+				if got, want := l.ID, tc.wantIDs[i]; got != want {
+					t.Errorf("logs[%d].ID = %d, want %d", i, got, want)
+				}
+				if l.PublicKey == nil || l.Hasher == nil {
+					t.Errorf("logs[%d] = %+v, want PublicKey and Hasher set", i, l)
+				}
+			}
+		})
+	}
+}
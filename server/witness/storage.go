@@ -0,0 +1,119 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witness
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+)
+
+// Storage keeps the latest root of each log cosigned by a witness.
+type Storage interface {
+	// GetLatest returns the latest cosigned root of the log, and its
+	// cosignature. Both are nil if no root of the log has been cosigned.
+	GetLatest(ctx context.Context, logID int64) (*trillian.SignedLogRoot, *trillian.LogRootCosignature, error)
+	// SetLatest replaces the latest cosigned root of the log.
+	SetLatest(ctx context.Context, logID int64, root *trillian.SignedLogRoot, cosig *trillian.LogRootCosignature) error
+}
+
+type memoryStorage struct {
+	mu     sync.Mutex
+	latest map[int64]*trillian.GetLatestCosignedLogRootResponse
+}
+
+// NewMemoryStorage returns a Storage which keeps the latest roots in memory.
+// A witness using it forgets the roots it cosigned when restarted, so it's
+// only suitable for tests.
+func NewMemoryStorage() Storage {
+	return &memoryStorage{latest: make(map[int64]*trillian.GetLatestCosignedLogRootResponse)}
+}
+
+func (m *memoryStorage) GetLatest(ctx context.Context, logID int64) (*trillian.SignedLogRoot, *trillian.LogRootCosignature, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	latest, ok := m.latest[logID]
+	if !ok {
+		return nil, nil, nil
+	}
+	return latest.SignedLogRoot, latest.Cosignature, nil
+}
+
+func (m *memoryStorage) SetLatest(ctx context.Context, logID int64, root *trillian.SignedLogRoot, cosig *trillian.LogRootCosignature) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.latest[logID] = &trillian.GetLatestCosignedLogRootResponse{SignedLogRoot: root, Cosignature: cosig}
+	return nil
+}
+
+type fileStorage struct {
+	dir string
+}
+
+// NewFileStorage returns a Storage which keeps the latest root of each log in
+// a file in dir. Files are replaced atomically, so a crashed witness never
+// sees a partially written root.
+func NewFileStorage(dir string) Storage {
+	return &fileStorage{dir: dir}
+}
+
+func (f *fileStorage) path(logID int64) string {
+	return filepath.Join(f.dir, fmt.Sprintf("%d.pb", logID))
+}
+
+func (f *fileStorage) GetLatest(ctx context.Context, logID int64) (*trillian.SignedLogRoot, *trillian.LogRootCosignature, error) {
+	data, err := ioutil.ReadFile(f.path(logID))
+	if os.IsNotExist(err) {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+	var latest trillian.GetLatestCosignedLogRootResponse
+	if err := proto.Unmarshal(data, &latest); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse latest root of log %d: %v", logID, err)
+	}
+	return latest.SignedLogRoot, latest.Cosignature, nil
+}
+
+func (f *fileStorage) SetLatest(ctx context.Context, logID int64, root *trillian.SignedLogRoot, cosig *trillian.LogRootCosignature) error {
+	data, err := proto.Marshal(&trillian.GetLatestCosignedLogRootResponse{SignedLogRoot: root, Cosignature: cosig})
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(f.dir, fmt.Sprintf("%d.pb.tmp", logID))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // Fails harmlessly once renamed.
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path(logID))
+}
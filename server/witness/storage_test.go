@@ -0,0 +1,87 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witness
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+)
+
+func TestStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "witness")
+	if err != nil {
+		t.Fatalf("TempDir(): %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, tc := range []struct {
+		desc string
+		new  func() Storage
+	}{
+		{desc: "memory", new: NewMemoryStorage},
+		{desc: "file", new: func() Storage { return NewFileStorage(dir) }},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := context.Background()
+			s := tc.new()
+
+			root, cosig, err := s.GetLatest(ctx, 1)
+			if err != nil || root != nil || cosig != nil {
+				t.Fatalf("GetLatest(): %v, %v, %v, want nils", root, cosig, err)
+			}
+
+			for i, want := range []*trillian.GetLatestCosignedLogRootResponse{
+				{
+					SignedLogRoot: &trillian.SignedLogRoot{LogRoot: []byte("root1")},
+					Cosignature:   &trillian.LogRootCosignature{WitnessId: "w", LogId: 1, TimestampNanos: 1},
+				},
+				{
+					SignedLogRoot: &trillian.SignedLogRoot{LogRoot: []byte("root2")},
+					Cosignature:   &trillian.LogRootCosignature{WitnessId: "w", LogId: 1, TimestampNanos: 2},
+				},
+			} {
+				if err := s.SetLatest(ctx, 1, want.SignedLogRoot, want.Cosignature); err != nil {
+					t.Fatalf("SetLatest(#%d): %v", i, err)
+				}
+				root, cosig, err := s.GetLatest(ctx, 1)
+				if err != nil {
+					t.Fatalf("GetLatest(#%d): %v", i, err)
+				}
+				if !proto.Equal(root, want.SignedLogRoot) || !proto.Equal(cosig, want.Cosignature) {
+					t.Errorf("GetLatest(#%d): %v, %v, want %v, %v", i, root, cosig, want.SignedLogRoot, want.Cosignature)
+				}
+			}
+
+			// Other logs are unaffected.
+			if root, _, err := s.GetLatest(ctx, 2); err != nil || root != nil {
+				t.Errorf("GetLatest(2): %v, %v, want nils", root, err)
+			}
+		})
+	}
+
+	// The file storage survives restarts.
+	root, _, err := NewFileStorage(dir).GetLatest(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("GetLatest(): %v", err)
+	}
+	if got, want := string(root.GetLogRoot()), "root2"; got != want {
+		t.Errorf("GetLatest(): root %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witness
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/google/trillian"
+	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/hashers"
+	"github.com/google/trillian/types"
+	"github.com/google/trillian/util/clock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	tcrypto "github.com/google/trillian/crypto"
+)
+
+// Log holds what a witness needs to verify the roots of a log.
+type Log struct {
+	// ID is the tree ID of the log.
+	ID int64
+	// PublicKey verifies the signatures of the log's roots.
+	PublicKey crypto.PublicKey
+	// SigHash is the hash function used by the log's signatures.
+	SigHash crypto.Hash
+	// Hasher is the hasher of the log's Merkle tree.
+	Hasher hashers.LogHasher
+}
+
+// Server is an implementation of trillian.TrillianWitnessServer.
+type Server struct {
+	id         string
+	signer     *tcrypto.Signer
+	logs       map[int64]*Log
+	storage    Storage
+	timeSource clock.TimeSource
+
+	mu sync.Mutex
+	// locks serialize the updates of the latest cosigned root of each log.
+	locks map[int64]*sync.Mutex
+}
+
+// New returns a trillian.TrillianWitnessServer implementation. It cosigns the
+// roots of logs as the witness identified by id, with signer, and keeps the
+// latest cosigned root of each log in storage.
+func New(id string, signer *tcrypto.Signer, logs []*Log, storage Storage, timeSource clock.TimeSource) *Server {
+	byID := make(map[int64]*Log, len(logs))
+	for _, l := range logs {
+		byID[l.ID] = l
+	}
+	return &Server{
+		id:         id,
+		signer:     signer,
+		logs:       byID,
+		storage:    storage,
+		timeSource: timeSource,
+		locks:      make(map[int64]*sync.Mutex),
+	}
+}
+
+func (s *Server) getLog(logID int64) (*Log, error) {
+	l, ok := s.logs[logID]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "log %d is not followed by this witness", logID)
+	}
+	return l, nil
+}
+
+func (s *Server) lock(logID int64) *sync.Mutex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l, ok := s.locks[logID]
+	if !ok {
+		l = &sync.Mutex{}
+		s.locks[logID] = l
+	}
+	return l
+}
+
+// GetLatestCosignedLogRoot implements trillian.TrillianWitnessServer.GetLatestCosignedLogRoot.
+func (s *Server) GetLatestCosignedLogRoot(ctx context.Context, req *trillian.GetLatestCosignedLogRootRequest) (*trillian.GetLatestCosignedLogRootResponse, error) {
+	if _, err := s.getLog(req.LogId); err != nil {
+		return nil, err
+	}
+	slr, cosig, err := s.storage.GetLatest(ctx, req.LogId)
+	if err != nil {
+		return nil, err
+	}
+	return &trillian.GetLatestCosignedLogRootResponse{SignedLogRoot: slr, Cosignature: cosig}, nil
+}
+
+// AddLogRoot implements trillian.TrillianWitnessServer.AddLogRoot.
+func (s *Server) AddLogRoot(ctx context.Context, req *trillian.AddLogRootRequest) (*trillian.AddLogRootResponse, error) {
+	l, err := s.getLog(req.LogId)
+	if err != nil {
+		return nil, err
+	}
+	root, err := tcrypto.VerifySignedLogRoot(l.PublicKey, l.SigHash, req.SignedLogRoot)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to verify log root: %v", err)
+	}
+
+	mu := s.lock(req.LogId)
+	mu.Lock()
+	defer mu.Unlock()
+
+	latestSLR, _, err := s.storage.GetLatest(ctx, req.LogId)
+	if err != nil {
+		return nil, err
+	}
+	if latestSLR != nil {
+		// The latest root was verified before it was stored.
+		var latest types.LogRootV1
+		if err := latest.UnmarshalBinary(latestSLR.LogRoot); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to unmarshal latest root: %v", err)
+		}
+		if root.TreeSize < latest.TreeSize {
+			return nil, status.Errorf(codes.FailedPrecondition, "root of size %d is older than the latest cosigned root of size %d", root.TreeSize, latest.TreeSize)
+		}
+		if root.TreeSize == latest.TreeSize && !bytes.Equal(root.RootHash, latest.RootHash) {
+			glog.Errorf("%v: log signed two roots of size %d: %x (cosigned) and %x", req.LogId, root.TreeSize, latest.RootHash, root.RootHash)
+		}
+		verifier := merkle.NewLogVerifier(l.Hasher)
+		if err := verifier.VerifyConsistencyProof(int64(latest.TreeSize), int64(root.TreeSize), latest.RootHash, root.RootHash, req.GetProof().GetHashes()); err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "root is not consistent with the latest cosigned root of size %d: %v", latest.TreeSize, err)
+		}
+	}
+
+	cosig, err := s.signer.SignCosignature(&types.CosignatureV1{
+		WitnessID:      []byte(s.id),
+		LogID:          uint64(req.LogId),
+		LogRoot:        req.SignedLogRoot.LogRoot,
+		TimestampNanos: uint64(s.timeSource.Now().UnixNano()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.storage.SetLatest(ctx, req.LogId, req.SignedLogRoot, cosig); err != nil {
+		return nil, err
+	}
+	return &trillian.AddLogRootResponse{Cosignature: cosig}, nil
+}
@@ -0,0 +1,187 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witness
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+	"github.com/google/trillian/crypto/keys/pem"
+	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/rfc6962"
+	"github.com/google/trillian/testonly"
+	"github.com/google/trillian/types"
+	"github.com/google/trillian/util/clock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	tcrypto "github.com/google/trillian/crypto"
+)
+
+const logID = 42
+
+// testLog is an in-memory log which signs its roots.
+type testLog struct {
+	t      *testing.T
+	signer *tcrypto.Signer
+	tree   *merkle.InMemoryMerkleTree
+}
+
+func newTestLog(t *testing.T, size int, prefix string) *testLog {
+	t.Helper()
+	key, err := pem.UnmarshalPrivateKey(testonly.DemoPrivateKey, testonly.DemoPrivateKeyPass)
+	if err != nil {
+		t.Fatalf("UnmarshalPrivateKey(): %v", err)
+	}
+	l := &testLog{
+		t:      t,
+		signer: tcrypto.NewSigner(logID, key, crypto.SHA256),
+		tree:   merkle.NewInMemoryMerkleTree(rfc6962.DefaultHasher),
+	}
+	for i := 0; i < size; i++ {
+		l.tree.AddLeaf([]byte(fmt.Sprintf("%s-%d", prefix, i)))
+	}
+	return l
+}
+
+func (l *testLog) root(size int64) *trillian.SignedLogRoot {
+	l.t.Helper()
+	slr, err := l.signer.SignLogRoot(&types.LogRootV1{
+		TreeSize:       uint64(size),
+		RootHash:       l.tree.RootAtSnapshot(size).Hash(),
+		TimestampNanos: uint64(size),
+	})
+	if err != nil {
+		l.t.Fatalf("SignLogRoot(): %v", err)
+	}
+	return slr
+}
+
+func (l *testLog) proof(first, second int64) *trillian.Proof {
+	var hashes [][]byte
+	for _, d := range l.tree.SnapshotConsistency(first, second) {
+		hashes = append(hashes, d.Value.Hash())
+	}
+	return &trillian.Proof{Hashes: hashes}
+}
+
+func newWitnessSigner(t *testing.T) *tcrypto.Signer {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey(): %v", err)
+	}
+	return tcrypto.NewSigner(0, key, crypto.SHA256)
+}
+
+func TestAddLogRoot(t *testing.T) {
+	ctx := context.Background()
+	log := newTestLog(t, 12, "leaf")
+	fork := newTestLog(t, 12, "fork")
+	signer := newWitnessSigner(t)
+	fakeTime := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	s := New("witness", signer, []*Log{{
+		ID:        logID,
+		PublicKey: log.signer.Public(),
+		SigHash:   crypto.SHA256,
+		Hasher:    rfc6962.DefaultHasher,
+	}}, NewMemoryStorage(), clock.NewFake(fakeTime))
+
+	badSig := log.root(5)
+	badSig.LogRootSignature = []byte("not a signature")
+
+	// The steps are run in order against the same witness.
+	for _, step := range []struct {
+		desc     string
+		logID    int64
+		root     *trillian.SignedLogRoot
+		proof    *trillian.Proof
+		wantCode codes.Code
+		// wantSize is the size of the latest cosigned root after the step.
+		wantSize uint64
+	}{
+		{desc: "unknownLog", logID: logID + 1, root: log.root(5), wantCode: codes.NotFound},
+		{desc: "badSignature", root: badSig, wantCode: codes.InvalidArgument},
+		{desc: "first", root: log.root(5), wantSize: 5},
+		{desc: "older", root: log.root(3), wantCode: codes.FailedPrecondition, wantSize: 5},
+		{desc: "wrongProof", root: log.root(10), proof: log.proof(4, 10), wantCode: codes.FailedPrecondition, wantSize: 5},
+		{desc: "missingProof", root: log.root(10), wantCode: codes.FailedPrecondition, wantSize: 5},
+		{desc: "consistent", root: log.root(10), proof: log.proof(5, 10), wantSize: 10},
+		{desc: "same", root: log.root(10), wantSize: 10},
+		{desc: "forkSameSize", root: fork.root(10), wantCode: codes.FailedPrecondition, wantSize: 10},
+		{desc: "forkLarger", root: fork.root(12), proof: fork.proof(10, 12), wantCode: codes.FailedPrecondition, wantSize: 10},
+		{desc: "consistentAgain", root: log.root(12), proof: log.proof(10, 12), wantSize: 12},
+	} {
+		id := step.logID
+		if id == 0 {
+			id = logID
+		}
+		rsp, err := s.AddLogRoot(ctx, &trillian.AddLogRootRequest{LogId: id, SignedLogRoot: step.root, Proof: step.proof})
+		if got, want := status.Code(err), step.wantCode; got != want {
+			t.Fatalf("%v: AddLogRoot(): %v, want code %v", step.desc, err, want)
+		}
+		if err == nil {
+			cosig, err := tcrypto.VerifyCosignature(signer.Public(), crypto.SHA256, step.root, rsp.Cosignature)
+			if err != nil {
+				t.Errorf("%v: VerifyCosignature(): %v", step.desc, err)
+			} else if got, want := cosig.TimestampNanos, uint64(fakeTime.UnixNano()); got != want {
+				t.Errorf("%v: cosignature timestamp %v, want %v", step.desc, got, want)
+			}
+			if got, want := rsp.Cosignature.WitnessId, "witness"; got != want {
+				t.Errorf("%v: WitnessId %q, want %q", step.desc, got, want)
+			}
+		}
+
+		latest, err := s.GetLatestCosignedLogRoot(ctx, &trillian.GetLatestCosignedLogRootRequest{LogId: logID})
+		if err != nil {
+			t.Fatalf("%v: GetLatestCosignedLogRoot(): %v", step.desc, err)
+		}
+		if step.wantSize == 0 {
+			if latest.SignedLogRoot != nil {
+				t.Errorf("%v: GetLatestCosignedLogRoot(): %v, want no root", step.desc, latest.SignedLogRoot)
+			}
+			continue
+		}
+		var root types.LogRootV1
+		if err := root.UnmarshalBinary(latest.GetSignedLogRoot().GetLogRoot()); err != nil {
+			t.Fatalf("%v: UnmarshalBinary(): %v", step.desc, err)
+		}
+		if got, want := root.TreeSize, step.wantSize; got != want {
+			t.Errorf("%v: latest cosigned size %d, want %d", step.desc, got, want)
+		}
+		if _, err := tcrypto.VerifyCosignature(signer.Public(), crypto.SHA256, latest.SignedLogRoot, latest.Cosignature); err != nil {
+			t.Errorf("%v: VerifyCosignature(latest): %v", step.desc, err)
+		}
+		if step.wantCode == codes.OK && !proto.Equal(latest.Cosignature, rsp.Cosignature) {
+			t.Errorf("%v: latest cosignature %v, want %v", step.desc, latest.Cosignature, rsp.Cosignature)
+		}
+	}
+}
+
+func TestGetLatestCosignedLogRootUnknownLog(t *testing.T) {
+	s := New("witness", newWitnessSigner(t), nil, NewMemoryStorage(), clock.System)
+	_, err := s.GetLatestCosignedLogRoot(context.Background(), &trillian.GetLatestCosignedLogRootRequest{LogId: logID})
+	if got, want := status.Code(err), codes.NotFound; got != want {
+		t.Errorf("GetLatestCosignedLogRoot(): %v, want code %v", err, want)
+	}
+}
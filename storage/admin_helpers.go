@@ -110,6 +110,21 @@ func HardDeleteTree(ctx context.Context, admin AdminStorage, treeID int64) error
 	})
 }
 
+// PurgeTreeData deletes at most limit records of a tree's data from storage.
+// It's a convenience wrapper around ReadWriteTransaction and AdminWriter's PurgeTreeData.
+// See ReadWriteTransaction if you need to perform more than one action per transaction.
+func PurgeTreeData(ctx context.Context, admin AdminStorage, treeID int64, limit int) (int, error) {
+	ctx, spanEnd := spanFor(ctx, "PurgeTreeData")
+	defer spanEnd()
+	var purged int
+	err := admin.ReadWriteTransaction(ctx, func(ctx context.Context, tx AdminTX) error {
+		var err error
+		purged, err = tx.PurgeTreeData(ctx, treeID, limit)
+		return err
+	})
+	return purged, err
+}
+
 // UndeleteTree undeletes a tree in storage.
 // It's a convenience wrapper around ReadWriteTransaction and AdminWriter's UndeleteTree.
 // See ReadWriteTransaction if you need to perform more than one action per transaction.
@@ -118,9 +118,20 @@ type AdminWriter interface {
 	// records related to it.
 	// The tree must exist and currently be soft deleted, as per SoftDeletedTree, otherwise an error
 	// is returned.
+	// Large trees should have their data purged via PurgeTreeData first, so that
+	// the transaction deleting the tree itself stays small.
 	// Hard deleted trees cannot be recovered.
 	HardDeleteTree(ctx context.Context, treeID int64) error
 
+	// PurgeTreeData deletes at most limit records of the data (roots, subtrees,
+	// leaves, queued entries and map leaves) of the specified tree, and returns
+	// the number of records deleted. The tree itself is kept, so purging can be
+	// spread over many transactions and resumed after a failure; fewer than limit
+	// records are deleted only once no data of the tree is left.
+	// The tree must exist and currently be soft deleted, as per SoftDeletedTree, otherwise an error
+	// is returned.
+	PurgeTreeData(ctx context.Context, treeID int64, limit int) (int, error)
+
 	// UndeleteTree undeletes a soft-deleted tree.
 	// The tree must exist and currently be soft deleted, as per SoftDeletedTree, otherwise an error
 	// is returned.
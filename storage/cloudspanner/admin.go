@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -440,6 +441,10 @@ func newTreeInfo(tree *trillian.Tree, treeID int64, now time.Time) (*spannerpb.T
 		PrivateKey:            tree.GetPrivateKey(),
 		PublicKeyDer:          tree.GetPublicKey().GetDer(),
 		MaxRootDurationMillis: int64(maxRootDuration / time.Millisecond),
+		SequencingConfig:      toSpannerSequencingConfig(tree.SequencingConfig),
+		RetentionPolicy:       toSpannerRetentionPolicy(tree.RetentionPolicy),
+		LogRootConfig:         toSpannerLogRootConfig(tree.LogRootConfig),
+		MapRetentionPolicy:    toSpannerMapRetentionPolicy(tree.MapRetentionPolicy),
 	}
 
 	switch tree.TreeType {
@@ -535,6 +540,9 @@ func (t *adminTX) UpdateTree(ctx context.Context, treeID int64, updateFunc func(
 	info.UpdateTimeNanos = now.UnixNano()
 	info.MaxRootDurationMillis = int64(maxRootDuration / time.Millisecond)
 	info.PrivateKey = tree.PrivateKey
+	info.SequencingConfig = toSpannerSequencingConfig(tree.SequencingConfig)
+	info.RetentionPolicy = toSpannerRetentionPolicy(tree.RetentionPolicy)
+	info.MapRetentionPolicy = toSpannerMapRetentionPolicy(tree.MapRetentionPolicy)
 
 	if err := t.updateTreeInfo(ctx, info); err != nil {
 		return nil, err
@@ -618,11 +626,92 @@ func (t *adminTX) HardDeleteTree(ctx context.Context, treeID int64) error {
 		spanner.Delete("SubtreeData", spanner.Key{info.TreeId}.AsPrefix()),
 		spanner.Delete("LeafData", spanner.Key{info.TreeId}.AsPrefix()),
 		spanner.Delete("SequencedLeafData", spanner.Key{info.TreeId}.AsPrefix()),
+		spanner.Delete("PrunedLeaves", spanner.Key{info.TreeId}),
 		spanner.Delete("Unsequenced", spanner.Key{info.TreeId}.AsPrefix()),
 		spanner.Delete("MapLeafData", spanner.Key{info.TreeId}.AsPrefix()),
 	})
 }
 
+// treeDataTables lists the tables holding the data of a tree, along with their
+// primary key columns.
+var treeDataTables = []struct {
+	table   string
+	keyCols []string
+}{
+	{table: "TreeHeads", keyCols: []string{"TreeID", "TreeRevision"}},
+	{table: "SubtreeData", keyCols: []string{"TreeID", "SubtreeID", "Revision"}},
+	{table: "LeafData", keyCols: []string{"TreeID", "LeafIdentityHash"}},
+	{table: "SequencedLeafData", keyCols: []string{"TreeID", "SequenceNumber"}},
+	{table: "PrunedLeaves", keyCols: []string{"TreeID"}},
+	{table: "Unsequenced", keyCols: []string{"TreeID", "Bucket", "QueueTimestampNanos", "MerkleLeafHash"}},
+	{table: "MapLeafData", keyCols: []string{"TreeID", "LeafIndex", "MapRevision"}},
+}
+
+// bytesKeyCols holds the BYTES columns of treeDataTables' keys, all other key
+// columns are INT64.
+var bytesKeyCols = map[string]bool{
+	"SubtreeID":        true,
+	"LeafIdentityHash": true,
+	"MerkleLeafHash":   true,
+	"LeafIndex":        true,
+}
+
+// PurgeTreeData implements AdminWriter.PurgeTreeData.
+func (t *adminTX) PurgeTreeData(ctx context.Context, treeID int64, limit int) (int, error) {
+	info, err := t.getTreeInfo(ctx, treeID)
+	if err != nil {
+		return 0, err
+	}
+	if !info.Deleted {
+		return 0, status.Errorf(codes.FailedPrecondition, "tree %v is not soft deleted", treeID)
+	}
+
+	stx, ok := t.tx.(*spanner.ReadWriteTransaction)
+	if !ok {
+		return 0, ErrWrongTXType
+	}
+
+	// Deleting whole key ranges may exceed the mutation limits of a single
+	// commit for large trees, so rows are deleted one by one instead.
+	var muts []*spanner.Mutation
+	for _, d := range treeDataTables {
+		if len(muts) >= limit {
+			break
+		}
+		stmt := spanner.NewStatement(fmt.Sprintf(
+			"SELECT %s FROM %s WHERE TreeID = @tree_id LIMIT @limit",
+			strings.Join(d.keyCols, ", "), d.table))
+		stmt.Params["tree_id"] = treeID
+		stmt.Params["limit"] = int64(limit - len(muts))
+		if err := stx.Query(ctx, stmt).Do(func(r *spanner.Row) error {
+			key := make(spanner.Key, 0, len(d.keyCols))
+			for i, col := range d.keyCols {
+				if bytesKeyCols[col] {
+					var v []byte
+					if err := r.Column(i, &v); err != nil {
+						return err
+					}
+					key = append(key, v)
+				} else {
+					var v int64
+					if err := r.Column(i, &v); err != nil {
+						return err
+					}
+					key = append(key, v)
+				}
+			}
+			muts = append(muts, spanner.Delete(d.table, key))
+			return nil
+		}); err != nil {
+			return 0, err
+		}
+	}
+	if err := stx.BufferWrite(muts); err != nil {
+		return 0, err
+	}
+	return len(muts), nil
+}
+
 // UndeleteTree implements AdminWriter.UndeleteTree.
 func (t *adminTX) UndeleteTree(ctx context.Context, treeID int64) (*trillian.Tree, error) {
 	info, err := t.getTreeInfo(ctx, treeID)
@@ -652,14 +741,18 @@ func toTrillianTree(info *spannerpb.TreeInfo) (*trillian.Tree, error) {
 		return nil, status.Errorf(codes.Internal, "failed to convert creation time: %v", err)
 	}
 	tree := &trillian.Tree{
-		TreeId:          info.TreeId,
-		DisplayName:     info.Name,
-		Description:     info.Description,
-		CreateTime:      createdPB,
-		UpdateTime:      updatedPB,
-		PrivateKey:      info.PrivateKey,
-		PublicKey:       &keyspb.PublicKey{Der: info.PublicKeyDer},
-		MaxRootDuration: ptypes.DurationProto(time.Duration(info.MaxRootDurationMillis) * time.Millisecond),
+		TreeId:             info.TreeId,
+		DisplayName:        info.Name,
+		Description:        info.Description,
+		CreateTime:         createdPB,
+		UpdateTime:         updatedPB,
+		PrivateKey:         info.PrivateKey,
+		PublicKey:          &keyspb.PublicKey{Der: info.PublicKeyDer},
+		MaxRootDuration:    ptypes.DurationProto(time.Duration(info.MaxRootDurationMillis) * time.Millisecond),
+		SequencingConfig:   toTrillianSequencingConfig(info.SequencingConfig),
+		RetentionPolicy:    toTrillianRetentionPolicy(info.RetentionPolicy),
+		LogRootConfig:      toTrillianLogRootConfig(info.LogRootConfig),
+		MapRetentionPolicy: toTrillianMapRetentionPolicy(info.MapRetentionPolicy),
 	}
 
 	ts, ok := treeStateReverseMap[info.TreeState]
@@ -743,3 +836,87 @@ func validateLogStorageConfig(config *spannerpb.LogStorageConfig) error {
 	}
 	return nil
 }
+
+func toSpannerSequencingConfig(c *trillian.SequencingConfig) *spannerpb.SequencingConfig {
+	if c == nil {
+		return nil
+	}
+	return &spannerpb.SequencingConfig{
+		Paused:          c.Paused,
+		BatchSize:       c.BatchSize,
+		GuardWindow:     c.GuardWindow,
+		MinRootInterval: c.MinRootInterval,
+	}
+}
+
+func toTrillianSequencingConfig(c *spannerpb.SequencingConfig) *trillian.SequencingConfig {
+	if c == nil {
+		return nil
+	}
+	return &trillian.SequencingConfig{
+		Paused:          c.Paused,
+		BatchSize:       c.BatchSize,
+		GuardWindow:     c.GuardWindow,
+		MinRootInterval: c.MinRootInterval,
+	}
+}
+
+func toSpannerRetentionPolicy(p *trillian.RetentionPolicy) *spannerpb.RetentionPolicy {
+	if p == nil {
+		return nil
+	}
+	return &spannerpb.RetentionPolicy{
+		MaxEntries: p.MaxEntries,
+		MaxAge:     p.MaxAge,
+	}
+}
+
+func toTrillianRetentionPolicy(p *spannerpb.RetentionPolicy) *trillian.RetentionPolicy {
+	if p == nil {
+		return nil
+	}
+	return &trillian.RetentionPolicy{
+		MaxEntries: p.MaxEntries,
+		MaxAge:     p.MaxAge,
+	}
+}
+
+func toSpannerLogRootConfig(c *trillian.LogRootConfig) *spannerpb.LogRootConfig {
+	if c == nil {
+		return nil
+	}
+	return &spannerpb.LogRootConfig{
+		Format: spannerpb.LogRootFormat(c.Format),
+		Origin: c.Origin,
+	}
+}
+
+func toTrillianLogRootConfig(c *spannerpb.LogRootConfig) *trillian.LogRootConfig {
+	if c == nil {
+		return nil
+	}
+	return &trillian.LogRootConfig{
+		Format: trillian.LogRootFormat(c.Format),
+		Origin: c.Origin,
+	}
+}
+
+func toSpannerMapRetentionPolicy(p *trillian.MapRetentionPolicy) *spannerpb.MapRetentionPolicy {
+	if p == nil {
+		return nil
+	}
+	return &spannerpb.MapRetentionPolicy{
+		MaxRevisions: p.MaxRevisions,
+		MaxAge:       p.MaxAge,
+	}
+}
+
+func toTrillianMapRetentionPolicy(p *spannerpb.MapRetentionPolicy) *trillian.MapRetentionPolicy {
+	if p == nil {
+		return nil
+	}
+	return &trillian.MapRetentionPolicy{
+		MaxRevisions: p.MaxRevisions,
+		MaxAge:       p.MaxAge,
+	}
+}
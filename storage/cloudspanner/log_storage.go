@@ -38,12 +38,20 @@ import (
 
 const (
 	leafDataTbl            = "LeafData"
+	prunedLeavesTbl        = "PrunedLeaves"
 	seqDataByMerkleHashIdx = "SequenceByMerkleHash"
 	seqDataTbl             = "SequencedLeafData"
 	unseqTable             = "Unsequenced"
 
 	unsequencedCountSQL = "SELECT Unsequenced.TreeID, COUNT(1) FROM Unsequenced GROUP BY TreeID"
 
+	// LeafData rows are shared by duplicate leaves, so the data of a leaf in
+	// the pruned range is kept if it's also referenced by a later leaf.
+	selectPrunableLeavesSQL = `SELECT DISTINCT sd.LeafIdentityHash FROM SequencedLeafData AS sd
+WHERE sd.TreeID = @tree_id AND sd.SequenceNumber >= @start AND sd.SequenceNumber < @xend
+AND NOT EXISTS (SELECT 1 FROM SequencedLeafData AS r
+  WHERE r.TreeID = sd.TreeID AND r.LeafIdentityHash = sd.LeafIdentityHash AND r.SequenceNumber >= @xend)`
+
 	// t.TreeType: 1 = Log, 3 = PreorderedLog.
 	// t.TreeState: 1 = Active, 5 = Draining.
 	getActiveLogIDsSQL = `SELECT t.TreeID FROM TreeRoots t
@@ -75,6 +83,7 @@ var (
 	colSequenceNumber          = "SequenceNumber"
 	colQueueTimestampNanos     = "QueueTimestampNanos"
 	colIntegrateTimestampNanos = "IntegrateTimestampNanos"
+	colPrunedSize              = "PrunedSize"
 )
 
 // NewLogStorage initialises and returns a new LogStorage.
@@ -147,9 +156,10 @@ func (ls *logStorage) begin(ctx context.Context, tree *trillian.Tree, readonly b
 	}
 
 	return &logTX{
-		ls:       ls,
-		dequeued: make(map[string]*QueuedEntry),
-		treeTX:   tx,
+		ls:            ls,
+		dequeued:      make(map[string]*QueuedEntry),
+		treeTX:        tx,
+		logRootConfig: tree.LogRootConfig,
 	}, nil
 }
 
@@ -302,6 +312,10 @@ type logTX struct {
 	// This is required to recover the primary key for the unsequenced entry in
 	// UpdateSequencedLeaves.
 	dequeued map[string]*QueuedEntry
+
+	// logRootConfig is the format in which the log roots of the tree are
+	// signed, and put back together from the TreeHeads.
+	logRootConfig *trillian.LogRootConfig
 }
 
 func (tx *logTX) getLogStorageConfig() *spannerpb.LogStorageConfig {
@@ -331,7 +345,7 @@ func (tx *logTX) LatestSignedLogRoot(ctx context.Context) (*trillian.SignedLogRo
 		TreeSize:       uint64(currentSTH.TreeSize),
 		Revision:       uint64(currentSTH.TreeRevision),
 		Metadata:       currentSTH.Metadata,
-	}).MarshalBinary()
+	}).MarshalForConfig(tx.logRootConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -702,6 +716,9 @@ WHERE sd.TreeID = @tree_id and sd.SequenceNumber IN UNNEST(@seq_nums)`)
 		}
 		ret = append(ret, l)
 	}
+	if err := tx.markPrunedLeaves(ctx, ret); err != nil {
+		return nil, err
+	}
 
 	return ret, nil
 }
@@ -765,6 +782,9 @@ WHERE sd.TreeID = @tree_id AND sd.SequenceNumber >= @start AND sd.SequenceNumber
 	if len(leaves) > 0 {
 		return nil, fmt.Errorf("inconsistency: unexpected extra data outside range %d, +%d", start, count)
 	}
+	if err := tx.markPrunedLeaves(ctx, ret); err != nil {
+		return nil, err
+	}
 
 	return ret, nil
 }
@@ -835,7 +855,99 @@ func (tx *logTX) getUsingIndex(ctx context.Context, idx string, keys [][]byte, b
 //   member of the returned leaves. We should convert this method to use SQL
 //   rather than denormalising IntegrateTimestampNanos into the index too.
 func (tx *logTX) GetLeavesByHash(ctx context.Context, hashes [][]byte, bySeq bool) ([]*trillian.LogLeaf, error) {
-	return tx.getUsingIndex(ctx, seqDataByMerkleHashIdx, hashes, bySeq)
+	leaves, err := tx.getUsingIndex(ctx, seqDataByMerkleHashIdx, hashes, bySeq)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.markPrunedLeaves(ctx, leaves); err != nil {
+		return nil, err
+	}
+	return leaves, nil
+}
+
+// getPrunedSize returns the number of leaves at the start of the tree which
+// have had their data pruned.
+func (tx *logTX) getPrunedSize(ctx context.Context) (int64, error) {
+	row, err := tx.stx.ReadRow(ctx, prunedLeavesTbl, spanner.Key{tx.treeID}, []string{colPrunedSize})
+	switch {
+	case spanner.ErrCode(err) == codes.NotFound:
+		return 0, nil
+	case err != nil:
+		return 0, err
+	}
+	var prunedSize int64
+	if err := row.Columns(&prunedSize); err != nil {
+		return 0, err
+	}
+	return prunedSize, nil
+}
+
+// markPrunedLeaves sets the DataPruned flag of the leaves whose data has been
+// pruned, and clears whatever is left of their LeafValue and ExtraData.
+func (tx *logTX) markPrunedLeaves(ctx context.Context, leaves []*trillian.LogLeaf) error {
+	if len(leaves) == 0 {
+		return nil
+	}
+	prunedSize, err := tx.getPrunedSize(ctx)
+	if err != nil {
+		return err
+	}
+	for _, leaf := range leaves {
+		if leaf.LeafIndex < prunedSize {
+			leaf.LeafValue, leaf.ExtraData, leaf.DataPruned = nil, nil, true
+		}
+	}
+	return nil
+}
+
+// PruneLeaves drops the data of the oldest leaves which haven't been pruned
+// yet, up to (but excluding) the leaf at index end.
+func (tx *logTX) PruneLeaves(ctx context.Context, end int64, limit int) (int, error) {
+	stx, ok := tx.stx.(*spanner.ReadWriteTransaction)
+	if !ok {
+		return 0, ErrWrongTXType
+	}
+	currentSTH, err := tx.currentSTH(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if end > currentSTH.TreeSize {
+		end = currentSTH.TreeSize
+	}
+	start, err := tx.getPrunedSize(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if last := start + int64(limit); end > last {
+		end = last
+	}
+	if end <= start {
+		return 0, nil
+	}
+
+	stmt := spanner.NewStatement(selectPrunableLeavesSQL)
+	stmt.Params["tree_id"] = tx.treeID
+	stmt.Params["start"] = start
+	stmt.Params["xend"] = end
+
+	var ms []*spanner.Mutation
+	cols := []string{colTreeID, colLeafIdentityHash, colLeafValue, colExtraData}
+	rows := stx.Query(ctx, stmt)
+	if err := rows.Do(func(r *spanner.Row) error {
+		var h []byte
+		if err := r.Columns(&h); err != nil {
+			return err
+		}
+		ms = append(ms, spanner.Update(leafDataTbl, cols, []interface{}{tx.treeID, h, []byte{}, []byte(nil)}))
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	ms = append(ms, spanner.InsertOrUpdate(prunedLeavesTbl, []string{colTreeID, colPrunedSize}, []interface{}{tx.treeID, end}))
+	if err := stx.BufferWrite(ms); err != nil {
+		return 0, fmt.Errorf("bufferwrite(): %v", err)
+	}
+	return int(end - start), nil
 }
 
 // QueuedEntry represents a leaf which was dequeued.
@@ -38,6 +38,36 @@ const (
 	colLeafIndex   = "LeafIndex"
 	colMapRevision = "MapRevision"
 	colLeafHash    = "LeafHash"
+
+	selectEarliestMapRevisionSQL = `SELECT MIN(t.TreeRevision) FROM TreeHeads t
+			WHERE t.TreeID = @tree_id`
+	selectCollectableMapRootsSQL = `SELECT t.TreeRevision FROM TreeHeads t
+			WHERE t.TreeID = @tree_id
+			AND t.TreeRevision < @xend
+			LIMIT @limit`
+
+	// The superseded versions of leaves and subtrees below a revision are those
+	// with a later version at or below it.
+	selectSupersededMapLeavesSQL = `SELECT t0.LeafIndex, t0.MapRevision FROM MapLeafData t0
+			WHERE t0.TreeID = @tree_id
+			AND t0.MapRevision < @xend
+			AND EXISTS(
+				SELECT 1 FROM MapLeafData t1
+				WHERE t1.TreeID = t0.TreeID
+				AND t1.LeafIndex = t0.LeafIndex
+				AND t1.MapRevision > t0.MapRevision
+				AND t1.MapRevision <= @xend)
+			LIMIT @limit`
+	selectSupersededSubtreesSQL = `SELECT t0.SubtreeID, t0.Revision FROM SubtreeData t0
+			WHERE t0.TreeID = @tree_id
+			AND t0.Revision < @xend
+			AND EXISTS(
+				SELECT 1 FROM SubtreeData t1
+				WHERE t1.TreeID = t0.TreeID
+				AND t1.SubtreeID = t0.SubtreeID
+				AND t1.Revision > t0.Revision
+				AND t1.Revision <= @xend)
+			LIMIT @limit`
 )
 
 var errFinished = errors.New("finished")
@@ -333,6 +363,135 @@ func (tx *mapTX) Get(ctx context.Context, revision int64, indexes [][]byte) ([]*
 	return ret, nil
 }
 
+// ListLeaves returns up to limit MapLeaf structs, ordered by index, holding the
+// values at revision of the indexes in the range [start, end), or [start, ∞)
+// if end is nil.
+// An error will be returned if there is a problem with the underlying
+// storage.
+func (tx *mapTX) ListLeaves(ctx context.Context, revision int64, start, end []byte, limit int) ([]*trillian.MapLeaf, error) {
+	ret := make([]*trillian.MapLeaf, 0)
+	if limit <= 0 {
+		return ret, nil
+	}
+	keys := spanner.KeyRange{Start: spanner.Key{tx.treeID}, End: spanner.Key{tx.treeID}, Kind: spanner.ClosedClosed}
+	if start != nil {
+		keys.Start = spanner.Key{tx.treeID, start}
+	}
+	if end != nil {
+		keys.End = spanner.Key{tx.treeID, end}
+		keys.Kind = spanner.ClosedOpen
+	}
+
+	cols := []string{colLeafIndex, colMapRevision, colLeafHash, colLeafValue, colExtraData}
+	var lastIndex []byte
+	rows := tx.stx.Read(ctx, mapLeafDataTbl, keys, cols)
+	err := rows.Do(func(r *spanner.Row) error {
+		var rev int64
+		var leaf trillian.MapLeaf
+		if err := r.Columns(&leaf.Index, &rev, &leaf.LeafHash, &leaf.LeafValue, &leaf.ExtraData); err != nil {
+			return err
+		}
+		// Leaves are stored by descending revision, so the first one we find for
+		// each index which satisfies this condition is good:
+		if rev > revision || (lastIndex != nil && bytes.Equal(leaf.Index, lastIndex)) {
+			return nil
+		}
+		lastIndex = leaf.Index
+		ret = append(ret, &leaf)
+		if len(ret) == limit {
+			return errFinished
+		}
+		return nil
+	})
+	if err != nil && err != errFinished {
+		glog.Errorf("failed to read MapLeafData rows for rev %d from %x: %v", revision, start, err)
+		return nil, err
+	}
+	return ret, nil
+}
+
+// ListChangedKeys returns up to limit indexes >= start, ordered, of the map
+// leaves which were set in the revisions (fromRevision, toRevision].
+// An error will be returned if there is a problem with the underlying
+// storage.
+func (tx *mapTX) ListChangedKeys(ctx context.Context, fromRevision, toRevision int64, start []byte, limit int) ([][]byte, error) {
+	ret := make([][]byte, 0)
+	if limit <= 0 {
+		return ret, nil
+	}
+	if start == nil {
+		start = []byte{}
+	}
+	query := spanner.NewStatement(
+		`SELECT DISTINCT t.LeafIndex FROM MapLeafData t
+				WHERE t.TreeID = @tree_id
+				AND t.LeafIndex >= @start
+				AND t.MapRevision > @from_rev
+				AND t.MapRevision <= @to_rev
+				ORDER BY t.LeafIndex
+				LIMIT @limit`)
+	query.Params["tree_id"] = tx.treeID
+	query.Params["start"] = start
+	query.Params["from_rev"] = fromRevision
+	query.Params["to_rev"] = toRevision
+	query.Params["limit"] = int64(limit)
+
+	rows := tx.stx.Query(ctx, query)
+	err := rows.Do(func(r *spanner.Row) error {
+		var index []byte
+		if err := r.Columns(&index); err != nil {
+			return err
+		}
+		ret = append(ret, index)
+		return nil
+	})
+	if err != nil {
+		glog.Errorf("failed to read MapLeafData rows for revs (%d, %d] from %x: %v", fromRevision, toRevision, start, err)
+		return nil, err
+	}
+	return ret, nil
+}
+
+// ListLeafRevisions returns up to limit revisions, ordered, in the range
+// (fromRevision, toRevision] at which the map leaf with the given index was
+// set.
+// An error will be returned if there is a problem with the underlying
+// storage.
+func (tx *mapTX) ListLeafRevisions(ctx context.Context, index []byte, fromRevision, toRevision int64, limit int) ([]int64, error) {
+	ret := make([]int64, 0)
+	if limit <= 0 {
+		return ret, nil
+	}
+	query := spanner.NewStatement(
+		`SELECT t.MapRevision FROM MapLeafData t
+				WHERE t.TreeID = @tree_id
+				AND t.LeafIndex = @leaf_index
+				AND t.MapRevision > @from_rev
+				AND t.MapRevision <= @to_rev
+				ORDER BY t.MapRevision
+				LIMIT @limit`)
+	query.Params["tree_id"] = tx.treeID
+	query.Params["leaf_index"] = index
+	query.Params["from_rev"] = fromRevision
+	query.Params["to_rev"] = toRevision
+	query.Params["limit"] = int64(limit)
+
+	rows := tx.stx.Query(ctx, query)
+	err := rows.Do(func(r *spanner.Row) error {
+		var rev int64
+		if err := r.Columns(&rev); err != nil {
+			return err
+		}
+		ret = append(ret, rev)
+		return nil
+	})
+	if err != nil {
+		glog.Errorf("failed to read MapLeafData rows for revs (%d, %d] of %x: %v", fromRevision, toRevision, index, err)
+		return nil, err
+	}
+	return ret, nil
+}
+
 // GetSignedMapRoot returns the SignedMapRoot for revision.
 // An error will be returned if there is a problem with the underlying storage.
 func (tx *mapTX) GetSignedMapRoot(ctx context.Context, revision int64) (*trillian.SignedMapRoot, error) {
@@ -359,6 +518,9 @@ func (tx *mapTX) GetSignedMapRoot(ctx context.Context, revision int64) (*trillia
 		return nil, err
 	}
 	if th == nil {
+		if err := tx.checkCollected(ctx, revision); err != nil {
+			return nil, err
+		}
 		if revision == 0 {
 			return nil, storage.ErrTreeNeedsInit
 		}
@@ -366,3 +528,85 @@ func (tx *mapTX) GetSignedMapRoot(ctx context.Context, revision int64) (*trillia
 	}
 	return sthToSMR(th)
 }
+
+// checkCollected returns an error with codes.OutOfRange if revision is below
+// the earliest revision of the map, i.e. it has been garbage collected.
+func (tx *mapTX) checkCollected(ctx context.Context, revision int64) error {
+	query := spanner.NewStatement(selectEarliestMapRevisionSQL)
+	query.Params["tree_id"] = tx.treeID
+
+	var earliest spanner.NullInt64
+	rows := tx.stx.Query(ctx, query)
+	if err := rows.Do(func(r *spanner.Row) error {
+		return r.Columns(&earliest)
+	}); err != nil {
+		return err
+	}
+	if earliest.Valid && revision < earliest.Int64 {
+		return storage.RevisionCollectedError(revision, earliest.Int64)
+	}
+	return nil
+}
+
+// CollectRevisions deletes at most limit records of the map revisions below
+// end, starting with their roots, and returns the number of records deleted.
+func (tx *mapTX) CollectRevisions(ctx context.Context, end int64, limit int) (int, error) {
+	stx, ok := tx.stx.(*spanner.ReadWriteTransaction)
+	if !ok {
+		return 0, ErrWrongTXType
+	}
+	if limit <= 0 {
+		return 0, nil
+	}
+
+	var ms []*spanner.Mutation
+	stmt := spanner.NewStatement(selectCollectableMapRootsSQL)
+	stmt.Params["tree_id"] = tx.treeID
+	stmt.Params["xend"] = end
+	stmt.Params["limit"] = int64(limit)
+	rows := stx.Query(ctx, stmt)
+	if err := rows.Do(func(r *spanner.Row) error {
+		var rev int64
+		if err := r.Columns(&rev); err != nil {
+			return err
+		}
+		ms = append(ms, spanner.Delete(treeHeadTbl, spanner.Key{tx.treeID, rev}))
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	for _, q := range []struct{ table, sql string }{
+		{mapLeafDataTbl, selectSupersededMapLeavesSQL},
+		{subtreeTbl, selectSupersededSubtreesSQL},
+	} {
+		if len(ms) >= limit {
+			break
+		}
+		stmt := spanner.NewStatement(q.sql)
+		stmt.Params["tree_id"] = tx.treeID
+		stmt.Params["xend"] = end
+		stmt.Params["limit"] = int64(limit - len(ms))
+		rows := stx.Query(ctx, stmt)
+		if err := rows.Do(func(r *spanner.Row) error {
+			var id []byte
+			var rev int64
+			if err := r.Columns(&id, &rev); err != nil {
+				return err
+			}
+			ms = append(ms, spanner.Delete(q.table, spanner.Key{tx.treeID, id, rev}))
+			return nil
+		}); err != nil {
+			glog.Errorf("failed to read superseded %s rows below rev %d: %v", q.table, end, err)
+			return 0, err
+		}
+	}
+
+	if len(ms) == 0 {
+		return 0, nil
+	}
+	if err := stx.BufferWrite(ms); err != nil {
+		return 0, fmt.Errorf("bufferwrite(): %v", err)
+	}
+	return len(ms), nil
+}
@@ -7,6 +7,7 @@ import (
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
 	any "github.com/golang/protobuf/ptypes/any"
+	duration "github.com/golang/protobuf/ptypes/duration"
 	math "math"
 )
 
@@ -187,6 +188,36 @@ func (SignatureAlgorithm) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_879d3e919e93c6ba, []int{4}
 }
 
+// Serialization format of the signed roots of a log.
+// Mirrors trillian.LogRootFormat.
+type LogRootFormat int32
+
+const (
+	LogRootFormat_LOG_ROOT_FORMAT_UNKNOWN    LogRootFormat = 0
+	LogRootFormat_LOG_ROOT_FORMAT_V1         LogRootFormat = 1
+	LogRootFormat_LOG_ROOT_FORMAT_CHECKPOINT LogRootFormat = 2
+)
+
+var LogRootFormat_name = map[int32]string{
+	0: "LOG_ROOT_FORMAT_UNKNOWN",
+	1: "LOG_ROOT_FORMAT_V1",
+	2: "LOG_ROOT_FORMAT_CHECKPOINT",
+}
+
+var LogRootFormat_value = map[string]int32{
+	"LOG_ROOT_FORMAT_UNKNOWN":    0,
+	"LOG_ROOT_FORMAT_V1":         1,
+	"LOG_ROOT_FORMAT_CHECKPOINT": 2,
+}
+
+func (x LogRootFormat) String() string {
+	return proto.EnumName(LogRootFormat_name, int32(x))
+}
+
+func (LogRootFormat) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_879d3e919e93c6ba, []int{5}
+}
+
 // LogStorageConfig holds settings which tune the storage implementation for
 // a given log tree.
 type LogStorageConfig struct {
@@ -325,10 +356,18 @@ type TreeInfo struct {
 	// If true the tree was soft deleted.
 	Deleted bool `protobuf:"varint,18,opt,name=deleted,proto3" json:"deleted,omitempty"`
 	// Time of tree deletion, if any.
-	DeleteTimeNanos      int64    `protobuf:"varint,19,opt,name=delete_time_nanos,json=deleteTimeNanos,proto3" json:"delete_time_nanos,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	DeleteTimeNanos int64 `protobuf:"varint,19,opt,name=delete_time_nanos,json=deleteTimeNanos,proto3" json:"delete_time_nanos,omitempty"`
+	// sequencing_config holds the per-tree settings of the log signer.
+	SequencingConfig *SequencingConfig `protobuf:"bytes,20,opt,name=sequencing_config,json=sequencingConfig,proto3" json:"sequencing_config,omitempty"`
+	// retention_policy describes which leaves of a log have their data pruned.
+	RetentionPolicy *RetentionPolicy `protobuf:"bytes,21,opt,name=retention_policy,json=retentionPolicy,proto3" json:"retention_policy,omitempty"`
+	// log_root_config describes how the signed roots of a log are serialized.
+	LogRootConfig *LogRootConfig `protobuf:"bytes,22,opt,name=log_root_config,json=logRootConfig,proto3" json:"log_root_config,omitempty"`
+	// map_retention_policy describes which revisions of a map are collected.
+	MapRetentionPolicy   *MapRetentionPolicy `protobuf:"bytes,23,opt,name=map_retention_policy,json=mapRetentionPolicy,proto3" json:"map_retention_policy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
 }
 
 func (m *TreeInfo) Reset()         { *m = TreeInfo{} }
@@ -505,6 +544,34 @@ func (m *TreeInfo) GetDeleteTimeNanos() int64 {
 	return 0
 }
 
+func (m *TreeInfo) GetSequencingConfig() *SequencingConfig {
+	if m != nil {
+		return m.SequencingConfig
+	}
+	return nil
+}
+
+func (m *TreeInfo) GetRetentionPolicy() *RetentionPolicy {
+	if m != nil {
+		return m.RetentionPolicy
+	}
+	return nil
+}
+
+func (m *TreeInfo) GetLogRootConfig() *LogRootConfig {
+	if m != nil {
+		return m.LogRootConfig
+	}
+	return nil
+}
+
+func (m *TreeInfo) GetMapRetentionPolicy() *MapRetentionPolicy {
+	if m != nil {
+		return m.MapRetentionPolicy
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*TreeInfo) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -513,6 +580,218 @@ func (*TreeInfo) XXX_OneofWrappers() []interface{} {
 	}
 }
 
+// SequencingConfig holds the per-tree settings of the log signer.
+// Mirrors trillian.SequencingConfig.
+type SequencingConfig struct {
+	Paused               bool               `protobuf:"varint,1,opt,name=paused,proto3" json:"paused,omitempty"`
+	BatchSize            int32              `protobuf:"varint,2,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`
+	GuardWindow          *duration.Duration `protobuf:"bytes,3,opt,name=guard_window,json=guardWindow,proto3" json:"guard_window,omitempty"`
+	MinRootInterval      *duration.Duration `protobuf:"bytes,4,opt,name=min_root_interval,json=minRootInterval,proto3" json:"min_root_interval,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *SequencingConfig) Reset()         { *m = SequencingConfig{} }
+func (m *SequencingConfig) String() string { return proto.CompactTextString(m) }
+func (*SequencingConfig) ProtoMessage()    {}
+func (*SequencingConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_879d3e919e93c6ba, []int{3}
+}
+
+func (m *SequencingConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SequencingConfig.Unmarshal(m, b)
+}
+func (m *SequencingConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SequencingConfig.Marshal(b, m, deterministic)
+}
+func (m *SequencingConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SequencingConfig.Merge(m, src)
+}
+func (m *SequencingConfig) XXX_Size() int {
+	return xxx_messageInfo_SequencingConfig.Size(m)
+}
+func (m *SequencingConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_SequencingConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SequencingConfig proto.InternalMessageInfo
+
+func (m *SequencingConfig) GetPaused() bool {
+	if m != nil {
+		return m.Paused
+	}
+	return false
+}
+
+func (m *SequencingConfig) GetBatchSize() int32 {
+	if m != nil {
+		return m.BatchSize
+	}
+	return 0
+}
+
+func (m *SequencingConfig) GetGuardWindow() *duration.Duration {
+	if m != nil {
+		return m.GuardWindow
+	}
+	return nil
+}
+
+func (m *SequencingConfig) GetMinRootInterval() *duration.Duration {
+	if m != nil {
+		return m.MinRootInterval
+	}
+	return nil
+}
+
+// RetentionPolicy describes which leaves of a log have their data pruned.
+// Mirrors trillian.RetentionPolicy.
+type RetentionPolicy struct {
+	MaxEntries           int64              `protobuf:"varint,1,opt,name=max_entries,json=maxEntries,proto3" json:"max_entries,omitempty"`
+	MaxAge               *duration.Duration `protobuf:"bytes,2,opt,name=max_age,json=maxAge,proto3" json:"max_age,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *RetentionPolicy) Reset()         { *m = RetentionPolicy{} }
+func (m *RetentionPolicy) String() string { return proto.CompactTextString(m) }
+func (*RetentionPolicy) ProtoMessage()    {}
+func (*RetentionPolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_879d3e919e93c6ba, []int{4}
+}
+
+func (m *RetentionPolicy) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RetentionPolicy.Unmarshal(m, b)
+}
+func (m *RetentionPolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RetentionPolicy.Marshal(b, m, deterministic)
+}
+func (m *RetentionPolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RetentionPolicy.Merge(m, src)
+}
+func (m *RetentionPolicy) XXX_Size() int {
+	return xxx_messageInfo_RetentionPolicy.Size(m)
+}
+func (m *RetentionPolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_RetentionPolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RetentionPolicy proto.InternalMessageInfo
+
+func (m *RetentionPolicy) GetMaxEntries() int64 {
+	if m != nil {
+		return m.MaxEntries
+	}
+	return 0
+}
+
+func (m *RetentionPolicy) GetMaxAge() *duration.Duration {
+	if m != nil {
+		return m.MaxAge
+	}
+	return nil
+}
+
+// MapRetentionPolicy describes which revisions of a map are collected.
+// Mirrors trillian.MapRetentionPolicy.
+type MapRetentionPolicy struct {
+	MaxRevisions         int64              `protobuf:"varint,1,opt,name=max_revisions,json=maxRevisions,proto3" json:"max_revisions,omitempty"`
+	MaxAge               *duration.Duration `protobuf:"bytes,2,opt,name=max_age,json=maxAge,proto3" json:"max_age,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *MapRetentionPolicy) Reset()         { *m = MapRetentionPolicy{} }
+func (m *MapRetentionPolicy) String() string { return proto.CompactTextString(m) }
+func (*MapRetentionPolicy) ProtoMessage()    {}
+func (*MapRetentionPolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_879d3e919e93c6ba, []int{5}
+}
+
+func (m *MapRetentionPolicy) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MapRetentionPolicy.Unmarshal(m, b)
+}
+func (m *MapRetentionPolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MapRetentionPolicy.Marshal(b, m, deterministic)
+}
+func (m *MapRetentionPolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MapRetentionPolicy.Merge(m, src)
+}
+func (m *MapRetentionPolicy) XXX_Size() int {
+	return xxx_messageInfo_MapRetentionPolicy.Size(m)
+}
+func (m *MapRetentionPolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_MapRetentionPolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MapRetentionPolicy proto.InternalMessageInfo
+
+func (m *MapRetentionPolicy) GetMaxRevisions() int64 {
+	if m != nil {
+		return m.MaxRevisions
+	}
+	return 0
+}
+
+func (m *MapRetentionPolicy) GetMaxAge() *duration.Duration {
+	if m != nil {
+		return m.MaxAge
+	}
+	return nil
+}
+
+// LogRootConfig describes how the signed roots of a log are serialized.
+// Mirrors trillian.LogRootConfig.
+type LogRootConfig struct {
+	Format               LogRootFormat `protobuf:"varint,1,opt,name=format,proto3,enum=spannerpb.LogRootFormat" json:"format,omitempty"`
+	Origin               string        `protobuf:"bytes,2,opt,name=origin,proto3" json:"origin,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *LogRootConfig) Reset()         { *m = LogRootConfig{} }
+func (m *LogRootConfig) String() string { return proto.CompactTextString(m) }
+func (*LogRootConfig) ProtoMessage()    {}
+func (*LogRootConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_879d3e919e93c6ba, []int{6}
+}
+
+func (m *LogRootConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogRootConfig.Unmarshal(m, b)
+}
+func (m *LogRootConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogRootConfig.Marshal(b, m, deterministic)
+}
+func (m *LogRootConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogRootConfig.Merge(m, src)
+}
+func (m *LogRootConfig) XXX_Size() int {
+	return xxx_messageInfo_LogRootConfig.Size(m)
+}
+func (m *LogRootConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogRootConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogRootConfig proto.InternalMessageInfo
+
+func (m *LogRootConfig) GetFormat() LogRootFormat {
+	if m != nil {
+		return m.Format
+	}
+	return LogRootFormat_LOG_ROOT_FORMAT_UNKNOWN
+}
+
+func (m *LogRootConfig) GetOrigin() string {
+	if m != nil {
+		return m.Origin
+	}
+	return ""
+}
+
 // TreeHead is the storage format for Trillian's commitment to a particular
 // tree state.
 type TreeHead struct {
@@ -540,7 +819,7 @@ func (m *TreeHead) Reset()         { *m = TreeHead{} }
 func (m *TreeHead) String() string { return proto.CompactTextString(m) }
 func (*TreeHead) ProtoMessage()    {}
 func (*TreeHead) Descriptor() ([]byte, []int) {
-	return fileDescriptor_879d3e919e93c6ba, []int{3}
+	return fileDescriptor_879d3e919e93c6ba, []int{7}
 }
 
 func (m *TreeHead) XXX_Unmarshal(b []byte) error {
@@ -616,74 +895,101 @@ func init() {
 	proto.RegisterEnum("spannerpb.HashStrategy", HashStrategy_name, HashStrategy_value)
 	proto.RegisterEnum("spannerpb.HashAlgorithm", HashAlgorithm_name, HashAlgorithm_value)
 	proto.RegisterEnum("spannerpb.SignatureAlgorithm", SignatureAlgorithm_name, SignatureAlgorithm_value)
+	proto.RegisterEnum("spannerpb.LogRootFormat", LogRootFormat_name, LogRootFormat_value)
 	proto.RegisterType((*LogStorageConfig)(nil), "spannerpb.LogStorageConfig")
 	proto.RegisterType((*MapStorageConfig)(nil), "spannerpb.MapStorageConfig")
 	proto.RegisterType((*TreeInfo)(nil), "spannerpb.TreeInfo")
+	proto.RegisterType((*SequencingConfig)(nil), "spannerpb.SequencingConfig")
+	proto.RegisterType((*RetentionPolicy)(nil), "spannerpb.RetentionPolicy")
+	proto.RegisterType((*MapRetentionPolicy)(nil), "spannerpb.MapRetentionPolicy")
+	proto.RegisterType((*LogRootConfig)(nil), "spannerpb.LogRootConfig")
 	proto.RegisterType((*TreeHead)(nil), "spannerpb.TreeHead")
 }
 
 func init() { proto.RegisterFile("spanner.proto", fileDescriptor_879d3e919e93c6ba) }
 
 var fileDescriptor_879d3e919e93c6ba = []byte{
-	// 958 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x55, 0xed, 0x6e, 0x1a, 0x47,
-	0x14, 0xf5, 0x1a, 0x0c, 0xcb, 0x35, 0xd8, 0xe3, 0x71, 0xdc, 0xac, 0x93, 0x56, 0x42, 0x6e, 0x2b,
-	0x51, 0x54, 0x41, 0xeb, 0xc8, 0x8e, 0xa2, 0x54, 0xaa, 0xd6, 0x18, 0x07, 0x9b, 0xb0, 0x44, 0xb3,
-	0xeb, 0x56, 0xc9, 0x9f, 0xd5, 0xc0, 0x8e, 0x61, 0xe5, 0xfd, 0xea, 0xee, 0x6c, 0x14, 0xf2, 0x0c,
-	0xfd, 0xd3, 0xc7, 0xec, 0x5b, 0x54, 0x33, 0xb3, 0x60, 0x8c, 0xd5, 0x7f, 0x33, 0xe7, 0x9c, 0x7b,
-	0xaf, 0xe7, 0xfa, 0x9c, 0x05, 0x1a, 0x59, 0x42, 0xa3, 0x88, 0xa5, 0x9d, 0x24, 0x8d, 0x79, 0x8c,
-	0x6b, 0xc5, 0x35, 0x99, 0xbc, 0x38, 0x9e, 0xc5, 0xf1, 0x2c, 0x60, 0x5d, 0x49, 0x4c, 0xf2, 0xbb,
-	0x2e, 0x8d, 0x16, 0x4a, 0x75, 0x12, 0x00, 0x7a, 0x1f, 0xcf, 0x6c, 0x1e, 0xa7, 0x74, 0xc6, 0x7a,
-	0x71, 0x74, 0xe7, 0xcf, 0x70, 0x1b, 0x0e, 0xa2, 0x3c, 0x74, 0xf3, 0x28, 0x63, 0x7f, 0xb9, 0x93,
-	0x7c, 0x7a, 0xcf, 0x78, 0x66, 0x68, 0x4d, 0xad, 0x55, 0x22, 0xfb, 0x51, 0x1e, 0xde, 0x0a, 0xfc,
-	0x42, 0xc1, 0xf8, 0x67, 0xc0, 0x42, 0x1b, 0xb2, 0xf4, 0x3e, 0x60, 0x2b, 0xf1, 0xb6, 0x14, 0xa3,
-	0x28, 0x0f, 0x47, 0x92, 0x28, 0xd4, 0x27, 0x18, 0xd0, 0x88, 0x26, 0x8f, 0xa6, 0x9d, 0xfc, 0x5d,
-	0x05, 0xdd, 0x49, 0x19, 0xbb, 0x8e, 0xee, 0x62, 0xfc, 0x1c, 0xaa, 0x3c, 0x65, 0xcc, 0xf5, 0xbd,
-	0x62, 0x60, 0x45, 0x5c, 0xaf, 0x3d, 0x7c, 0x04, 0x95, 0x7b, 0xb6, 0x10, 0xb8, 0xea, 0xbd, 0x73,
-	0xcf, 0x16, 0xd7, 0x1e, 0xc6, 0x50, 0x8e, 0x68, 0xc8, 0x8c, 0x52, 0x53, 0x6b, 0xd5, 0x88, 0x3c,
-	0xe3, 0x26, 0xec, 0x7a, 0x2c, 0x9b, 0xa6, 0x7e, 0xc2, 0xfd, 0x38, 0x32, 0xca, 0x92, 0x5a, 0x87,
-	0xf0, 0x2f, 0x50, 0x93, 0x53, 0xf8, 0x22, 0x61, 0xc6, 0x4e, 0x53, 0x6b, 0xed, 0x9d, 0x1e, 0x76,
-	0x56, 0xeb, 0xea, 0x88, 0xbf, 0xc6, 0x59, 0x24, 0x8c, 0xe8, 0xbc, 0x38, 0xe1, 0x57, 0x00, 0xb2,
-	0x22, 0xe3, 0x94, 0x33, 0x43, 0x97, 0x25, 0xcf, 0x36, 0x4a, 0x6c, 0xc1, 0x11, 0xd9, 0x59, 0x1e,
-	0xf1, 0x6f, 0xd0, 0x98, 0xd3, 0x6c, 0xee, 0x66, 0x3c, 0xa5, 0x9c, 0xcd, 0x16, 0x46, 0x4d, 0xd6,
-	0x3d, 0x5f, 0xab, 0x1b, 0xd0, 0x6c, 0x6e, 0x17, 0x34, 0xa9, 0xcf, 0xd7, 0x6e, 0xf8, 0x77, 0xd8,
-	0x93, 0xd5, 0x34, 0x98, 0xc5, 0xa9, 0xcf, 0xe7, 0xa1, 0x01, 0xb2, 0xdc, 0xd8, 0x28, 0x37, 0x97,
-	0x3c, 0x91, 0xd3, 0x56, 0x57, 0x6c, 0xc1, 0x61, 0xe6, 0xcf, 0x22, 0xca, 0xf3, 0x94, 0xad, 0x75,
-	0xd9, 0x95, 0x5d, 0xbe, 0x5b, 0xeb, 0x62, 0x2f, 0x55, 0x0f, 0xad, 0x70, 0xf6, 0x04, 0x13, 0xb6,
-	0x98, 0xa6, 0x8c, 0x72, 0xe6, 0x72, 0x3f, 0x64, 0x6e, 0x44, 0xa3, 0x38, 0x33, 0x1a, 0xca, 0x16,
-	0x8a, 0x70, 0xfc, 0x90, 0x59, 0x02, 0x16, 0xda, 0x3c, 0xf1, 0x36, 0xb4, 0x7b, 0x4a, 0xab, 0x88,
-	0x07, 0xed, 0x19, 0xec, 0x26, 0xa9, 0xff, 0x59, 0x88, 0xef, 0xd9, 0xc2, 0xd8, 0x6f, 0x6a, 0xad,
-	0xdd, 0xd3, 0x67, 0x1d, 0xe5, 0xd9, 0xce, 0xd2, 0xb3, 0x1d, 0x33, 0x5a, 0x10, 0x28, 0x84, 0x43,
-	0xb6, 0xc0, 0x3f, 0xc0, 0x5e, 0x92, 0x4f, 0x02, 0x7f, 0x2a, 0xaa, 0x5c, 0x8f, 0xa5, 0x06, 0x6a,
-	0x6a, 0xad, 0x3a, 0xa9, 0x2b, 0x74, 0xc8, 0x16, 0x97, 0x2c, 0xc5, 0x43, 0xc0, 0x41, 0x3c, 0x73,
-	0x33, 0x65, 0x39, 0x77, 0x2a, 0x3d, 0x67, 0x54, 0xe4, 0x8c, 0x97, 0x6b, 0x3b, 0xd8, 0x0c, 0xc1,
-	0x60, 0x8b, 0xa0, 0x60, 0x33, 0x18, 0x43, 0xc0, 0x21, 0x4d, 0x36, 0x9b, 0x55, 0x9f, 0x34, 0xdb,
-	0xf4, 0xb8, 0x68, 0x16, 0x6e, 0x60, 0xf8, 0x35, 0x18, 0x21, 0xfd, 0xe2, 0xa6, 0x71, 0xcc, 0x5d,
-	0x2f, 0x4f, 0xa9, 0x70, 0xa6, 0x1b, 0xfa, 0x41, 0xe0, 0x67, 0xc6, 0x81, 0xdc, 0xd4, 0x51, 0x48,
-	0xbf, 0x90, 0x38, 0xe6, 0x97, 0x05, 0x3b, 0x92, 0x24, 0x36, 0xa0, 0xea, 0xb1, 0x80, 0x71, 0xe6,
-	0x19, 0xb8, 0xa9, 0xb5, 0x74, 0xb2, 0xbc, 0x8a, 0xad, 0xab, 0xe3, 0xfa, 0xd6, 0x0f, 0xd5, 0xd6,
-	0x15, 0xb1, 0xda, 0xfa, 0x05, 0x82, 0xbd, 0xc7, 0xef, 0xb8, 0x29, 0xeb, 0x75, 0xd4, 0x38, 0xf9,
-	0x57, 0x53, 0x71, 0x1c, 0x30, 0xea, 0xfd, 0x7f, 0x1c, 0x8f, 0x41, 0xe7, 0x59, 0x31, 0x40, 0x05,
-	0xb2, 0xca, 0x33, 0xf5, 0xef, 0x7c, 0x59, 0x84, 0x2b, 0xf3, 0xbf, 0xaa, 0x5c, 0x96, 0x54, 0x8e,
-	0x6c, 0xff, 0x2b, 0x13, 0xa4, 0x7c, 0xb0, 0x70, 0xaa, 0x4c, 0x66, 0x9d, 0xe8, 0x02, 0x10, 0x46,
-	0xc6, 0xdf, 0x42, 0x6d, 0x65, 0x3b, 0x69, 0xf6, 0x3a, 0x79, 0x00, 0xf0, 0xf7, 0xd0, 0x90, 0x7d,
-	0x53, 0xf6, 0xd9, 0xcf, 0x44, 0xb0, 0x2b, 0xb2, 0x77, 0x5d, 0x80, 0xa4, 0xc0, 0xf0, 0x0b, 0xd0,
-	0x43, 0xc6, 0xa9, 0x47, 0x39, 0x95, 0x69, 0xab, 0x93, 0xd5, 0xfd, 0xa6, 0xac, 0xef, 0xa0, 0xca,
-	0x4d, 0x59, 0xd7, 0x51, 0xed, 0xa6, 0xac, 0x57, 0x91, 0xde, 0x7e, 0x0b, 0xb5, 0x55, 0x70, 0xf1,
-	0x37, 0x80, 0x6f, 0xad, 0xa1, 0x35, 0xfe, 0xd3, 0x72, 0x1d, 0xd2, 0xef, 0xbb, 0xb6, 0x63, 0x3a,
-	0x7d, 0xb4, 0x85, 0x01, 0x2a, 0x66, 0xcf, 0xb9, 0xfe, 0xa3, 0x8f, 0x34, 0x71, 0xbe, 0x22, 0xe3,
-	0x4f, 0x7d, 0x0b, 0x6d, 0xb7, 0x7f, 0x52, 0x7b, 0x92, 0x9f, 0x87, 0x5d, 0xa8, 0x16, 0xb5, 0x68,
-	0x0b, 0x57, 0xa1, 0xf4, 0x7e, 0xfc, 0x0e, 0x69, 0xe2, 0x30, 0x32, 0x3f, 0xa0, 0xed, 0xf6, 0x3f,
-	0x1a, 0xd4, 0xd7, 0x93, 0x8e, 0x8f, 0xe1, 0x68, 0x39, 0x6b, 0x60, 0xda, 0x03, 0xd7, 0x76, 0x88,
-	0xe9, 0xf4, 0xdf, 0x7d, 0x44, 0x5b, 0xb8, 0x0e, 0x3a, 0xb9, 0xea, 0xb9, 0xe7, 0x6f, 0xce, 0x4f,
-	0x91, 0x86, 0x0f, 0x61, 0xdf, 0xe9, 0xdb, 0x8e, 0x3b, 0x32, 0x3f, 0x48, 0x65, 0x9f, 0xa0, 0x6d,
-	0x51, 0x3d, 0xbe, 0xb8, 0xe9, 0xf7, 0x1c, 0x97, 0x5c, 0xf5, 0x84, 0xd0, 0xb5, 0x07, 0xe6, 0xe9,
-	0xd9, 0x39, 0x2a, 0xe1, 0x23, 0x38, 0xe8, 0x8d, 0xad, 0xeb, 0xa1, 0x2d, 0xa0, 0xb3, 0x5f, 0x4f,
-	0x5d, 0x01, 0x97, 0xf1, 0x01, 0x34, 0x1e, 0x60, 0x01, 0xed, 0xb4, 0x7f, 0x84, 0xc6, 0xa3, 0xaf,
-	0x07, 0xd6, 0xa1, 0x6c, 0x8d, 0xad, 0xe2, 0xc5, 0x85, 0xac, 0xdc, 0x7e, 0x0d, 0xf8, 0xe9, 0xe7,
-	0x01, 0x37, 0xa0, 0x66, 0x5a, 0x63, 0xeb, 0xe3, 0x68, 0x7c, 0x6b, 0xab, 0x17, 0x13, 0xdb, 0x44,
-	0x1a, 0xae, 0xc1, 0x4e, 0xbf, 0x77, 0x69, 0x9b, 0xa8, 0x74, 0xf1, 0xf6, 0xd3, 0x9b, 0x99, 0xcf,
-	0xe7, 0xf9, 0xa4, 0x33, 0x8d, 0xc3, 0x6e, 0xf1, 0x03, 0xc4, 0x53, 0x61, 0x61, 0x1a, 0x75, 0x0b,
-	0xeb, 0x75, 0xa7, 0x41, 0x9c, 0x7b, 0x45, 0x70, 0xba, 0xab, 0x00, 0x4d, 0x2a, 0x32, 0xf5, 0xaf,
-	0xfe, 0x0b, 0x00, 0x00, 0xff, 0xff, 0xba, 0x57, 0xcd, 0x18, 0xd3, 0x06, 0x00, 0x00,
+	// 1306 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0xff, 0x4e, 0xdb, 0xc8,
+	0x16, 0xc6, 0x10, 0x12, 0xfb, 0x24, 0x01, 0x33, 0xfc, 0x32, 0x70, 0xdb, 0x8b, 0xb8, 0xf7, 0x4a,
+	0x5c, 0xb4, 0x82, 0x96, 0xaa, 0xad, 0xaa, 0xae, 0xb4, 0x6b, 0x52, 0xd3, 0x00, 0x25, 0x46, 0x63,
+	0xb7, 0x55, 0xfb, 0x8f, 0x35, 0x89, 0x07, 0xc7, 0xc2, 0x3f, 0x52, 0x7b, 0xdc, 0x92, 0xbe, 0xc5,
+	0xbe, 0xc9, 0x3e, 0xc9, 0xbe, 0xc7, 0xbe, 0xc5, 0x6a, 0xc6, 0x76, 0x48, 0x1c, 0xed, 0xae, 0xb4,
+	0xff, 0x79, 0xbe, 0xf3, 0x9d, 0x73, 0x66, 0xbe, 0x39, 0x73, 0x8e, 0xa1, 0x9d, 0x8e, 0x48, 0x14,
+	0xd1, 0xe4, 0x78, 0x94, 0xc4, 0x2c, 0x46, 0x4a, 0xb1, 0x1c, 0xf5, 0x77, 0x77, 0xbc, 0x38, 0xf6,
+	0x02, 0x7a, 0x22, 0x0c, 0xfd, 0xec, 0xf6, 0x84, 0x44, 0xe3, 0x9c, 0xb5, 0xfb, 0xb8, 0x6a, 0x72,
+	0xb3, 0x84, 0x30, 0x3f, 0x8e, 0x72, 0xfb, 0x41, 0x00, 0xea, 0xbb, 0xd8, 0xb3, 0x58, 0x9c, 0x10,
+	0x8f, 0x76, 0xe2, 0xe8, 0xd6, 0xf7, 0xd0, 0x11, 0xac, 0x45, 0x59, 0xe8, 0x64, 0x51, 0x4a, 0xbf,
+	0x38, 0xfd, 0x6c, 0x70, 0x47, 0x59, 0xaa, 0x49, 0xfb, 0xd2, 0xe1, 0x12, 0x5e, 0x8d, 0xb2, 0xf0,
+	0x3d, 0xc7, 0xcf, 0x72, 0x18, 0xfd, 0x00, 0x88, 0x73, 0x43, 0x9a, 0xdc, 0x05, 0x74, 0x42, 0x5e,
+	0x14, 0x64, 0x35, 0xca, 0xc2, 0x6b, 0x61, 0x28, 0xd8, 0x07, 0x08, 0xd4, 0x6b, 0x32, 0x9a, 0xc9,
+	0x76, 0xf0, 0xab, 0x02, 0xb2, 0x9d, 0x50, 0x7a, 0x11, 0xdd, 0xc6, 0x68, 0x1b, 0x1a, 0x2c, 0xa1,
+	0xd4, 0xf1, 0xdd, 0x22, 0x61, 0x9d, 0x2f, 0x2f, 0x5c, 0xb4, 0x09, 0xf5, 0x3b, 0x3a, 0xe6, 0x78,
+	0x1e, 0x7b, 0xf9, 0x8e, 0x8e, 0x2f, 0x5c, 0x84, 0xa0, 0x16, 0x91, 0x90, 0x6a, 0x4b, 0xfb, 0xd2,
+	0xa1, 0x82, 0xc5, 0x37, 0xda, 0x87, 0xa6, 0x4b, 0xd3, 0x41, 0xe2, 0x8f, 0xf8, 0x39, 0xb5, 0x9a,
+	0x30, 0x4d, 0x43, 0xe8, 0x09, 0x28, 0x22, 0x0b, 0x1b, 0x8f, 0xa8, 0xb6, 0xbc, 0x2f, 0x1d, 0xae,
+	0x9c, 0xae, 0x1f, 0x4f, 0xe4, 0x3c, 0xe6, 0xbb, 0xb1, 0xc7, 0x23, 0x8a, 0x65, 0x56, 0x7c, 0xa1,
+	0x67, 0x00, 0xc2, 0x23, 0x65, 0x84, 0x51, 0x4d, 0x16, 0x2e, 0x1b, 0x15, 0x17, 0x8b, 0xdb, 0xb0,
+	0xc2, 0xca, 0x4f, 0xf4, 0x23, 0xb4, 0x87, 0x24, 0x1d, 0x3a, 0x29, 0x4b, 0x08, 0xa3, 0xde, 0x58,
+	0x53, 0x84, 0xdf, 0xf6, 0x94, 0x5f, 0x97, 0xa4, 0x43, 0xab, 0x30, 0xe3, 0xd6, 0x70, 0x6a, 0x85,
+	0x7e, 0x82, 0x15, 0xe1, 0x4d, 0x02, 0x2f, 0x4e, 0x7c, 0x36, 0x0c, 0x35, 0x10, 0xee, 0x5a, 0xc5,
+	0x5d, 0x2f, 0xed, 0xb8, 0x3d, 0x9c, 0x5e, 0xa2, 0x1e, 0xac, 0xa7, 0xbe, 0x17, 0x11, 0x96, 0x25,
+	0x74, 0x2a, 0x4a, 0x53, 0x44, 0x79, 0x34, 0x15, 0xc5, 0x2a, 0x59, 0x0f, 0xa1, 0x50, 0x3a, 0x87,
+	0xf1, 0xb2, 0x18, 0x24, 0x94, 0x30, 0xea, 0x30, 0x3f, 0xa4, 0x4e, 0x44, 0xa2, 0x38, 0xd5, 0xda,
+	0x79, 0x59, 0xe4, 0x06, 0xdb, 0x0f, 0x69, 0x8f, 0xc3, 0x9c, 0x9b, 0x8d, 0xdc, 0x0a, 0x77, 0x25,
+	0xe7, 0xe6, 0x86, 0x07, 0xee, 0x73, 0x68, 0x8e, 0x12, 0xff, 0x2b, 0x27, 0xdf, 0xd1, 0xb1, 0xb6,
+	0xba, 0x2f, 0x1d, 0x36, 0x4f, 0x37, 0x8e, 0xf3, 0xc2, 0x3d, 0x2e, 0x0b, 0xf7, 0x58, 0x8f, 0xc6,
+	0x18, 0x0a, 0xe2, 0x15, 0x1d, 0xa3, 0xff, 0xc2, 0xca, 0x28, 0xeb, 0x07, 0xfe, 0x80, 0x7b, 0x39,
+	0x2e, 0x4d, 0x34, 0x75, 0x5f, 0x3a, 0x6c, 0xe1, 0x56, 0x8e, 0x5e, 0xd1, 0xf1, 0x1b, 0x9a, 0xa0,
+	0x2b, 0x40, 0x41, 0xec, 0x39, 0x69, 0x5e, 0x72, 0xce, 0x40, 0xd4, 0x9c, 0x56, 0x17, 0x39, 0xf6,
+	0xa6, 0x34, 0xa8, 0x3e, 0x82, 0xee, 0x02, 0x56, 0x83, 0x0a, 0xc6, 0x83, 0x85, 0x64, 0x54, 0x0d,
+	0xd6, 0x98, 0x0b, 0x56, 0xad, 0x71, 0x1e, 0x2c, 0xac, 0x60, 0xe8, 0x25, 0x68, 0x21, 0xb9, 0x77,
+	0x92, 0x38, 0x66, 0x4e, 0xf9, 0x28, 0x9d, 0xd0, 0x0f, 0x02, 0x3f, 0xd5, 0xd6, 0x84, 0x52, 0x9b,
+	0x21, 0xb9, 0xc7, 0x71, 0xcc, 0xde, 0x14, 0xd6, 0x6b, 0x61, 0x44, 0x1a, 0x34, 0x5c, 0x1a, 0x50,
+	0x46, 0x5d, 0x0d, 0xed, 0x4b, 0x87, 0x32, 0x2e, 0x97, 0x5c, 0xf5, 0xfc, 0x73, 0x5a, 0xf5, 0xf5,
+	0x5c, 0xf5, 0xdc, 0xf0, 0xa0, 0x7a, 0x17, 0xd6, 0x52, 0xfa, 0x25, 0xa3, 0xd1, 0xc0, 0x8f, 0xbc,
+	0xf2, 0x28, 0x1b, 0x73, 0x47, 0xb1, 0x26, 0x9c, 0x7c, 0xdb, 0x58, 0x4d, 0x2b, 0x08, 0x32, 0x40,
+	0x4d, 0x28, 0xa3, 0x91, 0x38, 0xc0, 0x28, 0x0e, 0xfc, 0xc1, 0x58, 0xdb, 0x14, 0x81, 0x76, 0xa7,
+	0x02, 0xe1, 0x92, 0x72, 0x23, 0x18, 0x78, 0x35, 0x99, 0x05, 0xd0, 0xcf, 0xb0, 0xca, 0x6f, 0x4a,
+	0xe8, 0x51, 0x6c, 0x67, 0x4b, 0x44, 0xd1, 0x66, 0xaf, 0x89, 0x2b, 0x52, 0xec, 0xa5, 0x1d, 0x4c,
+	0x2f, 0x91, 0x09, 0x1b, 0xfc, 0x7a, 0xe6, 0x36, 0xb3, 0x2d, 0xc2, 0x3c, 0x9a, 0xbd, 0xa0, 0xea,
+	0x7e, 0x50, 0x38, 0x87, 0x9d, 0xa9, 0xb0, 0x32, 0x7b, 0xd7, 0x97, 0x35, 0xb9, 0xa5, 0xb6, 0x0f,
+	0x7e, 0x93, 0x40, 0xad, 0x0a, 0x83, 0xb6, 0xa0, 0x3e, 0x22, 0x59, 0x4a, 0xf3, 0xce, 0x25, 0xe3,
+	0x62, 0x85, 0x1e, 0x01, 0xf4, 0x09, 0x1b, 0x0c, 0x9d, 0xd4, 0xff, 0x4e, 0x45, 0xf7, 0x5a, 0xc6,
+	0x8a, 0x40, 0x2c, 0xff, 0x3b, 0x6f, 0x12, 0x2d, 0x2f, 0x23, 0x89, 0xeb, 0x7c, 0xf3, 0x23, 0x37,
+	0xfe, 0x26, 0x3a, 0x59, 0xf3, 0x74, 0x67, 0xae, 0xfc, 0xcb, 0x22, 0xc0, 0x4d, 0x41, 0xff, 0x28,
+	0xd8, 0xc8, 0x80, 0xb5, 0xd0, 0x8f, 0x72, 0xd1, 0xfc, 0x88, 0xd1, 0xe4, 0x2b, 0x09, 0xb4, 0xda,
+	0xdf, 0x85, 0x58, 0x0d, 0xfd, 0x88, 0xeb, 0x76, 0x51, 0x78, 0x1c, 0xdc, 0xc2, 0x6a, 0xe5, 0xec,
+	0xe8, 0xdf, 0xd0, 0xe4, 0xe5, 0x49, 0x23, 0x96, 0xf8, 0xb4, 0x6c, 0xff, 0x10, 0x92, 0x7b, 0x23,
+	0x47, 0xd0, 0x29, 0x34, 0x38, 0x81, 0x78, 0xf9, 0xa1, 0xfe, 0x32, 0x61, 0x3d, 0x24, 0xf7, 0xba,
+	0x47, 0x0f, 0x42, 0x40, 0xf3, 0xd2, 0xa3, 0xff, 0x40, 0x5b, 0xbc, 0x04, 0xfa, 0xd5, 0x4f, 0xfd,
+	0x38, 0x2a, 0x93, 0xb5, 0x78, 0xf9, 0x97, 0xd8, 0x3f, 0x4a, 0xf7, 0x09, 0xda, 0x33, 0x05, 0x83,
+	0x9e, 0x40, 0xfd, 0x36, 0x4e, 0x42, 0xc2, 0x34, 0x69, 0xae, 0x97, 0x16, 0xcc, 0x73, 0x61, 0xc7,
+	0x05, 0x8f, 0xdf, 0x6a, 0x9c, 0xf8, 0x9e, 0x1f, 0x89, 0xac, 0x0a, 0x2e, 0x56, 0x07, 0xbf, 0x4b,
+	0xf9, 0xd4, 0xea, 0x52, 0xe2, 0xfe, 0xf9, 0xd4, 0xda, 0x01, 0x99, 0xa5, 0xc5, 0x3b, 0xcc, 0xe7,
+	0x56, 0x83, 0xa5, 0xf9, 0xfb, 0xdb, 0x2b, 0x66, 0x90, 0xa8, 0x8a, 0x25, 0x61, 0x13, 0xe3, 0x46,
+	0x14, 0xc5, 0x1e, 0x28, 0xe2, 0x4a, 0x79, 0x43, 0x17, 0xd7, 0xd9, 0xc2, 0x32, 0x07, 0x78, 0xbf,
+	0x47, 0xff, 0x02, 0x65, 0xd2, 0x9d, 0xc5, 0x4c, 0x68, 0xe1, 0x07, 0x80, 0x8b, 0x29, 0xe2, 0x96,
+	0x6a, 0x8a, 0x5e, 0xb7, 0x84, 0x5b, 0x1c, 0x2c, 0xd5, 0x44, 0xbb, 0x20, 0x87, 0x94, 0x11, 0x97,
+	0x30, 0x22, 0x86, 0x52, 0x0b, 0x4f, 0xd6, 0x97, 0x35, 0x79, 0x59, 0xad, 0x5f, 0xd6, 0x64, 0x59,
+	0x55, 0x2e, 0x6b, 0x72, 0x43, 0x95, 0x8f, 0x5e, 0x83, 0x32, 0x99, 0x6f, 0x68, 0x0b, 0xd0, 0xfb,
+	0xde, 0x55, 0xcf, 0xfc, 0xd8, 0x73, 0x6c, 0x6c, 0x18, 0x8e, 0x65, 0xeb, 0xb6, 0xa1, 0x2e, 0x20,
+	0x80, 0xba, 0xde, 0xb1, 0x2f, 0x3e, 0x18, 0xaa, 0xc4, 0xbf, 0xcf, 0xb1, 0xf9, 0xd9, 0xe8, 0xa9,
+	0x8b, 0x47, 0xff, 0xcf, 0x75, 0x12, 0x53, 0xb4, 0x09, 0x8d, 0xc2, 0x57, 0x5d, 0x40, 0x0d, 0x58,
+	0x7a, 0x67, 0xbe, 0x55, 0x25, 0xfe, 0x71, 0xad, 0xdf, 0xa8, 0x8b, 0x47, 0xbf, 0x48, 0xd0, 0x9a,
+	0x1e, 0x88, 0x68, 0x07, 0x36, 0xcb, 0x5c, 0x5d, 0xdd, 0xea, 0x3a, 0x96, 0x8d, 0x75, 0xdb, 0x78,
+	0xfb, 0x49, 0x5d, 0x40, 0x2d, 0x90, 0xf1, 0x79, 0xc7, 0x79, 0xf1, 0xea, 0xc5, 0xa9, 0x2a, 0xa1,
+	0x75, 0x58, 0xb5, 0x0d, 0xcb, 0x76, 0xae, 0xf5, 0x1b, 0xc1, 0x34, 0xb0, 0xba, 0xc8, 0xbd, 0xcd,
+	0xb3, 0x4b, 0xa3, 0x63, 0x3b, 0xf8, 0xbc, 0xc3, 0x89, 0x8e, 0xd5, 0xd5, 0x4f, 0x9f, 0xbf, 0x50,
+	0x97, 0xd0, 0x26, 0xac, 0x75, 0xcc, 0xde, 0xc5, 0x95, 0xc5, 0xa1, 0xe7, 0x4f, 0x4f, 0x1d, 0x0e,
+	0xd7, 0xd0, 0x1a, 0xb4, 0x1f, 0x60, 0x0e, 0x2d, 0x1f, 0xfd, 0x0f, 0xda, 0x33, 0x43, 0x16, 0xc9,
+	0x50, 0xeb, 0x99, 0xbd, 0xe2, 0xc4, 0x05, 0xad, 0x76, 0xf4, 0x12, 0xd0, 0xfc, 0x14, 0x45, 0x6d,
+	0x50, 0xf4, 0x9e, 0xd9, 0xfb, 0x74, 0x6d, 0xbe, 0xb7, 0xf2, 0x13, 0x63, 0x4b, 0x57, 0x25, 0xa4,
+	0xc0, 0xb2, 0xd1, 0x79, 0x63, 0xe9, 0xea, 0xd2, 0x91, 0x0b, 0xed, 0x99, 0xc2, 0x43, 0x7b, 0xb0,
+	0xfd, 0xce, 0x7c, 0xeb, 0x60, 0xd3, 0xb4, 0x9d, 0x73, 0x13, 0x5f, 0xeb, 0xb6, 0xf3, 0xa0, 0xd9,
+	0x16, 0xa0, 0xaa, 0xf1, 0xc3, 0x53, 0x55, 0x42, 0x8f, 0x61, 0xb7, 0x8a, 0x77, 0xba, 0x46, 0xe7,
+	0xea, 0xc6, 0xbc, 0xe8, 0xd9, 0xea, 0xe2, 0xd9, 0xeb, 0xcf, 0xaf, 0x3c, 0x9f, 0x0d, 0xb3, 0xfe,
+	0xf1, 0x20, 0x0e, 0x4f, 0x8a, 0x5f, 0x42, 0x96, 0xf0, 0x79, 0x42, 0xa2, 0x93, 0xa2, 0xc7, 0x9d,
+	0x0c, 0x82, 0x38, 0x73, 0x8b, 0x07, 0x71, 0x32, 0x79, 0x18, 0xfd, 0xba, 0x78, 0x60, 0xcf, 0xfe,
+	0x18, 0x00, 0x6d, 0xe1, 0x5d, 0x0d, 0x80, 0x0a, 0x00, 0x00,
 }
@@ -52,6 +52,8 @@ type ReadOnlyLogTreeTX interface {
 	// LeafIndex. It will be shorter than `count` if the requested range has
 	// missing entries (e.g., it extends beyond the size of a LOG tree), or
 	// `count` is too big to handle in one go.
+	// Leaves whose data has been pruned are still returned, with the
+	// DataPruned flag set and no LeafValue or ExtraData.
 	// For PREORDERED_LOG trees, *must* return leaves beyond the tree size if
 	// they are stored, in order to allow integrating them into the tree.
 	GetLeavesByRange(ctx context.Context, start, count int64) ([]*trillian.LogLeaf, error)
@@ -64,6 +66,15 @@ type ReadOnlyLogTreeTX interface {
 	LatestSignedLogRoot(ctx context.Context) (*trillian.SignedLogRoot, error)
 }
 
+// LogRootHistoryReader is an optional interface of ReadOnlyLogTreeTX, for
+// storage implementations which keep all the SignedLogRoots of a tree rather
+// than only the latest one.
+type LogRootHistoryReader interface {
+	// GetSignedLogRoots returns at most `count` SignedLogRoots with timestamps
+	// at or after startNanos, ordered by timestamp.
+	GetSignedLogRoots(ctx context.Context, startNanos int64, count int) ([]*trillian.SignedLogRoot, error)
+}
+
 // LogTreeTX is the transactional interface for reading/updating a Log.
 // It extends the basic TreeTX interface with Log specific methods.
 // After a call to Commit or Rollback implementations must be in a clean state and have
@@ -119,6 +130,15 @@ type LogTreeTX interface {
 	// UpdateSequencedLeaves associates the leaves with the sequence numbers
 	// assigned to them.
 	UpdateSequencedLeaves(ctx context.Context, leaves []*trillian.LogLeaf) error
+
+	// PruneLeaves drops the LeafValue and ExtraData of at most `limit` leaves
+	// with indices below `end`, starting from the oldest leaf which has not
+	// been pruned yet, and returns the number of leaves pruned. The Merkle
+	// leaf hashes and the tree nodes are kept, so proofs can still be served.
+	// Leaves with indices at or beyond the tree size are never pruned.
+	//
+	// Subsequent reads return pruned leaves with the DataPruned flag set.
+	PruneLeaves(ctx context.Context, end int64, limit int) (int, error)
 }
 
 // ReadOnlyLogStorage represents a narrowed read-only view into a LogStorage.
@@ -18,6 +18,8 @@ import (
 	"context"
 
 	"github.com/google/trillian"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // ReadOnlyMapTX provides a read-only view into log data.
@@ -40,7 +42,8 @@ type ReadOnlyMapTreeTX interface {
 	ReadOnlyTreeTX
 
 	// GetSignedMapRoot returns the SignedMapRoot associated with the
-	// specified revision.
+	// specified revision. If the revision has been garbage collected, the
+	// returned error has codes.OutOfRange (see RevisionCollectedError).
 	GetSignedMapRoot(ctx context.Context, revision int64) (*trillian.SignedMapRoot, error)
 	// LatestSignedMapRoot returns the most recently created SignedMapRoot.
 	LatestSignedMapRoot(ctx context.Context) (*trillian.SignedMapRoot, error)
@@ -52,6 +55,19 @@ type ReadOnlyMapTreeTX interface {
 	// exist.  i.e. requesting a set of unknown keys would result in a
 	// zero-length array being returned.
 	Get(ctx context.Context, revision int64, keyHashes [][]byte) ([]*trillian.MapLeaf, error)
+	// ListLeaves retrieves up to limit leaves, in ascending order of key hash,
+	// whose key hash is in the range [start, end) at the specified revision.
+	// A nil end means that the range is unbounded above. Like Get, it returns
+	// the leaves whose latest value at the revision is empty.
+	ListLeaves(ctx context.Context, revision int64, start, end []byte, limit int) ([]*trillian.MapLeaf, error)
+	// ListChangedKeys retrieves up to limit key hashes >= start, in ascending
+	// order, of the leaves which were set at any revision in the range
+	// (fromRevision, toRevision].
+	ListChangedKeys(ctx context.Context, fromRevision, toRevision int64, start []byte, limit int) ([][]byte, error)
+	// ListLeafRevisions retrieves up to limit revisions in the range
+	// (fromRevision, toRevision], in ascending order, at which the leaf with
+	// the given key hash was set.
+	ListLeafRevisions(ctx context.Context, keyHash []byte, fromRevision, toRevision int64, limit int) ([]int64, error)
 }
 
 // MapTreeTX is the transactional interface for reading/modifying a Map.
@@ -67,6 +83,20 @@ type MapTreeTX interface {
 	StoreSignedMapRoot(ctx context.Context, root *trillian.SignedMapRoot) error
 	// Set sets key to leaf
 	Set(ctx context.Context, keyHash []byte, value *trillian.MapLeaf) error
+	// CollectRevisions garbage collects the revisions of the map below end,
+	// deleting at most limit records: the SignedMapRoots of those revisions,
+	// and the versions of leaves and subtrees which are superseded by a later
+	// version at or below end. Revisions at or above end read the same after
+	// collection. It returns the number of records deleted, which is less than
+	// limit only if nothing is left to collect below end.
+	CollectRevisions(ctx context.Context, end int64, limit int) (int, error)
+}
+
+// RevisionCollectedError returns the error with which reads of a map revision
+// fail once it has been garbage collected, given the earliest revision of the
+// map which is still available.
+func RevisionCollectedError(revision, earliest int64) error {
+	return status.Errorf(codes.OutOfRange, "map revision %d has been garbage collected, earliest available revision is %d", revision, earliest)
 }
 
 // ReadOnlyMapStorage provides a narrow read-only view into a MapStorage.
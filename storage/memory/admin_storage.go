@@ -203,6 +203,10 @@ func (t *adminTX) HardDeleteTree(ctx context.Context, treeID int64) error {
 	return fmt.Errorf("method not supported: HardDeleteTree")
 }
 
+func (t *adminTX) PurgeTreeData(ctx context.Context, treeID int64, limit int) (int, error) {
+	return 0, fmt.Errorf("method not supported: PurgeTreeData")
+}
+
 func (t *adminTX) UndeleteTree(ctx context.Context, treeID int64) (*trillian.Tree, error) {
 	return nil, fmt.Errorf("method not supported: UndeleteTree")
 }
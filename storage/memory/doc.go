@@ -12,8 +12,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package memory provides a simple in-process implementation of the tree- and
-// log-storage interfaces.
+// Package memory provides a simple in-process implementation of the tree-,
+// log- and map-storage interfaces.
 //
 // This implementation is intended SOLELY for use in integration tests which
 // exercise properties of the higher levels of Trillian componened - e.g.
@@ -25,7 +25,7 @@
 // scan ranges of keys in order.
 //
 // The implementation does provide transaction-like semantics for the
-// LogStorage interface, although conflict is avoided by each writable
+// LogStorage and MapStorage interfaces, although conflict is avoided by each writable
 // transaction exclusively locking the tree until it's committed or
 // rolled-back.
 //
@@ -73,6 +73,13 @@ func hashToSeqKey(treeID int64) btree.Item {
 	return &kv{k: fmt.Sprintf("/%d/h2s", treeID)}
 }
 
+// prunedKey formats a key for use in a tree's BTree store.
+// The associated Item value will be the number of leaves which have been
+// pruned, i.e. the index of the oldest leaf which still has its data.
+func prunedKey(treeID int64) btree.Item {
+	return &kv{k: fmt.Sprintf("/%d/pruned", treeID)}
+}
+
 // sthKey formats a key for use in a tree's BTree store.
 // The associated Item value will be the STH with the given timestamp.
 func sthKey(treeID int64, timestamp uint64) btree.Item {
@@ -362,6 +369,18 @@ func (t *logTreeTX) fetchLatestRoot(ctx context.Context) (*trillian.SignedLogRoo
 	return r.(*kv).v.(*trillian.SignedLogRoot), nil
 }
 
+func (t *logTreeTX) GetSignedLogRoots(ctx context.Context, startNanos int64, count int) ([]*trillian.SignedLogRoot, error) {
+	if startNanos < 0 || count <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid range startNanos=%d, count=%d", startNanos, count)
+	}
+	var ret []*trillian.SignedLogRoot
+	t.tx.AscendRange(sthKey(t.treeID, uint64(startNanos)), sthKey(t.treeID, math.MaxUint64), func(i btree.Item) bool {
+		ret = append(ret, i.(*kv).v.(*trillian.SignedLogRoot))
+		return len(ret) < count
+	})
+	return ret, nil
+}
+
 func (t *logTreeTX) StoreSignedLogRoot(ctx context.Context, slr *trillian.SignedLogRoot) error {
 	var root types.LogRootV1
 	if err := root.UnmarshalBinary(slr.LogRoot); err != nil {
@@ -422,6 +441,43 @@ func (t *logTreeTX) UpdateSequencedLeaves(ctx context.Context, leaves []*trillia
 	return nil
 }
 
+func (t *logTreeTX) PruneLeaves(ctx context.Context, end int64, limit int) (int, error) {
+	if treeSize := int64(t.root.TreeSize); end > treeSize {
+		end = treeSize
+	}
+	k := t.tx.Get(prunedKey(t.treeID)).(*kv)
+	start := k.v.(int64)
+	if last := start + int64(limit); end > last {
+		end = last
+	}
+	if end <= start {
+		return 0, nil
+	}
+
+	for seq := start; seq < end; seq++ {
+		item := t.tx.Get(seqLeafKey(t.treeID, seq))
+		if item == nil {
+			return 0, fmt.Errorf("missing leaf at index %d", seq)
+		}
+		// Leaves may be referenced by callers, so store a pruned copy.
+		leaf := item.(*kv).v.(*trillian.LogLeaf)
+		pruned := seqLeafKey(t.treeID, seq)
+		pruned.(*kv).v = &trillian.LogLeaf{
+			MerkleLeafHash:     leaf.MerkleLeafHash,
+			LeafIndex:          leaf.LeafIndex,
+			LeafIdentityHash:   leaf.LeafIdentityHash,
+			QueueTimestamp:     leaf.QueueTimestamp,
+			IntegrateTimestamp: leaf.IntegrateTimestamp,
+			DataPruned:         true,
+		}
+		t.tx.ReplaceOrInsert(pruned)
+	}
+	pk := prunedKey(t.treeID)
+	pk.(*kv).v = end
+	t.tx.ReplaceOrInsert(pk)
+	return int(end - start), nil
+}
+
 func (t *logTreeTX) GetActiveLogIDs(ctx context.Context) ([]int64, error) {
 	return getActiveLogIDs(t.ts.trees), nil
 }
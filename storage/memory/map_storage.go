@@ -0,0 +1,480 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/btree"
+	"github.com/google/trillian"
+	"github.com/google/trillian/merkle/hashers"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/cache"
+	"github.com/google/trillian/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var defaultMapStrata = []int{8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 176}
+
+// mapLeafPrefix formats the key prefix shared by all revisions of the map
+// leaf with the given key hash.
+func mapLeafPrefix(treeID int64, keyHash []byte) string {
+	return fmt.Sprintf("/%d/mapleaf/%x/", treeID, keyHash)
+}
+
+// mapLeafKey formats a key for use in a tree's BTree store.
+// The associated Item value will be the MapLeaf with the given key hash, as
+// written at the given revision.
+func mapLeafKey(treeID int64, keyHash []byte, rev int64) btree.Item {
+	return &kv{k: fmt.Sprintf("%s%020d", mapLeafPrefix(treeID, keyHash), rev)}
+}
+
+// mapRootPrefix formats the key prefix shared by all SignedMapRoots of a
+// tree.
+func mapRootPrefix(treeID int64) string {
+	return fmt.Sprintf("/%d/smr/", treeID)
+}
+
+// mapRootKey formats a key for use in a tree's BTree store.
+// The associated Item value will be the SignedMapRoot with the given revision.
+func mapRootKey(treeID, rev int64) btree.Item {
+	return &kv{k: fmt.Sprintf("%s%020d", mapRootPrefix(treeID), rev)}
+}
+
+type memoryMapStorage struct {
+	*TreeStorage
+}
+
+// NewMapStorage creates an in-memory MapStorage instance.
+func NewMapStorage(ts *TreeStorage) storage.MapStorage {
+	return &memoryMapStorage{TreeStorage: ts}
+}
+
+func (m *memoryMapStorage) CheckDatabaseAccessible(ctx context.Context) error {
+	return nil
+}
+
+func (m *memoryMapStorage) begin(ctx context.Context, tree *trillian.Tree, readonly bool) (storage.MapTreeTX, error) {
+	hasher, err := hashers.NewMapHasher(tree.HashStrategy)
+	if err != nil {
+		return nil, err
+	}
+
+	stCache := cache.NewMapSubtreeCache(defaultMapStrata, tree.TreeId, hasher)
+	ttx, err := m.TreeStorage.beginTreeTX(ctx, tree.TreeId, hasher.Size(), stCache, readonly)
+	if readonly && status.Code(err) == codes.NotFound {
+		// As with SQL-based storage, snapshots of unknown maps see an empty
+		// map, which has no roots, not even at revision 0.
+		ttx, err = m.TreeStorage.beginTX(newTree(&trillian.Tree{TreeId: tree.TreeId}), tree.TreeId, hasher.Size(), stCache, readonly), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	mtx := &mapTreeTX{
+		treeTX:       ttx,
+		ms:           m,
+		readRevision: -1,
+	}
+
+	if readonly {
+		// readRevision will be set later, by the first
+		// GetSignedMapRoot/LatestSignedMapRoot operation.
+		return mtx, nil
+	}
+
+	// A read-write transaction needs to know the current revision
+	// so it can write at revision+1.
+	root, err := mtx.LatestSignedMapRoot(ctx)
+	if err == storage.ErrTreeNeedsInit {
+		return mtx, err
+	} else if err != nil {
+		ttx.Rollback()
+		return nil, err
+	}
+
+	var mr types.MapRootV1
+	if err := mr.UnmarshalBinary(root.MapRoot); err != nil {
+		ttx.Rollback()
+		return nil, err
+	}
+
+	mtx.readRevision = int64(mr.Revision)
+	mtx.treeTX.writeRevision = int64(mr.Revision) + 1
+	return mtx, nil
+}
+
+func (m *memoryMapStorage) SnapshotForTree(ctx context.Context, tree *trillian.Tree) (storage.ReadOnlyMapTreeTX, error) {
+	return m.begin(ctx, tree, true /* readonly */)
+}
+
+func (m *memoryMapStorage) ReadWriteTransaction(ctx context.Context, tree *trillian.Tree, f storage.MapTXFunc) error {
+	tx, err := m.begin(ctx, tree, false /* readonly */)
+	if tx != nil {
+		defer tx.Close()
+	}
+	if err != nil && err != storage.ErrTreeNeedsInit {
+		return err
+	}
+	if err := f(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+type mapTreeTX struct {
+	treeTX
+	ms           *memoryMapStorage
+	readRevision int64
+}
+
+func (m *mapTreeTX) ReadRevision(ctx context.Context) (int64, error) {
+	return m.readRevision, nil
+}
+
+func (m *mapTreeTX) WriteRevision(ctx context.Context) (int64, error) {
+	if m.treeTX.writeRevision < 0 {
+		return m.treeTX.writeRevision, errors.New("mapTreeTX write revision not populated")
+	}
+	return m.treeTX.writeRevision, nil
+}
+
+func (m *mapTreeTX) Set(ctx context.Context, keyHash []byte, value *trillian.MapLeaf) error {
+	k := mapLeafKey(m.treeID, keyHash, m.writeRevision)
+	if m.tx.Has(k) {
+		return fmt.Errorf("map leaf %x already exists at revision %d", keyHash, m.writeRevision)
+	}
+	// Store a copy of the proto to protect against the caller modifying it.
+	k.(*kv).v = proto.Clone(value).(*trillian.MapLeaf)
+	m.tx.ReplaceOrInsert(k)
+	return nil
+}
+
+// Get returns a list of map leaves indicated by indexes.
+// If an index is not found, no corresponding entry is returned.
+// Each MapLeaf.Index is overwritten with the index the leaf was found at.
+func (m *mapTreeTX) Get(ctx context.Context, revision int64, indexes [][]byte) ([]*trillian.MapLeaf, error) {
+	ret := make([]*trillian.MapLeaf, 0, len(indexes))
+	for _, index := range indexes {
+		// Look for the most recent value at or below revision:
+		var leaf *trillian.MapLeaf
+		m.tx.DescendRange(mapLeafKey(m.treeID, index, revision), &kv{k: mapLeafPrefix(m.treeID, index)}, func(i btree.Item) bool {
+			leaf = proto.Clone(i.(*kv).v.(*trillian.MapLeaf)).(*trillian.MapLeaf)
+			return false
+		})
+		if leaf == nil {
+			continue
+		}
+		leaf.Index = index
+		ret = append(ret, leaf)
+	}
+	return ret, nil
+}
+
+// ListLeaves returns up to limit map leaves, ordered by index, whose index is
+// in the range [start, end), or [start, ∞) if end is nil.
+func (m *mapTreeTX) ListLeaves(ctx context.Context, revision int64, start, end []byte, limit int) ([]*trillian.MapLeaf, error) {
+	ret := make([]*trillian.MapLeaf, 0)
+	if limit <= 0 {
+		return ret, nil
+	}
+	// Hex-encoded key hashes, followed by "/", sort in the same order as the
+	// key hashes themselves, and all come before the "0" which follows the
+	// "/mapleaf" part of the prefix.
+	leafPrefix := fmt.Sprintf("/%d/mapleaf/", m.treeID)
+	last := &kv{k: fmt.Sprintf("/%d/mapleaf0", m.treeID)}
+	if end != nil {
+		last = &kv{k: mapLeafPrefix(m.treeID, end)}
+	}
+
+	var index string
+	var leaf *trillian.MapLeaf
+	flush := func() {
+		if leaf != nil {
+			ret = append(ret, leaf)
+			leaf = nil
+		}
+	}
+	var err error
+	m.tx.AscendRange(&kv{k: mapLeafPrefix(m.treeID, start)}, last, func(i btree.Item) bool {
+		k := i.(*kv).k[len(leafPrefix):]
+		sep := strings.IndexByte(k, '/')
+		var rev int64
+		if rev, err = strconv.ParseInt(k[sep+1:], 10, 64); err != nil {
+			return false
+		}
+		if k[:sep] != index {
+			flush()
+			if len(ret) == limit {
+				return false
+			}
+			index = k[:sep]
+		}
+		if rev > revision {
+			return true
+		}
+		leaf = proto.Clone(i.(*kv).v.(*trillian.MapLeaf)).(*trillian.MapLeaf)
+		if leaf.Index, err = hex.DecodeString(index); err != nil {
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(ret) < limit {
+		flush()
+	}
+	return ret, nil
+}
+
+// ListChangedKeys returns up to limit key hashes >= start, ordered, of the map
+// leaves which were set in the revisions (fromRevision, toRevision].
+func (m *mapTreeTX) ListChangedKeys(ctx context.Context, fromRevision, toRevision int64, start []byte, limit int) ([][]byte, error) {
+	ret := make([][]byte, 0)
+	if limit <= 0 {
+		return ret, nil
+	}
+	leafPrefix := fmt.Sprintf("/%d/mapleaf/", m.treeID)
+	last := &kv{k: fmt.Sprintf("/%d/mapleaf0", m.treeID)}
+
+	var index string
+	var err error
+	m.tx.AscendRange(&kv{k: mapLeafPrefix(m.treeID, start)}, last, func(i btree.Item) bool {
+		k := i.(*kv).k[len(leafPrefix):]
+		sep := strings.IndexByte(k, '/')
+		var rev int64
+		if rev, err = strconv.ParseInt(k[sep+1:], 10, 64); err != nil {
+			return false
+		}
+		if k[:sep] == index || rev <= fromRevision || rev > toRevision {
+			return true
+		}
+		index = k[:sep]
+		var keyHash []byte
+		if keyHash, err = hex.DecodeString(index); err != nil {
+			return false
+		}
+		ret = append(ret, keyHash)
+		return len(ret) < limit
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// ListLeafRevisions returns up to limit revisions, ordered, in the range
+// (fromRevision, toRevision] at which the map leaf with the given key hash was
+// set.
+func (m *mapTreeTX) ListLeafRevisions(ctx context.Context, keyHash []byte, fromRevision, toRevision int64, limit int) ([]int64, error) {
+	ret := make([]int64, 0)
+	if limit <= 0 || fromRevision >= toRevision {
+		return ret, nil
+	}
+	prefix := mapLeafPrefix(m.treeID, keyHash)
+
+	var err error
+	m.tx.AscendRange(mapLeafKey(m.treeID, keyHash, fromRevision+1), mapLeafKey(m.treeID, keyHash, toRevision+1), func(i btree.Item) bool {
+		var rev int64
+		if rev, err = strconv.ParseInt(i.(*kv).k[len(prefix):], 10, 64); err != nil {
+			return false
+		}
+		ret = append(ret, rev)
+		return len(ret) < limit
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (m *mapTreeTX) GetSignedMapRoot(ctx context.Context, revision int64) (*trillian.SignedMapRoot, error) {
+	r := m.tx.Get(mapRootKey(m.treeID, revision))
+	if r == nil {
+		if earliest, ok := m.earliestRevision(); ok && revision < earliest {
+			return nil, storage.RevisionCollectedError(revision, earliest)
+		}
+		if revision == 0 {
+			return nil, storage.ErrTreeNeedsInit
+		}
+		return nil, fmt.Errorf("no SignedMapRoot for revision %d", revision)
+	}
+	m.readRevision = revision
+	return proto.Clone(r.(*kv).v.(*trillian.SignedMapRoot)).(*trillian.SignedMapRoot), nil
+}
+
+// earliestRevision returns the revision of the earliest SignedMapRoot of the
+// tree, if any.
+func (m *mapTreeTX) earliestRevision() (int64, bool) {
+	var earliest int64
+	var ok bool
+	m.tx.AscendRange(mapRootKey(m.treeID, 0), mapRootKey(m.treeID, math.MaxInt64), func(i btree.Item) bool {
+		var r types.MapRootV1
+		if err := r.UnmarshalBinary(i.(*kv).v.(*trillian.SignedMapRoot).MapRoot); err == nil {
+			earliest, ok = int64(r.Revision), true
+		}
+		return false
+	})
+	return earliest, ok
+}
+
+func (m *mapTreeTX) LatestSignedMapRoot(ctx context.Context) (*trillian.SignedMapRoot, error) {
+	var root *trillian.SignedMapRoot
+	m.tx.DescendRange(mapRootKey(m.treeID, math.MaxInt64), &kv{k: mapRootPrefix(m.treeID)}, func(i btree.Item) bool {
+		root = i.(*kv).v.(*trillian.SignedMapRoot)
+		return false
+	})
+	// It's possible there are no roots for this tree yet
+	if root == nil {
+		return nil, storage.ErrTreeNeedsInit
+	}
+
+	var mr types.MapRootV1
+	if err := mr.UnmarshalBinary(root.MapRoot); err != nil {
+		return nil, err
+	}
+	m.readRevision = int64(mr.Revision)
+	return proto.Clone(root).(*trillian.SignedMapRoot), nil
+}
+
+func (m *mapTreeTX) StoreSignedMapRoot(ctx context.Context, root *trillian.SignedMapRoot) error {
+	var r types.MapRootV1
+	if err := r.UnmarshalBinary(root.MapRoot); err != nil {
+		return err
+	}
+
+	k := mapRootKey(m.treeID, int64(r.Revision))
+	if m.tx.Has(k) {
+		return fmt.Errorf("SignedMapRoot for revision %d already exists", r.Revision)
+	}
+	k.(*kv).v = proto.Clone(root).(*trillian.SignedMapRoot)
+	m.tx.ReplaceOrInsert(k)
+	return nil
+}
+
+// CollectRevisions deletes at most limit records of the map revisions below
+// end, starting with their roots, and returns the number of records deleted.
+func (m *mapTreeTX) CollectRevisions(ctx context.Context, end int64, limit int) (int, error) {
+	if limit <= 0 {
+		return 0, nil
+	}
+	var stale []btree.Item
+	m.tx.AscendRange(mapRootKey(m.treeID, 0), mapRootKey(m.treeID, end), func(i btree.Item) bool {
+		stale = append(stale, i)
+		return len(stale) < limit
+	})
+	if len(stale) < limit {
+		var err error
+		if stale, err = m.supersededLeaves(end, limit, stale); err != nil {
+			return 0, err
+		}
+	}
+	if len(stale) < limit {
+		var err error
+		if stale, err = m.supersededSubtrees(end, limit, stale); err != nil {
+			return 0, err
+		}
+	}
+	for _, i := range stale {
+		m.tx.Delete(i)
+	}
+	return len(stale), nil
+}
+
+// supersededLeaves appends to stale the versions of map leaves which are
+// superseded by a later version at or below revision end, up to limit items.
+func (m *mapTreeTX) supersededLeaves(end int64, limit int, stale []btree.Item) ([]btree.Item, error) {
+	leafPrefix := fmt.Sprintf("/%d/mapleaf/", m.treeID)
+	last := &kv{k: fmt.Sprintf("/%d/mapleaf0", m.treeID)}
+
+	// Versions of a leaf are ordered by revision, so each version at or below
+	// end supersedes the previous one.
+	var prev btree.Item
+	var prevIndex string
+	var err error
+	m.tx.AscendRange(&kv{k: leafPrefix}, last, func(i btree.Item) bool {
+		k := i.(*kv).k[len(leafPrefix):]
+		sep := strings.IndexByte(k, '/')
+		var rev int64
+		if rev, err = strconv.ParseInt(k[sep+1:], 10, 64); err != nil {
+			return false
+		}
+		if prev != nil && k[:sep] == prevIndex && rev <= end {
+			stale = append(stale, prev)
+		}
+		prev, prevIndex = i, k[:sep]
+		return len(stale) < limit
+	})
+	return stale, err
+}
+
+// supersededSubtrees appends to stale the versions of subtrees which are
+// superseded by a later version at or below revision end, up to limit items.
+func (m *mapTreeTX) supersededSubtrees(end int64, limit int, stale []btree.Item) ([]btree.Item, error) {
+	type version struct {
+		item btree.Item
+		rev  int64
+	}
+	subtreePrefix := fmt.Sprintf("/%d/subtree/", m.treeID)
+	last := &kv{k: fmt.Sprintf("/%d/subtree0", m.treeID)}
+
+	// Subtree keys don't order revisions numerically, so group them first.
+	versions := make(map[string][]version)
+	var ids []string
+	var err error
+	m.tx.AscendRange(&kv{k: subtreePrefix}, last, func(i btree.Item) bool {
+		k := i.(*kv).k
+		sep := strings.LastIndexByte(k, '/')
+		var rev int64
+		if rev, err = strconv.ParseInt(k[sep+1:], 10, 64); err != nil {
+			return false
+		}
+		if _, ok := versions[k[:sep]]; !ok {
+			ids = append(ids, k[:sep])
+		}
+		versions[k[:sep]] = append(versions[k[:sep]], version{item: i, rev: rev})
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		kept := int64(-1)
+		for _, v := range versions[id] {
+			if v.rev <= end && v.rev > kept {
+				kept = v.rev
+			}
+		}
+		for _, v := range versions[id] {
+			if v.rev < kept {
+				if len(stale) >= limit {
+					return stale, nil
+				}
+				stale = append(stale, v.item)
+			}
+		}
+	}
+	return stale, nil
+}
@@ -0,0 +1,214 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"crypto"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+	"github.com/google/trillian/integration/storagetest"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/testonly"
+	"github.com/google/trillian/types"
+
+	tcrypto "github.com/google/trillian/crypto"
+	storageto "github.com/google/trillian/storage/testonly"
+)
+
+var (
+	keyHash     = []byte("A Key Hash")
+	fixedSigner = tcrypto.NewSigner(0, testonly.NewSignerWithFixedSig(nil, []byte("notempty")), crypto.SHA256)
+)
+
+func TestMapIntegration(t *testing.T) {
+	storageFactory := func(_ context.Context, t *testing.T) (storage.MapStorage, storage.AdminStorage) {
+		ts := NewTreeStorage()
+		return NewMapStorage(ts), NewAdminStorage(ts)
+	}
+
+	storagetest.RunMapStorageTests(t, storageFactory)
+}
+
+func TestMapRootUpdate(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTreeStorage()
+	s := NewMapStorage(ts)
+	tree := createInitializedMapForTests(ctx, t, ts)
+
+	for _, rev := range []uint64{1, 2} {
+		root := mustSignMapRoot(t, &types.MapRootV1{
+			TimestampNanos: 98765 + rev,
+			Revision:       rev,
+			RootHash:       []byte(dummyRootHash),
+		})
+		runMapTX(ctx, s, tree, t, func(ctx context.Context, tx storage.MapTreeTX) error {
+			if got, err := tx.WriteRevision(ctx); err != nil || got != int64(rev) {
+				t.Fatalf("WriteRevision() = %d, %v; want %d, nil", got, err, rev)
+			}
+			return tx.StoreSignedMapRoot(ctx, root)
+		})
+
+		tx, err := s.SnapshotForTree(ctx, tree)
+		if err != nil {
+			t.Fatalf("SnapshotForTree(): %v", err)
+		}
+		got, err := tx.LatestSignedMapRoot(ctx)
+		if err != nil {
+			t.Fatalf("LatestSignedMapRoot(): %v", err)
+		}
+		if !proto.Equal(got, root) {
+			t.Errorf("LatestSignedMapRoot() = %v, want %v", got, root)
+		}
+		if got, _ := tx.ReadRevision(ctx); got != int64(rev) {
+			t.Errorf("ReadRevision() = %d, want %d", got, rev)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			t.Fatalf("Commit(): %v", err)
+		}
+	}
+}
+
+func TestMapSetGetMultipleRevisions(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTreeStorage()
+	s := NewMapStorage(ts)
+	tree := createInitializedMapForTests(ctx, t, ts)
+
+	leaves := []*trillian.MapLeaf{
+		{Index: keyHash, LeafHash: []byte{0}, LeafValue: []byte{0}},
+		{Index: keyHash, LeafHash: []byte{1}, LeafValue: []byte{1}},
+		{Index: keyHash, LeafHash: []byte{2}, LeafValue: []byte{2}},
+	}
+	// Write a leaf at each even revision, so odd revisions must fall back to
+	// the previous value.
+	for i, leaf := range leaves {
+		runMapTX(ctx, s, tree, t, func(ctx context.Context, tx storage.MapTreeTX) error {
+			tx.(*mapTreeTX).treeTX.writeRevision = int64(2 * i)
+			return tx.Set(ctx, keyHash, leaf)
+		})
+	}
+
+	for rev := int64(0); rev <= 2*int64(len(leaves)); rev++ {
+		want := leaves[len(leaves)-1]
+		if int(rev/2) < len(leaves) {
+			want = leaves[rev/2]
+		}
+		runMapTX(ctx, s, tree, t, func(ctx context.Context, tx storage.MapTreeTX) error {
+			got, err := tx.Get(ctx, rev, [][]byte{keyHash, []byte("This doesn't exist.")})
+			if err != nil {
+				t.Fatalf("Get(%d): %v", rev, err)
+			}
+			if len(got) != 1 || !proto.Equal(got[0], want) {
+				t.Errorf("Get(%d) = %v, want [%v]", rev, got, want)
+			}
+			return nil
+		})
+	}
+}
+
+func TestMapSetSameKeyInSameRevisionFails(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTreeStorage()
+	s := NewMapStorage(ts)
+	tree := createInitializedMapForTests(ctx, t, ts)
+
+	leaf := &trillian.MapLeaf{Index: keyHash, LeafValue: []byte("value")}
+	err := s.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+		if err := tx.Set(ctx, keyHash, leaf); err != nil {
+			t.Fatalf("Failed to set %v to %v: %v", keyHash, leaf, err)
+		}
+		return tx.Set(ctx, keyHash, leaf)
+	})
+	if err == nil {
+		t.Fatalf("Unexpectedly succeeded in setting %v to %v twice", keyHash, leaf)
+	}
+}
+
+func TestGetSignedMapRootNotExist(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTreeStorage()
+	s := NewMapStorage(ts)
+	tree, err := storage.CreateTree(ctx, NewAdminStorage(ts), storageto.MapTree)
+	if err != nil {
+		t.Fatalf("CreateTree(): %v", err)
+	}
+
+	runMapTX(ctx, s, tree, t, func(ctx context.Context, tx storage.MapTreeTX) error {
+		if _, err := tx.GetSignedMapRoot(ctx, 0); err != storage.ErrTreeNeedsInit {
+			t.Fatalf("GetSignedMapRoot() = %v, want %v", err, storage.ErrTreeNeedsInit)
+		}
+		return nil
+	})
+}
+
+func TestDuplicateSignedMapRoot(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTreeStorage()
+	s := NewMapStorage(ts)
+	tree := createInitializedMapForTests(ctx, t, ts)
+
+	err := s.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+		root := mustSignMapRoot(t, &types.MapRootV1{
+			TimestampNanos: 98765,
+			Revision:       5,
+			RootHash:       []byte(dummyRootHash),
+		})
+		if err := tx.StoreSignedMapRoot(ctx, root); err != nil {
+			t.Fatalf("Failed to store signed map root: %v", err)
+		}
+		// Shouldn't be able to do it again
+		return tx.StoreSignedMapRoot(ctx, root)
+	})
+	if err == nil {
+		t.Fatal("Allowed duplicate signed map root")
+	}
+}
+
+const dummyRootHash = "01234567890123456789012345678901"
+
+func mustSignMapRoot(t *testing.T, root *types.MapRootV1) *trillian.SignedMapRoot {
+	t.Helper()
+	r, err := fixedSigner.SignMapRoot(root)
+	if err != nil {
+		t.Fatalf("SignMapRoot(): %v", err)
+	}
+	return r
+}
+
+func runMapTX(ctx context.Context, s storage.MapStorage, tree *trillian.Tree, t *testing.T, f storage.MapTXFunc) {
+	t.Helper()
+	if err := s.ReadWriteTransaction(ctx, tree, f); err != nil {
+		t.Fatalf("Failed to run map tx: %v", err)
+	}
+}
+
+func createInitializedMapForTests(ctx context.Context, t *testing.T, ts *TreeStorage) *trillian.Tree {
+	t.Helper()
+	tree, err := storage.CreateTree(ctx, NewAdminStorage(ts), storageto.MapTree)
+	if err != nil {
+		t.Fatalf("CreateTree(): %v", err)
+	}
+
+	runMapTX(ctx, NewMapStorage(ts), tree, t, func(ctx context.Context, tx storage.MapTreeTX) error {
+		return tx.StoreSignedMapRoot(ctx, mustSignMapRoot(t, &types.MapRootV1{
+			RootHash: []byte("rootHash"),
+			Revision: 0,
+		}))
+	})
+	return tree
+}
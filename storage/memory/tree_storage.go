@@ -28,6 +28,8 @@ import (
 	"github.com/google/trillian/storage"
 	"github.com/google/trillian/storage/cache"
 	"github.com/google/trillian/storage/storagepb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const degree = 8
@@ -77,8 +79,8 @@ func (t *tree) RUnlock() {
 	t.mu.RUnlock()
 }
 
-// TreeStorage is shared between the memoryLog and (forthcoming) memoryMap-
-// Storage implementations, and contains functionality which is common to both,
+// TreeStorage is shared between the memoryLog and memoryMap Storage
+// implementations, and contains functionality which is common to both,
 type TreeStorage struct {
 	// mu only protects access to the trees map.
 	mu    sync.RWMutex
@@ -124,11 +126,23 @@ func newTree(t *trillian.Tree) *tree {
 	k.(*kv).v = make(map[string][]int64)
 	ret.store.ReplaceOrInsert(k)
 
+	k = prunedKey(t.TreeId)
+	k.(*kv).v = int64(0)
+	ret.store.ReplaceOrInsert(k)
+
 	return ret
 }
 
 func (m *TreeStorage) beginTreeTX(ctx context.Context, treeID int64, hashSizeBytes int, cache cache.SubtreeCache, readonly bool) (treeTX, error) {
 	tree := m.getTree(treeID)
+	if tree == nil {
+		return treeTX{}, status.Errorf(codes.NotFound, "tree %d not found", treeID)
+	}
+	return m.beginTX(tree, treeID, hashSizeBytes, cache, readonly), nil
+}
+
+// beginTX starts a transaction on tree.
+func (m *TreeStorage) beginTX(tree *tree, treeID int64, hashSizeBytes int, cache cache.SubtreeCache, readonly bool) treeTX {
 	// Lock the tree for the duration of the TX.
 	// It will be unlocked by a call to Commit or Rollback.
 	var unlock func()
@@ -148,7 +162,7 @@ func (m *TreeStorage) beginTreeTX(ctx context.Context, treeID int64, hashSizeByt
 		subtreeCache:  cache,
 		writeRevision: -1,
 		unlock:        unlock,
-	}, nil
+	}
 }
 
 type treeTX struct {
@@ -199,6 +213,10 @@ func (t *treeTX) getSubtrees(ctx context.Context, treeRevision int64, nodeIDs []
 			// Return a copy of the proto to protect against the caller modifying the stored one.
 			p := s.(*kv).v.(*storagepb.SubtreeProto)
 			v := proto.Clone(p).(*storagepb.SubtreeProto)
+			// Clone turns the empty prefix of the root subtree into nil.
+			if v.Prefix == nil {
+				v.Prefix = []byte{}
+			}
 			ret = append(ret, v)
 			break
 		}
@@ -0,0 +1,60 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+	"github.com/google/trillian/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	storageto "github.com/google/trillian/storage/testonly"
+)
+
+func TestUnknownTree(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTreeStorage()
+	logs, maps := NewLogStorage(ts, nil), NewMapStorage(ts)
+
+	logTree := proto.Clone(storageto.LogTree).(*trillian.Tree)
+	logTree.TreeId = 12345
+	if _, err := logs.SnapshotForTree(ctx, logTree); status.Code(err) != codes.NotFound {
+		t.Errorf("log SnapshotForTree(): %v, want %v", err, codes.NotFound)
+	}
+	err := logs.ReadWriteTransaction(ctx, logTree, func(context.Context, storage.LogTreeTX) error { return nil })
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("log ReadWriteTransaction(): %v, want %v", err, codes.NotFound)
+	}
+
+	mapTree := proto.Clone(storageto.MapTree).(*trillian.Tree)
+	mapTree.TreeId = 12345
+	err = maps.ReadWriteTransaction(ctx, mapTree, func(context.Context, storage.MapTreeTX) error { return nil })
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("map ReadWriteTransaction(): %v, want %v", err, codes.NotFound)
+	}
+	// Snapshots of unknown maps see an empty map.
+	tx, err := maps.SnapshotForTree(ctx, mapTree)
+	if err != nil {
+		t.Fatalf("map SnapshotForTree(): %v", err)
+	}
+	defer tx.Close()
+	if _, err := tx.GetSignedMapRoot(ctx, 0); err == nil {
+		t.Error("GetSignedMapRoot(0): nil, want error")
+	}
+}
@@ -0,0 +1,241 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package migrate applies versioned schema migrations to the databases of the
+// SQL storage implementations.
+//
+// The versions applied to a database are recorded in a schema version table.
+// Version 1 stands for the schema which predates version tracking, so a
+// database holding the Trillian tables but no version table is considered to
+// be at version 1. Later versions are reached by running the statements of
+// their Migration.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// BaselineVersion is the version of a schema which predates version tracking.
+const BaselineVersion = 1
+
+// Migration describes the changes between a schema version and the previous
+// one.
+type Migration struct {
+	// Version is the schema version reached by the migration.
+	Version int
+	// Description is a short summary of the changes, recorded along with the
+	// version.
+	Description string
+	// Statements are the SQL statements run, in order, by the migration.
+	Statements []string
+}
+
+// Dialect holds the SQL statements used to track schema versions in a
+// database.
+type Dialect struct {
+	// TableExistsSQL returns the number of tables called as its only
+	// parameter in the current database.
+	TableExistsSQL string
+	// VersionTable is the name of the schema version table.
+	VersionTable string
+	// TreesTable is the name of a table present in any Trillian schema,
+	// versioned or not.
+	TreesTable string
+	// CreateVersionTableSQL creates the schema version table.
+	CreateVersionTableSQL string
+	// SelectVersionSQL returns the latest version in the schema version table,
+	// or NULL if it's empty.
+	SelectVersionSQL string
+	// InsertVersionSQL records a version, taking the version, its description
+	// and the current time in milliseconds since the epoch as parameters.
+	InsertVersionSQL string
+}
+
+// Migrator applies migrations to a database.
+type Migrator struct {
+	db         *sql.DB
+	dialect    Dialect
+	migrations []Migration
+}
+
+// New returns a Migrator for the given database. The migrations must be
+// ordered, and reach consecutive versions starting at BaselineVersion+1.
+func New(db *sql.DB, dialect Dialect, migrations []Migration) (*Migrator, error) {
+	for i, m := range migrations {
+		if want := BaselineVersion + 1 + i; m.Version != want {
+			return nil, fmt.Errorf("migration %d reaches version %d, want %d", i, m.Version, want)
+		}
+		if len(m.Statements) == 0 {
+			return nil, fmt.Errorf("migration to version %d has no statements", m.Version)
+		}
+	}
+	return &Migrator{db: db, dialect: dialect, migrations: migrations}, nil
+}
+
+// Latest returns the version reached by the last migration.
+func (m *Migrator) Latest() int {
+	return BaselineVersion + len(m.migrations)
+}
+
+// Version returns the current schema version of the database, or 0 if it
+// doesn't hold a Trillian schema.
+func (m *Migrator) Version(ctx context.Context) (int, error) {
+	versioned, err := m.tableExists(ctx, m.dialect.VersionTable)
+	if err != nil {
+		return 0, err
+	}
+	if versioned {
+		var version sql.NullInt64
+		if err := m.db.QueryRowContext(ctx, m.dialect.SelectVersionSQL).Scan(&version); err != nil {
+			return 0, err
+		}
+		if version.Valid {
+			return int(version.Int64), nil
+		}
+	}
+
+	switch exists, err := m.tableExists(ctx, m.dialect.TreesTable); {
+	case err != nil:
+		return 0, err
+	case exists:
+		return BaselineVersion, nil
+	}
+	return 0, nil
+}
+
+// Pending returns the migrations not applied to the database yet.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	version, err := m.Version(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if version == 0 {
+		return nil, fmt.Errorf("no Trillian schema found, the database needs to be initialized with its storage.sql")
+	}
+	if version > m.Latest() {
+		return nil, fmt.Errorf("schema version %d is newer than the latest known version %d", version, m.Latest())
+	}
+	return m.migrations[version-BaselineVersion:], nil
+}
+
+// Up applies the pending migrations to the database, in order, and returns
+// them. The statements run are written to w, if not nil. If dryRun is true the
+// statements are only written, and the database is left untouched.
+//
+// Each migration is applied in its own transaction along with the recording of
+// its version. Note that some databases, like MySQL, implicitly commit schema
+// changes; if a migration fails part way through on those, the database has to
+// be fixed by hand before retrying.
+func (m *Migrator) Up(ctx context.Context, dryRun bool, w io.Writer) ([]Migration, error) {
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+	if w == nil {
+		w = ioutil.Discard
+	}
+
+	versioned, err := m.tableExists(ctx, m.dialect.VersionTable)
+	if err != nil {
+		return nil, err
+	}
+	if !versioned {
+		fmt.Fprintf(w, "%s;\n", m.dialect.CreateVersionTableSQL)
+		if !dryRun {
+			if _, err := m.db.ExecContext(ctx, m.dialect.CreateVersionTableSQL); err != nil {
+				return nil, fmt.Errorf("failed to create schema version table: %v", err)
+			}
+			if err := m.recordVersion(ctx, m.db, BaselineVersion, "Baseline schema"); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for i, mig := range pending {
+		fmt.Fprintf(w, "-- Version %d: %s\n", mig.Version, mig.Description)
+		for _, stmt := range mig.Statements {
+			fmt.Fprintf(w, "%s;\n", stmt)
+		}
+		if dryRun {
+			continue
+		}
+		glog.Infof("Migrating schema to version %d: %s", mig.Version, mig.Description)
+		if err := m.apply(ctx, mig); err != nil {
+			return pending[:i], fmt.Errorf("failed to migrate schema to version %d: %v", mig.Version, err)
+		}
+	}
+	return pending, nil
+}
+
+// Check returns an error if the schema version of the database is older than
+// minVersion.
+func (m *Migrator) Check(ctx context.Context, minVersion int) error {
+	version, err := m.Version(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %v", err)
+	}
+	if version < minVersion {
+		return fmt.Errorf("schema version %d is older than the required version %d, the database needs to be migrated with trillian_migrate", version, minVersion)
+	}
+	return nil
+}
+
+func (m *Migrator) apply(ctx context.Context, mig Migration) error {
+	tx, err := m.db.BeginTx(ctx, nil /* opts */)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range mig.Statements {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if err := m.recordVersion(ctx, tx, mig.Version, mig.Description); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// execer is implemented by both *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func (m *Migrator) recordVersion(ctx context.Context, e execer, version int, description string) error {
+	nowMillis := time.Now().UnixNano() / int64(time.Millisecond)
+	if _, err := e.ExecContext(ctx, m.dialect.InsertVersionSQL, version, description, nowMillis); err != nil {
+		return fmt.Errorf("failed to record schema version %d: %v", version, err)
+	}
+	return nil
+}
+
+func (m *Migrator) tableExists(ctx context.Context, table string) (bool, error) {
+	var count int
+	if err := m.db.QueryRowContext(ctx, m.dialect.TableExistsSQL, table).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
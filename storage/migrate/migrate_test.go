@@ -0,0 +1,108 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate_test
+
+import (
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/google/trillian/storage/migrate"
+	"github.com/google/trillian/storage/mysql"
+	"github.com/google/trillian/storage/postgres"
+	"github.com/google/trillian/testonly"
+)
+
+func TestNew(t *testing.T) {
+	step := func(version int) migrate.Migration {
+		return migrate.Migration{Version: version, Description: fmt.Sprintf("v%d", version), Statements: []string{"SELECT 1"}}
+	}
+	for _, tc := range []struct {
+		desc       string
+		migrations []migrate.Migration
+		wantLatest int
+		wantErr    bool
+	}{
+		{desc: "none", wantLatest: migrate.BaselineVersion},
+		{desc: "consecutive", migrations: []migrate.Migration{step(2), step(3)}, wantLatest: 3},
+		{desc: "startsAtBaseline", migrations: []migrate.Migration{step(1), step(2)}, wantErr: true},
+		{desc: "gap", migrations: []migrate.Migration{step(2), step(4)}, wantErr: true},
+		{desc: "unordered", migrations: []migrate.Migration{step(3), step(2)}, wantErr: true},
+		{desc: "noStatements", migrations: []migrate.Migration{{Version: 2}}, wantErr: true},
+	} {
+		m, err := migrate.New(nil, migrate.Dialect{}, tc.migrations)
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Errorf("%v: New(): %v, wantErr %v", tc.desc, err, tc.wantErr)
+			continue
+		}
+		if err == nil && m.Latest() != tc.wantLatest {
+			t.Errorf("%v: Latest() = %d, want %d", tc.desc, m.Latest(), tc.wantLatest)
+		}
+	}
+}
+
+// initialVersionRE matches the schema version recorded by a storage.sql file.
+var initialVersionRE = regexp.MustCompile(`(?i)SELECT (\d+), 'Initial schema'`)
+
+// TestSchemaFiles checks that the storage.sql files, the SchemaVersion
+// constants and the migrations of the SQL storage implementations agree on the
+// latest schema version.
+func TestSchemaFiles(t *testing.T) {
+	for _, tc := range []struct {
+		desc          string
+		files         []string
+		schemaVersion int
+		newMigrator   func(*sql.DB) (*migrate.Migrator, error)
+	}{
+		{
+			desc:          "mysql",
+			files:         []string{"../mysql/schema/storage.sql"},
+			schemaVersion: mysql.SchemaVersion,
+			newMigrator:   mysql.NewMigrator,
+		},
+		{
+			desc:          "postgres",
+			files:         []string{"../postgres/schema/storage.sql", "../postgres/storage_unsafe.sql"},
+			schemaVersion: postgres.SchemaVersion,
+			newMigrator:   postgres.NewMigrator,
+		},
+	} {
+		m, err := tc.newMigrator(nil)
+		if err != nil {
+			t.Errorf("%v: NewMigrator(): %v", tc.desc, err)
+			continue
+		}
+		if got, want := m.Latest(), tc.schemaVersion; got != want {
+			t.Errorf("%v: Latest() = %d, want SchemaVersion %d", tc.desc, got, want)
+		}
+		for _, file := range tc.files {
+			b, err := ioutil.ReadFile(testonly.RelativeToPackage(file))
+			if err != nil {
+				t.Fatalf("ReadFile(%v): %v", file, err)
+			}
+			match := initialVersionRE.FindSubmatch(b)
+			if match == nil {
+				t.Errorf("%v: no schema version recorded", file)
+				continue
+			}
+			if got, _ := strconv.Atoi(string(match[1])); got != tc.schemaVersion {
+				t.Errorf("%v: records schema version %d, want %d", file, got, tc.schemaVersion)
+			}
+		}
+	}
+}
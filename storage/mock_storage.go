@@ -217,6 +217,21 @@ func (mr *MockAdminTXMockRecorder) ListTrees(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrees", reflect.TypeOf((*MockAdminTX)(nil).ListTrees), arg0, arg1)
 }
 
+// PurgeTreeData mocks base method
+func (m *MockAdminTX) PurgeTreeData(arg0 context.Context, arg1 int64, arg2 int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTreeData", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeTreeData indicates an expected call of PurgeTreeData
+func (mr *MockAdminTXMockRecorder) PurgeTreeData(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTreeData", reflect.TypeOf((*MockAdminTX)(nil).PurgeTreeData), arg0, arg1, arg2)
+}
+
 // Rollback mocks base method
 func (m *MockAdminTX) Rollback() error {
 	m.ctrl.T.Helper()
@@ -572,6 +587,21 @@ func (mr *MockLogTreeTXMockRecorder) LatestSignedLogRoot(arg0 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LatestSignedLogRoot", reflect.TypeOf((*MockLogTreeTX)(nil).LatestSignedLogRoot), arg0)
 }
 
+// PruneLeaves mocks base method
+func (m *MockLogTreeTX) PruneLeaves(arg0 context.Context, arg1 int64, arg2 int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneLeaves", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneLeaves indicates an expected call of PruneLeaves
+func (mr *MockLogTreeTXMockRecorder) PruneLeaves(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneLeaves", reflect.TypeOf((*MockLogTreeTX)(nil).PruneLeaves), arg0, arg1, arg2)
+}
+
 // QueueLeaves mocks base method
 func (m *MockLogTreeTX) QueueLeaves(arg0 context.Context, arg1 []*trillian.LogLeaf, arg2 time.Time) ([]*trillian.LogLeaf, error) {
 	m.ctrl.T.Helper()
@@ -776,6 +806,21 @@ func (mr *MockMapTreeTXMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockMapTreeTX)(nil).Close))
 }
 
+// CollectRevisions mocks base method
+func (m *MockMapTreeTX) CollectRevisions(arg0 context.Context, arg1 int64, arg2 int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CollectRevisions", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CollectRevisions indicates an expected call of CollectRevisions
+func (mr *MockMapTreeTXMockRecorder) CollectRevisions(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectRevisions", reflect.TypeOf((*MockMapTreeTX)(nil).CollectRevisions), arg0, arg1, arg2)
+}
+
 // Commit mocks base method
 func (m *MockMapTreeTX) Commit(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -864,6 +909,51 @@ func (mr *MockMapTreeTXMockRecorder) LatestSignedMapRoot(arg0 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LatestSignedMapRoot", reflect.TypeOf((*MockMapTreeTX)(nil).LatestSignedMapRoot), arg0)
 }
 
+// ListChangedKeys mocks base method
+func (m *MockMapTreeTX) ListChangedKeys(arg0 context.Context, arg1, arg2 int64, arg3 []byte, arg4 int) ([][]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChangedKeys", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChangedKeys indicates an expected call of ListChangedKeys
+func (mr *MockMapTreeTXMockRecorder) ListChangedKeys(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChangedKeys", reflect.TypeOf((*MockMapTreeTX)(nil).ListChangedKeys), arg0, arg1, arg2, arg3, arg4)
+}
+
+// ListLeafRevisions mocks base method
+func (m *MockMapTreeTX) ListLeafRevisions(arg0 context.Context, arg1 []byte, arg2, arg3 int64, arg4 int) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLeafRevisions", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLeafRevisions indicates an expected call of ListLeafRevisions
+func (mr *MockMapTreeTXMockRecorder) ListLeafRevisions(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLeafRevisions", reflect.TypeOf((*MockMapTreeTX)(nil).ListLeafRevisions), arg0, arg1, arg2, arg3, arg4)
+}
+
+// ListLeaves mocks base method
+func (m *MockMapTreeTX) ListLeaves(arg0 context.Context, arg1 int64, arg2, arg3 []byte, arg4 int) ([]*trillian.MapLeaf, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLeaves", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]*trillian.MapLeaf)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLeaves indicates an expected call of ListLeaves
+func (mr *MockMapTreeTXMockRecorder) ListLeaves(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLeaves", reflect.TypeOf((*MockMapTreeTX)(nil).ListLeaves), arg0, arg1, arg2, arg3, arg4)
+}
+
 // ReadRevision mocks base method
 func (m *MockMapTreeTX) ReadRevision(arg0 context.Context) (int64, error) {
 	m.ctrl.T.Helper()
@@ -1463,6 +1553,51 @@ func (mr *MockReadOnlyMapTreeTXMockRecorder) LatestSignedMapRoot(arg0 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LatestSignedMapRoot", reflect.TypeOf((*MockReadOnlyMapTreeTX)(nil).LatestSignedMapRoot), arg0)
 }
 
+// ListChangedKeys mocks base method
+func (m *MockReadOnlyMapTreeTX) ListChangedKeys(arg0 context.Context, arg1, arg2 int64, arg3 []byte, arg4 int) ([][]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChangedKeys", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChangedKeys indicates an expected call of ListChangedKeys
+func (mr *MockReadOnlyMapTreeTXMockRecorder) ListChangedKeys(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChangedKeys", reflect.TypeOf((*MockReadOnlyMapTreeTX)(nil).ListChangedKeys), arg0, arg1, arg2, arg3, arg4)
+}
+
+// ListLeafRevisions mocks base method
+func (m *MockReadOnlyMapTreeTX) ListLeafRevisions(arg0 context.Context, arg1 []byte, arg2, arg3 int64, arg4 int) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLeafRevisions", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLeafRevisions indicates an expected call of ListLeafRevisions
+func (mr *MockReadOnlyMapTreeTXMockRecorder) ListLeafRevisions(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLeafRevisions", reflect.TypeOf((*MockReadOnlyMapTreeTX)(nil).ListLeafRevisions), arg0, arg1, arg2, arg3, arg4)
+}
+
+// ListLeaves mocks base method
+func (m *MockReadOnlyMapTreeTX) ListLeaves(arg0 context.Context, arg1 int64, arg2, arg3 []byte, arg4 int) ([]*trillian.MapLeaf, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLeaves", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]*trillian.MapLeaf)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLeaves indicates an expected call of ListLeaves
+func (mr *MockReadOnlyMapTreeTXMockRecorder) ListLeaves(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLeaves", reflect.TypeOf((*MockReadOnlyMapTreeTX)(nil).ListLeaves), arg0, arg1, arg2, arg3, arg4)
+}
+
 // ReadRevision mocks base method
 func (m *MockReadOnlyMapTreeTX) ReadRevision(arg0 context.Context) (int64, error) {
 	m.ctrl.T.Helper()
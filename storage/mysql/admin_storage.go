@@ -26,10 +26,15 @@ import (
 	"github.com/golang/protobuf/ptypes"
 	"github.com/google/trillian"
 	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/mysql/mysqlpb"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// MaxUnseqBuckets is the maximum number of buckets the Unsequenced queue of a
+// log can be spread over, see mysqlpb.StorageOptions.
+const MaxUnseqBuckets = 256
+
 const (
 	defaultSequenceIntervalSeconds = 60
 
@@ -54,16 +59,39 @@ const (
 			PublicKey,
 			MaxRootDurationMillis,
 			Deleted,
-			DeleteTimeMillis
+			DeleteTimeMillis,
+			SequencingConfig,
+			RetentionPolicy,
+			StorageSettings,
+			LogRootConfig,
+			MapRetentionPolicy
 		FROM Trees`
 	selectNonDeletedTrees = selectTrees + nonDeletedWhere
 	selectTreeByID        = selectTrees + " WHERE TreeId = ?"
 
 	updateTreeSQL = `UPDATE Trees
-		SET TreeState = ?, TreeType = ?, DisplayName = ?, Description = ?, UpdateTimeMillis = ?, MaxRootDurationMillis = ?, PrivateKey = ?
+		SET TreeState = ?, TreeType = ?, DisplayName = ?, Description = ?, UpdateTimeMillis = ?, MaxRootDurationMillis = ?, PrivateKey = ?,
+			SequencingConfig = ?, RetentionPolicy = ?, StorageSettings = ?, MapRetentionPolicy = ?
 		WHERE TreeId = ?`
+
+	deleteTreeDataSQL = "DELETE FROM %s WHERE TreeId = ?"
+	purgeTreeDataSQL  = deleteTreeDataSQL + " LIMIT ?"
 )
 
+// treeDataTables lists the tables holding the data of a tree, in the order
+// they're purged in. Referencing tables come before the tables they reference,
+// so that older schemas lacking "ON DELETE CASCADE" don't get in the way.
+var treeDataTables = []string{
+	"Unsequenced",
+	"SequencedLeafData",
+	"LeafData",
+	"PrunedLeaves",
+	"Subtree",
+	"TreeHead",
+	"MapLeaf",
+	"MapHead",
+}
+
 // NewAdminStorage returns a MySQL storage.AdminStorage implementation backed by DB.
 func NewAdminStorage(db *sql.DB) storage.AdminStorage {
 	return &mysqlAdminStorage{db}
@@ -276,8 +304,13 @@ func (t *adminTX) CreateTree(ctx context.Context, tree *trillian.Tree) (*trillia
 			UpdateTimeMillis,
 			PrivateKey,
 			PublicKey,
-			MaxRootDurationMillis)
-		VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+			MaxRootDurationMillis,
+			SequencingConfig,
+			RetentionPolicy,
+			StorageSettings,
+			LogRootConfig,
+			MapRetentionPolicy)
+		VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
 	if err != nil {
 		return nil, err
 	}
@@ -287,6 +320,26 @@ func (t *adminTX) CreateTree(ctx context.Context, tree *trillian.Tree) (*trillia
 	if err != nil {
 		return nil, fmt.Errorf("could not marshal PrivateKey: %v", err)
 	}
+	sequencingConfig, err := storage.MarshalSequencingConfig(newTree)
+	if err != nil {
+		return nil, err
+	}
+	retentionPolicy, err := storage.MarshalRetentionPolicy(newTree)
+	if err != nil {
+		return nil, err
+	}
+	storageSettings, err := storage.MarshalStorageSettings(newTree)
+	if err != nil {
+		return nil, err
+	}
+	logRootConfig, err := storage.MarshalLogRootConfig(newTree)
+	if err != nil {
+		return nil, err
+	}
+	mapRetentionPolicy, err := storage.MarshalMapRetentionPolicy(newTree)
+	if err != nil {
+		return nil, err
+	}
 
 	_, err = insertTreeStmt.ExecContext(
 		ctx,
@@ -303,6 +356,11 @@ func (t *adminTX) CreateTree(ctx context.Context, tree *trillian.Tree) (*trillia
 		privateKey,
 		newTree.PublicKey.GetDer(),
 		rootDuration/time.Millisecond,
+		sequencingConfig,
+		retentionPolicy,
+		storageSettings,
+		logRootConfig,
+		mapRetentionPolicy,
 	)
 	if err != nil {
 		return nil, err
@@ -354,7 +412,7 @@ func (t *adminTX) UpdateTree(ctx context.Context, treeID int64, updateFunc func(
 	if err := storage.ValidateTreeForUpdate(ctx, beforeUpdate, tree); err != nil {
 		return nil, err
 	}
-	if err := validateStorageSettings(tree); err != nil {
+	if err := validateStorageSettingsUpdate(beforeUpdate, tree); err != nil {
 		return nil, err
 	}
 
@@ -377,6 +435,22 @@ func (t *adminTX) UpdateTree(ctx context.Context, treeID int64, updateFunc func(
 	if err != nil {
 		return nil, fmt.Errorf("could not marshal PrivateKey: %v", err)
 	}
+	sequencingConfig, err := storage.MarshalSequencingConfig(tree)
+	if err != nil {
+		return nil, err
+	}
+	retentionPolicy, err := storage.MarshalRetentionPolicy(tree)
+	if err != nil {
+		return nil, err
+	}
+	storageSettings, err := storage.MarshalStorageSettings(tree)
+	if err != nil {
+		return nil, err
+	}
+	mapRetentionPolicy, err := storage.MarshalMapRetentionPolicy(tree)
+	if err != nil {
+		return nil, err
+	}
 
 	stmt, err := t.tx.PrepareContext(ctx, updateTreeSQL)
 	if err != nil {
@@ -393,6 +467,10 @@ func (t *adminTX) UpdateTree(ctx context.Context, treeID int64, updateFunc func(
 		nowMillis,
 		rootDuration/time.Millisecond,
 		privateKey,
+		sequencingConfig,
+		retentionPolicy,
+		storageSettings,
+		mapRetentionPolicy,
 		tree.TreeId); err != nil {
 		return nil, err
 	}
@@ -428,7 +506,13 @@ func (t *adminTX) HardDeleteTree(ctx context.Context, treeID int64) error {
 		return err
 	}
 
-	// TreeControl didn't have "ON DELETE CASCADE" on previous versions, so let's hit it explicitly
+	// Tree data and TreeControl didn't have "ON DELETE CASCADE" on previous versions, so let's hit
+	// them explicitly.
+	for _, table := range treeDataTables {
+		if _, err := t.tx.ExecContext(ctx, fmt.Sprintf(deleteTreeDataSQL, table), treeID); err != nil {
+			return err
+		}
+	}
 	if _, err := t.tx.ExecContext(ctx, "DELETE FROM TreeControl WHERE TreeId = ?", treeID); err != nil {
 		return err
 	}
@@ -436,6 +520,29 @@ func (t *adminTX) HardDeleteTree(ctx context.Context, treeID int64) error {
 	return err
 }
 
+func (t *adminTX) PurgeTreeData(ctx context.Context, treeID int64, limit int) (int, error) {
+	if err := validateDeleted(ctx, t.tx, treeID, true /* wantDeleted */); err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, table := range treeDataTables {
+		if purged >= limit {
+			break
+		}
+		res, err := t.tx.ExecContext(ctx, fmt.Sprintf(purgeTreeDataSQL, table), treeID, limit-purged)
+		if err != nil {
+			return purged, err
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return purged, err
+		}
+		purged += int(rows)
+	}
+	return purged, nil
+}
+
 func validateDeleted(ctx context.Context, tx *sql.Tx, treeID int64, wantDeleted bool) error {
 	var nullDeleted sql.NullBool
 	switch err := tx.QueryRowContext(ctx, "SELECT Deleted FROM Trees WHERE TreeId = ?", treeID).Scan(&nullDeleted); {
@@ -454,9 +561,61 @@ func validateDeleted(ctx context.Context, tx *sql.Tx, treeID int64, wantDeleted
 	return nil
 }
 
+// storageOptions returns the options held in tree.StorageSettings, or the
+// default options if they're unset.
+func storageOptions(tree *trillian.Tree) (*mysqlpb.StorageOptions, error) {
+	opts := &mysqlpb.StorageOptions{}
+	if tree.StorageSettings == nil {
+		return opts, nil
+	}
+	if err := ptypes.UnmarshalAny(tree.StorageSettings, opts); err != nil {
+		return nil, fmt.Errorf("storage_settings of type %v not supported, want mysqlpb.StorageOptions: %v", tree.StorageSettings.GetTypeUrl(), err)
+	}
+	return opts, nil
+}
+
+// numUnseqBuckets returns the number of buckets of the Unsequenced queue of
+// tree.
+func numUnseqBuckets(tree *trillian.Tree) (int, error) {
+	opts, err := storageOptions(tree)
+	if err != nil {
+		return 0, err
+	}
+	if opts.NumUnseqBuckets < 1 {
+		return 1, nil
+	}
+	return int(opts.NumUnseqBuckets), nil
+}
+
 func validateStorageSettings(tree *trillian.Tree) error {
-	if tree.StorageSettings != nil {
-		return fmt.Errorf("storage_settings not supported, but got %v", tree.StorageSettings)
+	opts, err := storageOptions(tree)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if n := opts.NumUnseqBuckets; n < 0 || n > MaxUnseqBuckets {
+		return status.Errorf(codes.InvalidArgument, "NumUnseqBuckets = %v, want a number in range [0, %v]", n, MaxUnseqBuckets)
+	}
+	return nil
+}
+
+// validateStorageSettingsUpdate checks that the number of Unsequenced buckets
+// doesn't decrease, as the leaves queued in the dropped buckets would never be
+// dequeued.
+func validateStorageSettingsUpdate(before, after *trillian.Tree) error {
+	if err := validateStorageSettings(after); err != nil {
+		return err
+	}
+	// before is known to be valid, as it's been read from storage.
+	was, err := numUnseqBuckets(before)
+	if err != nil {
+		return err
+	}
+	is, err := numUnseqBuckets(after)
+	if err != nil {
+		return err
+	}
+	if is < was {
+		return status.Errorf(codes.InvalidArgument, "NumUnseqBuckets decreased from %v to %v, it can only be increased", was, is)
 	}
 	return nil
 }
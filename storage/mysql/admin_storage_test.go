@@ -25,6 +25,7 @@ import (
 	"github.com/google/trillian"
 	"github.com/google/trillian/crypto/keyspb"
 	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/mysql/mysqlpb"
 	"github.com/google/trillian/storage/testonly"
 )
 
@@ -190,6 +191,56 @@ func TestAdminTX_StorageSettingsNotSupported(t *testing.T) {
 	}
 }
 
+func TestAdminTX_StorageOptions(t *testing.T) {
+	cleanTestDB(DB)
+	s := NewAdminStorage(DB)
+	ctx := context.Background()
+
+	withBuckets := func(n int32) *trillian.Tree {
+		tree := proto.Clone(testonly.LogTree).(*trillian.Tree)
+		settings, err := ptypes.MarshalAny(&mysqlpb.StorageOptions{NumUnseqBuckets: n})
+		if err != nil {
+			t.Fatalf("Error marshaling proto: %v", err)
+		}
+		tree.StorageSettings = settings
+		return tree
+	}
+
+	for _, n := range []int32{-1, MaxUnseqBuckets + 1} {
+		if _, err := storage.CreateTree(ctx, s, withBuckets(n)); err == nil {
+			t.Errorf("CreateTree(NumUnseqBuckets=%v): err = nil, want non-nil", n)
+		}
+	}
+
+	tree, err := storage.CreateTree(ctx, s, withBuckets(4))
+	if err != nil {
+		t.Fatalf("CreateTree() failed with err = %v", err)
+	}
+	got, err := storage.GetTree(ctx, s, tree.TreeId)
+	if err != nil {
+		t.Fatalf("GetTree() failed with err = %v", err)
+	}
+	if !proto.Equal(got.StorageSettings, tree.StorageSettings) {
+		t.Errorf("GetTree().StorageSettings = %v, want %v", got.StorageSettings, tree.StorageSettings)
+	}
+
+	for _, test := range []struct {
+		n       int32
+		wantErr bool
+	}{
+		{n: 8},
+		{n: 8},
+		{n: 2, wantErr: true},
+		{n: 0, wantErr: true},
+	} {
+		updated := withBuckets(test.n)
+		_, err := storage.UpdateTree(ctx, s, tree.TreeId, func(tree *trillian.Tree) { tree.StorageSettings = updated.StorageSettings })
+		if gotErr := err != nil; gotErr != test.wantErr {
+			t.Errorf("UpdateTree(NumUnseqBuckets=%v): err = %v, wantErr = %v", test.n, err, test.wantErr)
+		}
+	}
+}
+
 func TestAdminTX_HardDeleteTree(t *testing.T) {
 	cleanTestDB(DB)
 	s := NewAdminStorage(DB)
@@ -218,6 +269,58 @@ func TestAdminTX_HardDeleteTree(t *testing.T) {
 	}
 }
 
+func TestAdminTX_PurgeTreeData(t *testing.T) {
+	cleanTestDB(DB)
+	s := NewAdminStorage(DB)
+	ctx := context.Background()
+
+	tree, err := storage.CreateTree(ctx, s, testonly.LogTree)
+	if err != nil {
+		t.Fatalf("CreateTree() returned err = %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		if _, err := DB.ExecContext(ctx, "INSERT INTO Subtree(TreeId, SubtreeId, Nodes, SubtreeRevision) VALUES(?, ?, ?, ?)",
+			tree.TreeId, []byte{byte(i)}, []byte("nodes"), 1); err != nil {
+			t.Fatalf("Failed to insert subtree: %v", err)
+		}
+		if _, err := DB.ExecContext(ctx, "INSERT INTO TreeHead(TreeId, TreeHeadTimestamp, TreeSize, RootHash, RootSignature, TreeRevision) VALUES(?, ?, ?, ?, ?, ?)",
+			tree.TreeId, i, i, []byte("hash"), []byte("sig"), i); err != nil {
+			t.Fatalf("Failed to insert tree head: %v", err)
+		}
+	}
+	if _, err := storage.SoftDeleteTree(ctx, s, tree.TreeId); err != nil {
+		t.Fatalf("SoftDeleteTree() returned err = %v", err)
+	}
+
+	// The 10 records are purged 4 at a time.
+	for _, want := range []int{4, 4, 2, 0} {
+		purged, err := storage.PurgeTreeData(ctx, s, tree.TreeId, 4)
+		if err != nil {
+			t.Fatalf("PurgeTreeData() returned err = %v", err)
+		}
+		if purged != want {
+			t.Errorf("PurgeTreeData() = %v, want = %v", purged, want)
+		}
+	}
+	for _, table := range []string{"Subtree", "TreeHead"} {
+		var count int
+		if err := DB.QueryRowContext(ctx, fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE TreeId = ?", table), tree.TreeId).Scan(&count); err != nil {
+			t.Fatalf("QueryRowContext() returned err = %v", err)
+		}
+		if count != 0 {
+			t.Errorf("%v: %v records left after purging", table, count)
+		}
+	}
+
+	// The tree itself is kept until it's hard deleted.
+	if _, err := storage.GetTree(ctx, s, tree.TreeId); err != nil {
+		t.Errorf("GetTree() returned err = %v", err)
+	}
+	if err := storage.HardDeleteTree(ctx, s, tree.TreeId); err != nil {
+		t.Errorf("HardDeleteTree() returned err = %v", err)
+	}
+}
+
 func TestCheckDatabaseAccessible_Fails(t *testing.T) {
 	ctx := context.Background()
 
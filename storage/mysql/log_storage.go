@@ -20,6 +20,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"hash/fnv"
+	"math"
 	"sort"
 	"strconv"
 	"sync"
@@ -54,6 +56,9 @@ const (
 	selectLatestSignedLogRootSQL = `SELECT TreeHeadTimestamp,TreeSize,RootHash,TreeRevision,RootSignature
 			FROM TreeHead WHERE TreeId=?
 			ORDER BY TreeHeadTimestamp DESC LIMIT 1`
+	selectSignedLogRootsSQL = `SELECT TreeHeadTimestamp,TreeSize,RootHash,TreeRevision,RootSignature
+			FROM TreeHead WHERE TreeId=? AND TreeHeadTimestamp>=?
+			ORDER BY TreeHeadTimestamp LIMIT ?`
 
 	selectLeavesByRangeSQL = `SELECT s.MerkleLeafHash,l.LeafIdentityHash,l.LeafValue,s.SequenceNumber,l.ExtraData,l.QueueTimestampNanos,s.IntegrateTimestampNanos
 			FROM LeafData l,SequencedLeafData s
@@ -78,6 +83,17 @@ const (
 			FROM LeafData l LEFT JOIN SequencedLeafData s ON (l.LeafIdentityHash = s.LeafIdentityHash AND l.TreeID = s.TreeID)
 			WHERE l.LeafIdentityHash IN (` + placeholderSQL + `) AND l.TreeId = ?`
 
+	selectPrunedSizeSQL  = "SELECT PrunedSize FROM PrunedLeaves WHERE TreeId=?"
+	replacePrunedSizeSQL = `INSERT INTO PrunedLeaves(TreeId,PrunedSize) VALUES(?,?)
+			ON DUPLICATE KEY UPDATE PrunedSize=VALUES(PrunedSize)`
+	// LeafData rows are shared by duplicate leaves, so the data of a leaf in
+	// the pruned range is kept if it's also referenced by a later leaf.
+	pruneLeafDataSQL = `UPDATE LeafData l
+			JOIN SequencedLeafData s ON (l.TreeId = s.TreeId AND l.LeafIdentityHash = s.LeafIdentityHash)
+			LEFT JOIN SequencedLeafData r ON (r.TreeId = s.TreeId AND r.LeafIdentityHash = s.LeafIdentityHash AND r.SequenceNumber >= ?)
+			SET l.LeafValue = '', l.ExtraData = NULL
+			WHERE s.TreeId = ? AND s.SequenceNumber >= ? AND s.SequenceNumber < ? AND r.TreeId IS NULL`
+
 	// Same as above except with leaves ordered by sequence so we only incur this cost when necessary
 	orderBySequenceNumberSQL                     = " ORDER BY s.SequenceNumber"
 	selectLeavesByMerkleHashOrderedBySequenceSQL = selectLeavesByMerkleHashSQL + orderBySequenceNumberSQL
@@ -242,6 +258,10 @@ func (m *mySQLLogStorage) beginInternal(ctx context.Context, tree *trillian.Tree
 	if err != nil {
 		return nil, err
 	}
+	numBuckets, err := numUnseqBuckets(tree)
+	if err != nil {
+		return nil, err
+	}
 
 	stCache := cache.NewLogSubtreeCache(defaultLogStrata, hasher)
 	ttx, err := m.beginTreeTx(ctx, tree, hasher.Size(), stCache)
@@ -250,8 +270,10 @@ func (m *mySQLLogStorage) beginInternal(ctx context.Context, tree *trillian.Tree
 	}
 
 	ltx := &logTreeTX{
-		treeTX: ttx,
-		ls:     m,
+		treeTX:        ttx,
+		ls:            m,
+		numBuckets:    numBuckets,
+		logRootConfig: tree.LogRootConfig,
 	}
 	ltx.slr, err = ltx.fetchLatestRoot(ctx)
 	if err == storage.ErrTreeNeedsInit {
@@ -350,6 +372,11 @@ type logTreeTX struct {
 	ls   *mySQLLogStorage
 	root types.LogRootV1
 	slr  *trillian.SignedLogRoot
+	// numBuckets is the number of buckets of the Unsequenced queue.
+	numBuckets int
+	// logRootConfig is the format in which the log roots of the tree are
+	// signed, and put back together from the TreeHead rows.
+	logRootConfig *trillian.LogRootConfig
 }
 
 func (t *logTreeTX) ReadRevision(ctx context.Context) (int64, error) {
@@ -389,31 +416,84 @@ func (t *logTreeTX) DequeueLeaves(ctx context.Context, limit int, cutoffTime tim
 
 	leaves := make([]*trillian.LogLeaf, 0, limit)
 	dq := make([]dequeuedLeaf, 0, limit)
-	rows, err := stx.QueryContext(ctx, t.treeID, cutoffTime.UnixNano(), limit)
-	if err != nil {
-		glog.Warningf("Failed to select rows for work: %s", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		leaf, dqInfo, err := t.dequeueLeaf(rows)
-		if err != nil {
-			glog.Warningf("Error dequeuing leaf: %v", err)
-			return nil, err
-		}
-
-		if len(leaf.LeafIdentityHash) != t.hashSizeBytes {
-			return nil, errors.New("dequeued a leaf with incorrect hash size")
+	cutoff := cutoffTime.UnixNano()
+	// after holds the position of the last leaf dequeued from each bucket so
+	// far. Later selects start from there, rather than skip over the leaves
+	// already read with an OFFSET, which would scan them again.
+	after := make([]queuePosition, t.numBuckets)
+	for i := range after {
+		after[i] = queuePosition{timestampNanos: math.MinInt64, leafIdentityHash: []byte{}}
+	}
+	// The buckets are drained in turn, starting from a different one at each
+	// revision so that the share of the limit left over by the division
+	// doesn't always go to the same buckets.
+	active := make([]int, t.numBuckets)
+	for i := range active {
+		active[i] = int((t.root.Revision + uint64(i)) % uint64(t.numBuckets))
+	}
+	// Each round splits what's left of the limit between the buckets which
+	// may hold more leaves, i.e. those which filled their previous share.
+	for remaining := limit; remaining > 0 && len(active) > 0; {
+		share, extra := remaining/len(active), remaining%len(active)
+		var next, full []int
+		for i, bucket := range active {
+			want := share
+			if i < extra {
+				want++
+			}
+			if want == 0 {
+				next = append(next, bucket)
+				continue
+			}
+			pos := after[bucket]
+			rows, err := stx.QueryContext(ctx, t.treeID, bucket, cutoff, pos.timestampNanos, pos.leafIdentityHash, want)
+			if err != nil {
+				glog.Warningf("Failed to select rows for work: %s", err)
+				return nil, err
+			}
+			got := 0
+			for rows.Next() {
+				leaf, dqInfo, err := t.dequeueLeaf(rows, bucket)
+				if err != nil {
+					glog.Warningf("Error dequeuing leaf: %v", err)
+					rows.Close()
+					return nil, err
+				}
+
+				if len(leaf.LeafIdentityHash) != t.hashSizeBytes {
+					rows.Close()
+					return nil, errors.New("dequeued a leaf with incorrect hash size")
+				}
+
+				leaves = append(leaves, leaf)
+				dq = append(dq, dqInfo)
+				got++
+			}
+			err = rows.Err()
+			rows.Close()
+			if err != nil {
+				return nil, err
+			}
+			if got > 0 {
+				last := leaves[len(leaves)-1]
+				ts, err := ptypes.Timestamp(last.QueueTimestamp)
+				if err != nil {
+					return nil, fmt.Errorf("got invalid queue timestamp: %v", err)
+				}
+				after[bucket] = queuePosition{timestampNanos: ts.UnixNano(), leafIdentityHash: last.LeafIdentityHash}
+			}
+			remaining -= got
+			if got == want {
+				full = append(full, bucket)
+			}
 		}
-
-		leaves = append(leaves, leaf)
-		dq = append(dq, dqInfo)
+		active = append(next, full...)
 	}
-
-	if rows.Err() != nil {
-		return nil, rows.Err()
+	if t.numBuckets > 1 {
+		// Hand the leaves of all buckets over in the order they were queued in.
+		sort.Sort(byQueueTimestampAndLeafIdentityHash{leaves: leaves, dq: dq})
 	}
+
 	label := labelForTX(t)
 	selectDuration := time.Since(start)
 	observe(dequeueSelectLatency, selectDuration, label)
@@ -437,6 +517,13 @@ func (t *logTreeTX) DequeueLeaves(ctx context.Context, limit int, cutoffTime tim
 	return leaves, nil
 }
 
+// queuePosition is the position of a leaf in a bucket of the Unsequenced
+// queue, which is ordered by queue timestamp then leaf identity hash.
+type queuePosition struct {
+	timestampNanos   int64
+	leafIdentityHash []byte
+}
+
 // sortLeavesForInsert returns a slice containing the passed in leaves sorted
 // by LeafIdentityHash, and paired with their original positions.
 // QueueLeaves and AddSequencedLeaves use this to make the order that LeafData
@@ -498,6 +585,7 @@ func (t *logTreeTX) QueueLeaves(ctx context.Context, leaves []*trillian.LogLeaf,
 		// Create the work queue entry
 		args := []interface{}{
 			t.treeID,
+			unseqBucket(leaf.LeafIdentityHash, t.numBuckets),
 			leaf.LeafIdentityHash,
 			leaf.MerkleLeafHash,
 		}
@@ -660,6 +748,17 @@ func (t *logTreeTX) GetSequencedLeafCount(ctx context.Context) (int64, error) {
 }
 
 func (t *logTreeTX) GetLeavesByIndex(ctx context.Context, leaves []int64) ([]*trillian.LogLeaf, error) {
+	ret, err := t.getLeavesByIndexInternal(ctx, leaves)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.markPrunedLeaves(ctx, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (t *logTreeTX) getLeavesByIndexInternal(ctx context.Context, leaves []int64) ([]*trillian.LogLeaf, error) {
 	if t.treeType == trillian.TreeType_LOG {
 		treeSize := int64(t.root.TreeSize)
 		for _, leaf := range leaves {
@@ -726,7 +825,14 @@ func (t *logTreeTX) GetLeavesByIndex(ctx context.Context, leaves []int64) ([]*tr
 func (t *logTreeTX) GetLeavesByRange(ctx context.Context, start, count int64) ([]*trillian.LogLeaf, error) {
 	t.treeTX.mu.Lock()
 	defer t.treeTX.mu.Unlock()
-	return t.getLeavesByRangeInternal(ctx, start, count)
+	ret, err := t.getLeavesByRangeInternal(ctx, start, count)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.markPrunedLeaves(ctx, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
 }
 
 func (t *logTreeTX) getLeavesByRangeInternal(ctx context.Context, start, count int64) ([]*trillian.LogLeaf, error) {
@@ -804,7 +910,14 @@ func (t *logTreeTX) GetLeavesByHash(ctx context.Context, leafHashes [][]byte, or
 		return nil, err
 	}
 
-	return t.getLeavesByHashInternal(ctx, leafHashes, tmpl, "merkle")
+	ret, err := t.getLeavesByHashInternal(ctx, leafHashes, tmpl, "merkle")
+	if err != nil {
+		return nil, err
+	}
+	if err := t.markPrunedLeaves(ctx, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
 }
 
 // getLeafDataByIdentityHash retrieves leaf data by LeafIdentityHash, returned
@@ -818,6 +931,68 @@ func (t *logTreeTX) getLeafDataByIdentityHash(ctx context.Context, leafHashes []
 	return t.getLeavesByHashInternal(ctx, leafHashes, tmpl, "leaf-identity")
 }
 
+// getPrunedSize returns the number of leaves at the start of the tree which
+// have had their data pruned.
+func (t *logTreeTX) getPrunedSize(ctx context.Context) (int64, error) {
+	var prunedSize int64
+	err := t.tx.QueryRowContext(ctx, selectPrunedSizeSQL, t.treeID).Scan(&prunedSize)
+	switch {
+	case err == sql.ErrNoRows:
+		return 0, nil
+	case err != nil:
+		glog.Warningf("Error getting pruned size: %s", err)
+		return 0, err
+	}
+	return prunedSize, nil
+}
+
+// markPrunedLeaves sets the DataPruned flag of the leaves whose data has been
+// pruned, and clears whatever is left of their LeafValue and ExtraData.
+func (t *logTreeTX) markPrunedLeaves(ctx context.Context, leaves []*trillian.LogLeaf) error {
+	if len(leaves) == 0 {
+		return nil
+	}
+	prunedSize, err := t.getPrunedSize(ctx)
+	if err != nil {
+		return err
+	}
+	for _, leaf := range leaves {
+		if leaf.LeafIndex >= 0 && leaf.LeafIndex < prunedSize {
+			leaf.LeafValue, leaf.ExtraData, leaf.DataPruned = nil, nil, true
+		}
+	}
+	return nil
+}
+
+func (t *logTreeTX) PruneLeaves(ctx context.Context, end int64, limit int) (int, error) {
+	t.treeTX.mu.Lock()
+	defer t.treeTX.mu.Unlock()
+
+	if treeSize := int64(t.root.TreeSize); end > treeSize {
+		end = treeSize
+	}
+	start, err := t.getPrunedSize(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if last := start + int64(limit); end > last {
+		end = last
+	}
+	if end <= start {
+		return 0, nil
+	}
+
+	if _, err := t.tx.ExecContext(ctx, pruneLeafDataSQL, end, t.treeID, start, end); err != nil {
+		glog.Warningf("Failed to prune leaf data: %s", err)
+		return 0, err
+	}
+	if _, err := t.tx.ExecContext(ctx, replacePrunedSizeSQL, t.treeID, end); err != nil {
+		glog.Warningf("Failed to store pruned size: %s", err)
+		return 0, err
+	}
+	return int(end - start), nil
+}
+
 func (t *logTreeTX) LatestSignedLogRoot(ctx context.Context) (*trillian.SignedLogRoot, error) {
 	t.treeTX.mu.Lock()
 	defer t.treeTX.mu.Unlock()
@@ -840,14 +1015,51 @@ func (t *logTreeTX) fetchLatestRoot(ctx context.Context) (*trillian.SignedLogRoo
 		// It's possible there are no roots for this tree yet
 		return nil, storage.ErrTreeNeedsInit
 	}
+	return t.signedLogRoot(timestamp, treeSize, treeRevision, rootHash, rootSignatureBytes)
+}
 
-	// Put logRoot back together. Fortunately LogRoot has a deterministic serialization.
+func (t *logTreeTX) GetSignedLogRoots(ctx context.Context, startNanos int64, count int) ([]*trillian.SignedLogRoot, error) {
+	if startNanos < 0 || count <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid range startNanos=%d, count=%d", startNanos, count)
+	}
+	rows, err := t.tx.QueryContext(ctx, selectSignedLogRootsSQL, t.treeID, startNanos, count)
+	if err != nil {
+		glog.Warningf("Failed to get signed log roots: %s", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ret []*trillian.SignedLogRoot
+	for rows.Next() {
+		var timestamp, treeSize, treeRevision int64
+		var rootHash, rootSignatureBytes []byte
+		if err := rows.Scan(&timestamp, &treeSize, &rootHash, &treeRevision, &rootSignatureBytes); err != nil {
+			glog.Warningf("Failed to scan signed log root: %s", err)
+			return nil, err
+		}
+		root, err := t.signedLogRoot(timestamp, treeSize, treeRevision, rootHash, rootSignatureBytes)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, root)
+	}
+	if err := rows.Err(); err != nil {
+		glog.Warningf("Failed to read signed log roots: %s", err)
+		return nil, err
+	}
+	return ret, nil
+}
+
+// signedLogRoot puts a SignedLogRoot back together from the columns of a
+// TreeHead row, in the log root format of the tree.
+func (t *logTreeTX) signedLogRoot(timestamp, treeSize, treeRevision int64, rootHash, rootSignature []byte) (*trillian.SignedLogRoot, error) {
+	// Fortunately LogRoot has a deterministic serialization.
 	logRoot, err := (&types.LogRootV1{
 		RootHash:       rootHash,
 		TimestampNanos: uint64(timestamp),
 		Revision:       uint64(treeRevision),
 		TreeSize:       uint64(treeSize),
-	}).MarshalBinary()
+	}).MarshalForConfig(t.logRootConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -855,7 +1067,7 @@ func (t *logTreeTX) fetchLatestRoot(ctx context.Context) (*trillian.SignedLogRoo
 	return &trillian.SignedLogRoot{
 		KeyHint:          types.SerializeKeyHint(t.treeID),
 		LogRoot:          logRoot,
-		LogRootSignature: rootSignatureBytes,
+		LogRootSignature: rootSignature,
 	}, nil
 }
 
@@ -962,6 +1174,41 @@ func (l byLeafIdentityHashWithPosition) Less(i, j int) bool {
 	return bytes.Compare(l[i].leaf.LeafIdentityHash, l[j].leaf.LeafIdentityHash) == -1
 }
 
+// byQueueTimestampAndLeafIdentityHash allows sorting dequeued leaves, along
+// with their dequeue info, in the order the Unsequenced queries return them.
+type byQueueTimestampAndLeafIdentityHash struct {
+	leaves []*trillian.LogLeaf
+	dq     []dequeuedLeaf
+}
+
+func (l byQueueTimestampAndLeafIdentityHash) Len() int {
+	return len(l.leaves)
+}
+func (l byQueueTimestampAndLeafIdentityHash) Swap(i, j int) {
+	l.leaves[i], l.leaves[j] = l.leaves[j], l.leaves[i]
+	l.dq[i], l.dq[j] = l.dq[j], l.dq[i]
+}
+func (l byQueueTimestampAndLeafIdentityHash) Less(i, j int) bool {
+	ti, tj := l.leaves[i].QueueTimestamp, l.leaves[j].QueueTimestamp
+	if ti.Seconds != tj.Seconds {
+		return ti.Seconds < tj.Seconds
+	}
+	if ti.Nanos != tj.Nanos {
+		return ti.Nanos < tj.Nanos
+	}
+	return bytes.Compare(l.leaves[i].LeafIdentityHash, l.leaves[j].LeafIdentityHash) == -1
+}
+
+// unseqBucket returns the bucket of the Unsequenced queue a leaf is queued in.
+func unseqBucket(leafIdentityHash []byte, numBuckets int) int {
+	if numBuckets <= 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	h.Write(leafIdentityHash)
+	return int(h.Sum32() % uint32(numBuckets))
+}
+
 func isDuplicateErr(err error) bool {
 	switch err := err.(type) {
 	case *mysql.MySQLError:
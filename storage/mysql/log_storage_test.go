@@ -30,6 +30,7 @@ import (
 	"github.com/golang/protobuf/ptypes"
 	"github.com/google/trillian"
 	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/mysql/mysqlpb"
 	"github.com/google/trillian/storage/testonly"
 	"github.com/google/trillian/types"
 	"github.com/kylelemons/godebug/pretty"
@@ -42,7 +43,7 @@ import (
 	_ "github.com/go-sql-driver/mysql"
 )
 
-var allTables = []string{"Unsequenced", "TreeHead", "SequencedLeafData", "LeafData", "Subtree", "TreeControl", "Trees", "MapLeaf", "MapHead"}
+var allTables = []string{"Unsequenced", "TreeHead", "PrunedLeaves", "SequencedLeafData", "LeafData", "Subtree", "TreeControl", "Trees", "MapLeaf", "MapHead"}
 
 // Must be 32 bytes to match sha256 length if it was a real hash
 var dummyHash = []byte("hashxxxxhashxxxxhashxxxxhashxxxx")
@@ -746,6 +747,140 @@ func TestDequeueLeavesTimeOrdering(t *testing.T) {
 	}
 }
 
+func TestDequeueLeavesBuckets(t *testing.T) {
+	ctx := context.Background()
+	cleanTestDB(DB)
+	as := NewAdminStorage(DB)
+	const numBuckets, batchSize, limit = 4, 20, 7
+	settings, err := ptypes.MarshalAny(&mysqlpb.StorageOptions{NumUnseqBuckets: numBuckets})
+	if err != nil {
+		t.Fatalf("MarshalAny(): %v", err)
+	}
+	create := proto.Clone(testonly.LogTree).(*trillian.Tree)
+	create.StorageSettings = settings
+	tree := mustCreateTree(ctx, t, as, create)
+	s := NewLogStorage(DB, nil)
+
+	// The second batch is queued earlier, so it precedes the first one in each
+	// bucket.
+	runLogTX(s, tree, t, func(ctx context.Context, tx storage.LogTreeTX) error {
+		if _, err := tx.QueueLeaves(ctx, createTestLeaves(batchSize, 0), fakeQueueTime); err != nil {
+			t.Fatalf("QueueLeaves(1st batch) = %v", err)
+		}
+		if _, err := tx.QueueLeaves(ctx, createTestLeaves(batchSize, batchSize), fakeQueueTime.Add(-time.Second)); err != nil {
+			t.Fatalf("QueueLeaves(2nd batch) = %v", err)
+		}
+		return nil
+	})
+
+	// The leaves are spread across the buckets by their identity hash.
+	queued := make([]int, numBuckets)
+	for _, leaf := range createTestLeaves(2*batchSize, 0) {
+		queued[unseqBucket(leaf.LeafIdentityHash, numBuckets)]++
+	}
+	rows, err := DB.QueryContext(ctx, "SELECT Bucket, COUNT(*) FROM Unsequenced WHERE TreeId=? GROUP BY Bucket", tree.TreeId)
+	if err != nil {
+		t.Fatalf("Failed to count queued leaves: %v", err)
+	}
+	got := make([]int, numBuckets)
+	for rows.Next() {
+		var bucket, count int
+		if err := rows.Scan(&bucket, &count); err != nil {
+			t.Fatalf("Failed to scan queued leaf count: %v", err)
+		}
+		if bucket < 0 || bucket >= numBuckets {
+			t.Fatalf("Leaves queued in bucket %d, want bucket in range [0, %d)", bucket, numBuckets)
+		}
+		got[bucket] = count
+	}
+	rows.Close()
+	if !reflect.DeepEqual(got, queued) {
+		t.Errorf("Queued leaves per bucket = %v, want %v", got, queued)
+	}
+
+	var all []*trillian.LogLeaf
+	for len(all) < 2*batchSize {
+		runLogTX(s, tree, t, func(ctx context.Context, tx storage.LogTreeTX) error {
+			leaves, err := tx.DequeueLeaves(ctx, limit, fakeDequeueCutoffTime)
+			if err != nil {
+				t.Fatalf("DequeueLeaves() = %v", err)
+			}
+			want := 2*batchSize - len(all)
+			if want > limit {
+				want = limit
+			}
+			if got := len(leaves); got != want {
+				t.Fatalf("DequeueLeaves() returned %d leaves, want %d", got, want)
+			}
+			// The leaves are returned in queue order.
+			for i := 1; i < len(leaves); i++ {
+				prev, _ := ptypes.Timestamp(leaves[i-1].QueueTimestamp)
+				cur, _ := ptypes.Timestamp(leaves[i].QueueTimestamp)
+				if cur.Before(prev) || cur.Equal(prev) && bytes.Compare(leaves[i].LeafIdentityHash, leaves[i-1].LeafIdentityHash) < 0 {
+					t.Errorf("DequeueLeaves() returned leaf %d out of queue order", i)
+				}
+			}
+			// The limit is shared fairly: a bucket only gives fewer leaves than
+			// another one if it ran out of them.
+			taken := make([]int, numBuckets)
+			for _, leaf := range leaves {
+				taken[unseqBucket(leaf.LeafIdentityHash, numBuckets)]++
+			}
+			for a := range taken {
+				for b := range taken {
+					if taken[a] > taken[b]+1 && taken[b] < queued[b] {
+						t.Errorf("DequeueLeaves() took %v leaves per bucket from %v queued, want a fair share", taken, queued)
+					}
+				}
+			}
+			for b := range queued {
+				queued[b] -= taken[b]
+			}
+			all = append(all, leaves...)
+			return nil
+		})
+	}
+	ensureAllLeavesDistinct(all, t)
+
+	runLogTX(s, tree, t, func(ctx context.Context, tx storage.LogTreeTX) error {
+		leaves, err := tx.DequeueLeaves(ctx, limit, fakeDequeueCutoffTime)
+		if err != nil {
+			t.Fatalf("DequeueLeaves() = %v", err)
+		}
+		if len(leaves) != 0 {
+			t.Fatalf("Dequeued %d leaves but expected to get none", len(leaves))
+		}
+		return nil
+	})
+}
+
+func TestUnseqBucket(t *testing.T) {
+	leaves := createTestLeaves(100, 0)
+	for _, numBuckets := range []int{0, 1} {
+		for _, leaf := range leaves {
+			if got := unseqBucket(leaf.LeafIdentityHash, numBuckets); got != 0 {
+				t.Fatalf("unseqBucket(%x, %d) = %d, want 0", leaf.LeafIdentityHash, numBuckets, got)
+			}
+		}
+	}
+
+	const numBuckets = 8
+	used := make(map[int]bool)
+	for _, leaf := range leaves {
+		bucket := unseqBucket(leaf.LeafIdentityHash, numBuckets)
+		if bucket < 0 || bucket >= numBuckets {
+			t.Fatalf("unseqBucket(%x, %d) = %d, want bucket in range [0, %d)", leaf.LeafIdentityHash, numBuckets, bucket, numBuckets)
+		}
+		if again := unseqBucket(leaf.LeafIdentityHash, numBuckets); again != bucket {
+			t.Errorf("unseqBucket(%x, %d) = %d, then %d", leaf.LeafIdentityHash, numBuckets, bucket, again)
+		}
+		used[bucket] = true
+	}
+	if got, want := len(used), numBuckets; got != want {
+		t.Errorf("%d leaves spread over %d buckets, want %d", len(leaves), got, want)
+	}
+}
+
 func TestGetLeavesByHashNotPresent(t *testing.T) {
 	ctx := context.Background()
 	cleanTestDB(DB)
@@ -1055,6 +1190,99 @@ func TestGetLeavesByRangeFromPreorderedLog(t *testing.T) {
 	testGetLeavesByRangeImpl(t, testonly.PreorderedLogTree, tests)
 }
 
+func TestPruneLeaves(t *testing.T) {
+	ctx := context.Background()
+	cleanTestDB(DB)
+	as := NewAdminStorage(DB)
+	tree := mustCreateTree(ctx, t, as, testonly.LogTree)
+	s := NewLogStorage(DB, nil)
+	mustSignAndStoreLogRoot(ctx, t, s, tree, 10)
+
+	// Create leaves [0]..[11], where leaf [9] shares its data with leaf [1]. The
+	// shared data must be kept until leaf [9] is pruned too.
+	for i := int64(0); i < 12; i++ {
+		if i == 9 {
+			continue
+		}
+		data := []byte{byte(i)}
+		identityHash := sha256.Sum256(data)
+		createFakeLeaf(ctx, DB, tree.TreeId, identityHash[:], identityHash[:], data, someExtraData, i, t)
+	}
+	dupHash := sha256.Sum256([]byte{1})
+	if _, err := DB.ExecContext(ctx, "INSERT INTO SequencedLeafData(TreeId, SequenceNumber, LeafIdentityHash, MerkleLeafHash, IntegrateTimestampNanos) VALUES(?,?,?,?,?)",
+		tree.TreeId, 9, dupHash[:], dupHash[:], fakeIntegrateTime.UnixNano()); err != nil {
+		t.Fatalf("Failed to create duplicate leaf: %v", err)
+	}
+
+	// The steps are run in order against the same tree.
+	for _, step := range []struct {
+		end        int64
+		limit      int
+		wantPruned int
+		// wantSize is the number of leaves pruned after the step.
+		wantSize int64
+	}{
+		{end: 0, limit: 10, wantPruned: 0, wantSize: 0},
+		{end: 5, limit: 3, wantPruned: 3, wantSize: 3},
+		{end: 5, limit: 3, wantPruned: 2, wantSize: 5},
+		{end: 5, limit: 3, wantPruned: 0, wantSize: 5},
+		{end: 20, limit: 100, wantPruned: 5, wantSize: 10}, // Clipped to the tree size.
+	} {
+		runLogTX(s, tree, t, func(ctx context.Context, tx storage.LogTreeTX) error {
+			pruned, err := tx.PruneLeaves(ctx, step.end, step.limit)
+			if err != nil {
+				t.Fatalf("PruneLeaves(%d, %d): %v", step.end, step.limit, err)
+			}
+			if got, want := pruned, step.wantPruned; got != want {
+				t.Errorf("PruneLeaves(%d, %d): pruned %d leaves, want %d", step.end, step.limit, got, want)
+			}
+			return nil
+		})
+
+		runLogTX(s, tree, t, func(ctx context.Context, tx storage.LogTreeTX) error {
+			leaves, err := tx.GetLeavesByRange(ctx, 0, 10)
+			if err != nil {
+				t.Fatalf("GetLeavesByRange(): %v", err)
+			}
+			if got, want := len(leaves), 10; got != want {
+				t.Fatalf("GetLeavesByRange(): got %d leaves, want %d", got, want)
+			}
+			for i, leaf := range leaves {
+				wantPruned := int64(i) < step.wantSize
+				if got := leaf.DataPruned; got != wantPruned {
+					t.Errorf("Leaf %d: DataPruned=%v, want %v", i, got, wantPruned)
+				}
+				if got := len(leaf.MerkleLeafHash); got == 0 {
+					t.Errorf("Leaf %d: no MerkleLeafHash", i)
+				}
+				if wantPruned && (leaf.LeafValue != nil || leaf.ExtraData != nil) {
+					t.Errorf("Leaf %d: got data %x, %x for pruned leaf", i, leaf.LeafValue, leaf.ExtraData)
+				}
+				if !wantPruned && len(leaf.LeafValue) == 0 {
+					t.Errorf("Leaf %d: no LeafValue for retained leaf", i)
+				}
+			}
+			return nil
+		})
+	}
+
+	// Leaves beyond the tree size keep their data.
+	runLogTX(s, tree, t, func(ctx context.Context, tx storage.LogTreeTX) error {
+		hash := sha256.Sum256([]byte{10})
+		leaves, err := tx.GetLeavesByHash(ctx, [][]byte{hash[:]}, false)
+		if err != nil {
+			t.Fatalf("GetLeavesByHash(): %v", err)
+		}
+		if got, want := len(leaves), 1; got != want {
+			t.Fatalf("GetLeavesByHash(): got %d leaves, want %d", got, want)
+		}
+		if leaves[0].DataPruned || !bytes.Equal(leaves[0].LeafValue, []byte{10}) {
+			t.Errorf("GetLeavesByHash(): got %+v, want leaf with data", leaves[0])
+		}
+		return nil
+	})
+}
+
 // -----------------------------------------------------------------------------
 
 func TestLatestSignedRootNoneWritten(t *testing.T) {
@@ -1111,6 +1339,59 @@ func TestLatestSignedLogRoot(t *testing.T) {
 	}
 }
 
+func TestLatestSignedLogRootCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	cleanTestDB(DB)
+	as := NewAdminStorage(DB)
+	checkpointTree := proto.Clone(testonly.LogTree).(*trillian.Tree)
+	checkpointTree.LogRootConfig = &trillian.LogRootConfig{
+		Format: trillian.LogRootFormat_LOG_ROOT_FORMAT_CHECKPOINT,
+		Origin: "example.com/log",
+	}
+	tree := mustCreateTree(ctx, t, as, checkpointTree)
+	s := NewLogStorage(DB, nil)
+
+	// The signature of a checkpoint covers the checkpoint text, so the root
+	// read back must be serialized in the same format to verify.
+	signer := tcrypto.NewSigner(tree.TreeId, ttestonly.NewSignerWithFixedSig(nil, []byte("notempty")), crypto.SHA256)
+	signer.LogRootFormat = tree.LogRootConfig.Format
+	signer.CheckpointOrigin = tree.LogRootConfig.Origin
+	root, err := signer.SignLogRoot(&types.LogRootV1{
+		TimestampNanos: 98765,
+		TreeSize:       16,
+		Revision:       5,
+		RootHash:       []byte(dummyHash),
+	})
+	if err != nil {
+		t.Fatalf("SignLogRoot(): %v", err)
+	}
+
+	runLogTX(s, tree, t, func(ctx context.Context, tx storage.LogTreeTX) error {
+		if err := tx.StoreSignedLogRoot(ctx, root); err != nil {
+			t.Fatalf("Failed to store signed root: %v", err)
+		}
+		return nil
+	})
+
+	runLogTX(s, tree, t, func(ctx context.Context, tx storage.LogTreeTX) error {
+		root2, err := tx.LatestSignedLogRoot(ctx)
+		if err != nil {
+			t.Fatalf("Failed to read back new log root: %v", err)
+		}
+		if !proto.Equal(root, root2) {
+			t.Fatalf("Root round trip failed: <%v> and: <%v>", root, root2)
+		}
+		roots, err := tx.(storage.LogRootHistoryReader).GetSignedLogRoots(ctx, 0, 1)
+		if err != nil {
+			t.Fatalf("GetSignedLogRoots(): %v", err)
+		}
+		if len(roots) != 1 || !proto.Equal(root, roots[0]) {
+			t.Fatalf("GetSignedLogRoots(): %v, want [%v]", roots, root)
+		}
+		return nil
+	})
+}
+
 func TestDuplicateSignedLogRoot(t *testing.T) {
 	ctx := context.Background()
 	cleanTestDB(DB)
@@ -1191,6 +1472,65 @@ func TestLogRootUpdate(t *testing.T) {
 	})
 }
 
+func TestGetSignedLogRoots(t *testing.T) {
+	ctx := context.Background()
+	cleanTestDB(DB)
+	as := NewAdminStorage(DB)
+	tree := mustCreateTree(ctx, t, as, testonly.LogTree)
+	s := NewLogStorage(DB, nil)
+
+	signer := tcrypto.NewSigner(tree.TreeId, ttestonly.NewSignerWithFixedSig(nil, []byte("notempty")), crypto.SHA256)
+	var roots []*trillian.SignedLogRoot
+	for i := 0; i < 5; i++ {
+		root, err := signer.SignLogRoot(&types.LogRootV1{
+			TimestampNanos: uint64(1000 + 10*i),
+			TreeSize:       uint64(16 + i),
+			Revision:       uint64(i),
+			RootHash:       []byte(dummyHash),
+		})
+		if err != nil {
+			t.Fatalf("SignLogRoot(): %v", err)
+		}
+		roots = append(roots, root)
+	}
+	runLogTX(s, tree, t, func(ctx context.Context, tx storage.LogTreeTX) error {
+		for _, root := range roots {
+			if err := tx.StoreSignedLogRoot(ctx, root); err != nil {
+				t.Fatalf("Failed to store signed root: %v", err)
+			}
+		}
+		return nil
+	})
+
+	for _, test := range []struct {
+		start int64
+		count int
+		want  []*trillian.SignedLogRoot
+	}{
+		{start: 0, count: 10, want: roots},
+		{start: 1000, count: 2, want: roots[:2]},
+		{start: 1015, count: 2, want: roots[2:4]},
+		{start: 1040, count: 10, want: roots[4:]},
+		{start: 1041, count: 10, want: nil},
+	} {
+		runLogTX(s, tree, t, func(ctx context.Context, tx storage.LogTreeTX) error {
+			got, err := tx.(storage.LogRootHistoryReader).GetSignedLogRoots(ctx, test.start, test.count)
+			if err != nil {
+				t.Fatalf("GetSignedLogRoots(%d, %d): %v", test.start, test.count, err)
+			}
+			if len(got) != len(test.want) {
+				t.Fatalf("GetSignedLogRoots(%d, %d): got %d roots, want %d", test.start, test.count, len(got), len(test.want))
+			}
+			for i := range got {
+				if !proto.Equal(got[i], test.want[i]) {
+					t.Errorf("GetSignedLogRoots(%d, %d)[%d]: got %v, want %v", test.start, test.count, i, got[i], test.want[i])
+				}
+			}
+			return nil
+		})
+	}
+}
+
 func TestGetActiveLogIDs(t *testing.T) {
 	ctx := context.Background()
 
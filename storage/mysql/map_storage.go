@@ -37,7 +37,32 @@ const (
 		 ORDER BY MapHeadTimestamp DESC LIMIT 1`
 	selectGetSignedMapRootSQL = `SELECT MapHeadTimestamp, RootHash, MapRevision, RootSignature, MapperData
 		 FROM MapHead WHERE TreeId=? AND MapRevision=?`
-	insertMapLeafSQL = `INSERT INTO MapLeaf(TreeId, KeyHash, MapRevision, LeafValue) VALUES (?, ?, ?, ?)`
+	insertMapLeafSQL     = `INSERT INTO MapLeaf(TreeId, KeyHash, MapRevision, LeafValue) VALUES (?, ?, ?, ?)`
+	selectChangedKeysSQL = `SELECT DISTINCT KeyHash FROM MapLeaf
+		 WHERE TreeId=? AND KeyHash>=? AND MapRevision>? AND MapRevision<=?
+		 ORDER BY KeyHash LIMIT ?`
+	selectLeafRevisionsSQL = `SELECT MapRevision FROM MapLeaf
+		 WHERE TreeId=? AND KeyHash=? AND MapRevision>? AND MapRevision<=?
+		 ORDER BY MapRevision LIMIT ?`
+	selectEarliestMapRevisionSQL = `SELECT MIN(MapRevision) FROM MapHead WHERE TreeId=?`
+	deleteMapHeadsSQL            = `DELETE FROM MapHead WHERE TreeId=? AND MapRevision<? LIMIT ?`
+
+	// The superseded versions of leaves and subtrees below a revision are those
+	// with a later version at or below it.
+	selectSupersededMapLeavesSQL = `SELECT t0.KeyHash, t0.MapRevision FROM MapLeaf t0
+		 WHERE t0.TreeId=? AND t0.MapRevision<? AND EXISTS(
+			SELECT 1 FROM MapLeaf t1
+			WHERE t1.TreeId=t0.TreeId AND t1.KeyHash=t0.KeyHash
+			AND t1.MapRevision>t0.MapRevision AND t1.MapRevision<=?)
+		 LIMIT ?`
+	deleteMapLeafSQL            = `DELETE FROM MapLeaf WHERE TreeId=? AND KeyHash=? AND MapRevision=?`
+	selectSupersededSubtreesSQL = `SELECT t0.SubtreeId, t0.SubtreeRevision FROM Subtree t0
+		 WHERE t0.TreeId=? AND t0.SubtreeRevision<? AND EXISTS(
+			SELECT 1 FROM Subtree t1
+			WHERE t1.TreeId=t0.TreeId AND t1.SubtreeId=t0.SubtreeId
+			AND t1.SubtreeRevision>t0.SubtreeRevision AND t1.SubtreeRevision<=?)
+		 LIMIT ?`
+	deleteSubtreeSQL = `DELETE FROM Subtree WHERE TreeId=? AND SubtreeId=? AND SubtreeRevision=?`
 )
 
 var defaultMapStrata = []int{8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 176}
@@ -232,6 +257,138 @@ func (m *mapTreeTX) Get(ctx context.Context, revision int64, indexes [][]byte) (
 	return ret, nil
 }
 
+// ListLeaves returns up to limit map leaves, ordered by index, whose index is
+// in the range [start, end), or [start, ∞) if end is nil.
+func (m *mapTreeTX) ListLeaves(ctx context.Context, revision int64, start, end []byte, limit int) ([]*trillian.MapLeaf, error) {
+	m.treeTX.mu.Lock()
+	defer m.treeTX.mu.Unlock()
+
+	if limit <= 0 {
+		return []*trillian.MapLeaf{}, nil
+	}
+	if start == nil {
+		start = []byte{}
+	}
+	args := []interface{}{m.treeID, revision, start}
+	endSQL := ""
+	if end != nil {
+		endSQL = "AND t0.KeyHash < ?"
+		args = append(args, end)
+	}
+	args = append(args, limit)
+	selectMapLeavesSQL := `
+ SELECT t1.KeyHash, t1.LeafValue
+ FROM MapLeaf t1
+ INNER JOIN
+ (
+	SELECT TreeId, KeyHash, MAX(MapRevision) as maxrev
+	FROM MapLeaf t0
+	WHERE t0.TreeId = ? AND t0.MapRevision <= ? AND
+	      t0.KeyHash >= ? ` + endSQL + `
+	GROUP BY t0.TreeId, t0.KeyHash
+	ORDER BY t0.KeyHash
+	LIMIT ?
+ ) t2
+ ON t1.TreeId=t2.TreeId
+ AND t1.KeyHash=t2.KeyHash
+ AND t1.MapRevision=t2.maxrev
+ ORDER BY t1.KeyHash`
+
+	stmt, err := m.tx.PrepareContext(ctx, selectMapLeavesSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret := make([]*trillian.MapLeaf, 0, limit)
+	for rows.Next() {
+		var mapKeyHash, flatData []byte
+		if err := rows.Scan(&mapKeyHash, &flatData); err != nil {
+			return nil, err
+		}
+		mapLeaf, err := unmarshalMapLeaf(flatData, mapKeyHash)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, mapLeaf)
+	}
+	return ret, rows.Err()
+}
+
+// ListChangedKeys returns up to limit key hashes >= start, ordered, of the map
+// leaves which were set in the revisions (fromRevision, toRevision].
+func (m *mapTreeTX) ListChangedKeys(ctx context.Context, fromRevision, toRevision int64, start []byte, limit int) ([][]byte, error) {
+	m.treeTX.mu.Lock()
+	defer m.treeTX.mu.Unlock()
+
+	if limit <= 0 {
+		return [][]byte{}, nil
+	}
+	if start == nil {
+		start = []byte{}
+	}
+	stmt, err := m.tx.PrepareContext(ctx, selectChangedKeysSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, m.treeID, start, fromRevision, toRevision, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret := make([][]byte, 0, limit)
+	for rows.Next() {
+		var keyHash []byte
+		if err := rows.Scan(&keyHash); err != nil {
+			return nil, err
+		}
+		ret = append(ret, keyHash)
+	}
+	return ret, rows.Err()
+}
+
+// ListLeafRevisions returns up to limit revisions, ordered, in the range
+// (fromRevision, toRevision] at which the map leaf with the given key hash was
+// set.
+func (m *mapTreeTX) ListLeafRevisions(ctx context.Context, keyHash []byte, fromRevision, toRevision int64, limit int) ([]int64, error) {
+	m.treeTX.mu.Lock()
+	defer m.treeTX.mu.Unlock()
+
+	if limit <= 0 {
+		return []int64{}, nil
+	}
+	stmt, err := m.tx.PrepareContext(ctx, selectLeafRevisionsSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, m.treeID, keyHash, fromRevision, toRevision, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret := make([]int64, 0, limit)
+	for rows.Next() {
+		var rev int64
+		if err := rows.Scan(&rev); err != nil {
+			return nil, err
+		}
+		ret = append(ret, rev)
+	}
+	return ret, rows.Err()
+}
+
 func unmarshalMapLeaf(marshaledLeaf, mapKeyHash []byte) (*trillian.MapLeaf, error) {
 	if len(marshaledLeaf) == 0 {
 		return nil, errors.New("len(marshaledLeaf): 0 want > 0")
@@ -260,6 +417,11 @@ func (m *mapTreeTX) GetSignedMapRoot(ctx context.Context, revision int64) (*tril
 
 	err = stmt.QueryRowContext(ctx, m.treeID, revision).Scan(
 		&timestamp, &rootHash, &mapRevision, &rootSignatureBytes, &mapperMetaBytes)
+	if err == sql.ErrNoRows {
+		if err := m.checkCollected(ctx, revision); err != nil {
+			return nil, err
+		}
+	}
 	if err != nil {
 		if revision == 0 {
 			return nil, storage.ErrTreeNeedsInit
@@ -270,6 +432,19 @@ func (m *mapTreeTX) GetSignedMapRoot(ctx context.Context, revision int64) (*tril
 	return m.signedMapRoot(timestamp, mapRevision, rootHash, rootSignatureBytes, mapperMetaBytes)
 }
 
+// checkCollected returns an error with codes.OutOfRange if revision is below
+// the earliest revision of the map, i.e. it has been garbage collected.
+func (m *mapTreeTX) checkCollected(ctx context.Context, revision int64) error {
+	var earliest sql.NullInt64
+	if err := m.tx.QueryRowContext(ctx, selectEarliestMapRevisionSQL, m.treeID).Scan(&earliest); err != nil {
+		return err
+	}
+	if earliest.Valid && revision < earliest.Int64 {
+		return storage.RevisionCollectedError(revision, earliest.Int64)
+	}
+	return nil
+}
+
 func (m *mapTreeTX) LatestSignedMapRoot(ctx context.Context) (*trillian.SignedMapRoot, error) {
 	m.treeTX.mu.Lock()
 	defer m.treeTX.mu.Unlock()
@@ -338,3 +513,79 @@ func (m *mapTreeTX) StoreSignedMapRoot(ctx context.Context, root *trillian.Signe
 
 	return checkResultOkAndRowCountIs(res, err, 1)
 }
+
+// CollectRevisions deletes at most limit records of the map revisions below
+// end, starting with their roots, and returns the number of records deleted.
+func (m *mapTreeTX) CollectRevisions(ctx context.Context, end int64, limit int) (int, error) {
+	m.treeTX.mu.Lock()
+	defer m.treeTX.mu.Unlock()
+
+	if limit <= 0 {
+		return 0, nil
+	}
+	res, err := m.tx.ExecContext(ctx, deleteMapHeadsSQL, m.treeID, end, limit)
+	if err != nil {
+		glog.Warningf("Failed to delete map roots: %s", err)
+		return 0, err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	collected := int(rows)
+
+	for _, sqls := range []struct{ selectSQL, deleteSQL string }{
+		{selectSupersededMapLeavesSQL, deleteMapLeafSQL},
+		{selectSupersededSubtreesSQL, deleteSubtreeSQL},
+	} {
+		if collected >= limit {
+			break
+		}
+		n, err := m.deleteSuperseded(ctx, sqls.selectSQL, sqls.deleteSQL, end, limit-collected)
+		collected += n
+		if err != nil {
+			return collected, err
+		}
+	}
+	return collected, nil
+}
+
+// deleteSuperseded deletes at most limit of the (ID, revision) rows selected
+// by selectSQL as superseded at revision end, using deleteSQL.
+func (m *mapTreeTX) deleteSuperseded(ctx context.Context, selectSQL, deleteSQL string, end int64, limit int) (int, error) {
+	type version struct {
+		id       []byte
+		revision int64
+	}
+	rows, err := m.tx.QueryContext(ctx, selectSQL, m.treeID, end, end, limit)
+	if err != nil {
+		return 0, err
+	}
+	var versions []version
+	for rows.Next() {
+		var v version
+		if err := rows.Scan(&v.id, &v.revision); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		versions = append(versions, v)
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil || len(versions) == 0 {
+		return 0, err
+	}
+
+	stmt, err := m.tx.PrepareContext(ctx, deleteSQL)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+	for i, v := range versions {
+		if _, err := stmt.ExecContext(ctx, m.treeID, v.id, v.revision); err != nil {
+			glog.Warningf("Failed to delete superseded version: %s", err)
+			return i, err
+		}
+	}
+	return len(versions), nil
+}
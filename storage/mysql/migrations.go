@@ -0,0 +1,115 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/trillian/storage/migrate"
+)
+
+// SchemaVersion is the version of the schema in schema/storage.sql, which is
+// the minimum version required by this package.
+const SchemaVersion = 7
+
+var dialect = migrate.Dialect{
+	TableExistsSQL: "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?",
+	VersionTable:   "SchemaVersion",
+	TreesTable:     "Trees",
+	CreateVersionTableSQL: `CREATE TABLE IF NOT EXISTS SchemaVersion(
+  Version              INTEGER NOT NULL,
+  Description          VARCHAR(200) NOT NULL,
+  AppliedTimeMillis    BIGINT NOT NULL,
+  PRIMARY KEY(Version)
+)`,
+	SelectVersionSQL: "SELECT MAX(Version) FROM SchemaVersion",
+	InsertVersionSQL: "INSERT INTO SchemaVersion(Version, Description, AppliedTimeMillis) VALUES(?, ?, ?)",
+}
+
+// migrations takes a schema from migrate.BaselineVersion to SchemaVersion.
+// Schema changes must be made both here and in schema/storage.sql.
+var migrations = []migrate.Migration{
+	{
+		Version:     2,
+		Description: "Add Trees.SequencingConfig",
+		Statements: []string{
+			"ALTER TABLE Trees ADD COLUMN SequencingConfig MEDIUMBLOB",
+		},
+	},
+	{
+		Version:     3,
+		Description: "Add Trees.RetentionPolicy and the PrunedLeaves table",
+		Statements: []string{
+			"ALTER TABLE Trees ADD COLUMN RetentionPolicy MEDIUMBLOB",
+			`CREATE TABLE IF NOT EXISTS PrunedLeaves(
+  TreeId               BIGINT NOT NULL,
+  PrunedSize           BIGINT NOT NULL,
+  PRIMARY KEY(TreeId),
+  FOREIGN KEY(TreeId) REFERENCES Trees(TreeId) ON DELETE CASCADE
+)`,
+		},
+	},
+	{
+		Version:     4,
+		Description: "Add Trees.StorageSettings",
+		Statements: []string{
+			"ALTER TABLE Trees ADD COLUMN StorageSettings MEDIUMBLOB",
+		},
+	},
+	{
+		Version:     5,
+		Description: "Add the ElectionLease table",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS ElectionLease(
+  ResourceId           VARCHAR(255) NOT NULL,
+  LeaderId             VARCHAR(255) NOT NULL,
+  Term                 BIGINT NOT NULL,
+  ExpiryTimeMillis     BIGINT NOT NULL,
+  PRIMARY KEY(ResourceId)
+)`,
+		},
+	},
+	{
+		Version:     6,
+		Description: "Add Trees.LogRootConfig",
+		Statements: []string{
+			"ALTER TABLE Trees ADD COLUMN LogRootConfig MEDIUMBLOB",
+		},
+	},
+	{
+		Version:     7,
+		Description: "Add Trees.MapRetentionPolicy",
+		Statements: []string{
+			"ALTER TABLE Trees ADD COLUMN MapRetentionPolicy MEDIUMBLOB",
+		},
+	},
+}
+
+// NewMigrator returns a migrate.Migrator which brings the schema of db up to
+// SchemaVersion.
+func NewMigrator(db *sql.DB) (*migrate.Migrator, error) {
+	return migrate.New(db, dialect, migrations)
+}
+
+// CheckSchemaVersion returns an error if the schema of db is older than
+// SchemaVersion.
+func CheckSchemaVersion(ctx context.Context, db *sql.DB) error {
+	m, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	return m.Check(ctx, SchemaVersion)
+}
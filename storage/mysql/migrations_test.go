@@ -0,0 +1,74 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/trillian/storage/migrate"
+	"github.com/google/trillian/storage/testdb"
+)
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	db, done, err := testdb.NewTrillianDB(ctx)
+	if err != nil {
+		t.Fatalf("NewTrillianDB(): %v", err)
+	}
+	defer done(ctx)
+
+	m, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("NewMigrator(): %v", err)
+	}
+	if got, err := m.Version(ctx); err != nil || got != SchemaVersion {
+		t.Errorf("Version() = (%d, %v), want %d", got, err, SchemaVersion)
+	}
+	if err := CheckSchemaVersion(ctx, db); err != nil {
+		t.Errorf("CheckSchemaVersion(): %v", err)
+	}
+
+	// Without the version table the schema is assumed to predate versioning.
+	if _, err := db.ExecContext(ctx, "DROP TABLE SchemaVersion"); err != nil {
+		t.Fatalf("DROP TABLE: %v", err)
+	}
+	if got, err := m.Version(ctx); err != nil || got != migrate.BaselineVersion {
+		t.Errorf("Version() = (%d, %v), want %d", got, err, migrate.BaselineVersion)
+	}
+	if err := CheckSchemaVersion(ctx, db); err == nil {
+		t.Error("CheckSchemaVersion(): nil, want error for an old schema")
+	}
+
+	// A dry run prints all migrations but leaves the database alone.
+	var out bytes.Buffer
+	applied, err := m.Up(ctx, true /* dryRun */, &out)
+	if err != nil {
+		t.Fatalf("Up(dryRun): %v", err)
+	}
+	if got, want := len(applied), SchemaVersion-migrate.BaselineVersion; got != want {
+		t.Errorf("Up(dryRun) returned %d migrations, want %d", got, want)
+	}
+	for _, want := range []string{"CREATE TABLE IF NOT EXISTS SchemaVersion", "ALTER TABLE Trees ADD COLUMN RetentionPolicy"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Up(dryRun) printed %q, want substring %q", out.String(), want)
+		}
+	}
+	if got, err := m.Version(ctx); err != nil || got != migrate.BaselineVersion {
+		t.Errorf("Version() after dry run = (%d, %v), want %d", got, err, migrate.BaselineVersion)
+	}
+}
@@ -33,24 +33,26 @@ const (
 	selectQueuedLeavesSQL = `SELECT LeafIdentityHash,MerkleLeafHash,QueueTimestampNanos
 			FROM Unsequenced
 			WHERE TreeID=?
-			AND Bucket=0
+			AND Bucket=?
 			AND QueueTimestampNanos<=?
+			AND (QueueTimestampNanos,LeafIdentityHash)>(?,?)
 			ORDER BY QueueTimestampNanos,LeafIdentityHash ASC LIMIT ?`
 	insertUnsequencedEntrySQL = `INSERT INTO Unsequenced(TreeId,Bucket,LeafIdentityHash,MerkleLeafHash,QueueTimestampNanos)
-			VALUES(?,0,?,?,?)`
-	deleteUnsequencedSQL = "DELETE FROM Unsequenced WHERE TreeId=? AND Bucket=0 AND QueueTimestampNanos=? AND LeafIdentityHash=?"
+			VALUES(?,?,?,?,?)`
+	deleteUnsequencedSQL = "DELETE FROM Unsequenced WHERE TreeId=? AND Bucket=? AND QueueTimestampNanos=? AND LeafIdentityHash=?"
 )
 
 type dequeuedLeaf struct {
+	bucket              int
 	queueTimestampNanos int64
 	leafIdentityHash    []byte
 }
 
-func dequeueInfo(leafIDHash []byte, queueTimestamp int64) dequeuedLeaf {
-	return dequeuedLeaf{queueTimestampNanos: queueTimestamp, leafIdentityHash: leafIDHash}
+func dequeueInfo(bucket int, leafIDHash []byte, queueTimestamp int64) dequeuedLeaf {
+	return dequeuedLeaf{bucket: bucket, queueTimestampNanos: queueTimestamp, leafIdentityHash: leafIDHash}
 }
 
-func (t *logTreeTX) dequeueLeaf(rows *sql.Rows) (*trillian.LogLeaf, dequeuedLeaf, error) {
+func (t *logTreeTX) dequeueLeaf(rows *sql.Rows, bucket int) (*trillian.LogLeaf, dequeuedLeaf, error) {
 	var leafIDHash []byte
 	var merkleHash []byte
 	var queueTimestamp int64
@@ -73,7 +75,7 @@ func (t *logTreeTX) dequeueLeaf(rows *sql.Rows) (*trillian.LogLeaf, dequeuedLeaf
 		MerkleLeafHash:   merkleHash,
 		QueueTimestamp:   queueTimestampProto,
 	}
-	return leaf, dequeueInfo(leafIDHash, queueTimestamp), nil
+	return leaf, dequeueInfo(bucket, leafIDHash, queueTimestamp), nil
 }
 
 func queueArgs(_ int64, _ []byte, queueTimestamp time.Time) []interface{} {
@@ -111,8 +113,8 @@ func (t *logTreeTX) UpdateSequencedLeaves(ctx context.Context, leaves []*trillia
 // removeSequencedLeaves removes the passed in leaves slice (which may be
 // modified as part of the operation).
 func (t *logTreeTX) removeSequencedLeaves(ctx context.Context, leaves []dequeuedLeaf) error {
-	// Don't need to re-sort because the query ordered by leaf hash. If that changes because
-	// the query is expensive then the sort will need to be done here. See comment in
+	// Don't need to re-sort because DequeueLeaves ordered them by leaf hash. If that changes
+	// because the sort is expensive then the sort will need to be done here. See comment in
 	// QueueLeaves.
 	stx, err := t.tx.PrepareContext(ctx, deleteUnsequencedSQL)
 	if err != nil {
@@ -121,7 +123,7 @@ func (t *logTreeTX) removeSequencedLeaves(ctx context.Context, leaves []dequeued
 	}
 	defer stx.Close()
 	for _, dql := range leaves {
-		result, err := stx.ExecContext(ctx, t.treeID, dql.queueTimestampNanos, dql.leafIdentityHash)
+		result, err := stx.ExecContext(ctx, t.treeID, dql.bucket, dql.queueTimestampNanos, dql.leafIdentityHash)
 		err = checkResultOkAndRowCountIs(result, err, int64(1))
 		if err != nil {
 			return err
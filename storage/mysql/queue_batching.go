@@ -35,10 +35,11 @@ const (
 	selectQueuedLeavesSQL = `SELECT LeafIdentityHash,MerkleLeafHash,QueueTimestampNanos,QueueID
 			FROM Unsequenced
 			WHERE TreeID=?
-			AND Bucket=0
+			AND Bucket=?
 			AND QueueTimestampNanos<=?
+			AND (QueueTimestampNanos,LeafIdentityHash)>(?,?)
 			ORDER BY QueueTimestampNanos,LeafIdentityHash ASC LIMIT ?`
-	insertUnsequencedEntrySQL = `INSERT INTO Unsequenced(TreeId,Bucket,LeafIdentityHash,MerkleLeafHash,QueueTimestampNanos,QueueID) VALUES(?,0,?,?,?,?)`
+	insertUnsequencedEntrySQL = `INSERT INTO Unsequenced(TreeId,Bucket,LeafIdentityHash,MerkleLeafHash,QueueTimestampNanos,QueueID) VALUES(?,?,?,?,?,?)`
 	deleteUnsequencedSQL      = "DELETE FROM Unsequenced WHERE QueueID IN (<placeholder>)"
 )
 
@@ -48,7 +49,7 @@ func dequeueInfo(_ []byte, queueID []byte) dequeuedLeaf {
 	return dequeuedLeaf(queueID)
 }
 
-func (t *logTreeTX) dequeueLeaf(rows *sql.Rows) (*trillian.LogLeaf, dequeuedLeaf, error) {
+func (t *logTreeTX) dequeueLeaf(rows *sql.Rows, _ int) (*trillian.LogLeaf, dequeuedLeaf, error) {
 	var leafIDHash []byte
 	var merkleHash []byte
 	var queueTimestamp int64
@@ -117,8 +118,8 @@ func (m *mySQLLogStorage) getDeleteUnsequencedStmt(ctx context.Context, num int)
 // removeSequencedLeaves removes the passed in leaves slice (which may be
 // modified as part of the operation).
 func (t *logTreeTX) removeSequencedLeaves(ctx context.Context, queueIDs []dequeuedLeaf) error {
-	// Don't need to re-sort because the query ordered by leaf hash. If that changes because
-	// the query is expensive then the sort will need to be done here. See comment in
+	// Don't need to re-sort because DequeueLeaves ordered them by leaf hash. If that changes
+	// because the sort is expensive then the sort will need to be done here. See comment in
 	// QueueLeaves.
 	tmpl, err := t.ls.getDeleteUnsequencedStmt(ctx, len(queueIDs))
 	if err != nil {
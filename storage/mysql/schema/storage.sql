@@ -1,5 +1,27 @@
 # MySQL / MariaDB version of the tree schema
 
+-- ---------------------------------------------
+-- Schema version here
+-- ---------------------------------------------
+
+-- Each row records a schema version applied to the database, see the
+-- storage/migrate package. Databases created from this file start at the
+-- version below, later versions are applied by trillian_migrate. The version
+-- is only recorded if the Trees table doesn't exist yet, so that running this
+-- file again on an older database doesn't mark it as up to date; such
+-- databases must be brought up to date with trillian_migrate.
+CREATE TABLE IF NOT EXISTS SchemaVersion(
+  Version              INTEGER NOT NULL,
+  Description          VARCHAR(200) NOT NULL,
+  AppliedTimeMillis    BIGINT NOT NULL,
+  PRIMARY KEY(Version)
+);
+
+INSERT IGNORE INTO SchemaVersion(Version, Description, AppliedTimeMillis)
+  SELECT 7, 'Initial schema', FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000) FROM DUAL
+  WHERE NOT EXISTS (SELECT * FROM information_schema.tables
+    WHERE table_schema = DATABASE() AND table_name = 'Trees');
+
 -- ---------------------------------------------
 -- Tree stuff here
 -- ---------------------------------------------
@@ -22,6 +44,11 @@ CREATE TABLE IF NOT EXISTS Trees(
   PublicKey             MEDIUMBLOB NOT NULL,
   Deleted               BOOLEAN,
   DeleteTimeMillis      BIGINT,
+  SequencingConfig      MEDIUMBLOB,
+  RetentionPolicy       MEDIUMBLOB,
+  StorageSettings       MEDIUMBLOB,
+  LogRootConfig         MEDIUMBLOB,
+  MapRetentionPolicy    MEDIUMBLOB,
   PRIMARY KEY(TreeId)
 );
 
@@ -117,10 +144,20 @@ CREATE TABLE IF NOT EXISTS SequencedLeafData(
 CREATE INDEX SequencedLeafMerkleIdx
   ON SequencedLeafData(TreeId, MerkleLeafHash);
 
+-- Leaves with a SequenceNumber below PrunedSize have had their LeafValue and
+-- ExtraData dropped according to the tree's retention policy. The hashes in
+-- SequencedLeafData are kept, so that proofs can still be served.
+CREATE TABLE IF NOT EXISTS PrunedLeaves(
+  TreeId               BIGINT NOT NULL,
+  PrunedSize           BIGINT NOT NULL,
+  PRIMARY KEY(TreeId),
+  FOREIGN KEY(TreeId) REFERENCES Trees(TreeId) ON DELETE CASCADE
+);
+
 CREATE TABLE IF NOT EXISTS Unsequenced(
   TreeId               BIGINT NOT NULL,
-  -- The bucket field is to allow the use of time based ring bucketed schemes if desired. If
-  -- unused this should be set to zero for all entries.
+  -- The bucket the leaf is queued in, picked from its LeafIdentityHash. Logs
+  -- have a single bucket, zero, unless their storage settings ask for more.
   Bucket               INTEGER NOT NULL,
   -- This is a personality specific hash of some subset of the leaf data.
   -- It's only purpose is to allow Trillian to identify duplicate entries in
@@ -167,3 +204,12 @@ CREATE TABLE IF NOT EXISTS MapHead(
 
 CREATE UNIQUE INDEX MapHeadRevisionIdx
   ON MapHead(TreeId, MapRevision);
+
+-- Master election leases, see util/election2/sql.
+CREATE TABLE IF NOT EXISTS ElectionLease(
+  ResourceId           VARCHAR(255) NOT NULL,
+  LeaderId             VARCHAR(255) NOT NULL,
+  Term                 BIGINT NOT NULL,
+  ExpiryTimeMillis     BIGINT NOT NULL,
+  PRIMARY KEY(ResourceId)
+);
@@ -49,7 +49,12 @@ const (
 		public_key,
 		max_root_duration_millis,
 		deleted,
-		delete_time_millis
+		delete_time_millis,
+		sequencing_config,
+		retention_policy,
+		storage_settings,
+		log_root_config,
+		map_retention_policy
 	FROM trees`
 
 	nonDeletedWhere       = " WHERE deleted = false"
@@ -73,8 +78,13 @@ const (
 		update_time_millis,
 		private_key,
 		public_key,
-		max_root_duration_millis)
-	VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+		max_root_duration_millis,
+		sequencing_config,
+		retention_policy,
+		storage_settings,
+		log_root_config,
+		map_retention_policy)
+	VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)`
 
 	insertTreeControlSQL = `INSERT INTO tree_control(
 		tree_id,
@@ -84,8 +94,9 @@ const (
 	VALUES($1, $2, $3, $4)`
 
 	updateTreeSQL = `UPDATE trees SET tree_state = $1, tree_type = $2, display_name = $3, 
-		description = $4, update_time_millis = $5, max_root_duration_millis = $6, private_key = $7
-		WHERE tree_id = $8`
+		description = $4, update_time_millis = $5, max_root_duration_millis = $6, private_key = $7,
+		sequencing_config = $8, retention_policy = $9, storage_settings = $10, map_retention_policy = $11
+		WHERE tree_id = $12`
 
 	softDeleteSQL = "UPDATE trees SET deleted = $1, delete_time_millis = $2 WHERE tree_id = $3"
 
@@ -94,8 +105,24 @@ const (
 	deleteFromTreeControlSQL = "DELETE FROM tree_control WHERE tree_id = $1"
 
 	deleteFromTreesSQL = "DELETE FROM trees WHERE tree_id = $1"
+
+	deleteTreeDataSQL = "DELETE FROM %s WHERE tree_id = $1"
+	// Postgres has no DELETE ... LIMIT, so rows are picked by their ctid.
+	purgeTreeDataSQL = "DELETE FROM %[1]s WHERE ctid = ANY(ARRAY(SELECT ctid FROM %[1]s WHERE tree_id = $1 LIMIT $2))"
 )
 
+// treeDataTables lists the tables holding the data of a tree, in the order
+// they're purged in. Referencing tables come before the tables they reference.
+var treeDataTables = []string{
+	"unsequenced",
+	"sequenced_leaf_data",
+	"leaf_data",
+	"subtree",
+	"tree_head",
+	"map_leaf",
+	"map_head",
+}
+
 // NewAdminStorage returns a storage.AdminStorage implementation
 func NewAdminStorage(db *sql.DB) storage.AdminStorage {
 	return &pgAdminStorage{db}
@@ -310,6 +337,26 @@ func (t *adminTX) CreateTree(ctx context.Context, tree *trillian.Tree) (*trillia
 	if err != nil {
 		return nil, fmt.Errorf("could not marshal PrivateKey: %v", err)
 	}
+	sequencingConfig, err := storage.MarshalSequencingConfig(newTree)
+	if err != nil {
+		return nil, err
+	}
+	retentionPolicy, err := storage.MarshalRetentionPolicy(newTree)
+	if err != nil {
+		return nil, err
+	}
+	storageSettings, err := storage.MarshalStorageSettings(newTree)
+	if err != nil {
+		return nil, err
+	}
+	logRootConfig, err := storage.MarshalLogRootConfig(newTree)
+	if err != nil {
+		return nil, err
+	}
+	mapRetentionPolicy, err := storage.MarshalMapRetentionPolicy(newTree)
+	if err != nil {
+		return nil, err
+	}
 
 	_, err = insertTreeStmt.ExecContext(
 		ctx,
@@ -326,6 +373,11 @@ func (t *adminTX) CreateTree(ctx context.Context, tree *trillian.Tree) (*trillia
 		privateKey,
 		newTree.PublicKey.GetDer(),
 		rootDuration/time.Millisecond,
+		sequencingConfig,
+		retentionPolicy,
+		storageSettings,
+		logRootConfig,
+		mapRetentionPolicy,
 	)
 	if err != nil {
 		return nil, err
@@ -381,6 +433,22 @@ func (t *adminTX) UpdateTree(ctx context.Context, treeID int64, updateFunc func(
 	if err != nil {
 		return nil, fmt.Errorf("could not marshal PrivateKey: %v", err)
 	}
+	sequencingConfig, err := storage.MarshalSequencingConfig(tree)
+	if err != nil {
+		return nil, err
+	}
+	retentionPolicy, err := storage.MarshalRetentionPolicy(tree)
+	if err != nil {
+		return nil, err
+	}
+	storageSettings, err := storage.MarshalStorageSettings(tree)
+	if err != nil {
+		return nil, err
+	}
+	mapRetentionPolicy, err := storage.MarshalMapRetentionPolicy(tree)
+	if err != nil {
+		return nil, err
+	}
 
 	stmt, err := t.tx.PrepareContext(ctx, updateTreeSQL)
 	if err != nil {
@@ -397,6 +465,10 @@ func (t *adminTX) UpdateTree(ctx context.Context, treeID int64, updateFunc func(
 		nowMillis,
 		rootDuration/time.Millisecond,
 		privateKey,
+		sequencingConfig,
+		retentionPolicy,
+		storageSettings,
+		mapRetentionPolicy,
 		tree.TreeId); err != nil {
 		return nil, err
 	}
@@ -417,6 +489,11 @@ func (t *adminTX) HardDeleteTree(ctx context.Context, treeID int64) error {
 		return err
 	}
 
+	for _, table := range treeDataTables {
+		if _, err := t.tx.ExecContext(ctx, fmt.Sprintf(deleteTreeDataSQL, table), treeID); err != nil {
+			return err
+		}
+	}
 	if _, err := t.tx.ExecContext(ctx, deleteFromTreeControlSQL, treeID); err != nil {
 		return err
 	}
@@ -424,6 +501,29 @@ func (t *adminTX) HardDeleteTree(ctx context.Context, treeID int64) error {
 	return err
 }
 
+func (t *adminTX) PurgeTreeData(ctx context.Context, treeID int64, limit int) (int, error) {
+	if err := validateDeleted(ctx, t.tx, treeID, true /* wantDeleted */); err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, table := range treeDataTables {
+		if purged >= limit {
+			break
+		}
+		res, err := t.tx.ExecContext(ctx, fmt.Sprintf(purgeTreeDataSQL, table), treeID, limit-purged)
+		if err != nil {
+			return purged, err
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return purged, err
+		}
+		purged += int(rows)
+	}
+	return purged, nil
+}
+
 // updateDeleted updates the Deleted and DeleteTimeMillis fields of the specified tree.
 // deleteTimeMillis must be either an int64 (in millis since epoch) or nil.
 func (t *adminTX) updateDeleted(ctx context.Context, treeID int64, deleted bool, deleteTimeMillis interface{}) (*trillian.Tree, error) {
@@ -51,6 +51,9 @@ const (
 	//selectLatestSignedLogRootSQL  = `SELECT tree_head_timestamp,tree_size,root_hash,tree_revision,root_signature
 	//              FROM tree_head WHERE tree_id=$1
 	//              ORDER BY tree_head_timestamp DESC LIMIT 1`
+	selectSignedLogRootsSQL = `SELECT tree_head_timestamp,tree_size,root_hash,tree_revision,root_signature
+                        FROM tree_head WHERE tree_id=$1 AND tree_head_timestamp>=$2
+                        ORDER BY tree_head_timestamp LIMIT $3`
 
 	selectLeavesByRangeSQL = `SELECT s.merkle_leaf_hash,l.leaf_identity_hash,l.leaf_value,s.sequence_number,l.extra_data,l.queue_timestamp_nanos,s.integrate_timestamp_nanos
                         FROM leaf_data l,sequenced_leaf_data s
@@ -270,8 +273,9 @@ func (m *postgresLogStorage) beginInternal(ctx context.Context, tree *trillian.T
 	}
 
 	ltx := &logTreeTX{
-		treeTX: ttx,
-		ls:     m,
+		treeTX:        ttx,
+		ls:            m,
+		logRootConfig: tree.LogRootConfig,
 	}
 	ltx.slr, err = ltx.fetchLatestRoot(ctx)
 	if err == storage.ErrTreeNeedsInit {
@@ -357,6 +361,9 @@ type logTreeTX struct {
 	ls   *postgresLogStorage
 	root types.LogRootV1
 	slr  *trillian.SignedLogRoot
+	// logRootConfig is the format in which the log roots of the tree are
+	// signed, and put back together from the stored rows.
+	logRootConfig *trillian.LogRootConfig
 }
 
 func (t *logTreeTX) ReadRevision(ctx context.Context) (int64, error) {
@@ -660,6 +667,12 @@ func (t *logTreeTX) GetSequencedLeafCount(ctx context.Context) (int64, error) {
 	return sequencedLeafCount, err
 }
 
+// PruneLeaves is not implemented by the PostgreSQL storage, which keeps the
+// data of all leaves.
+func (t *logTreeTX) PruneLeaves(ctx context.Context, end int64, limit int) (int, error) {
+	return 0, status.Errorf(codes.Unimplemented, "PruneLeaves is not implemented")
+}
+
 func (t *logTreeTX) GetLeavesByIndex(ctx context.Context, leaves []int64) ([]*trillian.LogLeaf, error) {
 	if t.treeType == trillian.TreeType_LOG {
 		treeSize := int64(t.root.TreeSize)
@@ -829,7 +842,10 @@ func (t *logTreeTX) fetchLatestRoot(ctx context.Context) (*trillian.SignedLogRoo
 	}
 	var logRoot types.LogRootV1
 	json.Unmarshal(jsonObj, &logRoot)
-	newRoot, _ := logRoot.MarshalBinary()
+	newRoot, err := logRoot.MarshalForConfig(t.logRootConfig)
+	if err != nil {
+		return nil, err
+	}
 	return &trillian.SignedLogRoot{
 		KeyHint:          types.SerializeKeyHint(t.treeID),
 		LogRoot:          newRoot,
@@ -837,6 +853,47 @@ func (t *logTreeTX) fetchLatestRoot(ctx context.Context) (*trillian.SignedLogRoo
 	}, nil
 }
 
+func (t *logTreeTX) GetSignedLogRoots(ctx context.Context, startNanos int64, count int) ([]*trillian.SignedLogRoot, error) {
+	if startNanos < 0 || count <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid range startNanos=%d, count=%d", startNanos, count)
+	}
+	rows, err := t.tx.QueryContext(ctx, selectSignedLogRootsSQL, t.treeID, startNanos, count)
+	if err != nil {
+		glog.Warningf("Failed to get signed log roots: %s", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ret []*trillian.SignedLogRoot
+	for rows.Next() {
+		var timestamp, treeSize, treeRevision int64
+		var rootHash, rootSignatureBytes []byte
+		if err := rows.Scan(&timestamp, &treeSize, &rootHash, &treeRevision, &rootSignatureBytes); err != nil {
+			glog.Warningf("Failed to scan signed log root: %s", err)
+			return nil, err
+		}
+		logRoot, err := (&types.LogRootV1{
+			RootHash:       rootHash,
+			TimestampNanos: uint64(timestamp),
+			Revision:       uint64(treeRevision),
+			TreeSize:       uint64(treeSize),
+		}).MarshalForConfig(t.logRootConfig)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, &trillian.SignedLogRoot{
+			KeyHint:          types.SerializeKeyHint(t.treeID),
+			LogRoot:          logRoot,
+			LogRootSignature: rootSignatureBytes,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		glog.Warningf("Failed to read signed log roots: %s", err)
+		return nil, err
+	}
+	return ret, nil
+}
+
 func (t *logTreeTX) StoreSignedLogRoot(ctx context.Context, root *trillian.SignedLogRoot) error {
 	var logRoot types.LogRootV1
 	if err := logRoot.UnmarshalBinary(root.LogRoot); err != nil {
@@ -1009,6 +1009,57 @@ func TestLatestSignedLogRoot(t *testing.T) {
 	}
 }
 
+func TestLatestSignedLogRootCheckpoint(t *testing.T) {
+	cleanTestDB(db, t)
+	checkpointTree := proto.Clone(testonly.LogTree).(*trillian.Tree)
+	checkpointTree.LogRootConfig = &trillian.LogRootConfig{
+		Format: trillian.LogRootFormat_LOG_ROOT_FORMAT_CHECKPOINT,
+		Origin: "example.com/log",
+	}
+	tree := createTreeOrPanic(db, checkpointTree)
+	s := NewLogStorage(db, nil)
+
+	// The signature of a checkpoint covers the checkpoint text, so the root
+	// read back must be serialized in the same format to verify.
+	signer := tcrypto.NewSigner(tree.TreeId, ttestonly.NewSignerWithFixedSig(nil, []byte("notempty")), crypto.SHA256)
+	signer.LogRootFormat = tree.LogRootConfig.Format
+	signer.CheckpointOrigin = tree.LogRootConfig.Origin
+	root, err := signer.SignLogRoot(&types.LogRootV1{
+		TimestampNanos: 98765,
+		TreeSize:       16,
+		Revision:       5,
+		RootHash:       []byte(dummyHash),
+	})
+	if err != nil {
+		t.Fatalf("SignLogRoot(): %v", err)
+	}
+
+	runLogTX(s, tree, t, func(ctx context.Context, tx storage.LogTreeTX) error {
+		if err := tx.StoreSignedLogRoot(ctx, root); err != nil {
+			t.Fatalf("Failed to store signed root: %v", err)
+		}
+		return nil
+	})
+
+	runLogTX(s, tree, t, func(ctx context.Context, tx storage.LogTreeTX) error {
+		root2, err := tx.LatestSignedLogRoot(ctx)
+		if err != nil {
+			t.Fatalf("Failed to read back new log root: %v", err)
+		}
+		if !proto.Equal(root, root2) {
+			t.Fatalf("Root round trip failed: <%v> and: <%v>", root, root2)
+		}
+		roots, err := tx.(storage.LogRootHistoryReader).GetSignedLogRoots(ctx, 0, 1)
+		if err != nil {
+			t.Fatalf("GetSignedLogRoots(): %v", err)
+		}
+		if len(roots) != 1 || !proto.Equal(root, roots[0]) {
+			t.Fatalf("GetSignedLogRoots(): %v, want [%v]", roots, root)
+		}
+		return nil
+	})
+}
+
 func TestDuplicateSignedLogRoot(t *testing.T) {
 	cleanTestDB(db, t)
 	tree := createTreeOrPanic(db, testonly.LogTree)
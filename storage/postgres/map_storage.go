@@ -0,0 +1,456 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+	"github.com/google/trillian/merkle/hashers"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/cache"
+	"github.com/google/trillian/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	insertMapHeadSQL = `INSERT INTO map_head(tree_id, map_head_timestamp, root_hash, map_revision, root_signature, mapper_data)
+	VALUES($1, $2, $3, $4, $5, $6)`
+	selectLatestSignedMapRootSQL = `SELECT map_head_timestamp, root_hash, map_revision, root_signature, mapper_data
+		 FROM map_head WHERE tree_id=$1
+		 ORDER BY map_head_timestamp DESC LIMIT 1`
+	selectGetSignedMapRootSQL = `SELECT map_head_timestamp, root_hash, map_revision, root_signature, mapper_data
+		 FROM map_head WHERE tree_id=$1 AND map_revision=$2`
+	insertMapLeafSQL = `INSERT INTO map_leaf(tree_id, key_hash, map_revision, leaf_value) VALUES ($1, $2, $3, $4)`
+	selectMapLeafSQL = `
+		SELECT t1.key_hash, t1.leaf_value
+		FROM map_leaf t1
+		INNER JOIN
+		(
+			SELECT tree_id, key_hash, MAX(map_revision) AS max_revision
+			FROM map_leaf t0
+			WHERE t0.key_hash IN (` + placeholderSQL + `) AND
+			t0.tree_id = <param> AND t0.map_revision <= <param>
+			GROUP BY t0.tree_id, t0.key_hash
+		) t2
+		ON t1.tree_id = t2.tree_id
+		AND t1.key_hash = t2.key_hash
+		AND t1.map_revision = t2.max_revision`
+	selectMapLeavesSQL = `
+		SELECT t1.key_hash, t1.leaf_value
+		FROM map_leaf t1
+		INNER JOIN
+		(
+			SELECT tree_id, key_hash, MAX(map_revision) AS max_revision
+			FROM map_leaf t0
+			WHERE t0.tree_id = $1 AND t0.map_revision <= $2 AND
+			t0.key_hash >= $3 AND ($4::BYTEA IS NULL OR t0.key_hash < $4)
+			GROUP BY t0.tree_id, t0.key_hash
+			ORDER BY t0.key_hash
+			LIMIT $5
+		) t2
+		ON t1.tree_id = t2.tree_id
+		AND t1.key_hash = t2.key_hash
+		AND t1.map_revision = t2.max_revision
+		ORDER BY t1.key_hash`
+	selectChangedKeysSQL = `SELECT DISTINCT key_hash FROM map_leaf
+		WHERE tree_id=$1 AND key_hash>=$2 AND map_revision>$3 AND map_revision<=$4
+		ORDER BY key_hash LIMIT $5`
+	selectLeafRevisionsSQL = `SELECT map_revision FROM map_leaf
+		WHERE tree_id=$1 AND key_hash=$2 AND map_revision>$3 AND map_revision<=$4
+		ORDER BY map_revision LIMIT $5`
+)
+
+var defaultMapStrata = []int{8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 176}
+
+type postgresMapStorage struct {
+	*pgTreeStorage
+	admin storage.AdminStorage
+}
+
+// NewMapStorage creates a storage.MapStorage instance for the specified PostgreSQL URL.
+// It assumes storage.AdminStorage is backed by the same PostgreSQL database as well.
+func NewMapStorage(db *sql.DB) storage.MapStorage {
+	return &postgresMapStorage{
+		admin:         NewAdminStorage(db),
+		pgTreeStorage: newTreeStorage(db),
+	}
+}
+
+func (m *postgresMapStorage) CheckDatabaseAccessible(ctx context.Context) error {
+	return m.db.PingContext(ctx)
+}
+
+func (m *postgresMapStorage) getMapLeafStmt(ctx context.Context, num int) (*sql.Stmt, error) {
+	skeleton := &statementSkeleton{
+		sql:               selectMapLeafSQL,
+		firstInsertion:    "%s",
+		firstPlaceholders: 1,
+		restInsertion:     "%s",
+		restPlaceholders:  1,
+		num:               num,
+	}
+	return m.getStmt(ctx, skeleton)
+}
+
+func (m *postgresMapStorage) begin(ctx context.Context, tree *trillian.Tree, readonly bool) (storage.MapTreeTX, error) {
+	hasher, err := hashers.NewMapHasher(tree.HashStrategy)
+	if err != nil {
+		return nil, err
+	}
+
+	stCache := cache.NewMapSubtreeCache(defaultMapStrata, tree.TreeId, hasher)
+	ttx, err := m.beginTreeTx(ctx, tree, hasher.Size(), stCache)
+	if err != nil {
+		return nil, err
+	}
+
+	mtx := &mapTreeTX{
+		treeTX:       ttx,
+		ms:           m,
+		readRevision: -1,
+	}
+
+	if readonly {
+		// readRevision will be set later, by the first
+		// GetSignedMapRoot/LatestSignedMapRoot operation.
+		return mtx, nil
+	}
+
+	// A read-write transaction needs to know the current revision
+	// so it can write at revision+1.
+	root, err := mtx.LatestSignedMapRoot(ctx)
+	if err != nil && err != storage.ErrTreeNeedsInit {
+		ttx.Rollback()
+		return nil, err
+	}
+	if err == storage.ErrTreeNeedsInit {
+		return mtx, err
+	}
+
+	var mr types.MapRootV1
+	if err := mr.UnmarshalBinary(root.MapRoot); err != nil {
+		ttx.Rollback()
+		return nil, err
+	}
+
+	mtx.readRevision = int64(mr.Revision)
+	mtx.treeTX.writeRevision = int64(mr.Revision) + 1
+	return mtx, nil
+}
+
+func (m *postgresMapStorage) SnapshotForTree(ctx context.Context, tree *trillian.Tree) (storage.ReadOnlyMapTreeTX, error) {
+	return m.begin(ctx, tree, true /* readonly */)
+}
+
+func (m *postgresMapStorage) ReadWriteTransaction(ctx context.Context, tree *trillian.Tree, f storage.MapTXFunc) error {
+	tx, err := m.begin(ctx, tree, false /* readonly */)
+	if tx != nil {
+		defer tx.Close()
+	}
+	if err != nil && err != storage.ErrTreeNeedsInit {
+		return err
+	}
+	if err := f(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+type mapTreeTX struct {
+	treeTX
+	ms           *postgresMapStorage
+	readRevision int64
+}
+
+func (m *mapTreeTX) ReadRevision(ctx context.Context) (int64, error) {
+	return m.readRevision, nil
+}
+
+func (m *mapTreeTX) WriteRevision(ctx context.Context) (int64, error) {
+	if m.treeTX.writeRevision < 0 {
+		return m.treeTX.writeRevision, errors.New("mapTreeTX write revision not populated")
+	}
+	return m.treeTX.writeRevision, nil
+}
+
+func (m *mapTreeTX) Set(ctx context.Context, keyHash []byte, value *trillian.MapLeaf) error {
+	flatValue, err := proto.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := m.tx.PrepareContext(ctx, insertMapLeafSQL)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, m.treeID, keyHash, m.writeRevision, flatValue)
+	return err
+}
+
+// Get returns a list of map leaves indicated by indexes.
+// If an index is not found, no corresponding entry is returned.
+// Each MapLeaf.Index is overwritten with the index the leaf was found at.
+func (m *mapTreeTX) Get(ctx context.Context, revision int64, indexes [][]byte) ([]*trillian.MapLeaf, error) {
+	// If no indexes are requested, return an empty set.
+	if len(indexes) == 0 {
+		return []*trillian.MapLeaf{}, nil
+	}
+
+	stmt, err := m.ms.getMapLeafStmt(ctx, len(indexes))
+	if err != nil {
+		return nil, err
+	}
+	stx := m.tx.StmtContext(ctx, stmt)
+	defer stx.Close()
+
+	args := make([]interface{}, 0, len(indexes)+2)
+	for _, index := range indexes {
+		args = append(args, index)
+	}
+	args = append(args, m.treeID)
+	args = append(args, revision)
+
+	rows, err := stx.QueryContext(ctx, args...)
+	// It's possible there are no values for any of these keys yet
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret := make([]*trillian.MapLeaf, 0, len(indexes))
+	for rows.Next() {
+		var mapKeyHash, flatData []byte
+		if err := rows.Scan(&mapKeyHash, &flatData); err != nil {
+			return nil, err
+		}
+		mapLeaf, err := unmarshalMapLeaf(flatData, mapKeyHash)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, mapLeaf)
+	}
+	return ret, rows.Err()
+}
+
+// ListLeaves returns up to limit map leaves, ordered by index, whose index is
+// in the range [start, end), or [start, ∞) if end is nil.
+func (m *mapTreeTX) ListLeaves(ctx context.Context, revision int64, start, end []byte, limit int) ([]*trillian.MapLeaf, error) {
+	if limit <= 0 {
+		return []*trillian.MapLeaf{}, nil
+	}
+
+	stmt, err := m.tx.PrepareContext(ctx, selectMapLeavesSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	if start == nil {
+		start = []byte{}
+	}
+	rows, err := stmt.QueryContext(ctx, m.treeID, revision, start, end, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret := make([]*trillian.MapLeaf, 0, limit)
+	for rows.Next() {
+		var mapKeyHash, flatData []byte
+		if err := rows.Scan(&mapKeyHash, &flatData); err != nil {
+			return nil, err
+		}
+		mapLeaf, err := unmarshalMapLeaf(flatData, mapKeyHash)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, mapLeaf)
+	}
+	return ret, rows.Err()
+}
+
+// ListChangedKeys returns up to limit key hashes >= start, ordered, of the map
+// leaves which were set in the revisions (fromRevision, toRevision].
+func (m *mapTreeTX) ListChangedKeys(ctx context.Context, fromRevision, toRevision int64, start []byte, limit int) ([][]byte, error) {
+	if limit <= 0 {
+		return [][]byte{}, nil
+	}
+	if start == nil {
+		start = []byte{}
+	}
+	stmt, err := m.tx.PrepareContext(ctx, selectChangedKeysSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, m.treeID, start, fromRevision, toRevision, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret := make([][]byte, 0, limit)
+	for rows.Next() {
+		var keyHash []byte
+		if err := rows.Scan(&keyHash); err != nil {
+			return nil, err
+		}
+		ret = append(ret, keyHash)
+	}
+	return ret, rows.Err()
+}
+
+// ListLeafRevisions returns up to limit revisions, ordered, in the range
+// (fromRevision, toRevision] at which the map leaf with the given key hash was
+// set.
+func (m *mapTreeTX) ListLeafRevisions(ctx context.Context, keyHash []byte, fromRevision, toRevision int64, limit int) ([]int64, error) {
+	if limit <= 0 {
+		return []int64{}, nil
+	}
+	stmt, err := m.tx.PrepareContext(ctx, selectLeafRevisionsSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, m.treeID, keyHash, fromRevision, toRevision, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret := make([]int64, 0, limit)
+	for rows.Next() {
+		var rev int64
+		if err := rows.Scan(&rev); err != nil {
+			return nil, err
+		}
+		ret = append(ret, rev)
+	}
+	return ret, rows.Err()
+}
+
+func unmarshalMapLeaf(marshaledLeaf, mapKeyHash []byte) (*trillian.MapLeaf, error) {
+	if len(marshaledLeaf) == 0 {
+		return nil, errors.New("len(marshaledLeaf): 0 want > 0")
+	}
+	var mapLeaf trillian.MapLeaf
+	if err := proto.Unmarshal(marshaledLeaf, &mapLeaf); err != nil {
+		return nil, err
+	}
+	mapLeaf.Index = mapKeyHash
+	return &mapLeaf, nil
+}
+
+func (m *mapTreeTX) GetSignedMapRoot(ctx context.Context, revision int64) (*trillian.SignedMapRoot, error) {
+	var timestamp, mapRevision int64
+	var rootHash, rootSignatureBytes []byte
+	var mapperMetaBytes []byte
+
+	stmt, err := m.tx.PrepareContext(ctx, selectGetSignedMapRootSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	err = stmt.QueryRowContext(ctx, m.treeID, revision).Scan(
+		&timestamp, &rootHash, &mapRevision, &rootSignatureBytes, &mapperMetaBytes)
+	if err != nil {
+		if revision == 0 {
+			return nil, storage.ErrTreeNeedsInit
+		}
+		return nil, err
+	}
+	m.readRevision = mapRevision
+	return m.signedMapRoot(timestamp, mapRevision, rootHash, rootSignatureBytes, mapperMetaBytes)
+}
+
+func (m *mapTreeTX) LatestSignedMapRoot(ctx context.Context) (*trillian.SignedMapRoot, error) {
+	var timestamp, mapRevision int64
+	var rootHash, rootSignatureBytes []byte
+	var mapperMetaBytes []byte
+
+	stmt, err := m.tx.PrepareContext(ctx, selectLatestSignedMapRootSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	err = stmt.QueryRowContext(ctx, m.treeID).Scan(
+		&timestamp, &rootHash, &mapRevision, &rootSignatureBytes, &mapperMetaBytes)
+
+	// It's possible there are no roots for this tree yet
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrTreeNeedsInit
+	} else if err != nil {
+		return nil, err
+	}
+	m.readRevision = mapRevision
+	return m.signedMapRoot(timestamp, mapRevision, rootHash, rootSignatureBytes, mapperMetaBytes)
+}
+
+func (m *mapTreeTX) signedMapRoot(timestamp, mapRevision int64, rootHash, rootSignature, mapperMeta []byte) (*trillian.SignedMapRoot, error) {
+	mapRoot, err := (&types.MapRootV1{
+		RootHash:       rootHash,
+		TimestampNanos: uint64(timestamp),
+		Revision:       uint64(mapRevision),
+		Metadata:       mapperMeta,
+	}).MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &trillian.SignedMapRoot{
+		MapRoot:   mapRoot,
+		Signature: rootSignature,
+	}, nil
+}
+
+func (m *mapTreeTX) StoreSignedMapRoot(ctx context.Context, root *trillian.SignedMapRoot) error {
+	var r types.MapRootV1
+	if err := r.UnmarshalBinary(root.MapRoot); err != nil {
+		return err
+	}
+
+	stmt, err := m.tx.PrepareContext(ctx, insertMapHeadSQL)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, m.treeID, int64(r.TimestampNanos), r.RootHash, int64(r.Revision), root.Signature, r.Metadata)
+	if err != nil {
+		glog.Warningf("Failed to store signed map root: %s", err)
+	}
+
+	return checkResultOkAndRowCountIs(res, err, 1)
+}
+
+// CollectRevisions is not implemented by the PostgreSQL storage, which keeps
+// all revisions of maps.
+func (m *mapTreeTX) CollectRevisions(ctx context.Context, end int64, limit int) (int, error) {
+	return 0, status.Errorf(codes.Unimplemented, "CollectRevisions is not implemented")
+}
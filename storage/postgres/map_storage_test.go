@@ -0,0 +1,352 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+	"github.com/google/trillian/examples/ct/ctmapper/ctmapperpb"
+	"github.com/google/trillian/integration/storagetest"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/testonly"
+	"github.com/google/trillian/types"
+	"github.com/kylelemons/godebug/pretty"
+
+	tcrypto "github.com/google/trillian/crypto"
+	storageto "github.com/google/trillian/storage/testonly"
+)
+
+var fixedSigner = tcrypto.NewSigner(0, testonly.NewSignerWithFixedSig(nil, []byte("notempty")), crypto.SHA256)
+
+func TestMapIntegration(t *testing.T) {
+	storageFactory := func(_ context.Context, t *testing.T) (storage.MapStorage, storage.AdminStorage) {
+		cleanTestDB(db, t)
+		return NewMapStorage(db), NewAdminStorage(db)
+	}
+
+	storagetest.RunMapStorageTests(t, storageFactory)
+}
+
+func mustSignMapRoot(t *testing.T, root *types.MapRootV1) *trillian.SignedMapRoot {
+	t.Helper()
+	r, err := fixedSigner.SignMapRoot(root)
+	if err != nil {
+		t.Fatalf("SignMapRoot(): %v", err)
+	}
+	return r
+}
+
+func TestMapRootUpdate(t *testing.T) {
+	cleanTestDB(db, t)
+	ctx := context.Background()
+	s := NewMapStorage(db)
+	tree := createInitializedMapForTests(ctx, t, s)
+
+	populatedMetadata := testonly.MustMarshalAnyNoT(&ctmapperpb.MapperMetadata{HighestFullyCompletedSeq: 1})
+
+	for _, tc := range []struct {
+		desc         string
+		root         *trillian.SignedMapRoot
+		wantMetadata []byte
+	}{
+		{
+			desc: "Initial root",
+			root: mustSignMapRoot(t, &types.MapRootV1{
+				TimestampNanos: 98765,
+				Revision:       5,
+				RootHash:       dummyHash,
+			}),
+		},
+		{
+			desc: "Root update",
+			root: mustSignMapRoot(t, &types.MapRootV1{
+				TimestampNanos: 98766,
+				Revision:       6,
+				RootHash:       dummyHash,
+			}),
+		},
+		{
+			desc: "Root with non-default (populated) MapperMetadata",
+			root: mustSignMapRoot(t, &types.MapRootV1{
+				TimestampNanos: 98769,
+				Revision:       7,
+				RootHash:       dummyHash,
+				Metadata:       populatedMetadata,
+			}),
+			wantMetadata: populatedMetadata,
+		},
+	} {
+		runMapTX(ctx, s, tree, t, func(ctx context.Context, tx storage.MapTreeTX) error {
+			if err := tx.StoreSignedMapRoot(ctx, tc.root); err != nil {
+				t.Fatalf("%v: Failed to store signed map root: %v", tc.desc, err)
+			}
+			return nil
+		})
+
+		runMapTX(ctx, s, tree, t, func(ctx context.Context, tx storage.MapTreeTX) error {
+			smr, err := tx.LatestSignedMapRoot(ctx)
+			if err != nil {
+				t.Fatalf("%v: Failed to read back new map root: %v", tc.desc, err)
+			}
+			if !proto.Equal(smr, tc.root) {
+				t.Errorf("%v: LatestSignedMapRoot() = %v, want %v", tc.desc, smr, tc.root)
+			}
+
+			var root types.MapRootV1
+			if err := root.UnmarshalBinary(smr.MapRoot); err != nil {
+				t.Fatalf("%v: UnmarshalBinary(): %v", tc.desc, err)
+			}
+			if got, want := root.Metadata, tc.wantMetadata; !bytes.Equal(got, want) {
+				t.Errorf("%v: LatestSignedMapRoot() diff(-got, +want) \n%v", tc.desc, pretty.Compare(got, want))
+			}
+			return nil
+		})
+	}
+}
+
+var keyHash = []byte("A Key Hash")
+var mapLeaf = &trillian.MapLeaf{
+	Index:     keyHash,
+	LeafHash:  []byte("A Hash"),
+	LeafValue: []byte("A Value"),
+	ExtraData: []byte("Some Extra Data"),
+}
+
+func TestMapSetGetRoundTrip(t *testing.T) {
+	cleanTestDB(db, t)
+	ctx := context.Background()
+	s := NewMapStorage(db)
+	tree := createInitializedMapForTests(ctx, t, s)
+
+	runMapTX(ctx, s, tree, t, func(ctx context.Context, tx storage.MapTreeTX) error {
+		if err := tx.Set(ctx, keyHash, mapLeaf); err != nil {
+			t.Fatalf("Failed to set %v to %v: %v", keyHash, mapLeaf, err)
+		}
+		return nil
+	})
+
+	runMapTX(ctx, s, tree, t, func(ctx context.Context, tx storage.MapTreeTX) error {
+		readValues, err := tx.Get(ctx, 1, [][]byte{keyHash})
+		if err != nil {
+			t.Fatalf("Failed to get %v:  %v", keyHash, err)
+		}
+		if got, want := len(readValues), 1; got != want {
+			t.Fatalf("Got %d values, expected %d", got, want)
+		}
+		if got, want := readValues[0], mapLeaf; !proto.Equal(got, want) {
+			t.Fatalf("Read back %v, but expected %v", got, want)
+		}
+		return nil
+	})
+}
+
+func TestMapSetSameKeyInSameRevisionFails(t *testing.T) {
+	cleanTestDB(db, t)
+	ctx := context.Background()
+	s := NewMapStorage(db)
+	tree := createInitializedMapForTests(ctx, t, s)
+
+	err := s.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+		if err := tx.Set(ctx, keyHash, mapLeaf); err != nil {
+			t.Fatalf("Failed to set %v to %v: %v", keyHash, mapLeaf, err)
+		}
+		return tx.Set(ctx, keyHash, mapLeaf)
+	})
+	if err == nil {
+		t.Fatalf("Unexpectedly succeeded in setting %v to %v twice", keyHash, mapLeaf)
+	}
+}
+
+func TestMapGet0Results(t *testing.T) {
+	cleanTestDB(db, t)
+	ctx := context.Background()
+	s := NewMapStorage(db)
+	tree := createInitializedMapForTests(ctx, t, s)
+
+	for _, tc := range []struct {
+		index [][]byte
+	}{
+		{index: nil}, //empty list.
+		{index: [][]byte{[]byte("This doesn't exist.")}},
+	} {
+		t.Run(fmt.Sprintf("tx.Get(%s)", tc.index), func(t *testing.T) {
+			runMapTX(ctx, s, tree, t, func(ctx context.Context, tx storage.MapTreeTX) error {
+				readValues, err := tx.Get(ctx, 1, tc.index)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got, want := len(readValues), 0; got != want {
+					t.Fatalf("len = %d, want %d", got, want)
+				}
+				return nil
+			})
+		})
+	}
+}
+
+func TestMapSetGetMultipleRevisions(t *testing.T) {
+	cleanTestDB(db, t)
+	ctx := context.Background()
+	s := NewMapStorage(db)
+	tree := createInitializedMapForTests(ctx, t, s)
+
+	tests := []struct {
+		rev  int64
+		leaf *trillian.MapLeaf
+	}{
+		{0, &trillian.MapLeaf{Index: keyHash, LeafHash: []byte{0}, LeafValue: []byte{0}, ExtraData: []byte{0}}},
+		{1, &trillian.MapLeaf{Index: keyHash, LeafHash: []byte{1}, LeafValue: []byte{1}, ExtraData: []byte{1}}},
+		{2, &trillian.MapLeaf{Index: keyHash, LeafHash: []byte{2}, LeafValue: []byte{2}, ExtraData: []byte{2}}},
+		{3, &trillian.MapLeaf{Index: keyHash, LeafHash: []byte{3}, LeafValue: []byte{3}, ExtraData: []byte{3}}},
+	}
+
+	for _, tc := range tests {
+		// Write the current test case.
+		runMapTX(ctx, s, tree, t, func(ctx context.Context, tx storage.MapTreeTX) error {
+			tx.(*mapTreeTX).treeTX.writeRevision = tc.rev
+			if err := tx.Set(ctx, keyHash, tc.leaf); err != nil {
+				t.Fatalf("Failed to set %v to %v: %v", keyHash, tc.leaf, err)
+			}
+			return nil
+		})
+
+		// Read at a point in time in the future. Expect to get the latest value.
+		// Read at each point in the past. Expect to get that exact point in history.
+		for i := int64(0); i < int64(len(tests)); i++ {
+			expectRev := i
+			if expectRev > tc.rev {
+				expectRev = tc.rev // For future revisions, expect the current value.
+			}
+
+			runMapTX(ctx, s, tree, t, func(ctx context.Context, tx storage.MapTreeTX) error {
+				readValues, err := tx.Get(ctx, i, [][]byte{keyHash})
+				if err != nil {
+					t.Fatalf("At i %d failed to get %v:  %v", i, keyHash, err)
+				}
+				if got, want := len(readValues), 1; got != want {
+					t.Fatalf("At i %d got %d values, expected %d", i, got, want)
+				}
+				if got, want := readValues[0], tests[expectRev].leaf; !proto.Equal(got, want) {
+					t.Fatalf("At i %d read back %v, but expected %v", i, got, want)
+				}
+				return nil
+			})
+		}
+	}
+}
+
+func TestGetSignedMapRootNotExist(t *testing.T) {
+	cleanTestDB(db, t)
+	ctx := context.Background()
+	tree := createTreeOrPanic(db, storageto.MapTree) // Uninitialized: no revision 0 MapRoot exists.
+	s := NewMapStorage(db)
+
+	err := s.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+		_, err := tx.GetSignedMapRoot(ctx, 0)
+		if got, want := err, storage.ErrTreeNeedsInit; got != want {
+			t.Fatalf("GetSignedMapRoot: %v, want %v", got, want)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ReadWriteTransaction: %v", err)
+	}
+}
+
+func TestGetSignedMapRoot(t *testing.T) {
+	cleanTestDB(db, t)
+	ctx := context.Background()
+	s := NewMapStorage(db)
+	tree := createInitializedMapForTests(ctx, t, s)
+
+	revision := int64(5)
+	root := mustSignMapRoot(t, &types.MapRootV1{
+		TimestampNanos: 98765,
+		Revision:       uint64(revision),
+		RootHash:       dummyHash,
+	})
+	runMapTX(ctx, s, tree, t, func(ctx context.Context, tx storage.MapTreeTX) error {
+		if err := tx.StoreSignedMapRoot(ctx, root); err != nil {
+			t.Fatalf("Failed to store signed root: %v", err)
+		}
+		return nil
+	})
+
+	runMapTX(ctx, s, tree, t, func(ctx context.Context, tx storage.MapTreeTX) error {
+		root2, err := tx.GetSignedMapRoot(ctx, revision)
+		if err != nil {
+			t.Fatalf("Failed to get back new map root: %v", err)
+		}
+		if !proto.Equal(root, root2) {
+			t.Fatalf("Getting root round trip failed: <%#v> and: <%#v>", root, root2)
+		}
+		return nil
+	})
+}
+
+func TestDuplicateSignedMapRoot(t *testing.T) {
+	cleanTestDB(db, t)
+	ctx := context.Background()
+	s := NewMapStorage(db)
+	tree := createInitializedMapForTests(ctx, t, s)
+
+	err := s.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+		root := mustSignMapRoot(t, &types.MapRootV1{
+			TimestampNanos: 98765,
+			Revision:       5,
+			RootHash:       dummyHash,
+		})
+		if err := tx.StoreSignedMapRoot(ctx, root); err != nil {
+			t.Fatalf("Failed to store signed map root: %v", err)
+		}
+		// Shouldn't be able to do it again
+		return tx.StoreSignedMapRoot(ctx, root)
+	})
+	if err == nil {
+		t.Fatal("Allowed duplicate signed map root")
+	}
+}
+
+func runMapTX(ctx context.Context, s storage.MapStorage, tree *trillian.Tree, t *testing.T, f storage.MapTXFunc) {
+	t.Helper()
+	if err := s.ReadWriteTransaction(ctx, tree, f); err != nil {
+		t.Fatalf("Failed to run map tx: %v", err)
+	}
+}
+
+func createInitializedMapForTests(ctx context.Context, t *testing.T, s storage.MapStorage) *trillian.Tree {
+	t.Helper()
+	tree := createTreeOrPanic(db, storageto.MapTree)
+
+	signer := tcrypto.NewSigner(tree.TreeId, testonly.NewSignerWithFixedSig(nil, []byte("sig")), crypto.SHA256)
+	err := s.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+		initialRoot, _ := signer.SignMapRoot(&types.MapRootV1{
+			RootHash: []byte("rootHash"),
+			Revision: 0,
+		})
+		return tx.StoreSignedMapRoot(ctx, initialRoot)
+	})
+	if err != nil {
+		t.Fatalf("ReadWriteTransaction() = %v", err)
+	}
+
+	return tree
+}
@@ -0,0 +1,134 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/trillian/storage/migrate"
+)
+
+// SchemaVersion is the version of the schema in schema/storage.sql, which is
+// the minimum version required by this package.
+const SchemaVersion = 8
+
+var dialect = migrate.Dialect{
+	TableExistsSQL: "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = $1",
+	VersionTable:   "schema_version",
+	TreesTable:     "trees",
+	CreateVersionTableSQL: `CREATE TABLE IF NOT EXISTS schema_version(
+  version               INTEGER NOT NULL,
+  description           VARCHAR(200) NOT NULL,
+  applied_time_millis   BIGINT NOT NULL,
+  PRIMARY KEY(version)
+)`,
+	SelectVersionSQL: "SELECT MAX(version) FROM schema_version",
+	InsertVersionSQL: "INSERT INTO schema_version(version, description, applied_time_millis) VALUES($1, $2, $3)",
+}
+
+// migrations takes a schema from migrate.BaselineVersion to SchemaVersion.
+// Schema changes must be made both here and in schema/storage.sql.
+var migrations = []migrate.Migration{
+	{
+		Version:     2,
+		Description: "Add the map_leaf and map_head tables",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS map_leaf(
+  tree_id               BIGINT NOT NULL,
+  key_hash              BYTEA NOT NULL,
+  map_revision          BIGINT NOT NULL,
+  leaf_value            BYTEA NOT NULL,
+  PRIMARY KEY(tree_id, key_hash, map_revision),
+  FOREIGN KEY(tree_id) REFERENCES trees(tree_id) ON DELETE CASCADE
+)`,
+			`CREATE TABLE IF NOT EXISTS map_head(
+  tree_id               BIGINT NOT NULL,
+  map_head_timestamp    BIGINT,
+  root_hash             BYTEA NOT NULL,
+  map_revision          BIGINT,
+  root_signature        BYTEA NOT NULL,
+  mapper_data           BYTEA,
+  PRIMARY KEY(tree_id, map_head_timestamp),
+  FOREIGN KEY(tree_id) REFERENCES trees(tree_id) ON DELETE CASCADE
+)`,
+			"CREATE UNIQUE INDEX IF NOT EXISTS MapHeadRevisionIdx ON map_head(tree_id, map_revision)",
+		},
+	},
+	{
+		Version:     3,
+		Description: "Add trees.sequencing_config",
+		Statements: []string{
+			"ALTER TABLE trees ADD COLUMN IF NOT EXISTS sequencing_config BYTEA",
+		},
+	},
+	{
+		Version:     4,
+		Description: "Add trees.retention_policy",
+		Statements: []string{
+			"ALTER TABLE trees ADD COLUMN IF NOT EXISTS retention_policy BYTEA",
+		},
+	},
+	{
+		Version:     5,
+		Description: "Add trees.storage_settings",
+		Statements: []string{
+			"ALTER TABLE trees ADD COLUMN IF NOT EXISTS storage_settings BYTEA",
+		},
+	},
+	{
+		Version:     6,
+		Description: "Add the election_lease table",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS election_lease(
+  resource_id           VARCHAR(255) NOT NULL,
+  leader_id             VARCHAR(255) NOT NULL,
+  term                  BIGINT NOT NULL,
+  expiry_time_millis    BIGINT NOT NULL,
+  PRIMARY KEY(resource_id)
+)`,
+		},
+	},
+	{
+		Version:     7,
+		Description: "Add trees.log_root_config",
+		Statements: []string{
+			"ALTER TABLE trees ADD COLUMN IF NOT EXISTS log_root_config BYTEA",
+		},
+	},
+	{
+		Version:     8,
+		Description: "Add trees.map_retention_policy",
+		Statements: []string{
+			"ALTER TABLE trees ADD COLUMN IF NOT EXISTS map_retention_policy BYTEA",
+		},
+	},
+}
+
+// NewMigrator returns a migrate.Migrator which brings the schema of db up to
+// SchemaVersion.
+func NewMigrator(db *sql.DB) (*migrate.Migrator, error) {
+	return migrate.New(db, dialect, migrations)
+}
+
+// CheckSchemaVersion returns an error if the schema of db is older than
+// SchemaVersion.
+func CheckSchemaVersion(ctx context.Context, db *sql.DB) error {
+	m, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	return m.Check(ctx, SchemaVersion)
+}
@@ -10,6 +10,25 @@ CREATE TYPE E_HASH_STRATEGY AS ENUM('RFC6962_SHA256', 'TEST_MAP_HASHER', 'OBJECT
 CREATE TYPE E_HASH_ALGORITHM AS ENUM('SHA256');
 CREATE TYPE E_SIGNATURE_ALGORITHM AS ENUM('ECDSA', 'RSA');
 
+-- Each row records a schema version applied to the database, see the
+-- storage/migrate package. Databases created from this file start at the
+-- version below, later versions are applied by trillian_migrate. The version
+-- is only recorded if the trees table doesn't exist yet, so that running this
+-- file again on an older database doesn't mark it as up to date; such
+-- databases must be brought up to date with trillian_migrate.
+CREATE TABLE IF NOT EXISTS schema_version(
+  version               INTEGER NOT NULL,
+  description           VARCHAR(200) NOT NULL,
+  applied_time_millis   BIGINT NOT NULL,
+  PRIMARY KEY(version)
+);
+
+INSERT INTO schema_version(version, description, applied_time_millis)
+  SELECT 8, 'Initial schema', (EXTRACT(EPOCH FROM NOW()) * 1000)::BIGINT
+  WHERE NOT EXISTS (SELECT * FROM information_schema.tables
+    WHERE table_schema = current_schema() AND table_name = 'trees')
+  ON CONFLICT DO NOTHING;
+
 -- Tree parameters should not be changed after creation. Doing so can
 -- render the data in the tree unusable or inconsistent.
 CREATE TABLE IF NOT EXISTS trees (
@@ -30,6 +49,11 @@ CREATE TABLE IF NOT EXISTS trees (
   delete_time_millis       BIGINT,
   current_tree_data        json,
   root_signature	   BYTEA,
+  sequencing_config        BYTEA,
+  retention_policy         BYTEA,
+  storage_settings         BYTEA,
+  log_root_config          BYTEA,
+  map_retention_policy     BYTEA,
   PRIMARY KEY(tree_id)
 );
 
@@ -136,6 +160,41 @@ CREATE TABLE IF NOT EXISTS unsequenced(
   PRIMARY KEY (queue_timestamp_nanos, leaf_identity_hash)
 );
 
+-- ---------------------------------------------
+-- Map specific stuff here
+-- ---------------------------------------------
+
+CREATE TABLE IF NOT EXISTS map_leaf(
+  tree_id               BIGINT NOT NULL,
+  key_hash              BYTEA NOT NULL,
+  map_revision          BIGINT NOT NULL,
+  leaf_value            BYTEA NOT NULL,
+  PRIMARY KEY(tree_id, key_hash, map_revision)
+);
+
+CREATE TABLE IF NOT EXISTS map_head(
+  tree_id               BIGINT NOT NULL,
+  map_head_timestamp    BIGINT,
+  root_hash             BYTEA NOT NULL,
+  map_revision          BIGINT,
+  root_signature        BYTEA NOT NULL,
+  mapper_data           BYTEA,
+  PRIMARY KEY(tree_id, map_head_timestamp)
+);
+
+-- The MapHeadRevisionIdx is used to enforce that there is only one SMR at any
+-- map revision.
+CREATE UNIQUE INDEX IF NOT EXISTS MapHeadRevisionIdx ON map_head(tree_id, map_revision);
+
+-- Master election leases, see util/election2/sql.
+CREATE TABLE IF NOT EXISTS election_lease(
+  resource_id           VARCHAR(255) NOT NULL,
+  leader_id             VARCHAR(255) NOT NULL,
+  term                  BIGINT NOT NULL,
+  expiry_time_millis    BIGINT NOT NULL,
+  PRIMARY KEY(resource_id)
+);
+
 CREATE OR REPLACE FUNCTION public.insert_leaf_data_ignore_duplicates(tree_id bigint, leaf_identity_hash bytea, leaf_value bytea, extra_data bytea, queue_timestamp_nanos bigint)
  RETURNS boolean
  LANGUAGE plpgsql
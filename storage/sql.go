@@ -60,6 +60,7 @@ func ReadTree(row Row) (*trillian.Tree, error) {
 	var privateKey, publicKey []byte
 	var deleted sql.NullBool
 	var deleteMillis sql.NullInt64
+	var sequencingConfig, retentionPolicy, storageSettings, logRootConfig, mapRetentionPolicy []byte
 	err := row.Scan(
 		&tree.TreeId,
 		&treeState,
@@ -76,6 +77,11 @@ func ReadTree(row Row) (*trillian.Tree, error) {
 		&maxRootDurationMillis,
 		&deleted,
 		&deleteMillis,
+		&sequencingConfig,
+		&retentionPolicy,
+		&storageSettings,
+		&logRootConfig,
+		&mapRetentionPolicy,
 	)
 	if err != nil {
 		return nil, err
@@ -140,6 +146,37 @@ func ReadTree(row Row) (*trillian.Tree, error) {
 	}
 	tree.PublicKey = &keyspb.PublicKey{Der: publicKey}
 
+	if len(sequencingConfig) > 0 {
+		tree.SequencingConfig = &trillian.SequencingConfig{}
+		if err := proto.Unmarshal(sequencingConfig, tree.SequencingConfig); err != nil {
+			return nil, fmt.Errorf("could not unmarshal SequencingConfig: %v", err)
+		}
+	}
+	if len(retentionPolicy) > 0 {
+		tree.RetentionPolicy = &trillian.RetentionPolicy{}
+		if err := proto.Unmarshal(retentionPolicy, tree.RetentionPolicy); err != nil {
+			return nil, fmt.Errorf("could not unmarshal RetentionPolicy: %v", err)
+		}
+	}
+	if len(storageSettings) > 0 {
+		tree.StorageSettings = &any.Any{}
+		if err := proto.Unmarshal(storageSettings, tree.StorageSettings); err != nil {
+			return nil, fmt.Errorf("could not unmarshal StorageSettings: %v", err)
+		}
+	}
+	if len(logRootConfig) > 0 {
+		tree.LogRootConfig = &trillian.LogRootConfig{}
+		if err := proto.Unmarshal(logRootConfig, tree.LogRootConfig); err != nil {
+			return nil, fmt.Errorf("could not unmarshal LogRootConfig: %v", err)
+		}
+	}
+	if len(mapRetentionPolicy) > 0 {
+		tree.MapRetentionPolicy = &trillian.MapRetentionPolicy{}
+		if err := proto.Unmarshal(mapRetentionPolicy, tree.MapRetentionPolicy); err != nil {
+			return nil, fmt.Errorf("could not unmarshal MapRetentionPolicy: %v", err)
+		}
+	}
+
 	tree.Deleted = deleted.Valid && deleted.Bool
 	if tree.Deleted && deleteMillis.Valid {
 		tree.DeleteTime, err = ptypes.TimestampProto(FromMillisSinceEpoch(deleteMillis.Int64))
@@ -150,3 +187,68 @@ func ReadTree(row Row) (*trillian.Tree, error) {
 
 	return tree, nil
 }
+
+// MarshalSequencingConfig returns the serialized SequencingConfig of tree, or
+// nil if it's unset.
+func MarshalSequencingConfig(tree *trillian.Tree) ([]byte, error) {
+	if tree.SequencingConfig == nil {
+		return nil, nil
+	}
+	b, err := proto.Marshal(tree.SequencingConfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal SequencingConfig: %v", err)
+	}
+	return b, nil
+}
+
+// MarshalRetentionPolicy returns the serialized RetentionPolicy of tree, or nil
+// if it's unset.
+func MarshalRetentionPolicy(tree *trillian.Tree) ([]byte, error) {
+	if tree.RetentionPolicy == nil {
+		return nil, nil
+	}
+	b, err := proto.Marshal(tree.RetentionPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal RetentionPolicy: %v", err)
+	}
+	return b, nil
+}
+
+// MarshalStorageSettings returns the serialized StorageSettings of tree, or nil
+// if they're unset.
+func MarshalStorageSettings(tree *trillian.Tree) ([]byte, error) {
+	if tree.StorageSettings == nil {
+		return nil, nil
+	}
+	b, err := proto.Marshal(tree.StorageSettings)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal StorageSettings: %v", err)
+	}
+	return b, nil
+}
+
+// MarshalLogRootConfig returns the serialized LogRootConfig of tree, or nil if
+// it's unset.
+func MarshalLogRootConfig(tree *trillian.Tree) ([]byte, error) {
+	if tree.LogRootConfig == nil {
+		return nil, nil
+	}
+	b, err := proto.Marshal(tree.LogRootConfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal LogRootConfig: %v", err)
+	}
+	return b, nil
+}
+
+// MarshalMapRetentionPolicy returns the serialized MapRetentionPolicy of tree,
+// or nil if it's unset.
+func MarshalMapRetentionPolicy(tree *trillian.Tree) ([]byte, error) {
+	if tree.MapRetentionPolicy == nil {
+		return nil, nil
+	}
+	b, err := proto.Marshal(tree.MapRetentionPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal MapRetentionPolicy: %v", err)
+	}
+	return b, nil
+}
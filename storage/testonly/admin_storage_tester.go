@@ -178,6 +178,8 @@ func (tester *AdminStorageTester) RunAllTests(t *testing.T) {
 	t.Run("TestSoftDeleteTreeErrors", tester.TestSoftDeleteTreeErrors)
 	t.Run("TestHardDeleteTree", tester.TestHardDeleteTree)
 	t.Run("TestHardDeleteTreeErrors", tester.TestHardDeleteTreeErrors)
+	t.Run("TestPurgeTreeData", tester.TestPurgeTreeData)
+	t.Run("TestPurgeTreeDataErrors", tester.TestPurgeTreeDataErrors)
 	t.Run("TestUndeleteTree", tester.TestUndeleteTree)
 	t.Run("TestUndeleteTreeErrors", tester.TestUndeleteTreeErrors)
 	t.Run("TestAdminTXReadWriteTransaction", tester.TestAdminTXReadWriteTransaction)
@@ -198,6 +200,12 @@ func (tester *AdminStorageTester) TestCreateTree(t *testing.T) {
 	validTreeWithoutOptionals.DisplayName = ""
 	validTreeWithoutOptionals.Description = ""
 
+	checkpointTree := proto.Clone(LogTree).(*trillian.Tree)
+	checkpointTree.LogRootConfig = &trillian.LogRootConfig{
+		Format: trillian.LogRootFormat_LOG_ROOT_FORMAT_CHECKPOINT,
+		Origin: "example.com/llamas",
+	}
+
 	tests := []struct {
 		desc    string
 		tree    *trillian.Tree
@@ -224,6 +232,10 @@ func (tester *AdminStorageTester) TestCreateTree(t *testing.T) {
 			desc: "validTreeWithoutOptionals",
 			tree: validTreeWithoutOptionals,
 		},
+		{
+			desc: "checkpointTree",
+			tree: checkpointTree,
+		},
 	}
 
 	ctx := context.Background()
@@ -308,6 +320,30 @@ func (tester *AdminStorageTester) TestUpdateTree(t *testing.T) {
 		tree.TreeType = trillian.TreeType_MAP
 	}
 
+	sequencingConfig := &trillian.SequencingConfig{
+		Paused:          true,
+		BatchSize:       25,
+		GuardWindow:     ptypes.DurationProto(2 * time.Second),
+		MinRootInterval: ptypes.DurationProto(time.Minute),
+	}
+	sequencingConfigChangedTree := tweakedCopy(LogTree, func(tree *trillian.Tree) {
+		tree.SequencingConfig = sequencingConfig
+	})
+	sequencingConfigChangedFunc := func(tree *trillian.Tree) {
+		tree.SequencingConfig = sequencingConfig
+	}
+
+	retentionPolicy := &trillian.RetentionPolicy{
+		MaxEntries: 1000,
+		MaxAge:     ptypes.DurationProto(24 * time.Hour),
+	}
+	retentionPolicyChangedTree := tweakedCopy(LogTree, func(tree *trillian.Tree) {
+		tree.RetentionPolicy = retentionPolicy
+	})
+	retentionPolicyChangedFunc := func(tree *trillian.Tree) {
+		tree.RetentionPolicy = retentionPolicy
+	}
+
 	referenceMap := proto.Clone(MapTree).(*trillian.Tree)
 	validMap := proto.Clone(referenceMap).(*trillian.Tree)
 	validMap.DisplayName = "Updated Map"
@@ -315,6 +351,17 @@ func (tester *AdminStorageTester) TestUpdateTree(t *testing.T) {
 		tree.DisplayName = validMap.DisplayName
 	}
 
+	mapRetentionPolicy := &trillian.MapRetentionPolicy{
+		MaxRevisions: 100,
+		MaxAge:       ptypes.DurationProto(24 * time.Hour),
+	}
+	mapRetentionPolicyChangedTree := tweakedCopy(MapTree, func(tree *trillian.Tree) {
+		tree.MapRetentionPolicy = mapRetentionPolicy
+	})
+	mapRetentionPolicyChangedFunc := func(tree *trillian.Tree) {
+		tree.MapRetentionPolicy = mapRetentionPolicy
+	}
+
 	newPrivateKey := &empty.Empty{}
 	privateKeyChangedButKeyMaterialSameTree := tweakedCopy(LogTree, func(tree *trillian.Tree) {
 		tree.PrivateKey = testonly.MustMarshalAny(t, newPrivateKey)
@@ -369,12 +416,30 @@ func (tester *AdminStorageTester) TestUpdateTree(t *testing.T) {
 			updateFunc: readonlyChangedFunc,
 			wantErr:    true,
 		},
+		{
+			desc:       "sequencingConfigChanged",
+			create:     referenceLog,
+			updateFunc: sequencingConfigChangedFunc,
+			want:       sequencingConfigChangedTree,
+		},
+		{
+			desc:       "retentionPolicyChanged",
+			create:     referenceLog,
+			updateFunc: retentionPolicyChangedFunc,
+			want:       retentionPolicyChangedTree,
+		},
 		{
 			desc:       "validMap",
 			create:     referenceMap,
 			updateFunc: validMapFunc,
 			want:       validMap,
 		},
+		{
+			desc:       "mapRetentionPolicyChanged",
+			create:     referenceMap,
+			updateFunc: mapRetentionPolicyChangedFunc,
+			want:       mapRetentionPolicyChangedTree,
+		},
 		{
 			desc:       "privateKeyChangedButKeyMaterialSame",
 			create:     referenceLog,
@@ -626,6 +691,55 @@ func (tester *AdminStorageTester) TestHardDeleteTreeErrors(t *testing.T) {
 	}
 }
 
+// TestPurgeTreeData tests success scenarios of PurgeTreeData.
+func (tester *AdminStorageTester) TestPurgeTreeData(t *testing.T) {
+	ctx := context.Background()
+	s := tester.NewAdminStorage()
+
+	logTree := makeTreeOrFail(ctx, s, spec{Tree: LogTree, Deleted: true}, t.Fatalf)
+	mapTree := makeTreeOrFail(ctx, s, spec{Tree: MapTree, Deleted: true}, t.Fatalf)
+
+	tests := []struct {
+		desc   string
+		treeID int64
+	}{
+		{desc: "logTree", treeID: logTree.TreeId},
+		{desc: "mapTree", treeID: mapTree.TreeId},
+	}
+	for _, test := range tests {
+		// New trees hold no data, so nothing is left to purge.
+		if purged, err := storage.PurgeTreeData(ctx, s, test.treeID, 10); err != nil || purged >= 10 {
+			t.Errorf("%v: PurgeTreeData() = (%v, %v), want less than 10 records purged", test.desc, purged, err)
+			continue
+		}
+		if err := storage.HardDeleteTree(ctx, s, test.treeID); err != nil {
+			t.Errorf("%v: HardDeleteTree() returned err = %v", test.desc, err)
+		}
+	}
+}
+
+// TestPurgeTreeDataErrors tests error scenarios of PurgeTreeData.
+func (tester *AdminStorageTester) TestPurgeTreeDataErrors(t *testing.T) {
+	ctx := context.Background()
+	s := tester.NewAdminStorage()
+
+	activeTree := makeTreeOrFail(ctx, s, spec{Tree: LogTree}, t.Fatalf)
+
+	tests := []struct {
+		desc     string
+		treeID   int64
+		wantCode codes.Code
+	}{
+		{desc: "unknownTree", treeID: 12345, wantCode: codes.NotFound},
+		{desc: "activeTree", treeID: activeTree.TreeId, wantCode: codes.FailedPrecondition},
+	}
+	for _, test := range tests {
+		if _, err := storage.PurgeTreeData(ctx, s, test.treeID, 10); status.Code(err) != test.wantCode {
+			t.Errorf("%v: PurgeTreeData() returned err = %v, wantCode = %s", test.desc, err, test.wantCode)
+		}
+	}
+}
+
 // TestUndeleteTree tests success scenarios of UndeleteTree.
 func (tester *AdminStorageTester) TestUndeleteTree(t *testing.T) {
 	ctx := context.Background()
@@ -17,13 +17,16 @@ package storage
 import (
 	"bytes"
 	"context"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/duration"
 	"github.com/google/trillian"
 	"github.com/google/trillian/crypto/keys"
 	"github.com/google/trillian/crypto/keys/der"
 	"github.com/google/trillian/crypto/sigpb"
+	"github.com/google/trillian/types"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -56,9 +59,38 @@ func ValidateTreeForCreation(ctx context.Context, tree *trillian.Tree) error {
 		return status.Errorf(codes.InvalidArgument, "invalid delete_time: %+v (must be nil)", tree.DeleteTime)
 	}
 
+	if err := validateLogRootConfig(tree); err != nil {
+		return err
+	}
 	return validateMutableTreeFields(ctx, tree)
 }
 
+func validateLogRootConfig(tree *trillian.Tree) error {
+	c := tree.LogRootConfig
+	if c == nil {
+		return nil
+	}
+	if tree.TreeType != trillian.TreeType_LOG && tree.TreeType != trillian.TreeType_PREORDERED_LOG {
+		return status.Errorf(codes.InvalidArgument, "log_root_config not supported by tree_type %v", tree.TreeType)
+	}
+	switch c.Format {
+	case trillian.LogRootFormat_LOG_ROOT_FORMAT_UNKNOWN, trillian.LogRootFormat_LOG_ROOT_FORMAT_V1:
+		if c.Origin != "" {
+			return status.Errorf(codes.InvalidArgument, "log_root_config.origin only supported by %v", trillian.LogRootFormat_LOG_ROOT_FORMAT_CHECKPOINT)
+		}
+	case trillian.LogRootFormat_LOG_ROOT_FORMAT_CHECKPOINT:
+		if alg := tree.SignatureAlgorithm; alg != sigpb.DigitallySigned_ECDSA && alg != sigpb.DigitallySigned_ED25519 {
+			return status.Errorf(codes.InvalidArgument, "log_root_config.format %v not supported by signature_algorithm %v", c.Format, alg)
+		}
+		if err := types.ValidateCheckpointOrigin(c.Origin); err != nil {
+			return status.Errorf(codes.InvalidArgument, "log_root_config.origin: %v", err)
+		}
+	default:
+		return status.Errorf(codes.InvalidArgument, "invalid log_root_config.format: %v", c.Format)
+	}
+	return nil
+}
+
 // validateTreeTypeUpdate returns nil iff oldTree.TreeType can be updated to
 // newTree.TreeType. The tree type is changeable only if the Tree is and
 // remains in the FROZEN state.
@@ -111,6 +143,8 @@ func ValidateTreeForUpdate(ctx context.Context, storedTree, newTree *trillian.Tr
 		return status.Error(codes.InvalidArgument, "readonly field changed: deleted")
 	case !proto.Equal(storedTree.DeleteTime, newTree.DeleteTime):
 		return status.Error(codes.InvalidArgument, "readonly field changed: delete_time")
+	case !proto.Equal(storedTree.LogRootConfig, newTree.LogRootConfig):
+		return status.Error(codes.InvalidArgument, "readonly field changed: log_root_config")
 	}
 	return validateMutableTreeFields(ctx, newTree)
 }
@@ -119,12 +153,23 @@ func validateMutableTreeFields(ctx context.Context, tree *trillian.Tree) error {
 	if tree.TreeState == trillian.TreeState_UNKNOWN_TREE_STATE {
 		return status.Errorf(codes.InvalidArgument, "invalid tree_state: %v", tree.TreeState)
 	}
-	if duration, err := ptypes.Duration(tree.MaxRootDuration); err != nil {
+	maxRootDuration, err := ptypes.Duration(tree.MaxRootDuration)
+	if err != nil {
 		return status.Errorf(codes.InvalidArgument, "max_root_duration malformed: %v", tree.MaxRootDuration)
-	} else if duration < 0 {
+	} else if maxRootDuration < 0 {
 		return status.Errorf(codes.InvalidArgument, "max_root_duration negative: %v", tree.MaxRootDuration)
 	}
 
+	if err := validateSequencingConfig(tree.SequencingConfig, maxRootDuration); err != nil {
+		return err
+	}
+	if err := validateRetentionPolicy(tree); err != nil {
+		return err
+	}
+	if err := validateMapRetentionPolicy(tree); err != nil {
+		return err
+	}
+
 	// Implementations may vary, so let's assume storage_settings is mutable.
 	// Other than checking that it's a valid Any there isn't much to do at this layer, though.
 	if tree.StorageSettings != nil {
@@ -154,3 +199,79 @@ func validateMutableTreeFields(ctx context.Context, tree *trillian.Tree) error {
 
 	return nil
 }
+
+// validateSequencingConfig checks c, the SequencingConfig of a tree whose
+// max_root_duration is maxRootDuration. A min_root_interval above a non-zero
+// maxRootDuration is rejected, as the two can't both be honoured.
+func validateSequencingConfig(c *trillian.SequencingConfig, maxRootDuration time.Duration) error {
+	if c == nil {
+		return nil
+	}
+	if c.BatchSize < 0 {
+		return status.Errorf(codes.InvalidArgument, "sequencing_config.batch_size negative: %v", c.BatchSize)
+	}
+	for _, d := range []struct {
+		name     string
+		duration *duration.Duration
+	}{
+		{name: "guard_window", duration: c.GuardWindow},
+		{name: "min_root_interval", duration: c.MinRootInterval},
+	} {
+		if d.duration == nil {
+			continue
+		}
+		if v, err := ptypes.Duration(d.duration); err != nil {
+			return status.Errorf(codes.InvalidArgument, "sequencing_config.%v malformed: %v", d.name, d.duration)
+		} else if v < 0 {
+			return status.Errorf(codes.InvalidArgument, "sequencing_config.%v negative: %v", d.name, d.duration)
+		}
+	}
+	if c.MinRootInterval != nil && maxRootDuration > 0 {
+		if v, _ := ptypes.Duration(c.MinRootInterval); v > maxRootDuration {
+			return status.Errorf(codes.InvalidArgument, "sequencing_config.min_root_interval %v above max_root_duration %v", v, maxRootDuration)
+		}
+	}
+	return nil
+}
+
+func validateRetentionPolicy(tree *trillian.Tree) error {
+	p := tree.RetentionPolicy
+	if p == nil {
+		return nil
+	}
+	if tree.TreeType == trillian.TreeType_MAP {
+		return status.Errorf(codes.InvalidArgument, "retention_policy not supported for tree_type: %v", tree.TreeType)
+	}
+	if p.MaxEntries < 0 {
+		return status.Errorf(codes.InvalidArgument, "retention_policy.max_entries negative: %v", p.MaxEntries)
+	}
+	if p.MaxAge != nil {
+		if v, err := ptypes.Duration(p.MaxAge); err != nil {
+			return status.Errorf(codes.InvalidArgument, "retention_policy.max_age malformed: %v", p.MaxAge)
+		} else if v < 0 {
+			return status.Errorf(codes.InvalidArgument, "retention_policy.max_age negative: %v", p.MaxAge)
+		}
+	}
+	return nil
+}
+
+func validateMapRetentionPolicy(tree *trillian.Tree) error {
+	p := tree.MapRetentionPolicy
+	if p == nil {
+		return nil
+	}
+	if tree.TreeType != trillian.TreeType_MAP {
+		return status.Errorf(codes.InvalidArgument, "map_retention_policy not supported for tree_type: %v", tree.TreeType)
+	}
+	if p.MaxRevisions < 0 {
+		return status.Errorf(codes.InvalidArgument, "map_retention_policy.max_revisions negative: %v", p.MaxRevisions)
+	}
+	if p.MaxAge != nil {
+		if v, err := ptypes.Duration(p.MaxAge); err != nil {
+			return status.Errorf(codes.InvalidArgument, "map_retention_policy.max_age malformed: %v", p.MaxAge)
+		} else if v < 0 {
+			return status.Errorf(codes.InvalidArgument, "map_retention_policy.max_age negative: %v", p.MaxAge)
+		}
+	}
+	return nil
+}
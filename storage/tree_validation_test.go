@@ -118,6 +118,28 @@ func TestValidateTreeForCreation(t *testing.T) {
 	deleteTimeTree := newTree()
 	deleteTimeTree.DeleteTime = ptypes.TimestampNow()
 
+	checkpointConfig := func(format trillian.LogRootFormat, origin string) *trillian.LogRootConfig {
+		return &trillian.LogRootConfig{Format: format, Origin: origin}
+	}
+	checkpointTree := newTree()
+	checkpointTree.LogRootConfig = checkpointConfig(trillian.LogRootFormat_LOG_ROOT_FORMAT_CHECKPOINT, "example.com/log")
+	v1Tree := newTree()
+	v1Tree.LogRootConfig = checkpointConfig(trillian.LogRootFormat_LOG_ROOT_FORMAT_V1, "")
+	v1WithOrigin := newTree()
+	v1WithOrigin.LogRootConfig = checkpointConfig(trillian.LogRootFormat_LOG_ROOT_FORMAT_V1, "example.com/log")
+	checkpointNoOrigin := newTree()
+	checkpointNoOrigin.LogRootConfig = checkpointConfig(trillian.LogRootFormat_LOG_ROOT_FORMAT_CHECKPOINT, "")
+	checkpointBadOrigin := newTree()
+	checkpointBadOrigin.LogRootConfig = checkpointConfig(trillian.LogRootFormat_LOG_ROOT_FORMAT_CHECKPOINT, "example.com log")
+	checkpointRSA := newTree()
+	checkpointRSA.SignatureAlgorithm = sigpb.DigitallySigned_RSA
+	checkpointRSA.LogRootConfig = checkpointConfig(trillian.LogRootFormat_LOG_ROOT_FORMAT_CHECKPOINT, "example.com/log")
+	checkpointMap := newTree()
+	checkpointMap.TreeType = trillian.TreeType_MAP
+	checkpointMap.LogRootConfig = checkpointConfig(trillian.LogRootFormat_LOG_ROOT_FORMAT_CHECKPOINT, "example.com/log")
+	unknownFormat := newTree()
+	unknownFormat.LogRootConfig = checkpointConfig(trillian.LogRootFormat(99), "")
+
 	tests := []struct {
 		desc    string
 		tree    *trillian.Tree
@@ -220,6 +242,44 @@ func TestValidateTreeForCreation(t *testing.T) {
 			tree:    deleteTimeTree,
 			wantErr: true,
 		},
+		{
+			desc: "checkpointTree",
+			tree: checkpointTree,
+		},
+		{
+			desc: "v1Tree",
+			tree: v1Tree,
+		},
+		{
+			desc:    "v1WithOrigin",
+			tree:    v1WithOrigin,
+			wantErr: true,
+		},
+		{
+			desc:    "checkpointNoOrigin",
+			tree:    checkpointNoOrigin,
+			wantErr: true,
+		},
+		{
+			desc:    "checkpointBadOrigin",
+			tree:    checkpointBadOrigin,
+			wantErr: true,
+		},
+		{
+			desc:    "checkpointRSA",
+			tree:    checkpointRSA,
+			wantErr: true,
+		},
+		{
+			desc:    "checkpointMap",
+			tree:    checkpointMap,
+			wantErr: true,
+		},
+		{
+			desc:    "unknownFormat",
+			tree:    unknownFormat,
+			wantErr: true,
+		},
 	}
 	for _, test := range tests {
 		err := ValidateTreeForCreation(ctx, test.tree)
@@ -285,6 +345,135 @@ func TestValidateTreeForUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			desc: "validSequencingConfig",
+			updatefn: func(tree *trillian.Tree) {
+				tree.SequencingConfig = &trillian.SequencingConfig{
+					Paused:          true,
+					BatchSize:       10,
+					GuardWindow:     ptypes.DurationProto(time.Second),
+					MinRootInterval: ptypes.DurationProto(time.Second),
+				}
+			},
+		},
+		{
+			desc: "negativeBatchSize",
+			updatefn: func(tree *trillian.Tree) {
+				tree.SequencingConfig = &trillian.SequencingConfig{BatchSize: -1}
+			},
+			wantErr: true,
+		},
+		{
+			desc: "negativeGuardWindow",
+			updatefn: func(tree *trillian.Tree) {
+				tree.SequencingConfig = &trillian.SequencingConfig{GuardWindow: ptypes.DurationProto(-time.Second)}
+			},
+			wantErr: true,
+		},
+		{
+			desc: "negativeMinRootInterval",
+			updatefn: func(tree *trillian.Tree) {
+				tree.SequencingConfig = &trillian.SequencingConfig{MinRootInterval: ptypes.DurationProto(-time.Second)}
+			},
+			wantErr: true,
+		},
+		{
+			desc: "minRootIntervalWithinMaxRootDuration",
+			updatefn: func(tree *trillian.Tree) {
+				tree.MaxRootDuration = ptypes.DurationProto(time.Hour)
+				tree.SequencingConfig = &trillian.SequencingConfig{MinRootInterval: ptypes.DurationProto(time.Hour)}
+			},
+		},
+		{
+			desc: "minRootIntervalAboveMaxRootDuration",
+			updatefn: func(tree *trillian.Tree) {
+				tree.MaxRootDuration = ptypes.DurationProto(time.Minute)
+				tree.SequencingConfig = &trillian.SequencingConfig{MinRootInterval: ptypes.DurationProto(time.Hour)}
+			},
+			wantErr: true,
+		},
+		{
+			// A zero max_root_duration doesn't force any roots.
+			desc: "minRootIntervalNoMaxRootDuration",
+			updatefn: func(tree *trillian.Tree) {
+				tree.MaxRootDuration = ptypes.DurationProto(0)
+				tree.SequencingConfig = &trillian.SequencingConfig{MinRootInterval: ptypes.DurationProto(time.Hour)}
+			},
+		},
+		{
+			desc: "validRetentionPolicy",
+			updatefn: func(tree *trillian.Tree) {
+				tree.RetentionPolicy = &trillian.RetentionPolicy{
+					MaxEntries: 1000,
+					MaxAge:     ptypes.DurationProto(24 * time.Hour),
+				}
+			},
+		},
+		{
+			desc: "negativeMaxEntries",
+			updatefn: func(tree *trillian.Tree) {
+				tree.RetentionPolicy = &trillian.RetentionPolicy{MaxEntries: -1}
+			},
+			wantErr: true,
+		},
+		{
+			desc: "negativeMaxAge",
+			updatefn: func(tree *trillian.Tree) {
+				tree.RetentionPolicy = &trillian.RetentionPolicy{MaxAge: ptypes.DurationProto(-time.Second)}
+			},
+			wantErr: true,
+		},
+		{
+			desc:     "mapRetentionPolicy",
+			treeType: trillian.TreeType_MAP,
+			updatefn: func(tree *trillian.Tree) {
+				tree.RetentionPolicy = &trillian.RetentionPolicy{MaxEntries: 1000}
+			},
+			wantErr: true,
+		},
+		{
+			desc:     "validMapRetentionPolicy",
+			treeType: trillian.TreeType_MAP,
+			updatefn: func(tree *trillian.Tree) {
+				tree.MapRetentionPolicy = &trillian.MapRetentionPolicy{
+					MaxRevisions: 100,
+					MaxAge:       ptypes.DurationProto(24 * time.Hour),
+				}
+			},
+		},
+		{
+			desc:     "negativeMaxRevisions",
+			treeType: trillian.TreeType_MAP,
+			updatefn: func(tree *trillian.Tree) {
+				tree.MapRetentionPolicy = &trillian.MapRetentionPolicy{MaxRevisions: -1}
+			},
+			wantErr: true,
+		},
+		{
+			desc:     "negativeMapMaxAge",
+			treeType: trillian.TreeType_MAP,
+			updatefn: func(tree *trillian.Tree) {
+				tree.MapRetentionPolicy = &trillian.MapRetentionPolicy{MaxAge: ptypes.DurationProto(-time.Second)}
+			},
+			wantErr: true,
+		},
+		{
+			desc: "logMapRetentionPolicy",
+			updatefn: func(tree *trillian.Tree) {
+				tree.MapRetentionPolicy = &trillian.MapRetentionPolicy{MaxRevisions: 100}
+			},
+			wantErr: true,
+		},
+		{
+			desc: "logRootConfig",
+			updatefn: func(tree *trillian.Tree) {
+				tree.LogRootConfig = &trillian.LogRootConfig{
+					Format: trillian.LogRootFormat_LOG_ROOT_FORMAT_CHECKPOINT,
+					Origin: "example.com/log",
+				}
+			},
+			wantErr: true,
+		},
 		{
 			desc: "differentPrivateKeyProtoButSameKeyMaterial",
 			updatefn: func(tree *trillian.Tree) {
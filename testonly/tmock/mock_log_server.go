@@ -64,6 +64,21 @@ func (mr *MockTrillianLogServerMockRecorder) AddSequencedLeaves(arg0, arg1 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSequencedLeaves", reflect.TypeOf((*MockTrillianLogServer)(nil).AddSequencedLeaves), arg0, arg1)
 }
 
+// GetBatchInclusionProof mocks base method
+func (m *MockTrillianLogServer) GetBatchInclusionProof(arg0 context.Context, arg1 *trillian.GetBatchInclusionProofRequest) (*trillian.GetBatchInclusionProofResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBatchInclusionProof", arg0, arg1)
+	ret0, _ := ret[0].(*trillian.GetBatchInclusionProofResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBatchInclusionProof indicates an expected call of GetBatchInclusionProof
+func (mr *MockTrillianLogServerMockRecorder) GetBatchInclusionProof(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBatchInclusionProof", reflect.TypeOf((*MockTrillianLogServer)(nil).GetBatchInclusionProof), arg0, arg1)
+}
+
 // GetConsistencyProof mocks base method
 func (m *MockTrillianLogServer) GetConsistencyProof(arg0 context.Context, arg1 *trillian.GetConsistencyProofRequest) (*trillian.GetConsistencyProofResponse, error) {
 	m.ctrl.T.Helper()
@@ -184,6 +199,21 @@ func (mr *MockTrillianLogServerMockRecorder) GetLeavesByRange(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeavesByRange", reflect.TypeOf((*MockTrillianLogServer)(nil).GetLeavesByRange), arg0, arg1)
 }
 
+// GetLeavesByRangeWithProof mocks base method
+func (m *MockTrillianLogServer) GetLeavesByRangeWithProof(arg0 context.Context, arg1 *trillian.GetLeavesByRangeWithProofRequest) (*trillian.GetLeavesByRangeWithProofResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLeavesByRangeWithProof", arg0, arg1)
+	ret0, _ := ret[0].(*trillian.GetLeavesByRangeWithProofResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLeavesByRangeWithProof indicates an expected call of GetLeavesByRangeWithProof
+func (mr *MockTrillianLogServerMockRecorder) GetLeavesByRangeWithProof(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeavesByRangeWithProof", reflect.TypeOf((*MockTrillianLogServer)(nil).GetLeavesByRangeWithProof), arg0, arg1)
+}
+
 // GetSequencedLeafCount mocks base method
 func (m *MockTrillianLogServer) GetSequencedLeafCount(arg0 context.Context, arg1 *trillian.GetSequencedLeafCountRequest) (*trillian.GetSequencedLeafCountResponse, error) {
 	m.ctrl.T.Helper()
@@ -243,3 +273,17 @@ func (mr *MockTrillianLogServerMockRecorder) QueueLeaves(arg0, arg1 interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueLeaves", reflect.TypeOf((*MockTrillianLogServer)(nil).QueueLeaves), arg0, arg1)
 }
+
+// WatchLeaves mocks base method
+func (m *MockTrillianLogServer) WatchLeaves(arg0 *trillian.WatchLeavesRequest, arg1 trillian.TrillianLog_WatchLeavesServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchLeaves", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WatchLeaves indicates an expected call of WatchLeaves
+func (mr *MockTrillianLogServerMockRecorder) WatchLeaves(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchLeaves", reflect.TypeOf((*MockTrillianLogServer)(nil).WatchLeaves), arg0, arg1)
+}
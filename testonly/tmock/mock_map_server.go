@@ -79,6 +79,21 @@ func (mr *MockTrillianMapServerMockRecorder) GetLeafByRevision(arg0, arg1 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeafByRevision", reflect.TypeOf((*MockTrillianMapServer)(nil).GetLeafByRevision), arg0, arg1)
 }
 
+// GetLeafHistory mocks base method
+func (m *MockTrillianMapServer) GetLeafHistory(arg0 context.Context, arg1 *trillian.GetLeafHistoryRequest) (*trillian.GetLeafHistoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLeafHistory", arg0, arg1)
+	ret0, _ := ret[0].(*trillian.GetLeafHistoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLeafHistory indicates an expected call of GetLeafHistory
+func (mr *MockTrillianMapServerMockRecorder) GetLeafHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeafHistory", reflect.TypeOf((*MockTrillianMapServer)(nil).GetLeafHistory), arg0, arg1)
+}
+
 // GetLeaves mocks base method
 func (m *MockTrillianMapServer) GetLeaves(arg0 context.Context, arg1 *trillian.GetMapLeavesRequest) (*trillian.GetMapLeavesResponse, error) {
 	m.ctrl.T.Helper()
@@ -124,6 +139,21 @@ func (mr *MockTrillianMapServerMockRecorder) GetLeavesByRevisionNoProof(arg0, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeavesByRevisionNoProof", reflect.TypeOf((*MockTrillianMapServer)(nil).GetLeavesByRevisionNoProof), arg0, arg1)
 }
 
+// GetRevisionDiff mocks base method
+func (m *MockTrillianMapServer) GetRevisionDiff(arg0 context.Context, arg1 *trillian.GetRevisionDiffRequest) (*trillian.GetRevisionDiffResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisionDiff", arg0, arg1)
+	ret0, _ := ret[0].(*trillian.GetRevisionDiffResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevisionDiff indicates an expected call of GetRevisionDiff
+func (mr *MockTrillianMapServerMockRecorder) GetRevisionDiff(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisionDiff", reflect.TypeOf((*MockTrillianMapServer)(nil).GetRevisionDiff), arg0, arg1)
+}
+
 // GetSignedMapRoot mocks base method
 func (m *MockTrillianMapServer) GetSignedMapRoot(arg0 context.Context, arg1 *trillian.GetSignedMapRootRequest) (*trillian.GetSignedMapRootResponse, error) {
 	m.ctrl.T.Helper()
@@ -169,6 +199,21 @@ func (mr *MockTrillianMapServerMockRecorder) InitMap(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitMap", reflect.TypeOf((*MockTrillianMapServer)(nil).InitMap), arg0, arg1)
 }
 
+// ListLeavesByRevision mocks base method
+func (m *MockTrillianMapServer) ListLeavesByRevision(arg0 context.Context, arg1 *trillian.ListMapLeavesByRevisionRequest) (*trillian.ListMapLeavesByRevisionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLeavesByRevision", arg0, arg1)
+	ret0, _ := ret[0].(*trillian.ListMapLeavesByRevisionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLeavesByRevision indicates an expected call of ListLeavesByRevision
+func (mr *MockTrillianMapServerMockRecorder) ListLeavesByRevision(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLeavesByRevision", reflect.TypeOf((*MockTrillianMapServer)(nil).ListLeavesByRevision), arg0, arg1)
+}
+
 // SetLeaves mocks base method
 func (m *MockTrillianMapServer) SetLeaves(arg0 context.Context, arg1 *trillian.SetMapLeavesRequest) (*trillian.SetMapLeavesResponse, error) {
 	m.ctrl.T.Helper()
@@ -212,7 +212,12 @@ func Signer(ctx context.Context, tree *trillian.Tree) (*tcrypto.Signer, error) {
 		return nil, fmt.Errorf("%s signature not supported by signer of type %T", tree.SignatureAlgorithm, signer)
 	}
 
-	return tcrypto.NewSigner(tree.GetTreeId(), signer, hash), nil
+	s := tcrypto.NewSigner(tree.GetTreeId(), signer, hash)
+	if c := tree.LogRootConfig; c != nil {
+		s.LogRootFormat = c.Format
+		s.CheckpointOrigin = c.Origin
+	}
+	return s, nil
 }
 
 func spanFor(ctx context.Context, name string) (context.Context, func()) {
@@ -32,16 +32,22 @@ type LogRootFormat int32
 const (
 	LogRootFormat_LOG_ROOT_FORMAT_UNKNOWN LogRootFormat = 0
 	LogRootFormat_LOG_ROOT_FORMAT_V1      LogRootFormat = 1
+	// A checkpoint: the text of a signed note holding the origin of the log, the
+	// tree size, the root hash and extension lines (see the types package).
+	// Its signature is a note signature, made with an Ed25519 or ECDSA key.
+	LogRootFormat_LOG_ROOT_FORMAT_CHECKPOINT LogRootFormat = 2
 )
 
 var LogRootFormat_name = map[int32]string{
 	0: "LOG_ROOT_FORMAT_UNKNOWN",
 	1: "LOG_ROOT_FORMAT_V1",
+	2: "LOG_ROOT_FORMAT_CHECKPOINT",
 }
 
 var LogRootFormat_value = map[string]int32{
-	"LOG_ROOT_FORMAT_UNKNOWN": 0,
-	"LOG_ROOT_FORMAT_V1":      1,
+	"LOG_ROOT_FORMAT_UNKNOWN":    0,
+	"LOG_ROOT_FORMAT_V1":         1,
+	"LOG_ROOT_FORMAT_CHECKPOINT": 2,
 }
 
 func (x LogRootFormat) String() string {
@@ -79,6 +85,64 @@ func (MapRootFormat) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_364603a4e17a2a56, []int{1}
 }
 
+// EntryTimestampFormat specifies the fields that are covered by the
+// SignedEntryTimestamp signature, as well as their ordering and formats.
+// Its values don't overlap with those of LogRootFormat, so that a signed entry
+// timestamp can't be mistaken for a signed log root.
+type EntryTimestampFormat int32
+
+const (
+	EntryTimestampFormat_ENTRY_TIMESTAMP_FORMAT_UNKNOWN EntryTimestampFormat = 0
+	EntryTimestampFormat_ENTRY_TIMESTAMP_FORMAT_V1      EntryTimestampFormat = 256
+)
+
+var EntryTimestampFormat_name = map[int32]string{
+	0:   "ENTRY_TIMESTAMP_FORMAT_UNKNOWN",
+	256: "ENTRY_TIMESTAMP_FORMAT_V1",
+}
+
+var EntryTimestampFormat_value = map[string]int32{
+	"ENTRY_TIMESTAMP_FORMAT_UNKNOWN": 0,
+	"ENTRY_TIMESTAMP_FORMAT_V1":      256,
+}
+
+func (x EntryTimestampFormat) String() string {
+	return proto.EnumName(EntryTimestampFormat_name, int32(x))
+}
+
+func (EntryTimestampFormat) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_364603a4e17a2a56, []int{2}
+}
+
+// CosignatureFormat specifies the fields that are covered by the
+// LogRootCosignature signature, as well as their ordering and formats.
+// Its values don't overlap with those of LogRootFormat or
+// EntryTimestampFormat, so that a cosignature can't be mistaken for either.
+type CosignatureFormat int32
+
+const (
+	CosignatureFormat_COSIGNATURE_FORMAT_UNKNOWN CosignatureFormat = 0
+	CosignatureFormat_COSIGNATURE_FORMAT_V1      CosignatureFormat = 512
+)
+
+var CosignatureFormat_name = map[int32]string{
+	0:   "COSIGNATURE_FORMAT_UNKNOWN",
+	512: "COSIGNATURE_FORMAT_V1",
+}
+
+var CosignatureFormat_value = map[string]int32{
+	"COSIGNATURE_FORMAT_UNKNOWN": 0,
+	"COSIGNATURE_FORMAT_V1":      512,
+}
+
+func (x CosignatureFormat) String() string {
+	return proto.EnumName(CosignatureFormat_name, int32(x))
+}
+
+func (CosignatureFormat) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_364603a4e17a2a56, []int{3}
+}
+
 // Defines the way empty / node / leaf hashes are constructed incorporating
 // preimage protection, which can be application specific.
 type HashStrategy int32
@@ -126,7 +190,7 @@ func (x HashStrategy) String() string {
 }
 
 func (HashStrategy) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_364603a4e17a2a56, []int{2}
+	return fileDescriptor_364603a4e17a2a56, []int{4}
 }
 
 // State of the tree.
@@ -175,7 +239,7 @@ func (x TreeState) String() string {
 }
 
 func (TreeState) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_364603a4e17a2a56, []int{3}
+	return fileDescriptor_364603a4e17a2a56, []int{5}
 }
 
 // Type of the tree.
@@ -213,7 +277,7 @@ func (x TreeType) String() string {
 }
 
 func (TreeType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_364603a4e17a2a56, []int{4}
+	return fileDescriptor_364603a4e17a2a56, []int{6}
 }
 
 // Represents a tree, which may be either a verifiable log or map.
@@ -281,10 +345,27 @@ type Tree struct {
 	Deleted bool `protobuf:"varint,19,opt,name=deleted,proto3" json:"deleted,omitempty"`
 	// Time of tree deletion, if any.
 	// Readonly.
-	DeleteTime           *timestamp.Timestamp `protobuf:"bytes,20,opt,name=delete_time,json=deleteTime,proto3" json:"delete_time,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	DeleteTime *timestamp.Timestamp `protobuf:"bytes,20,opt,name=delete_time,json=deleteTime,proto3" json:"delete_time,omitempty"`
+	// Per-tree settings of the log signer, overriding its defaults.
+	// Optional, only used by LOG and PREORDERED_LOG trees.
+	SequencingConfig *SequencingConfig `protobuf:"bytes,21,opt,name=sequencing_config,json=sequencingConfig,proto3" json:"sequencing_config,omitempty"`
+	// Retention policy for the leaf data of the tree.  Leaf values and extra
+	// data of entries matching the policy are dropped by the log signer, while
+	// their Merkle hashes are kept so that proofs can still be served.
+	// Optional, only used by LOG and PREORDERED_LOG trees.
+	RetentionPolicy *RetentionPolicy `protobuf:"bytes,22,opt,name=retention_policy,json=retentionPolicy,proto3" json:"retention_policy,omitempty"`
+	// Format of the SignedLogRoots of the tree. It can only be set when the tree
+	// is created. If unset, LOG_ROOT_FORMAT_V1 is used.
+	// Optional, only used by LOG and PREORDERED_LOG trees.
+	LogRootConfig *LogRootConfig `protobuf:"bytes,23,opt,name=log_root_config,json=logRootConfig,proto3" json:"log_root_config,omitempty"`
+	// Retention policy for the revisions of the tree. Revisions outside of the
+	// policy are garbage collected by the map server, and reads of collected
+	// revisions fail with OUT_OF_RANGE.
+	// Optional, only used by MAP trees.
+	MapRetentionPolicy   *MapRetentionPolicy `protobuf:"bytes,24,opt,name=map_retention_policy,json=mapRetentionPolicy,proto3" json:"map_retention_policy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
 }
 
 func (m *Tree) Reset()         { *m = Tree{} }
@@ -424,20 +505,297 @@ func (m *Tree) GetDeleteTime() *timestamp.Timestamp {
 	return nil
 }
 
+func (m *Tree) GetSequencingConfig() *SequencingConfig {
+	if m != nil {
+		return m.SequencingConfig
+	}
+	return nil
+}
+
+func (m *Tree) GetRetentionPolicy() *RetentionPolicy {
+	if m != nil {
+		return m.RetentionPolicy
+	}
+	return nil
+}
+
+func (m *Tree) GetLogRootConfig() *LogRootConfig {
+	if m != nil {
+		return m.LogRootConfig
+	}
+	return nil
+}
+
+func (m *Tree) GetMapRetentionPolicy() *MapRetentionPolicy {
+	if m != nil {
+		return m.MapRetentionPolicy
+	}
+	return nil
+}
+
+// SequencingConfig holds the settings used by the log signer when integrating
+// queued leaves into a tree. Unset fields fall back to the defaults the signer
+// was started with.
+type SequencingConfig struct {
+	// If true, sequencing of the tree is paused: queued leaves aren't integrated
+	// and no new roots are signed (not even after max_root_duration).
+	// Leaves may still be queued while sequencing is paused.
+	Paused bool `protobuf:"varint,1,opt,name=paused,proto3" json:"paused,omitempty"`
+	// Maximum number of leaves integrated into the tree in a single signing run.
+	// If zero, the signer's default batch size is used.
+	BatchSize int32 `protobuf:"varint,2,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`
+	// Leaves queued more recently than guard_window aren't integrated yet.
+	// If unset, the signer's default guard window is used.
+	GuardWindow *duration.Duration `protobuf:"bytes,3,opt,name=guard_window,json=guardWindow,proto3" json:"guard_window,omitempty"`
+	// Minimum interval between two consecutive roots of the tree. Signing runs
+	// are skipped until min_root_interval has elapsed since the latest root,
+	// which lets a busy tree integrate bigger batches less often.
+	// If unset or zero, a new root may be signed on every signing run.
+	// Must not exceed the max_root_duration of the tree, unless that is zero.
+	MinRootInterval      *duration.Duration `protobuf:"bytes,4,opt,name=min_root_interval,json=minRootInterval,proto3" json:"min_root_interval,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *SequencingConfig) Reset()         { *m = SequencingConfig{} }
+func (m *SequencingConfig) String() string { return proto.CompactTextString(m) }
+func (*SequencingConfig) ProtoMessage()    {}
+func (*SequencingConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_364603a4e17a2a56, []int{1}
+}
+
+func (m *SequencingConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SequencingConfig.Unmarshal(m, b)
+}
+func (m *SequencingConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SequencingConfig.Marshal(b, m, deterministic)
+}
+func (m *SequencingConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SequencingConfig.Merge(m, src)
+}
+func (m *SequencingConfig) XXX_Size() int {
+	return xxx_messageInfo_SequencingConfig.Size(m)
+}
+func (m *SequencingConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_SequencingConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SequencingConfig proto.InternalMessageInfo
+
+func (m *SequencingConfig) GetPaused() bool {
+	if m != nil {
+		return m.Paused
+	}
+	return false
+}
+
+func (m *SequencingConfig) GetBatchSize() int32 {
+	if m != nil {
+		return m.BatchSize
+	}
+	return 0
+}
+
+func (m *SequencingConfig) GetGuardWindow() *duration.Duration {
+	if m != nil {
+		return m.GuardWindow
+	}
+	return nil
+}
+
+func (m *SequencingConfig) GetMinRootInterval() *duration.Duration {
+	if m != nil {
+		return m.MinRootInterval
+	}
+	return nil
+}
+
+// RetentionPolicy describes which leaves of a log have their leaf_value and
+// extra_data pruned.  Pruning always proceeds from the oldest leaf onwards: a
+// leaf is pruned if it is older than max_age, or if it is not among the
+// latest max_entries leaves of the tree.  Unset (zero) limits are ignored.
+type RetentionPolicy struct {
+	// Number of most recent leaves whose data is retained.
+	MaxEntries int64 `protobuf:"varint,1,opt,name=max_entries,json=maxEntries,proto3" json:"max_entries,omitempty"`
+	// Maximum age of the retained leaf data, measured from the later of the
+	// leaf's queue_timestamp and integrate_timestamp.
+	MaxAge               *duration.Duration `protobuf:"bytes,2,opt,name=max_age,json=maxAge,proto3" json:"max_age,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *RetentionPolicy) Reset()         { *m = RetentionPolicy{} }
+func (m *RetentionPolicy) String() string { return proto.CompactTextString(m) }
+func (*RetentionPolicy) ProtoMessage()    {}
+func (*RetentionPolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_364603a4e17a2a56, []int{2}
+}
+
+func (m *RetentionPolicy) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RetentionPolicy.Unmarshal(m, b)
+}
+func (m *RetentionPolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RetentionPolicy.Marshal(b, m, deterministic)
+}
+func (m *RetentionPolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RetentionPolicy.Merge(m, src)
+}
+func (m *RetentionPolicy) XXX_Size() int {
+	return xxx_messageInfo_RetentionPolicy.Size(m)
+}
+func (m *RetentionPolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_RetentionPolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RetentionPolicy proto.InternalMessageInfo
+
+func (m *RetentionPolicy) GetMaxEntries() int64 {
+	if m != nil {
+		return m.MaxEntries
+	}
+	return 0
+}
+
+func (m *RetentionPolicy) GetMaxAge() *duration.Duration {
+	if m != nil {
+		return m.MaxAge
+	}
+	return nil
+}
+
+// MapRetentionPolicy describes which revisions of a map are garbage collected.
+// A revision is collected if it is older than max_age, or if it is not among
+// the latest max_revisions revisions of the map.  Unset (zero) limits are
+// ignored, and the latest revision is never collected.  Collection removes the
+// map root of the revision, and the versions of leaves and subtrees which are
+// superseded at the oldest retained revision.
+type MapRetentionPolicy struct {
+	// Number of most recent revisions which are retained.
+	MaxRevisions int64 `protobuf:"varint,1,opt,name=max_revisions,json=maxRevisions,proto3" json:"max_revisions,omitempty"`
+	// Maximum age of the retained revisions, measured from the timestamp of
+	// their map root.
+	MaxAge               *duration.Duration `protobuf:"bytes,2,opt,name=max_age,json=maxAge,proto3" json:"max_age,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *MapRetentionPolicy) Reset()         { *m = MapRetentionPolicy{} }
+func (m *MapRetentionPolicy) String() string { return proto.CompactTextString(m) }
+func (*MapRetentionPolicy) ProtoMessage()    {}
+func (*MapRetentionPolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_364603a4e17a2a56, []int{3}
+}
+
+func (m *MapRetentionPolicy) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MapRetentionPolicy.Unmarshal(m, b)
+}
+func (m *MapRetentionPolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MapRetentionPolicy.Marshal(b, m, deterministic)
+}
+func (m *MapRetentionPolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MapRetentionPolicy.Merge(m, src)
+}
+func (m *MapRetentionPolicy) XXX_Size() int {
+	return xxx_messageInfo_MapRetentionPolicy.Size(m)
+}
+func (m *MapRetentionPolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_MapRetentionPolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MapRetentionPolicy proto.InternalMessageInfo
+
+func (m *MapRetentionPolicy) GetMaxRevisions() int64 {
+	if m != nil {
+		return m.MaxRevisions
+	}
+	return 0
+}
+
+func (m *MapRetentionPolicy) GetMaxAge() *duration.Duration {
+	if m != nil {
+		return m.MaxAge
+	}
+	return nil
+}
+
+// LogRootConfig selects the format of the SignedLogRoots of a log.
+type LogRootConfig struct {
+	// Format of the log roots. LOG_ROOT_FORMAT_UNKNOWN means LOG_ROOT_FORMAT_V1.
+	Format LogRootFormat `protobuf:"varint,1,opt,name=format,proto3,enum=trillian.LogRootFormat" json:"format,omitempty"`
+	// Origin of the checkpoints of the log, i.e. their first line, which should
+	// uniquely identify the log, e.g. "example.com/log". It's also the key name
+	// in the signature line of checkpoint notes, so it may not contain spaces or
+	// "+" characters. Required by LOG_ROOT_FORMAT_CHECKPOINT.
+	Origin               string   `protobuf:"bytes,2,opt,name=origin,proto3" json:"origin,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogRootConfig) Reset()         { *m = LogRootConfig{} }
+func (m *LogRootConfig) String() string { return proto.CompactTextString(m) }
+func (*LogRootConfig) ProtoMessage()    {}
+func (*LogRootConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_364603a4e17a2a56, []int{4}
+}
+
+func (m *LogRootConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogRootConfig.Unmarshal(m, b)
+}
+func (m *LogRootConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogRootConfig.Marshal(b, m, deterministic)
+}
+func (m *LogRootConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogRootConfig.Merge(m, src)
+}
+func (m *LogRootConfig) XXX_Size() int {
+	return xxx_messageInfo_LogRootConfig.Size(m)
+}
+func (m *LogRootConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogRootConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogRootConfig proto.InternalMessageInfo
+
+func (m *LogRootConfig) GetFormat() LogRootFormat {
+	if m != nil {
+		return m.Format
+	}
+	return LogRootFormat_LOG_ROOT_FORMAT_UNKNOWN
+}
+
+func (m *LogRootConfig) GetOrigin() string {
+	if m != nil {
+		return m.Origin
+	}
+	return ""
+}
+
+// SignedEntryTimestamp is a promise by a Log to incorporate a leaf into a
+// SignedLogRoot within the Log's max_root_duration of timestamp_nanos. The
+// signature covers the TLS serialization of an EntryTimestamp with the
+// ENTRY_TIMESTAMP_FORMAT_V1 tag (see the types package), and is made with the
+// Log's private key.
 type SignedEntryTimestamp struct {
-	TimestampNanos       int64                  `protobuf:"varint,1,opt,name=timestamp_nanos,json=timestampNanos,proto3" json:"timestamp_nanos,omitempty"`
-	LogId                int64                  `protobuf:"varint,2,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
-	Signature            *sigpb.DigitallySigned `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
-	XXX_unrecognized     []byte                 `json:"-"`
-	XXX_sizecache        int32                  `json:"-"`
+	// timestamp_nanos is the time at which the leaf was queued.
+	TimestampNanos int64                  `protobuf:"varint,1,opt,name=timestamp_nanos,json=timestampNanos,proto3" json:"timestamp_nanos,omitempty"`
+	LogId          int64                  `protobuf:"varint,2,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	Signature      *sigpb.DigitallySigned `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	// leaf_identity_hash identifies the leaf that's promised to be incorporated.
+	LeafIdentityHash     []byte   `protobuf:"bytes,4,opt,name=leaf_identity_hash,json=leafIdentityHash,proto3" json:"leaf_identity_hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *SignedEntryTimestamp) Reset()         { *m = SignedEntryTimestamp{} }
 func (m *SignedEntryTimestamp) String() string { return proto.CompactTextString(m) }
 func (*SignedEntryTimestamp) ProtoMessage()    {}
 func (*SignedEntryTimestamp) Descriptor() ([]byte, []int) {
-	return fileDescriptor_364603a4e17a2a56, []int{1}
+	return fileDescriptor_364603a4e17a2a56, []int{5}
 }
 
 func (m *SignedEntryTimestamp) XXX_Unmarshal(b []byte) error {
@@ -479,6 +837,84 @@ func (m *SignedEntryTimestamp) GetSignature() *sigpb.DigitallySigned {
 	return nil
 }
 
+func (m *SignedEntryTimestamp) GetLeafIdentityHash() []byte {
+	if m != nil {
+		return m.LeafIdentityHash
+	}
+	return nil
+}
+
+// LogRootCosignature is a statement by a witness that it has verified a
+// SignedLogRoot of a Log, and found it consistent with every root of the Log
+// it had verified before. The signature covers the TLS serialization of a
+// Cosignature with the COSIGNATURE_FORMAT_V1 tag (see the types package),
+// which includes the log_root bytes of the SignedLogRoot, and is made with the
+// witness's private key.
+type LogRootCosignature struct {
+	// witness_id identifies the witness which made the cosignature.
+	WitnessId string `protobuf:"bytes,1,opt,name=witness_id,json=witnessId,proto3" json:"witness_id,omitempty"`
+	LogId     int64  `protobuf:"varint,2,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	// timestamp_nanos is the time at which the witness verified the root.
+	TimestampNanos       int64                  `protobuf:"varint,3,opt,name=timestamp_nanos,json=timestampNanos,proto3" json:"timestamp_nanos,omitempty"`
+	Signature            *sigpb.DigitallySigned `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *LogRootCosignature) Reset()         { *m = LogRootCosignature{} }
+func (m *LogRootCosignature) String() string { return proto.CompactTextString(m) }
+func (*LogRootCosignature) ProtoMessage()    {}
+func (*LogRootCosignature) Descriptor() ([]byte, []int) {
+	return fileDescriptor_364603a4e17a2a56, []int{6}
+}
+
+func (m *LogRootCosignature) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogRootCosignature.Unmarshal(m, b)
+}
+func (m *LogRootCosignature) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogRootCosignature.Marshal(b, m, deterministic)
+}
+func (m *LogRootCosignature) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogRootCosignature.Merge(m, src)
+}
+func (m *LogRootCosignature) XXX_Size() int {
+	return xxx_messageInfo_LogRootCosignature.Size(m)
+}
+func (m *LogRootCosignature) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogRootCosignature.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogRootCosignature proto.InternalMessageInfo
+
+func (m *LogRootCosignature) GetWitnessId() string {
+	if m != nil {
+		return m.WitnessId
+	}
+	return ""
+}
+
+func (m *LogRootCosignature) GetLogId() int64 {
+	if m != nil {
+		return m.LogId
+	}
+	return 0
+}
+
+func (m *LogRootCosignature) GetTimestampNanos() int64 {
+	if m != nil {
+		return m.TimestampNanos
+	}
+	return 0
+}
+
+func (m *LogRootCosignature) GetSignature() *sigpb.DigitallySigned {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
 // SignedLogRoot represents a commitment by a Log to a particular tree.
 type SignedLogRoot struct {
 	// key_hint is a hint to identify the public key for signature verification.
@@ -493,6 +929,8 @@ type SignedLogRoot struct {
 	// log_root holds the TLS-serialization of the following structure (described
 	// in RFC5246 notation): Clients should validate log_root_signature with
 	// VerifySignedLogRoot before deserializing log_root.
+	// Logs whose LogRootConfig selects LOG_ROOT_FORMAT_CHECKPOINT hold the text
+	// of a checkpoint instead, see the types package.
 	// enum { v1(1), (65535)} Version;
 	// struct {
 	//   uint64 tree_size;
@@ -535,7 +973,7 @@ func (m *SignedLogRoot) Reset()         { *m = SignedLogRoot{} }
 func (m *SignedLogRoot) String() string { return proto.CompactTextString(m) }
 func (*SignedLogRoot) ProtoMessage()    {}
 func (*SignedLogRoot) Descriptor() ([]byte, []int) {
-	return fileDescriptor_364603a4e17a2a56, []int{2}
+	return fileDescriptor_364603a4e17a2a56, []int{7}
 }
 
 func (m *SignedLogRoot) XXX_Unmarshal(b []byte) error {
@@ -607,7 +1045,7 @@ func (m *SignedMapRoot) Reset()         { *m = SignedMapRoot{} }
 func (m *SignedMapRoot) String() string { return proto.CompactTextString(m) }
 func (*SignedMapRoot) ProtoMessage()    {}
 func (*SignedMapRoot) Descriptor() ([]byte, []int) {
-	return fileDescriptor_364603a4e17a2a56, []int{3}
+	return fileDescriptor_364603a4e17a2a56, []int{8}
 }
 
 func (m *SignedMapRoot) XXX_Unmarshal(b []byte) error {
@@ -645,11 +1083,18 @@ func (m *SignedMapRoot) GetSignature() []byte {
 func init() {
 	proto.RegisterEnum("trillian.LogRootFormat", LogRootFormat_name, LogRootFormat_value)
 	proto.RegisterEnum("trillian.MapRootFormat", MapRootFormat_name, MapRootFormat_value)
+	proto.RegisterEnum("trillian.EntryTimestampFormat", EntryTimestampFormat_name, EntryTimestampFormat_value)
+	proto.RegisterEnum("trillian.CosignatureFormat", CosignatureFormat_name, CosignatureFormat_value)
 	proto.RegisterEnum("trillian.HashStrategy", HashStrategy_name, HashStrategy_value)
 	proto.RegisterEnum("trillian.TreeState", TreeState_name, TreeState_value)
 	proto.RegisterEnum("trillian.TreeType", TreeType_name, TreeType_value)
 	proto.RegisterType((*Tree)(nil), "trillian.Tree")
+	proto.RegisterType((*SequencingConfig)(nil), "trillian.SequencingConfig")
+	proto.RegisterType((*RetentionPolicy)(nil), "trillian.RetentionPolicy")
+	proto.RegisterType((*MapRetentionPolicy)(nil), "trillian.MapRetentionPolicy")
+	proto.RegisterType((*LogRootConfig)(nil), "trillian.LogRootConfig")
 	proto.RegisterType((*SignedEntryTimestamp)(nil), "trillian.SignedEntryTimestamp")
+	proto.RegisterType((*LogRootCosignature)(nil), "trillian.LogRootCosignature")
 	proto.RegisterType((*SignedLogRoot)(nil), "trillian.SignedLogRoot")
 	proto.RegisterType((*SignedMapRoot)(nil), "trillian.SignedMapRoot")
 }
@@ -657,71 +1102,99 @@ func init() {
 func init() { proto.RegisterFile("trillian.proto", fileDescriptor_364603a4e17a2a56) }
 
 var fileDescriptor_364603a4e17a2a56 = []byte{
-	// 1052 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x55, 0x5b, 0x6f, 0xe2, 0x46,
-	0x14, 0x5e, 0x83, 0x01, 0x33, 0x5c, 0x32, 0x99, 0xec, 0xc5, 0xa1, 0x55, 0x97, 0x46, 0x95, 0x4a,
-	0xa3, 0x8a, 0x74, 0x69, 0x13, 0xa9, 0xda, 0x87, 0xca, 0x09, 0x4e, 0x80, 0x24, 0x80, 0xc6, 0xee,
-	0x56, 0x9b, 0x97, 0x91, 0x81, 0xa9, 0xb1, 0xe2, 0x9b, 0xec, 0xa1, 0x5a, 0xff, 0x84, 0xaa, 0x7d,
-	0xdf, 0xbf, 0x5b, 0xcd, 0xf8, 0x42, 0x42, 0x76, 0xbb, 0x2f, 0xc9, 0x9c, 0xf3, 0x5d, 0xce, 0x19,
-	0xcf, 0x19, 0x06, 0xb4, 0x59, 0xe4, 0xb8, 0xae, 0x63, 0xf9, 0xfd, 0x30, 0x0a, 0x58, 0x80, 0x94,
-	0x3c, 0xee, 0x74, 0x96, 0x51, 0x12, 0xb2, 0xe0, 0xe4, 0x9e, 0x26, 0x71, 0xb8, 0xc8, 0xfe, 0xa5,
-	0xac, 0x8e, 0x9a, 0x61, 0xb1, 0x63, 0x87, 0x8b, 0xf4, 0x6f, 0x86, 0x1c, 0xda, 0x41, 0x60, 0xbb,
-	0xf4, 0x44, 0x44, 0x8b, 0xcd, 0x9f, 0x27, 0x96, 0x9f, 0x64, 0xd0, 0x37, 0xbb, 0xd0, 0x6a, 0x13,
-	0x59, 0xcc, 0x09, 0xb2, 0xd2, 0x9d, 0xd7, 0xbb, 0x38, 0x73, 0x3c, 0x1a, 0x33, 0xcb, 0x0b, 0x53,
-	0xc2, 0xd1, 0xdf, 0x35, 0x20, 0x9b, 0x11, 0xa5, 0xe8, 0x15, 0xa8, 0xb1, 0x88, 0x52, 0xe2, 0xac,
-	0x54, 0xa9, 0x2b, 0xf5, 0xca, 0xb8, 0xca, 0xc3, 0xf1, 0x0a, 0x0d, 0x00, 0x10, 0x40, 0xcc, 0x2c,
-	0x46, 0xd5, 0x52, 0x57, 0xea, 0xb5, 0x07, 0x07, 0xfd, 0x62, 0x8b, 0x5c, 0x6c, 0x70, 0x08, 0xd7,
-	0x59, 0xbe, 0x44, 0x27, 0x40, 0x04, 0x84, 0x25, 0x21, 0x55, 0xcb, 0x42, 0x82, 0x1e, 0x4b, 0xcc,
-	0x24, 0xa4, 0x58, 0x61, 0xd9, 0x0a, 0xbd, 0x05, 0xad, 0xb5, 0x15, 0xaf, 0x49, 0xcc, 0x22, 0x8b,
-	0x51, 0x3b, 0x51, 0x65, 0x21, 0x7a, 0xb9, 0x15, 0x8d, 0xac, 0x78, 0x6d, 0x64, 0x28, 0x6e, 0xae,
-	0x1f, 0x44, 0xe8, 0x1a, 0xb4, 0x85, 0xd8, 0x72, 0xed, 0x20, 0x72, 0xd8, 0xda, 0x53, 0x2b, 0x42,
-	0xfd, 0x5d, 0x3f, 0xfd, 0x8a, 0x43, 0xc7, 0x76, 0x98, 0xe5, 0xba, 0x89, 0xe1, 0xd8, 0x3e, 0x5d,
-	0x09, 0x2b, 0x2d, 0xe7, 0x62, 0x51, 0xb8, 0x08, 0xd1, 0x1d, 0x38, 0x88, 0x1d, 0xdb, 0xb7, 0xd8,
-	0x26, 0xa2, 0x0f, 0x1c, 0xab, 0xc2, 0xf1, 0x87, 0xcf, 0x38, 0x1a, 0xb9, 0x62, 0x6b, 0x8b, 0xe2,
-	0x27, 0x39, 0xf4, 0x2d, 0x68, 0xae, 0x9c, 0x38, 0x74, 0xad, 0x84, 0xf8, 0x96, 0x47, 0x55, 0xa5,
-	0x2b, 0xf5, 0xea, 0xb8, 0x91, 0xe5, 0xa6, 0x96, 0x47, 0x51, 0x17, 0x34, 0x56, 0x34, 0x5e, 0x46,
-	0x4e, 0xc8, 0x4f, 0x51, 0xad, 0x67, 0x8c, 0x6d, 0x0a, 0x9d, 0x82, 0x46, 0x18, 0x39, 0x7f, 0x59,
-	0x8c, 0x92, 0x7b, 0x9a, 0xa8, 0xcd, 0xae, 0xd4, 0x6b, 0x0c, 0x9e, 0xf7, 0xd3, 0x83, 0xee, 0xe7,
-	0x07, 0xdd, 0xd7, 0xfc, 0x04, 0x83, 0x8c, 0x78, 0x4d, 0x13, 0xf4, 0x1b, 0x80, 0x31, 0x0b, 0x22,
-	0xcb, 0xa6, 0x24, 0xa6, 0x8c, 0x39, 0xbe, 0x1d, 0xab, 0xad, 0xff, 0xd1, 0xee, 0x65, 0x6c, 0x23,
-	0x23, 0xa3, 0x9f, 0x00, 0x08, 0x37, 0x0b, 0xd7, 0x59, 0x8a, 0xb2, 0x6d, 0x21, 0xdd, 0xef, 0x67,
-	0x23, 0x3c, 0x17, 0xc8, 0x35, 0x4d, 0x70, 0x3d, 0xcc, 0x97, 0x48, 0x07, 0xfb, 0x9e, 0xf5, 0x81,
-	0x44, 0x41, 0xc0, 0x48, 0x3e, 0x97, 0xea, 0x9e, 0x10, 0x1e, 0x3e, 0xa9, 0x39, 0xcc, 0x08, 0x78,
-	0xcf, 0xb3, 0x3e, 0xe0, 0x20, 0x60, 0x79, 0x02, 0xbd, 0x05, 0x8d, 0x65, 0x44, 0xf9, 0x7e, 0xf9,
-	0xf0, 0xaa, 0x50, 0x18, 0x74, 0x9e, 0x18, 0x98, 0xf9, 0x64, 0x63, 0x90, 0xd2, 0x79, 0x82, 0x8b,
-	0x37, 0xe1, 0xaa, 0x10, 0xef, 0x7f, 0x59, 0x9c, 0xd2, 0x85, 0x58, 0x05, 0xb5, 0x15, 0x75, 0x29,
-	0xa3, 0x2b, 0xf5, 0xa0, 0x2b, 0xf5, 0x14, 0x9c, 0x87, 0xdc, 0x36, 0x5d, 0xa6, 0xb6, 0xcf, 0xbf,
-	0x6c, 0x9b, 0xd2, 0x79, 0x62, 0x22, 0x2b, 0x08, 0x1e, 0x4c, 0x64, 0xa5, 0x06, 0x95, 0x89, 0xac,
-	0x00, 0xd8, 0x98, 0xc8, 0x4a, 0x03, 0x36, 0x8f, 0xfe, 0x95, 0xc0, 0xf3, 0x74, 0xa0, 0x74, 0x9f,
-	0x45, 0x49, 0x21, 0x46, 0xdf, 0x83, 0xbd, 0xe2, 0xde, 0x12, 0xdf, 0xf2, 0x83, 0x38, 0xbb, 0xa3,
-	0xed, 0x22, 0x3d, 0xe5, 0x59, 0xf4, 0x02, 0x54, 0xdd, 0xc0, 0xe6, 0x77, 0xb8, 0x24, 0xf0, 0x8a,
-	0x1b, 0xd8, 0xe3, 0x15, 0xfa, 0x05, 0xd4, 0x8b, 0x69, 0x14, 0xd7, 0xb1, 0x31, 0x78, 0xf9, 0xe9,
-	0x49, 0xc6, 0x5b, 0xe2, 0xd1, 0x47, 0x09, 0xb4, 0xd2, 0xec, 0x4d, 0x60, 0xf3, 0x13, 0x41, 0x87,
-	0x40, 0xb9, 0xa7, 0x09, 0x59, 0x3b, 0x3e, 0x53, 0x6b, 0x5d, 0xa9, 0xd7, 0xc4, 0xb5, 0x7b, 0x9a,
-	0x8c, 0x1c, 0x5f, 0x40, 0xbc, 0x32, 0x3f, 0x6b, 0x31, 0xd6, 0x4d, 0x5c, 0x73, 0x33, 0xd5, 0x8f,
-	0x00, 0xe5, 0x10, 0xd9, 0xb6, 0x51, 0x17, 0x24, 0x98, 0x91, 0x8a, 0x0b, 0x34, 0x91, 0x15, 0x09,
-	0x96, 0x26, 0xb2, 0x52, 0x82, 0xe5, 0x89, 0xac, 0x94, 0xa1, 0x3c, 0x91, 0x15, 0x19, 0x56, 0x26,
-	0xb2, 0x52, 0x81, 0xd5, 0x89, 0xac, 0x54, 0x61, 0xed, 0x28, 0xca, 0x1b, 0xbb, 0xb5, 0xc2, 0xbc,
-	0x31, 0xcf, 0x0a, 0xd3, 0xea, 0xa9, 0x71, 0xcd, 0xcb, 0xa0, 0xaf, 0x1f, 0xee, 0x5d, 0x16, 0xd8,
-	0x36, 0xf1, 0xc9, 0x6a, 0x45, 0x9d, 0xe2, 0x88, 0x14, 0x58, 0x3f, 0x1e, 0x82, 0x56, 0xf6, 0x19,
-	0x2e, 0x83, 0xc8, 0xb3, 0x18, 0xfa, 0x0a, 0xbc, 0xba, 0x99, 0x5d, 0x11, 0x3c, 0x9b, 0x99, 0xe4,
-	0x72, 0x86, 0x6f, 0x35, 0x93, 0xfc, 0x3e, 0xbd, 0x9e, 0xce, 0xfe, 0x98, 0xc2, 0x67, 0xe8, 0x25,
-	0x40, 0xbb, 0xe0, 0xbb, 0x37, 0x50, 0xe2, 0x2e, 0x59, 0xcf, 0x5b, 0x97, 0x5b, 0x6d, 0xfe, 0x79,
-	0x97, 0x5d, 0x50, 0xb8, 0x7c, 0x94, 0x40, 0xf3, 0xe1, 0xef, 0x21, 0x3a, 0x04, 0x2f, 0x32, 0x15,
-	0x19, 0x69, 0xc6, 0x88, 0x18, 0x26, 0xd6, 0x4c, 0xfd, 0xea, 0x3d, 0x7c, 0x86, 0x10, 0x68, 0xe3,
-	0xcb, 0x8b, 0xb3, 0x5f, 0xcf, 0x06, 0xc4, 0x18, 0x69, 0x83, 0xd3, 0x33, 0x28, 0xa1, 0x03, 0xb0,
-	0x67, 0xea, 0x86, 0x49, 0xb8, 0x39, 0xe7, 0xeb, 0x18, 0x96, 0xb8, 0xc7, 0xec, 0x7c, 0xa2, 0x5f,
-	0x98, 0x64, 0x87, 0x5f, 0x46, 0x2f, 0xc0, 0xfe, 0xc5, 0x6c, 0x3a, 0xbe, 0x36, 0x78, 0xea, 0xf4,
-	0xcd, 0x80, 0xf0, 0xb4, 0x8c, 0xf6, 0x41, 0x6b, 0x9b, 0xe6, 0xa9, 0xca, 0xf1, 0x3f, 0x12, 0xa8,
-	0x17, 0x2f, 0x02, 0xef, 0x3f, 0x6f, 0xcb, 0xc4, 0xba, 0x4e, 0x0c, 0x53, 0x33, 0x75, 0xf8, 0x0c,
-	0x01, 0x50, 0xd5, 0x2e, 0xcc, 0xf1, 0x3b, 0x1d, 0x4a, 0x7c, 0x7d, 0x89, 0x67, 0x77, 0xfa, 0x14,
-	0x96, 0xd0, 0x6b, 0xf0, 0x6a, 0xa8, 0xcf, 0xb1, 0x7e, 0xa1, 0x99, 0xfa, 0x90, 0x18, 0xb3, 0x4b,
-	0x93, 0x0c, 0xf5, 0x1b, 0xdd, 0xd4, 0x87, 0xb0, 0xdc, 0x29, 0x29, 0xd2, 0x0e, 0x61, 0xa4, 0xe1,
-	0x61, 0x41, 0x90, 0x05, 0xa1, 0x09, 0x94, 0x21, 0xd6, 0xc6, 0xd3, 0xf1, 0xf4, 0x0a, 0x56, 0x8e,
-	0xaf, 0x80, 0x92, 0xbf, 0x35, 0x7c, 0x0f, 0x8f, 0x7a, 0x31, 0xdf, 0xcf, 0x79, 0x2b, 0x35, 0x50,
-	0xbe, 0x99, 0x5d, 0x41, 0x89, 0x2f, 0x6e, 0xb5, 0x39, 0x2c, 0xf1, 0x0f, 0x36, 0xc7, 0xfa, 0x0c,
-	0x0f, 0x75, 0xac, 0x0f, 0x09, 0x07, 0xcb, 0xe7, 0x23, 0x70, 0xb8, 0x0c, 0xbc, 0xfc, 0x7a, 0x3f,
-	0x7e, 0xde, 0xcf, 0x5b, 0x66, 0x16, 0xcf, 0x79, 0x38, 0x97, 0xee, 0x3a, 0xb6, 0xc3, 0xd6, 0x9b,
-	0x45, 0x7f, 0x19, 0x78, 0x27, 0xd9, 0xfb, 0x9b, 0x4b, 0x16, 0x55, 0xa1, 0xf9, 0xf9, 0xbf, 0x00,
-	0x00, 0x00, 0xff, 0xff, 0x7f, 0x1e, 0xf7, 0xb2, 0x24, 0x08, 0x00, 0x00,
+	// 1496 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x57, 0xdd, 0x6e, 0xdb, 0xc8,
+	0x15, 0x0e, 0x25, 0x5a, 0xa2, 0x8e, 0x24, 0x8b, 0x1e, 0xff, 0xd1, 0xea, 0xae, 0xe3, 0xaa, 0x05,
+	0xea, 0x1a, 0x85, 0xdd, 0xb8, 0x4d, 0x80, 0x62, 0x0b, 0x2c, 0x14, 0x89, 0xb6, 0x25, 0xc7, 0x92,
+	0x30, 0xe2, 0x66, 0x9b, 0xdc, 0x10, 0xb4, 0x38, 0xa6, 0x06, 0x11, 0x7f, 0x4a, 0x8e, 0x92, 0x70,
+	0xaf, 0x7a, 0xdf, 0x07, 0xd8, 0x67, 0xd8, 0x57, 0xe8, 0x43, 0xf4, 0x99, 0x8a, 0x19, 0x0e, 0x25,
+	0x5b, 0xb2, 0x93, 0x62, 0x6f, 0x6c, 0x9e, 0x73, 0xbe, 0xf3, 0x9d, 0x9f, 0x39, 0x3c, 0x43, 0xc1,
+	0x26, 0x8b, 0xe9, 0x6c, 0x46, 0x9d, 0xe0, 0x34, 0x8a, 0x43, 0x16, 0x22, 0x2d, 0x97, 0x9b, 0xcd,
+	0x49, 0x9c, 0x46, 0x2c, 0x3c, 0xfb, 0x40, 0xd2, 0x24, 0xba, 0x95, 0xff, 0x32, 0x54, 0xd3, 0x90,
+	0xb6, 0x84, 0x7a, 0xd1, 0x6d, 0xf6, 0x57, 0x5a, 0x0e, 0xbc, 0x30, 0xf4, 0x66, 0xe4, 0x4c, 0x48,
+	0xb7, 0xf3, 0xbb, 0x33, 0x27, 0x48, 0xa5, 0xe9, 0x70, 0xd5, 0xe4, 0xce, 0x63, 0x87, 0xd1, 0x50,
+	0x86, 0x6e, 0x3e, 0x5f, 0xb5, 0x33, 0xea, 0x93, 0x84, 0x39, 0x7e, 0x94, 0x01, 0x5a, 0xbf, 0x54,
+	0x40, 0xb5, 0x62, 0x42, 0xd0, 0x3e, 0x94, 0x59, 0x4c, 0x88, 0x4d, 0x5d, 0x43, 0x39, 0x52, 0x8e,
+	0x8b, 0xb8, 0xc4, 0xc5, 0x9e, 0x8b, 0xce, 0x01, 0x84, 0x21, 0x61, 0x0e, 0x23, 0x46, 0xe1, 0x48,
+	0x39, 0xde, 0x3c, 0xdf, 0x3e, 0x5d, 0x94, 0xc8, 0x9d, 0xc7, 0xdc, 0x84, 0x2b, 0x2c, 0x7f, 0x44,
+	0x67, 0x20, 0x04, 0x9b, 0xa5, 0x11, 0x31, 0x8a, 0xc2, 0x05, 0x3d, 0x74, 0xb1, 0xd2, 0x88, 0x60,
+	0x8d, 0xc9, 0x27, 0xf4, 0x1d, 0xd4, 0xa7, 0x4e, 0x32, 0xb5, 0x13, 0x16, 0x3b, 0x8c, 0x78, 0xa9,
+	0xa1, 0x0a, 0xa7, 0xbd, 0xa5, 0xd3, 0x95, 0x93, 0x4c, 0xc7, 0xd2, 0x8a, 0x6b, 0xd3, 0x7b, 0x12,
+	0xba, 0x86, 0x4d, 0xe1, 0xec, 0xcc, 0xbc, 0x30, 0xa6, 0x6c, 0xea, 0x1b, 0x1b, 0xc2, 0xfb, 0xf7,
+	0xa7, 0x59, 0x17, 0xbb, 0xd4, 0xa3, 0xcc, 0x99, 0xcd, 0xd2, 0x31, 0xf5, 0x02, 0xe2, 0x0a, 0xaa,
+	0x76, 0x8e, 0xc5, 0xf5, 0xe9, 0x7d, 0x11, 0xbd, 0x87, 0xed, 0x84, 0x7a, 0x81, 0xc3, 0xe6, 0x31,
+	0xb9, 0xc7, 0x58, 0x12, 0x8c, 0x7f, 0x7c, 0x82, 0x71, 0x9c, 0x7b, 0x2c, 0x69, 0x51, 0xb2, 0xa6,
+	0x43, 0xbf, 0x85, 0x9a, 0x4b, 0x93, 0x68, 0xe6, 0xa4, 0x76, 0xe0, 0xf8, 0xc4, 0xd0, 0x8e, 0x94,
+	0xe3, 0x0a, 0xae, 0x4a, 0xdd, 0xc0, 0xf1, 0x09, 0x3a, 0x82, 0xaa, 0x4b, 0x92, 0x49, 0x4c, 0x23,
+	0x7e, 0x8a, 0x46, 0x45, 0x22, 0x96, 0x2a, 0xf4, 0x12, 0xaa, 0x51, 0x4c, 0x3f, 0x3a, 0x8c, 0xd8,
+	0x1f, 0x48, 0x6a, 0xd4, 0x8e, 0x94, 0xe3, 0xea, 0xf9, 0xce, 0x69, 0x76, 0xd0, 0xa7, 0xf9, 0x41,
+	0x9f, 0xb6, 0x83, 0x14, 0x83, 0x04, 0x5e, 0x93, 0x14, 0x7d, 0x0f, 0x7a, 0xc2, 0xc2, 0xd8, 0xf1,
+	0x88, 0x9d, 0x10, 0xc6, 0x68, 0xe0, 0x25, 0x46, 0xfd, 0x0b, 0xbe, 0x0d, 0x89, 0x1e, 0x4b, 0x30,
+	0xfa, 0x33, 0x40, 0x34, 0xbf, 0x9d, 0xd1, 0x89, 0x08, 0xbb, 0x29, 0x5c, 0xb7, 0x4e, 0xe5, 0x08,
+	0x8f, 0x84, 0xe5, 0x9a, 0xa4, 0xb8, 0x12, 0xe5, 0x8f, 0xc8, 0x84, 0x2d, 0xdf, 0xf9, 0x6c, 0xc7,
+	0x61, 0xc8, 0xec, 0x7c, 0x2e, 0x8d, 0x86, 0x70, 0x3c, 0x58, 0x8b, 0xd9, 0x95, 0x00, 0xdc, 0xf0,
+	0x9d, 0xcf, 0x38, 0x0c, 0x59, 0xae, 0x40, 0xdf, 0x41, 0x75, 0x12, 0x13, 0x5e, 0x2f, 0x1f, 0x5e,
+	0x43, 0x17, 0x04, 0xcd, 0x35, 0x02, 0x2b, 0x9f, 0x6c, 0x0c, 0x19, 0x9c, 0x2b, 0xb8, 0xf3, 0x3c,
+	0x72, 0x17, 0xce, 0x5b, 0x5f, 0x77, 0xce, 0xe0, 0xc2, 0xd9, 0x80, 0xb2, 0x4b, 0x66, 0x84, 0x11,
+	0xd7, 0xd8, 0x3e, 0x52, 0x8e, 0x35, 0x9c, 0x8b, 0x9c, 0x36, 0x7b, 0xcc, 0x68, 0x77, 0xbe, 0x4e,
+	0x9b, 0xc1, 0x05, 0xed, 0x25, 0x6c, 0x25, 0xe4, 0x9f, 0x73, 0x12, 0x4c, 0x68, 0xe0, 0xd9, 0x93,
+	0x30, 0xb8, 0xa3, 0x9e, 0xb1, 0x2b, 0x29, 0x16, 0x03, 0x3f, 0x5e, 0x40, 0x3a, 0x02, 0x81, 0xf5,
+	0x64, 0x45, 0x83, 0xba, 0xa0, 0xc7, 0x84, 0x91, 0x80, 0xb7, 0xc9, 0x8e, 0xc2, 0x19, 0x9d, 0xa4,
+	0xc6, 0x9e, 0xec, 0xef, 0x82, 0x07, 0xe7, 0x88, 0x91, 0x00, 0xe0, 0x46, 0xfc, 0x50, 0x81, 0xbe,
+	0x87, 0xc6, 0x2c, 0xf4, 0xb2, 0x63, 0x92, 0xc9, 0xec, 0x0b, 0x92, 0xfd, 0x25, 0xc9, 0x9b, 0xd0,
+	0xe3, 0x67, 0x22, 0x33, 0xa9, 0xcf, 0xee, 0x8b, 0x68, 0x00, 0x3b, 0xbe, 0x13, 0xd9, 0x6b, 0xa9,
+	0x18, 0x82, 0xe5, 0x9b, 0x25, 0xcb, 0x8d, 0x13, 0xad, 0x66, 0x83, 0xfc, 0x35, 0x5d, 0x5f, 0xd5,
+	0x90, 0xbe, 0xdd, 0x57, 0xb5, 0xb2, 0xae, 0xf5, 0x55, 0x0d, 0xf4, 0x6a, 0x5f, 0xd5, 0xaa, 0x7a,
+	0xad, 0xf5, 0x5f, 0x05, 0xf4, 0xd5, 0xae, 0xa0, 0x3d, 0x28, 0x45, 0xce, 0x3c, 0x21, 0xd9, 0xda,
+	0xd2, 0xb0, 0x94, 0xd0, 0xb7, 0x00, 0xb7, 0x0e, 0x9b, 0x4c, 0xed, 0x84, 0xfe, 0x94, 0xad, 0xad,
+	0x0d, 0x5c, 0x11, 0x9a, 0x31, 0xfd, 0x89, 0xa0, 0xbf, 0x43, 0xcd, 0x9b, 0x3b, 0xb1, 0x6b, 0x7f,
+	0xa2, 0x81, 0x1b, 0x7e, 0x32, 0x8a, 0xb2, 0x6d, 0x4f, 0x8e, 0x65, 0x55, 0xc0, 0x7f, 0x14, 0x68,
+	0x31, 0xd9, 0x34, 0xc8, 0x5a, 0x46, 0x03, 0x46, 0xe2, 0x8f, 0xce, 0xcc, 0x50, 0xbf, 0x46, 0xd1,
+	0xf0, 0x69, 0xc0, 0xdb, 0xd6, 0x93, 0x1e, 0xad, 0x3b, 0x68, 0xac, 0xd4, 0x8e, 0x9e, 0x43, 0x95,
+	0xbf, 0x33, 0x24, 0x60, 0x31, 0x25, 0x89, 0x5c, 0xc5, 0xe0, 0x3b, 0x9f, 0xcd, 0x4c, 0x83, 0xce,
+	0xa1, 0xcc, 0x01, 0x8e, 0x97, 0x15, 0xf5, 0xc5, 0x80, 0x25, 0xdf, 0xf9, 0xdc, 0xf6, 0x48, 0xcb,
+	0x07, 0xb4, 0xde, 0x7a, 0xf4, 0x3b, 0xa8, 0x8b, 0xd7, 0x93, 0x7c, 0xa4, 0x09, 0x0d, 0x83, 0x3c,
+	0x58, 0x8d, 0xbf, 0x7f, 0xb9, 0xee, 0x57, 0x85, 0xfb, 0x07, 0xd4, 0x1f, 0xcc, 0x0b, 0x3a, 0x83,
+	0xd2, 0x5d, 0x18, 0xfb, 0x0e, 0x13, 0x21, 0x36, 0x1f, 0x19, 0xac, 0x0b, 0x61, 0xc6, 0x12, 0xc6,
+	0x0f, 0x35, 0x8c, 0xa9, 0x47, 0x03, 0x11, 0xb4, 0x82, 0xa5, 0xd4, 0xfa, 0x8f, 0x02, 0x3b, 0xd9,
+	0xca, 0xe5, 0xed, 0x48, 0x17, 0xaf, 0x17, 0xfa, 0x03, 0x34, 0x16, 0x37, 0x9b, 0x1d, 0x38, 0x41,
+	0x98, 0x57, 0xb3, 0xb9, 0x50, 0x0f, 0xb8, 0x16, 0xed, 0x42, 0x89, 0x0f, 0x3b, 0x75, 0x05, 0x73,
+	0x11, 0x6f, 0xcc, 0x42, 0xaf, 0xe7, 0xa2, 0xbf, 0x42, 0x65, 0xb1, 0xaf, 0xe5, 0x2c, 0xec, 0x3d,
+	0xbe, 0xeb, 0xf1, 0x12, 0x88, 0xfe, 0x04, 0x68, 0x46, 0x9c, 0x3b, 0x9b, 0xba, 0xbc, 0xb1, 0x2c,
+	0xb5, 0xf9, 0x55, 0x22, 0xe6, 0xa0, 0x86, 0x75, 0x6e, 0xe9, 0x49, 0x03, 0xbf, 0x71, 0x5a, 0xbf,
+	0x28, 0x80, 0x16, 0x7d, 0x59, 0x92, 0x7c, 0x0b, 0xf0, 0x89, 0xb2, 0x80, 0x24, 0x49, 0x7e, 0xf7,
+	0x56, 0x70, 0x45, 0x6a, 0x7a, 0xee, 0x53, 0x09, 0x3f, 0x52, 0x70, 0xf1, 0xd1, 0x82, 0x1f, 0x54,
+	0xa6, 0xfe, 0x9f, 0x95, 0xb5, 0x7e, 0x56, 0xa0, 0x9e, 0x69, 0x65, 0xc6, 0xe8, 0x00, 0xb4, 0x0f,
+	0x24, 0xb5, 0xa7, 0x34, 0x60, 0x46, 0x59, 0x54, 0x58, 0xfe, 0x40, 0xd2, 0x2b, 0x1a, 0x08, 0x53,
+	0xbe, 0x40, 0xc4, 0x95, 0x56, 0xc3, 0x65, 0xb9, 0x20, 0x44, 0x87, 0xa4, 0xc9, 0x5e, 0xa6, 0x51,
+	0x91, 0x1d, 0xca, 0x40, 0x8b, 0xcb, 0xb3, 0xaf, 0x6a, 0x8a, 0x5e, 0xe8, 0xab, 0x5a, 0x41, 0x2f,
+	0xf6, 0x55, 0xad, 0xa8, 0xab, 0x7d, 0x55, 0x53, 0xf5, 0x8d, 0xbe, 0xaa, 0x6d, 0xe8, 0xa5, 0xbe,
+	0xaa, 0x95, 0xf4, 0x72, 0x2b, 0xce, 0x13, 0xe3, 0x13, 0x2d, 0x13, 0x13, 0xdb, 0x87, 0x47, 0xcf,
+	0x88, 0xcb, 0xbe, 0x34, 0x7d, 0xb3, 0x5a, 0x7b, 0xed, 0x5e, 0x8d, 0x8f, 0x46, 0x5b, 0xc4, 0x59,
+	0xac, 0x1f, 0x4d, 0xaf, 0x9c, 0xb8, 0x50, 0x7f, 0x30, 0xa7, 0xe8, 0x37, 0xb0, 0xff, 0x66, 0x78,
+	0x69, 0xe3, 0xe1, 0xd0, 0xb2, 0x2f, 0x86, 0xf8, 0xa6, 0x6d, 0xd9, 0x3f, 0x0c, 0xae, 0x07, 0xc3,
+	0x1f, 0x07, 0xfa, 0x33, 0xb4, 0x07, 0x68, 0xd5, 0xf8, 0xf6, 0x85, 0xae, 0xa0, 0x43, 0x68, 0xae,
+	0xea, 0x3b, 0x57, 0x66, 0xe7, 0x7a, 0x34, 0xec, 0x0d, 0x2c, 0xbd, 0x70, 0xd2, 0x85, 0xba, 0xac,
+	0x69, 0x19, 0xe5, 0xa6, 0x3d, 0x7a, 0x3a, 0xca, 0xaa, 0x91, 0x47, 0x39, 0x79, 0x0f, 0x3b, 0x0f,
+	0xdf, 0x0d, 0x49, 0xd6, 0x82, 0x43, 0x73, 0x60, 0xe1, 0x77, 0xb6, 0xd5, 0xbb, 0x31, 0xc7, 0x56,
+	0xfb, 0x66, 0xb4, 0xce, 0x79, 0x08, 0x07, 0x4f, 0x60, 0xde, 0xbe, 0xd0, 0xff, 0x55, 0x38, 0x19,
+	0xc2, 0xd6, 0xbd, 0xc9, 0x95, 0xc4, 0x87, 0xd0, 0xec, 0x0c, 0xc7, 0xbd, 0xcb, 0x41, 0xdb, 0xfa,
+	0x01, 0x9b, 0xeb, 0xa4, 0x4d, 0xd8, 0x7d, 0xc4, 0xce, 0x09, 0xd5, 0x93, 0x9f, 0x15, 0xa8, 0xdd,
+	0xff, 0xb0, 0x43, 0x07, 0xb0, 0x2b, 0x3d, 0xed, 0xab, 0xf6, 0xf8, 0xca, 0x1e, 0x5b, 0xb8, 0x6d,
+	0x99, 0x97, 0xef, 0xf4, 0x67, 0x08, 0xc1, 0x26, 0xbe, 0xe8, 0xbc, 0xfa, 0xdb, 0xab, 0x73, 0x7b,
+	0x7c, 0xd5, 0x3e, 0x7f, 0xf9, 0x4a, 0x57, 0xd0, 0x36, 0x34, 0x2c, 0x73, 0x6c, 0xd9, 0xbc, 0x13,
+	0x1c, 0x6f, 0x62, 0xbd, 0xc0, 0x39, 0x86, 0xaf, 0xfb, 0x66, 0xc7, 0xb2, 0x57, 0xf0, 0x45, 0xb4,
+	0x0b, 0x5b, 0x9d, 0xe1, 0xa0, 0x77, 0x3d, 0xe6, 0xaa, 0x97, 0x2f, 0xce, 0x6d, 0xae, 0x56, 0xd1,
+	0x16, 0xd4, 0x97, 0x6a, 0xae, 0xda, 0x38, 0xf9, 0xb7, 0x02, 0x95, 0xc5, 0xa7, 0x2d, 0x6f, 0x76,
+	0x9e, 0x96, 0x85, 0x4d, 0xd3, 0x1e, 0x5b, 0x6d, 0xcb, 0xd4, 0x9f, 0x21, 0x80, 0x52, 0xbb, 0x63,
+	0xf5, 0xde, 0x9a, 0xba, 0xc2, 0x9f, 0x2f, 0xf0, 0xf0, 0xbd, 0x39, 0xd0, 0x0b, 0xe8, 0x39, 0xec,
+	0x77, 0xcd, 0x11, 0x36, 0x3b, 0x6d, 0xcb, 0xec, 0xda, 0xe3, 0xe1, 0x85, 0x65, 0x77, 0xcd, 0x37,
+	0xa6, 0x65, 0x76, 0xf5, 0x62, 0xb3, 0xa0, 0x29, 0x2b, 0x80, 0xab, 0x36, 0xee, 0x2e, 0x00, 0xaa,
+	0x00, 0xd4, 0x40, 0xeb, 0xe2, 0x76, 0x6f, 0xd0, 0x1b, 0x5c, 0xea, 0x1b, 0x27, 0x97, 0xa0, 0xe5,
+	0x1f, 0xcd, 0xbc, 0x86, 0x07, 0xb9, 0x58, 0xef, 0x46, 0x3c, 0x95, 0x32, 0x14, 0xdf, 0x0c, 0x2f,
+	0x75, 0x85, 0x3f, 0xdc, 0xb4, 0x47, 0x7a, 0x81, 0x37, 0x6c, 0x84, 0xcd, 0x21, 0xee, 0x9a, 0xd8,
+	0xec, 0xda, 0xdc, 0x58, 0x7c, 0x7d, 0x05, 0x07, 0x93, 0xd0, 0xcf, 0x57, 0xf8, 0xc3, 0xdf, 0x29,
+	0xaf, 0xeb, 0x96, 0x94, 0x47, 0x5c, 0x1c, 0x29, 0xef, 0x9b, 0x1e, 0x65, 0xd3, 0xf9, 0xed, 0xe9,
+	0x24, 0xf4, 0xcf, 0xe4, 0x0f, 0x89, 0xdc, 0xe5, 0xb6, 0x24, 0x7c, 0xfe, 0xf2, 0xbf, 0x01, 0x00,
+	0xa7, 0x60, 0x3b, 0x53, 0xed, 0x0c, 0x00, 0x00,
 }
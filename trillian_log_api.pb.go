@@ -83,12 +83,15 @@ func (m *ChargeTo) GetUser() []string {
 }
 
 type QueueLeafRequest struct {
-	LogId                int64     `protobuf:"varint,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
-	Leaf                 *LogLeaf  `protobuf:"bytes,2,opt,name=leaf,proto3" json:"leaf,omitempty"`
-	ChargeTo             *ChargeTo `protobuf:"bytes,3,opt,name=charge_to,json=chargeTo,proto3" json:"charge_to,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
-	XXX_unrecognized     []byte    `json:"-"`
-	XXX_sizecache        int32     `json:"-"`
+	LogId    int64     `protobuf:"varint,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	Leaf     *LogLeaf  `protobuf:"bytes,2,opt,name=leaf,proto3" json:"leaf,omitempty"`
+	ChargeTo *ChargeTo `protobuf:"bytes,3,opt,name=charge_to,json=chargeTo,proto3" json:"charge_to,omitempty"`
+	// If return_signed_entry_timestamp is set, the response carries a promise
+	// by the Log to incorporate the leaf.
+	ReturnSignedEntryTimestamp bool     `protobuf:"varint,4,opt,name=return_signed_entry_timestamp,json=returnSignedEntryTimestamp,proto3" json:"return_signed_entry_timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral       struct{} `json:"-"`
+	XXX_unrecognized           []byte   `json:"-"`
+	XXX_sizecache              int32    `json:"-"`
 }
 
 func (m *QueueLeafRequest) Reset()         { *m = QueueLeafRequest{} }
@@ -137,15 +140,25 @@ func (m *QueueLeafRequest) GetChargeTo() *ChargeTo {
 	return nil
 }
 
+func (m *QueueLeafRequest) GetReturnSignedEntryTimestamp() bool {
+	if m != nil {
+		return m.ReturnSignedEntryTimestamp
+	}
+	return false
+}
+
 type QueueLeafResponse struct {
 	// queued_leaf describes the leaf which is or will be incorporated into the
 	// Log.  If the submitted leaf was already present in the Log (as indicated by
 	// its leaf identity hash), then the returned leaf will be the pre-existing
 	// leaf entry rather than the submitted leaf.
-	QueuedLeaf           *QueuedLogLeaf `protobuf:"bytes,2,opt,name=queued_leaf,json=queuedLeaf,proto3" json:"queued_leaf,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
-	XXX_unrecognized     []byte         `json:"-"`
-	XXX_sizecache        int32          `json:"-"`
+	QueuedLeaf *QueuedLogLeaf `protobuf:"bytes,2,opt,name=queued_leaf,json=queuedLeaf,proto3" json:"queued_leaf,omitempty"`
+	// signed_entry_timestamp is only set if requested. It refers to queued_leaf,
+	// so for pre-existing leaves it carries their original queue timestamp.
+	SignedEntryTimestamp *SignedEntryTimestamp `protobuf:"bytes,3,opt,name=signed_entry_timestamp,json=signedEntryTimestamp,proto3" json:"signed_entry_timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
 }
 
 func (m *QueueLeafResponse) Reset()         { *m = QueueLeafResponse{} }
@@ -180,6 +193,13 @@ func (m *QueueLeafResponse) GetQueuedLeaf() *QueuedLogLeaf {
 	return nil
 }
 
+func (m *QueueLeafResponse) GetSignedEntryTimestamp() *SignedEntryTimestamp {
+	if m != nil {
+		return m.SignedEntryTimestamp
+	}
+	return nil
+}
+
 type AddSequencedLeafRequest struct {
 	LogId                int64     `protobuf:"varint,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
 	Leaf                 *LogLeaf  `protobuf:"bytes,2,opt,name=leaf,proto3" json:"leaf,omitempty"`
@@ -514,6 +534,136 @@ func (m *GetInclusionProofByHashResponse) GetSignedLogRoot() *SignedLogRoot {
 	return nil
 }
 
+type GetBatchInclusionProofRequest struct {
+	LogId int64 `protobuf:"varint,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	// The indices of the leaves to prove inclusion for. They may be given in
+	// any order, and duplicates are ignored.
+	LeafIndex            []int64   `protobuf:"varint,2,rep,packed,name=leaf_index,json=leafIndex,proto3" json:"leaf_index,omitempty"`
+	TreeSize             int64     `protobuf:"varint,3,opt,name=tree_size,json=treeSize,proto3" json:"tree_size,omitempty"`
+	ChargeTo             *ChargeTo `protobuf:"bytes,4,opt,name=charge_to,json=chargeTo,proto3" json:"charge_to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *GetBatchInclusionProofRequest) Reset()         { *m = GetBatchInclusionProofRequest{} }
+func (m *GetBatchInclusionProofRequest) String() string { return proto.CompactTextString(m) }
+func (*GetBatchInclusionProofRequest) ProtoMessage()    {}
+func (*GetBatchInclusionProofRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5ad20a6a54aa5af3, []int{9}
+}
+
+func (m *GetBatchInclusionProofRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBatchInclusionProofRequest.Unmarshal(m, b)
+}
+func (m *GetBatchInclusionProofRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBatchInclusionProofRequest.Marshal(b, m, deterministic)
+}
+func (m *GetBatchInclusionProofRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBatchInclusionProofRequest.Merge(m, src)
+}
+func (m *GetBatchInclusionProofRequest) XXX_Size() int {
+	return xxx_messageInfo_GetBatchInclusionProofRequest.Size(m)
+}
+func (m *GetBatchInclusionProofRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBatchInclusionProofRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBatchInclusionProofRequest proto.InternalMessageInfo
+
+func (m *GetBatchInclusionProofRequest) GetLogId() int64 {
+	if m != nil {
+		return m.LogId
+	}
+	return 0
+}
+
+func (m *GetBatchInclusionProofRequest) GetLeafIndex() []int64 {
+	if m != nil {
+		return m.LeafIndex
+	}
+	return nil
+}
+
+func (m *GetBatchInclusionProofRequest) GetTreeSize() int64 {
+	if m != nil {
+		return m.TreeSize
+	}
+	return 0
+}
+
+func (m *GetBatchInclusionProofRequest) GetChargeTo() *ChargeTo {
+	if m != nil {
+		return m.ChargeTo
+	}
+	return nil
+}
+
+type GetBatchInclusionProofResponse struct {
+	// The indices of the leaves covered by the proof, in increasing order and
+	// without duplicates.
+	LeafIndex []int64 `protobuf:"varint,1,rep,packed,name=leaf_index,json=leafIndex,proto3" json:"leaf_index,omitempty"`
+	// The hashes of the maximal subtrees that contain none of the requested
+	// leaves, ordered by the index of their leftmost leaf. This is the order
+	// in which they are consumed when recomputing the root hash from left to
+	// right.
+	//
+	// The hashes field may be empty if the requested tree_size was larger than
+	// that available at the server. In this case, the signed_log_root field
+	// will indicate the tree size that the server is aware of.
+	Hashes               [][]byte       `protobuf:"bytes,2,rep,name=hashes,proto3" json:"hashes,omitempty"`
+	SignedLogRoot        *SignedLogRoot `protobuf:"bytes,3,opt,name=signed_log_root,json=signedLogRoot,proto3" json:"signed_log_root,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *GetBatchInclusionProofResponse) Reset()         { *m = GetBatchInclusionProofResponse{} }
+func (m *GetBatchInclusionProofResponse) String() string { return proto.CompactTextString(m) }
+func (*GetBatchInclusionProofResponse) ProtoMessage()    {}
+func (*GetBatchInclusionProofResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5ad20a6a54aa5af3, []int{10}
+}
+
+func (m *GetBatchInclusionProofResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBatchInclusionProofResponse.Unmarshal(m, b)
+}
+func (m *GetBatchInclusionProofResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBatchInclusionProofResponse.Marshal(b, m, deterministic)
+}
+func (m *GetBatchInclusionProofResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBatchInclusionProofResponse.Merge(m, src)
+}
+func (m *GetBatchInclusionProofResponse) XXX_Size() int {
+	return xxx_messageInfo_GetBatchInclusionProofResponse.Size(m)
+}
+func (m *GetBatchInclusionProofResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBatchInclusionProofResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBatchInclusionProofResponse proto.InternalMessageInfo
+
+func (m *GetBatchInclusionProofResponse) GetLeafIndex() []int64 {
+	if m != nil {
+		return m.LeafIndex
+	}
+	return nil
+}
+
+func (m *GetBatchInclusionProofResponse) GetHashes() [][]byte {
+	if m != nil {
+		return m.Hashes
+	}
+	return nil
+}
+
+func (m *GetBatchInclusionProofResponse) GetSignedLogRoot() *SignedLogRoot {
+	if m != nil {
+		return m.SignedLogRoot
+	}
+	return nil
+}
+
 type GetConsistencyProofRequest struct {
 	LogId                int64     `protobuf:"varint,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
 	FirstTreeSize        int64     `protobuf:"varint,2,opt,name=first_tree_size,json=firstTreeSize,proto3" json:"first_tree_size,omitempty"`
@@ -528,7 +678,7 @@ func (m *GetConsistencyProofRequest) Reset()         { *m = GetConsistencyProofR
 func (m *GetConsistencyProofRequest) String() string { return proto.CompactTextString(m) }
 func (*GetConsistencyProofRequest) ProtoMessage()    {}
 func (*GetConsistencyProofRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{9}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{11}
 }
 
 func (m *GetConsistencyProofRequest) XXX_Unmarshal(b []byte) error {
@@ -595,7 +745,7 @@ func (m *GetConsistencyProofResponse) Reset()         { *m = GetConsistencyProof
 func (m *GetConsistencyProofResponse) String() string { return proto.CompactTextString(m) }
 func (*GetConsistencyProofResponse) ProtoMessage()    {}
 func (*GetConsistencyProofResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{10}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{12}
 }
 
 func (m *GetConsistencyProofResponse) XXX_Unmarshal(b []byte) error {
@@ -645,7 +795,7 @@ func (m *GetLatestSignedLogRootRequest) Reset()         { *m = GetLatestSignedLo
 func (m *GetLatestSignedLogRootRequest) String() string { return proto.CompactTextString(m) }
 func (*GetLatestSignedLogRootRequest) ProtoMessage()    {}
 func (*GetLatestSignedLogRootRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{11}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{13}
 }
 
 func (m *GetLatestSignedLogRootRequest) XXX_Unmarshal(b []byte) error {
@@ -702,7 +852,7 @@ func (m *GetLatestSignedLogRootResponse) Reset()         { *m = GetLatestSignedL
 func (m *GetLatestSignedLogRootResponse) String() string { return proto.CompactTextString(m) }
 func (*GetLatestSignedLogRootResponse) ProtoMessage()    {}
 func (*GetLatestSignedLogRootResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{12}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{14}
 }
 
 func (m *GetLatestSignedLogRootResponse) XXX_Unmarshal(b []byte) error {
@@ -753,7 +903,7 @@ func (m *GetSequencedLeafCountRequest) Reset()         { *m = GetSequencedLeafCo
 func (m *GetSequencedLeafCountRequest) String() string { return proto.CompactTextString(m) }
 func (*GetSequencedLeafCountRequest) ProtoMessage()    {}
 func (*GetSequencedLeafCountRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{13}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{15}
 }
 
 func (m *GetSequencedLeafCountRequest) XXX_Unmarshal(b []byte) error {
@@ -799,7 +949,7 @@ func (m *GetSequencedLeafCountResponse) Reset()         { *m = GetSequencedLeafC
 func (m *GetSequencedLeafCountResponse) String() string { return proto.CompactTextString(m) }
 func (*GetSequencedLeafCountResponse) ProtoMessage()    {}
 func (*GetSequencedLeafCountResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{14}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{16}
 }
 
 func (m *GetSequencedLeafCountResponse) XXX_Unmarshal(b []byte) error {
@@ -841,7 +991,7 @@ func (m *GetEntryAndProofRequest) Reset()         { *m = GetEntryAndProofRequest
 func (m *GetEntryAndProofRequest) String() string { return proto.CompactTextString(m) }
 func (*GetEntryAndProofRequest) ProtoMessage()    {}
 func (*GetEntryAndProofRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{15}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{17}
 }
 
 func (m *GetEntryAndProofRequest) XXX_Unmarshal(b []byte) error {
@@ -903,7 +1053,7 @@ func (m *GetEntryAndProofResponse) Reset()         { *m = GetEntryAndProofRespon
 func (m *GetEntryAndProofResponse) String() string { return proto.CompactTextString(m) }
 func (*GetEntryAndProofResponse) ProtoMessage()    {}
 func (*GetEntryAndProofResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{16}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{18}
 }
 
 func (m *GetEntryAndProofResponse) XXX_Unmarshal(b []byte) error {
@@ -957,7 +1107,7 @@ func (m *InitLogRequest) Reset()         { *m = InitLogRequest{} }
 func (m *InitLogRequest) String() string { return proto.CompactTextString(m) }
 func (*InitLogRequest) ProtoMessage()    {}
 func (*InitLogRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{17}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{19}
 }
 
 func (m *InitLogRequest) XXX_Unmarshal(b []byte) error {
@@ -1003,7 +1153,7 @@ func (m *InitLogResponse) Reset()         { *m = InitLogResponse{} }
 func (m *InitLogResponse) String() string { return proto.CompactTextString(m) }
 func (*InitLogResponse) ProtoMessage()    {}
 func (*InitLogResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{18}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{20}
 }
 
 func (m *InitLogResponse) XXX_Unmarshal(b []byte) error {
@@ -1032,19 +1182,22 @@ func (m *InitLogResponse) GetCreated() *SignedLogRoot {
 }
 
 type QueueLeavesRequest struct {
-	LogId                int64      `protobuf:"varint,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
-	Leaves               []*LogLeaf `protobuf:"bytes,2,rep,name=leaves,proto3" json:"leaves,omitempty"`
-	ChargeTo             *ChargeTo  `protobuf:"bytes,3,opt,name=charge_to,json=chargeTo,proto3" json:"charge_to,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
-	XXX_unrecognized     []byte     `json:"-"`
-	XXX_sizecache        int32      `json:"-"`
+	LogId    int64      `protobuf:"varint,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	Leaves   []*LogLeaf `protobuf:"bytes,2,rep,name=leaves,proto3" json:"leaves,omitempty"`
+	ChargeTo *ChargeTo  `protobuf:"bytes,3,opt,name=charge_to,json=chargeTo,proto3" json:"charge_to,omitempty"`
+	// If return_signed_entry_timestamps is set, the response carries a promise
+	// by the Log to incorporate each of the leaves.
+	ReturnSignedEntryTimestamps bool     `protobuf:"varint,4,opt,name=return_signed_entry_timestamps,json=returnSignedEntryTimestamps,proto3" json:"return_signed_entry_timestamps,omitempty"`
+	XXX_NoUnkeyedLiteral        struct{} `json:"-"`
+	XXX_unrecognized            []byte   `json:"-"`
+	XXX_sizecache               int32    `json:"-"`
 }
 
 func (m *QueueLeavesRequest) Reset()         { *m = QueueLeavesRequest{} }
 func (m *QueueLeavesRequest) String() string { return proto.CompactTextString(m) }
 func (*QueueLeavesRequest) ProtoMessage()    {}
 func (*QueueLeavesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{19}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{21}
 }
 
 func (m *QueueLeavesRequest) XXX_Unmarshal(b []byte) error {
@@ -1086,19 +1239,28 @@ func (m *QueueLeavesRequest) GetChargeTo() *ChargeTo {
 	return nil
 }
 
+func (m *QueueLeavesRequest) GetReturnSignedEntryTimestamps() bool {
+	if m != nil {
+		return m.ReturnSignedEntryTimestamps
+	}
+	return false
+}
+
 type QueueLeavesResponse struct {
 	// Same number and order as in the corresponding request.
-	QueuedLeaves         []*QueuedLogLeaf `protobuf:"bytes,2,rep,name=queued_leaves,json=queuedLeaves,proto3" json:"queued_leaves,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	QueuedLeaves []*QueuedLogLeaf `protobuf:"bytes,2,rep,name=queued_leaves,json=queuedLeaves,proto3" json:"queued_leaves,omitempty"`
+	// Only set if requested. Same number and order as queued_leaves.
+	SignedEntryTimestamps []*SignedEntryTimestamp `protobuf:"bytes,3,rep,name=signed_entry_timestamps,json=signedEntryTimestamps,proto3" json:"signed_entry_timestamps,omitempty"`
+	XXX_NoUnkeyedLiteral  struct{}                `json:"-"`
+	XXX_unrecognized      []byte                  `json:"-"`
+	XXX_sizecache         int32                   `json:"-"`
 }
 
 func (m *QueueLeavesResponse) Reset()         { *m = QueueLeavesResponse{} }
 func (m *QueueLeavesResponse) String() string { return proto.CompactTextString(m) }
 func (*QueueLeavesResponse) ProtoMessage()    {}
 func (*QueueLeavesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{20}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{22}
 }
 
 func (m *QueueLeavesResponse) XXX_Unmarshal(b []byte) error {
@@ -1126,6 +1288,13 @@ func (m *QueueLeavesResponse) GetQueuedLeaves() []*QueuedLogLeaf {
 	return nil
 }
 
+func (m *QueueLeavesResponse) GetSignedEntryTimestamps() []*SignedEntryTimestamp {
+	if m != nil {
+		return m.SignedEntryTimestamps
+	}
+	return nil
+}
+
 type AddSequencedLeavesRequest struct {
 	LogId                int64      `protobuf:"varint,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
 	Leaves               []*LogLeaf `protobuf:"bytes,2,rep,name=leaves,proto3" json:"leaves,omitempty"`
@@ -1139,7 +1308,7 @@ func (m *AddSequencedLeavesRequest) Reset()         { *m = AddSequencedLeavesReq
 func (m *AddSequencedLeavesRequest) String() string { return proto.CompactTextString(m) }
 func (*AddSequencedLeavesRequest) ProtoMessage()    {}
 func (*AddSequencedLeavesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{21}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{23}
 }
 
 func (m *AddSequencedLeavesRequest) XXX_Unmarshal(b []byte) error {
@@ -1193,7 +1362,7 @@ func (m *AddSequencedLeavesResponse) Reset()         { *m = AddSequencedLeavesRe
 func (m *AddSequencedLeavesResponse) String() string { return proto.CompactTextString(m) }
 func (*AddSequencedLeavesResponse) ProtoMessage()    {}
 func (*AddSequencedLeavesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{22}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{24}
 }
 
 func (m *AddSequencedLeavesResponse) XXX_Unmarshal(b []byte) error {
@@ -1234,7 +1403,7 @@ func (m *GetLeavesByIndexRequest) Reset()         { *m = GetLeavesByIndexRequest
 func (m *GetLeavesByIndexRequest) String() string { return proto.CompactTextString(m) }
 func (*GetLeavesByIndexRequest) ProtoMessage()    {}
 func (*GetLeavesByIndexRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{23}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{25}
 }
 
 func (m *GetLeavesByIndexRequest) XXX_Unmarshal(b []byte) error {
@@ -1289,7 +1458,7 @@ func (m *GetLeavesByIndexResponse) Reset()         { *m = GetLeavesByIndexRespon
 func (m *GetLeavesByIndexResponse) String() string { return proto.CompactTextString(m) }
 func (*GetLeavesByIndexResponse) ProtoMessage()    {}
 func (*GetLeavesByIndexResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{24}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{26}
 }
 
 func (m *GetLeavesByIndexResponse) XXX_Unmarshal(b []byte) error {
@@ -1338,7 +1507,7 @@ func (m *GetLeavesByRangeRequest) Reset()         { *m = GetLeavesByRangeRequest
 func (m *GetLeavesByRangeRequest) String() string { return proto.CompactTextString(m) }
 func (*GetLeavesByRangeRequest) ProtoMessage()    {}
 func (*GetLeavesByRangeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{25}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{27}
 }
 
 func (m *GetLeavesByRangeRequest) XXX_Unmarshal(b []byte) error {
@@ -1403,7 +1572,7 @@ func (m *GetLeavesByRangeResponse) Reset()         { *m = GetLeavesByRangeRespon
 func (m *GetLeavesByRangeResponse) String() string { return proto.CompactTextString(m) }
 func (*GetLeavesByRangeResponse) ProtoMessage()    {}
 func (*GetLeavesByRangeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{26}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{28}
 }
 
 func (m *GetLeavesByRangeResponse) XXX_Unmarshal(b []byte) error {
@@ -1438,6 +1607,160 @@ func (m *GetLeavesByRangeResponse) GetSignedLogRoot() *SignedLogRoot {
 	return nil
 }
 
+type GetLeavesByRangeWithProofRequest struct {
+	LogId      int64 `protobuf:"varint,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	StartIndex int64 `protobuf:"varint,2,opt,name=start_index,json=startIndex,proto3" json:"start_index,omitempty"`
+	Count      int64 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	// The size of the tree to prove inclusion in. If zero, the size of the
+	// latest log root is used.
+	TreeSize             int64     `protobuf:"varint,4,opt,name=tree_size,json=treeSize,proto3" json:"tree_size,omitempty"`
+	ChargeTo             *ChargeTo `protobuf:"bytes,5,opt,name=charge_to,json=chargeTo,proto3" json:"charge_to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *GetLeavesByRangeWithProofRequest) Reset()         { *m = GetLeavesByRangeWithProofRequest{} }
+func (m *GetLeavesByRangeWithProofRequest) String() string { return proto.CompactTextString(m) }
+func (*GetLeavesByRangeWithProofRequest) ProtoMessage()    {}
+func (*GetLeavesByRangeWithProofRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5ad20a6a54aa5af3, []int{29}
+}
+
+func (m *GetLeavesByRangeWithProofRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetLeavesByRangeWithProofRequest.Unmarshal(m, b)
+}
+func (m *GetLeavesByRangeWithProofRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetLeavesByRangeWithProofRequest.Marshal(b, m, deterministic)
+}
+func (m *GetLeavesByRangeWithProofRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetLeavesByRangeWithProofRequest.Merge(m, src)
+}
+func (m *GetLeavesByRangeWithProofRequest) XXX_Size() int {
+	return xxx_messageInfo_GetLeavesByRangeWithProofRequest.Size(m)
+}
+func (m *GetLeavesByRangeWithProofRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetLeavesByRangeWithProofRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetLeavesByRangeWithProofRequest proto.InternalMessageInfo
+
+func (m *GetLeavesByRangeWithProofRequest) GetLogId() int64 {
+	if m != nil {
+		return m.LogId
+	}
+	return 0
+}
+
+func (m *GetLeavesByRangeWithProofRequest) GetStartIndex() int64 {
+	if m != nil {
+		return m.StartIndex
+	}
+	return 0
+}
+
+func (m *GetLeavesByRangeWithProofRequest) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *GetLeavesByRangeWithProofRequest) GetTreeSize() int64 {
+	if m != nil {
+		return m.TreeSize
+	}
+	return 0
+}
+
+func (m *GetLeavesByRangeWithProofRequest) GetChargeTo() *ChargeTo {
+	if m != nil {
+		return m.ChargeTo
+	}
+	return nil
+}
+
+type GetLeavesByRangeWithProofResponse struct {
+	// Returned log leaves starting from the `start_index` of the request, in
+	// order. There may be fewer than `request.count` leaves returned, if the
+	// requested range extended beyond the tree size or if the server opted to
+	// return fewer leaves than requested.
+	Leaves []*LogLeaf `protobuf:"bytes,1,rep,name=leaves,proto3" json:"leaves,omitempty"`
+	// The hashes of the compact range [0, start_index), i.e. of the minimal set
+	// of perfect subtrees covering the leaves to the left of the range, ordered
+	// from left to right.
+	LeftHashes [][]byte `protobuf:"bytes,2,rep,name=left_hashes,json=leftHashes,proto3" json:"left_hashes,omitempty"`
+	// The hashes of the compact range [start_index + len(leaves), tree_size),
+	// ordered from left to right.
+	RightHashes [][]byte `protobuf:"bytes,3,rep,name=right_hashes,json=rightHashes,proto3" json:"right_hashes,omitempty"`
+	// The size of the tree which the proof is for.
+	TreeSize             int64          `protobuf:"varint,4,opt,name=tree_size,json=treeSize,proto3" json:"tree_size,omitempty"`
+	SignedLogRoot        *SignedLogRoot `protobuf:"bytes,5,opt,name=signed_log_root,json=signedLogRoot,proto3" json:"signed_log_root,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *GetLeavesByRangeWithProofResponse) Reset()         { *m = GetLeavesByRangeWithProofResponse{} }
+func (m *GetLeavesByRangeWithProofResponse) String() string { return proto.CompactTextString(m) }
+func (*GetLeavesByRangeWithProofResponse) ProtoMessage()    {}
+func (*GetLeavesByRangeWithProofResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5ad20a6a54aa5af3, []int{30}
+}
+
+func (m *GetLeavesByRangeWithProofResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetLeavesByRangeWithProofResponse.Unmarshal(m, b)
+}
+func (m *GetLeavesByRangeWithProofResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetLeavesByRangeWithProofResponse.Marshal(b, m, deterministic)
+}
+func (m *GetLeavesByRangeWithProofResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetLeavesByRangeWithProofResponse.Merge(m, src)
+}
+func (m *GetLeavesByRangeWithProofResponse) XXX_Size() int {
+	return xxx_messageInfo_GetLeavesByRangeWithProofResponse.Size(m)
+}
+func (m *GetLeavesByRangeWithProofResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetLeavesByRangeWithProofResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetLeavesByRangeWithProofResponse proto.InternalMessageInfo
+
+func (m *GetLeavesByRangeWithProofResponse) GetLeaves() []*LogLeaf {
+	if m != nil {
+		return m.Leaves
+	}
+	return nil
+}
+
+func (m *GetLeavesByRangeWithProofResponse) GetLeftHashes() [][]byte {
+	if m != nil {
+		return m.LeftHashes
+	}
+	return nil
+}
+
+func (m *GetLeavesByRangeWithProofResponse) GetRightHashes() [][]byte {
+	if m != nil {
+		return m.RightHashes
+	}
+	return nil
+}
+
+func (m *GetLeavesByRangeWithProofResponse) GetTreeSize() int64 {
+	if m != nil {
+		return m.TreeSize
+	}
+	return 0
+}
+
+func (m *GetLeavesByRangeWithProofResponse) GetSignedLogRoot() *SignedLogRoot {
+	if m != nil {
+		return m.SignedLogRoot
+	}
+	return nil
+}
+
 type GetLeavesByHashRequest struct {
 	LogId int64 `protobuf:"varint,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
 	// The Merkle leaf hash of the leaf to be retrieved.
@@ -1455,7 +1778,7 @@ func (m *GetLeavesByHashRequest) Reset()         { *m = GetLeavesByHashRequest{}
 func (m *GetLeavesByHashRequest) String() string { return proto.CompactTextString(m) }
 func (*GetLeavesByHashRequest) ProtoMessage()    {}
 func (*GetLeavesByHashRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{27}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{31}
 }
 
 func (m *GetLeavesByHashRequest) XXX_Unmarshal(b []byte) error {
@@ -1516,7 +1839,7 @@ func (m *GetLeavesByHashResponse) Reset()         { *m = GetLeavesByHashResponse
 func (m *GetLeavesByHashResponse) String() string { return proto.CompactTextString(m) }
 func (*GetLeavesByHashResponse) ProtoMessage()    {}
 func (*GetLeavesByHashResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{28}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{32}
 }
 
 func (m *GetLeavesByHashResponse) XXX_Unmarshal(b []byte) error {
@@ -1551,6 +1874,143 @@ func (m *GetLeavesByHashResponse) GetSignedLogRoot() *SignedLogRoot {
 	return nil
 }
 
+type WatchLeavesRequest struct {
+	LogId int64 `protobuf:"varint,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	// The index of the first leaf to be streamed.
+	StartIndex int64 `protobuf:"varint,2,opt,name=start_index,json=startIndex,proto3" json:"start_index,omitempty"`
+	// The maximum number of leaves in each response. If zero or negative, the
+	// server picks a default.
+	MaxBatchSize int64 `protobuf:"varint,3,opt,name=max_batch_size,json=maxBatchSize,proto3" json:"max_batch_size,omitempty"`
+	// The tree size of a root already trusted by the client, if any. The first
+	// response will contain a consistency proof from this tree size.
+	FirstTreeSize        int64     `protobuf:"varint,4,opt,name=first_tree_size,json=firstTreeSize,proto3" json:"first_tree_size,omitempty"`
+	ChargeTo             *ChargeTo `protobuf:"bytes,5,opt,name=charge_to,json=chargeTo,proto3" json:"charge_to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *WatchLeavesRequest) Reset()         { *m = WatchLeavesRequest{} }
+func (m *WatchLeavesRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchLeavesRequest) ProtoMessage()    {}
+func (*WatchLeavesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5ad20a6a54aa5af3, []int{33}
+}
+
+func (m *WatchLeavesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchLeavesRequest.Unmarshal(m, b)
+}
+func (m *WatchLeavesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchLeavesRequest.Marshal(b, m, deterministic)
+}
+func (m *WatchLeavesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchLeavesRequest.Merge(m, src)
+}
+func (m *WatchLeavesRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchLeavesRequest.Size(m)
+}
+func (m *WatchLeavesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchLeavesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchLeavesRequest proto.InternalMessageInfo
+
+func (m *WatchLeavesRequest) GetLogId() int64 {
+	if m != nil {
+		return m.LogId
+	}
+	return 0
+}
+
+func (m *WatchLeavesRequest) GetStartIndex() int64 {
+	if m != nil {
+		return m.StartIndex
+	}
+	return 0
+}
+
+func (m *WatchLeavesRequest) GetMaxBatchSize() int64 {
+	if m != nil {
+		return m.MaxBatchSize
+	}
+	return 0
+}
+
+func (m *WatchLeavesRequest) GetFirstTreeSize() int64 {
+	if m != nil {
+		return m.FirstTreeSize
+	}
+	return 0
+}
+
+func (m *WatchLeavesRequest) GetChargeTo() *ChargeTo {
+	if m != nil {
+		return m.ChargeTo
+	}
+	return nil
+}
+
+type WatchLeavesResponse struct {
+	// Log leaves in order, continuing from the last leaf sent in the previous
+	// response (or from `request.start_index` for the first response).
+	Leaves []*LogLeaf `protobuf:"bytes,1,rep,name=leaves,proto3" json:"leaves,omitempty"`
+	// A signed log root whose tree size covers all of the leaves sent so far.
+	SignedLogRoot *SignedLogRoot `protobuf:"bytes,2,opt,name=signed_log_root,json=signedLogRoot,proto3" json:"signed_log_root,omitempty"`
+	// A consistency proof from the root sent in the previous response (or from
+	// `request.first_tree_size` for the first response) to `signed_log_root`.
+	// Empty if the tree size hasn't changed, or there is no previous root.
+	Proof                *Proof   `protobuf:"bytes,3,opt,name=proof,proto3" json:"proof,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchLeavesResponse) Reset()         { *m = WatchLeavesResponse{} }
+func (m *WatchLeavesResponse) String() string { return proto.CompactTextString(m) }
+func (*WatchLeavesResponse) ProtoMessage()    {}
+func (*WatchLeavesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5ad20a6a54aa5af3, []int{34}
+}
+
+func (m *WatchLeavesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchLeavesResponse.Unmarshal(m, b)
+}
+func (m *WatchLeavesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchLeavesResponse.Marshal(b, m, deterministic)
+}
+func (m *WatchLeavesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchLeavesResponse.Merge(m, src)
+}
+func (m *WatchLeavesResponse) XXX_Size() int {
+	return xxx_messageInfo_WatchLeavesResponse.Size(m)
+}
+func (m *WatchLeavesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchLeavesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchLeavesResponse proto.InternalMessageInfo
+
+func (m *WatchLeavesResponse) GetLeaves() []*LogLeaf {
+	if m != nil {
+		return m.Leaves
+	}
+	return nil
+}
+
+func (m *WatchLeavesResponse) GetSignedLogRoot() *SignedLogRoot {
+	if m != nil {
+		return m.SignedLogRoot
+	}
+	return nil
+}
+
+func (m *WatchLeavesResponse) GetProof() *Proof {
+	if m != nil {
+		return m.Proof
+	}
+	return nil
+}
+
 // QueuedLogLeaf provides the result of submitting an entry to the log.
 // TODO(pavelkalinnikov): Consider renaming it to AddLogLeafResult or the like.
 type QueuedLogLeaf struct {
@@ -1576,7 +2036,7 @@ func (m *QueuedLogLeaf) Reset()         { *m = QueuedLogLeaf{} }
 func (m *QueuedLogLeaf) String() string { return proto.CompactTextString(m) }
 func (*QueuedLogLeaf) ProtoMessage()    {}
 func (*QueuedLogLeaf) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{29}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{35}
 }
 
 func (m *QueuedLogLeaf) XXX_Unmarshal(b []byte) error {
@@ -1675,17 +2135,23 @@ type LogLeaf struct {
 	QueueTimestamp *timestamp.Timestamp `protobuf:"bytes,6,opt,name=queue_timestamp,json=queueTimestamp,proto3" json:"queue_timestamp,omitempty"`
 	// integrate_timestamp holds the time at which this leaf was integrated into
 	// the tree.  Clients should not set this field on submissions.
-	IntegrateTimestamp   *timestamp.Timestamp `protobuf:"bytes,7,opt,name=integrate_timestamp,json=integrateTimestamp,proto3" json:"integrate_timestamp,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	IntegrateTimestamp *timestamp.Timestamp `protobuf:"bytes,7,opt,name=integrate_timestamp,json=integrateTimestamp,proto3" json:"integrate_timestamp,omitempty"`
+	// data_pruned is set on read operations if leaf_value and extra_data of this
+	// leaf have been dropped according to the tree's retention_policy.  The
+	// Merkle leaf hash, leaf index and timestamps are still returned, so that
+	// inclusion and consistency proofs keep working.  Clients should not set
+	// this field on submissions.
+	DataPruned           bool     `protobuf:"varint,8,opt,name=data_pruned,json=dataPruned,proto3" json:"data_pruned,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *LogLeaf) Reset()         { *m = LogLeaf{} }
 func (m *LogLeaf) String() string { return proto.CompactTextString(m) }
 func (*LogLeaf) ProtoMessage()    {}
 func (*LogLeaf) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{30}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{36}
 }
 
 func (m *LogLeaf) XXX_Unmarshal(b []byte) error {
@@ -1755,6 +2221,13 @@ func (m *LogLeaf) GetIntegrateTimestamp() *timestamp.Timestamp {
 	return nil
 }
 
+func (m *LogLeaf) GetDataPruned() bool {
+	if m != nil {
+		return m.DataPruned
+	}
+	return false
+}
+
 // Proof holds a consistency or inclusion proof for a Merkle tree, as returned
 // by the API.
 type Proof struct {
@@ -1772,7 +2245,7 @@ func (m *Proof) Reset()         { *m = Proof{} }
 func (m *Proof) String() string { return proto.CompactTextString(m) }
 func (*Proof) ProtoMessage()    {}
 func (*Proof) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{31}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{37}
 }
 
 func (m *Proof) XXX_Unmarshal(b []byte) error {
@@ -1817,6 +2290,8 @@ func init() {
 	proto.RegisterType((*GetInclusionProofResponse)(nil), "trillian.GetInclusionProofResponse")
 	proto.RegisterType((*GetInclusionProofByHashRequest)(nil), "trillian.GetInclusionProofByHashRequest")
 	proto.RegisterType((*GetInclusionProofByHashResponse)(nil), "trillian.GetInclusionProofByHashResponse")
+	proto.RegisterType((*GetBatchInclusionProofRequest)(nil), "trillian.GetBatchInclusionProofRequest")
+	proto.RegisterType((*GetBatchInclusionProofResponse)(nil), "trillian.GetBatchInclusionProofResponse")
 	proto.RegisterType((*GetConsistencyProofRequest)(nil), "trillian.GetConsistencyProofRequest")
 	proto.RegisterType((*GetConsistencyProofResponse)(nil), "trillian.GetConsistencyProofResponse")
 	proto.RegisterType((*GetLatestSignedLogRootRequest)(nil), "trillian.GetLatestSignedLogRootRequest")
@@ -1835,8 +2310,12 @@ func init() {
 	proto.RegisterType((*GetLeavesByIndexResponse)(nil), "trillian.GetLeavesByIndexResponse")
 	proto.RegisterType((*GetLeavesByRangeRequest)(nil), "trillian.GetLeavesByRangeRequest")
 	proto.RegisterType((*GetLeavesByRangeResponse)(nil), "trillian.GetLeavesByRangeResponse")
+	proto.RegisterType((*GetLeavesByRangeWithProofRequest)(nil), "trillian.GetLeavesByRangeWithProofRequest")
+	proto.RegisterType((*GetLeavesByRangeWithProofResponse)(nil), "trillian.GetLeavesByRangeWithProofResponse")
 	proto.RegisterType((*GetLeavesByHashRequest)(nil), "trillian.GetLeavesByHashRequest")
 	proto.RegisterType((*GetLeavesByHashResponse)(nil), "trillian.GetLeavesByHashResponse")
+	proto.RegisterType((*WatchLeavesRequest)(nil), "trillian.WatchLeavesRequest")
+	proto.RegisterType((*WatchLeavesResponse)(nil), "trillian.WatchLeavesResponse")
 	proto.RegisterType((*QueuedLogLeaf)(nil), "trillian.QueuedLogLeaf")
 	proto.RegisterType((*LogLeaf)(nil), "trillian.LogLeaf")
 	proto.RegisterType((*Proof)(nil), "trillian.Proof")
@@ -1845,104 +2324,124 @@ func init() {
 func init() { proto.RegisterFile("trillian_log_api.proto", fileDescriptor_5ad20a6a54aa5af3) }
 
 var fileDescriptor_5ad20a6a54aa5af3 = []byte{
-	// 1540 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x58, 0x5b, 0x6f, 0xdc, 0x44,
-	0x14, 0xae, 0xe3, 0x5c, 0x4f, 0x9a, 0x6c, 0x32, 0xa1, 0xcd, 0xc6, 0x69, 0xda, 0xd4, 0x69, 0xda,
-	0x6d, 0x28, 0x31, 0x29, 0x42, 0xa0, 0xa8, 0x02, 0x35, 0x29, 0x0a, 0xa1, 0x0b, 0x14, 0x27, 0x42,
-	0x15, 0x3c, 0x58, 0x5e, 0xef, 0xc4, 0xb1, 0xd8, 0x78, 0xb6, 0xf6, 0x6c, 0xd4, 0x6d, 0x55, 0x89,
-	0x8b, 0xca, 0xe5, 0x01, 0x78, 0x80, 0x87, 0xbe, 0x70, 0x79, 0x43, 0xfc, 0x01, 0x7e, 0x06, 0x42,
-	0xe2, 0x2f, 0xf0, 0xc0, 0xcf, 0x40, 0x9e, 0x19, 0xaf, 0x2f, 0x6b, 0x7b, 0x77, 0x4b, 0x5b, 0x78,
-	0x5b, 0x9f, 0x39, 0x73, 0xce, 0x77, 0xbe, 0x99, 0x73, 0xe6, 0x9c, 0x85, 0xd3, 0xd4, 0x73, 0x1a,
-	0x0d, 0xc7, 0x74, 0x8d, 0x06, 0xb1, 0x0d, 0xb3, 0xe9, 0xac, 0x37, 0x3d, 0x42, 0x09, 0x1a, 0x0f,
-	0xe5, 0xca, 0x19, 0x9b, 0x10, 0xbb, 0x81, 0x35, 0xb3, 0xe9, 0x68, 0xa6, 0xeb, 0x12, 0x6a, 0x52,
-	0x87, 0xb8, 0x3e, 0xd7, 0x53, 0xce, 0x89, 0x55, 0xf6, 0x55, 0x6b, 0x1d, 0x68, 0xd4, 0x39, 0xc2,
-	0x3e, 0x35, 0x8f, 0x9a, 0x42, 0x61, 0x5e, 0x28, 0x78, 0x4d, 0x4b, 0xf3, 0xa9, 0x49, 0x5b, 0xe1,
-	0xce, 0xe9, 0xd0, 0x03, 0xff, 0x56, 0xcf, 0xc2, 0xf8, 0xf6, 0xa1, 0xe9, 0xd9, 0x78, 0x9f, 0x20,
-	0x04, 0xc3, 0x2d, 0x1f, 0x7b, 0x65, 0x69, 0x59, 0xae, 0x4c, 0xe8, 0xec, 0xb7, 0xfa, 0x89, 0x04,
-	0x33, 0xef, 0xb5, 0x70, 0x0b, 0x57, 0xb1, 0x79, 0xa0, 0xe3, 0x3b, 0x2d, 0xec, 0x53, 0x74, 0x0a,
-	0x46, 0x03, 0xdc, 0x4e, 0xbd, 0x2c, 0x2d, 0x4b, 0x15, 0x59, 0x1f, 0x69, 0x10, 0x7b, 0xb7, 0x8e,
-	0x56, 0x61, 0xb8, 0x81, 0xcd, 0x83, 0xf2, 0xd0, 0xb2, 0x54, 0x99, 0xbc, 0x3a, 0xbb, 0xde, 0x71,
-	0x55, 0x25, 0x36, 0xdb, 0xce, 0x96, 0x91, 0x06, 0x13, 0x16, 0x73, 0x69, 0x50, 0x52, 0x96, 0x99,
-	0x2e, 0x8a, 0x74, 0x43, 0x34, 0xfa, 0xb8, 0x25, 0x7e, 0xa9, 0x6f, 0xc3, 0x6c, 0x0c, 0x82, 0xdf,
-	0x24, 0xae, 0x8f, 0xd1, 0xab, 0x30, 0x79, 0x27, 0x10, 0xd6, 0x8d, 0x98, 0xcf, 0xf9, 0xc8, 0x0e,
-	0xdb, 0x51, 0x0f, 0x3d, 0x03, 0xd7, 0x0d, 0x7e, 0xab, 0x5f, 0x4a, 0x30, 0x7f, 0xbd, 0x5e, 0xdf,
-	0x0b, 0x82, 0x71, 0x2d, 0x2e, 0xfc, 0x8f, 0x22, 0xbb, 0x09, 0xe5, 0x6e, 0x24, 0x22, 0x40, 0x0d,
-	0x46, 0x3d, 0xec, 0xb7, 0x1a, 0xb4, 0x57, 0x6c, 0x42, 0x4d, 0xfd, 0x51, 0x82, 0xf2, 0x0e, 0xa6,
-	0xbb, 0xae, 0xd5, 0x68, 0xf9, 0x0e, 0x71, 0x6f, 0x79, 0x84, 0xf4, 0x0a, 0x6c, 0x09, 0x20, 0x40,
-	0x6e, 0x38, 0x6e, 0x1d, 0xdf, 0x65, 0x8e, 0x64, 0x7d, 0x22, 0x90, 0xec, 0x06, 0x02, 0xb4, 0x08,
-	0x13, 0xd4, 0xc3, 0xd8, 0xf0, 0x9d, 0x7b, 0x98, 0x05, 0x24, 0xeb, 0xe3, 0x81, 0x60, 0xcf, 0xb9,
-	0x87, 0x93, 0xd1, 0x0e, 0xf7, 0x11, 0xed, 0x67, 0x12, 0x2c, 0x64, 0x00, 0x14, 0xf1, 0xae, 0xc2,
-	0x48, 0x33, 0x10, 0x88, 0x70, 0x4b, 0x91, 0x29, 0xae, 0xc7, 0x57, 0xd1, 0xeb, 0x50, 0xf2, 0x1d,
-	0xdb, 0x0d, 0xce, 0x9d, 0xd8, 0x86, 0x47, 0x08, 0x15, 0x4c, 0xc7, 0xf8, 0xd9, 0x63, 0x0a, 0x55,
-	0x62, 0xeb, 0x84, 0x50, 0x7d, 0xca, 0x8f, 0x7f, 0xaa, 0xbf, 0x4b, 0x70, 0xb6, 0x0b, 0xc5, 0x56,
-	0xfb, 0x4d, 0xd3, 0x3f, 0xec, 0x41, 0xd6, 0x22, 0x30, 0x6a, 0x8c, 0x43, 0xd3, 0x3f, 0x64, 0x28,
-	0x4f, 0xea, 0xe3, 0x81, 0x20, 0xd8, 0x5a, 0x4c, 0xd5, 0x1a, 0xcc, 0x12, 0xaf, 0x8e, 0x3d, 0xa3,
-	0xd6, 0x36, 0x7c, 0x71, 0xda, 0x8c, 0xb2, 0x71, 0xbd, 0xc4, 0x16, 0xb6, 0xda, 0xe1, 0x25, 0x48,
-	0xd2, 0x3a, 0xd2, 0x07, 0xad, 0x5f, 0x49, 0x70, 0x2e, 0x37, 0xa0, 0x6e, 0x72, 0xe5, 0xa7, 0x49,
-	0xee, 0x6f, 0x12, 0x28, 0x3b, 0x98, 0x6e, 0x13, 0xd7, 0x77, 0x7c, 0x8a, 0x5d, 0xab, 0xdd, 0xcf,
-	0x2d, 0xbc, 0x08, 0xa5, 0x03, 0xc7, 0xf3, 0xa9, 0x11, 0x31, 0xc8, 0xaf, 0xe2, 0x14, 0x13, 0xef,
-	0x87, 0x34, 0x56, 0x60, 0xc6, 0xc7, 0x16, 0x71, 0xeb, 0x46, 0x9a, 0xea, 0x69, 0x2e, 0xdf, 0x7f,
-	0xec, 0xbb, 0xf9, 0x50, 0x82, 0xc5, 0x4c, 0xe0, 0xcf, 0xf8, 0x76, 0x7e, 0x2b, 0xc1, 0xd2, 0x0e,
-	0xa6, 0x55, 0x93, 0x62, 0x9f, 0x26, 0x35, 0x8b, 0x39, 0x4c, 0x44, 0x3c, 0xd4, 0x3b, 0xe2, 0x2c,
-	0xd2, 0xe5, 0x0c, 0xd2, 0x83, 0x72, 0x79, 0x36, 0x0f, 0x91, 0x20, 0x27, 0x23, 0xea, 0xa1, 0x41,
-	0xa2, 0x8e, 0xd8, 0x95, 0x8b, 0xd8, 0x55, 0x0f, 0xe0, 0xcc, 0x0e, 0xa6, 0x89, 0x72, 0xb9, 0x4d,
-	0x5a, 0xee, 0x93, 0xa6, 0x46, 0x7d, 0x8d, 0x9d, 0x41, 0x96, 0x1f, 0x11, 0x70, 0x58, 0x36, 0xad,
-	0x40, 0x1a, 0x2f, 0x9b, 0x4c, 0x4d, 0xfd, 0x41, 0x82, 0xf9, 0x1d, 0x4c, 0xdf, 0x70, 0xa9, 0xd7,
-	0xbe, 0xee, 0xd6, 0xff, 0x77, 0x85, 0xf8, 0x57, 0xfe, 0x52, 0xa4, 0xf0, 0x0d, 0x76, 0xd3, 0xc3,
-	0x27, 0x51, 0x2e, 0x7e, 0x12, 0x33, 0xae, 0xc6, 0xf0, 0x40, 0x09, 0x71, 0x1b, 0xa6, 0x77, 0x5d,
-	0x87, 0x06, 0x9f, 0x4f, 0xf8, 0x94, 0x6f, 0x40, 0xa9, 0x63, 0x59, 0xc4, 0xbe, 0x01, 0x63, 0x96,
-	0x87, 0x4d, 0x8a, 0xb9, 0xed, 0x02, 0x94, 0xa1, 0x9e, 0xfa, 0x85, 0x04, 0x28, 0xec, 0x4e, 0x8e,
-	0xb1, 0xdf, 0x03, 0xe4, 0x65, 0x18, 0x6d, 0x30, 0x3d, 0x51, 0x88, 0x33, 0x78, 0x13, 0x0a, 0x83,
-	0x37, 0x13, 0x7b, 0x30, 0x97, 0x00, 0x22, 0x62, 0xba, 0x06, 0x53, 0x51, 0xa3, 0x14, 0x79, 0xce,
-	0x6d, 0x27, 0x4e, 0x76, 0x5a, 0xa5, 0x63, 0xec, 0xab, 0xdf, 0x48, 0xb0, 0x90, 0x6a, 0x51, 0x9e,
-	0x5e, 0x94, 0xfd, 0xdc, 0xdd, 0x77, 0x41, 0xc9, 0xc2, 0x13, 0x1d, 0x20, 0xef, 0x86, 0x7a, 0x86,
-	0x19, 0xea, 0xa9, 0x1f, 0xf3, 0x64, 0xe5, 0x86, 0xb6, 0xda, 0x2c, 0xdf, 0x06, 0x4c, 0x56, 0x39,
-	0x99, 0xac, 0x03, 0xbf, 0xe0, 0x9f, 0xf3, 0x7c, 0x4c, 0x41, 0x10, 0x21, 0x0d, 0x40, 0xe6, 0xbf,
-	0x7e, 0x7d, 0x1e, 0x25, 0xb9, 0xd0, 0x4d, 0xd7, 0xc6, 0x3d, 0xb8, 0x38, 0x07, 0x93, 0x3e, 0x35,
-	0x3d, 0x9a, 0xa8, 0x5c, 0xc0, 0x44, 0x9c, 0x8d, 0xe7, 0x60, 0x84, 0x97, 0x49, 0x5e, 0xb6, 0xf8,
-	0xc7, 0xe0, 0xe7, 0x9e, 0xe2, 0x48, 0x40, 0xeb, 0xe2, 0x48, 0x7a, 0x0c, 0x8e, 0x06, 0x7a, 0xab,
-	0x82, 0xe2, 0x79, 0x3a, 0x06, 0x64, 0xf0, 0xbe, 0x51, 0x4e, 0xf4, 0x8d, 0x99, 0xad, 0xa1, 0xfc,
-	0x84, 0x5a, 0xc3, 0x87, 0xc9, 0xf3, 0x4c, 0xb4, 0x84, 0xcf, 0xf2, 0x5e, 0xd5, 0x60, 0x2a, 0x91,
-	0x7d, 0x9d, 0xd7, 0x43, 0x2a, 0x7e, 0x3d, 0xd6, 0x60, 0x94, 0x4f, 0xaf, 0x9d, 0x82, 0xce, 0xe7,
-	0xda, 0x75, 0xaf, 0x69, 0xad, 0xef, 0xb1, 0x15, 0x5d, 0x68, 0xa8, 0x7f, 0x0c, 0xc1, 0x58, 0x68,
-	0xbe, 0x02, 0x33, 0x47, 0xd8, 0xfb, 0xa8, 0x81, 0x8d, 0x88, 0x78, 0x89, 0x35, 0xec, 0xd3, 0x5c,
-	0x5e, 0x0d, 0xe9, 0x0f, 0x53, 0xf9, 0xd8, 0x6c, 0xb4, 0xb0, 0x68, 0xea, 0xd9, 0x69, 0xbd, 0x1f,
-	0x08, 0x82, 0x65, 0x7c, 0x97, 0x7a, 0xa6, 0x51, 0x37, 0xa9, 0xc9, 0x82, 0x3e, 0xa9, 0x4f, 0x30,
-	0xc9, 0x0d, 0x93, 0x9a, 0xa9, 0x42, 0x30, 0x9c, 0x7e, 0xb5, 0xaf, 0x00, 0xe2, 0xcb, 0x75, 0xec,
-	0x52, 0x87, 0xb6, 0x39, 0x90, 0x11, 0x66, 0x65, 0x86, 0xa9, 0x89, 0x05, 0x06, 0x65, 0x1b, 0x4a,
-	0xac, 0xf4, 0x1a, 0x9d, 0x61, 0xbe, 0x3c, 0xca, 0xa2, 0x56, 0xc2, 0xa8, 0xc3, 0x71, 0x7f, 0x7d,
-	0x3f, 0xd4, 0xd0, 0xa7, 0xd9, 0x96, 0xce, 0x37, 0xba, 0x09, 0x73, 0x8e, 0x4b, 0xb1, 0xed, 0x99,
-	0x34, 0x6e, 0x68, 0xac, 0xa7, 0x21, 0xd4, 0xd9, 0xd6, 0x91, 0xa9, 0x37, 0x60, 0x84, 0xbd, 0xf9,
-	0xa9, 0x38, 0xa5, 0x74, 0x9c, 0xa7, 0x61, 0x34, 0x88, 0x0c, 0xfb, 0x65, 0x99, 0xdd, 0x6e, 0xf1,
-	0xf5, 0xd6, 0xf0, 0xf8, 0xd0, 0x8c, 0x7c, 0xf5, 0xef, 0x29, 0x98, 0xdc, 0x17, 0xe7, 0x5b, 0x25,
-	0x36, 0x72, 0x61, 0xa2, 0x33, 0xce, 0x23, 0x25, 0x55, 0x9f, 0x63, 0xc3, 0xb8, 0xb2, 0x98, 0xb9,
-	0xc6, 0xaf, 0xaf, 0x5a, 0xf9, 0xf4, 0xcf, 0xbf, 0xbe, 0x1b, 0x52, 0xd5, 0x25, 0xed, 0x78, 0xa3,
-	0x86, 0xa9, 0xb9, 0xa1, 0x35, 0x88, 0xed, 0x6b, 0xf7, 0x79, 0x02, 0x3e, 0xd0, 0xf8, 0xd5, 0xdd,
-	0x94, 0xd6, 0xd0, 0xd7, 0x12, 0xcc, 0xa4, 0xa7, 0x6c, 0x74, 0x3e, 0xb2, 0x9d, 0xf3, 0x5f, 0x80,
-	0xa2, 0x16, 0xa9, 0x08, 0x14, 0x57, 0x19, 0x8a, 0x2b, 0xea, 0xa5, 0x62, 0x14, 0x61, 0x62, 0xd7,
-	0x03, 0x3c, 0x3f, 0x4b, 0x30, 0xdb, 0x35, 0xaf, 0xa1, 0x98, 0xb7, 0xbc, 0x21, 0x5e, 0x59, 0x29,
-	0xd4, 0x11, 0x90, 0xb6, 0x18, 0xa4, 0x6b, 0x68, 0xb3, 0x10, 0x92, 0x76, 0x3f, 0x3a, 0xd0, 0x07,
-	0x9b, 0x4e, 0x68, 0xca, 0xe0, 0xcd, 0xdd, 0x2f, 0xbc, 0x6e, 0x64, 0x8d, 0x94, 0xa8, 0x52, 0x00,
-	0x22, 0x51, 0x0e, 0x95, 0xcb, 0x7d, 0x68, 0x0a, 0xd0, 0xaf, 0x30, 0xd0, 0x1b, 0x48, 0x2b, 0xe6,
-	0x31, 0xc2, 0x59, 0xe3, 0xc9, 0x84, 0xbe, 0x97, 0x60, 0x2e, 0x63, 0x6e, 0x43, 0x17, 0x12, 0xbe,
-	0x73, 0xe6, 0x51, 0x65, 0xb5, 0x87, 0x96, 0x40, 0xf7, 0x22, 0x43, 0xb7, 0x86, 0x2a, 0xd9, 0xe8,
-	0x36, 0xad, 0x68, 0xa3, 0x20, 0xf0, 0x91, 0x78, 0x24, 0xba, 0x87, 0x26, 0x74, 0x29, 0xe1, 0x33,
-	0x7f, 0xd0, 0x53, 0x2a, 0xbd, 0x15, 0x05, 0xbe, 0xe7, 0x19, 0xbe, 0x55, 0xb4, 0x92, 0xc3, 0x5e,
-	0x50, 0xb1, 0xfd, 0xcd, 0x06, 0xb3, 0x80, 0x7e, 0x92, 0xe0, 0x54, 0xe6, 0x74, 0x83, 0x2e, 0x26,
-	0x1c, 0xe6, 0x8e, 0x59, 0xca, 0xa5, 0x9e, 0x7a, 0x02, 0xd7, 0xcb, 0x0c, 0x97, 0x86, 0x5e, 0xe8,
-	0x33, 0x3b, 0xf8, 0x3c, 0xc5, 0x12, 0x36, 0x3d, 0x9e, 0xc4, 0x13, 0x36, 0x67, 0xb4, 0x52, 0xd4,
-	0x22, 0x95, 0x64, 0xc2, 0xa2, 0xb5, 0xfe, 0xb3, 0x03, 0x59, 0x30, 0x26, 0x06, 0x05, 0x54, 0x8e,
-	0x5c, 0x24, 0xa7, 0x12, 0x65, 0x21, 0x63, 0x45, 0xf8, 0x5c, 0x61, 0x3e, 0x97, 0xd4, 0xc5, 0x9c,
-	0xeb, 0xe3, 0xb8, 0x0e, 0x45, 0x55, 0x98, 0x8c, 0x75, 0xef, 0xe8, 0x4c, 0x77, 0xed, 0x8b, 0xfa,
-	0x6e, 0x65, 0x29, 0x67, 0x55, 0x38, 0x3c, 0x81, 0x4c, 0x40, 0xdd, 0x5d, 0x32, 0x5a, 0xc9, 0xad,
-	0x68, 0x31, 0xdb, 0x17, 0x8a, 0x95, 0x3a, 0x2e, 0x3e, 0x64, 0x87, 0x94, 0xe8, 0x59, 0x53, 0x87,
-	0x94, 0xd5, 0x52, 0xa7, 0x0e, 0x29, 0xb3, 0xe5, 0xed, 0x32, 0xce, 0x9a, 0xbd, 0x1c, 0xe3, 0xf1,
-	0x1e, 0x35, 0xc7, 0x78, 0xa2, 0x57, 0x54, 0x4f, 0xa0, 0xdb, 0x50, 0x4a, 0x35, 0x45, 0x68, 0x39,
-	0x73, 0x63, 0xbc, 0x98, 0x9d, 0x2f, 0xd0, 0x08, 0x2d, 0x6f, 0xbd, 0x03, 0x0b, 0x16, 0x39, 0x0a,
-	0x5f, 0xd9, 0xe4, 0x5f, 0xed, 0x5b, 0x73, 0xb1, 0x47, 0xf0, 0x7a, 0xd3, 0xb9, 0x15, 0x08, 0x6f,
-	0x49, 0x1f, 0x28, 0xb6, 0x43, 0x0f, 0x5b, 0xb5, 0x75, 0x8b, 0x1c, 0x69, 0xe2, 0x5f, 0xfb, 0x70,
-	0x63, 0x6d, 0x94, 0xed, 0x7c, 0xe9, 0x9f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xe5, 0xa4, 0xd4, 0xe5,
-	0x30, 0x18, 0x00, 0x00,
+	// 1861 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x59, 0xcd, 0x6f, 0x1b, 0x41,
+	0x15, 0x67, 0xb2, 0x4e, 0xe2, 0x3c, 0xe7, 0x73, 0xd2, 0x26, 0xce, 0xa6, 0xce, 0xc7, 0xa6, 0x69,
+	0xdd, 0xb4, 0xc4, 0x4d, 0xab, 0x0a, 0x14, 0x2a, 0x50, 0x92, 0xa2, 0x34, 0x34, 0x2a, 0xc1, 0x89,
+	0xda, 0x0a, 0x0e, 0xab, 0xb5, 0x77, 0x62, 0xaf, 0x70, 0x76, 0xdd, 0xdd, 0x71, 0x94, 0xb4, 0xaa,
+	0x84, 0x40, 0x45, 0xe5, 0x00, 0x1c, 0xe0, 0xd0, 0x0b, 0x1f, 0x52, 0x0f, 0xb4, 0xe2, 0xce, 0x91,
+	0x13, 0x37, 0x10, 0x17, 0x8e, 0x5c, 0xf9, 0x03, 0xe0, 0x3f, 0x40, 0x3b, 0x33, 0xeb, 0xfd, 0xf0,
+	0xee, 0xda, 0x6e, 0xda, 0xc2, 0xcd, 0xfb, 0xe6, 0xcd, 0x9b, 0xdf, 0xfb, 0xcd, 0x9b, 0x79, 0x6f,
+	0x9e, 0x61, 0x86, 0xda, 0x46, 0xa3, 0x61, 0x68, 0xa6, 0xda, 0xb0, 0x6a, 0xaa, 0xd6, 0x34, 0xd6,
+	0x9b, 0xb6, 0x45, 0x2d, 0x9c, 0xf5, 0xe4, 0xf2, 0x95, 0x9a, 0x65, 0xd5, 0x1a, 0xa4, 0xa4, 0x35,
+	0x8d, 0x92, 0x66, 0x9a, 0x16, 0xd5, 0xa8, 0x61, 0x99, 0x0e, 0xd7, 0x93, 0x17, 0xc5, 0x28, 0xfb,
+	0xaa, 0xb4, 0x8e, 0x4b, 0xd4, 0x38, 0x21, 0x0e, 0xd5, 0x4e, 0x9a, 0x42, 0x61, 0x56, 0x28, 0xd8,
+	0xcd, 0x6a, 0xc9, 0xa1, 0x1a, 0x6d, 0x79, 0x33, 0xc7, 0xbd, 0x15, 0xf8, 0xb7, 0xb2, 0x00, 0xd9,
+	0x9d, 0xba, 0x66, 0xd7, 0xc8, 0x91, 0x85, 0x31, 0x64, 0x5a, 0x0e, 0xb1, 0xf3, 0x68, 0x49, 0x2a,
+	0x8e, 0x94, 0xd9, 0x6f, 0xe5, 0x2f, 0x08, 0x26, 0xbf, 0xd7, 0x22, 0x2d, 0xb2, 0x4f, 0xb4, 0xe3,
+	0x32, 0x79, 0xde, 0x22, 0x0e, 0xc5, 0x97, 0x61, 0xc8, 0xc5, 0x6d, 0xe8, 0x79, 0xb4, 0x84, 0x8a,
+	0x52, 0x79, 0xb0, 0x61, 0xd5, 0xf6, 0x74, 0xbc, 0x0a, 0x99, 0x06, 0xd1, 0x8e, 0xf3, 0x03, 0x4b,
+	0xa8, 0x98, 0xbb, 0x33, 0xb5, 0xde, 0x5e, 0x6a, 0xdf, 0xaa, 0xb1, 0xe9, 0x6c, 0x18, 0x97, 0x60,
+	0xa4, 0xca, 0x96, 0x54, 0xa9, 0x95, 0x97, 0x98, 0x2e, 0xf6, 0x75, 0x3d, 0x34, 0xe5, 0x6c, 0xd5,
+	0xc3, 0xb5, 0x05, 0x05, 0x9b, 0xd0, 0x96, 0x6d, 0xaa, 0x8e, 0x51, 0x33, 0x89, 0xae, 0x12, 0x93,
+	0xda, 0xe7, 0x6a, 0xdb, 0xe7, 0x7c, 0x66, 0x09, 0x15, 0xb3, 0x65, 0x99, 0x2b, 0x1d, 0x32, 0x9d,
+	0x6f, 0xbb, 0x2a, 0x47, 0x9e, 0x86, 0xf2, 0x0e, 0xc1, 0x54, 0xc0, 0x0d, 0xa7, 0x69, 0x99, 0x0e,
+	0xc1, 0x5f, 0x87, 0xdc, 0x73, 0x57, 0xa8, 0xab, 0x01, 0xdc, 0xb3, 0x3e, 0x16, 0x36, 0x43, 0xf7,
+	0xd0, 0x03, 0xd7, 0x75, 0x7f, 0xe3, 0x23, 0x98, 0x49, 0xc0, 0xc2, 0x1d, 0x5a, 0xf0, 0x8d, 0xc4,
+	0xe1, 0x29, 0x5f, 0x72, 0xe2, 0x50, 0xbe, 0x41, 0x30, 0xbb, 0xa5, 0xeb, 0x87, 0x2e, 0xcd, 0x66,
+	0x95, 0xe8, 0xff, 0x3b, 0xce, 0x95, 0x47, 0x90, 0xef, 0x44, 0x22, 0x68, 0x2b, 0xc1, 0x90, 0x4d,
+	0x9c, 0x56, 0x83, 0x76, 0x63, 0x4c, 0xa8, 0x29, 0xbf, 0x45, 0x90, 0xdf, 0x25, 0x74, 0xcf, 0xac,
+	0x36, 0x5a, 0x8e, 0x61, 0x99, 0x07, 0xb6, 0x65, 0x75, 0x73, 0xac, 0x00, 0xe0, 0x22, 0x57, 0x0d,
+	0x53, 0x27, 0x67, 0x6c, 0x21, 0xa9, 0x3c, 0xe2, 0x4a, 0xf6, 0x5c, 0x01, 0x9e, 0x87, 0x11, 0x6a,
+	0x13, 0xa2, 0x3a, 0xc6, 0x0b, 0xc2, 0x1c, 0x92, 0xca, 0x59, 0x57, 0x70, 0x68, 0xbc, 0x20, 0x61,
+	0x6f, 0x33, 0x3d, 0x78, 0xfb, 0x13, 0x04, 0x73, 0x31, 0x00, 0x85, 0xbf, 0xab, 0x30, 0xd8, 0x74,
+	0x05, 0xc2, 0xdd, 0x09, 0xdf, 0x14, 0xd7, 0xe3, 0xa3, 0xf8, 0x5b, 0x30, 0x21, 0x62, 0xc2, 0xf5,
+	0xc7, 0xb6, 0x2c, 0x9a, 0x97, 0xa2, 0xfc, 0xf0, 0x60, 0xd8, 0xb7, 0x6a, 0x65, 0xcb, 0xa2, 0xe5,
+	0x31, 0x27, 0xf8, 0xa9, 0xfc, 0x1d, 0xc1, 0x42, 0x07, 0x8a, 0xed, 0xf3, 0x87, 0x9a, 0x53, 0xef,
+	0x42, 0xd6, 0x3c, 0x30, 0x6a, 0xd4, 0xba, 0xe6, 0xd4, 0x19, 0xca, 0xd1, 0x72, 0xd6, 0x15, 0xb8,
+	0x53, 0xd3, 0xa9, 0x5a, 0x83, 0x29, 0xcb, 0xd6, 0x89, 0xad, 0x56, 0xce, 0x55, 0x47, 0xec, 0xb6,
+	0x38, 0x4f, 0x13, 0x6c, 0x60, 0xfb, 0xdc, 0x0b, 0x82, 0x30, 0xad, 0x83, 0x3d, 0xd0, 0xfa, 0x33,
+	0x04, 0x8b, 0x89, 0x0e, 0x75, 0x92, 0x2b, 0x7d, 0x4e, 0x72, 0xdf, 0x21, 0x28, 0xec, 0x12, 0xba,
+	0xad, 0xd1, 0x6a, 0xfd, 0x42, 0x81, 0x28, 0x7d, 0xce, 0x40, 0x7c, 0xcb, 0x43, 0x20, 0x16, 0xa5,
+	0x20, 0x2c, 0x8c, 0x07, 0x45, 0xf1, 0xcc, 0xc0, 0x90, 0x1b, 0x05, 0xc4, 0x61, 0x50, 0x47, 0xcb,
+	0xe2, 0xeb, 0xe2, 0x04, 0xfe, 0x09, 0x81, 0xbc, 0x4b, 0xe8, 0x8e, 0x65, 0x3a, 0x86, 0x43, 0x89,
+	0x59, 0x3d, 0xef, 0x85, 0xbd, 0x6b, 0x30, 0x71, 0x6c, 0xd8, 0x0e, 0x55, 0x7d, 0x92, 0xf8, 0x59,
+	0x1e, 0x63, 0xe2, 0x23, 0x8f, 0xa9, 0x22, 0x4c, 0x3a, 0xa4, 0x6a, 0x99, 0xba, 0x1a, 0x65, 0x73,
+	0x9c, 0xcb, 0x8f, 0x3e, 0x9a, 0xd3, 0xd7, 0x08, 0xe6, 0x63, 0x81, 0x7f, 0xe1, 0xe3, 0xfd, 0x4b,
+	0x1e, 0x81, 0xfb, 0x1a, 0x25, 0x0e, 0x0d, 0x6b, 0xa6, 0x73, 0x18, 0xf2, 0x78, 0xa0, 0x87, 0x84,
+	0x19, 0x43, 0xba, 0x14, 0x43, 0xba, 0xf2, 0x86, 0x47, 0x5b, 0x2c, 0x22, 0x41, 0x4e, 0x8c, 0xd7,
+	0x03, 0xfd, 0x78, 0xed, 0xb3, 0x2b, 0xa5, 0xb1, 0xab, 0x1c, 0xc3, 0x95, 0x5d, 0x42, 0x43, 0xf9,
+	0x66, 0xc7, 0x6a, 0x99, 0x9f, 0x9a, 0x1a, 0xe5, 0x9b, 0x50, 0x48, 0x58, 0x27, 0x72, 0xbc, 0xaa,
+	0xae, 0x34, 0x98, 0x77, 0x98, 0x9a, 0xf2, 0x1b, 0x04, 0xb3, 0xbb, 0x84, 0xb2, 0xc4, 0xbd, 0x65,
+	0xea, 0xff, 0x77, 0x99, 0xec, 0x03, 0x4f, 0xb5, 0x11, 0x7c, 0xfd, 0x45, 0xba, 0x57, 0x53, 0x48,
+	0xe9, 0x35, 0x45, 0x4c, 0x68, 0x64, 0xfa, 0x3a, 0x10, 0xcf, 0x60, 0x7c, 0xcf, 0x34, 0xa8, 0xfb,
+	0xf9, 0x89, 0x77, 0xf9, 0x01, 0x4c, 0xb4, 0x2d, 0x0b, 0xdf, 0x37, 0x60, 0xb8, 0x6a, 0x13, 0x8d,
+	0x12, 0x6e, 0x3b, 0x05, 0xa5, 0xa7, 0xa7, 0xfc, 0x0d, 0x01, 0xf6, 0x8a, 0xc6, 0x53, 0xe2, 0x74,
+	0x01, 0x79, 0x03, 0x86, 0x1a, 0x4c, 0x4f, 0x64, 0xb2, 0x18, 0xde, 0x84, 0x42, 0xff, 0x15, 0xf0,
+	0x0e, 0x2c, 0xa4, 0x56, 0xc0, 0x8e, 0x48, 0xd9, 0xf3, 0xc9, 0x25, 0xb0, 0xa3, 0xfc, 0x11, 0xc1,
+	0x74, 0xc8, 0x1d, 0xc1, 0xcc, 0x7d, 0x18, 0xf3, 0xab, 0x60, 0x1f, 0x7f, 0x62, 0x55, 0x37, 0xda,
+	0xae, 0x83, 0x5d, 0x5f, 0x9e, 0xc0, 0x6c, 0x12, 0x26, 0x69, 0x49, 0xea, 0xa1, 0x14, 0xbe, 0xec,
+	0xc4, 0xa2, 0xfd, 0x05, 0x82, 0xb9, 0x48, 0x05, 0xfa, 0xf9, 0xf6, 0xa0, 0x97, 0x93, 0xf5, 0x5d,
+	0x90, 0xe3, 0xf0, 0xf8, 0xe1, 0xc5, 0x8b, 0xdd, 0xae, 0xf4, 0x79, 0x7a, 0xca, 0x8f, 0xf8, 0x55,
+	0xc2, 0x0d, 0x6d, 0x9f, 0xb3, 0xdb, 0xe0, 0x62, 0xb5, 0x48, 0xdf, 0x05, 0xda, 0x4f, 0xf9, 0x6d,
+	0x11, 0x81, 0x20, 0x5c, 0xea, 0x83, 0xcc, 0x0b, 0xe7, 0xc6, 0xb7, 0x61, 0x2e, 0xca, 0x9a, 0x59,
+	0x23, 0x5d, 0xb8, 0x58, 0x84, 0x9c, 0x43, 0x35, 0x9b, 0x86, 0xee, 0x55, 0x60, 0x22, 0xce, 0xc6,
+	0x25, 0x18, 0xe4, 0x97, 0x38, 0xbf, 0x54, 0xf9, 0x47, 0xff, 0xfb, 0x1e, 0xe1, 0x48, 0x40, 0xeb,
+	0xe0, 0x08, 0x7d, 0x04, 0x47, 0x7d, 0x65, 0x52, 0xe5, 0xcf, 0x08, 0x96, 0xa2, 0x40, 0x9e, 0x1a,
+	0xb4, 0xde, 0x4b, 0x0e, 0xfa, 0x48, 0xb2, 0x42, 0xb9, 0x29, 0x93, 0x96, 0x9b, 0x7a, 0x89, 0xb6,
+	0x7f, 0x23, 0x58, 0x4e, 0x71, 0xa0, 0x7f, 0x4a, 0x17, 0x21, 0xd7, 0x20, 0xc7, 0x54, 0x0d, 0x15,
+	0xbc, 0xe0, 0x8a, 0x1e, 0x32, 0x09, 0x5e, 0x86, 0x51, 0xdb, 0xa8, 0xd5, 0xdb, 0x1a, 0x12, 0xd3,
+	0xc8, 0x31, 0x99, 0x50, 0x49, 0x75, 0x31, 0x66, 0xcf, 0x06, 0xfb, 0xda, 0xb3, 0x0f, 0x08, 0x66,
+	0x02, 0x2e, 0xf7, 0xff, 0x94, 0x93, 0x42, 0x4f, 0xb9, 0xd8, 0xd7, 0x9a, 0xf4, 0x89, 0x5e, 0x6b,
+	0xaf, 0xc3, 0x67, 0x30, 0xf4, 0x4a, 0xfb, 0x92, 0x77, 0xc1, 0x5f, 0x11, 0xe0, 0xa7, 0xee, 0x03,
+	0xa8, 0xa7, 0x2b, 0xbf, 0x6b, 0x64, 0x5f, 0x85, 0xf1, 0x13, 0xed, 0x4c, 0xad, 0xb8, 0x16, 0x83,
+	0x45, 0xd6, 0xe8, 0x89, 0x76, 0xc6, 0xde, 0x59, 0x6c, 0xa7, 0x63, 0x4a, 0xe6, 0x4c, 0xdc, 0x3b,
+	0xa5, 0x6f, 0x56, 0xdf, 0x23, 0x98, 0x0e, 0x79, 0xf3, 0xe5, 0x6f, 0x8e, 0x5e, 0x6b, 0xf0, 0x0a,
+	0x8c, 0x85, 0x52, 0x55, 0xbb, 0x10, 0x44, 0xe9, 0x85, 0xe0, 0x1a, 0x0c, 0xf1, 0x1e, 0x63, 0xbb,
+	0x36, 0xe3, 0xdd, 0xc7, 0x75, 0xbb, 0x59, 0x5d, 0x3f, 0x64, 0x23, 0x65, 0xa1, 0xa1, 0xfc, 0x67,
+	0x00, 0x86, 0x3d, 0xf3, 0x45, 0x98, 0x3c, 0x21, 0xf6, 0x0f, 0x1b, 0x44, 0xf5, 0x23, 0x1e, 0xb1,
+	0xe6, 0xc5, 0x38, 0x97, 0xef, 0x7b, 0x71, 0xef, 0xe5, 0xbd, 0x53, 0xad, 0xd1, 0x22, 0xa2, 0xc1,
+	0xc1, 0x8e, 0xc9, 0x13, 0x57, 0xe0, 0x0e, 0x93, 0x33, 0x6a, 0x6b, 0xaa, 0xae, 0x51, 0x8d, 0x39,
+	0x39, 0x5a, 0x1e, 0x61, 0x92, 0x07, 0x1a, 0xd5, 0x22, 0x59, 0x33, 0x13, 0x2d, 0xc0, 0x6f, 0x01,
+	0xe6, 0xc3, 0x3a, 0x31, 0xa9, 0x41, 0xcf, 0x39, 0x90, 0x41, 0x66, 0x65, 0x92, 0xa9, 0x89, 0x01,
+	0x06, 0x65, 0x07, 0x26, 0x58, 0xfd, 0x13, 0x68, 0xf9, 0x0d, 0x31, 0xaf, 0x65, 0xcf, 0x6b, 0xaf,
+	0x29, 0xbb, 0xee, 0xd7, 0x38, 0xe3, 0x6c, 0x4a, 0xfb, 0x1b, 0x3f, 0x82, 0x69, 0xc3, 0xa4, 0xa4,
+	0x66, 0x6b, 0x34, 0x68, 0x68, 0xb8, 0xab, 0x21, 0xdc, 0x9e, 0xe6, 0x1b, 0x5b, 0x84, 0x9c, 0xeb,
+	0xb7, 0xda, 0xb4, 0x5b, 0x26, 0xd1, 0xf3, 0x59, 0x76, 0x1d, 0x80, 0x2b, 0x3a, 0x60, 0x12, 0xe5,
+	0x01, 0x0c, 0xb2, 0x7d, 0xee, 0x68, 0x1d, 0xa0, 0xa4, 0xd6, 0x81, 0x14, 0x6c, 0x1d, 0x7c, 0x27,
+	0x93, 0x1d, 0x98, 0x94, 0xee, 0xfc, 0x73, 0x12, 0x72, 0x47, 0x22, 0x00, 0xf6, 0xad, 0x1a, 0x36,
+	0x61, 0xa4, 0xdd, 0x51, 0xc5, 0x72, 0xa4, 0xda, 0x09, 0x74, 0x2e, 0xe5, 0xf9, 0xd8, 0x31, 0x7e,
+	0x0c, 0x94, 0xe2, 0x8f, 0xff, 0xf1, 0xaf, 0x5f, 0x0d, 0x28, 0x4a, 0xa1, 0x74, 0xba, 0x51, 0x21,
+	0x54, 0xdb, 0x28, 0x35, 0xac, 0x9a, 0x53, 0x7a, 0xc9, 0x8f, 0xfa, 0xab, 0x12, 0x3f, 0x02, 0x9b,
+	0x68, 0x0d, 0xff, 0x1c, 0xc1, 0x64, 0xb4, 0x25, 0x89, 0x97, 0x7d, 0xdb, 0x09, 0x8d, 0x53, 0x59,
+	0x49, 0x53, 0x11, 0x28, 0xee, 0x30, 0x14, 0xb7, 0x94, 0xeb, 0xe9, 0x28, 0xbc, 0x2b, 0x57, 0x77,
+	0xf1, 0xfc, 0x1e, 0xc1, 0x54, 0x47, 0x73, 0x0b, 0x07, 0x56, 0x4b, 0xea, 0x78, 0xca, 0x2b, 0xa9,
+	0x3a, 0x02, 0xd2, 0x36, 0x83, 0x74, 0x1f, 0x6f, 0xa6, 0x42, 0x2a, 0xbd, 0xf4, 0x37, 0xf4, 0xd5,
+	0xa6, 0xe1, 0x99, 0x52, 0xf9, 0x43, 0xee, 0x0f, 0xfc, 0x46, 0x8f, 0xeb, 0xbf, 0xe1, 0x62, 0x0a,
+	0x88, 0x50, 0xa2, 0x92, 0x6f, 0xf4, 0xa0, 0x29, 0x40, 0x7f, 0x8d, 0x81, 0xde, 0xc0, 0xa5, 0x74,
+	0x1e, 0x7d, 0x9c, 0x15, 0x7e, 0xda, 0xf0, 0x7b, 0x9e, 0x27, 0x63, 0xfa, 0x5e, 0xf8, 0x7a, 0x68,
+	0xf9, 0xe4, 0xfe, 0x9d, 0x5c, 0xec, 0xae, 0x28, 0x60, 0x7e, 0x83, 0xc1, 0xbc, 0x87, 0xef, 0xa6,
+	0xc3, 0xe4, 0x29, 0x23, 0x4a, 0xea, 0xaf, 0x11, 0x4c, 0xc7, 0xb4, 0x93, 0xf0, 0xd5, 0xd0, 0xf2,
+	0x09, 0x6d, 0x32, 0x79, 0xb5, 0x8b, 0x96, 0x40, 0x78, 0x9b, 0x21, 0x5c, 0xc3, 0xc5, 0x78, 0x84,
+	0x9b, 0x55, 0x7f, 0xa2, 0x80, 0xf5, 0x56, 0x54, 0x1a, 0x9d, 0xbd, 0x9c, 0x08, 0x83, 0xc9, 0xfd,
+	0x27, 0xb9, 0xd8, 0x5d, 0x51, 0xe0, 0xbb, 0xc9, 0xf0, 0xad, 0xe2, 0x95, 0x04, 0x06, 0xdd, 0x24,
+	0xe5, 0x6c, 0x36, 0x98, 0x05, 0xfc, 0x3b, 0x04, 0x97, 0x63, 0x9b, 0x2e, 0xf8, 0x5a, 0x68, 0xc1,
+	0xc4, 0xee, 0x8f, 0x7c, 0xbd, 0xab, 0x9e, 0xc0, 0x75, 0x8f, 0xe1, 0x2a, 0xe1, 0xaf, 0xf6, 0x78,
+	0x90, 0x79, 0x9b, 0x87, 0xdd, 0x2d, 0xd1, 0xae, 0x49, 0xf0, 0x6e, 0x49, 0xe8, 0xf8, 0xc8, 0x4a,
+	0x9a, 0x4a, 0xf8, 0x6e, 0xc1, 0x6b, 0xbd, 0x1f, 0x64, 0x5c, 0x85, 0x61, 0xd1, 0xbf, 0xc0, 0x79,
+	0x7f, 0x89, 0x70, 0xb3, 0x44, 0x9e, 0x8b, 0x19, 0x11, 0x6b, 0xae, 0xb0, 0x35, 0x0b, 0xca, 0x7c,
+	0x42, 0xf8, 0x18, 0xa6, 0x41, 0xf1, 0x3e, 0xe4, 0x02, 0xed, 0x00, 0x7c, 0xa5, 0xf3, 0x9a, 0xf6,
+	0xab, 0x2f, 0xb9, 0x90, 0x30, 0x2a, 0x16, 0xfc, 0x0a, 0xd6, 0x00, 0x77, 0x3e, 0x8f, 0xf1, 0x4a,
+	0xe2, 0xe5, 0x1b, 0xb0, 0x7d, 0x35, 0x5d, 0xa9, 0xbd, 0xc4, 0x0f, 0xd8, 0x26, 0x85, 0x1e, 0xab,
+	0x91, 0x4d, 0x8a, 0x7b, 0x4b, 0xcb, 0x4a, 0x9a, 0x4a, 0x82, 0x71, 0xf6, 0x36, 0x49, 0x30, 0x1e,
+	0x7c, 0x9c, 0xca, 0x4a, 0x9a, 0x4a, 0xdb, 0xf8, 0x29, 0xcc, 0x45, 0x47, 0xdb, 0x0f, 0x1f, 0xbc,
+	0x96, 0x6c, 0x22, 0xfa, 0xbc, 0x93, 0x6f, 0xf6, 0xa4, 0xdb, 0x5e, 0xf7, 0x19, 0x4c, 0x44, 0x2a,
+	0x7a, 0xbc, 0x14, 0x6b, 0x21, 0x78, 0xdf, 0x2f, 0xa7, 0x68, 0xb4, 0x2d, 0x3f, 0x86, 0x5c, 0xa0,
+	0xaa, 0x0d, 0x06, 0x4f, 0x67, 0xe9, 0x2e, 0x17, 0x12, 0x46, 0x3d, 0x6b, 0xb7, 0xd1, 0xf6, 0x63,
+	0x98, 0xab, 0x5a, 0x27, 0x5e, 0xe5, 0x13, 0xfe, 0x93, 0x7a, 0x7b, 0x3a, 0x50, 0x77, 0x6c, 0x35,
+	0x8d, 0x03, 0x57, 0x78, 0x80, 0xbe, 0x2f, 0xd7, 0x0c, 0x5a, 0x6f, 0x55, 0xd6, 0xab, 0xd6, 0x49,
+	0x89, 0x4f, 0x2c, 0x79, 0x13, 0x2b, 0x43, 0x6c, 0xe6, 0xdd, 0xff, 0x0e, 0x00, 0x47, 0xbe, 0x8e,
+	0x4f, 0x6a, 0x1f, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1975,6 +2474,14 @@ type TrillianLogClient interface {
 	// If any of the leaves that match the given Merkle has have a leaf index that
 	// is beyond the requested tree size, the corresponding proof entry will be empty.
 	GetInclusionProofByHash(ctx context.Context, in *GetInclusionProofByHashRequest, opts ...grpc.CallOption) (*GetInclusionProofByHashResponse, error)
+	// GetBatchInclusionProof returns a single inclusion proof covering all the
+	// leaves with the given indices in a particular tree. Nodes shared between
+	// the individual proofs are only included once, and nodes that can be
+	// computed from the requested leaves are omitted.
+	//
+	// If the requested tree_size is larger than the server is aware of, the
+	// response will include the latest known log root and an empty proof.
+	GetBatchInclusionProof(ctx context.Context, in *GetBatchInclusionProofRequest, opts ...grpc.CallOption) (*GetBatchInclusionProofResponse, error)
 	// GetConsistencyProof returns a consistency proof between different sizes of
 	// a particular tree.
 	//
@@ -2018,9 +2525,25 @@ type TrillianLogClient interface {
 	// GetLeavesByRange returns a batch of leaves whose leaf indices are in a
 	// sequential range.
 	GetLeavesByRange(ctx context.Context, in *GetLeavesByRangeRequest, opts ...grpc.CallOption) (*GetLeavesByRangeResponse, error)
+	// GetLeavesByRangeWithProof returns a range of consecutive leaves, together
+	// with a single proof of their inclusion in a particular tree: the hashes
+	// of the compact ranges to the left and to the right of the leaves.
+	//
+	// If the requested tree_size is larger than the server is aware of, the
+	// response will include the latest known log root and no leaves.
+	GetLeavesByRangeWithProof(ctx context.Context, in *GetLeavesByRangeWithProofRequest, opts ...grpc.CallOption) (*GetLeavesByRangeWithProofResponse, error)
 	// GetLeavesByHash returns a batch of leaves which are identified by their
 	// Merkle leaf hash values.
 	GetLeavesByHash(ctx context.Context, in *GetLeavesByHashRequest, opts ...grpc.CallOption) (*GetLeavesByHashResponse, error)
+	// WatchLeaves streams the leaves of a log in order, starting from the leaf
+	// index provided in the request. Once all the currently integrated leaves
+	// have been sent, the stream waits for the log to grow and sends new leaves
+	// as they are integrated. Each response carries the signed log root that
+	// covers all of the leaves in it, and a consistency proof from the root
+	// sent before it. Each batch is charged to the read quota by its number of
+	// leaves. The server ends the stream after a while, and clients resume it
+	// with a new request from the next leaf.
+	WatchLeaves(ctx context.Context, in *WatchLeavesRequest, opts ...grpc.CallOption) (TrillianLog_WatchLeavesClient, error)
 }
 
 type trillianLogClient struct {
@@ -2067,6 +2590,15 @@ func (c *trillianLogClient) GetInclusionProofByHash(ctx context.Context, in *Get
 	return out, nil
 }
 
+func (c *trillianLogClient) GetBatchInclusionProof(ctx context.Context, in *GetBatchInclusionProofRequest, opts ...grpc.CallOption) (*GetBatchInclusionProofResponse, error) {
+	out := new(GetBatchInclusionProofResponse)
+	err := c.cc.Invoke(ctx, "/trillian.TrillianLog/GetBatchInclusionProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *trillianLogClient) GetConsistencyProof(ctx context.Context, in *GetConsistencyProofRequest, opts ...grpc.CallOption) (*GetConsistencyProofResponse, error) {
 	out := new(GetConsistencyProofResponse)
 	err := c.cc.Invoke(ctx, "/trillian.TrillianLog/GetConsistencyProof", in, out, opts...)
@@ -2148,6 +2680,15 @@ func (c *trillianLogClient) GetLeavesByRange(ctx context.Context, in *GetLeavesB
 	return out, nil
 }
 
+func (c *trillianLogClient) GetLeavesByRangeWithProof(ctx context.Context, in *GetLeavesByRangeWithProofRequest, opts ...grpc.CallOption) (*GetLeavesByRangeWithProofResponse, error) {
+	out := new(GetLeavesByRangeWithProofResponse)
+	err := c.cc.Invoke(ctx, "/trillian.TrillianLog/GetLeavesByRangeWithProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *trillianLogClient) GetLeavesByHash(ctx context.Context, in *GetLeavesByHashRequest, opts ...grpc.CallOption) (*GetLeavesByHashResponse, error) {
 	out := new(GetLeavesByHashResponse)
 	err := c.cc.Invoke(ctx, "/trillian.TrillianLog/GetLeavesByHash", in, out, opts...)
@@ -2157,6 +2698,38 @@ func (c *trillianLogClient) GetLeavesByHash(ctx context.Context, in *GetLeavesBy
 	return out, nil
 }
 
+func (c *trillianLogClient) WatchLeaves(ctx context.Context, in *WatchLeavesRequest, opts ...grpc.CallOption) (TrillianLog_WatchLeavesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TrillianLog_serviceDesc.Streams[0], "/trillian.TrillianLog/WatchLeaves", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &trillianLogWatchLeavesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TrillianLog_WatchLeavesClient interface {
+	Recv() (*WatchLeavesResponse, error)
+	grpc.ClientStream
+}
+
+type trillianLogWatchLeavesClient struct {
+	grpc.ClientStream
+}
+
+func (x *trillianLogWatchLeavesClient) Recv() (*WatchLeavesResponse, error) {
+	m := new(WatchLeavesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // TrillianLogServer is the server API for TrillianLog service.
 type TrillianLogServer interface {
 	// QueueLeaf adds a single leaf to the queue of pending leaves for a normal
@@ -2177,6 +2750,14 @@ type TrillianLogServer interface {
 	// If any of the leaves that match the given Merkle has have a leaf index that
 	// is beyond the requested tree size, the corresponding proof entry will be empty.
 	GetInclusionProofByHash(context.Context, *GetInclusionProofByHashRequest) (*GetInclusionProofByHashResponse, error)
+	// GetBatchInclusionProof returns a single inclusion proof covering all the
+	// leaves with the given indices in a particular tree. Nodes shared between
+	// the individual proofs are only included once, and nodes that can be
+	// computed from the requested leaves are omitted.
+	//
+	// If the requested tree_size is larger than the server is aware of, the
+	// response will include the latest known log root and an empty proof.
+	GetBatchInclusionProof(context.Context, *GetBatchInclusionProofRequest) (*GetBatchInclusionProofResponse, error)
 	// GetConsistencyProof returns a consistency proof between different sizes of
 	// a particular tree.
 	//
@@ -2220,9 +2801,25 @@ type TrillianLogServer interface {
 	// GetLeavesByRange returns a batch of leaves whose leaf indices are in a
 	// sequential range.
 	GetLeavesByRange(context.Context, *GetLeavesByRangeRequest) (*GetLeavesByRangeResponse, error)
+	// GetLeavesByRangeWithProof returns a range of consecutive leaves, together
+	// with a single proof of their inclusion in a particular tree: the hashes
+	// of the compact ranges to the left and to the right of the leaves.
+	//
+	// If the requested tree_size is larger than the server is aware of, the
+	// response will include the latest known log root and no leaves.
+	GetLeavesByRangeWithProof(context.Context, *GetLeavesByRangeWithProofRequest) (*GetLeavesByRangeWithProofResponse, error)
 	// GetLeavesByHash returns a batch of leaves which are identified by their
 	// Merkle leaf hash values.
 	GetLeavesByHash(context.Context, *GetLeavesByHashRequest) (*GetLeavesByHashResponse, error)
+	// WatchLeaves streams the leaves of a log in order, starting from the leaf
+	// index provided in the request. Once all the currently integrated leaves
+	// have been sent, the stream waits for the log to grow and sends new leaves
+	// as they are integrated. Each response carries the signed log root that
+	// covers all of the leaves in it, and a consistency proof from the root
+	// sent before it. Each batch is charged to the read quota by its number of
+	// leaves. The server ends the stream after a while, and clients resume it
+	// with a new request from the next leaf.
+	WatchLeaves(*WatchLeavesRequest, TrillianLog_WatchLeavesServer) error
 }
 
 // UnimplementedTrillianLogServer can be embedded to have forward compatible implementations.
@@ -2241,6 +2838,9 @@ func (*UnimplementedTrillianLogServer) GetInclusionProof(ctx context.Context, re
 func (*UnimplementedTrillianLogServer) GetInclusionProofByHash(ctx context.Context, req *GetInclusionProofByHashRequest) (*GetInclusionProofByHashResponse, error) {
 	return nil, status1.Errorf(codes.Unimplemented, "method GetInclusionProofByHash not implemented")
 }
+func (*UnimplementedTrillianLogServer) GetBatchInclusionProof(ctx context.Context, req *GetBatchInclusionProofRequest) (*GetBatchInclusionProofResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method GetBatchInclusionProof not implemented")
+}
 func (*UnimplementedTrillianLogServer) GetConsistencyProof(ctx context.Context, req *GetConsistencyProofRequest) (*GetConsistencyProofResponse, error) {
 	return nil, status1.Errorf(codes.Unimplemented, "method GetConsistencyProof not implemented")
 }
@@ -2268,9 +2868,15 @@ func (*UnimplementedTrillianLogServer) GetLeavesByIndex(ctx context.Context, req
 func (*UnimplementedTrillianLogServer) GetLeavesByRange(ctx context.Context, req *GetLeavesByRangeRequest) (*GetLeavesByRangeResponse, error) {
 	return nil, status1.Errorf(codes.Unimplemented, "method GetLeavesByRange not implemented")
 }
+func (*UnimplementedTrillianLogServer) GetLeavesByRangeWithProof(ctx context.Context, req *GetLeavesByRangeWithProofRequest) (*GetLeavesByRangeWithProofResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method GetLeavesByRangeWithProof not implemented")
+}
 func (*UnimplementedTrillianLogServer) GetLeavesByHash(ctx context.Context, req *GetLeavesByHashRequest) (*GetLeavesByHashResponse, error) {
 	return nil, status1.Errorf(codes.Unimplemented, "method GetLeavesByHash not implemented")
 }
+func (*UnimplementedTrillianLogServer) WatchLeaves(req *WatchLeavesRequest, srv TrillianLog_WatchLeavesServer) error {
+	return status1.Errorf(codes.Unimplemented, "method WatchLeaves not implemented")
+}
 
 func RegisterTrillianLogServer(s *grpc.Server, srv TrillianLogServer) {
 	s.RegisterService(&_TrillianLog_serviceDesc, srv)
@@ -2348,6 +2954,24 @@ func _TrillianLog_GetInclusionProofByHash_Handler(srv interface{}, ctx context.C
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TrillianLog_GetBatchInclusionProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBatchInclusionProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrillianLogServer).GetBatchInclusionProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trillian.TrillianLog/GetBatchInclusionProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrillianLogServer).GetBatchInclusionProof(ctx, req.(*GetBatchInclusionProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TrillianLog_GetConsistencyProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetConsistencyProofRequest)
 	if err := dec(in); err != nil {
@@ -2510,6 +3134,24 @@ func _TrillianLog_GetLeavesByRange_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TrillianLog_GetLeavesByRangeWithProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLeavesByRangeWithProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrillianLogServer).GetLeavesByRangeWithProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trillian.TrillianLog/GetLeavesByRangeWithProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrillianLogServer).GetLeavesByRangeWithProof(ctx, req.(*GetLeavesByRangeWithProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TrillianLog_GetLeavesByHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetLeavesByHashRequest)
 	if err := dec(in); err != nil {
@@ -2528,6 +3170,27 @@ func _TrillianLog_GetLeavesByHash_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TrillianLog_WatchLeaves_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchLeavesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TrillianLogServer).WatchLeaves(m, &trillianLogWatchLeavesServer{stream})
+}
+
+type TrillianLog_WatchLeavesServer interface {
+	Send(*WatchLeavesResponse) error
+	grpc.ServerStream
+}
+
+type trillianLogWatchLeavesServer struct {
+	grpc.ServerStream
+}
+
+func (x *trillianLogWatchLeavesServer) Send(m *WatchLeavesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _TrillianLog_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "trillian.TrillianLog",
 	HandlerType: (*TrillianLogServer)(nil),
@@ -2548,6 +3211,10 @@ var _TrillianLog_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetInclusionProofByHash",
 			Handler:    _TrillianLog_GetInclusionProofByHash_Handler,
 		},
+		{
+			MethodName: "GetBatchInclusionProof",
+			Handler:    _TrillianLog_GetBatchInclusionProof_Handler,
+		},
 		{
 			MethodName: "GetConsistencyProof",
 			Handler:    _TrillianLog_GetConsistencyProof_Handler,
@@ -2584,11 +3251,21 @@ var _TrillianLog_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetLeavesByRange",
 			Handler:    _TrillianLog_GetLeavesByRange_Handler,
 		},
+		{
+			MethodName: "GetLeavesByRangeWithProof",
+			Handler:    _TrillianLog_GetLeavesByRangeWithProof_Handler,
+		},
 		{
 			MethodName: "GetLeavesByHash",
 			Handler:    _TrillianLog_GetLeavesByHash_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchLeaves",
+			Handler:       _TrillianLog_WatchLeaves_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "trillian_log_api.proto",
 }
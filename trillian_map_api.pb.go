@@ -25,6 +25,42 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// MapProofFormat specifies the encoding of the inclusion proofs of map leaves.
+type MapProofFormat int32
+
+const (
+	// The proofs are in MapLeafInclusion.inclusion, with one entry for each
+	// level of the tree.
+	MapProofFormat_MAP_PROOF_FORMAT_FULL MapProofFormat = 0
+	// The proofs are in MapLeafInclusion.compressed_inclusion, which only holds
+	// the non-empty entries.
+	MapProofFormat_MAP_PROOF_FORMAT_COMPRESSED MapProofFormat = 1
+	// Like MAP_PROOF_FORMAT_COMPRESSED, but the non-empty entries of all the
+	// proofs of a response are deduplicated in its proof_hashes, and referred to
+	// by CompressedMapInclusionProof.hash_refs.
+	MapProofFormat_MAP_PROOF_FORMAT_COMPRESSED_BATCH MapProofFormat = 2
+)
+
+var MapProofFormat_name = map[int32]string{
+	0: "MAP_PROOF_FORMAT_FULL",
+	1: "MAP_PROOF_FORMAT_COMPRESSED",
+	2: "MAP_PROOF_FORMAT_COMPRESSED_BATCH",
+}
+
+var MapProofFormat_value = map[string]int32{
+	"MAP_PROOF_FORMAT_FULL":             0,
+	"MAP_PROOF_FORMAT_COMPRESSED":       1,
+	"MAP_PROOF_FORMAT_COMPRESSED_BATCH": 2,
+}
+
+func (x MapProofFormat) String() string {
+	return proto.EnumName(MapProofFormat_name, int32(x))
+}
+
+func (MapProofFormat) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_28d34dfba22a7ce2, []int{0}
+}
+
 // MapLeaf represents the data behind Map leaves.
 type MapLeaf struct {
 	// index is the location of this leaf.
@@ -144,6 +180,71 @@ func (m *MapLeaves) GetLeaves() []*MapLeaf {
 	return nil
 }
 
+// CompressedMapInclusionProof is an inclusion proof of a map leaf which omits
+// the entries for empty subtrees.
+type CompressedMapInclusionProof struct {
+	// bitmap has one bit for each level of the tree, which is set if the proof
+	// entry at that height is non-empty. The bit for height h is the bit
+	// 0x80 >> (h % 8) of byte h / 8.
+	Bitmap []byte `protobuf:"bytes,1,opt,name=bitmap,proto3" json:"bitmap,omitempty"`
+	// hashes holds the non-empty entries of the proof, in ascending order of
+	// height.
+	Hashes [][]byte `protobuf:"bytes,2,rep,name=hashes,proto3" json:"hashes,omitempty"`
+	// hash_refs replaces hashes in MAP_PROOF_FORMAT_COMPRESSED_BATCH proofs: it
+	// holds the positions in the proof_hashes of the response of the non-empty
+	// entries of the proof, in ascending order of height.
+	HashRefs             []uint32 `protobuf:"varint,3,rep,packed,name=hash_refs,json=hashRefs,proto3" json:"hash_refs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CompressedMapInclusionProof) Reset()         { *m = CompressedMapInclusionProof{} }
+func (m *CompressedMapInclusionProof) String() string { return proto.CompactTextString(m) }
+func (*CompressedMapInclusionProof) ProtoMessage()    {}
+func (*CompressedMapInclusionProof) Descriptor() ([]byte, []int) {
+	return fileDescriptor_28d34dfba22a7ce2, []int{2}
+}
+
+func (m *CompressedMapInclusionProof) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompressedMapInclusionProof.Unmarshal(m, b)
+}
+func (m *CompressedMapInclusionProof) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompressedMapInclusionProof.Marshal(b, m, deterministic)
+}
+func (m *CompressedMapInclusionProof) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompressedMapInclusionProof.Merge(m, src)
+}
+func (m *CompressedMapInclusionProof) XXX_Size() int {
+	return xxx_messageInfo_CompressedMapInclusionProof.Size(m)
+}
+func (m *CompressedMapInclusionProof) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompressedMapInclusionProof.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompressedMapInclusionProof proto.InternalMessageInfo
+
+func (m *CompressedMapInclusionProof) GetBitmap() []byte {
+	if m != nil {
+		return m.Bitmap
+	}
+	return nil
+}
+
+func (m *CompressedMapInclusionProof) GetHashes() [][]byte {
+	if m != nil {
+		return m.Hashes
+	}
+	return nil
+}
+
+func (m *CompressedMapInclusionProof) GetHashRefs() []uint32 {
+	if m != nil {
+		return m.HashRefs
+	}
+	return nil
+}
+
 type MapLeafInclusion struct {
 	Leaf *MapLeaf `protobuf:"bytes,1,opt,name=leaf,proto3" json:"leaf,omitempty"`
 	// inclusion holds the inclusion proof for this leaf in the map root. It
@@ -153,7 +254,15 @@ type MapLeafInclusion struct {
 	// that the node in question has an empty subtree beneath it (and so its
 	// associated hash value is hasher.HashEmpty(index, height) rather than
 	// hasher.HashChildren(l_hash, r_hash)).
-	Inclusion            [][]byte `protobuf:"bytes,2,rep,name=inclusion,proto3" json:"inclusion,omitempty"`
+	Inclusion [][]byte `protobuf:"bytes,2,rep,name=inclusion,proto3" json:"inclusion,omitempty"`
+	// compressed_inclusion holds the inclusion proof instead of inclusion if it
+	// is in a compressed MapProofFormat.
+	CompressedInclusion *CompressedMapInclusionProof `protobuf:"bytes,3,opt,name=compressed_inclusion,json=compressedInclusion,proto3" json:"compressed_inclusion,omitempty"`
+	// vrf_proof is the VRF proof of the raw key the leaf was requested by, if
+	// any. The index of the leaf is the leading bytes of the VRF output of the
+	// key under the map's key pair (see crypto/vrf). The map's signing key
+	// doubles as its VRF key, so rotating it changes the index of every key.
+	VrfProof             []byte   `protobuf:"bytes,4,opt,name=vrf_proof,json=vrfProof,proto3" json:"vrf_proof,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -163,7 +272,7 @@ func (m *MapLeafInclusion) Reset()         { *m = MapLeafInclusion{} }
 func (m *MapLeafInclusion) String() string { return proto.CompactTextString(m) }
 func (*MapLeafInclusion) ProtoMessage()    {}
 func (*MapLeafInclusion) Descriptor() ([]byte, []int) {
-	return fileDescriptor_28d34dfba22a7ce2, []int{2}
+	return fileDescriptor_28d34dfba22a7ce2, []int{3}
 }
 
 func (m *MapLeafInclusion) XXX_Unmarshal(b []byte) error {
@@ -198,9 +307,30 @@ func (m *MapLeafInclusion) GetInclusion() [][]byte {
 	return nil
 }
 
+func (m *MapLeafInclusion) GetCompressedInclusion() *CompressedMapInclusionProof {
+	if m != nil {
+		return m.CompressedInclusion
+	}
+	return nil
+}
+
+func (m *MapLeafInclusion) GetVrfProof() []byte {
+	if m != nil {
+		return m.VrfProof
+	}
+	return nil
+}
+
 type GetMapLeavesRequest struct {
-	MapId                int64    `protobuf:"varint,1,opt,name=map_id,json=mapId,proto3" json:"map_id,omitempty"`
-	Index                [][]byte `protobuf:"bytes,2,rep,name=index,proto3" json:"index,omitempty"`
+	MapId int64    `protobuf:"varint,1,opt,name=map_id,json=mapId,proto3" json:"map_id,omitempty"`
+	Index [][]byte `protobuf:"bytes,2,rep,name=index,proto3" json:"index,omitempty"`
+	// The format in which the inclusion proofs should be returned. Servers
+	// which don't support it return them in MAP_PROOF_FORMAT_FULL.
+	ProofFormat MapProofFormat `protobuf:"varint,4,opt,name=proof_format,json=proofFormat,proto3,enum=trillian.MapProofFormat" json:"proof_format,omitempty"`
+	// Raw key(s) to query instead of index. The server derives the index of
+	// each key with the map's VRF, and returns the VRF proofs in the
+	// inclusions. It is an error to set both index and key.
+	Key                  [][]byte `protobuf:"bytes,5,rep,name=key,proto3" json:"key,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -210,7 +340,7 @@ func (m *GetMapLeavesRequest) Reset()         { *m = GetMapLeavesRequest{} }
 func (m *GetMapLeavesRequest) String() string { return proto.CompactTextString(m) }
 func (*GetMapLeavesRequest) ProtoMessage()    {}
 func (*GetMapLeavesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_28d34dfba22a7ce2, []int{3}
+	return fileDescriptor_28d34dfba22a7ce2, []int{4}
 }
 
 func (m *GetMapLeavesRequest) XXX_Unmarshal(b []byte) error {
@@ -245,6 +375,20 @@ func (m *GetMapLeavesRequest) GetIndex() [][]byte {
 	return nil
 }
 
+func (m *GetMapLeavesRequest) GetProofFormat() MapProofFormat {
+	if m != nil {
+		return m.ProofFormat
+	}
+	return MapProofFormat_MAP_PROOF_FORMAT_FULL
+}
+
+func (m *GetMapLeavesRequest) GetKey() [][]byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
 type GetMapLeafRequest struct {
 	MapId                int64    `protobuf:"varint,1,opt,name=map_id,json=mapId,proto3" json:"map_id,omitempty"`
 	Index                []byte   `protobuf:"bytes,2,opt,name=index,proto3" json:"index,omitempty"`
@@ -257,7 +401,7 @@ func (m *GetMapLeafRequest) Reset()         { *m = GetMapLeafRequest{} }
 func (m *GetMapLeafRequest) String() string { return proto.CompactTextString(m) }
 func (*GetMapLeafRequest) ProtoMessage()    {}
 func (*GetMapLeafRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_28d34dfba22a7ce2, []int{4}
+	return fileDescriptor_28d34dfba22a7ce2, []int{5}
 }
 
 func (m *GetMapLeafRequest) XXX_Unmarshal(b []byte) error {
@@ -305,7 +449,7 @@ func (m *GetMapLeafByRevisionRequest) Reset()         { *m = GetMapLeafByRevisio
 func (m *GetMapLeafByRevisionRequest) String() string { return proto.CompactTextString(m) }
 func (*GetMapLeafByRevisionRequest) ProtoMessage()    {}
 func (*GetMapLeafByRevisionRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_28d34dfba22a7ce2, []int{5}
+	return fileDescriptor_28d34dfba22a7ce2, []int{6}
 }
 
 func (m *GetMapLeafByRevisionRequest) XXX_Unmarshal(b []byte) error {
@@ -354,7 +498,12 @@ type GetMapLeavesByRevisionRequest struct {
 	// index(es) to query.  It is an error to request the same index more than once.
 	Index [][]byte `protobuf:"bytes,2,rep,name=index,proto3" json:"index,omitempty"`
 	// revision >= 0.
-	Revision             int64    `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"`
+	Revision int64 `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"`
+	// The format in which the inclusion proofs should be returned. Servers
+	// which don't support it return them in MAP_PROOF_FORMAT_FULL.
+	ProofFormat MapProofFormat `protobuf:"varint,4,opt,name=proof_format,json=proofFormat,proto3,enum=trillian.MapProofFormat" json:"proof_format,omitempty"`
+	// Raw key(s) to query instead of index, as in GetMapLeavesRequest.
+	Key                  [][]byte `protobuf:"bytes,5,rep,name=key,proto3" json:"key,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -364,7 +513,7 @@ func (m *GetMapLeavesByRevisionRequest) Reset()         { *m = GetMapLeavesByRev
 func (m *GetMapLeavesByRevisionRequest) String() string { return proto.CompactTextString(m) }
 func (*GetMapLeavesByRevisionRequest) ProtoMessage()    {}
 func (*GetMapLeavesByRevisionRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_28d34dfba22a7ce2, []int{6}
+	return fileDescriptor_28d34dfba22a7ce2, []int{7}
 }
 
 func (m *GetMapLeavesByRevisionRequest) XXX_Unmarshal(b []byte) error {
@@ -406,6 +555,20 @@ func (m *GetMapLeavesByRevisionRequest) GetRevision() int64 {
 	return 0
 }
 
+func (m *GetMapLeavesByRevisionRequest) GetProofFormat() MapProofFormat {
+	if m != nil {
+		return m.ProofFormat
+	}
+	return MapProofFormat_MAP_PROOF_FORMAT_FULL
+}
+
+func (m *GetMapLeavesByRevisionRequest) GetKey() [][]byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
 type GetMapLeafResponse struct {
 	MapLeafInclusion     *MapLeafInclusion `protobuf:"bytes,1,opt,name=map_leaf_inclusion,json=mapLeafInclusion,proto3" json:"map_leaf_inclusion,omitempty"`
 	MapRoot              *SignedMapRoot    `protobuf:"bytes,2,opt,name=map_root,json=mapRoot,proto3" json:"map_root,omitempty"`
@@ -418,7 +581,7 @@ func (m *GetMapLeafResponse) Reset()         { *m = GetMapLeafResponse{} }
 func (m *GetMapLeafResponse) String() string { return proto.CompactTextString(m) }
 func (*GetMapLeafResponse) ProtoMessage()    {}
 func (*GetMapLeafResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_28d34dfba22a7ce2, []int{7}
+	return fileDescriptor_28d34dfba22a7ce2, []int{8}
 }
 
 func (m *GetMapLeafResponse) XXX_Unmarshal(b []byte) error {
@@ -454,18 +617,23 @@ func (m *GetMapLeafResponse) GetMapRoot() *SignedMapRoot {
 }
 
 type GetMapLeavesResponse struct {
-	MapLeafInclusion     []*MapLeafInclusion `protobuf:"bytes,2,rep,name=map_leaf_inclusion,json=mapLeafInclusion,proto3" json:"map_leaf_inclusion,omitempty"`
-	MapRoot              *SignedMapRoot      `protobuf:"bytes,3,opt,name=map_root,json=mapRoot,proto3" json:"map_root,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
-	XXX_unrecognized     []byte              `json:"-"`
-	XXX_sizecache        int32               `json:"-"`
+	MapLeafInclusion []*MapLeafInclusion `protobuf:"bytes,2,rep,name=map_leaf_inclusion,json=mapLeafInclusion,proto3" json:"map_leaf_inclusion,omitempty"`
+	MapRoot          *SignedMapRoot      `protobuf:"bytes,3,opt,name=map_root,json=mapRoot,proto3" json:"map_root,omitempty"`
+	// The format of the inclusion proofs in map_leaf_inclusion.
+	ProofFormat MapProofFormat `protobuf:"varint,4,opt,name=proof_format,json=proofFormat,proto3,enum=trillian.MapProofFormat" json:"proof_format,omitempty"`
+	// The deduplicated non-empty entries of the inclusion proofs, if they are
+	// in MAP_PROOF_FORMAT_COMPRESSED_BATCH.
+	ProofHashes          [][]byte `protobuf:"bytes,5,rep,name=proof_hashes,json=proofHashes,proto3" json:"proof_hashes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *GetMapLeavesResponse) Reset()         { *m = GetMapLeavesResponse{} }
 func (m *GetMapLeavesResponse) String() string { return proto.CompactTextString(m) }
 func (*GetMapLeavesResponse) ProtoMessage()    {}
 func (*GetMapLeavesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_28d34dfba22a7ce2, []int{8}
+	return fileDescriptor_28d34dfba22a7ce2, []int{9}
 }
 
 func (m *GetMapLeavesResponse) XXX_Unmarshal(b []byte) error {
@@ -500,6 +668,20 @@ func (m *GetMapLeavesResponse) GetMapRoot() *SignedMapRoot {
 	return nil
 }
 
+func (m *GetMapLeavesResponse) GetProofFormat() MapProofFormat {
+	if m != nil {
+		return m.ProofFormat
+	}
+	return MapProofFormat_MAP_PROOF_FORMAT_FULL
+}
+
+func (m *GetMapLeavesResponse) GetProofHashes() [][]byte {
+	if m != nil {
+		return m.ProofHashes
+	}
+	return nil
+}
+
 // GetLastInRangeByRevisionRequest specifies a range in the map at a revision.
 // The range is defined as the entire subtree below a particular point in the
 // Merkle tree. Another way of saying this is that the range matches all leaves
@@ -520,7 +702,7 @@ func (m *GetLastInRangeByRevisionRequest) Reset()         { *m = GetLastInRangeB
 func (m *GetLastInRangeByRevisionRequest) String() string { return proto.CompactTextString(m) }
 func (*GetLastInRangeByRevisionRequest) ProtoMessage()    {}
 func (*GetLastInRangeByRevisionRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_28d34dfba22a7ce2, []int{9}
+	return fileDescriptor_28d34dfba22a7ce2, []int{10}
 }
 
 func (m *GetLastInRangeByRevisionRequest) XXX_Unmarshal(b []byte) error {
@@ -569,6 +751,570 @@ func (m *GetLastInRangeByRevisionRequest) GetPrefixBits() int32 {
 	return 0
 }
 
+// ListMapLeavesByRevisionRequest requests a page of the leaves in a map at a
+// revision, in ascending order of index.
+type ListMapLeavesByRevisionRequest struct {
+	MapId int64 `protobuf:"varint,1,opt,name=map_id,json=mapId,proto3" json:"map_id,omitempty"`
+	// revision >= 0.
+	Revision int64 `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	// If prefix_bits > 0, only the leaves whose index shares a common prefix of
+	// `prefix_bits` with `prefix` are listed. It can't be combined with
+	// start_index and end_index.
+	Prefix []byte `protobuf:"bytes,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// prefix_bits is the number of bits to include, starting from the left, or
+	// most significant bit (MSB).
+	PrefixBits int32 `protobuf:"varint,4,opt,name=prefix_bits,json=prefixBits,proto3" json:"prefix_bits,omitempty"`
+	// If set, only the leaves whose index is >= start_index are listed.
+	StartIndex []byte `protobuf:"bytes,5,opt,name=start_index,json=startIndex,proto3" json:"start_index,omitempty"`
+	// If set, only the leaves whose index is < end_index are listed.
+	EndIndex []byte `protobuf:"bytes,6,opt,name=end_index,json=endIndex,proto3" json:"end_index,omitempty"`
+	// The maximum number of leaves to return. The server may return fewer, and
+	// picks a default if page_size is 0.
+	PageSize int32 `protobuf:"varint,7,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// The next_page_token of the previous response, if any. All other fields
+	// of the request must be the same as in the request for that response.
+	PageToken            string   `protobuf:"bytes,8,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListMapLeavesByRevisionRequest) Reset()         { *m = ListMapLeavesByRevisionRequest{} }
+func (m *ListMapLeavesByRevisionRequest) String() string { return proto.CompactTextString(m) }
+func (*ListMapLeavesByRevisionRequest) ProtoMessage()    {}
+func (*ListMapLeavesByRevisionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_28d34dfba22a7ce2, []int{11}
+}
+
+func (m *ListMapLeavesByRevisionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListMapLeavesByRevisionRequest.Unmarshal(m, b)
+}
+func (m *ListMapLeavesByRevisionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListMapLeavesByRevisionRequest.Marshal(b, m, deterministic)
+}
+func (m *ListMapLeavesByRevisionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListMapLeavesByRevisionRequest.Merge(m, src)
+}
+func (m *ListMapLeavesByRevisionRequest) XXX_Size() int {
+	return xxx_messageInfo_ListMapLeavesByRevisionRequest.Size(m)
+}
+func (m *ListMapLeavesByRevisionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListMapLeavesByRevisionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListMapLeavesByRevisionRequest proto.InternalMessageInfo
+
+func (m *ListMapLeavesByRevisionRequest) GetMapId() int64 {
+	if m != nil {
+		return m.MapId
+	}
+	return 0
+}
+
+func (m *ListMapLeavesByRevisionRequest) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *ListMapLeavesByRevisionRequest) GetPrefix() []byte {
+	if m != nil {
+		return m.Prefix
+	}
+	return nil
+}
+
+func (m *ListMapLeavesByRevisionRequest) GetPrefixBits() int32 {
+	if m != nil {
+		return m.PrefixBits
+	}
+	return 0
+}
+
+func (m *ListMapLeavesByRevisionRequest) GetStartIndex() []byte {
+	if m != nil {
+		return m.StartIndex
+	}
+	return nil
+}
+
+func (m *ListMapLeavesByRevisionRequest) GetEndIndex() []byte {
+	if m != nil {
+		return m.EndIndex
+	}
+	return nil
+}
+
+func (m *ListMapLeavesByRevisionRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListMapLeavesByRevisionRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+type ListMapLeavesByRevisionResponse struct {
+	// The leaves of the requested page, in ascending order of index. Leaves
+	// which have been set to an empty value are included.
+	Leaves []*MapLeaf `protobuf:"bytes,1,rep,name=leaves,proto3" json:"leaves,omitempty"`
+	// A token to request the next page with, or empty if there are no more
+	// leaves in the requested range.
+	NextPageToken        string   `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListMapLeavesByRevisionResponse) Reset()         { *m = ListMapLeavesByRevisionResponse{} }
+func (m *ListMapLeavesByRevisionResponse) String() string { return proto.CompactTextString(m) }
+func (*ListMapLeavesByRevisionResponse) ProtoMessage()    {}
+func (*ListMapLeavesByRevisionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_28d34dfba22a7ce2, []int{12}
+}
+
+func (m *ListMapLeavesByRevisionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListMapLeavesByRevisionResponse.Unmarshal(m, b)
+}
+func (m *ListMapLeavesByRevisionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListMapLeavesByRevisionResponse.Marshal(b, m, deterministic)
+}
+func (m *ListMapLeavesByRevisionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListMapLeavesByRevisionResponse.Merge(m, src)
+}
+func (m *ListMapLeavesByRevisionResponse) XXX_Size() int {
+	return xxx_messageInfo_ListMapLeavesByRevisionResponse.Size(m)
+}
+func (m *ListMapLeavesByRevisionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListMapLeavesByRevisionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListMapLeavesByRevisionResponse proto.InternalMessageInfo
+
+func (m *ListMapLeavesByRevisionResponse) GetLeaves() []*MapLeaf {
+	if m != nil {
+		return m.Leaves
+	}
+	return nil
+}
+
+func (m *ListMapLeavesByRevisionResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+// GetRevisionDiffRequest requests a page of the indexes of the leaves which
+// were set after from_revision, up to and including to_revision.
+type GetRevisionDiffRequest struct {
+	MapId int64 `protobuf:"varint,1,opt,name=map_id,json=mapId,proto3" json:"map_id,omitempty"`
+	// from_revision >= 0.
+	FromRevision int64 `protobuf:"varint,2,opt,name=from_revision,json=fromRevision,proto3" json:"from_revision,omitempty"`
+	// to_revision > from_revision.
+	ToRevision int64 `protobuf:"varint,3,opt,name=to_revision,json=toRevision,proto3" json:"to_revision,omitempty"`
+	// If set, the leaves at both revisions are returned, with their inclusion
+	// proofs.
+	IncludeValues bool `protobuf:"varint,4,opt,name=include_values,json=includeValues,proto3" json:"include_values,omitempty"`
+	// The maximum number of indexes to return. The server may return fewer, and
+	// picks a default if page_size is 0.
+	PageSize int32 `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// The next_page_token of the previous response, if any. All other fields
+	// of the request must be the same as in the request for that response.
+	PageToken            string   `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetRevisionDiffRequest) Reset()         { *m = GetRevisionDiffRequest{} }
+func (m *GetRevisionDiffRequest) String() string { return proto.CompactTextString(m) }
+func (*GetRevisionDiffRequest) ProtoMessage()    {}
+func (*GetRevisionDiffRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_28d34dfba22a7ce2, []int{13}
+}
+
+func (m *GetRevisionDiffRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetRevisionDiffRequest.Unmarshal(m, b)
+}
+func (m *GetRevisionDiffRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetRevisionDiffRequest.Marshal(b, m, deterministic)
+}
+func (m *GetRevisionDiffRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetRevisionDiffRequest.Merge(m, src)
+}
+func (m *GetRevisionDiffRequest) XXX_Size() int {
+	return xxx_messageInfo_GetRevisionDiffRequest.Size(m)
+}
+func (m *GetRevisionDiffRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetRevisionDiffRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetRevisionDiffRequest proto.InternalMessageInfo
+
+func (m *GetRevisionDiffRequest) GetMapId() int64 {
+	if m != nil {
+		return m.MapId
+	}
+	return 0
+}
+
+func (m *GetRevisionDiffRequest) GetFromRevision() int64 {
+	if m != nil {
+		return m.FromRevision
+	}
+	return 0
+}
+
+func (m *GetRevisionDiffRequest) GetToRevision() int64 {
+	if m != nil {
+		return m.ToRevision
+	}
+	return 0
+}
+
+func (m *GetRevisionDiffRequest) GetIncludeValues() bool {
+	if m != nil {
+		return m.IncludeValues
+	}
+	return false
+}
+
+func (m *GetRevisionDiffRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *GetRevisionDiffRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+// MapLeafDiff describes a map leaf which was set between two revisions.
+type MapLeafDiff struct {
+	Index []byte `protobuf:"bytes,1,opt,name=index,proto3" json:"index,omitempty"`
+	// The leaf at from_revision, and its inclusion proof in from_map_root.
+	// Only set if include_values was requested.
+	FromLeaf *MapLeafInclusion `protobuf:"bytes,2,opt,name=from_leaf,json=fromLeaf,proto3" json:"from_leaf,omitempty"`
+	// The leaf at to_revision, and its inclusion proof in to_map_root.
+	// Only set if include_values was requested.
+	ToLeaf               *MapLeafInclusion `protobuf:"bytes,3,opt,name=to_leaf,json=toLeaf,proto3" json:"to_leaf,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *MapLeafDiff) Reset()         { *m = MapLeafDiff{} }
+func (m *MapLeafDiff) String() string { return proto.CompactTextString(m) }
+func (*MapLeafDiff) ProtoMessage()    {}
+func (*MapLeafDiff) Descriptor() ([]byte, []int) {
+	return fileDescriptor_28d34dfba22a7ce2, []int{14}
+}
+
+func (m *MapLeafDiff) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MapLeafDiff.Unmarshal(m, b)
+}
+func (m *MapLeafDiff) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MapLeafDiff.Marshal(b, m, deterministic)
+}
+func (m *MapLeafDiff) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MapLeafDiff.Merge(m, src)
+}
+func (m *MapLeafDiff) XXX_Size() int {
+	return xxx_messageInfo_MapLeafDiff.Size(m)
+}
+func (m *MapLeafDiff) XXX_DiscardUnknown() {
+	xxx_messageInfo_MapLeafDiff.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MapLeafDiff proto.InternalMessageInfo
+
+func (m *MapLeafDiff) GetIndex() []byte {
+	if m != nil {
+		return m.Index
+	}
+	return nil
+}
+
+func (m *MapLeafDiff) GetFromLeaf() *MapLeafInclusion {
+	if m != nil {
+		return m.FromLeaf
+	}
+	return nil
+}
+
+func (m *MapLeafDiff) GetToLeaf() *MapLeafInclusion {
+	if m != nil {
+		return m.ToLeaf
+	}
+	return nil
+}
+
+type GetRevisionDiffResponse struct {
+	FromMapRoot *SignedMapRoot `protobuf:"bytes,1,opt,name=from_map_root,json=fromMapRoot,proto3" json:"from_map_root,omitempty"`
+	ToMapRoot   *SignedMapRoot `protobuf:"bytes,2,opt,name=to_map_root,json=toMapRoot,proto3" json:"to_map_root,omitempty"`
+	// The leaves set between the two revisions, in ascending order of index.
+	// A leaf which was set to the value it already had is included.
+	Diffs []*MapLeafDiff `protobuf:"bytes,3,rep,name=diffs,proto3" json:"diffs,omitempty"`
+	// A token to request the next page with, or empty if there are no more
+	// leaves which were set between the two revisions.
+	NextPageToken        string   `protobuf:"bytes,4,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetRevisionDiffResponse) Reset()         { *m = GetRevisionDiffResponse{} }
+func (m *GetRevisionDiffResponse) String() string { return proto.CompactTextString(m) }
+func (*GetRevisionDiffResponse) ProtoMessage()    {}
+func (*GetRevisionDiffResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_28d34dfba22a7ce2, []int{15}
+}
+
+func (m *GetRevisionDiffResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetRevisionDiffResponse.Unmarshal(m, b)
+}
+func (m *GetRevisionDiffResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetRevisionDiffResponse.Marshal(b, m, deterministic)
+}
+func (m *GetRevisionDiffResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetRevisionDiffResponse.Merge(m, src)
+}
+func (m *GetRevisionDiffResponse) XXX_Size() int {
+	return xxx_messageInfo_GetRevisionDiffResponse.Size(m)
+}
+func (m *GetRevisionDiffResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetRevisionDiffResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetRevisionDiffResponse proto.InternalMessageInfo
+
+func (m *GetRevisionDiffResponse) GetFromMapRoot() *SignedMapRoot {
+	if m != nil {
+		return m.FromMapRoot
+	}
+	return nil
+}
+
+func (m *GetRevisionDiffResponse) GetToMapRoot() *SignedMapRoot {
+	if m != nil {
+		return m.ToMapRoot
+	}
+	return nil
+}
+
+func (m *GetRevisionDiffResponse) GetDiffs() []*MapLeafDiff {
+	if m != nil {
+		return m.Diffs
+	}
+	return nil
+}
+
+func (m *GetRevisionDiffResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+// GetLeafHistoryRequest requests a page of the values which the leaf at index
+// had from from_revision up to and including to_revision.
+type GetLeafHistoryRequest struct {
+	MapId int64  `protobuf:"varint,1,opt,name=map_id,json=mapId,proto3" json:"map_id,omitempty"`
+	Index []byte `protobuf:"bytes,2,opt,name=index,proto3" json:"index,omitempty"`
+	// from_revision >= 0.
+	FromRevision int64 `protobuf:"varint,3,opt,name=from_revision,json=fromRevision,proto3" json:"from_revision,omitempty"`
+	// to_revision >= from_revision.
+	ToRevision int64 `protobuf:"varint,4,opt,name=to_revision,json=toRevision,proto3" json:"to_revision,omitempty"`
+	// The maximum number of revisions of the leaf to consider. The server may
+	// return fewer versions, and picks a default if page_size is 0.
+	PageSize int32 `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// The next_page_token of the previous response, if any. All other fields
+	// of the request must be the same as in the request for that response.
+	PageToken            string   `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetLeafHistoryRequest) Reset()         { *m = GetLeafHistoryRequest{} }
+func (m *GetLeafHistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*GetLeafHistoryRequest) ProtoMessage()    {}
+func (*GetLeafHistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_28d34dfba22a7ce2, []int{16}
+}
+
+func (m *GetLeafHistoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetLeafHistoryRequest.Unmarshal(m, b)
+}
+func (m *GetLeafHistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetLeafHistoryRequest.Marshal(b, m, deterministic)
+}
+func (m *GetLeafHistoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetLeafHistoryRequest.Merge(m, src)
+}
+func (m *GetLeafHistoryRequest) XXX_Size() int {
+	return xxx_messageInfo_GetLeafHistoryRequest.Size(m)
+}
+func (m *GetLeafHistoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetLeafHistoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetLeafHistoryRequest proto.InternalMessageInfo
+
+func (m *GetLeafHistoryRequest) GetMapId() int64 {
+	if m != nil {
+		return m.MapId
+	}
+	return 0
+}
+
+func (m *GetLeafHistoryRequest) GetIndex() []byte {
+	if m != nil {
+		return m.Index
+	}
+	return nil
+}
+
+func (m *GetLeafHistoryRequest) GetFromRevision() int64 {
+	if m != nil {
+		return m.FromRevision
+	}
+	return 0
+}
+
+func (m *GetLeafHistoryRequest) GetToRevision() int64 {
+	if m != nil {
+		return m.ToRevision
+	}
+	return 0
+}
+
+func (m *GetLeafHistoryRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *GetLeafHistoryRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+// MapLeafVersion is the value of a map leaf at a revision, with its inclusion
+// proof in the map root of that revision.
+type MapLeafVersion struct {
+	MapRoot              *SignedMapRoot    `protobuf:"bytes,1,opt,name=map_root,json=mapRoot,proto3" json:"map_root,omitempty"`
+	Leaf                 *MapLeafInclusion `protobuf:"bytes,2,opt,name=leaf,proto3" json:"leaf,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *MapLeafVersion) Reset()         { *m = MapLeafVersion{} }
+func (m *MapLeafVersion) String() string { return proto.CompactTextString(m) }
+func (*MapLeafVersion) ProtoMessage()    {}
+func (*MapLeafVersion) Descriptor() ([]byte, []int) {
+	return fileDescriptor_28d34dfba22a7ce2, []int{17}
+}
+
+func (m *MapLeafVersion) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MapLeafVersion.Unmarshal(m, b)
+}
+func (m *MapLeafVersion) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MapLeafVersion.Marshal(b, m, deterministic)
+}
+func (m *MapLeafVersion) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MapLeafVersion.Merge(m, src)
+}
+func (m *MapLeafVersion) XXX_Size() int {
+	return xxx_messageInfo_MapLeafVersion.Size(m)
+}
+func (m *MapLeafVersion) XXX_DiscardUnknown() {
+	xxx_messageInfo_MapLeafVersion.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MapLeafVersion proto.InternalMessageInfo
+
+func (m *MapLeafVersion) GetMapRoot() *SignedMapRoot {
+	if m != nil {
+		return m.MapRoot
+	}
+	return nil
+}
+
+func (m *MapLeafVersion) GetLeaf() *MapLeafInclusion {
+	if m != nil {
+		return m.Leaf
+	}
+	return nil
+}
+
+type GetLeafHistoryResponse struct {
+	// The versions of the leaf, in ascending order of revision. The first page
+	// starts with the leaf at from_revision, and is followed by the leaf at each
+	// revision where its value changed.
+	Versions []*MapLeafVersion `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
+	// A token to request the next page with, or empty if there are no more
+	// revisions of the leaf up to to_revision.
+	NextPageToken        string   `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetLeafHistoryResponse) Reset()         { *m = GetLeafHistoryResponse{} }
+func (m *GetLeafHistoryResponse) String() string { return proto.CompactTextString(m) }
+func (*GetLeafHistoryResponse) ProtoMessage()    {}
+func (*GetLeafHistoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_28d34dfba22a7ce2, []int{18}
+}
+
+func (m *GetLeafHistoryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetLeafHistoryResponse.Unmarshal(m, b)
+}
+func (m *GetLeafHistoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetLeafHistoryResponse.Marshal(b, m, deterministic)
+}
+func (m *GetLeafHistoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetLeafHistoryResponse.Merge(m, src)
+}
+func (m *GetLeafHistoryResponse) XXX_Size() int {
+	return xxx_messageInfo_GetLeafHistoryResponse.Size(m)
+}
+func (m *GetLeafHistoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetLeafHistoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetLeafHistoryResponse proto.InternalMessageInfo
+
+func (m *GetLeafHistoryResponse) GetVersions() []*MapLeafVersion {
+	if m != nil {
+		return m.Versions
+	}
+	return nil
+}
+
+func (m *GetLeafHistoryResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
 type SetMapLeavesRequest struct {
 	MapId int64 `protobuf:"varint,1,opt,name=map_id,json=mapId,proto3" json:"map_id,omitempty"`
 	// The leaves being set must have unique Index values within the request.
@@ -578,7 +1324,11 @@ type SetMapLeavesRequest struct {
 	// this revision already exists, does not match the current write revision, or
 	// is negative. If revision = 0 then the leaves will be written to the current
 	// write revision.
-	Revision             int64    `protobuf:"varint,6,opt,name=revision,proto3" json:"revision,omitempty"`
+	Revision int64 `protobuf:"varint,6,opt,name=revision,proto3" json:"revision,omitempty"`
+	// Raw keys of the leaves, in the same order as leaves. If set, the server
+	// derives the index of each leaf from its key with the map's VRF, and the
+	// leaves must not have an index.
+	Keys                 [][]byte `protobuf:"bytes,7,rep,name=keys,proto3" json:"keys,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -588,7 +1338,7 @@ func (m *SetMapLeavesRequest) Reset()         { *m = SetMapLeavesRequest{} }
 func (m *SetMapLeavesRequest) String() string { return proto.CompactTextString(m) }
 func (*SetMapLeavesRequest) ProtoMessage()    {}
 func (*SetMapLeavesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_28d34dfba22a7ce2, []int{10}
+	return fileDescriptor_28d34dfba22a7ce2, []int{19}
 }
 
 func (m *SetMapLeavesRequest) XXX_Unmarshal(b []byte) error {
@@ -637,6 +1387,13 @@ func (m *SetMapLeavesRequest) GetRevision() int64 {
 	return 0
 }
 
+func (m *SetMapLeavesRequest) GetKeys() [][]byte {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
 type SetMapLeavesResponse struct {
 	MapRoot              *SignedMapRoot `protobuf:"bytes,2,opt,name=map_root,json=mapRoot,proto3" json:"map_root,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
@@ -648,7 +1405,7 @@ func (m *SetMapLeavesResponse) Reset()         { *m = SetMapLeavesResponse{} }
 func (m *SetMapLeavesResponse) String() string { return proto.CompactTextString(m) }
 func (*SetMapLeavesResponse) ProtoMessage()    {}
 func (*SetMapLeavesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_28d34dfba22a7ce2, []int{11}
+	return fileDescriptor_28d34dfba22a7ce2, []int{20}
 }
 
 func (m *SetMapLeavesResponse) XXX_Unmarshal(b []byte) error {
@@ -700,7 +1457,7 @@ func (m *WriteMapLeavesRequest) Reset()         { *m = WriteMapLeavesRequest{} }
 func (m *WriteMapLeavesRequest) String() string { return proto.CompactTextString(m) }
 func (*WriteMapLeavesRequest) ProtoMessage()    {}
 func (*WriteMapLeavesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_28d34dfba22a7ce2, []int{12}
+	return fileDescriptor_28d34dfba22a7ce2, []int{21}
 }
 
 func (m *WriteMapLeavesRequest) XXX_Unmarshal(b []byte) error {
@@ -763,7 +1520,7 @@ func (m *WriteMapLeavesResponse) Reset()         { *m = WriteMapLeavesResponse{}
 func (m *WriteMapLeavesResponse) String() string { return proto.CompactTextString(m) }
 func (*WriteMapLeavesResponse) ProtoMessage()    {}
 func (*WriteMapLeavesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_28d34dfba22a7ce2, []int{13}
+	return fileDescriptor_28d34dfba22a7ce2, []int{22}
 }
 
 func (m *WriteMapLeavesResponse) XXX_Unmarshal(b []byte) error {
@@ -802,7 +1559,7 @@ func (m *GetSignedMapRootRequest) Reset()         { *m = GetSignedMapRootRequest
 func (m *GetSignedMapRootRequest) String() string { return proto.CompactTextString(m) }
 func (*GetSignedMapRootRequest) ProtoMessage()    {}
 func (*GetSignedMapRootRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_28d34dfba22a7ce2, []int{14}
+	return fileDescriptor_28d34dfba22a7ce2, []int{23}
 }
 
 func (m *GetSignedMapRootRequest) XXX_Unmarshal(b []byte) error {
@@ -842,7 +1599,7 @@ func (m *GetSignedMapRootByRevisionRequest) Reset()         { *m = GetSignedMapR
 func (m *GetSignedMapRootByRevisionRequest) String() string { return proto.CompactTextString(m) }
 func (*GetSignedMapRootByRevisionRequest) ProtoMessage()    {}
 func (*GetSignedMapRootByRevisionRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_28d34dfba22a7ce2, []int{15}
+	return fileDescriptor_28d34dfba22a7ce2, []int{24}
 }
 
 func (m *GetSignedMapRootByRevisionRequest) XXX_Unmarshal(b []byte) error {
@@ -888,7 +1645,7 @@ func (m *GetSignedMapRootResponse) Reset()         { *m = GetSignedMapRootRespon
 func (m *GetSignedMapRootResponse) String() string { return proto.CompactTextString(m) }
 func (*GetSignedMapRootResponse) ProtoMessage()    {}
 func (*GetSignedMapRootResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_28d34dfba22a7ce2, []int{16}
+	return fileDescriptor_28d34dfba22a7ce2, []int{25}
 }
 
 func (m *GetSignedMapRootResponse) XXX_Unmarshal(b []byte) error {
@@ -927,7 +1684,7 @@ func (m *InitMapRequest) Reset()         { *m = InitMapRequest{} }
 func (m *InitMapRequest) String() string { return proto.CompactTextString(m) }
 func (*InitMapRequest) ProtoMessage()    {}
 func (*InitMapRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_28d34dfba22a7ce2, []int{17}
+	return fileDescriptor_28d34dfba22a7ce2, []int{26}
 }
 
 func (m *InitMapRequest) XXX_Unmarshal(b []byte) error {
@@ -966,7 +1723,7 @@ func (m *InitMapResponse) Reset()         { *m = InitMapResponse{} }
 func (m *InitMapResponse) String() string { return proto.CompactTextString(m) }
 func (*InitMapResponse) ProtoMessage()    {}
 func (*InitMapResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_28d34dfba22a7ce2, []int{18}
+	return fileDescriptor_28d34dfba22a7ce2, []int{27}
 }
 
 func (m *InitMapResponse) XXX_Unmarshal(b []byte) error {
@@ -995,8 +1752,10 @@ func (m *InitMapResponse) GetCreated() *SignedMapRoot {
 }
 
 func init() {
+	proto.RegisterEnum("trillian.MapProofFormat", MapProofFormat_name, MapProofFormat_value)
 	proto.RegisterType((*MapLeaf)(nil), "trillian.MapLeaf")
 	proto.RegisterType((*MapLeaves)(nil), "trillian.MapLeaves")
+	proto.RegisterType((*CompressedMapInclusionProof)(nil), "trillian.CompressedMapInclusionProof")
 	proto.RegisterType((*MapLeafInclusion)(nil), "trillian.MapLeafInclusion")
 	proto.RegisterType((*GetMapLeavesRequest)(nil), "trillian.GetMapLeavesRequest")
 	proto.RegisterType((*GetMapLeafRequest)(nil), "trillian.GetMapLeafRequest")
@@ -1005,6 +1764,14 @@ func init() {
 	proto.RegisterType((*GetMapLeafResponse)(nil), "trillian.GetMapLeafResponse")
 	proto.RegisterType((*GetMapLeavesResponse)(nil), "trillian.GetMapLeavesResponse")
 	proto.RegisterType((*GetLastInRangeByRevisionRequest)(nil), "trillian.GetLastInRangeByRevisionRequest")
+	proto.RegisterType((*ListMapLeavesByRevisionRequest)(nil), "trillian.ListMapLeavesByRevisionRequest")
+	proto.RegisterType((*ListMapLeavesByRevisionResponse)(nil), "trillian.ListMapLeavesByRevisionResponse")
+	proto.RegisterType((*GetRevisionDiffRequest)(nil), "trillian.GetRevisionDiffRequest")
+	proto.RegisterType((*MapLeafDiff)(nil), "trillian.MapLeafDiff")
+	proto.RegisterType((*GetRevisionDiffResponse)(nil), "trillian.GetRevisionDiffResponse")
+	proto.RegisterType((*GetLeafHistoryRequest)(nil), "trillian.GetLeafHistoryRequest")
+	proto.RegisterType((*MapLeafVersion)(nil), "trillian.MapLeafVersion")
+	proto.RegisterType((*GetLeafHistoryResponse)(nil), "trillian.GetLeafHistoryResponse")
 	proto.RegisterType((*SetMapLeavesRequest)(nil), "trillian.SetMapLeavesRequest")
 	proto.RegisterType((*SetMapLeavesResponse)(nil), "trillian.SetMapLeavesResponse")
 	proto.RegisterType((*WriteMapLeavesRequest)(nil), "trillian.WriteMapLeavesRequest")
@@ -1019,69 +1786,108 @@ func init() {
 func init() { proto.RegisterFile("trillian_map_api.proto", fileDescriptor_28d34dfba22a7ce2) }
 
 var fileDescriptor_28d34dfba22a7ce2 = []byte{
-	// 981 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x57, 0xdd, 0x6e, 0x1b, 0x45,
-	0x14, 0xee, 0x7a, 0xfd, 0x97, 0x63, 0x94, 0xba, 0x93, 0x34, 0x75, 0x37, 0x09, 0x49, 0xa7, 0x8a,
-	0xd2, 0xa8, 0x92, 0x97, 0x18, 0xc4, 0x45, 0x84, 0x10, 0x44, 0x11, 0xf9, 0x51, 0x12, 0x45, 0x6b,
-	0x68, 0xa5, 0x0a, 0xc9, 0x4c, 0xec, 0xb1, 0x3d, 0xd2, 0x7a, 0x67, 0xd9, 0x9d, 0x44, 0x81, 0xaa,
-	0x37, 0x5c, 0x70, 0xc7, 0x0d, 0x70, 0x87, 0x94, 0x37, 0xe9, 0x53, 0xf0, 0x0a, 0x3c, 0x08, 0x9a,
-	0x99, 0xf5, 0xae, 0xd7, 0x5e, 0x3b, 0x56, 0x0a, 0x77, 0x3b, 0xe7, 0xff, 0x9b, 0xef, 0x9c, 0x33,
-	0x36, 0xac, 0x88, 0x80, 0xb9, 0x2e, 0x23, 0x5e, 0x6b, 0x40, 0xfc, 0x16, 0xf1, 0x59, 0xdd, 0x0f,
-	0xb8, 0xe0, 0xa8, 0x3c, 0x94, 0x5b, 0x8b, 0xc3, 0x2f, 0xad, 0xb1, 0xd6, 0x7a, 0x9c, 0xf7, 0x5c,
-	0x6a, 0x13, 0x9f, 0xd9, 0xc4, 0xf3, 0xb8, 0x20, 0x82, 0x71, 0x2f, 0xd4, 0x5a, 0xfc, 0x33, 0x94,
-	0xce, 0x88, 0x7f, 0x4a, 0x49, 0x17, 0x2d, 0x43, 0x81, 0x79, 0x1d, 0x7a, 0x53, 0x33, 0x36, 0x8d,
-	0x17, 0x1f, 0x39, 0xfa, 0x80, 0x56, 0x61, 0xc1, 0xa5, 0xa4, 0xdb, 0xea, 0x93, 0xb0, 0x5f, 0xcb,
-	0x29, 0x4d, 0x59, 0x0a, 0x8e, 0x48, 0xd8, 0x47, 0xeb, 0x00, 0x4a, 0x79, 0x4d, 0xdc, 0x2b, 0x5a,
-	0x33, 0x95, 0x56, 0x99, 0xbf, 0x92, 0x02, 0xa9, 0xa6, 0x37, 0x22, 0x20, 0xad, 0x0e, 0x11, 0xa4,
-	0x96, 0xd7, 0x6a, 0x25, 0x39, 0x20, 0x82, 0xe0, 0xcf, 0x61, 0x41, 0xe7, 0xbe, 0xa6, 0x21, 0xda,
-	0x81, 0xa2, 0xab, 0xbe, 0x6a, 0xc6, 0xa6, 0xf9, 0xa2, 0xd2, 0x78, 0x54, 0x8f, 0x71, 0x44, 0x05,
-	0x3a, 0x91, 0x01, 0x7e, 0x0d, 0xd5, 0x48, 0x74, 0xec, 0xb5, 0xdd, 0xab, 0x90, 0x71, 0x0f, 0x6d,
-	0x41, 0x5e, 0xe6, 0x55, 0xb5, 0x67, 0x3a, 0x2b, 0x35, 0x5a, 0x83, 0x05, 0x36, 0xf4, 0xa9, 0xe5,
-	0x36, 0x4d, 0x59, 0x50, 0x2c, 0xc0, 0x47, 0xb0, 0x74, 0x48, 0x45, 0x5c, 0x93, 0x43, 0x7f, 0xbc,
-	0xa2, 0xa1, 0x40, 0x8f, 0xa1, 0x28, 0x2f, 0x9b, 0x75, 0x54, 0x74, 0xd3, 0x29, 0x0c, 0x88, 0x7f,
-	0xdc, 0x49, 0xee, 0x4b, 0xc7, 0xd1, 0x87, 0x93, 0x7c, 0xd9, 0xac, 0xe6, 0xf1, 0x57, 0xf0, 0x28,
-	0x8e, 0xd4, 0x9d, 0x3f, 0x4e, 0x72, 0xef, 0xb8, 0x0b, 0xab, 0x49, 0x84, 0xfd, 0x9f, 0x1c, 0x7a,
-	0xcd, 0x64, 0x8d, 0xf7, 0x89, 0x85, 0x2c, 0x28, 0x07, 0x91, 0xbf, 0x22, 0xc9, 0x74, 0xe2, 0x33,
-	0xee, 0xc3, 0xfa, 0x28, 0xe6, 0xfb, 0x64, 0x32, 0xe7, 0xcb, 0xf4, 0xbb, 0x01, 0x68, 0xf4, 0x52,
-	0x42, 0x9f, 0x7b, 0x21, 0x45, 0x47, 0x80, 0x64, 0x7c, 0xd5, 0x47, 0x09, 0x37, 0x9a, 0x47, 0x6b,
-	0x82, 0xc7, 0x98, 0x71, 0xa7, 0x3a, 0x18, 0xef, 0x81, 0x06, 0x94, 0x65, 0xa4, 0x80, 0x73, 0xa1,
-	0xf0, 0x57, 0x1a, 0x4f, 0x12, 0xff, 0x26, 0xeb, 0x79, 0xb4, 0x73, 0x46, 0x7c, 0x87, 0x73, 0xe1,
-	0x94, 0x06, 0xfa, 0x03, 0xff, 0x69, 0xc0, 0x72, 0x9a, 0xf3, 0x99, 0x65, 0xe5, 0x54, 0x6f, 0xde,
-	0xbf, 0x2c, 0x73, 0xce, 0xb2, 0x7e, 0x33, 0x60, 0xe3, 0x90, 0x8a, 0x53, 0x12, 0x8a, 0x63, 0xcf,
-	0x21, 0x5e, 0x8f, 0xce, 0x4d, 0xcc, 0x28, 0x05, 0xb9, 0x34, 0x05, 0x68, 0x05, 0x8a, 0x7e, 0x40,
-	0xbb, 0xec, 0x26, 0x9a, 0xd5, 0xe8, 0x84, 0x36, 0xa0, 0xa2, 0xbf, 0x5a, 0x97, 0x4c, 0x84, 0x6a,
-	0x52, 0x0b, 0x0e, 0x68, 0xd1, 0x3e, 0x13, 0x21, 0xfe, 0xcb, 0x80, 0xa5, 0xe6, 0xfc, 0xa3, 0x91,
-	0x0c, 0x73, 0xee, 0x8e, 0x61, 0x96, 0xe5, 0x0e, 0xa8, 0x20, 0x6a, 0x43, 0x14, 0xf4, 0x7a, 0x19,
-	0x9e, 0x53, 0x50, 0x8a, 0x69, 0x28, 0x7a, 0xce, 0x4e, 0xf2, 0xe5, 0x7c, 0xb5, 0x80, 0x4f, 0x60,
-	0xb9, 0x99, 0xc5, 0xe1, 0x7d, 0x1a, 0xe2, 0xd6, 0x80, 0xc7, 0xaf, 0x03, 0x26, 0xe8, 0xff, 0x8c,
-	0xd5, 0x1c, 0xc3, 0xba, 0x0d, 0x0f, 0xe9, 0x8d, 0x4f, 0xdb, 0xa2, 0x15, 0x43, 0xce, 0xab, 0x34,
-	0x8b, 0x5a, 0x3c, 0x64, 0x1f, 0x7f, 0x06, 0x2b, 0xe3, 0xf5, 0x45, 0x70, 0x47, 0xaf, 0xcb, 0x18,
-	0x1b, 0xbe, 0x4f, 0xe0, 0xc9, 0x21, 0x15, 0x69, 0xcc, 0x33, 0x71, 0xe1, 0x57, 0xf0, 0x6c, 0xdc,
-	0xe3, 0xbf, 0xe8, 0x41, 0x7c, 0x0e, 0xb5, 0xc9, 0x4a, 0x3e, 0x80, 0xb0, 0x6d, 0x58, 0x3c, 0xf6,
-	0x98, 0x64, 0xff, 0x0e, 0x40, 0x07, 0xf0, 0x30, 0x36, 0x8c, 0xf2, 0xed, 0x42, 0xa9, 0x1d, 0x50,
-	0x22, 0x68, 0x27, 0x5a, 0x38, 0xd3, 0xd3, 0x45, 0x76, 0x8d, 0xf7, 0x65, 0xa8, 0x7c, 0x1b, 0xd9,
-	0x9c, 0x11, 0x1f, 0x7d, 0x03, 0x25, 0x39, 0xa8, 0xf2, 0x71, 0x59, 0x4d, 0x9c, 0x27, 0x96, 0xbf,
-	0xb5, 0x96, 0xad, 0xd4, 0x85, 0xe0, 0x07, 0xe8, 0x8d, 0x7a, 0x31, 0xd2, 0xcb, 0x1e, 0x6d, 0x65,
-	0x39, 0x4d, 0xb0, 0x70, 0x67, 0xec, 0x53, 0x58, 0xd0, 0xb1, 0x65, 0x13, 0xae, 0x67, 0x18, 0x27,
-	0x5d, 0x6e, 0x7d, 0x3c, 0x4d, 0x1d, 0x47, 0xfb, 0x41, 0xbd, 0x92, 0xe3, 0xcf, 0x05, 0xda, 0xce,
-	0x76, 0x9c, 0xac, 0xf6, 0xee, 0x0c, 0xdf, 0x83, 0x95, 0x91, 0xe1, 0x9c, 0x5f, 0x04, 0x9c, 0x77,
-	0xe7, 0x4f, 0xb4, 0x34, 0x3e, 0x89, 0xf2, 0xc7, 0xc3, 0x03, 0x74, 0x6b, 0xa8, 0x0e, 0xcc, 0xdc,
-	0xad, 0x68, 0x27, 0x15, 0x7c, 0xd6, 0xfe, 0xb5, 0x26, 0x07, 0x1d, 0x1f, 0xfc, 0xf2, 0xf7, 0x3f,
-	0x7f, 0xe4, 0xbe, 0x44, 0x5f, 0xd8, 0xd7, 0xbb, 0x97, 0x54, 0x90, 0x5d, 0x7b, 0x40, 0xfc, 0xd0,
-	0x7e, 0xab, 0xdb, 0xf1, 0x9d, 0x2d, 0x1b, 0x3b, 0xb4, 0xdf, 0x0e, 0x67, 0xe1, 0x9d, 0xad, 0x17,
-	0xc3, 0x9e, 0x4b, 0x42, 0xd1, 0x62, 0x5e, 0x2b, 0x90, 0x99, 0x24, 0x5d, 0xcd, 0x2c, 0xba, 0x9a,
-	0xb3, 0xe9, 0x6a, 0x66, 0x5f, 0xe6, 0xaf, 0x06, 0x54, 0xc7, 0x07, 0x0e, 0x3d, 0x4b, 0xc1, 0xcc,
-	0x5a, 0x0b, 0x16, 0x9e, 0x65, 0x12, 0x45, 0x7f, 0xa9, 0xf0, 0x6e, 0xa1, 0xe7, 0xb3, 0xf0, 0xee,
-	0xb9, 0x44, 0xc8, 0xb1, 0xbc, 0x35, 0x14, 0xad, 0x53, 0x36, 0x0a, 0x7a, 0x39, 0x3d, 0xdf, 0xe4,
-	0xdd, 0xcf, 0x53, 0x9c, 0xad, 0x8a, 0xdb, 0x41, 0xdb, 0x73, 0x92, 0x81, 0xda, 0x50, 0x8a, 0x16,
-	0x04, 0xaa, 0x25, 0xf1, 0xd3, 0xcb, 0xc5, 0x7a, 0x9a, 0xa1, 0x89, 0x12, 0x3e, 0x57, 0x09, 0xd7,
-	0xf1, 0x6a, 0x76, 0xc2, 0x3d, 0xe6, 0x31, 0xd1, 0x78, 0x6f, 0x40, 0x75, 0x64, 0x7f, 0xa8, 0x55,
-	0x8e, 0xbe, 0xfb, 0xc0, 0x91, 0x9a, 0xd2, 0xe9, 0x0e, 0x54, 0x54, 0xfc, 0xa8, 0x95, 0x36, 0x12,
-	0xab, 0xcc, 0x17, 0xce, 0xda, 0x9c, 0x6e, 0x30, 0x6c, 0xa7, 0xfd, 0x73, 0x78, 0xda, 0xe6, 0x83,
-	0xba, 0xfe, 0x53, 0x51, 0x4f, 0xff, 0xd7, 0xd8, 0x5f, 0x1a, 0x41, 0xf6, 0xb5, 0xcf, 0x2e, 0xa4,
-	0xf0, 0xc2, 0x78, 0x63, 0xf5, 0x98, 0xe8, 0x5f, 0x5d, 0xd6, 0xdb, 0x7c, 0x60, 0x47, 0xff, 0x46,
-	0x86, 0x8e, 0x97, 0x45, 0xe5, 0xf9, 0xe9, 0xbf, 0x01, 0x00, 0x00, 0xff, 0xff, 0xdf, 0x22, 0xc9,
-	0xc2, 0xd9, 0x0c, 0x00, 0x00,
+	// 1603 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x58, 0x4b, 0x6f, 0xdb, 0x56,
+	0x16, 0x0e, 0x45, 0x3d, 0x8f, 0x62, 0x59, 0xb9, 0x7e, 0x44, 0xa1, 0xe3, 0x58, 0x66, 0xe0, 0xb1,
+	0x3d, 0x01, 0xac, 0x89, 0x13, 0x4c, 0x80, 0x64, 0x30, 0x18, 0x3b, 0x8e, 0x63, 0x07, 0x76, 0x6c,
+	0x50, 0x4e, 0x26, 0x08, 0x06, 0xe0, 0x5c, 0x5b, 0x57, 0x36, 0x27, 0x12, 0xc9, 0x21, 0xaf, 0x05,
+	0x3b, 0x41, 0x36, 0xb3, 0xc8, 0x6e, 0x16, 0x6d, 0x17, 0xdd, 0xe5, 0x17, 0x14, 0xe8, 0xb2, 0xff,
+	0xa0, 0xcb, 0x6e, 0xba, 0xe9, 0xa6, 0xbb, 0xfc, 0x84, 0xfe, 0x80, 0xe2, 0x3e, 0x44, 0x49, 0x24,
+	0xf5, 0x80, 0x9d, 0x74, 0xc7, 0x7b, 0xce, 0xb9, 0xe7, 0xc5, 0xef, 0x7e, 0xf7, 0x90, 0x30, 0x4d,
+	0x3d, 0xab, 0xd1, 0xb0, 0xb0, 0x6d, 0x36, 0xb1, 0x6b, 0x62, 0xd7, 0x5a, 0x71, 0x3d, 0x87, 0x3a,
+	0x28, 0xdb, 0x96, 0x6b, 0x85, 0xf6, 0x93, 0xd0, 0x68, 0x37, 0x8f, 0x1d, 0xe7, 0xb8, 0x41, 0x2a,
+	0xd8, 0xb5, 0x2a, 0xd8, 0xb6, 0x1d, 0x8a, 0xa9, 0xe5, 0xd8, 0xbe, 0xd0, 0xea, 0x6f, 0x21, 0xb3,
+	0x8b, 0xdd, 0x1d, 0x82, 0xeb, 0x68, 0x12, 0x52, 0x96, 0x5d, 0x23, 0x67, 0x25, 0xa5, 0xac, 0x2c,
+	0x5d, 0x35, 0xc4, 0x02, 0xcd, 0x40, 0xae, 0x41, 0x70, 0xdd, 0x3c, 0xc1, 0xfe, 0x49, 0x29, 0xc1,
+	0x35, 0x59, 0x26, 0xd8, 0xc2, 0xfe, 0x09, 0x9a, 0x05, 0xe0, 0xca, 0x16, 0x6e, 0x9c, 0x92, 0x92,
+	0xca, 0xb5, 0xdc, 0xfc, 0x25, 0x13, 0x30, 0x35, 0x39, 0xa3, 0x1e, 0x36, 0x6b, 0x98, 0xe2, 0x52,
+	0x52, 0xa8, 0xb9, 0x64, 0x03, 0x53, 0xac, 0xff, 0x15, 0x72, 0x22, 0x76, 0x8b, 0xf8, 0x68, 0x19,
+	0xd2, 0x0d, 0xfe, 0x54, 0x52, 0xca, 0xea, 0x52, 0x7e, 0xf5, 0xda, 0x4a, 0x50, 0x87, 0x4c, 0xd0,
+	0x90, 0x06, 0xfa, 0x7f, 0x60, 0xe6, 0xb1, 0xd3, 0x74, 0x3d, 0xe2, 0xfb, 0xa4, 0xb6, 0x8b, 0xdd,
+	0x6d, 0xfb, 0xa8, 0x71, 0xea, 0x5b, 0x8e, 0xbd, 0xef, 0x39, 0x4e, 0x1d, 0x4d, 0x43, 0xfa, 0xd0,
+	0xa2, 0x4d, 0xec, 0xca, 0x42, 0xe4, 0x8a, 0xc9, 0x59, 0x11, 0xc4, 0x2f, 0x25, 0xca, 0x2a, 0x93,
+	0x8b, 0x15, 0xab, 0x90, 0x3d, 0x99, 0x1e, 0xa9, 0xfb, 0x25, 0xb5, 0xac, 0x2e, 0x8d, 0x19, 0x59,
+	0x26, 0x30, 0x48, 0xdd, 0xd7, 0x7f, 0x52, 0xa0, 0x28, 0xe3, 0x07, 0x61, 0xd0, 0x02, 0x24, 0x59,
+	0x91, 0xdc, 0x7f, 0x6c, 0xa6, 0x5c, 0x8d, 0x6e, 0x42, 0xce, 0x6a, 0xef, 0x91, 0x31, 0x3b, 0x02,
+	0xf4, 0x0a, 0x26, 0x8f, 0x82, 0x2a, 0xcc, 0x8e, 0xa1, 0xca, 0x9d, 0x2e, 0x74, 0x9c, 0x0e, 0xa8,
+	0xd5, 0x98, 0xe8, 0xb8, 0xe8, 0xa4, 0x37, 0x03, 0xb9, 0x96, 0x57, 0x37, 0x5d, 0x66, 0x21, 0xbb,
+	0x9e, 0x6d, 0x79, 0x75, 0xbe, 0x43, 0xff, 0x56, 0x81, 0x89, 0xa7, 0x84, 0x06, 0x8d, 0x37, 0xc8,
+	0x7f, 0x4f, 0x89, 0x4f, 0xd1, 0x14, 0xa4, 0x19, 0xa2, 0xac, 0x1a, 0xaf, 0x4a, 0x35, 0x52, 0x4d,
+	0xec, 0x6e, 0xd7, 0x3a, 0xa0, 0x10, 0xf9, 0x8b, 0x05, 0x7a, 0x04, 0x57, 0xb9, 0x77, 0xb3, 0xee,
+	0x78, 0x4d, 0x4c, 0x79, 0x90, 0xc2, 0x6a, 0xa9, 0xa7, 0x11, 0x3c, 0xdc, 0x26, 0xd7, 0x1b, 0x79,
+	0xb7, 0xb3, 0x40, 0x45, 0x50, 0xdf, 0x90, 0xf3, 0x52, 0x8a, 0x3b, 0x64, 0x8f, 0xcf, 0x92, 0x59,
+	0xb5, 0x98, 0xd4, 0xff, 0x01, 0xd7, 0x82, 0xc4, 0xea, 0xa3, 0xa7, 0xd5, 0xc1, 0xaa, 0x5e, 0x87,
+	0x99, 0x8e, 0x87, 0xf5, 0x73, 0x83, 0xb4, 0x2c, 0xd6, 0x90, 0x8b, 0xf8, 0x42, 0x1a, 0x64, 0x3d,
+	0xb9, 0x9f, 0xbf, 0x12, 0xd5, 0x08, 0xd6, 0xfa, 0x0f, 0x0a, 0xcc, 0x76, 0xf7, 0xf0, 0x22, 0xa1,
+	0xd4, 0x91, 0x42, 0x7d, 0xe6, 0x4e, 0xeb, 0x5f, 0x2b, 0x80, 0xba, 0x9b, 0xec, 0xbb, 0x8e, 0xed,
+	0x13, 0xb4, 0x05, 0x88, 0xa5, 0xcb, 0xcf, 0x72, 0x07, 0x89, 0x02, 0xde, 0x5a, 0x04, 0xde, 0x01,
+	0xd2, 0x8c, 0x62, 0x33, 0x24, 0x41, 0xab, 0x90, 0x65, 0x9e, 0x3c, 0xc7, 0xa1, 0xbc, 0x9f, 0xf9,
+	0xd5, 0xeb, 0x9d, 0xfd, 0x55, 0xeb, 0xd8, 0xe6, 0x28, 0x36, 0x1c, 0x87, 0x1a, 0x99, 0xa6, 0x78,
+	0xd0, 0x7f, 0x53, 0x60, 0xb2, 0x17, 0x92, 0x03, 0xd3, 0x4a, 0x94, 0xd5, 0x4b, 0xa5, 0xa5, 0x8e,
+	0x96, 0xd6, 0xe5, 0x5a, 0x3f, 0xdf, 0xde, 0x2c, 0x29, 0x47, 0xbc, 0x03, 0x61, 0xb2, 0xc5, 0x45,
+	0xfa, 0xff, 0x15, 0x98, 0x7b, 0x4a, 0xe8, 0x0e, 0xf6, 0xe9, 0xb6, 0x6d, 0x60, 0xfb, 0x98, 0x8c,
+	0x8c, 0xa3, 0x6e, 0xc4, 0x24, 0x42, 0x88, 0x99, 0x86, 0xb4, 0xeb, 0x91, 0xba, 0x75, 0x26, 0xf9,
+	0x58, 0xae, 0xd0, 0x1c, 0xe4, 0xc5, 0x93, 0x79, 0x68, 0x51, 0x9f, 0x57, 0x93, 0x32, 0x40, 0x88,
+	0xd6, 0x2d, 0xea, 0xeb, 0x1f, 0x12, 0x70, 0x6b, 0xc7, 0xf2, 0x2f, 0x00, 0xeb, 0x2f, 0x91, 0x0e,
+	0x33, 0xf0, 0x29, 0xf6, 0xa8, 0x29, 0x4e, 0x4c, 0x8a, 0xef, 0x06, 0x2e, 0xda, 0x6e, 0xdf, 0x4c,
+	0xc4, 0xae, 0x49, 0x75, 0x5a, 0xd0, 0x1c, 0xb1, 0x6b, 0x81, 0xd2, 0xc5, 0xc7, 0xc4, 0xf4, 0xad,
+	0xb7, 0xa4, 0x94, 0xe1, 0xce, 0xb3, 0x4c, 0x50, 0xb5, 0xde, 0xf2, 0x7b, 0x89, 0x2b, 0xa9, 0xf3,
+	0x86, 0xd8, 0xa5, 0x6c, 0x59, 0x59, 0xca, 0x19, 0xdc, 0xfc, 0x80, 0x09, 0x74, 0x0a, 0x73, 0x7d,
+	0xfb, 0x20, 0x91, 0x39, 0xfa, 0x6d, 0x85, 0xfe, 0x04, 0xe3, 0x36, 0x39, 0xa3, 0x66, 0x57, 0xc4,
+	0x04, 0x8f, 0x38, 0xc6, 0xc4, 0xfb, 0x41, 0xd4, 0x5f, 0x14, 0x98, 0x7e, 0x4a, 0x68, 0x3b, 0xd4,
+	0x86, 0x55, 0x1f, 0x46, 0x82, 0xb7, 0x61, 0xac, 0xee, 0x39, 0x4d, 0x33, 0xd4, 0xfb, 0xab, 0x4c,
+	0xd8, 0x76, 0xc3, 0xda, 0x48, 0x1d, 0x33, 0xc4, 0x2f, 0x40, 0x9d, 0xc0, 0x60, 0x01, 0x0a, 0xfc,
+	0x6c, 0xd5, 0x88, 0xb8, 0xc6, 0xc5, 0xbb, 0xc8, 0x1a, 0x63, 0x52, 0xca, 0xaf, 0x72, 0xbf, 0xb7,
+	0xa1, 0xa9, 0x81, 0x0d, 0x4d, 0x87, 0x1b, 0xfa, 0x95, 0x02, 0x79, 0xd9, 0x16, 0x56, 0x56, 0x9f,
+	0x49, 0xe3, 0x01, 0xe4, 0x78, 0x39, 0xfc, 0x6a, 0x4d, 0x0c, 0xe5, 0x9e, 0x2c, 0x33, 0x66, 0x22,
+	0x74, 0x0f, 0x32, 0xd4, 0x11, 0xdb, 0xd4, 0xa1, 0xdb, 0xd2, 0xd4, 0x61, 0x02, 0xfd, 0x93, 0x02,
+	0xd7, 0x23, 0xed, 0x96, 0x6f, 0xf7, 0x91, 0x6c, 0x6c, 0x40, 0x19, 0xca, 0x60, 0xca, 0xc8, 0x33,
+	0x6b, 0xb9, 0x40, 0x0f, 0x78, 0xc3, 0x47, 0x25, 0xc1, 0x1c, 0x75, 0xda, 0x1b, 0xef, 0x40, 0xaa,
+	0x66, 0xd5, 0xe5, 0x0c, 0x92, 0x5f, 0x9d, 0x8a, 0x14, 0xc1, 0x73, 0x14, 0x36, 0x71, 0xa8, 0x4a,
+	0xc6, 0xa1, 0xea, 0x47, 0x05, 0xa6, 0x18, 0xc9, 0xb0, 0x89, 0xcd, 0xf2, 0xa9, 0xe3, 0x9d, 0x5f,
+	0xe8, 0x36, 0x8c, 0x40, 0x4d, 0x1d, 0x0e, 0xb5, 0x64, 0x04, 0x6a, 0x97, 0xc1, 0x10, 0x85, 0x82,
+	0x6c, 0xc3, 0x4b, 0xe2, 0x45, 0x38, 0x5d, 0x19, 0x91, 0xd3, 0x57, 0xe4, 0xe4, 0x36, 0x1c, 0x5e,
+	0xdc, 0x4e, 0x6f, 0xf1, 0x33, 0xd9, 0xd3, 0x3d, 0x89, 0x91, 0xfb, 0x90, 0x6d, 0x89, 0x44, 0xda,
+	0x1c, 0x50, 0x8a, 0x78, 0x93, 0x99, 0x1a, 0x81, 0xe5, 0xc8, 0x64, 0xf0, 0xbd, 0x02, 0x13, 0xd5,
+	0xd1, 0xa7, 0xb4, 0x0e, 0x1d, 0x25, 0x86, 0xd1, 0x91, 0x06, 0xd9, 0x26, 0xa1, 0x98, 0x4f, 0xe4,
+	0x82, 0x53, 0x83, 0x75, 0x0f, 0x8f, 0xa7, 0x43, 0x3c, 0x8e, 0x20, 0xf9, 0x86, 0x9c, 0xfb, 0xa5,
+	0x0c, 0xbf, 0xc8, 0xf8, 0xb3, 0x98, 0xdb, 0x9e, 0x25, 0xb3, 0xc9, 0x62, 0x4a, 0x7f, 0x06, 0x93,
+	0xd5, 0xb8, 0x3b, 0xfc, 0x22, 0x03, 0xc1, 0x47, 0x05, 0xa6, 0xfe, 0xe9, 0x59, 0x94, 0x7c, 0xe1,
+	0xfa, 0xd5, 0x50, 0xfd, 0x8b, 0x30, 0x4e, 0xce, 0x5c, 0x72, 0x44, 0xc3, 0x20, 0x2e, 0x08, 0x71,
+	0x1b, 0xc8, 0xfa, 0x7d, 0x98, 0x0e, 0xe7, 0x27, 0xcb, 0xed, 0x6e, 0xa1, 0x12, 0x1a, 0x1b, 0xff,
+	0xc2, 0x19, 0xa7, 0xb7, 0xe6, 0x81, 0x75, 0xe9, 0x2f, 0x61, 0x3e, 0xbc, 0xe3, 0x73, 0x5c, 0xca,
+	0xfa, 0x73, 0x28, 0x45, 0x33, 0xb9, 0xc4, 0x0b, 0x5b, 0x84, 0xc2, 0xb6, 0x6d, 0xb1, 0xb7, 0x3f,
+	0xa4, 0xa0, 0x0d, 0x18, 0x0f, 0x0c, 0x65, 0xbc, 0xbb, 0x90, 0x39, 0xf2, 0x08, 0xa6, 0xa4, 0x36,
+	0xf4, 0x14, 0x4b, 0xbb, 0x3f, 0xfb, 0x50, 0xe8, 0x9d, 0xbd, 0xd0, 0x0d, 0x98, 0xda, 0x5d, 0xdb,
+	0x37, 0xf7, 0x8d, 0xbd, 0xbd, 0x4d, 0x73, 0x73, 0xcf, 0xd8, 0x5d, 0x3b, 0x30, 0x37, 0x5f, 0xec,
+	0xec, 0x14, 0xaf, 0xa0, 0x39, 0x98, 0x89, 0xa8, 0x1e, 0xef, 0xed, 0xee, 0x1b, 0x4f, 0xaa, 0xd5,
+	0x27, 0x1b, 0x45, 0x05, 0x2d, 0xc0, 0xfc, 0x00, 0x03, 0x73, 0x7d, 0xed, 0xe0, 0xf1, 0x56, 0x31,
+	0xb1, 0xfa, 0x2b, 0x40, 0xfe, 0x40, 0x26, 0xb6, 0x8b, 0x5d, 0xb4, 0x09, 0x19, 0x49, 0x0d, 0x68,
+	0xa6, 0x93, 0x71, 0xe4, 0x0b, 0x46, 0xbb, 0x19, 0xaf, 0x14, 0xd5, 0xeb, 0x57, 0xd0, 0x6b, 0xfe,
+	0xd9, 0xd3, 0xfb, 0xc5, 0x82, 0x16, 0xe2, 0x36, 0x45, 0x5e, 0xfd, 0x50, 0xdf, 0x3b, 0x90, 0x13,
+	0xbe, 0x19, 0xf2, 0x67, 0x63, 0x8c, 0x3b, 0x47, 0x4b, 0xbb, 0xd5, 0x4f, 0x1d, 0x78, 0xfb, 0x37,
+	0xff, 0x72, 0x0c, 0xcf, 0x44, 0x68, 0x31, 0x7e, 0x63, 0x34, 0xdb, 0xe1, 0x11, 0xfe, 0x05, 0x5a,
+	0x4c, 0x84, 0xe7, 0x8e, 0xf8, 0xb0, 0x1f, 0x39, 0xd0, 0x44, 0xf8, 0xf8, 0xb3, 0xbf, 0x06, 0x57,
+	0xd0, 0x47, 0x05, 0x4a, 0xfd, 0x06, 0x6e, 0xb4, 0xdc, 0xe3, 0x7c, 0xd0, 0x50, 0xae, 0x45, 0xd9,
+	0x45, 0xdf, 0xf8, 0xdf, 0xcf, 0x9f, 0xbe, 0x49, 0xfc, 0x1d, 0xfd, 0xad, 0xd2, 0xba, 0x7b, 0x48,
+	0x28, 0xbe, 0x5b, 0x69, 0x62, 0xd7, 0xaf, 0xbc, 0x13, 0x67, 0xe0, 0x7d, 0x85, 0x9d, 0x26, 0xbf,
+	0xf2, 0xae, 0x7d, 0x00, 0xdf, 0x57, 0x04, 0x1b, 0x3d, 0x6c, 0x60, 0x9f, 0x0d, 0xb8, 0xa6, 0xc7,
+	0x22, 0xa1, 0x26, 0x4c, 0xb2, 0xc1, 0x33, 0xd2, 0xe1, 0xa5, 0x4e, 0xc0, 0xc1, 0x03, 0xba, 0xb6,
+	0x3c, 0x82, 0x65, 0xd0, 0xed, 0x57, 0x30, 0x1e, 0x9a, 0x80, 0x50, 0xb9, 0xa7, 0x0b, 0x31, 0xb3,
+	0xa8, 0x36, 0x3f, 0xc0, 0x22, 0xf0, 0xfc, 0x02, 0x0a, 0xbd, 0xd7, 0x26, 0x9a, 0xeb, 0x6d, 0x6f,
+	0x64, 0x1c, 0xd1, 0xca, 0xfd, 0x0d, 0xba, 0xe1, 0x5c, 0x8d, 0x83, 0x73, 0x75, 0x30, 0x9c, 0xab,
+	0xf1, 0x60, 0xfb, 0xa0, 0x40, 0x31, 0xcc, 0x82, 0xa8, 0xb7, 0xbc, 0x38, 0xae, 0xd6, 0xf4, 0x41,
+	0x26, 0xd2, 0xfb, 0x1d, 0x8e, 0x87, 0x05, 0x74, 0x7b, 0x10, 0x1e, 0x1e, 0x36, 0x30, 0x65, 0x5c,
+	0xf9, 0x51, 0x01, 0x2d, 0xec, 0xa9, 0xeb, 0xed, 0xdf, 0xe9, 0x1f, 0x2f, 0x0a, 0x80, 0x51, 0x92,
+	0xab, 0xf0, 0xe4, 0x96, 0xd1, 0xe2, 0x88, 0x60, 0x45, 0x47, 0x90, 0x91, 0xac, 0x8d, 0xba, 0x86,
+	0x9c, 0x5e, 0xc6, 0xd7, 0x6e, 0xc4, 0x68, 0x64, 0xc0, 0xdb, 0x3c, 0xe0, 0xac, 0x3e, 0x13, 0x1f,
+	0xf0, 0xa1, 0x65, 0x5b, 0x74, 0xf5, 0xbb, 0x04, 0x14, 0xbb, 0xf8, 0x95, 0xdf, 0xaf, 0xe8, 0xc5,
+	0x25, 0x29, 0xa7, 0x0f, 0x13, 0xfc, 0xc1, 0x07, 0xcd, 0x80, 0x3c, 0x2f, 0x47, 0x22, 0xb7, 0xeb,
+	0x2c, 0xc4, 0x4e, 0x39, 0x5a, 0xb9, 0xbf, 0x41, 0xdb, 0xe7, 0xfa, 0x73, 0xb8, 0x71, 0xe4, 0x34,
+	0x57, 0xc4, 0xcf, 0xdd, 0x95, 0xde, 0x7f, 0xbe, 0xeb, 0x13, 0x5d, 0x8d, 0x5c, 0x73, 0xad, 0x7d,
+	0x26, 0xdc, 0x57, 0x5e, 0x6b, 0xc7, 0x16, 0x3d, 0x39, 0x3d, 0x5c, 0x39, 0x72, 0x9a, 0x15, 0xf9,
+	0x57, 0xb8, 0xbd, 0xf1, 0x30, 0xcd, 0x77, 0xde, 0xfb, 0x7d, 0x00, 0x0b, 0xe6, 0xad, 0x0d, 0x61,
+	0x16, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1108,6 +1914,18 @@ type TrillianMapClient interface {
 	GetLeavesByRevisionNoProof(ctx context.Context, in *GetMapLeavesByRevisionRequest, opts ...grpc.CallOption) (*MapLeaves, error)
 	// GetLastInRangeByRevision returns the last leaf in a requested range.
 	GetLastInRangeByRevision(ctx context.Context, in *GetLastInRangeByRevisionRequest, opts ...grpc.CallOption) (*MapLeaf, error)
+	// ListLeavesByRevision returns a page of the leaves in the map at a
+	// revision, without inclusion proofs, in ascending order of index. It can
+	// be used to walk through the whole map, or through a range of indexes.
+	ListLeavesByRevision(ctx context.Context, in *ListMapLeavesByRevisionRequest, opts ...grpc.CallOption) (*ListMapLeavesByRevisionResponse, error)
+	// GetRevisionDiff returns a page of the indexes of the leaves which were
+	// set between two map revisions, in ascending order of index, optionally
+	// with the leaves at both revisions and their inclusion proofs.
+	GetRevisionDiff(ctx context.Context, in *GetRevisionDiffRequest, opts ...grpc.CallOption) (*GetRevisionDiffResponse, error)
+	// GetLeafHistory returns a page of the values which a leaf had in a range
+	// of map revisions, each with its inclusion proof and the map root of its
+	// revision.
+	GetLeafHistory(ctx context.Context, in *GetLeafHistoryRequest, opts ...grpc.CallOption) (*GetLeafHistoryResponse, error)
 	// SetLeaves sets the values for the provided leaves, and returns the new map
 	// root if successful. Note that if a SetLeaves request fails for a
 	// server-side reason (i.e. not an invalid request), the API user is required
@@ -1180,6 +1998,33 @@ func (c *trillianMapClient) GetLastInRangeByRevision(ctx context.Context, in *Ge
 	return out, nil
 }
 
+func (c *trillianMapClient) ListLeavesByRevision(ctx context.Context, in *ListMapLeavesByRevisionRequest, opts ...grpc.CallOption) (*ListMapLeavesByRevisionResponse, error) {
+	out := new(ListMapLeavesByRevisionResponse)
+	err := c.cc.Invoke(ctx, "/trillian.TrillianMap/ListLeavesByRevision", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trillianMapClient) GetRevisionDiff(ctx context.Context, in *GetRevisionDiffRequest, opts ...grpc.CallOption) (*GetRevisionDiffResponse, error) {
+	out := new(GetRevisionDiffResponse)
+	err := c.cc.Invoke(ctx, "/trillian.TrillianMap/GetRevisionDiff", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trillianMapClient) GetLeafHistory(ctx context.Context, in *GetLeafHistoryRequest, opts ...grpc.CallOption) (*GetLeafHistoryResponse, error) {
+	out := new(GetLeafHistoryResponse)
+	err := c.cc.Invoke(ctx, "/trillian.TrillianMap/GetLeafHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *trillianMapClient) SetLeaves(ctx context.Context, in *SetMapLeavesRequest, opts ...grpc.CallOption) (*SetMapLeavesResponse, error) {
 	out := new(SetMapLeavesResponse)
 	err := c.cc.Invoke(ctx, "/trillian.TrillianMap/SetLeaves", in, out, opts...)
@@ -1230,6 +2075,18 @@ type TrillianMapServer interface {
 	GetLeavesByRevisionNoProof(context.Context, *GetMapLeavesByRevisionRequest) (*MapLeaves, error)
 	// GetLastInRangeByRevision returns the last leaf in a requested range.
 	GetLastInRangeByRevision(context.Context, *GetLastInRangeByRevisionRequest) (*MapLeaf, error)
+	// ListLeavesByRevision returns a page of the leaves in the map at a
+	// revision, without inclusion proofs, in ascending order of index. It can
+	// be used to walk through the whole map, or through a range of indexes.
+	ListLeavesByRevision(context.Context, *ListMapLeavesByRevisionRequest) (*ListMapLeavesByRevisionResponse, error)
+	// GetRevisionDiff returns a page of the indexes of the leaves which were
+	// set between two map revisions, in ascending order of index, optionally
+	// with the leaves at both revisions and their inclusion proofs.
+	GetRevisionDiff(context.Context, *GetRevisionDiffRequest) (*GetRevisionDiffResponse, error)
+	// GetLeafHistory returns a page of the values which a leaf had in a range
+	// of map revisions, each with its inclusion proof and the map root of its
+	// revision.
+	GetLeafHistory(context.Context, *GetLeafHistoryRequest) (*GetLeafHistoryResponse, error)
 	// SetLeaves sets the values for the provided leaves, and returns the new map
 	// root if successful. Note that if a SetLeaves request fails for a
 	// server-side reason (i.e. not an invalid request), the API user is required
@@ -1262,6 +2119,15 @@ func (*UnimplementedTrillianMapServer) GetLeavesByRevisionNoProof(ctx context.Co
 func (*UnimplementedTrillianMapServer) GetLastInRangeByRevision(ctx context.Context, req *GetLastInRangeByRevisionRequest) (*MapLeaf, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetLastInRangeByRevision not implemented")
 }
+func (*UnimplementedTrillianMapServer) ListLeavesByRevision(ctx context.Context, req *ListMapLeavesByRevisionRequest) (*ListMapLeavesByRevisionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLeavesByRevision not implemented")
+}
+func (*UnimplementedTrillianMapServer) GetRevisionDiff(ctx context.Context, req *GetRevisionDiffRequest) (*GetRevisionDiffResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRevisionDiff not implemented")
+}
+func (*UnimplementedTrillianMapServer) GetLeafHistory(ctx context.Context, req *GetLeafHistoryRequest) (*GetLeafHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLeafHistory not implemented")
+}
 func (*UnimplementedTrillianMapServer) SetLeaves(ctx context.Context, req *SetMapLeavesRequest) (*SetMapLeavesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetLeaves not implemented")
 }
@@ -1387,6 +2253,60 @@ func _TrillianMap_GetLastInRangeByRevision_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TrillianMap_ListLeavesByRevision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMapLeavesByRevisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrillianMapServer).ListLeavesByRevision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trillian.TrillianMap/ListLeavesByRevision",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrillianMapServer).ListLeavesByRevision(ctx, req.(*ListMapLeavesByRevisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrillianMap_GetRevisionDiff_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRevisionDiffRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrillianMapServer).GetRevisionDiff(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trillian.TrillianMap/GetRevisionDiff",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrillianMapServer).GetRevisionDiff(ctx, req.(*GetRevisionDiffRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrillianMap_GetLeafHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLeafHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrillianMapServer).GetLeafHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trillian.TrillianMap/GetLeafHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrillianMapServer).GetLeafHistory(ctx, req.(*GetLeafHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TrillianMap_SetLeaves_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SetMapLeavesRequest)
 	if err := dec(in); err != nil {
@@ -1487,6 +2407,18 @@ var _TrillianMap_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetLastInRangeByRevision",
 			Handler:    _TrillianMap_GetLastInRangeByRevision_Handler,
 		},
+		{
+			MethodName: "ListLeavesByRevision",
+			Handler:    _TrillianMap_ListLeavesByRevision_Handler,
+		},
+		{
+			MethodName: "GetRevisionDiff",
+			Handler:    _TrillianMap_GetRevisionDiff_Handler,
+		},
+		{
+			MethodName: "GetLeafHistory",
+			Handler:    _TrillianMap_GetLeafHistory_Handler,
+		},
 		{
 			MethodName: "SetLeaves",
 			Handler:    _TrillianMap_SetLeaves_Handler,
@@ -1515,6 +2447,9 @@ type TrillianMapWriteClient interface {
 	// GetLeavesByRevision returns the requested map leaves without inclusion proofs.
 	// This API is designed for internal use where verification is not needed.
 	GetLeavesByRevision(ctx context.Context, in *GetMapLeavesByRevisionRequest, opts ...grpc.CallOption) (*MapLeaves, error)
+	// ListLeavesByRevision returns a page of the leaves in the map at a
+	// revision, in ascending order of index.
+	ListLeavesByRevision(ctx context.Context, in *ListMapLeavesByRevisionRequest, opts ...grpc.CallOption) (*ListMapLeavesByRevisionResponse, error)
 	// WriteLeaves sets the values for the provided leaves, and returns the new map
 	// revision if successful.
 	WriteLeaves(ctx context.Context, in *WriteMapLeavesRequest, opts ...grpc.CallOption) (*WriteMapLeavesResponse, error)
@@ -1537,6 +2472,15 @@ func (c *trillianMapWriteClient) GetLeavesByRevision(ctx context.Context, in *Ge
 	return out, nil
 }
 
+func (c *trillianMapWriteClient) ListLeavesByRevision(ctx context.Context, in *ListMapLeavesByRevisionRequest, opts ...grpc.CallOption) (*ListMapLeavesByRevisionResponse, error) {
+	out := new(ListMapLeavesByRevisionResponse)
+	err := c.cc.Invoke(ctx, "/trillian.TrillianMapWrite/ListLeavesByRevision", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *trillianMapWriteClient) WriteLeaves(ctx context.Context, in *WriteMapLeavesRequest, opts ...grpc.CallOption) (*WriteMapLeavesResponse, error) {
 	out := new(WriteMapLeavesResponse)
 	err := c.cc.Invoke(ctx, "/trillian.TrillianMapWrite/WriteLeaves", in, out, opts...)
@@ -1551,6 +2495,9 @@ type TrillianMapWriteServer interface {
 	// GetLeavesByRevision returns the requested map leaves without inclusion proofs.
 	// This API is designed for internal use where verification is not needed.
 	GetLeavesByRevision(context.Context, *GetMapLeavesByRevisionRequest) (*MapLeaves, error)
+	// ListLeavesByRevision returns a page of the leaves in the map at a
+	// revision, in ascending order of index.
+	ListLeavesByRevision(context.Context, *ListMapLeavesByRevisionRequest) (*ListMapLeavesByRevisionResponse, error)
 	// WriteLeaves sets the values for the provided leaves, and returns the new map
 	// revision if successful.
 	WriteLeaves(context.Context, *WriteMapLeavesRequest) (*WriteMapLeavesResponse, error)
@@ -1563,6 +2510,9 @@ type UnimplementedTrillianMapWriteServer struct {
 func (*UnimplementedTrillianMapWriteServer) GetLeavesByRevision(ctx context.Context, req *GetMapLeavesByRevisionRequest) (*MapLeaves, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetLeavesByRevision not implemented")
 }
+func (*UnimplementedTrillianMapWriteServer) ListLeavesByRevision(ctx context.Context, req *ListMapLeavesByRevisionRequest) (*ListMapLeavesByRevisionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLeavesByRevision not implemented")
+}
 func (*UnimplementedTrillianMapWriteServer) WriteLeaves(ctx context.Context, req *WriteMapLeavesRequest) (*WriteMapLeavesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method WriteLeaves not implemented")
 }
@@ -1589,6 +2539,24 @@ func _TrillianMapWrite_GetLeavesByRevision_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TrillianMapWrite_ListLeavesByRevision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMapLeavesByRevisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrillianMapWriteServer).ListLeavesByRevision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trillian.TrillianMapWrite/ListLeavesByRevision",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrillianMapWriteServer).ListLeavesByRevision(ctx, req.(*ListMapLeavesByRevisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TrillianMapWrite_WriteLeaves_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(WriteMapLeavesRequest)
 	if err := dec(in); err != nil {
@@ -1615,6 +2583,10 @@ var _TrillianMapWrite_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetLeavesByRevision",
 			Handler:    _TrillianMapWrite_GetLeavesByRevision_Handler,
 		},
+		{
+			MethodName: "ListLeavesByRevision",
+			Handler:    _TrillianMapWrite_ListLeavesByRevision_Handler,
+		},
 		{
 			MethodName: "WriteLeaves",
 			Handler:    _TrillianMapWrite_WriteLeaves_Handler,
@@ -0,0 +1,380 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: trillian_witness_api.proto
+
+package trillian
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type GetLatestCosignedLogRootRequest struct {
+	LogId                int64    `protobuf:"varint,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetLatestCosignedLogRootRequest) Reset()         { *m = GetLatestCosignedLogRootRequest{} }
+func (m *GetLatestCosignedLogRootRequest) String() string { return proto.CompactTextString(m) }
+func (*GetLatestCosignedLogRootRequest) ProtoMessage()    {}
+func (*GetLatestCosignedLogRootRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a269304f39ef0e67, []int{0}
+}
+
+func (m *GetLatestCosignedLogRootRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetLatestCosignedLogRootRequest.Unmarshal(m, b)
+}
+func (m *GetLatestCosignedLogRootRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetLatestCosignedLogRootRequest.Marshal(b, m, deterministic)
+}
+func (m *GetLatestCosignedLogRootRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetLatestCosignedLogRootRequest.Merge(m, src)
+}
+func (m *GetLatestCosignedLogRootRequest) XXX_Size() int {
+	return xxx_messageInfo_GetLatestCosignedLogRootRequest.Size(m)
+}
+func (m *GetLatestCosignedLogRootRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetLatestCosignedLogRootRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetLatestCosignedLogRootRequest proto.InternalMessageInfo
+
+func (m *GetLatestCosignedLogRootRequest) GetLogId() int64 {
+	if m != nil {
+		return m.LogId
+	}
+	return 0
+}
+
+type GetLatestCosignedLogRootResponse struct {
+	// signed_log_root is unset if the witness hasn't cosigned any root of the
+	// Log yet.
+	SignedLogRoot        *SignedLogRoot      `protobuf:"bytes,1,opt,name=signed_log_root,json=signedLogRoot,proto3" json:"signed_log_root,omitempty"`
+	Cosignature          *LogRootCosignature `protobuf:"bytes,2,opt,name=cosignature,proto3" json:"cosignature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *GetLatestCosignedLogRootResponse) Reset()         { *m = GetLatestCosignedLogRootResponse{} }
+func (m *GetLatestCosignedLogRootResponse) String() string { return proto.CompactTextString(m) }
+func (*GetLatestCosignedLogRootResponse) ProtoMessage()    {}
+func (*GetLatestCosignedLogRootResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a269304f39ef0e67, []int{1}
+}
+
+func (m *GetLatestCosignedLogRootResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetLatestCosignedLogRootResponse.Unmarshal(m, b)
+}
+func (m *GetLatestCosignedLogRootResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetLatestCosignedLogRootResponse.Marshal(b, m, deterministic)
+}
+func (m *GetLatestCosignedLogRootResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetLatestCosignedLogRootResponse.Merge(m, src)
+}
+func (m *GetLatestCosignedLogRootResponse) XXX_Size() int {
+	return xxx_messageInfo_GetLatestCosignedLogRootResponse.Size(m)
+}
+func (m *GetLatestCosignedLogRootResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetLatestCosignedLogRootResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetLatestCosignedLogRootResponse proto.InternalMessageInfo
+
+func (m *GetLatestCosignedLogRootResponse) GetSignedLogRoot() *SignedLogRoot {
+	if m != nil {
+		return m.SignedLogRoot
+	}
+	return nil
+}
+
+func (m *GetLatestCosignedLogRootResponse) GetCosignature() *LogRootCosignature {
+	if m != nil {
+		return m.Cosignature
+	}
+	return nil
+}
+
+type AddLogRootRequest struct {
+	LogId         int64          `protobuf:"varint,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	SignedLogRoot *SignedLogRoot `protobuf:"bytes,2,opt,name=signed_log_root,json=signedLogRoot,proto3" json:"signed_log_root,omitempty"`
+	// proof is a consistency proof from the latest root cosigned by the witness
+	// (see GetLatestCosignedLogRoot) to signed_log_root. It's not needed if the
+	// witness hasn't cosigned any root of the Log yet, or if that root has the
+	// same size as signed_log_root.
+	Proof                *Proof   `protobuf:"bytes,3,opt,name=proof,proto3" json:"proof,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddLogRootRequest) Reset()         { *m = AddLogRootRequest{} }
+func (m *AddLogRootRequest) String() string { return proto.CompactTextString(m) }
+func (*AddLogRootRequest) ProtoMessage()    {}
+func (*AddLogRootRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a269304f39ef0e67, []int{2}
+}
+
+func (m *AddLogRootRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddLogRootRequest.Unmarshal(m, b)
+}
+func (m *AddLogRootRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddLogRootRequest.Marshal(b, m, deterministic)
+}
+func (m *AddLogRootRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddLogRootRequest.Merge(m, src)
+}
+func (m *AddLogRootRequest) XXX_Size() int {
+	return xxx_messageInfo_AddLogRootRequest.Size(m)
+}
+func (m *AddLogRootRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddLogRootRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddLogRootRequest proto.InternalMessageInfo
+
+func (m *AddLogRootRequest) GetLogId() int64 {
+	if m != nil {
+		return m.LogId
+	}
+	return 0
+}
+
+func (m *AddLogRootRequest) GetSignedLogRoot() *SignedLogRoot {
+	if m != nil {
+		return m.SignedLogRoot
+	}
+	return nil
+}
+
+func (m *AddLogRootRequest) GetProof() *Proof {
+	if m != nil {
+		return m.Proof
+	}
+	return nil
+}
+
+type AddLogRootResponse struct {
+	Cosignature          *LogRootCosignature `protobuf:"bytes,1,opt,name=cosignature,proto3" json:"cosignature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *AddLogRootResponse) Reset()         { *m = AddLogRootResponse{} }
+func (m *AddLogRootResponse) String() string { return proto.CompactTextString(m) }
+func (*AddLogRootResponse) ProtoMessage()    {}
+func (*AddLogRootResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a269304f39ef0e67, []int{3}
+}
+
+func (m *AddLogRootResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddLogRootResponse.Unmarshal(m, b)
+}
+func (m *AddLogRootResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddLogRootResponse.Marshal(b, m, deterministic)
+}
+func (m *AddLogRootResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddLogRootResponse.Merge(m, src)
+}
+func (m *AddLogRootResponse) XXX_Size() int {
+	return xxx_messageInfo_AddLogRootResponse.Size(m)
+}
+func (m *AddLogRootResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddLogRootResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddLogRootResponse proto.InternalMessageInfo
+
+func (m *AddLogRootResponse) GetCosignature() *LogRootCosignature {
+	if m != nil {
+		return m.Cosignature
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*GetLatestCosignedLogRootRequest)(nil), "trillian.GetLatestCosignedLogRootRequest")
+	proto.RegisterType((*GetLatestCosignedLogRootResponse)(nil), "trillian.GetLatestCosignedLogRootResponse")
+	proto.RegisterType((*AddLogRootRequest)(nil), "trillian.AddLogRootRequest")
+	proto.RegisterType((*AddLogRootResponse)(nil), "trillian.AddLogRootResponse")
+}
+
+func init() { proto.RegisterFile("trillian_witness_api.proto", fileDescriptor_a269304f39ef0e67) }
+
+var fileDescriptor_a269304f39ef0e67 = []byte{
+	// 340 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x92, 0xcd, 0x4a, 0xc3, 0x40,
+	0x14, 0x85, 0x9d, 0x96, 0x16, 0xb9, 0x45, 0x8b, 0x03, 0xda, 0x18, 0x0b, 0x96, 0x80, 0xa0, 0x2e,
+	0x52, 0xa8, 0x1b, 0x57, 0x4a, 0xdb, 0x85, 0x14, 0xba, 0x28, 0xb1, 0x20, 0xb8, 0x29, 0x69, 0x33,
+	0x8e, 0x03, 0x69, 0x6e, 0xcc, 0x4c, 0xf1, 0x3d, 0x7c, 0x05, 0x9f, 0xc8, 0x37, 0x92, 0x66, 0x9a,
+	0x8e, 0xe9, 0x0f, 0xb6, 0xbb, 0x90, 0x73, 0xbe, 0x93, 0x7b, 0x72, 0x2f, 0xd8, 0x2a, 0x11, 0x61,
+	0x28, 0xfc, 0x68, 0xf4, 0x29, 0x54, 0xc4, 0xa4, 0x1c, 0xf9, 0xb1, 0x70, 0xe3, 0x04, 0x15, 0xd2,
+	0xc3, 0x4c, 0xb3, 0x8f, 0xb3, 0x27, 0xad, 0xd8, 0x67, 0x4b, 0x2a, 0x44, 0x6e, 0x08, 0xe7, 0x1e,
+	0x2e, 0x9f, 0x98, 0xea, 0xfb, 0x8a, 0x49, 0xd5, 0x45, 0x29, 0x78, 0xc4, 0x82, 0x3e, 0x72, 0x0f,
+	0x51, 0x79, 0xec, 0x63, 0xc6, 0xa4, 0xa2, 0xa7, 0x50, 0x9e, 0x33, 0x22, 0xb0, 0x48, 0x83, 0x5c,
+	0x17, 0xbd, 0x52, 0x88, 0xbc, 0x17, 0x38, 0xdf, 0x04, 0x1a, 0xdb, 0x51, 0x19, 0x63, 0x24, 0x19,
+	0x7d, 0x84, 0xaa, 0x16, 0xd2, 0xcf, 0x26, 0x88, 0x2a, 0x0d, 0xa9, 0xb4, 0x6a, 0xee, 0x72, 0xc0,
+	0xe7, 0x1c, 0x79, 0x94, 0x0b, 0xa2, 0x0f, 0x50, 0x99, 0xa4, 0xd9, 0xbe, 0x9a, 0x25, 0xcc, 0x2a,
+	0xa4, 0x70, 0xdd, 0xc0, 0x0b, 0x5f, 0xd7, 0x78, 0xbc, 0xbf, 0x80, 0xf3, 0x45, 0xe0, 0xa4, 0x1d,
+	0xec, 0x56, 0x69, 0xd3, 0xb4, 0x85, 0xbd, 0xa6, 0xbd, 0x82, 0x52, 0x9c, 0x20, 0xbe, 0x59, 0xc5,
+	0x14, 0xab, 0x1a, 0x6c, 0x30, 0x7f, 0xed, 0x69, 0xd5, 0x19, 0x02, 0x6d, 0x07, 0x6b, 0xff, 0x6a,
+	0xa5, 0x2a, 0xd9, 0xb3, 0x6a, 0xeb, 0x87, 0x40, 0x75, 0xb8, 0x30, 0xbf, 0xe8, 0xd3, 0xa0, 0x12,
+	0xac, 0x6d, 0x3b, 0xa2, 0x37, 0x26, 0xfa, 0x9f, 0x13, 0xb0, 0x6f, 0x77, 0xb1, 0xea, 0x1a, 0xce,
+	0x01, 0xed, 0x01, 0x98, 0x7a, 0xf4, 0xc2, 0xb0, 0x6b, 0x8b, 0xb0, 0xeb, 0x9b, 0xc5, 0x2c, 0xaa,
+	0xe3, 0xc1, 0xf9, 0x04, 0xa7, 0x2e, 0x47, 0xe4, 0x21, 0x73, 0xf3, 0x37, 0xdd, 0xa9, 0xad, 0xb4,
+	0x6d, 0xc7, 0x62, 0x30, 0x17, 0x06, 0xe4, 0xd5, 0xe6, 0x42, 0xbd, 0xcf, 0xc6, 0xee, 0x04, 0xa7,
+	0x4d, 0x0d, 0x37, 0x33, 0x78, 0x5c, 0x4e, 0xe9, 0xbb, 0xdf, 0x01, 0x00, 0x2d, 0xa8, 0x9f, 0xa6,
+	0x49, 0x03, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// TrillianWitnessClient is the client API for TrillianWitness service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type TrillianWitnessClient interface {
+	// GetLatestCosignedLogRoot returns the latest root of a Log cosigned by the
+	// witness, together with the cosignature.
+	GetLatestCosignedLogRoot(ctx context.Context, in *GetLatestCosignedLogRootRequest, opts ...grpc.CallOption) (*GetLatestCosignedLogRootResponse, error)
+	// AddLogRoot verifies a root of a Log, and its consistency with the latest
+	// root of the Log cosigned by the witness. If successful, the root becomes
+	// the latest one, and its cosignature is returned.
+	//
+	// Returns FAILED_PRECONDITION if the root isn't consistent with the latest
+	// cosigned root according to the provided proof, which may be because the
+	// witness has cosigned a later root in the meantime.
+	AddLogRoot(ctx context.Context, in *AddLogRootRequest, opts ...grpc.CallOption) (*AddLogRootResponse, error)
+}
+
+type trillianWitnessClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewTrillianWitnessClient(cc *grpc.ClientConn) TrillianWitnessClient {
+	return &trillianWitnessClient{cc}
+}
+
+func (c *trillianWitnessClient) GetLatestCosignedLogRoot(ctx context.Context, in *GetLatestCosignedLogRootRequest, opts ...grpc.CallOption) (*GetLatestCosignedLogRootResponse, error) {
+	out := new(GetLatestCosignedLogRootResponse)
+	err := c.cc.Invoke(ctx, "/trillian.TrillianWitness/GetLatestCosignedLogRoot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trillianWitnessClient) AddLogRoot(ctx context.Context, in *AddLogRootRequest, opts ...grpc.CallOption) (*AddLogRootResponse, error) {
+	out := new(AddLogRootResponse)
+	err := c.cc.Invoke(ctx, "/trillian.TrillianWitness/AddLogRoot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TrillianWitnessServer is the server API for TrillianWitness service.
+type TrillianWitnessServer interface {
+	// GetLatestCosignedLogRoot returns the latest root of a Log cosigned by the
+	// witness, together with the cosignature.
+	GetLatestCosignedLogRoot(context.Context, *GetLatestCosignedLogRootRequest) (*GetLatestCosignedLogRootResponse, error)
+	// AddLogRoot verifies a root of a Log, and its consistency with the latest
+	// root of the Log cosigned by the witness. If successful, the root becomes
+	// the latest one, and its cosignature is returned.
+	//
+	// Returns FAILED_PRECONDITION if the root isn't consistent with the latest
+	// cosigned root according to the provided proof, which may be because the
+	// witness has cosigned a later root in the meantime.
+	AddLogRoot(context.Context, *AddLogRootRequest) (*AddLogRootResponse, error)
+}
+
+// UnimplementedTrillianWitnessServer can be embedded to have forward compatible implementations.
+type UnimplementedTrillianWitnessServer struct {
+}
+
+func (*UnimplementedTrillianWitnessServer) GetLatestCosignedLogRoot(ctx context.Context, req *GetLatestCosignedLogRootRequest) (*GetLatestCosignedLogRootResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLatestCosignedLogRoot not implemented")
+}
+func (*UnimplementedTrillianWitnessServer) AddLogRoot(ctx context.Context, req *AddLogRootRequest) (*AddLogRootResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddLogRoot not implemented")
+}
+
+func RegisterTrillianWitnessServer(s *grpc.Server, srv TrillianWitnessServer) {
+	s.RegisterService(&_TrillianWitness_serviceDesc, srv)
+}
+
+func _TrillianWitness_GetLatestCosignedLogRoot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLatestCosignedLogRootRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrillianWitnessServer).GetLatestCosignedLogRoot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trillian.TrillianWitness/GetLatestCosignedLogRoot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrillianWitnessServer).GetLatestCosignedLogRoot(ctx, req.(*GetLatestCosignedLogRootRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrillianWitness_AddLogRoot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddLogRootRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrillianWitnessServer).AddLogRoot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trillian.TrillianWitness/AddLogRoot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrillianWitnessServer).AddLogRoot(ctx, req.(*AddLogRootRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _TrillianWitness_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "trillian.TrillianWitness",
+	HandlerType: (*TrillianWitnessServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetLatestCosignedLogRoot",
+			Handler:    _TrillianWitness_GetLatestCosignedLogRoot_Handler,
+		},
+		{
+			MethodName: "AddLogRoot",
+			Handler:    _TrillianWitness_AddLogRoot_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "trillian_witness_api.proto",
+}
@@ -0,0 +1,159 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Extension lines holding the fields of LogRootV1 which checkpoints don't
+// have otherwise.
+const (
+	checkpointTimestamp = "timestamp "
+	checkpointRevision  = "revision "
+	checkpointMetadata  = "metadata "
+)
+
+// MarshalCheckpoint returns the LOG_ROOT_FORMAT_CHECKPOINT serialization of
+// the log root, i.e. the text of a signed note made of the following lines:
+//
+//	<origin>
+//	<tree size, in decimal>
+//	<root hash, in standard base64>
+//	timestamp <timestamp_nanos, in decimal>
+//	revision <revision, in decimal>
+//	metadata <metadata, in standard base64>
+//
+// The last three lines are extension lines, and the metadata one is omitted
+// if the metadata is empty.
+func (l *LogRootV1) MarshalCheckpoint(origin string) ([]byte, error) {
+	if err := ValidateCheckpointOrigin(origin); err != nil {
+		return nil, err
+	}
+	if len(l.RootHash) > 128 {
+		return nil, fmt.Errorf("root hash too long: %d bytes", len(l.RootHash))
+	}
+	if len(l.Metadata) > 65535 {
+		return nil, fmt.Errorf("metadata too long: %d bytes", len(l.Metadata))
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s\n%d\n%s\n", origin, l.TreeSize, base64.StdEncoding.EncodeToString(l.RootHash))
+	fmt.Fprintf(&b, "%s%d\n%s%d\n", checkpointTimestamp, l.TimestampNanos, checkpointRevision, l.Revision)
+	if len(l.Metadata) > 0 {
+		fmt.Fprintf(&b, "%s%s\n", checkpointMetadata, base64.StdEncoding.EncodeToString(l.Metadata))
+	}
+	return b.Bytes(), nil
+}
+
+// ParseCheckpoint parses the text of a checkpoint, and returns its origin and
+// the log root it holds. Extension lines other than the ones written by
+// MarshalCheckpoint are ignored; the fields of the log root which they would
+// hold are left zero if they are missing.
+func ParseCheckpoint(text []byte) (string, *LogRootV1, error) {
+	if !utf8.Valid(text) {
+		return "", nil, errors.New("checkpoint is not valid UTF-8")
+	}
+	if len(text) == 0 || text[len(text)-1] != '\n' {
+		return "", nil, errors.New("checkpoint does not end with a newline")
+	}
+	lines := strings.Split(string(text[:len(text)-1]), "\n")
+	if len(lines) < 3 {
+		return "", nil, fmt.Errorf("checkpoint has %d lines, want at least 3", len(lines))
+	}
+	origin := lines[0]
+	if err := ValidateCheckpointOrigin(origin); err != nil {
+		return "", nil, err
+	}
+
+	var root LogRootV1
+	var err error
+	if root.TreeSize, err = parseCheckpointUint(lines[1]); err != nil {
+		return "", nil, fmt.Errorf("invalid checkpoint tree size: %v", err)
+	}
+	if root.RootHash, err = base64.StdEncoding.DecodeString(lines[2]); err != nil || len(root.RootHash) > 128 {
+		return "", nil, fmt.Errorf("invalid checkpoint root hash %q", lines[2])
+	}
+
+	seen := make(map[string]bool)
+	for _, line := range lines[3:] {
+		if line == "" {
+			return "", nil, errors.New("empty checkpoint extension line")
+		}
+		var prefix string
+		for _, p := range []string{checkpointTimestamp, checkpointRevision, checkpointMetadata} {
+			if strings.HasPrefix(line, p) {
+				prefix = p
+			}
+		}
+		if prefix == "" {
+			continue
+		}
+		if seen[prefix] {
+			return "", nil, fmt.Errorf("duplicate checkpoint extension line %q", line)
+		}
+		seen[prefix] = true
+		value := line[len(prefix):]
+		switch prefix {
+		case checkpointTimestamp:
+			root.TimestampNanos, err = parseCheckpointUint(value)
+		case checkpointRevision:
+			root.Revision, err = parseCheckpointUint(value)
+		case checkpointMetadata:
+			if root.Metadata, err = base64.StdEncoding.DecodeString(value); err == nil && len(root.Metadata) > 65535 {
+				err = errors.New("metadata too long")
+			}
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid checkpoint extension line %q: %v", line, err)
+		}
+	}
+	return origin, &root, nil
+}
+
+// ValidateCheckpointOrigin returns an error if origin can't be the origin of
+// a checkpoint. The origin is also the name of the key in the signature lines
+// of checkpoint notes, so it must be non-empty, valid UTF-8, and free of
+// spaces, other Unicode white space and "+" characters.
+func ValidateCheckpointOrigin(origin string) error {
+	if origin == "" {
+		return errors.New("empty checkpoint origin")
+	}
+	if !utf8.ValidString(origin) || strings.IndexFunc(origin, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsControl(r) || r == '+'
+	}) >= 0 {
+		return fmt.Errorf("invalid checkpoint origin %q", origin)
+	}
+	return nil
+}
+
+// parseCheckpointUint parses a decimal integer written without sign or
+// leading zeros.
+func parseCheckpointUint(s string) (uint64, error) {
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if strconv.FormatUint(v, 10) != s {
+		return 0, fmt.Errorf("non-canonical integer %q", s)
+	}
+	return v, nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckpoint(t *testing.T) {
+	for _, tc := range []struct {
+		root LogRootV1
+		want string
+	}{
+		{
+			root: LogRootV1{TreeSize: 0, RootHash: []byte{}, TimestampNanos: 0, Revision: 0},
+			want: "example.com/log\n0\n\ntimestamp 0\nrevision 0\n",
+		},
+		{
+			root: LogRootV1{TreeSize: 42, RootHash: []byte("root"), TimestampNanos: 1000, Revision: 3, Metadata: []byte("meta")},
+			want: "example.com/log\n42\ncm9vdA==\ntimestamp 1000\nrevision 3\nmetadata bWV0YQ==\n",
+		},
+	} {
+		text, err := tc.root.MarshalCheckpoint("example.com/log")
+		if err != nil {
+			t.Fatalf("MarshalCheckpoint(): %v", err)
+		}
+		if got := string(text); got != tc.want {
+			t.Errorf("MarshalCheckpoint(): %q, want %q", got, tc.want)
+		}
+
+		origin, root, err := ParseCheckpoint(text)
+		if err != nil {
+			t.Fatalf("ParseCheckpoint(): %v", err)
+		}
+		if origin != "example.com/log" || !reflect.DeepEqual(*root, tc.root) {
+			t.Errorf("ParseCheckpoint(): %q, %+v, want %q, %+v", origin, root, "example.com/log", tc.root)
+		}
+
+		var unmarshalled LogRootV1
+		if err := unmarshalled.UnmarshalBinary(text); err != nil {
+			t.Fatalf("UnmarshalBinary(): %v", err)
+		}
+		if !reflect.DeepEqual(unmarshalled, tc.root) {
+			t.Errorf("UnmarshalBinary(): %+v, want %+v", unmarshalled, tc.root)
+		}
+	}
+}
+
+func TestParseCheckpointExtensions(t *testing.T) {
+	origin, root, err := ParseCheckpoint([]byte("origin\n5\ncm9vdA==\nsome other extension\nrevision 2\n"))
+	if err != nil {
+		t.Fatalf("ParseCheckpoint(): %v", err)
+	}
+	want := LogRootV1{TreeSize: 5, RootHash: []byte("root"), Revision: 2}
+	if origin != "origin" || !reflect.DeepEqual(*root, want) {
+		t.Errorf("ParseCheckpoint(): %q, %+v, want %q, %+v", origin, root, "origin", want)
+	}
+}
+
+func TestParseCheckpointErrors(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"origin\n5\ncm9vdA==",
+		"origin\n5\n",
+		"\n5\ncm9vdA==\n",
+		"an origin\n5\ncm9vdA==\n",
+		"origin+1\n5\ncm9vdA==\n",
+		"origin\n-5\ncm9vdA==\n",
+		"origin\n05\ncm9vdA==\n",
+		"origin\nfive\ncm9vdA==\n",
+		"origin\n5\nnot base64\n",
+		"origin\n5\n" + strings.Repeat("AAAA", 44) + "\n",
+		"origin\n5\ncm9vdA==\n\n",
+		"origin\n5\ncm9vdA==\nrevision x\n",
+		"origin\n5\ncm9vdA==\nrevision 1\nrevision 2\n",
+		"origin\n5\ncm9vdA==\ntimestamp -1\n",
+		"origin\n5\ncm9vdA==\nmetadata !\n",
+		"origin\n5\ncm9vdA==\n\xff\n",
+	} {
+		if _, root, err := ParseCheckpoint([]byte(text)); err == nil {
+			t.Errorf("ParseCheckpoint(%q): %+v, want error", text, root)
+		}
+	}
+}
+
+func TestMarshalCheckpointErrors(t *testing.T) {
+	for _, origin := range []string{"", "an origin", "origin\n", "origin+1"} {
+		if _, err := (&LogRootV1{}).MarshalCheckpoint(origin); err == nil {
+			t.Errorf("MarshalCheckpoint(%q): nil, want error", origin)
+		}
+	}
+	if _, err := (&LogRootV1{RootHash: make([]byte, 129)}).MarshalCheckpoint("origin"); err == nil {
+		t.Error("MarshalCheckpoint() with long root hash: nil, want error")
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/google/certificate-transparency-go/tls"
+
+	"github.com/google/trillian"
+)
+
+// CosignatureV1 holds the TLS-deserialization of the following structure
+// (described in RFC5246 section 4 notation):
+// struct {
+//   opaque witness_id<0..255>;
+//   uint64 log_id;
+//   opaque log_root<1..65535>;
+//   uint64 timestamp_nanos;
+// } CosignatureV1;
+//
+// LogRoot holds the log_root bytes of the cosigned SignedLogRoot.
+type CosignatureV1 struct {
+	WitnessID      []byte `tls:"minlen:0,maxlen:255"`
+	LogID          uint64
+	LogRoot        []byte `tls:"minlen:1,maxlen:65535"`
+	TimestampNanos uint64
+}
+
+// Cosignature holds the TLS-deserialization of the following structure
+// (described in RFC5246 section 4 notation):
+// enum { v1(512), (65535)} Version;
+// struct {
+//   Version version;
+//   select(version) {
+//     case v1: CosignatureV1;
+//   }
+// } Cosignature;
+type Cosignature struct {
+	Version tls.Enum       `tls:"size:2"`
+	V1      *CosignatureV1 `tls:"selector:Version,val:512"`
+}
+
+// NewCosignatureV1 returns the CosignatureV1 covered by the signature of
+// cosig, which is over logRoot.
+func NewCosignatureV1(logRoot []byte, cosig *trillian.LogRootCosignature) *CosignatureV1 {
+	return &CosignatureV1{
+		WitnessID:      []byte(cosig.WitnessId),
+		LogID:          uint64(cosig.LogId),
+		LogRoot:        logRoot,
+		TimestampNanos: uint64(cosig.TimestampNanos),
+	}
+}
+
+// UnmarshalBinary verifies that cosignatureBytes is a TLS serialized
+// Cosignature, has the COSIGNATURE_FORMAT_V1 tag, and populates the caller
+// with the deserialized *CosignatureV1.
+func (c *CosignatureV1) UnmarshalBinary(cosignatureBytes []byte) error {
+	if len(cosignatureBytes) < 3 {
+		return fmt.Errorf("cosignatureBytes too short")
+	}
+	if c == nil {
+		return fmt.Errorf("nil cosignature")
+	}
+	version := binary.BigEndian.Uint16(cosignatureBytes)
+	if version != uint16(trillian.CosignatureFormat_COSIGNATURE_FORMAT_V1) {
+		return fmt.Errorf("invalid Cosignature.Version: %v, want %v",
+			version, trillian.CosignatureFormat_COSIGNATURE_FORMAT_V1)
+	}
+
+	var cosignature Cosignature
+	if _, err := tls.Unmarshal(cosignatureBytes, &cosignature); err != nil {
+		return err
+	}
+
+	*c = *cosignature.V1
+	return nil
+}
+
+// MarshalBinary returns a canonical TLS serialization of Cosignature.
+func (c *CosignatureV1) MarshalBinary() ([]byte, error) {
+	return tls.Marshal(Cosignature{
+		Version: tls.Enum(trillian.CosignatureFormat_COSIGNATURE_FORMAT_V1),
+		V1:      c,
+	})
+}
@@ -0,0 +1,97 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/trillian"
+)
+
+func TestCosignature(t *testing.T) {
+	for _, c := range []*CosignatureV1{
+		{WitnessID: []byte("w1"), LogID: 1, LogRoot: []byte("root"), TimestampNanos: 12345},
+		{WitnessID: []byte{}, LogID: 3561657513447883733, LogRoot: MustMarshalLogRoot(&LogRootV1{})},
+	} {
+		b, err := c.MarshalBinary()
+		if err != nil {
+			t.Errorf("%v MarshalBinary(): %v", c, err)
+			continue
+		}
+		var got CosignatureV1
+		if err := got.UnmarshalBinary(b); err != nil {
+			t.Errorf("UnmarshalBinary(): %v", err)
+			continue
+		}
+		if !reflect.DeepEqual(&got, c) {
+			t.Errorf("serialize/parse round trip failed. got %#v, want %#v", got, c)
+		}
+	}
+}
+
+func TestUnmarshalCosignature(t *testing.T) {
+	for _, tc := range []struct {
+		cosignature []byte
+		wantErr     bool
+	}{
+		{cosignature: mustMarshalCosignature(&CosignatureV1{LogRoot: []byte("root")})},
+		{
+			// A log root isn't a cosignature.
+			cosignature: MustMarshalLogRoot(&LogRootV1{}),
+			wantErr:     true,
+		},
+		{
+			// Neither is an entry timestamp.
+			cosignature: mustMarshalEntryTimestamp(&EntryTimestampV1{}),
+			wantErr:     true,
+		},
+		{
+			// Correct type, but truncated.
+			cosignature: []byte{2, 0, 5, 5, 5, 5, 5, 5, 5, 5},
+			wantErr:     true,
+		},
+		{cosignature: []byte("foo"), wantErr: true},
+		{cosignature: nil, wantErr: true},
+	} {
+		var got CosignatureV1
+		err := got.UnmarshalBinary(tc.cosignature)
+		if got, want := err != nil, tc.wantErr; got != want {
+			t.Errorf("UnmarshalBinary(): %v, wantErr %v", err, want)
+		}
+	}
+
+	// Unmarshaling to a nil should throw an error.
+	var nilPtr *CosignatureV1
+	if err := nilPtr.UnmarshalBinary(mustMarshalCosignature(&CosignatureV1{LogRoot: []byte("root")})); err == nil {
+		t.Errorf("nil.UnmarshalBinary(): %v, want err", err)
+	}
+}
+
+func TestNewCosignatureV1(t *testing.T) {
+	cosig := &trillian.LogRootCosignature{WitnessId: "w", LogId: 10, TimestampNanos: 20}
+	want := &CosignatureV1{WitnessID: []byte("w"), LogID: 10, LogRoot: []byte("root"), TimestampNanos: 20}
+	if got := NewCosignatureV1([]byte("root"), cosig); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewCosignatureV1(): %#v, want %#v", got, want)
+	}
+}
+
+func mustMarshalCosignature(c *CosignatureV1) []byte {
+	b, err := c.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
@@ -0,0 +1,94 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/google/certificate-transparency-go/tls"
+
+	"github.com/google/trillian"
+)
+
+// EntryTimestampV1 holds the TLS-deserialization of the following structure
+// (described in RFC5246 section 4 notation):
+// struct {
+//   uint64 log_id;
+//   opaque leaf_identity_hash<0..65535>;
+//   uint64 timestamp_nanos;
+// } EntryTimestampV1;
+type EntryTimestampV1 struct {
+	LogID            uint64
+	LeafIdentityHash []byte `tls:"minlen:0,maxlen:65535"`
+	TimestampNanos   uint64
+}
+
+// EntryTimestamp holds the TLS-deserialization of the following structure
+// (described in RFC5246 section 4 notation):
+// enum { v1(256), (65535)} Version;
+// struct {
+//   Version version;
+//   select(version) {
+//     case v1: EntryTimestampV1;
+//   }
+// } EntryTimestamp;
+type EntryTimestamp struct {
+	Version tls.Enum          `tls:"size:2"`
+	V1      *EntryTimestampV1 `tls:"selector:Version,val:256"`
+}
+
+// NewEntryTimestampV1 returns the EntryTimestampV1 covered by the signature
+// of set.
+func NewEntryTimestampV1(set *trillian.SignedEntryTimestamp) *EntryTimestampV1 {
+	return &EntryTimestampV1{
+		LogID:            uint64(set.LogId),
+		LeafIdentityHash: set.LeafIdentityHash,
+		TimestampNanos:   uint64(set.TimestampNanos),
+	}
+}
+
+// UnmarshalBinary verifies that entryTimestampBytes is a TLS serialized
+// EntryTimestamp, has the ENTRY_TIMESTAMP_FORMAT_V1 tag, and populates the
+// caller with the deserialized *EntryTimestampV1.
+func (e *EntryTimestampV1) UnmarshalBinary(entryTimestampBytes []byte) error {
+	if len(entryTimestampBytes) < 3 {
+		return fmt.Errorf("entryTimestampBytes too short")
+	}
+	if e == nil {
+		return fmt.Errorf("nil entry timestamp")
+	}
+	version := binary.BigEndian.Uint16(entryTimestampBytes)
+	if version != uint16(trillian.EntryTimestampFormat_ENTRY_TIMESTAMP_FORMAT_V1) {
+		return fmt.Errorf("invalid EntryTimestamp.Version: %v, want %v",
+			version, trillian.EntryTimestampFormat_ENTRY_TIMESTAMP_FORMAT_V1)
+	}
+
+	var entryTimestamp EntryTimestamp
+	if _, err := tls.Unmarshal(entryTimestampBytes, &entryTimestamp); err != nil {
+		return err
+	}
+
+	*e = *entryTimestamp.V1
+	return nil
+}
+
+// MarshalBinary returns a canonical TLS serialization of EntryTimestamp.
+func (e *EntryTimestampV1) MarshalBinary() ([]byte, error) {
+	return tls.Marshal(EntryTimestamp{
+		Version: tls.Enum(trillian.EntryTimestampFormat_ENTRY_TIMESTAMP_FORMAT_V1),
+		V1:      e,
+	})
+}
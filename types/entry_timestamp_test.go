@@ -0,0 +1,100 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/trillian"
+)
+
+func TestEntryTimestamp(t *testing.T) {
+	for _, et := range []*EntryTimestampV1{
+		{LogID: 1, LeafIdentityHash: []byte("foo"), TimestampNanos: 12345},
+		{LogID: 3561657513447883733, LeafIdentityHash: []byte{}},
+	} {
+		b, err := et.MarshalBinary()
+		if err != nil {
+			t.Errorf("%v MarshalBinary(): %v", et, err)
+			continue
+		}
+		var got EntryTimestampV1
+		if err := got.UnmarshalBinary(b); err != nil {
+			t.Errorf("UnmarshalBinary(): %v", err)
+			continue
+		}
+		if !reflect.DeepEqual(&got, et) {
+			t.Errorf("serialize/parse round trip failed. got %#v, want %#v", got, et)
+		}
+	}
+}
+
+func TestUnmarshalEntryTimestamp(t *testing.T) {
+	for _, tc := range []struct {
+		entryTimestamp []byte
+		wantErr        bool
+	}{
+		{entryTimestamp: mustMarshalEntryTimestamp(&EntryTimestampV1{})},
+		{
+			// A log root isn't an entry timestamp.
+			entryTimestamp: MustMarshalLogRoot(&LogRootV1{}),
+			wantErr:        true,
+		},
+		{
+			// Correct type, but truncated.
+			entryTimestamp: []byte{1, 0, 5, 5, 5, 5, 5, 5, 5, 5},
+			wantErr:        true,
+		},
+		{entryTimestamp: []byte("foo"), wantErr: true},
+		{entryTimestamp: nil, wantErr: true},
+	} {
+		var got EntryTimestampV1
+		err := got.UnmarshalBinary(tc.entryTimestamp)
+		if got, want := err != nil, tc.wantErr; got != want {
+			t.Errorf("UnmarshalBinary(): %v, wantErr %v", err, want)
+		}
+	}
+
+	// Unmarshaling to a nil should throw an error.
+	var nilPtr *EntryTimestampV1
+	if err := nilPtr.UnmarshalBinary(mustMarshalEntryTimestamp(&EntryTimestampV1{})); err == nil {
+		t.Errorf("nil.UnmarshalBinary(): %v, want err", err)
+	}
+}
+
+func TestEntryTimestampIsNotLogRoot(t *testing.T) {
+	b := mustMarshalEntryTimestamp(&EntryTimestampV1{LogID: 1, LeafIdentityHash: make([]byte, 32)})
+	var root LogRootV1
+	if err := root.UnmarshalBinary(b); err == nil {
+		t.Errorf("LogRootV1.UnmarshalBinary(%x): %v, want err", b, err)
+	}
+}
+
+func TestNewEntryTimestampV1(t *testing.T) {
+	set := &trillian.SignedEntryTimestamp{LogId: 10, LeafIdentityHash: []byte("id"), TimestampNanos: 20}
+	want := &EntryTimestampV1{LogID: 10, LeafIdentityHash: []byte("id"), TimestampNanos: 20}
+	if got := NewEntryTimestampV1(set); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewEntryTimestampV1(): %#v, want %#v", got, want)
+	}
+}
+
+func mustMarshalEntryTimestamp(et *EntryTimestampV1) []byte {
+	b, err := et.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
@@ -58,7 +58,9 @@ type LogRoot struct {
 
 // UnmarshalBinary verifies that logRootBytes is a TLS serialized LogRoot, has
 // the LOG_ROOT_FORMAT_V1 tag, and populates the caller with the deserialized
-// *LogRootV1.
+// *LogRootV1. logRootBytes may also be a LOG_ROOT_FORMAT_CHECKPOINT
+// serialization, as returned by MarshalCheckpoint, which is told apart by its
+// first byte: the high byte of the TLS version is zero.
 func (l *LogRootV1) UnmarshalBinary(logRootBytes []byte) error {
 	if len(logRootBytes) < 3 {
 		return fmt.Errorf("logRootBytes too short")
@@ -66,6 +68,14 @@ func (l *LogRootV1) UnmarshalBinary(logRootBytes []byte) error {
 	if l == nil {
 		return fmt.Errorf("nil log root")
 	}
+	if logRootBytes[0] != 0 {
+		_, root, err := ParseCheckpoint(logRootBytes)
+		if err != nil {
+			return err
+		}
+		*l = *root
+		return nil
+	}
 	version := binary.BigEndian.Uint16(logRootBytes)
 	if version != uint16(trillian.LogRootFormat_LOG_ROOT_FORMAT_V1) {
 		return fmt.Errorf("invalid LogRoot.Version: %v, want %v",
@@ -81,6 +91,22 @@ func (l *LogRootV1) UnmarshalBinary(logRootBytes []byte) error {
 	return nil
 }
 
+// MarshalForConfig returns the serialization of the log root in the format set
+// by config: a checkpoint with the configured origin for
+// LOG_ROOT_FORMAT_CHECKPOINT, or the TLS serialization of MarshalBinary
+// otherwise. Both are deterministic, so storage which only keeps the fields of
+// signed log roots can put them back together with it.
+func (l *LogRootV1) MarshalForConfig(config *trillian.LogRootConfig) ([]byte, error) {
+	switch format := config.GetFormat(); format {
+	case trillian.LogRootFormat_LOG_ROOT_FORMAT_UNKNOWN, trillian.LogRootFormat_LOG_ROOT_FORMAT_V1:
+		return l.MarshalBinary()
+	case trillian.LogRootFormat_LOG_ROOT_FORMAT_CHECKPOINT:
+		return l.MarshalCheckpoint(config.GetOrigin())
+	default:
+		return nil, fmt.Errorf("unsupported log root format %v", format)
+	}
+}
+
 // MarshalBinary returns a canonical TLS serialization of LogRoot.
 func (l *LogRootV1) MarshalBinary() ([]byte, error) {
 	return tls.Marshal(LogRoot{
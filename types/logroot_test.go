@@ -15,10 +15,13 @@
 package types
 
 import (
+	"bytes"
 	"encoding"
 	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/google/trillian"
 )
 
 func TestLogRoot(t *testing.T) {
@@ -89,6 +92,45 @@ func TestUnmarshalLogRoot(t *testing.T) {
 	}
 }
 
+func TestMarshalForConfig(t *testing.T) {
+	root := &LogRootV1{TreeSize: 42, RootHash: []byte("root"), TimestampNanos: 1000, Revision: 3}
+	v1 := MustMarshalLogRoot(root)
+	checkpoint, err := root.MarshalCheckpoint("example.com/log")
+	if err != nil {
+		t.Fatalf("MarshalCheckpoint(): %v", err)
+	}
+	for _, tc := range []struct {
+		desc    string
+		config  *trillian.LogRootConfig
+		want    []byte
+		wantErr bool
+	}{
+		{desc: "nil", config: nil, want: v1},
+		{desc: "v1", config: &trillian.LogRootConfig{Format: trillian.LogRootFormat_LOG_ROOT_FORMAT_V1}, want: v1},
+		{
+			desc:   "checkpoint",
+			config: &trillian.LogRootConfig{Format: trillian.LogRootFormat_LOG_ROOT_FORMAT_CHECKPOINT, Origin: "example.com/log"},
+			want:   checkpoint,
+		},
+		{
+			desc:    "checkpointNoOrigin",
+			config:  &trillian.LogRootConfig{Format: trillian.LogRootFormat_LOG_ROOT_FORMAT_CHECKPOINT},
+			wantErr: true,
+		},
+		{desc: "unknownFormat", config: &trillian.LogRootConfig{Format: 99}, wantErr: true},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := root.MarshalForConfig(tc.config)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("MarshalForConfig(): %v, wantErr %v", err, tc.wantErr)
+			}
+			if !bytes.Equal(got, tc.want) {
+				t.Errorf("MarshalForConfig(): %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
 func MustMarshalLogRoot(root *LogRootV1) []byte {
 	b, err := root.MarshalBinary()
 	if err != nil {
@@ -0,0 +1,90 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TileDataLevel is the Level of tiles holding log entries rather than hashes.
+const TileDataLevel = -1
+
+// Tile identifies a fixed-height tile of a log's Merkle tree. A hash tile
+// holds the hashes of Width consecutive nodes at tree level Level*Height,
+// starting from node Index*2^Height. Together they are the bottom row of the
+// perfect subtree of height Height rooted at node Index on tree level
+// (Level+1)*Height, or of its left part if the tile is partial (Width is
+// smaller than 2^Height). An entry bundle, whose Level is TileDataLevel,
+// holds the leaves under the tile at level 0 with the same Index and Width.
+type Tile struct {
+	Height int
+	Level  int
+	Index  uint64
+	Width  int
+}
+
+// Path returns the relative URL path of the tile, which is of the form
+// "tile/H/L/N" for full tiles, and "tile/H/L/N.p/W" for partial ones. L is
+// "data" for entry bundles.
+func (t Tile) Path() string {
+	level := "data"
+	if t.Level != TileDataLevel {
+		level = strconv.Itoa(t.Level)
+	}
+	p := fmt.Sprintf("tile/%d/%s/%d", t.Height, level, t.Index)
+	if t.Width != 1<<uint(t.Height) {
+		p += fmt.Sprintf(".p/%d", t.Width)
+	}
+	return p
+}
+
+// ParseTilePath parses a path returned by Tile.Path. Only canonical paths,
+// which Path returns for a valid tile, are accepted.
+func ParseTilePath(path string) (Tile, error) {
+	parts := strings.Split(path, "/")
+	if len(parts) != 4 && len(parts) != 5 || parts[0] != "tile" {
+		return Tile{}, fmt.Errorf("malformed tile path %q", path)
+	}
+	var t Tile
+	var err error
+	if t.Height, err = strconv.Atoi(parts[1]); err != nil || t.Height < 1 || t.Height > 30 {
+		return Tile{}, fmt.Errorf("invalid tile height in %q", path)
+	}
+	if parts[2] == "data" {
+		t.Level = TileDataLevel
+	} else if t.Level, err = strconv.Atoi(parts[2]); err != nil || t.Level < 0 || t.Level*t.Height >= 64 {
+		return Tile{}, fmt.Errorf("invalid tile level in %q", path)
+	}
+	t.Width = 1 << uint(t.Height)
+	index := parts[3]
+	if len(parts) == 5 {
+		if !strings.HasSuffix(index, ".p") {
+			return Tile{}, fmt.Errorf("malformed tile path %q", path)
+		}
+		index = strings.TrimSuffix(index, ".p")
+		if t.Width, err = strconv.Atoi(parts[4]); err != nil || t.Width < 1 || t.Width >= 1<<uint(t.Height) {
+			return Tile{}, fmt.Errorf("invalid tile width in %q", path)
+		}
+	}
+	if t.Index, err = strconv.ParseUint(index, 10, 64); err != nil {
+		return Tile{}, fmt.Errorf("invalid tile index in %q", path)
+	}
+	if t.Path() != path {
+		return Tile{}, fmt.Errorf("non-canonical tile path %q", path)
+	}
+	return t, nil
+}
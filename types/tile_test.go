@@ -0,0 +1,68 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "testing"
+
+func TestTilePath(t *testing.T) {
+	for _, tc := range []struct {
+		tile Tile
+		path string
+	}{
+		{tile: Tile{Height: 8, Level: 0, Index: 0, Width: 256}, path: "tile/8/0/0"},
+		{tile: Tile{Height: 8, Level: 2, Index: 1234, Width: 256}, path: "tile/8/2/1234"},
+		{tile: Tile{Height: 8, Level: 1, Index: 7, Width: 5}, path: "tile/8/1/7.p/5"},
+		{tile: Tile{Height: 4, Level: TileDataLevel, Index: 3, Width: 16}, path: "tile/4/data/3"},
+		{tile: Tile{Height: 4, Level: TileDataLevel, Index: 3, Width: 1}, path: "tile/4/data/3.p/1"},
+	} {
+		if got := tc.tile.Path(); got != tc.path {
+			t.Errorf("%+v.Path(): %q, want %q", tc.tile, got, tc.path)
+		}
+		got, err := ParseTilePath(tc.path)
+		if err != nil {
+			t.Errorf("ParseTilePath(%q): %v", tc.path, err)
+			continue
+		}
+		if got != tc.tile {
+			t.Errorf("ParseTilePath(%q): %+v, want %+v", tc.path, got, tc.tile)
+		}
+	}
+}
+
+func TestParseTilePathErrors(t *testing.T) {
+	for _, path := range []string{
+		"",
+		"tile/8/0",
+		"tiles/8/0/0",
+		"tile/0/0/0",
+		"tile/31/0/0",
+		"tile/8/-1/0",
+		"tile/8/8/0",
+		"tile/8/x/0",
+		"tile/8/0/-1",
+		"tile/8/0/007",
+		"tile/08/0/7",
+		"tile/8/0/7/5",
+		"tile/8/0/7.p",
+		"tile/8/0/7.p/0",
+		"tile/8/0/7.p/256",
+		"tile/8/0/7.p/05",
+		"tile/8/0/7/extra/parts",
+	} {
+		if got, err := ParseTilePath(path); err == nil {
+			t.Errorf("ParseTilePath(%q): %+v, want error", path, got)
+		}
+	}
+}
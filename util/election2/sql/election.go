@@ -0,0 +1,358 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sql provides an implementation of master election based on lease
+// rows in a SQL database, such as the one used by the MySQL or PostgreSQL
+// storage.
+//
+// Each resource has a single row holding its current master, the term of its
+// mastership, and the time its lease expires at. An instance becomes the master
+// by taking over an expired (or released) lease, which increments the term, and
+// stays the master for as long as it keeps renewing the lease. The term only
+// grows, so it tells apart the successive masters of a resource.
+//
+// Storage writes don't check the term, so it doesn't fence off a former master:
+// an instance which stalls past its lease expiry may still write until it
+// notices that its mastership context is canceled.
+//
+// Lease expiry is measured by the clocks of the instances, so the lease
+// duration must be well above the clock skew between them.
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/google/trillian/util/clock"
+	"github.com/google/trillian/util/election2"
+)
+
+// closeTimeout bounds the resignation attempted by Close if its context is
+// already canceled.
+const closeTimeout = 5 * time.Second
+
+// Dialect holds the SQL statements used to manage the lease rows.
+type Dialect struct {
+	// InsertSQL creates the lease row of a resource, unless it exists
+	// already, with an empty leader, a zero term and an expired lease. Takes
+	// the resource ID as parameter.
+	InsertSQL string
+	// SelectForUpdateSQL reads and locks the leader ID, term and
+	// lease expiry time (in milliseconds since the epoch) of a resource. Takes
+	// the resource ID as parameter.
+	SelectForUpdateSQL string
+	// UpdateSQL sets the leader ID, term and lease expiry time of a
+	// resource. Takes them as parameters, followed by the resource ID.
+	UpdateSQL string
+}
+
+// MySQLDialect manages the lease rows held in the ElectionLease table of the
+// MySQL storage schema.
+var MySQLDialect = Dialect{
+	InsertSQL:          "INSERT IGNORE INTO ElectionLease(ResourceId, LeaderId, Term, ExpiryTimeMillis) VALUES(?, '', 0, 0)",
+	SelectForUpdateSQL: "SELECT LeaderId, Term, ExpiryTimeMillis FROM ElectionLease WHERE ResourceId = ? FOR UPDATE",
+	UpdateSQL:          "UPDATE ElectionLease SET LeaderId = ?, Term = ?, ExpiryTimeMillis = ? WHERE ResourceId = ?",
+}
+
+// PostgresDialect manages the lease rows held in the election_lease table of
+// the PostgreSQL storage schema.
+var PostgresDialect = Dialect{
+	InsertSQL:          "INSERT INTO election_lease(resource_id, leader_id, term, expiry_time_millis) VALUES($1, '', 0, 0) ON CONFLICT DO NOTHING",
+	SelectForUpdateSQL: "SELECT leader_id, term, expiry_time_millis FROM election_lease WHERE resource_id = $1 FOR UPDATE",
+	UpdateSQL:          "UPDATE election_lease SET leader_id = $1, term = $2, expiry_time_millis = $3 WHERE resource_id = $4",
+}
+
+// Election is an implementation of election2.Election based on lease rows in
+// a SQL database.
+type Election struct {
+	resourceID string
+	instanceID string
+	db         *sql.DB
+	dialect    Dialect
+	lease      time.Duration
+
+	mu sync.Mutex
+	// term is the term of the current mastership, or 0 if the instance isn't
+	// the master.
+	term int64
+	// cancels holds the cancel functions of the mastership contexts.
+	cancels []context.CancelFunc
+}
+
+// Term returns the term of the mastership captured by the last successful
+// Await, or 0 if the instance has resigned since, or never was the master. The
+// term of a resource grows with each new master.
+func (e *Election) Term() int64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.term
+}
+
+// Await blocks until the instance captures mastership.
+func (e *Election) Await(ctx context.Context) error {
+	if _, err := e.db.ExecContext(ctx, e.dialect.InsertSQL, e.resourceID); err != nil {
+		return fmt.Errorf("failed to create lease row: %v", err)
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if ok, err := e.tryCapture(ctx); err != nil {
+			return err
+		} else if ok {
+			return nil
+		}
+		if err := clock.SleepContext(ctx, e.retryInterval()); err != nil {
+			return err
+		}
+	}
+}
+
+// WithMastership returns a "mastership context" which remains active until the
+// instance stops being the master, or the passed in context is canceled.
+func (e *Election) WithMastership(ctx context.Context) (context.Context, error) {
+	cctx, cancel := context.WithCancel(ctx)
+	term := e.Term()
+	if term == 0 {
+		// Not the master. Return a canceled context.
+		cancel()
+		return cctx, nil
+	}
+	expiry, ok, err := e.renew(ctx, term, e.lease)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if !ok {
+		// Mastership has been overtaken, or the lease expired.
+		cancel()
+		return cctx, nil
+	}
+
+	e.mu.Lock()
+	e.cancels = append(e.cancels, cancel)
+	e.mu.Unlock()
+
+	// Another instance can take over as soon as the lease expires, so the
+	// mastership context must not outlive it, even if renewing it hangs.
+	expired := time.AfterFunc(expiry.Sub(clock.System.Now()), func() {
+		glog.Warningf("%s: lease expired", e.resourceID)
+		cancel()
+	})
+
+	// Keep renewing the lease until the mastership context is done, or the
+	// lease can't be renewed.
+	go func() {
+		defer func() {
+			expired.Stop()
+			cancel()
+			glog.Infof("%s: canceled mastership context", e.resourceID)
+		}()
+
+		for {
+			if err := clock.SleepContext(cctx, e.retryInterval()); err != nil {
+				return
+			}
+			rctx, rcancel := context.WithDeadline(cctx, expiry)
+			newExpiry, ok, err := e.renew(rctx, term, e.lease)
+			rcancel()
+			switch {
+			case err != nil && cctx.Err() != nil:
+				return
+			case err != nil:
+				glog.Warningf("%s: failed to renew lease: %v", e.resourceID, err)
+				if !clock.System.Now().Before(expiry) {
+					glog.Warningf("%s: lease expired", e.resourceID)
+					return
+				}
+			case !ok:
+				glog.Warningf("%s: mastership overtaken", e.resourceID)
+				return
+			default:
+				expiry = newExpiry
+				expired.Reset(expiry.Sub(clock.System.Now()))
+			}
+		}
+	}()
+
+	return cctx, nil
+}
+
+// Resign releases mastership for this instance. The instance can be elected
+// again using Await. Idempotent, might be useful to retry if fails.
+func (e *Election) Resign(ctx context.Context) error {
+	term := e.Term()
+	if term == 0 {
+		return nil // Resigning if not master is a no-op.
+	}
+	// Renewing the lease for no time makes it expired straight away, so other
+	// instances don't have to wait for it to run out.
+	if _, _, err := e.renew(ctx, term, 0); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.term == term {
+		e.term = 0
+	}
+	for _, cancel := range e.cancels {
+		cancel()
+	}
+	e.cancels = nil
+	return nil
+}
+
+// Close resigns and permanently stops participating in election. No other
+// method should be called after Close.
+func (e *Election) Close(ctx context.Context) error {
+	if ctx.Err() != nil {
+		// Resign anyway, so that other instances can take over straight away.
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), closeTimeout)
+		defer cancel()
+	}
+	err := e.Resign(ctx)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, cancel := range e.cancels {
+		cancel()
+	}
+	e.cancels = nil
+	return err
+}
+
+// retryInterval returns the time between attempts to capture mastership, or to
+// renew the lease.
+func (e *Election) retryInterval() time.Duration {
+	return e.lease / 3
+}
+
+// tryCapture makes the instance the master, unless another instance holds an
+// unexpired lease. The lease is extended if the instance is the master
+// already. Returns whether the instance is the master.
+func (e *Election) tryCapture(ctx context.Context) (bool, error) {
+	term := e.Term()
+	var captured int64
+	err := e.inTX(ctx, func(tx *sql.Tx, leaderID string, current int64, expiry time.Time) error {
+		now := clock.System.Now()
+		switch {
+		case term != 0 && current == term && leaderID == e.instanceID && now.Before(expiry):
+			// Already the master.
+			captured = current
+		case !now.Before(expiry):
+			// The lease has expired, or been released, so take over.
+			captured = current + 1
+		default:
+			return nil
+		}
+		return e.update(ctx, tx, captured, now.Add(e.lease))
+	})
+	if err != nil || captured == 0 {
+		return false, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if captured != term {
+		glog.Infof("%s: captured mastership with term %d", e.resourceID, captured)
+	}
+	e.term = captured
+	return true, nil
+}
+
+// renew extends the lease held with the given term for d from now.
+// Returns the new expiry time, and whether the lease was still held.
+func (e *Election) renew(ctx context.Context, term int64, d time.Duration) (time.Time, bool, error) {
+	var newExpiry time.Time
+	err := e.inTX(ctx, func(tx *sql.Tx, leaderID string, current int64, expiry time.Time) error {
+		now := clock.System.Now()
+		if current != term || leaderID != e.instanceID || !now.Before(expiry) {
+			return nil
+		}
+		newExpiry = now.Add(d)
+		return e.update(ctx, tx, term, newExpiry)
+	})
+	return newExpiry, !newExpiry.IsZero(), err
+}
+
+// inTX runs f in a transaction, passing it the locked lease row of the
+// resource.
+func (e *Election) inTX(ctx context.Context, f func(tx *sql.Tx, leaderID string, term int64, expiry time.Time) error) error {
+	tx, err := e.db.BeginTx(ctx, nil /* opts */)
+	if err != nil {
+		return err
+	}
+	var leaderID string
+	var term, expiryMillis int64
+	if err := tx.QueryRowContext(ctx, e.dialect.SelectForUpdateSQL, e.resourceID).Scan(&leaderID, &term, &expiryMillis); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to read lease row: %v", err)
+	}
+	if err := f(tx, leaderID, term, time.Unix(0, expiryMillis*int64(time.Millisecond))); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (e *Election) update(ctx context.Context, tx *sql.Tx, term int64, expiry time.Time) error {
+	expiryMillis := expiry.UnixNano() / int64(time.Millisecond)
+	if _, err := tx.ExecContext(ctx, e.dialect.UpdateSQL, e.instanceID, term, expiryMillis, e.resourceID); err != nil {
+		return fmt.Errorf("failed to update lease row: %v", err)
+	}
+	return nil
+}
+
+// Factory creates Election instances.
+type Factory struct {
+	db         *sql.DB
+	dialect    Dialect
+	instanceID string
+	lease      time.Duration
+}
+
+// NewFactory builds an election factory that uses the given parameters. The
+// passed in database should remain valid for the lifetime of the object, and
+// hold the lease table of the dialect. Masters hold leases for the given
+// duration, and renew them three times as often.
+func NewFactory(instanceID string, db *sql.DB, dialect Dialect, lease time.Duration) *Factory {
+	return &Factory{
+		db:         db,
+		dialect:    dialect,
+		instanceID: instanceID,
+		lease:      lease,
+	}
+}
+
+// NewElection creates a specific Election instance.
+func (f *Factory) NewElection(ctx context.Context, resourceID string) (election2.Election, error) {
+	if f.lease <= 0 {
+		return nil, fmt.Errorf("lease duration must be positive, got %v", f.lease)
+	}
+	el := &Election{
+		resourceID: resourceID,
+		instanceID: f.instanceID,
+		db:         f.db,
+		dialect:    f.dialect,
+		lease:      f.lease,
+	}
+	glog.Infof("Election created: %s for %s", resourceID, f.instanceID)
+	return el, nil
+}
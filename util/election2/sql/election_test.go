@@ -0,0 +1,275 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/trillian/storage/testdb"
+	"github.com/google/trillian/util/election2/testonly"
+
+	pgtestdb "github.com/google/trillian/storage/postgres/testdb"
+)
+
+type testDB struct {
+	name      string
+	available func() bool
+	newDB     func(context.Context) (*sql.DB, func(context.Context), error)
+	dialect   Dialect
+	// table is the name of the lease table.
+	table string
+}
+
+var testDBs = []testDB{
+	{name: "mysql", available: testdb.MySQLAvailable, newDB: testdb.NewTrillianDB, dialect: MySQLDialect, table: "ElectionLease"},
+	{name: "postgres", available: pgtestdb.PGAvailable, newDB: pgtestdb.NewTrillianDB, dialect: PostgresDialect, table: "election_lease"},
+}
+
+// runWithDBs runs f against a new database of each available system.
+func runWithDBs(t *testing.T, f func(t *testing.T, db *sql.DB, tdb testDB)) {
+	t.Helper()
+	for _, tdb := range testDBs {
+		tdb := tdb
+		t.Run(tdb.name, func(t *testing.T) {
+			if !tdb.available() {
+				t.Skipf("Skipping test as %s not available", tdb.name)
+			}
+			ctx := context.Background()
+			db, done, err := tdb.newDB(ctx)
+			if err != nil {
+				t.Fatalf("Failed to create test database: %v", err)
+			}
+			defer done(ctx)
+			f(t, db, tdb)
+		})
+	}
+}
+
+func TestElection(t *testing.T) {
+	runWithDBs(t, func(t *testing.T, db *sql.DB, tdb testDB) {
+		for _, nt := range testonly.Tests {
+			// Start each test from an empty table for better isolation, as the
+			// tests reuse resource IDs.
+			if _, err := db.Exec("DELETE FROM " + tdb.table); err != nil {
+				t.Fatalf("Failed to clear leases: %v", err)
+			}
+			fact := NewFactory("testID", db, tdb.dialect, 5*time.Second)
+			t.Run(nt.Name, func(t *testing.T) {
+				nt.Run(t, fact)
+			})
+		}
+	})
+}
+
+func TestElectionTakeover(t *testing.T) {
+	const lease = 500 * time.Millisecond
+	runWithDBs(t, func(t *testing.T, db *sql.DB, tdb testDB) {
+		ctx := context.Background()
+		newElection := func(instanceID string) *Election {
+			e, err := NewFactory(instanceID, db, tdb.dialect, lease).NewElection(ctx, "res")
+			if err != nil {
+				t.Fatalf("NewElection(%s): %v", instanceID, err)
+			}
+			return e.(*Election)
+		}
+		e1, e2 := newElection("inst1"), newElection("inst2")
+		awaitFor := func(e *Election, d time.Duration) error {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+			return e.Await(ctx)
+		}
+
+		if err := awaitFor(e1, time.Second); err != nil {
+			t.Fatalf("Await(e1): %v", err)
+		}
+		mctx, err := e1.WithMastership(ctx)
+		if err != nil {
+			t.Fatalf("WithMastership(e1): %v", err)
+		}
+		// The lease of e1 is being renewed, so e2 can't take over.
+		if got, want := awaitFor(e2, 3*lease), context.DeadlineExceeded; got != want {
+			t.Fatalf("Await(e2) while e1 is the master: %v, want %v", got, want)
+		}
+		if mctx.Err() != nil {
+			t.Fatal("Mastership context of e1 canceled while it's the master")
+		}
+		term1 := e1.Term()
+
+		// Once e1 resigns, e2 takes over with a bigger term.
+		if err := e1.Resign(ctx); err != nil {
+			t.Fatalf("Resign(e1): %v", err)
+		}
+		if err := awaitFor(e2, time.Second); err != nil {
+			t.Fatalf("Await(e2) after e1 resigned: %v", err)
+		}
+		if term2 := e2.Term(); term2 <= term1 {
+			t.Errorf("Term(e2) = %d, want > %d", term2, term1)
+		}
+		if got := e1.Term(); got != 0 {
+			t.Errorf("Term(e1) after Resign = %d, want 0", got)
+		}
+		term2 := e2.Term()
+
+		// The lease of e2 isn't renewed without a mastership context, so e1 takes
+		// over when it expires.
+		if err := awaitFor(e1, 3*lease); err != nil {
+			t.Fatalf("Await(e1) after the lease of e2 expired: %v", err)
+		}
+		if term3 := e1.Term(); term3 <= term2 {
+			t.Errorf("Term(e1) = %d, want > %d", term3, term2)
+		}
+		mctx2, err := e2.WithMastership(ctx)
+		if err != nil {
+			t.Fatalf("WithMastership(e2): %v", err)
+		}
+		if mctx2.Err() == nil {
+			t.Error("Mastership context of e2 active after its lease was taken over")
+		}
+
+		for _, e := range []*Election{e1, e2} {
+			if err := e.Close(ctx); err != nil {
+				t.Errorf("Close(%s): %v", e.instanceID, err)
+			}
+		}
+	})
+}
+
+func TestMastershipEndsWhenRenewalHangs(t *testing.T) {
+	const lease = 300 * time.Millisecond
+	ctx := context.Background()
+	fdb := &fakeLeaseDB{}
+	db := sql.OpenDB(fdb)
+	defer db.Close()
+
+	e, err := NewFactory("inst1", db, MySQLDialect, lease).NewElection(ctx, "res")
+	if err != nil {
+		t.Fatalf("NewElection(): %v", err)
+	}
+	if err := e.Await(ctx); err != nil {
+		t.Fatalf("Await(): %v", err)
+	}
+	mctx, err := e.WithMastership(ctx)
+	if err != nil {
+		t.Fatalf("WithMastership(): %v", err)
+	}
+	if mctx.Err() != nil {
+		t.Fatal("Mastership context canceled while the lease is held")
+	}
+
+	// From now on every database call hangs, so the lease can't be renewed
+	// and runs out. Another instance could then take over, so the mastership
+	// context must end by the time the lease expires.
+	hang := make(chan struct{})
+	defer close(hang)
+	fdb.setHang(hang)
+	select {
+	case <-mctx.Done():
+	case <-time.After(3 * lease):
+		t.Fatal("Mastership context still active after the lease expired")
+	}
+}
+
+// fakeLeaseDB is a database/sql connector to an in-memory database holding a
+// single lease row, managed with MySQLDialect. Its statements can be made to
+// hang, regardless of their context.
+type fakeLeaseDB struct {
+	mu           sync.Mutex
+	leaderID     string
+	term         int64
+	expiryMillis int64
+	// hang, if not nil, blocks statements until it is closed.
+	hang chan struct{}
+}
+
+func (f *fakeLeaseDB) setHang(hang chan struct{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.hang = hang
+}
+
+func (f *fakeLeaseDB) wait() {
+	f.mu.Lock()
+	hang := f.hang
+	f.mu.Unlock()
+	if hang != nil {
+		<-hang
+	}
+}
+
+func (f *fakeLeaseDB) Connect(context.Context) (driver.Conn, error) { return fakeConn{f}, nil }
+func (f *fakeLeaseDB) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{ db *fakeLeaseDB }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{c.db, query}, nil }
+func (c fakeConn) Close() error                              { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)                 { return fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct {
+	db    *fakeLeaseDB
+	query string
+}
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.wait()
+	if s.query == MySQLDialect.UpdateSQL {
+		s.db.mu.Lock()
+		defer s.db.mu.Unlock()
+		s.db.leaderID = args[0].(string)
+		s.db.term = args[1].(int64)
+		s.db.expiryMillis = args[2].(int64)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.db.wait()
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return &fakeRows{row: []driver.Value{s.db.leaderID, s.db.term, s.db.expiryMillis}}, nil
+}
+
+type fakeRows struct {
+	row  []driver.Value
+	done bool
+}
+
+func (r *fakeRows) Columns() []string {
+	return []string{"LeaderId", "Term", "ExpiryTimeMillis"}
+}
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	copy(dest, r.row)
+	r.done = true
+	return nil
+}